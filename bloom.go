@@ -2,6 +2,7 @@ package parquet
 
 import (
 	"io"
+	"math"
 
 	"github.com/segmentio/parquet-go/bloom"
 	"github.com/segmentio/parquet-go/deprecated"
@@ -97,6 +98,53 @@ func (f splitBlockFilter) NewFilter(numValues int64, bitsPerValue uint) bloom.Mu
 	return make(bloom.SplitBlockFilter, bloom.NumSplitBlocksOf(numValues, bitsPerValue))
 }
 
+// SizedSplitBlockFilter constructs a split block bloom filter object for the
+// column at the given path, sized to hold ndv distinct values with a false
+// positive probability of fpp.
+//
+// By default, filters are sized for the number of values in the column chunk
+// with 10 bits per value, which gives a false positive probability of about 1%.
+// This is wasteful for columns with few distinct values, and may not be enough
+// for columns where lookups must rarely yield false positives. Passing zero
+// as ndv or fpp retains the default for this parameter.
+//
+// The function panics if fpp is not in the [0, 1) range.
+func SizedSplitBlockFilter(ndv int64, fpp float64, path ...string) BloomFilterColumn {
+	if fpp < 0 || fpp >= 1 {
+		panic("false positive probability of bloom filters must be in the [0, 1) range")
+	}
+	f := sizedSplitBlockFilter{splitBlockFilter: path, ndv: ndv}
+	if fpp > 0 {
+		f.bitsPerValue = splitBlockFilterBitsPerValue(fpp)
+	}
+	return f
+}
+
+type sizedSplitBlockFilter struct {
+	splitBlockFilter
+	ndv          int64
+	bitsPerValue uint
+}
+
+func (f sizedSplitBlockFilter) NewFilter(numValues int64, bitsPerValue uint) bloom.MutableFilter {
+	if f.ndv > 0 {
+		numValues = f.ndv
+	}
+	if f.bitsPerValue > 0 {
+		bitsPerValue = f.bitsPerValue
+	}
+	return f.splitBlockFilter.NewFilter(numValues, bitsPerValue)
+}
+
+// splitBlockFilterBitsPerValue returns the number of bits per value that split
+// block bloom filters need to achieve the given false positive probability.
+//
+// Each value sets one bit in each of the 8 words of a block, the formula is
+// the one given by the parquet specification to size split block filters.
+func splitBlockFilterBitsPerValue(fpp float64) uint {
+	return uint(math.Ceil(-8 / math.Log(1-math.Pow(fpp, 1.0/8))))
+}
+
 // Creates a header from the given bloom filter.
 //
 // For now there is only one type of filter supported, but we provide this
@@ -104,7 +152,7 @@ func (f splitBlockFilter) NewFilter(numValues int64, bitsPerValue uint) bloom.Mu
 // are added to the parquet specs.
 func bloomFilterHeader(filter BloomFilterColumn) (header format.BloomFilterHeader) {
 	switch filter.(type) {
-	case splitBlockFilter:
+	case splitBlockFilter, sizedSplitBlockFilter:
 		header.Algorithm.Block = &format.SplitBlockAlgorithm{}
 	}
 	switch filter.Hash().(type) {
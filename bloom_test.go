@@ -194,3 +194,57 @@ func BenchmarkBloomFilterEncoder(b *testing.B) {
 
 	b.SetBytes(8 * N)
 }
+
+func TestSizedSplitBlockFilter(t *testing.T) {
+	for _, test := range []struct {
+		fpp          float64
+		bitsPerValue uint
+	}{
+		{fpp: 0.1, bitsPerValue: 6},
+		{fpp: 0.01, bitsPerValue: 10},
+		{fpp: 0.001, bitsPerValue: 15},
+	} {
+		if n := splitBlockFilterBitsPerValue(test.fpp); n != test.bitsPerValue {
+			t.Errorf("wrong number of bits per value for fpp=%g: want=%d got=%d", test.fpp, test.bitsPerValue, n)
+		}
+	}
+
+	const numValues, bitsPerValue = 100e3, 10
+
+	for _, test := range []struct {
+		scenario  string
+		filter    BloomFilterColumn
+		numBlocks int
+	}{
+		{
+			scenario:  "default",
+			filter:    SizedSplitBlockFilter(0, 0, "a"),
+			numBlocks: bloom.NumSplitBlocksOf(numValues, bitsPerValue),
+		},
+		{
+			scenario:  "ndv",
+			filter:    SizedSplitBlockFilter(100, 0, "a"),
+			numBlocks: bloom.NumSplitBlocksOf(100, bitsPerValue),
+		},
+		{
+			scenario:  "fpp",
+			filter:    SizedSplitBlockFilter(0, 0.001, "a"),
+			numBlocks: bloom.NumSplitBlocksOf(numValues, 15),
+		},
+		{
+			scenario:  "ndv+fpp",
+			filter:    SizedSplitBlockFilter(1e6, 0.1, "a"),
+			numBlocks: bloom.NumSplitBlocksOf(1e6, 6),
+		},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			f := test.filter.NewFilter(numValues, bitsPerValue).(bloom.SplitBlockFilter)
+			if len(f) != test.numBlocks {
+				t.Errorf("wrong number of blocks: want=%d got=%d", test.numBlocks, len(f))
+			}
+			if header := bloomFilterHeader(test.filter); header.Algorithm.Block == nil {
+				t.Error("bloom filter header does not declare the split block algorithm")
+			}
+		})
+	}
+}
@@ -1,7 +1,11 @@
 package parquet
 
 import (
+	"fmt"
+	"io"
 	"sort"
+
+	"github.com/segmentio/parquet-go/encoding/plain"
 )
 
 // Buffer represents an in-memory group of parquet rows.
@@ -16,8 +20,37 @@ type Buffer struct {
 	colbuf  [][]Value
 	columns []ColumnBuffer
 	sorted  []ColumnBuffer
+	// State of buffers configured with KeyColumns: the keys of rows, empty for
+	// rows that were replaced and will be removed when the buffer is compacted,
+	// and the index of rows by key.
+	keyColumns  []int
+	keyError    error
+	keybuf      []byte
+	keys        map[string]int
+	rowKeys     []string
+	numReplaced int
+	// Indexes of the rows starting sorted runs in buffers configured with
+	// SortOnInsert, after the first run which starts at index zero. The runs
+	// are merged when the buffer is compacted.
+	runs []int
+	// Error that occurred when compacting the buffer, which is returned when
+	// writing or reading rows since the columns may not be aligned anymore.
+	compactError error
 }
 
+// KeyConflict values define how buffers configured with KeyColumns handle rows
+// with a key that already exists in the buffer.
+type KeyConflict int
+
+const (
+	// ReplaceRow replaces the existing row with the row being written, so the
+	// last row written with a key wins.
+	ReplaceRow KeyConflict = iota
+	// KeepRow keeps the existing row and drops the row being written, so the
+	// first row written with a key wins.
+	KeepRow
+)
+
 // NewBuffer constructs a new buffer, using the given list of buffer options
 // to configure the buffer returned by the function.
 //
@@ -94,9 +127,45 @@ func (buf *Buffer) configure(schema *Schema) {
 	buf.schema = schema
 	buf.rowbuf = make([]Value, 0, 10)
 	buf.colbuf = make([][]Value, len(buf.columns))
+	buf.configureKeys()
+}
+
+func (buf *Buffer) configureKeys() {
+	buf.keyColumns = buf.keyColumns[:0]
+	buf.keyError = nil
+	buf.keys = nil
+	buf.rowKeys = buf.rowKeys[:0]
+	buf.numReplaced = 0
+
+	for _, key := range buf.config.KeyColumns {
+		columnIndex := -1
+		forEachLeafColumnOf(buf.schema, func(leaf leafColumn) {
+			if leaf.path.String() != key {
+				return
+			}
+			if leaf.maxRepetitionLevel > 0 {
+				buf.keyError = fmt.Errorf("key column %q of parquet buffer cannot be repeated", key)
+			}
+			columnIndex = int(leaf.columnIndex)
+		})
+		if columnIndex < 0 {
+			buf.keyError = fmt.Errorf("key column %q not found in the schema of parquet buffer", key)
+		}
+		if buf.keyError != nil {
+			return
+		}
+		buf.keyColumns = append(buf.keyColumns, columnIndex)
+	}
+
+	if len(buf.keyColumns) > 0 {
+		buf.keys = make(map[string]int)
+	}
 }
 
 // Size returns the estimated size of the buffer in memory (in bytes).
+//
+// When the buffer was configured with KeyColumns, the size includes the rows
+// that were replaced until they are removed from the columns.
 func (buf *Buffer) Size() int64 {
 	size := int64(0)
 	for _, col := range buf.columns {
@@ -116,7 +185,13 @@ func (buf *Buffer) NumColumns() int { return len(buf.columns) }
 // Column returns the buffer column at index i.
 //
 // The method panics if i is negative or beyond the last column index in buf.
-func (buf *Buffer) Column(i int) ColumnChunk { return buf.columns[i] }
+//
+// When the buffer was configured with KeyColumns or SortOnInsert, the column
+// holds the rows in the order they were written, including the rows that were
+// replaced, until the buffer is compacted by a call to Rows.
+func (buf *Buffer) Column(i int) ColumnChunk {
+	return buf.columns[i]
+}
 
 // ColumnBuffer returns the buffer column at index i.
 //
@@ -126,7 +201,9 @@ func (buf *Buffer) Column(i int) ColumnChunk { return buf.columns[i] }
 // types, which removes the need for making a type assertion if the program
 // needed to write directly to the column buffers. The presence of the Column
 // method is still required to satisfy the RowGroup interface.
-func (buf *Buffer) ColumnBuffer(i int) ColumnBuffer { return buf.columns[i] }
+func (buf *Buffer) ColumnBuffer(i int) ColumnBuffer {
+	return buf.columns[i]
+}
 
 // Schema returns the schema of the buffer.
 //
@@ -142,7 +219,16 @@ func (buf *Buffer) Schema() *Schema { return buf.schema }
 func (buf *Buffer) SortingColumns() []SortingColumn { return buf.config.SortingColumns }
 
 // Len returns the number of rows written to the buffer.
+//
+// When the buffer was configured with KeyColumns, rows that were replaced are
+// not counted.
 func (buf *Buffer) Len() int {
+	return buf.numRows() - buf.numReplaced
+}
+
+// numRows returns the number of rows held in the columns of the buffer,
+// including the rows that were replaced and not yet removed.
+func (buf *Buffer) numRows() int {
 	if len(buf.columns) == 0 {
 		return 0
 	} else {
@@ -169,6 +255,15 @@ func (buf *Buffer) Swap(i, j int) {
 	for _, col := range buf.columns {
 		col.Swap(i, j)
 	}
+	if buf.keys != nil {
+		buf.rowKeys[i], buf.rowKeys[j] = buf.rowKeys[j], buf.rowKeys[i]
+		if key := buf.rowKeys[i]; key != "" {
+			buf.keys[key] = i
+		}
+		if key := buf.rowKeys[j]; key != "" {
+			buf.keys[key] = j
+		}
+	}
 }
 
 // Reset clears the content of the buffer, allowing it to be reused.
@@ -176,6 +271,13 @@ func (buf *Buffer) Reset() {
 	for _, col := range buf.columns {
 		col.Reset()
 	}
+	if buf.keys != nil {
+		buf.keys = make(map[string]int)
+		buf.rowKeys = buf.rowKeys[:0]
+		buf.numReplaced = 0
+	}
+	buf.runs = buf.runs[:0]
+	buf.compactError = nil
 }
 
 // Write writes a row held in a Go value to the buffer.
@@ -202,22 +304,217 @@ func (buf *Buffer) WriteRow(row Row) error {
 	if buf.schema == nil {
 		return ErrRowGroupSchemaMissing
 	}
+	if buf.keyError != nil {
+		return buf.keyError
+	}
+	if buf.compactError != nil {
+		return buf.compactError
+	}
 
 	for _, value := range row {
 		columnIndex := value.Column()
 		buf.colbuf[columnIndex] = append(buf.colbuf[columnIndex], value)
 	}
 
+	replacedRow := -1
+	if buf.keys != nil {
+		buf.keybuf = buf.keybuf[:0]
+		for _, columnIndex := range buf.keyColumns {
+			buf.keybuf = appendKey(buf.keybuf, buf.colbuf[columnIndex])
+		}
+		if i, exists := buf.keys[string(buf.keybuf)]; exists {
+			if buf.config.KeyConflict == KeepRow {
+				return nil
+			}
+			replacedRow = i
+		}
+	}
+
 	for columnIndex, values := range buf.colbuf {
 		if err := buf.columns[columnIndex].WriteRow(values); err != nil {
 			return err
 		}
 	}
 
+	if buf.keys != nil {
+		key := string(buf.keybuf)
+		if replacedRow >= 0 {
+			buf.rowKeys[replacedRow] = ""
+			buf.numReplaced++
+		}
+		buf.keys[key] = len(buf.rowKeys)
+		buf.rowKeys = append(buf.rowKeys, key)
+	}
+
+	if buf.config.SortOnInsert {
+		buf.trackRuns(buf.numRows() - 1)
+	}
 	return nil
 }
 
+// appendKey appends the encoding of the value of a key column to b. Values are
+// prefixed with a null marker, and byte arrays with their length, so distinct
+// sequences of values never produce the same key.
+func appendKey(b []byte, values []Value) []byte {
+	if len(values) == 0 || values[0].IsNull() {
+		return append(b, 0)
+	}
+	b = append(b, 1)
+	switch v := values[0]; v.Kind() {
+	case ByteArray, FixedLenByteArray:
+		return plain.AppendByteArray(b, v.ByteArray())
+	default:
+		return v.AppendBytes(b)
+	}
+}
+
+// readValues fills values with the values read from r.
+func readValues(r ValueReader, values []Value) (int, error) {
+	n := 0
+	for n < len(values) {
+		c, err := r.ReadValues(values[n:])
+		n += c
+		if err != nil {
+			if err == io.EOF && n == len(values) {
+				err = nil
+			}
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// compact removes the rows that were replaced in buffers configured with
+// KeyColumns, preserving the order of the remaining rows, and merges the sorted
+// runs of rows in buffers configured with SortOnInsert.
+//
+// The columns are only rewritten when Rows is called, so writing rows does not
+// rewrite them each time a row is replaced or written out of order, and the
+// other methods reading the buffer never mutate it. Errors are retained and
+// returned when writing or reading rows.
+func (buf *Buffer) compact() {
+	if (buf.numReplaced > 0 || len(buf.runs) > 0) && buf.compactError == nil {
+		buf.compactError = buf.rewriteRows()
+	}
+}
+
+func (buf *Buffer) rewriteRows() error {
+	order := buf.sortedRows()
+	if buf.numReplaced > 0 {
+		rows := order[:0]
+		for _, i := range order {
+			// Rows written directly to the column buffers have no keys.
+			if i >= len(buf.rowKeys) || buf.rowKeys[i] != "" {
+				rows = append(rows, i)
+			}
+		}
+		order = rows
+	}
+
+	var rows []Row
+	for _, col := range buf.columns {
+		// Read the values back from a copy of the column, since the values
+		// may share memory with the column buffer being rewritten. Pages
+		// expose the values in the current order of rows.
+		page := col.Clone().Page()
+		values := make([]Value, page.NumValues())
+		if _, err := readValues(page.Values(), values); err != nil {
+			return fmt.Errorf("reading values of parquet buffer column %d: %w", col.Column(), err)
+		}
+		col.Reset()
+
+		rows = rows[:0]
+		forEachRepeatedRowOf(values, func(row Row) error {
+			rows = append(rows, row)
+			return nil
+		})
+		for _, i := range order {
+			if err := col.WriteRow(rows[i]); err != nil {
+				return fmt.Errorf("writing values of parquet buffer column %d: %w", col.Column(), err)
+			}
+		}
+	}
+
+	if buf.keys != nil {
+		rowKeys := make([]string, len(order))
+		for i, j := range order {
+			if j < len(buf.rowKeys) {
+				if rowKeys[i] = buf.rowKeys[j]; rowKeys[i] != "" {
+					buf.keys[rowKeys[i]] = i
+				}
+			}
+		}
+		buf.rowKeys = rowKeys
+	}
+	buf.runs = buf.runs[:0]
+	buf.numReplaced = 0
+	return nil
+}
+
+// sortedRows returns the indexes of rows in the order that they must appear
+// in the buffer, merging the sorted runs pairwise until a single one remains.
+func (buf *Buffer) sortedRows() []int {
+	n := buf.numRows()
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	if len(buf.runs) == 0 {
+		return order
+	}
+
+	bounds := make([]int, 0, len(buf.runs)+2)
+	bounds = append(bounds, 0)
+	bounds = append(bounds, buf.runs...)
+	bounds = append(bounds, n)
+	merged := make([]int, n)
+
+	for len(bounds) > 2 {
+		next := make([]int, 1, len(bounds)/2+2)
+		for k := 0; k+1 < len(bounds); k += 2 {
+			lo, mid, hi := bounds[k], bounds[k+1], bounds[k+1]
+			if k+2 < len(bounds) {
+				hi = bounds[k+2]
+			}
+			buf.mergeRows(merged[lo:hi], order[lo:mid], order[mid:hi])
+			next = append(next, hi)
+		}
+		bounds = next
+		order, merged = merged, order
+	}
+	return order
+}
+
+// mergeRows merges the sorted sequences of row indexes a and b into dst, rows
+// of a are placed first when rows are equal.
+func (buf *Buffer) mergeRows(dst, a, b []int) {
+	i, j := 0, 0
+	for k := range dst {
+		if j == len(b) || (i < len(a) && !buf.Less(b[j], a[i])) {
+			dst[k] = a[i]
+			i++
+		} else {
+			dst[k] = b[j]
+			j++
+		}
+	}
+}
+
 // WriteRowGroup satisfies the RowGroupWriter interface.
+//
+// The values of the row group are copied column by column, one page at a time,
+// without reconstructing the rows; this makes it efficient to load row groups
+// from files or other buffers, for example to sort or compact them.
+//
+// The row group must have the same schema as the buffer, and its sorting
+// columns must have the sorting columns of the buffer as prefix, otherwise
+// ErrRowGroupSchemaMismatch or ErrRowGroupSortingColumnsMismatch is returned.
+// If the buffer had no schema, it adopts the schema of the row group.
+//
+// When the buffer was configured with SortOnInsert, the sorted runs of the rows
+// of the row group are recorded after being copied. When the buffer was
+// configured with KeyColumns, the rows are copied one at a time so their keys
+// can be checked against the rows already in the buffer.
 func (buf *Buffer) WriteRowGroup(rowGroup RowGroup) (int64, error) {
 	rowGroupSchema := rowGroup.Schema()
 	switch {
@@ -231,16 +528,60 @@ func (buf *Buffer) WriteRowGroup(rowGroup RowGroup) (int64, error) {
 	if !sortingColumnsHavePrefix(rowGroup.SortingColumns(), buf.SortingColumns()) {
 		return 0, ErrRowGroupSortingColumnsMismatch
 	}
-	n := buf.NumRows()
+	if buf.keys != nil || buf.keyError != nil {
+		// Hide the WriteRowGroup method of buf so CopyRows does not recurse.
+		return CopyRows(struct{ RowWriter }{buf}, rowGroup.Rows())
+	}
+	n := buf.numRows()
 	_, err := CopyRows(bufferWriter{buf}, rowGroup.Rows())
-	return buf.NumRows() - n, err
+	if buf.config.SortOnInsert {
+		buf.trackRuns(n)
+	}
+	return int64(buf.numRows() - n), err
+}
+
+// trackRuns records the rows starting sorted runs among the rows written to
+// the buffer from index i.
+func (buf *Buffer) trackRuns(i int) {
+	if len(buf.sorted) == 0 {
+		return
+	}
+	if i == 0 {
+		i = 1
+	}
+	for n := buf.numRows(); i < n; i++ {
+		if buf.Less(i, i-1) {
+			buf.runs = append(buf.runs, i)
+		}
+	}
 }
 
 // Rows returns a reader exposing the current content of the buffer.
 //
 // The buffer and the returned reader share memory. Mutating the buffer
 // concurrently to reading rows may result in non-deterministic behavior.
-func (buf *Buffer) Rows() Rows { return &rowGroupRowReader{rowGroup: buf} }
+//
+// When the buffer was configured with KeyColumns or SortOnInsert, Rows first
+// compacts the buffer, which rewrites its columns if rows were replaced or
+// written out of order since the last call. Rows is therefore a mutation, and
+// must be called once before the buffer is read from multiple goroutines.
+func (buf *Buffer) Rows() Rows {
+	if buf.compact(); buf.compactError != nil {
+		return &errorRows{schema: buf.schema, err: buf.compactError}
+	}
+	return &rowGroupRowReader{rowGroup: buf}
+}
+
+// errorRows is an implementation of Rows returning an error when rows are read
+// or seeked.
+type errorRows struct {
+	schema *Schema
+	err    error
+}
+
+func (r *errorRows) ReadRow(row Row) (Row, error) { return row, r.err }
+func (r *errorRows) SeekToRow(int64) error        { return r.err }
+func (r *errorRows) Schema() *Schema              { return r.schema }
 
 // bufferWriter is an adapter for Buffer which implements both RowWriter and
 // PageWriter to enable optimizations in CopyRows for types that support writing
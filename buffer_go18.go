@@ -0,0 +1,198 @@
+//go:build go1.18
+
+package parquet
+
+import (
+	"reflect"
+	"unsafe"
+
+	"github.com/segmentio/parquet-go/internal/cast"
+)
+
+// GenericBuffer is similar to a Buffer but uses a type parameter to define the
+// Go type representing the schema of rows in the buffer.
+//
+// When T is a struct type made of required leaf fields of primitive types
+// (booleans, integers, floating point numbers, and strings), the values are
+// written directly from the Go values to the column buffers, without going
+// through reflection and the intermediary Row representation. Other types are
+// supported as well but do not benefit from this optimization.
+type GenericBuffer[T any] struct {
+	base    Buffer
+	columns []genericColumnWriter
+}
+
+// NewGenericBuffer constructs a new buffer of rows of type T.
+//
+// If no schema is passed in the option list, it is deducted from the Go type
+// T, which then has to be a struct or pointer to struct.
+//
+// The function panics if the buffer configuration is invalid.
+func NewGenericBuffer[T any](options ...RowGroupOption) *GenericBuffer[T] {
+	config, err := NewRowGroupConfig(options...)
+	if err != nil {
+		panic(err)
+	}
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if config.Schema == nil {
+		config.Schema = schemaOf(dereference(t))
+	}
+	buf := &GenericBuffer[T]{
+		base: Buffer{config: config},
+	}
+	buf.base.configure(config.Schema)
+	if t.Kind() == reflect.Struct && config.Schema == schemaOf(t) {
+		buf.columns = genericColumnWritersOf(t, &buf.base)
+	}
+	return buf
+}
+
+// Size returns the estimated size of the buffer in memory (in bytes).
+func (buf *GenericBuffer[T]) Size() int64 { return buf.base.Size() }
+
+// NumRows returns the number of rows written to the buffer.
+func (buf *GenericBuffer[T]) NumRows() int64 { return buf.base.NumRows() }
+
+// NumColumns returns the number of columns in the buffer.
+func (buf *GenericBuffer[T]) NumColumns() int { return buf.base.NumColumns() }
+
+// Column returns the buffer column at index i.
+func (buf *GenericBuffer[T]) Column(i int) ColumnChunk { return buf.base.Column(i) }
+
+// ColumnBuffer returns the buffer column at index i.
+func (buf *GenericBuffer[T]) ColumnBuffer(i int) ColumnBuffer { return buf.base.ColumnBuffer(i) }
+
+// Schema returns the schema of the buffer.
+func (buf *GenericBuffer[T]) Schema() *Schema { return buf.base.Schema() }
+
+// SortingColumns returns the list of columns by which the buffer will be
+// sorted.
+func (buf *GenericBuffer[T]) SortingColumns() []SortingColumn { return buf.base.SortingColumns() }
+
+// Len returns the number of rows written to the buffer.
+func (buf *GenericBuffer[T]) Len() int { return buf.base.Len() }
+
+// Less returns true if row[i] < row[j] in the buffer.
+func (buf *GenericBuffer[T]) Less(i, j int) bool { return buf.base.Less(i, j) }
+
+// Swap exchanges the rows at indexes i and j.
+func (buf *GenericBuffer[T]) Swap(i, j int) { buf.base.Swap(i, j) }
+
+// Reset clears the content of the buffer, allowing it to be reused.
+func (buf *GenericBuffer[T]) Reset() { buf.base.Reset() }
+
+// Write writes the rows to the buffer, returning the number of rows written.
+func (buf *GenericBuffer[T]) Write(rows []T) (int, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	if buf.columns != nil {
+		p := unsafe.Pointer(&rows[0])
+		size := unsafe.Sizeof(rows[0])
+		for _, write := range buf.columns {
+			write(p, size, len(rows))
+		}
+		return len(rows), nil
+	}
+	for i := range rows {
+		if err := buf.base.Write(&rows[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(rows), nil
+}
+
+// WriteRow writes a parquet row to the buffer.
+func (buf *GenericBuffer[T]) WriteRow(row Row) error { return buf.base.WriteRow(row) }
+
+// WriteRowGroup satisfies the RowGroupWriter interface.
+func (buf *GenericBuffer[T]) WriteRowGroup(rowGroup RowGroup) (int64, error) {
+	return buf.base.WriteRowGroup(rowGroup)
+}
+
+// Rows returns a reader exposing the current content of the buffer.
+func (buf *GenericBuffer[T]) Rows() Rows { return buf.base.Rows() }
+
+// genericColumnWriter is the signature of functions writing the values of a
+// column from a contiguous sequence of n Go values of the given size.
+type genericColumnWriter func(rows unsafe.Pointer, size uintptr, n int)
+
+// genericColumnWritersOf returns the functions writing each column of buf from
+// Go values of type t, or nil if one of the columns cannot be written directly
+// from the Go values.
+func genericColumnWritersOf(t reflect.Type, buf *Buffer) []genericColumnWriter {
+	fields := structFieldsOf(t)
+	if len(fields) != len(buf.columns) {
+		return nil
+	}
+	columns := make([]genericColumnWriter, len(buf.columns))
+
+	forEachLeafColumnOf(buf.schema, func(leaf leafColumn) {
+		// The struct fields and leaf columns are both sorted by name, nested
+		// and embedded fields are written through the generic code path.
+		i := int(leaf.columnIndex)
+		f := fields[i]
+		if len(leaf.path) != 1 || leaf.path[0] != f.Name || len(f.Index) != 1 {
+			return
+		}
+		if leaf.maxRepetitionLevel > 0 || leaf.maxDefinitionLevel > 0 {
+			return
+		}
+		if f.Type == durationType && durationUnitOf(leaf.node.Type()) != 0 {
+			return // durations are scaled to the unit of the column
+		}
+		columns[i] = genericColumnWriterOf(f.Type.Kind(), t.Field(f.Index[0]).Offset, buf.columns[i])
+	})
+
+	for _, column := range columns {
+		if column == nil {
+			return nil
+		}
+	}
+	return columns
+}
+
+func genericColumnWriterOf(kind reflect.Kind, offset uintptr, column ColumnBuffer) genericColumnWriter {
+	switch kind {
+	case reflect.Bool:
+		return primitiveColumnWriterOf[bool](offset, column)
+	case reflect.Int32:
+		return primitiveColumnWriterOf[int32](offset, column)
+	case reflect.Int64:
+		return primitiveColumnWriterOf[int64](offset, column)
+	case reflect.Uint32:
+		return primitiveColumnWriterOf[uint32](offset, column)
+	case reflect.Uint64:
+		return primitiveColumnWriterOf[uint64](offset, column)
+	case reflect.Float32:
+		return primitiveColumnWriterOf[float32](offset, column)
+	case reflect.Float64:
+		return primitiveColumnWriterOf[float64](offset, column)
+	case reflect.String:
+		if col, ok := column.(*byteArrayColumnBuffer); ok {
+			return func(rows unsafe.Pointer, size uintptr, n int) {
+				for i := 0; i < n; i++ {
+					col.values.Push(cast.StringToBytes(*(*string)(unsafe.Add(rows, uintptr(i)*size+offset))))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func primitiveColumnWriterOf[V primitive](offset uintptr, column ColumnBuffer) genericColumnWriter {
+	col, ok := column.(*columnBuffer[V])
+	if !ok {
+		return nil
+	}
+	return func(rows unsafe.Pointer, size uintptr, n int) {
+		for i := 0; i < n; i++ {
+			col.values = append(col.values, *(*V)(unsafe.Add(rows, uintptr(i)*size+offset)))
+		}
+	}
+}
+
+var (
+	_ RowGroup       = (*GenericBuffer[struct{}])(nil)
+	_ RowGroupWriter = (*GenericBuffer[struct{}])(nil)
+)
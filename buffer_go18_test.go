@@ -0,0 +1,139 @@
+//go:build go1.18
+
+package parquet_test
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+type genericBufferFlatRow struct {
+	Bool    bool    `parquet:"bool"`
+	Int32   int32   `parquet:"int32"`
+	Int64   int64   `parquet:"int64"`
+	Uint32  uint32  `parquet:"uint32"`
+	Uint64  uint64  `parquet:"uint64"`
+	Float32 float32 `parquet:"float32"`
+	Float64 float64 `parquet:"float64"`
+	String  string  `parquet:"string"`
+}
+
+type genericBufferNestedRow struct {
+	ID       int64    `parquet:"id"`
+	Name     *string  `parquet:"name,optional"`
+	Tags     []string `parquet:"tags"`
+	Category string   `parquet:"category,dict"`
+}
+
+func TestGenericBuffer(t *testing.T) {
+	t.Run("flat", testGenericBuffer[genericBufferFlatRow])
+	t.Run("nested", testGenericBuffer[genericBufferNestedRow])
+}
+
+func testGenericBuffer[T any](t *testing.T) {
+	rows := makeGenericBufferRows[T](100)
+
+	generic := parquet.NewGenericBuffer[T]()
+	if n, err := generic.Write(rows[:50]); err != nil {
+		t.Fatal(err)
+	} else if n != 50 {
+		t.Fatalf("wrong number of rows written: want=50 got=%d", n)
+	}
+	if _, err := generic.Write(rows[50:]); err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := parquet.NewBuffer()
+	for i := range rows {
+		if err := buffer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if generic.NumRows() != buffer.NumRows() {
+		t.Fatalf("wrong number of rows: want=%d got=%d", buffer.NumRows(), generic.NumRows())
+	}
+
+	want, got := readAllRows(t, buffer.Rows()), readAllRows(t, generic.Rows())
+	for i := range want {
+		if !want[i].Equal(got[i]) {
+			t.Fatalf("rows at index %d mismatch:\nwant = %+v\ngot  = %+v", i, want[i], got[i])
+		}
+	}
+
+	generic.Reset()
+	if n := generic.NumRows(); n != 0 {
+		t.Errorf("buffer was not reset: %d rows", n)
+	}
+}
+
+func makeGenericBufferRows[T any](n int) []T {
+	rows := make([]T, n)
+	for i := range rows {
+		v := reflect.ValueOf(&rows[i]).Elem()
+		switch row := v.Addr().Interface().(type) {
+		case *genericBufferFlatRow:
+			*row = genericBufferFlatRow{
+				Bool:    i%2 == 0,
+				Int32:   int32(i),
+				Int64:   int64(i) << 32,
+				Uint32:  uint32(i) * 3,
+				Uint64:  uint64(i) << 40,
+				Float32: float32(i) / 2,
+				Float64: float64(i) / 3,
+				String:  fmt.Sprint(i),
+			}
+		case *genericBufferNestedRow:
+			name := fmt.Sprint(i)
+			*row = genericBufferNestedRow{
+				ID:       int64(i),
+				Tags:     []string{"a", "b"}[:i%3%2],
+				Category: fmt.Sprint(i % 4),
+			}
+			if i%2 == 0 {
+				row.Name = &name
+			}
+		}
+	}
+	return rows
+}
+
+func readAllRows(t testing.TB, rows parquet.Rows) []parquet.Row {
+	var all []parquet.Row
+	for {
+		row, err := rows.ReadRow(nil)
+		if err != nil {
+			if err != io.EOF {
+				t.Fatal(err)
+			}
+			return all
+		}
+		all = append(all, row)
+	}
+}
+
+func BenchmarkGenericBuffer(b *testing.B) {
+	rows := makeGenericBufferRows[genericBufferFlatRow](1000)
+
+	b.Run("Buffer", func(b *testing.B) {
+		buffer := parquet.NewBuffer()
+		for i := 0; i < b.N; i++ {
+			for j := range rows {
+				buffer.Write(&rows[j])
+			}
+			buffer.Reset()
+		}
+	})
+
+	b.Run("GenericBuffer", func(b *testing.B) {
+		buffer := parquet.NewGenericBuffer[genericBufferFlatRow]()
+		for i := 0; i < b.N; i++ {
+			buffer.Write(rows)
+			buffer.Reset()
+		}
+	})
+}
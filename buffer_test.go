@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"math"
+	"reflect"
 	"sort"
 	"testing"
 	"testing/quick"
@@ -421,3 +422,321 @@ func TestBufferGenerateBloomFilters(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestBufferSortRepeatedColumnWithEmptyRows(t *testing.T) {
+	type Row struct {
+		ID   int64    `parquet:"id"`
+		Tags []string `parquet:"tags"`
+	}
+
+	buffer := parquet.NewBuffer(parquet.SortingColumns(parquet.Ascending("id")))
+	for i := 10; i > 0; i-- {
+		buffer.Write(&Row{ID: int64(i), Tags: []string{"a", "b", "c"}[:i%4]})
+	}
+	sort.Sort(buffer)
+
+	reader := parquet.NewRowGroupReader(buffer)
+	for i := 1; i <= 10; i++ {
+		var row Row
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if row.ID != int64(i) || len(row.Tags) != i%4 {
+			t.Fatalf("row %d mismatch: %+v", i, row)
+		}
+	}
+}
+
+func TestBufferWriteRowGroupRepeatedColumn(t *testing.T) {
+	type Row struct {
+		ID   int64    `parquet:"id"`
+		Tags []string `parquet:"tags"`
+	}
+
+	output := new(bytes.Buffer)
+	writer := parquet.NewWriter(output)
+	for i := 0; i < 100; i++ {
+		writer.Write(&Row{ID: int64(i), Tags: []string{"a", "b", "c"}[:i%4]})
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(output.Bytes()), int64(output.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := parquet.NewBuffer()
+	if _, err := buffer.WriteRowGroup(f.RowGroup(0)); err != nil {
+		t.Fatal(err)
+	}
+	// Reordering the rows requires the buffer to know where each row starts
+	// in the repeated column.
+	for i, j := 0, buffer.Len()-1; i < j; i, j = i+1, j-1 {
+		buffer.Swap(i, j)
+	}
+
+	reader := parquet.NewRowGroupReader(buffer)
+	for i := 99; i >= 0; i-- {
+		var row Row
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if row.ID != int64(i) || len(row.Tags) != i%4 {
+			t.Fatalf("row %d mismatch: %+v", i, row)
+		}
+	}
+}
+
+func TestBufferSortOnInsert(t *testing.T) {
+	type Row struct {
+		ID    int64    `parquet:"id"`
+		Seq   int64    `parquet:"seq"`
+		Name  *string  `parquet:"name,optional"`
+		Tags  []string `parquet:"tags"`
+		Value string   `parquet:"value,dict"`
+	}
+
+	makeRow := func(id, seq int64) *Row {
+		name := "row"
+		return &Row{
+			ID:    id,
+			Seq:   seq,
+			Name:  []*string{nil, &name}[id%2],
+			Tags:  []string{"a", "b", "c"}[:id%4],
+			Value: []string{"x", "y"}[id%2],
+		}
+	}
+
+	buffer := parquet.NewBuffer(
+		parquet.SortingColumns(parquet.Ascending("id")),
+		parquet.SortOnInsert(true),
+	)
+	// Mostly ordered ids, with some late rows and duplicates.
+	ids := []int64{1, 2, 4, 5, 3, 6, 8, 7, 9, 0, 5, 10}
+	for seq, id := range ids {
+		if err := buffer.Write(makeRow(id, int64(seq))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	other := parquet.NewBuffer(parquet.SortingColumns(parquet.Ascending("id")))
+	for _, id := range []int64{3, 11} {
+		other.Write(makeRow(id, int64(len(ids))))
+		ids = append(ids, id)
+	}
+	if _, err := buffer.WriteRowGroup(other); err != nil {
+		t.Fatal(err)
+	}
+
+	// The runs are merged by the first call to Rows, reading the columns
+	// before does not mutate the buffer.
+	if buffer.Column(0).NumValues() != int64(len(ids)) || sort.IsSorted(buffer) {
+		t.Fatal("buffer rows were reordered before calling Rows")
+	}
+	buffer.Rows()
+	if !sort.IsSorted(buffer) {
+		t.Fatal("buffer rows are not sorted")
+	}
+
+	reader := parquet.NewRowGroupReader(buffer)
+	prev := &Row{ID: -1}
+	for i := range ids {
+		row := new(Row)
+		if err := reader.Read(row); err != nil {
+			t.Fatal(err)
+		}
+		want := makeRow(row.ID, row.Seq)
+		if ids[row.Seq] != row.ID {
+			t.Fatalf("row %d has id %d but was written with id %d", i, row.ID, ids[row.Seq])
+		}
+		if (row.Name == nil) != (want.Name == nil) || len(row.Tags) != len(want.Tags) || row.Value != want.Value {
+			t.Fatalf("row %d mismatch: want=%+v got=%+v", i, want, row)
+		}
+		if row.ID < prev.ID || (row.ID == prev.ID && row.Seq < prev.Seq) {
+			t.Fatalf("row %d is out of order: %+v after %+v", i, row, prev)
+		}
+		prev = row
+	}
+}
+
+func TestBufferSortOnInsertRuns(t *testing.T) {
+	type Row struct {
+		ID   int64    `parquet:"id"`
+		Tags []string `parquet:"tags"`
+	}
+
+	buffer := parquet.NewBuffer(
+		parquet.SortingColumns(parquet.Ascending("id")),
+		parquet.SortOnInsert(true),
+	)
+	// Descending ids start a new run on every row, interleaved with the Len
+	// calls which must not merge the runs.
+	const numRows = 257
+	for i := numRows - 1; i >= 0; i-- {
+		if err := buffer.Write(&Row{ID: int64(i), Tags: []string{"a", "b", "c"}[:i%4]}); err != nil {
+			t.Fatal(err)
+		}
+		if n := buffer.Len(); n != numRows-i {
+			t.Fatalf("wrong number of rows: want=%d got=%d", numRows-i, n)
+		}
+	}
+
+	reader := parquet.NewRowGroupReader(buffer)
+	for i := 0; i < numRows; i++ {
+		row := Row{}
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if row.ID != int64(i) || len(row.Tags) != i%4 {
+			t.Fatalf("wrong row at index %d: %+v", i, row)
+		}
+	}
+}
+
+func TestBufferKeyColumns(t *testing.T) {
+	type Row struct {
+		Tenant string   `parquet:"tenant,dict"`
+		ID     *int64   `parquet:"id,optional"`
+		Seq    int64    `parquet:"seq"`
+		Tags   []string `parquet:"tags"`
+	}
+
+	makeRow := func(tenant string, id, seq int64) Row {
+		row := Row{Tenant: tenant, Seq: seq, Tags: []string{"a", "b", "c"}[:seq%4]}
+		if id >= 0 {
+			row.ID = &id
+		}
+		return row
+	}
+
+	rows := []Row{
+		makeRow("a", 1, 0),
+		makeRow("b", 1, 1),
+		makeRow("a", 2, 2),
+		makeRow("a", -1, 3),
+		makeRow("a", 1, 4),
+		makeRow("a", -1, 5),
+		makeRow("b", 1, 6),
+	}
+
+	readRows := func(t *testing.T, buffer *parquet.Buffer) []Row {
+		reader := parquet.NewRowGroupReader(buffer)
+		values := make([]Row, buffer.NumRows())
+		for i := range values {
+			if err := reader.Read(&values[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return values
+	}
+
+	seqsOf := func(rows []Row) []int64 {
+		seqs := make([]int64, len(rows))
+		for i, row := range rows {
+			seqs[i] = row.Seq
+			if want := makeRow(row.Tenant, 0, row.Seq); len(row.Tags) != len(want.Tags) {
+				t.Errorf("row %d has %d tags, want %d", i, len(row.Tags), len(want.Tags))
+			}
+		}
+		return seqs
+	}
+
+	for _, test := range []struct {
+		scenario string
+		options  []parquet.RowGroupOption
+		seqs     []int64
+	}{
+		{
+			scenario: "replace",
+			options:  []parquet.RowGroupOption{parquet.KeyColumns(parquet.ReplaceRow, "tenant", "id")},
+			seqs:     []int64{2, 4, 5, 6},
+		},
+		{
+			scenario: "keep",
+			options:  []parquet.RowGroupOption{parquet.KeyColumns(parquet.KeepRow, "tenant", "id")},
+			seqs:     []int64{0, 1, 2, 3},
+		},
+		{
+			scenario: "replace after keep",
+			options: []parquet.RowGroupOption{
+				parquet.KeyColumns(parquet.KeepRow, "tenant", "id"),
+				&parquet.RowGroupConfig{KeyColumns: []string{"tenant", "id"}, KeyConflict: parquet.ReplaceRow},
+			},
+			seqs: []int64{2, 4, 5, 6},
+		},
+		{
+			scenario: "keep after replace",
+			options: []parquet.RowGroupOption{
+				&parquet.RowGroupConfig{KeyColumns: []string{"tenant", "id"}, KeyConflict: parquet.ReplaceRow},
+				parquet.KeyColumns(parquet.KeepRow, "tenant", "id"),
+			},
+			seqs: []int64{0, 1, 2, 3},
+		},
+		{
+			scenario: "sorted",
+			options: []parquet.RowGroupOption{
+				parquet.KeyColumns(parquet.ReplaceRow, "tenant", "id"),
+				parquet.SortingColumns(parquet.Descending("seq")),
+				parquet.SortOnInsert(true),
+			},
+			seqs: []int64{6, 5, 4, 2},
+		},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			buffer := parquet.NewBuffer(test.options...)
+			for _, row := range rows {
+				if err := buffer.Write(row); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if seqs := seqsOf(readRows(t, buffer)); !reflect.DeepEqual(seqs, test.seqs) {
+				t.Errorf("wrong rows after writes: want=%v got=%v", test.seqs, seqs)
+			}
+
+			buffer.Reset()
+			other := parquet.NewBuffer(parquet.SortingColumns(buffer.SortingColumns()...))
+			for _, row := range rows {
+				other.Write(row)
+			}
+			if _, err := buffer.WriteRowGroup(other); err != nil {
+				t.Fatal(err)
+			}
+			if seqs := seqsOf(readRows(t, buffer)); !reflect.DeepEqual(seqs, test.seqs) {
+				t.Errorf("wrong rows after writing row group: want=%v got=%v", test.seqs, seqs)
+			}
+		})
+	}
+
+	t.Run("interleaved", func(t *testing.T) {
+		const numKeys, numRows = 10, 1000
+		buffer := parquet.NewBuffer(parquet.KeyColumns(parquet.ReplaceRow, "tenant", "id"))
+		for i := 0; i < numRows; i++ {
+			if err := buffer.Write(makeRow("a", int64(i%numKeys), int64(i))); err != nil {
+				t.Fatal(err)
+			}
+			want := i + 1
+			if want > numKeys {
+				want = numKeys
+			}
+			if n := buffer.Len(); n != want {
+				t.Fatalf("wrong number of rows after writing row %d: want=%d got=%d", i, want, n)
+			}
+		}
+
+		seqs := seqsOf(readRows(t, buffer))
+		for i, seq := range seqs {
+			if want := int64(numRows - numKeys + i); seq != want {
+				t.Fatalf("wrong row at index %d: want=%d got=%d", i, want, seq)
+			}
+		}
+	})
+
+	t.Run("repeated", func(t *testing.T) {
+		buffer := parquet.NewBuffer(parquet.KeyColumns(parquet.ReplaceRow, "tags"))
+		if err := buffer.Write(rows[0]); err == nil {
+			t.Fatal("expected an error writing to a buffer keyed by a repeated column")
+		}
+	})
+}
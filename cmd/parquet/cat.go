@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func cat(args []string) error {
+	path, err := parseFlags(flag.NewFlagSet("cat", flag.ExitOnError), args)
+	if err != nil {
+		return err
+	}
+	return printRows(path, func(f *parquet.File, w *parquet.JSONWriter) error {
+		return writeRows(w, f, 0, f.NumRows())
+	})
+}
+
+// printRows opens the parquet file at path and calls write to output rows
+// of the file as JSON lines on stdout.
+func printRows(path string, write func(*parquet.File, *parquet.JSONWriter) error) error {
+	f, close, err := openFile(path)
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	output := bufio.NewWriter(os.Stdout)
+	if err := write(f, parquet.NewJSONWriter(output, schemaOf(f))); err != nil {
+		return err
+	}
+	return output.Flush()
+}
+
+// writeRows writes up to limit rows of f to w, starting at rowIndex.
+//
+// The row groups that end before rowIndex are skipped using the row counts
+// from the file metadata, and rows are then seeked to within the first row
+// group, which makes use of the page index when the file has one.
+func writeRows(w *parquet.JSONWriter, f *parquet.File, rowIndex, limit int64) error {
+	var row parquet.Row
+
+	for i, n := 0, f.NumRowGroups(); i < n && limit > 0; i++ {
+		rowGroup := f.RowGroup(i)
+		numRows := rowGroup.NumRows()
+
+		if rowIndex >= numRows {
+			rowIndex -= numRows
+			continue
+		}
+
+		rows := rowGroup.Rows()
+		if err := rows.SeekToRow(rowIndex); err != nil {
+			return err
+		}
+		rowIndex = 0
+
+		for limit > 0 {
+			var err error
+			row, err = rows.ReadRow(row[:0])
+			if err != nil {
+				if err == io.EOF {
+					break
+				}
+				return err
+			}
+			if err := w.WriteRow(row); err != nil {
+				return err
+			}
+			limit--
+		}
+	}
+
+	return nil
+}
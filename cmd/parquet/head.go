@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func head(args []string) error {
+	flags := flag.NewFlagSet("head", flag.ExitOnError)
+	n := flags.Int64("n", 10, "Number of rows to print")
+
+	path, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	return printRows(path, func(f *parquet.File, w *parquet.JSONWriter) error {
+		return writeRows(w, f, 0, *n)
+	})
+}
+
+func tail(args []string) error {
+	flags := flag.NewFlagSet("tail", flag.ExitOnError)
+	n := flags.Int64("n", 10, "Number of rows to print")
+
+	path, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	return printRows(path, func(f *parquet.File, w *parquet.JSONWriter) error {
+		rowIndex := f.NumRows() - *n
+		if rowIndex < 0 {
+			rowIndex = 0
+		}
+		return writeRows(w, f, rowIndex, *n)
+	})
+}
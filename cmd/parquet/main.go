@@ -0,0 +1,116 @@
+// Command parquet is a tool to inspect parquet files.
+//
+// Usage:
+//
+//	parquet schema FILE   print the schema of a parquet file
+//	parquet meta FILE     print the footer, row group, and column chunk metadata
+//	parquet cat FILE      print the rows of a parquet file as JSON lines
+//	parquet head FILE     print the first rows of a parquet file as JSON lines
+//	parquet tail FILE     print the last rows of a parquet file as JSON lines
+//	parquet sample FILE   print rows selected at random as JSON lines
+//
+// The command is built on top of the parquet package, which guarantees that
+// the output reflects what programs using the package see when reading files.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/segmentio/parquet-go"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{name: "schema", usage: "print the schema of a parquet file", run: schema},
+	{name: "meta", usage: "print the footer, row group, and column chunk metadata", run: meta},
+	{name: "cat", usage: "print the rows of a parquet file as JSON lines", run: cat},
+	{name: "head", usage: "print the first rows of a parquet file as JSON lines", run: head},
+	{name: "tail", usage: "print the last rows of a parquet file as JSON lines", run: tail},
+	{name: "sample", usage: "print rows selected at random as JSON lines", run: sample},
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	name, args := flag.Arg(0), flag.Args()[1:]
+
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(args); err != nil {
+				fmt.Fprintf(os.Stderr, "parquet %s: %v\n", name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "parquet: unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	w := flag.CommandLine.Output()
+	fmt.Fprintf(w, "usage: parquet <command> [flags] FILE\n\ncommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-8s %s\n", cmd.name, cmd.usage)
+	}
+}
+
+// parseFlags parses the arguments of a sub-command, which must end with the
+// path to a single parquet file.
+func parseFlags(flags *flag.FlagSet, args []string) (string, error) {
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: parquet %s [flags] FILE\n", flags.Name())
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return "", fmt.Errorf("expected exactly one file argument, got %d", flags.NArg())
+	}
+	return flags.Arg(0), nil
+}
+
+// openFile opens the parquet file at path. The returned function must be
+// called to release the file once the program is done using it.
+func openFile(path string) (*parquet.File, func() error, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+
+	p, err := parquet.OpenFile(f, s.Size())
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return p, f.Close, nil
+}
+
+func schemaOf(f *parquet.File) *parquet.Schema {
+	root := f.Root()
+	return parquet.NewSchema(root.Name(), root)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/segmentio/parquet-go/format"
+)
+
+func meta(args []string) error {
+	path, err := parseFlags(flag.NewFlagSet("meta", flag.ExitOnError), args)
+	if err != nil {
+		return err
+	}
+
+	f, close, err := openFile(path)
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	m := f.Metadata()
+
+	fmt.Fprintf(w, "file:\t%s\n", path)
+	fmt.Fprintf(w, "size:\t%d\n", f.Size())
+	fmt.Fprintf(w, "version:\t%d\n", m.Version)
+	fmt.Fprintf(w, "created by:\t%s\n", m.CreatedBy)
+	fmt.Fprintf(w, "rows:\t%d\n", m.NumRows)
+	fmt.Fprintf(w, "row groups:\t%d\n", len(m.RowGroups))
+	fmt.Fprintf(w, "page index:\t%t\n", len(f.ColumnIndexes()) != 0)
+
+	if len(m.KeyValueMetadata) != 0 {
+		fmt.Fprintf(w, "\nkey/value metadata:\n")
+		for _, kv := range m.KeyValueMetadata {
+			fmt.Fprintf(w, "  %s:\t%s\n", kv.Key, kv.Value)
+		}
+	}
+
+	for i := range m.RowGroups {
+		writeRowGroupMetadata(w, i, &m.RowGroups[i])
+	}
+
+	return w.Flush()
+}
+
+func writeRowGroupMetadata(w io.Writer, index int, rowGroup *format.RowGroup) {
+	fmt.Fprintf(w, "\nrow group %d:\n", index)
+	fmt.Fprintf(w, "  rows:\t%d\n", rowGroup.NumRows)
+	fmt.Fprintf(w, "  total byte size:\t%d\n", rowGroup.TotalByteSize)
+	if rowGroup.TotalCompressedSize != 0 {
+		fmt.Fprintf(w, "  total compressed size:\t%d\n", rowGroup.TotalCompressedSize)
+	}
+	if len(rowGroup.SortingColumns) != 0 {
+		sorting := make([]string, len(rowGroup.SortingColumns))
+		for i, s := range rowGroup.SortingColumns {
+			sorting[i] = fmt.Sprintf("%d", s.ColumnIdx)
+			if s.Descending {
+				sorting[i] += " DESC"
+			}
+		}
+		fmt.Fprintf(w, "  sorting columns:\t%s\n", strings.Join(sorting, ", "))
+	}
+
+	fmt.Fprintf(w, "\n  column\ttype\tcodec\tencodings\tvalues\tcompressed\tuncompressed\toffset\tnulls\tmin\tmax\n")
+
+	for i := range rowGroup.Columns {
+		c := &rowGroup.Columns[i].MetaData
+
+		encodings := make([]string, len(c.Encoding))
+		for j, e := range c.Encoding {
+			encodings[j] = e.String()
+		}
+
+		offset := c.DataPageOffset
+		if c.DictionaryPageOffset != 0 && c.DictionaryPageOffset < offset {
+			offset = c.DictionaryPageOffset
+		}
+
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%d\t%d\t%d\t%d\t%d\t%s\t%s\n",
+			strings.Join(c.PathInSchema, "."),
+			c.Type,
+			c.Codec,
+			strings.Join(encodings, ","),
+			c.NumValues,
+			c.TotalCompressedSize,
+			c.TotalUncompressedSize,
+			offset,
+			c.Statistics.NullCount,
+			formatStatistic(c.Type, c.Statistics.MinValue),
+			formatStatistic(c.Type, c.Statistics.MaxValue),
+		)
+	}
+}
+
+// formatStatistic returns a human-readable representation of a min or max
+// value of a column chunk, which are encoded with the PLAIN encoding.
+func formatStatistic(t format.Type, b []byte) string {
+	if len(b) == 0 {
+		return "-"
+	}
+
+	switch t {
+	case format.Boolean:
+		return strconv.FormatBool(b[0] != 0)
+	case format.Int32:
+		if len(b) == 4 {
+			return strconv.FormatInt(int64(int32(binary.LittleEndian.Uint32(b))), 10)
+		}
+	case format.Int64:
+		if len(b) == 8 {
+			return strconv.FormatInt(int64(binary.LittleEndian.Uint64(b)), 10)
+		}
+	case format.Float:
+		if len(b) == 4 {
+			return strconv.FormatFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), 'g', -1, 32)
+		}
+	case format.Double:
+		if len(b) == 8 {
+			return strconv.FormatFloat(math.Float64frombits(binary.LittleEndian.Uint64(b)), 'g', -1, 64)
+		}
+	case format.ByteArray, format.FixedLenByteArray:
+		const maxLength = 32
+		if utf8.Valid(b) {
+			s := string(b)
+			if len(s) > maxLength {
+				s = s[:maxLength] + "..."
+			}
+			return strconv.Quote(s)
+		}
+		if len(b) > maxLength {
+			return "0x" + hex.EncodeToString(b[:maxLength]) + "..."
+		}
+	}
+
+	return "0x" + hex.EncodeToString(b)
+}
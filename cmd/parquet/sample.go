@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func sample(args []string) error {
+	flags := flag.NewFlagSet("sample", flag.ExitOnError)
+	n := flags.Int64("n", 10, "Number of rows to print")
+	seed := flags.Int64("seed", 0, "Seed of the random number generator (defaults to the current time)")
+
+	path, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	return printRows(path, func(f *parquet.File, w *parquet.JSONWriter) error {
+		for _, rowIndex := range sampleRowIndexes(rand.New(rand.NewSource(*seed)), f.NumRows(), *n) {
+			if err := writeRows(w, f, rowIndex, 1); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// sampleRowIndexes returns n distinct row indexes selected at random in the
+// range [0:numRows), in increasing order.
+//
+// The implementation uses Floyd's algorithm, which only requires memory
+// proportional to the number of selected rows.
+func sampleRowIndexes(prng *rand.Rand, numRows, n int64) []int64 {
+	if n > numRows {
+		n = numRows
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	selected := make(map[int64]struct{}, n)
+	indexes := make([]int64, 0, n)
+
+	for j := numRows - n; j < numRows; j++ {
+		i := prng.Int63n(j + 1)
+		if _, exists := selected[i]; exists {
+			i = j
+		}
+		selected[i] = struct{}{}
+		indexes = append(indexes, i)
+	}
+
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+	return indexes
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func schema(args []string) error {
+	path, err := parseFlags(flag.NewFlagSet("schema", flag.ExitOnError), args)
+	if err != nil {
+		return err
+	}
+
+	f, close, err := openFile(path)
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	s := schemaOf(f)
+	if err := parquet.Print(os.Stdout, s.Name(), s); err != nil {
+		return err
+	}
+	_, err = os.Stdout.WriteString("\n")
+	return err
+}
@@ -0,0 +1,120 @@
+// Command parquetgen generates Go struct types from parquet schemas.
+//
+// The schema is read either from a parquet file, or from a text file
+// containing a schema definition in the format produced by parquet.Print:
+//
+//	parquetgen -package model -type Event -accessors events.parquet > event.go
+//
+// When no input file is given, the schema definition is read from stdin.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/parquetgen"
+)
+
+func main() {
+	var (
+		packageName string
+		typeName    string
+		output      string
+		generator   parquetgen.Generator
+	)
+
+	flag.StringVar(&packageName, "package", "main", "Name of the package that the generated code belongs to")
+	flag.StringVar(&typeName, "type", "", "Name of the generated struct type (defaults to the schema name)")
+	flag.StringVar(&output, "o", "", "Path to the output file (defaults to stdout)")
+	flag.BoolVar(&generator.Accessors, "accessors", false, "Generate getter methods for the struct fields")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: parquetgen [flags] [file.parquet|schema.txt]\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	generator.Package = packageName
+
+	if err := run(&generator, flag.Arg(0), typeName, output); err != nil {
+		fmt.Fprintf(os.Stderr, "parquetgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(generator *parquetgen.Generator, input, typeName, output string) error {
+	schema, err := readSchema(input)
+	if err != nil {
+		return err
+	}
+
+	if typeName == "" {
+		typeName = typeNameOf(schema.Name())
+	}
+
+	src, err := generator.Generate(typeName, schema)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(output, src, 0644)
+}
+
+func readSchema(path string) (*parquet.Schema, error) {
+	if path == "" {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		return parquet.ParseSchema(string(b))
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(b, []byte("PAR1")) {
+		return parquet.ParseSchema(string(b))
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	root := f.Root()
+	name := root.Name()
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return parquet.NewSchema(name, root), nil
+}
+
+func typeNameOf(schemaName string) string {
+	words := strings.FieldsFunc(schemaName, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	name := strings.Join(words, "")
+	if name == "" || !unicode.IsLetter(rune(name[0])) {
+		name = "Row" + name
+	}
+	return name
+}
@@ -32,10 +32,13 @@ type Column struct {
 	encoding    []encoding.Encoding
 	compression []compress.Codec
 
-	depth              int8
-	maxRepetitionLevel int8
-	maxDefinitionLevel int8
+	depth              int16
+	maxRepetitionLevel int16
+	maxDefinitionLevel int16
 	index              int16
+	// Position of the column chunks in the row groups, which may differ from
+	// the index of the column since the columns are sorted by name.
+	chunkIndex int
 }
 
 // Type returns the type of the column.
@@ -102,6 +105,9 @@ func (c *Column) Path() []string { return c.path }
 // Name returns the column name.
 func (c *Column) Name() string { return c.schema.Name }
 
+// ID returns the field id of the column, or zero if it has none.
+func (c *Column) ID() int { return int(c.schema.FieldID) }
+
 // Columns returns the list of child columns.
 //
 // The method returns the same slice across multiple calls, the program must
@@ -247,12 +253,6 @@ func (c *Column) setLevels(depth, repetition, definition, index int) (int, error
 	if index > MaxColumnIndex {
 		return -1, fmt.Errorf("cannot represent parquet rows with more than %d columns: %s", MaxColumnIndex, c.path)
 	}
-	if repetition > MaxRepetitionLevel {
-		return -1, fmt.Errorf("cannot represent parquet columns with more than %d repetition levels: %s", MaxRepetitionLevel, c.path)
-	}
-	if definition > MaxDefinitionLevel {
-		return -1, fmt.Errorf("cannot represent parquet columns with more than %d definition levels: %s", MaxDefinitionLevel, c.path)
-	}
 
 	switch schemaRepetitionTypeOf(c.schema) {
 	case format.Optional:
@@ -262,9 +262,16 @@ func (c *Column) setLevels(depth, repetition, definition, index int) (int, error
 		definition++
 	}
 
-	c.depth = int8(depth)
-	c.maxRepetitionLevel = int8(repetition)
-	c.maxDefinitionLevel = int8(definition)
+	if repetition > MaxRepetitionLevel {
+		return -1, fmt.Errorf("cannot represent parquet columns with more than %d repetition levels: %s", MaxRepetitionLevel, c.path)
+	}
+	if definition > MaxDefinitionLevel {
+		return -1, fmt.Errorf("cannot represent parquet columns with more than %d definition levels: %s", MaxDefinitionLevel, c.path)
+	}
+
+	c.depth = int16(depth)
+	c.maxRepetitionLevel = int16(repetition)
+	c.maxDefinitionLevel = int16(definition)
 	depth++
 
 	if len(c.columns) > 0 {
@@ -310,6 +317,7 @@ func (cl *columnLoader) open(file *File, path []string) (*Column, error) {
 		rowGroups := file.metadata.RowGroups
 		rowGroupColumnIndex := cl.rowGroupColumnIndex
 		cl.rowGroupColumnIndex++
+		c.chunkIndex = rowGroupColumnIndex
 
 		c.chunks = make([]*format.ColumnChunk, 0, len(rowGroups))
 		c.columnIndex = make([]*format.ColumnIndex, 0, len(rowGroups))
@@ -324,19 +332,22 @@ func (cl *columnLoader) open(file *File, path []string) (*Column, error) {
 
 		if len(file.columnIndexes) > 0 {
 			for i := range rowGroups {
-				if rowGroupColumnIndex >= len(file.columnIndexes) {
+				// The page index is laid out by row group, then by column.
+				j := (i * len(rowGroups[i].Columns)) + rowGroupColumnIndex
+				if j >= len(file.columnIndexes) {
 					return nil, fmt.Errorf("row group at index %d does not have enough column index pages", i)
 				}
-				c.columnIndex = append(c.columnIndex, &file.columnIndexes[rowGroupColumnIndex])
+				c.columnIndex = append(c.columnIndex, &file.columnIndexes[j])
 			}
 		}
 
 		if len(file.offsetIndexes) > 0 {
 			for i := range rowGroups {
-				if rowGroupColumnIndex >= len(file.offsetIndexes) {
+				j := (i * len(rowGroups[i].Columns)) + rowGroupColumnIndex
+				if j >= len(file.offsetIndexes) {
 					return nil, fmt.Errorf("row group at index %d does not have enough offset index pages", i)
 				}
-				c.offsetIndex = append(c.offsetIndex, &file.offsetIndexes[rowGroupColumnIndex])
+				c.offsetIndex = append(c.offsetIndex, &file.offsetIndexes[j])
 			}
 		}
 
@@ -351,7 +362,7 @@ func (cl *columnLoader) open(file *File, path []string) (*Column, error) {
 
 		c.compression = make([]compress.Codec, len(c.chunks))
 		for i, chunk := range c.chunks {
-			c.compression[i] = LookupCompressionCodec(chunk.MetaData.Codec)
+			c.compression[i] = file.compressedPageReaderPoolOf(chunk.MetaData.Codec).codec
 		}
 		sortCodecs(c.compression)
 		c.compression = dedupeSortedCodecs(c.compression)
@@ -359,6 +370,9 @@ func (cl *columnLoader) open(file *File, path []string) (*Column, error) {
 	}
 
 	c.typ = &groupType{}
+	if lt := c.schema.LogicalType; lt != nil && (lt.Map != nil || lt.List != nil || lt.Variant != nil) {
+		c.typ = schemaElementTypeOf(c.schema)
+	}
 	c.names = make([]string, numChildren)
 	c.columns = make([]*Column, numChildren)
 
@@ -439,11 +453,16 @@ func schemaElementTypeOf(s *format.SchemaElement) Type {
 			return (*mapType)(lt.Map)
 		case lt.List != nil:
 			return (*listType)(lt.List)
+		case lt.Variant != nil:
+			return (*variantType)(lt.Variant)
 		case lt.Enum != nil:
 			return (*enumType)(lt.Enum)
 		case lt.Decimal != nil:
-			// TODO:
-			// return (*decimalType)(lt.Decimal)
+			// Decimals annotate one of multiple physical types, which is used
+			// to decode the values.
+			if t := schemaPhysicalTypeOf(s); t != nil {
+				return &decimalType{decimal: *lt.Decimal, Type: t}
+			}
 		case lt.Date != nil:
 			return (*dateType)(lt.Date)
 		case lt.Time != nil:
@@ -453,13 +472,21 @@ func schemaElementTypeOf(s *format.SchemaElement) Type {
 		case lt.Integer != nil:
 			return (*intType)(lt.Integer)
 		case lt.Unknown != nil:
-			return (*nullType)(lt.Unknown)
+			// Columns of the NULL logical type are written with a physical
+			// type, which is used to decode the (absent) values.
+			if t := schemaPhysicalTypeOf(s); t != nil {
+				return &nullType{Type: t}
+			}
 		case lt.Json != nil:
 			return (*jsonType)(lt.Json)
 		case lt.Bson != nil:
 			return (*bsonType)(lt.Bson)
 		case lt.UUID != nil:
 			return (*uuidType)(lt.UUID)
+		case lt.Geometry != nil:
+			return (*geometryType)(lt.Geometry)
+		case lt.Geography != nil:
+			return (*geographyType)(lt.Geography)
 		}
 	}
 
@@ -479,7 +506,9 @@ func schemaElementTypeOf(s *format.SchemaElement) Type {
 		case deprecated.Enum:
 			return &enumType{}
 		case deprecated.Decimal:
-			// TODO
+			if t := schemaPhysicalTypeOf(s); t != nil && s.Scale != nil && s.Precision != nil {
+				return &decimalType{decimal: format.DecimalType{Scale: *s.Scale, Precision: *s.Precision}, Type: t}
+			}
 		case deprecated.Date:
 			return &dateType{}
 		case deprecated.TimeMillis:
@@ -515,9 +544,21 @@ func schemaElementTypeOf(s *format.SchemaElement) Type {
 		}
 	}
 
+	if t := schemaPhysicalTypeOf(s); t != nil {
+		// The column only has a physical type, use it directly.
+		return t
+	}
+
+	// If we reach this point, we are likely reading a parquet column that was
+	// written with a non-standard type or is in a newer version of the format
+	// than this package supports.
+	return &unsupportedType{}
+}
+
+// schemaPhysicalTypeOf converts the physical type of s to one of the primitive
+// types supported by this package, returning nil if s has no physical type.
+func schemaPhysicalTypeOf(s *format.SchemaElement) Type {
 	if t := s.Type; t != nil {
-		// The column only has a physical type, convert it to one of the
-		// primitive types supported by this package.
 		switch kind := Kind(*t); kind {
 		case Boolean:
 			return BooleanType
@@ -539,11 +580,7 @@ func schemaElementTypeOf(s *format.SchemaElement) Type {
 			}
 		}
 	}
-
-	// If we reach this point, we are likely reading a parquet column that was
-	// written with a non-standard type or is in a newer version of the format
-	// than this package supports.
-	return &nullType{}
+	return nil
 }
 
 func schemaRepetitionTypeOf(s *format.SchemaElement) format.FieldRepetitionType {
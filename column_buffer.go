@@ -63,7 +63,7 @@ type ColumnBuffer interface {
 	Size() int64
 }
 
-func columnIndexOfNullable(base ColumnBuffer, maxDefinitionLevel int8, definitionLevels []int8) ColumnIndex {
+func columnIndexOfNullable(base ColumnBuffer, maxDefinitionLevel int16, definitionLevels []int16) ColumnIndex {
 	return &nullableColumnIndex{
 		ColumnIndex:        base.ColumnIndex(),
 		maxDefinitionLevel: maxDefinitionLevel,
@@ -73,8 +73,8 @@ func columnIndexOfNullable(base ColumnBuffer, maxDefinitionLevel int8, definitio
 
 type nullableColumnIndex struct {
 	ColumnIndex
-	maxDefinitionLevel int8
-	definitionLevels   []int8
+	maxDefinitionLevel int16
+	definitionLevels   []int16
 }
 
 func (index *nullableColumnIndex) NullPage(i int) bool {
@@ -85,9 +85,9 @@ func (index *nullableColumnIndex) NullCount(i int) int64 {
 	return int64(countLevelsNotEqual(index.definitionLevels, index.maxDefinitionLevel))
 }
 
-type nullOrdering func(column ColumnBuffer, i, j int, maxDefinitionLevel, definitionLevel1, definitionLevel2 int8) bool
+type nullOrdering func(column ColumnBuffer, i, j int, maxDefinitionLevel, definitionLevel1, definitionLevel2 int16) bool
 
-func nullsGoFirst(column ColumnBuffer, i, j int, maxDefinitionLevel, definitionLevel1, definitionLevel2 int8) bool {
+func nullsGoFirst(column ColumnBuffer, i, j int, maxDefinitionLevel, definitionLevel1, definitionLevel2 int16) bool {
 	if definitionLevel1 != maxDefinitionLevel {
 		return definitionLevel2 == maxDefinitionLevel
 	} else {
@@ -95,7 +95,7 @@ func nullsGoFirst(column ColumnBuffer, i, j int, maxDefinitionLevel, definitionL
 	}
 }
 
-func nullsGoLast(column ColumnBuffer, i, j int, maxDefinitionLevel, definitionLevel1, definitionLevel2 int8) bool {
+func nullsGoLast(column ColumnBuffer, i, j int, maxDefinitionLevel, definitionLevel1, definitionLevel2 int16) bool {
 	return definitionLevel1 == maxDefinitionLevel && (definitionLevel2 != maxDefinitionLevel || column.Less(i, j))
 }
 
@@ -121,20 +121,20 @@ func (col *reversedColumnBuffer) Less(i, j int) bool { return col.ColumnBuffer.L
 // column or one of its parent(s) are marked optional.
 type optionalColumnBuffer struct {
 	base               ColumnBuffer
-	maxDefinitionLevel int8
+	maxDefinitionLevel int16
 	rows               []int32
 	sortIndex          []int32
-	definitionLevels   []int8
+	definitionLevels   []int16
 	nullOrdering       nullOrdering
 }
 
-func newOptionalColumnBuffer(base ColumnBuffer, maxDefinitionLevel int8, nullOrdering nullOrdering) *optionalColumnBuffer {
+func newOptionalColumnBuffer(base ColumnBuffer, maxDefinitionLevel int16, nullOrdering nullOrdering) *optionalColumnBuffer {
 	n := base.Cap()
 	return &optionalColumnBuffer{
 		base:               base,
 		maxDefinitionLevel: maxDefinitionLevel,
 		rows:               make([]int32, 0, n),
-		definitionLevels:   make([]int8, 0, n),
+		definitionLevels:   make([]int16, 0, n),
 		nullOrdering:       nullOrdering,
 	}
 }
@@ -144,7 +144,7 @@ func (col *optionalColumnBuffer) Clone() ColumnBuffer {
 		base:               col.base.Clone(),
 		maxDefinitionLevel: col.maxDefinitionLevel,
 		rows:               append([]int32{}, col.rows...),
-		definitionLevels:   append([]int8{}, col.definitionLevels...),
+		definitionLevels:   append([]int16{}, col.definitionLevels...),
 		nullOrdering:       col.nullOrdering,
 	}
 }
@@ -232,7 +232,7 @@ func (col *optionalColumnBuffer) Reset() {
 }
 
 func (col *optionalColumnBuffer) Size() int64 {
-	return sizeOfInt32(col.rows) + sizeOfInt32(col.sortIndex) + sizeOfInt8(col.definitionLevels) + col.base.Size()
+	return sizeOfInt32(col.rows) + sizeOfInt32(col.sortIndex) + sizeOfInt16(col.definitionLevels) + col.base.Size()
 }
 
 func (col *optionalColumnBuffer) Cap() int { return cap(col.rows) }
@@ -335,7 +335,10 @@ func (col *optionalColumnBuffer) ReadRowAt(row Row, index int64) (Row, error) {
 	}
 
 	if definitionLevel := col.definitionLevels[index]; definitionLevel != col.maxDefinitionLevel {
-		row = append(row, Value{definitionLevel: definitionLevel})
+		row = append(row, Value{
+			definitionLevel: definitionLevel,
+			columnIndex:     ^int16(col.Column()),
+		})
 	} else {
 		var err error
 		var n = len(row)
@@ -371,12 +374,13 @@ func (col *optionalColumnBuffer) Values() ValueReader {
 // are marked repeated.
 type repeatedColumnBuffer struct {
 	base               ColumnBuffer
-	maxRepetitionLevel int8
-	maxDefinitionLevel int8
+	maxRepetitionLevel int16
+	maxDefinitionLevel int16
 	rows               []region
-	repetitionLevels   []int8
-	definitionLevels   []int8
+	repetitionLevels   []int16
+	definitionLevels   []int16
 	buffer             []Value
+	offsets            []uint32
 	reordering         *repeatedColumnBuffer
 	nullOrdering       nullOrdering
 }
@@ -388,15 +392,15 @@ type region struct {
 
 func sizeOfRegion(regions []region) int64 { return 8 * int64(len(regions)) }
 
-func newRepeatedColumnBuffer(base ColumnBuffer, maxRepetitionLevel, maxDefinitionLevel int8, nullOrdering nullOrdering) *repeatedColumnBuffer {
+func newRepeatedColumnBuffer(base ColumnBuffer, maxRepetitionLevel, maxDefinitionLevel int16, nullOrdering nullOrdering) *repeatedColumnBuffer {
 	n := base.Cap()
 	return &repeatedColumnBuffer{
 		base:               base,
 		maxRepetitionLevel: maxRepetitionLevel,
 		maxDefinitionLevel: maxDefinitionLevel,
 		rows:               make([]region, 0, n/8),
-		repetitionLevels:   make([]int8, 0, n),
-		definitionLevels:   make([]int8, 0, n),
+		repetitionLevels:   make([]int16, 0, n),
+		definitionLevels:   make([]int16, 0, n),
 		nullOrdering:       nullOrdering,
 	}
 }
@@ -407,8 +411,8 @@ func (col *repeatedColumnBuffer) Clone() ColumnBuffer {
 		maxRepetitionLevel: col.maxRepetitionLevel,
 		maxDefinitionLevel: col.maxDefinitionLevel,
 		rows:               append([]region{}, col.rows...),
-		repetitionLevels:   append([]int8{}, col.repetitionLevels...),
-		definitionLevels:   append([]int8{}, col.definitionLevels...),
+		repetitionLevels:   append([]int16{}, col.repetitionLevels...),
+		definitionLevels:   append([]int16{}, col.definitionLevels...),
 		nullOrdering:       col.nullOrdering,
 	}
 }
@@ -460,13 +464,28 @@ func (col *repeatedColumnBuffer) Page() BufferedPage {
 		column := col.reordering
 		column.Reset()
 
+		// The offsets of rows index the repetition and definition levels,
+		// which include null values that are not written to the base column;
+		// they have to be translated to offsets of values in the base column.
+		if n := len(col.definitionLevels) + 1; n > cap(col.offsets) {
+			col.offsets = make([]uint32, n)
+		}
+		offsets := col.offsets[:len(col.definitionLevels)+1]
+		offsets[0] = 0
+		for i, definitionLevel := range col.definitionLevels {
+			offsets[i+1] = offsets[i]
+			if definitionLevel == col.maxDefinitionLevel {
+				offsets[i+1]++
+			}
+		}
+
 		for _, row := range col.rows {
-			numNulls := countLevelsNotEqual(col.definitionLevels[row.offset:row.offset+row.length], col.maxDefinitionLevel)
-			numValues := int64(row.length) - int64(numNulls)
+			valueOffset := int64(offsets[row.offset])
+			numValues := int64(offsets[row.offset+row.length]) - valueOffset
 
 			for i := int64(0); i < numValues; i++ {
 				var err error
-				if buffer, err = col.base.ReadRowAt(buffer[:0], int64(row.offset)+i); err != nil {
+				if buffer, err = col.base.ReadRowAt(buffer[:0], valueOffset+i); err != nil {
 					return newErrorPage(col.Column(), "reordering rows of repeated column: %w", err)
 				}
 				if err = column.base.WriteRow(buffer); err != nil {
@@ -511,7 +530,7 @@ func (col *repeatedColumnBuffer) Reset() {
 }
 
 func (col *repeatedColumnBuffer) Size() int64 {
-	return sizeOfRegion(col.rows) + sizeOfInt8(col.repetitionLevels) + sizeOfInt8(col.definitionLevels) + col.base.Size()
+	return sizeOfRegion(col.rows) + sizeOfInt16(col.repetitionLevels) + sizeOfInt16(col.definitionLevels) + col.base.Size()
 }
 
 func (col *repeatedColumnBuffer) Cap() int { return cap(col.rows) }
@@ -558,6 +577,9 @@ func (col *repeatedColumnBuffer) WriteValues(values []Value) (numValues int, err
 	}
 
 	if len(continuation) > 0 {
+		if len(col.rows) == 0 {
+			return 0, errValuesStartInTheMiddleOfRow(continuation[0].repetitionLevel)
+		}
 		lastRow := &col.rows[len(col.rows)-1]
 
 		for i, v := range continuation {
@@ -631,6 +653,7 @@ func (col *repeatedColumnBuffer) ReadRowAt(row Row, index int64) (Row, error) {
 			row = append(row, Value{
 				repetitionLevel: repetitionLevels[i],
 				definitionLevel: definitionLevels[i],
+				columnIndex:     ^int16(col.Column()),
 			})
 		} else {
 			var err error
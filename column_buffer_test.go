@@ -2,6 +2,7 @@ package parquet_test
 
 import (
 	"bytes"
+	"io"
 	"testing"
 
 	"github.com/segmentio/parquet-go"
@@ -40,3 +41,37 @@ func TestOptionalDictWriteRowGroup(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestNullValuesPreserveColumnIndex(t *testing.T) {
+	type testStruct struct {
+		A int64    `parquet:"a"`
+		B *string  `parquet:"b,optional"`
+		C []string `parquet:"c"`
+	}
+
+	schema := parquet.SchemaOf(&testStruct{})
+	buffer := parquet.NewBuffer(schema)
+	if err := buffer.WriteRow(schema.Deconstruct(nil, &testStruct{A: 1})); err != nil {
+		t.Fatal("writing row:", err)
+	}
+
+	row, err := buffer.Rows().ReadRow(nil)
+	if err != nil {
+		t.Fatal("reading rows:", err)
+	}
+	for i, value := range row {
+		if value.Column() != i {
+			t.Errorf("wrong column index of value %d (%v): got=%d want=%d", i, value, value.Column(), i)
+		}
+	}
+
+	for i := 0; i < buffer.NumColumns(); i++ {
+		values := make([]parquet.Value, 1)
+		if _, err := buffer.ColumnBuffer(i).Page().Values().ReadValues(values); err != nil && err != io.EOF {
+			t.Fatal("reading values:", err)
+		}
+		if values[0].Column() != i {
+			t.Errorf("wrong column index of page value %d (%v): got=%d want=%d", i, values[0], values[0].Column(), i)
+		}
+	}
+}
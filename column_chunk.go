@@ -1,6 +1,7 @@
 package parquet
 
 import (
+	"fmt"
 	"io"
 )
 
@@ -13,6 +14,11 @@ type ColumnChunk interface {
 	Column() int
 
 	// Returns a reader exposing the pages of the column.
+	//
+	// The reader returns the data pages of the column chunk one at a time, in
+	// the order they appear in the chunk, and io.EOF after the last page. Data
+	// pages encoded with a dictionary expose it from their Dictionary method;
+	// PagesWithDictionary can be used to also receive dictionary pages.
 	Pages() Pages
 
 	// Returns the components of the page index for this column chunk,
@@ -36,11 +42,236 @@ type ColumnChunk interface {
 
 // Pages is an interface implemented by page readers returned by calling the
 // Pages method of ColumnChunk instances.
+//
+// The pages returned by ReadPage are only valid until the next call to
+// ReadPage, or until the reader is closed when the implementation has a Close
+// method, since the buffers holding their data are reused. This applies to the
+// Page values and the readers returned by their PageData method; the values
+// read from the pages, and the pages returned by their Buffer method, do not
+// share memory with the page data and remain valid.
 type Pages interface {
 	PageReader
 	RowSeeker
 }
 
+// closePages releases the resources held by pages if the implementation
+// supports it, which is the case of pages read from parquet files.
+func closePages(pages Pages) error {
+	if c, ok := pages.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// PagesWithDictionary wraps pages to return the dictionary of the column chunk
+// as a page of its own, before the first data page which refers to it.
+//
+// The dictionary page is the page returned by the Page method of the
+// dictionary, programs can tell it apart from data pages since the following
+// page returns this dictionary from its Dictionary method. This is useful to
+// programs that process column chunks page by page and need to see the pages
+// in the same order as they were written in the file, for example to re-encode
+// them. After seeking to a row, the dictionary is returned again before the
+// next data page.
+func PagesWithDictionary(pages Pages) Pages {
+	return &dictionaryPages{base: pages}
+}
+
+type dictionaryPages struct {
+	base       Pages
+	dictionary Dictionary
+	next       Page
+}
+
+func (r *dictionaryPages) ReadPage() (Page, error) {
+	if p := r.next; p != nil {
+		r.next = nil
+		return p, nil
+	}
+	p, err := r.base.ReadPage()
+	if err != nil {
+		return nil, err
+	}
+	if d := p.Dictionary(); d != nil && d != r.dictionary {
+		r.dictionary, r.next = d, p
+		return d.Page(), nil
+	}
+	return p, nil
+}
+
+func (r *dictionaryPages) SeekToRow(rowIndex int64) error {
+	r.dictionary, r.next = nil, nil
+	return r.base.SeekToRow(rowIndex)
+}
+
+func (r *dictionaryPages) Close() error {
+	return closePages(r.base)
+}
+
+// NewColumnChunkValueReader creates a reader exposing the values of a column
+// chunk, without reconstructing the rows that they belong to.
+//
+// The values carry their repetition and definition levels, which programs can
+// use to determine where rows start and which values are null. This is useful
+// to programs that compute aggregates on columns, since they do not pay the
+// cost of reading the other columns of the row group:
+//
+//	columnIndex := parquet.ColumnMappingOf(rowGroup.Schema()).ColumnIndex("path", "to", "column")
+//	values := parquet.NewColumnChunkValueReader(rowGroup.Column(columnIndex))
+//
+// The returned reader also implements RowSeeker, to position it at the values
+// of a given row.
+func NewColumnChunkValueReader(column ColumnChunk) ValueReader {
+	return &columnChunkValueReader{column: column}
+}
+
+type columnChunkValueReader struct {
+	column ColumnChunk
+	pages  Pages
+	values ValueReader
+}
+
+func (r *columnChunkValueReader) ReadValues(values []Value) (int, error) {
+	if r.pages == nil {
+		r.pages = r.column.Pages()
+	}
+	for len(values) > 0 {
+		if r.values == nil {
+			p, err := r.pages.ReadPage()
+			if err != nil {
+				return 0, err
+			}
+			r.values = p.Values()
+		}
+		n, err := r.values.ReadValues(values)
+		if err == io.EOF {
+			r.values = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+	return 0, nil
+}
+
+func (r *columnChunkValueReader) SeekToRow(rowIndex int64) error {
+	if r.pages == nil {
+		r.pages = r.column.Pages()
+	}
+	r.values = nil
+	return r.pages.SeekToRow(rowIndex)
+}
+
+// ColumnChunkDictionary returns the dictionary of a column chunk, or nil if the
+// column chunk is not dictionary encoded.
+//
+// The dictionary exposes the distinct values of the column chunk, indexed by
+// the keys that dictionary encoded pages use to reference them. Programs can
+// use it to check the cardinality of a column, or to resolve the values of a
+// join without decoding the data pages. On column chunks of parquet files, only
+// the dictionary page is read.
+//
+// Note that writers may fall back to other encodings when dictionaries grow too
+// large, in which case some of the pages of the column chunk may not reference
+// the dictionary.
+func ColumnChunkDictionary(column ColumnChunk) (Dictionary, error) {
+	switch c := column.(type) {
+	case *fileColumnChunk:
+		return c.dictionary()
+	case interface{ Dictionary() Dictionary }:
+		return c.Dictionary(), nil
+	}
+	page, err := column.Pages().ReadPage()
+	if err != nil {
+		if err == io.EOF {
+			err = nil
+		}
+		return nil, err
+	}
+	return page.Dictionary(), nil
+}
+
+// ColumnChunkByteRange returns the byte range occupied by a column chunk in its
+// parquet file, including the dictionary page. The returned boolean is false
+// if the column chunk does not belong to a parquet file.
+//
+// Combined with the page locations of the offset index, it allows programs to
+// read the dictionary page, which is not recorded in the offset index: when
+// the column chunk is dictionary encoded, the dictionary page occupies the
+// bytes between offset and the location of the first data page.
+func ColumnChunkByteRange(column ColumnChunk) (offset, length int64, ok bool) {
+	c, ok := column.(*fileColumnChunk)
+	if !ok {
+		return 0, 0, false
+	}
+	metadata := &c.chunk.MetaData
+	offset = metadata.DataPageOffset
+	if metadata.DictionaryPageOffset != 0 {
+		offset = metadata.DictionaryPageOffset
+	}
+	return offset, metadata.TotalCompressedSize, true
+}
+
+// PageInfo carries the information found in the header of a page of a column
+// chunk.
+//
+// The Header field is one of DictionaryPageHeader, DataPageHeaderV1, or
+// DataPageHeaderV2, which expose the page type, number of values, encodings,
+// and null count of the page.
+type PageInfo struct {
+	Header PageHeader
+	// Offset of the page header in the file, and size of the header.
+	Offset     int64
+	HeaderSize int64
+	// Size of the page data, before and after decompression.
+	CompressedSize   int64
+	UncompressedSize int64
+	// Checksum of the page data, zero if the writer did not compute it.
+	CRC uint32
+	// Bounds of the page values decoded from the statistics of data pages,
+	// null if the page header did not contain statistics.
+	MinValue Value
+	MaxValue Value
+}
+
+// PageInfoReader is an interface implemented by types that expose the headers
+// of pages in a column chunk.
+type PageInfoReader interface {
+	// Reads the information of the next page of the column chunk, returning
+	// io.EOF after the last page.
+	ReadPageInfo() (PageInfo, error)
+}
+
+// NewPageInfoReader returns a reader exposing the page headers of a column
+// chunk, including the dictionary page, without reading or decoding the page
+// data. This is useful to tools auditing the layout and encodings of pages,
+// for example:
+//
+//	pages := parquet.NewPageInfoReader(rowGroup.Column(columnIndex))
+//	for {
+//		page, err := pages.ReadPageInfo()
+//		if err != nil {
+//			...
+//		}
+//		fmt.Println(page.Header.PageType(), page.Header.Encoding(), page.CompressedSize)
+//	}
+//
+// Only column chunks of parquet files have page headers, the reader returns an
+// error for other column chunks.
+func NewPageInfoReader(column ColumnChunk) PageInfoReader {
+	c, ok := column.(*fileColumnChunk)
+	if !ok {
+		return &errorPageInfoReader{err: fmt.Errorf("cannot read page headers of column chunk of type %T", column)}
+	}
+	return c.pageInfos()
+}
+
+type errorPageInfoReader struct{ err error }
+
+func (r *errorPageInfoReader) ReadPageInfo() (PageInfo, error) { return PageInfo{}, r.err }
+
 type pageAndValueWriter interface {
 	PageWriter
 	ValueWriter
@@ -62,6 +293,18 @@ func (r *columnChunkReader) buffered() int {
 	return len(r.buffer) - r.offset
 }
 
+func (r *columnChunkReader) close() error {
+	clearValues(r.buffer)
+	r.buffer = r.buffer[:0]
+	r.offset = 0
+	r.page = nil
+	r.values = nil
+	err := closePages(r.reader)
+	r.reader = nil
+	r.column = nil
+	return err
+}
+
 func (r *columnChunkReader) seekToRow(rowIndex int64) error {
 	// TODO: there are a few optimizations we can make here:
 	// * is the row buffered already? => advance the offset
@@ -238,9 +481,9 @@ func (r *columnChunkReader) writeRowsTo(w pageAndValueWriter, limit int64) (numR
 	return numRows, nil
 }
 
-type columnReadRowFunc func(Row, int8, []columnChunkReader) (Row, error)
+type columnReadRowFunc func(Row, int16, []columnChunkReader) (Row, error)
 
-func columnReadRowFuncOf(node Node, columnIndex int, repetitionDepth int8) (int, columnReadRowFunc) {
+func columnReadRowFuncOf(node Node, columnIndex int, repetitionDepth int16) (int, columnReadRowFunc) {
 	var read columnReadRowFunc
 
 	if node.Repeated() {
@@ -261,8 +504,8 @@ func columnReadRowFuncOf(node Node, columnIndex int, repetitionDepth int8) (int,
 }
 
 //go:noinline
-func columnReadRowFuncOfRepeated(read columnReadRowFunc, repetitionDepth int8) columnReadRowFunc {
-	return func(row Row, repetitionLevel int8, columns []columnChunkReader) (Row, error) {
+func columnReadRowFuncOfRepeated(read columnReadRowFunc, repetitionDepth int16) columnReadRowFunc {
+	return func(row Row, repetitionLevel int16, columns []columnChunkReader) (Row, error) {
 		var err error
 
 		for {
@@ -281,7 +524,7 @@ func columnReadRowFuncOfRepeated(read columnReadRowFunc, repetitionDepth int8) c
 }
 
 //go:noinline
-func columnReadRowFuncOfGroup(node Node, columnIndex int, repetitionDepth int8) (int, columnReadRowFunc) {
+func columnReadRowFuncOfGroup(node Node, columnIndex int, repetitionDepth int16) (int, columnReadRowFunc) {
 	names := node.ChildNames()
 	if len(names) == 1 {
 		// Small optimization for a somewhat common case of groups with a single
@@ -296,7 +539,7 @@ func columnReadRowFuncOfGroup(node Node, columnIndex int, repetitionDepth int8)
 		columnIndex, group[i] = columnReadRowFuncOf(node.ChildByName(name), columnIndex, repetitionDepth)
 	}
 
-	return columnIndex, func(row Row, repetitionLevel int8, columns []columnChunkReader) (Row, error) {
+	return columnIndex, func(row Row, repetitionLevel int16, columns []columnChunkReader) (Row, error) {
 		var err error
 
 		for _, read := range group {
@@ -310,11 +553,11 @@ func columnReadRowFuncOfGroup(node Node, columnIndex int, repetitionDepth int8)
 }
 
 //go:noinline
-func columnReadRowFuncOfLeaf(columnIndex int, repetitionDepth int8) (int, columnReadRowFunc) {
+func columnReadRowFuncOfLeaf(columnIndex int, repetitionDepth int16) (int, columnReadRowFunc) {
 	var read columnReadRowFunc
 
 	if repetitionDepth == 0 {
-		read = func(row Row, _ int8, columns []columnChunkReader) (Row, error) {
+		read = func(row Row, _ int16, columns []columnChunkReader) (Row, error) {
 			col := &columns[columnIndex]
 
 			for {
@@ -329,7 +572,7 @@ func columnReadRowFuncOfLeaf(columnIndex int, repetitionDepth int8) (int, column
 			}
 		}
 	} else {
-		read = func(row Row, repetitionLevel int8, columns []columnChunkReader) (Row, error) {
+		read = func(row Row, repetitionLevel int16, columns []columnChunkReader) (Row, error) {
 			col := &columns[columnIndex]
 
 			for {
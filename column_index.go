@@ -6,6 +6,8 @@ import (
 	"github.com/segmentio/parquet-go/internal/bits"
 )
 
+// ColumnIndex is the data structure representing column indexes, which record
+// the bounds and null counts of each page of a column chunk.
 type ColumnIndex interface {
 	// NumPages returns the number of paged in the column index.
 	NumPages() int
@@ -32,6 +34,63 @@ type ColumnIndex interface {
 	IsDescending() bool
 }
 
+// ColumnIndexPage carries the information recorded for a page in a column
+// index.
+type ColumnIndexPage struct {
+	// True if the page contains only null values, in which case the min and
+	// max values are null.
+	NullPage bool
+	// Number of null values in the page.
+	NullCount int64
+	// Bounds of the non-null values of the page, decoded according to the
+	// type of the column.
+	MinValue Value
+	MaxValue Value
+}
+
+// ColumnIndexPages returns the information recorded in a column index for each
+// page of a column chunk, with the min and max values decoded, for example:
+//
+//	for i, page := range parquet.ColumnIndexPages(columnChunk.ColumnIndex()) {
+//		fmt.Printf("page %d: [%v:%v]\n", i, page.MinValue, page.MaxValue)
+//	}
+//
+// The function returns nil if the column index is nil.
+func ColumnIndexPages(index ColumnIndex) []ColumnIndexPage {
+	if index == nil {
+		return nil
+	}
+	pages := make([]ColumnIndexPage, index.NumPages())
+	for i := range pages {
+		pages[i] = ColumnIndexPage{
+			NullPage:  index.NullPage(i),
+			NullCount: index.NullCount(i),
+			MinValue:  index.MinValue(i),
+			MaxValue:  index.MaxValue(i),
+		}
+	}
+	return pages
+}
+
+// ColumnIndexBoundaryOrder returns the order of the min and max values of the
+// pages in a column index, based on the ordering rules of the column's logical
+// type. When the pages are ordered, programs can binary search the column index
+// to find the pages that may contain a value.
+//
+// The function returns format.Unordered if the column index is nil.
+func ColumnIndexBoundaryOrder(index ColumnIndex) format.BoundaryOrder {
+	switch {
+	case index == nil:
+		return format.Unordered
+	case index.IsAscending():
+		return format.Ascending
+	case index.IsDescending():
+		return format.Descending
+	default:
+		return format.Unordered
+	}
+}
+
 // NewColumnIndex constructs a ColumnIndex instance from the given parquet
 // format column index. The kind argument configures the type of values
 func NewColumnIndex(kind Kind, index *format.ColumnIndex) ColumnIndex {
@@ -198,8 +257,8 @@ func (i *baseColumnIndexer) observe(numValues, numNulls int64) {
 
 func (i *baseColumnIndexer) columnIndex(minValues, maxValues [][]byte, minOrder, maxOrder int) format.ColumnIndex {
 	return format.ColumnIndex{
-		NullPages:     i.nullPages,
-		NullCounts:    i.nullCounts,
+		NullPages:     copyBooleans(i.nullPages),
+		NullCounts:    copyInt64s(i.nullCounts),
 		MinValues:     minValues,
 		MaxValues:     maxValues,
 		BoundaryOrder: boundaryOrderOf(minOrder, maxOrder),
@@ -361,6 +420,20 @@ func splitFixedLenByteArrayList(size int, data []byte) [][]byte {
 	return values
 }
 
+// The null pages and null counts are copied because the writer retains the
+// column indexes of all row groups while reusing the indexers.
+func copyBooleans(b []bool) []bool {
+	c := make([]bool, len(b))
+	copy(c, b)
+	return c
+}
+
+func copyInt64s(v []int64) []int64 {
+	c := make([]int64, len(v))
+	copy(c, v)
+	return c
+}
+
 func boundaryOrderOf(minOrder, maxOrder int) format.BoundaryOrder {
 	if minOrder == maxOrder {
 		switch {
@@ -49,8 +49,8 @@ func (i *columnIndexer[T]) ColumnIndex() format.ColumnIndex {
 	minOrder := i.class.order(i.minValues)
 	maxOrder := i.class.order(i.maxValues)
 	return format.ColumnIndex{
-		NullPages:     i.nullPages,
-		NullCounts:    i.nullCounts,
+		NullPages:     copyBooleans(i.nullPages),
+		NullCounts:    copyInt64s(i.nullCounts),
 		MinValues:     minValues,
 		MaxValues:     maxValues,
 		BoundaryOrder: boundaryOrderOf(minOrder, maxOrder),
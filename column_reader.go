@@ -38,14 +38,14 @@ type ColumnReader interface {
 type fileColumnReader struct {
 	remain             int
 	numValues          int
-	maxRepetitionLevel int8
-	maxDefinitionLevel int8
+	maxRepetitionLevel int16
+	maxDefinitionLevel int16
 	repetitions        levelReader
 	definitions        levelReader
 	values             ColumnReader
 }
 
-func newFileColumnReader(values ColumnReader, maxRepetitionLevel, maxDefinitionLevel int8, bufferSize int) *fileColumnReader {
+func newFileColumnReader(values ColumnReader, maxRepetitionLevel, maxDefinitionLevel int16, bufferSize int) *fileColumnReader {
 	repetitionBufferSize := 0
 	definitionBufferSize := 0
 
@@ -83,8 +83,8 @@ func (r *fileColumnReader) ReadValues(values []Value) (int, error) {
 
 	for r.remain > 0 && len(values) > 0 {
 		var err error
-		var repetitionLevels []int8
-		var definitionLevels []int8
+		var repetitionLevels []int16
+		var definitionLevels []int16
 		var numValues = r.remain
 
 		if len(values) < numValues {
@@ -162,10 +162,10 @@ func (r *fileColumnReader) ReadValues(values []Value) (int, error) {
 
 func (r *fileColumnReader) reset(numValues int, repetitions, definitions, values encoding.Decoder) {
 	if repetitions != nil {
-		repetitions.SetBitWidth(bits.Len8(r.maxRepetitionLevel))
+		repetitions.SetBitWidth(bits.Len16(r.maxRepetitionLevel))
 	}
 	if definitions != nil {
-		definitions.SetBitWidth(bits.Len8(r.maxDefinitionLevel))
+		definitions.SetBitWidth(bits.Len16(r.maxDefinitionLevel))
 	}
 	r.remain = numValues
 	r.numValues = numValues
@@ -180,18 +180,18 @@ func (r *fileColumnReader) Reset(encoding.Decoder) {
 
 type levelReader struct {
 	decoder encoding.Decoder
-	levels  []int8
+	levels  []int16
 	offset  int
 	count   int
 }
 
 func makeLevelReader(bufferSize int) levelReader {
 	return levelReader{
-		levels: make([]int8, 0, bufferSize),
+		levels: make([]int16, 0, bufferSize),
 	}
 }
 
-func (r *levelReader) readLevel() (int8, error) {
+func (r *levelReader) readLevel() (int16, error) {
 	for {
 		if r.offset < len(r.levels) {
 			lvl := r.levels[r.offset]
@@ -204,7 +204,7 @@ func (r *levelReader) readLevel() (int8, error) {
 	}
 }
 
-func (r *levelReader) peekLevels() ([]int8, error) {
+func (r *levelReader) peekLevels() ([]int16, error) {
 	if r.offset == len(r.levels) {
 		if err := r.decodeLevels(); err != nil {
 			return nil, err
@@ -227,7 +227,7 @@ func (r *levelReader) discardLevels(n int) {
 }
 
 func (r *levelReader) decodeLevels() error {
-	n, err := r.decoder.DecodeInt8(r.levels[:cap(r.levels)])
+	n, err := r.decoder.DecodeInt16(r.levels[:cap(r.levels)])
 	if n == 0 {
 		return err
 	}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"runtime"
 	"sort"
+	"strconv"
 	"sync"
 
 	"github.com/segmentio/parquet-go/compress"
@@ -62,9 +63,15 @@ var (
 	// Pools of compressed page readers used to retain compression codecs across
 	// page reads to reduce the compute and memory footprint of creating new
 	// decompressors for every new page read in a parquet file.
-	compressedPageReaders = [len(compressionCodecs)]sync.Pool{}
+	compressedPageReaders = [len(compressionCodecs)]compressedPageReaderPool{}
 )
 
+func init() {
+	for i := range compressedPageReaders {
+		compressedPageReaders[i].codec = LookupCompressionCodec(format.CompressionCodec(i))
+	}
+}
+
 // LookupCompressionCodec returns the compression codec associated with the
 // given code.
 //
@@ -79,11 +86,27 @@ func LookupCompressionCodec(codec format.CompressionCodec) compress.Codec {
 	return &unsupported{codec}
 }
 
-func acquireCompressedPageReader(codec format.CompressionCodec, page io.Reader) *compressedPageReader {
-	r, _ := compressedPageReaders[codec].Get().(*compressedPageReader)
+// compressedPageReaderPool is a pool of readers decompressing pages with a
+// codec. The global pools are used for the codecs of the package, files use
+// their own pools for codecs which depend on the file configuration, like zstd
+// codecs with dictionaries.
+type compressedPageReaderPool struct {
+	codec compress.Codec
+	pool  sync.Pool
+}
+
+func compressedPageReaderPoolOf(codec format.CompressionCodec) *compressedPageReaderPool {
+	if codec < 0 || int(codec) >= len(compressedPageReaders) {
+		return &compressedPageReaderPool{codec: &unsupported{codec}}
+	}
+	return &compressedPageReaders[codec]
+}
+
+func acquireCompressedPageReader(pool *compressedPageReaderPool, page io.Reader) *compressedPageReader {
+	r, _ := pool.pool.Get().(*compressedPageReader)
 	if r == nil {
-		r = &compressedPageReader{codec: codec}
-		r.reader, r.err = LookupCompressionCodec(codec).NewReader(page)
+		r = &compressedPageReader{pool: pool}
+		r.reader, r.err = pool.codec.NewReader(page)
 		runtime.SetFinalizer(r, func(r *compressedPageReader) { r.Close() })
 	} else {
 		r.Reset(page)
@@ -93,11 +116,11 @@ func acquireCompressedPageReader(codec format.CompressionCodec, page io.Reader)
 
 func releaseCompressedPageReader(r *compressedPageReader) {
 	r.Reset(nil)
-	compressedPageReaders[r.codec].Put(r)
+	r.pool.pool.Put(r)
 }
 
 type compressedPageReader struct {
-	codec  format.CompressionCodec
+	pool   *compressedPageReaderPool
 	reader compress.Reader
 	err    error
 }
@@ -117,6 +140,55 @@ func (r *compressedPageReader) Reset(page io.Reader) {
 	r.err = r.reader.Reset(page)
 }
 
+// zstdDictionaryKey is the key of the column chunk metadata where writers
+// record the identifier of the dictionary of zstd codecs.
+const zstdDictionaryKey = "zstd.dictionary.id"
+
+// zstdDictionaryIDOf returns the identifier of the dictionary of codec, or zero
+// if it is not a zstd codec configured with a dictionary.
+func zstdDictionaryIDOf(codec compress.Codec) (uint32, error) {
+	z, ok := codec.(*zstd.Codec)
+	if !ok || len(z.Dictionary) == 0 {
+		return 0, nil
+	}
+	return zstd.DictionaryID(z.Dictionary)
+}
+
+// zstdDictionaryIDOfColumnChunk returns the identifier of the dictionary that
+// was used to compress the pages of a column chunk, or zero if there were none.
+func zstdDictionaryIDOfColumnChunk(chunk *format.ColumnChunk) (uint32, error) {
+	if chunk.MetaData.Codec != format.Zstd {
+		return 0, nil
+	}
+	for _, kv := range chunk.MetaData.KeyValueMetadata {
+		if kv.Key == zstdDictionaryKey {
+			id, err := strconv.ParseUint(kv.Value, 10, 32)
+			if err != nil {
+				return 0, fmt.Errorf("invalid zstd dictionary id: %q", kv.Value)
+			}
+			return uint32(id), nil
+		}
+	}
+	return 0, nil
+}
+
+// newZstdDictionaryPools returns pools of readers decompressing pages with
+// zstd codecs configured with each of the dictionaries, indexed by dictionary
+// identifier.
+func newZstdDictionaryPools(dictionaries [][]byte) (map[uint32]*compressedPageReaderPool, error) {
+	pools := make(map[uint32]*compressedPageReaderPool, len(dictionaries))
+	for _, dictionary := range dictionaries {
+		id, err := zstd.DictionaryID(dictionary)
+		if err != nil {
+			return nil, err
+		}
+		codec := Zstd
+		codec.Dictionary = dictionary
+		pools[id] = &compressedPageReaderPool{codec: &codec}
+	}
+	return pools, nil
+}
+
 type unsupported struct{ codec format.CompressionCodec }
 
 func (u *unsupported) String() string {
@@ -152,6 +224,28 @@ func (w unsupportedWriter) Flush() error                { return nil }
 func (w unsupportedWriter) Reset(io.Writer) error       { return nil }
 func (w unsupportedWriter) Write(b []byte) (int, error) { return 0, w.error() }
 
+// ColumnCodec associates a compression codec with a column, it is used to
+// configure the compression of columns on parquet writers.
+type ColumnCodec struct {
+	// The path of the leaf column that the codec applies to.
+	Path []string
+
+	// The compression codec used to compress the pages of the column.
+	Codec compress.Codec
+}
+
+// searchColumnCodec returns the codec configured for the column at the given
+// path, or nil if there were none. When the path is configured multiple times,
+// the last codec takes precedence.
+func searchColumnCodec(codecs []ColumnCodec, path columnPath) compress.Codec {
+	for i := len(codecs) - 1; i >= 0; i-- {
+		if path.equal(codecs[i].Path) {
+			return codecs[i].Codec
+		}
+	}
+	return nil
+}
+
 func sortCodecs(codecs []compress.Codec) {
 	if len(codecs) > 1 {
 		sort.Slice(codecs, func(i, j int) bool {
@@ -3,6 +3,7 @@ package compress_test
 import (
 	"bytes"
 	"io"
+	"os"
 	"testing"
 	"testing/iotest"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/segmentio/parquet-go/compress/brotli"
 	"github.com/segmentio/parquet-go/compress/gzip"
 	"github.com/segmentio/parquet-go/compress/lz4"
+	"github.com/segmentio/parquet-go/compress/lzo"
 	"github.com/segmentio/parquet-go/compress/snappy"
 	"github.com/segmentio/parquet-go/compress/uncompressed"
 	"github.com/segmentio/parquet-go/compress/zstd"
@@ -49,6 +51,11 @@ func TestCompressionCodec(t *testing.T) {
 			scenario: "lz4",
 			codec:    new(lz4.Codec),
 		},
+
+		{
+			scenario: "lzo",
+			codec:    &lzo.Codec{BlockSize: 4096},
+		},
 	}
 
 	buffer := new(bytes.Buffer)
@@ -103,3 +110,57 @@ func TestCompressionCodec(t *testing.T) {
 		})
 	}
 }
+
+func TestZstdDictionary(t *testing.T) {
+	dictionary, err := os.ReadFile("../fixtures/zstd.dict")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := zstd.DictionaryID(dictionary); err != nil {
+		t.Fatal(err)
+	} else if id != 42 {
+		t.Errorf("wrong dictionary id: want=42 got=%d", id)
+	}
+	if _, err := zstd.DictionaryID([]byte("not a dictionary")); err == nil {
+		t.Error("reading the id of an invalid dictionary did not fail")
+	}
+
+	codec := &zstd.Codec{Dictionary: dictionary}
+	content := []byte("method=GET path=/api/v1/users/1234 status=200 user_agent=Mozilla/5.0 (X11; Linux x86_64)")
+
+	compressed := new(bytes.Buffer)
+	w, err := codec.NewWriter(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := codec.NewReader(bytes.NewReader(compressed.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	decompressed, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(content, decompressed) {
+		t.Errorf("content mismatch after compressing and decompressing:\n%q\n%q", content, decompressed)
+	}
+
+	// The frames reference the dictionary, they cannot be decompressed
+	// without it.
+	r, err = new(zstd.Codec).NewReader(bytes.NewReader(compressed.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if _, err := io.ReadAll(r); err == nil {
+		t.Error("decompressing without the dictionary did not fail")
+	}
+}
@@ -0,0 +1,192 @@
+// Package lzo implements the LZO parquet compression codec.
+//
+// The codec reads and writes pages in the format produced by the Hadoop LZO
+// codec, which frames LZO1X compressed blocks with their uncompressed and
+// compressed sizes. Pages holding a raw LZO1X stream can also be read.
+//
+// The parquet package only registers the codec to read files when it is built
+// with the parquet.lzo build tag.
+package lzo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/segmentio/parquet-go/compress"
+	"github.com/segmentio/parquet-go/format"
+)
+
+const (
+	// DefaultBlockSize is the maximum size of uncompressed blocks written in
+	// the Hadoop LZO framing.
+	DefaultBlockSize = 256 * 1024
+)
+
+var errCorrupted = errors.New("lzo: corrupted input")
+
+type Codec struct {
+	// The maximum size of uncompressed blocks, defaults to DefaultBlockSize.
+	BlockSize int
+}
+
+func (c *Codec) String() string {
+	return "LZO"
+}
+
+func (c *Codec) CompressionCodec() format.CompressionCodec {
+	return format.LZO
+}
+
+func (c *Codec) NewReader(r io.Reader) (compress.Reader, error) {
+	return &reader{input: r, offset: -1}, nil
+}
+
+func (c *Codec) NewWriter(w io.Writer) (compress.Writer, error) {
+	return &writer{output: w, blockSize: c.blockSize()}, nil
+}
+
+func (c *Codec) blockSize() int {
+	if c.BlockSize > 0 {
+		return c.BlockSize
+	}
+	return DefaultBlockSize
+}
+
+type reader struct {
+	input  io.Reader
+	buffer bytes.Buffer
+	offset int
+	data   []byte
+}
+
+func (r *reader) Close() error {
+	r.Reset(r.input)
+	return nil
+}
+
+func (r *reader) Reset(rr io.Reader) error {
+	r.input = rr
+	r.buffer.Reset()
+	r.offset = -1
+	r.data = r.data[:0]
+	return nil
+}
+
+func (r *reader) Read(b []byte) (int, error) {
+	if r.offset < 0 {
+		if r.input == nil {
+			return 0, io.EOF
+		}
+
+		_, err := r.buffer.ReadFrom(r.input)
+		if err != nil {
+			return 0, err
+		}
+
+		r.data, err = decodeBlocks(r.data[:0], r.buffer.Bytes())
+		if err != nil {
+			// The input may not have been framed by the Hadoop codec.
+			r.data, err = Decode(r.data[:0], r.buffer.Bytes())
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		r.offset = 0
+	}
+
+	n := copy(b, r.data[r.offset:])
+	r.offset += n
+	if r.offset == len(r.data) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+type writer struct {
+	output    io.Writer
+	blockSize int
+	buffer    []byte
+	data      []byte
+}
+
+func (w *writer) Close() error {
+	if w.output == nil {
+		w.buffer = w.buffer[:0]
+		return nil
+	}
+	w.data = encodeBlocks(w.data[:0], w.buffer, w.blockSize)
+	w.buffer = w.buffer[:0]
+	_, err := w.output.Write(w.data)
+	w.data = w.data[:0]
+	return err
+}
+
+func (w *writer) Reset(ww io.Writer) error {
+	w.output = ww
+	w.buffer = w.buffer[:0]
+	w.data = w.data[:0]
+	return nil
+}
+
+func (w *writer) Write(b []byte) (int, error) {
+	w.buffer = append(w.buffer, b...)
+	return len(b), nil
+}
+
+// decodeBlocks appends to dst the data decompressed from src in the Hadoop LZO
+// framing: each block starts with its uncompressed size, followed by chunks
+// of LZO1X compressed data prefixed with their size, until the uncompressed
+// size of the block was produced. All sizes are 32 bits big-endian integers.
+func decodeBlocks(dst, src []byte) ([]byte, error) {
+	for len(src) > 0 {
+		if len(src) < 4 {
+			return dst, errCorrupted
+		}
+		blockSize := int(binary.BigEndian.Uint32(src))
+		blockStart := len(dst)
+		src = src[4:]
+
+		for len(dst)-blockStart < blockSize {
+			if len(src) < 4 {
+				return dst, errCorrupted
+			}
+			chunkSize := int(binary.BigEndian.Uint32(src))
+			src = src[4:]
+			if chunkSize > len(src) {
+				return dst, errCorrupted
+			}
+			var err error
+			if dst, err = Decode(dst, src[:chunkSize]); err != nil {
+				return dst, err
+			}
+			src = src[chunkSize:]
+		}
+
+		if len(dst)-blockStart != blockSize {
+			return dst, errCorrupted
+		}
+	}
+	return dst, nil
+}
+
+// encodeBlocks appends to dst the data compressed from src in the Hadoop LZO
+// framing, with one chunk of compressed data per block.
+func encodeBlocks(dst, src []byte, blockSize int) []byte {
+	for len(src) > 0 {
+		block := src
+		if len(block) > blockSize {
+			block = block[:blockSize]
+		}
+		src = src[len(block):]
+
+		offset := len(dst)
+		dst = append(dst, 0, 0, 0, 0, 0, 0, 0, 0)
+		dst = Encode(dst, block)
+		binary.BigEndian.PutUint32(dst[offset:], uint32(len(block)))
+		binary.BigEndian.PutUint32(dst[offset+4:], uint32(len(dst)-offset-8))
+	}
+	return dst
+}
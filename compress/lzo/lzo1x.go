@@ -0,0 +1,307 @@
+package lzo
+
+import "encoding/binary"
+
+// The LZO1X format is a sequence of literal runs and matches, terminated by a
+// match instruction with a zero distance. Each instruction starts with a byte
+// whose value selects how the following bytes are interpreted:
+//
+//	0-15    literal run, or match of the M1 kind after a match
+//	16-31   match of the M4 kind, distances in 16K..48K
+//	32-63   match of the M3 kind, distances up to 16K
+//	64-255  match of the M2 kind, distances up to 2K and lengths up to 8
+//
+// The two low bits of the last but one byte of matches encode the number of
+// literals (up to 3) copied after the match; longer literal runs are encoded
+// with their own instruction.
+const (
+	m2MaxLength  = 8
+	m2MaxOffset  = 0x0800
+	m3MaxOffset  = 0x4000
+	m4MaxOffset  = 0xBFFF
+	m3MaxLength  = 33
+	m4MaxLength  = 9
+	minMatchSize = 3
+)
+
+// Decode appends to dst the data decompressed from the LZO1X stream in src.
+func Decode(dst, src []byte) ([]byte, error) {
+	base := len(dst)
+	i := 0
+
+	next := func() (int, bool) {
+		if i == len(src) {
+			return 0, false
+		}
+		b := src[i]
+		i++
+		return int(b), true
+	}
+
+	// length reads the extension of instruction lengths, which are encoded as
+	// a sequence of zero bytes each adding 255 to the length, and a final
+	// non-zero byte.
+	length := func(n int) (int, bool) {
+		for i < len(src) && src[i] == 0 {
+			n += 255
+			i++
+		}
+		b, ok := next()
+		return n + b, ok
+	}
+
+	literals := func(n int) bool {
+		if n > len(src)-i {
+			return false
+		}
+		dst = append(dst, src[i:i+n]...)
+		i += n
+		return true
+	}
+
+	match := func(distance, n int) bool {
+		if distance <= 0 || distance > len(dst)-base {
+			return false
+		}
+		// The copy is done byte by byte because the source may overlap with
+		// the bytes being written when the distance is less than the length.
+		for j := len(dst) - distance; n > 0; n-- {
+			dst = append(dst, dst[j])
+			j++
+		}
+		return true
+	}
+
+	const (
+		stateLiteralRun = iota
+		stateFirstLiteralRun
+		stateMatch
+	)
+
+	state := stateLiteralRun
+	t := 0
+
+	if len(src) > 0 && src[0] > 17 {
+		t = int(src[0]) - 17
+		i++
+		if !literals(t) {
+			return dst, errCorrupted
+		}
+		if t < 4 {
+			t, state = -1, stateMatch
+		} else {
+			state = stateFirstLiteralRun
+		}
+	}
+
+	for {
+		var ok bool
+
+		switch state {
+		case stateLiteralRun:
+			if t, ok = next(); !ok {
+				return dst, errCorrupted
+			}
+			if t >= 16 {
+				state = stateMatch
+				continue
+			}
+			if t == 0 {
+				if t, ok = length(15); !ok {
+					return dst, errCorrupted
+				}
+			}
+			if !literals(t + 3) {
+				return dst, errCorrupted
+			}
+			state = stateFirstLiteralRun
+			continue
+
+		case stateFirstLiteralRun:
+			if t, ok = next(); !ok {
+				return dst, errCorrupted
+			}
+			if t >= 16 {
+				state = stateMatch
+				continue
+			}
+			// Three bytes match with a distance greater than the maximum of
+			// M2 matches, only valid right after a literal run.
+			b, ok := next()
+			if !ok || !match(1+m2MaxOffset+(t>>2)+(b<<2), 3) {
+				return dst, errCorrupted
+			}
+
+		case stateMatch:
+			if t < 0 {
+				if t, ok = next(); !ok {
+					return dst, errCorrupted
+				}
+			}
+			switch {
+			case t >= 64:
+				b, ok := next()
+				if !ok || !match(1+((t>>2)&7)+(b<<3), (t>>5)+1) {
+					return dst, errCorrupted
+				}
+			case t >= 32:
+				n := t & 31
+				if n == 0 {
+					if n, ok = length(31); !ok {
+						return dst, errCorrupted
+					}
+				}
+				if len(src)-i < 2 {
+					return dst, errCorrupted
+				}
+				distance := 1 + int(binary.LittleEndian.Uint16(src[i:])>>2)
+				i += 2
+				if !match(distance, n+2) {
+					return dst, errCorrupted
+				}
+			case t >= 16:
+				n := t & 7
+				if n == 0 {
+					if n, ok = length(7); !ok {
+						return dst, errCorrupted
+					}
+				}
+				if len(src)-i < 2 {
+					return dst, errCorrupted
+				}
+				distance := (t&8)<<11 + int(binary.LittleEndian.Uint16(src[i:])>>2)
+				i += 2
+				if distance == 0 {
+					if i != len(src) {
+						return dst, errCorrupted
+					}
+					return dst, nil
+				}
+				if !match(distance+0x4000, n+2) {
+					return dst, errCorrupted
+				}
+			default:
+				// Two bytes match, only valid right after a match.
+				b, ok := next()
+				if !ok || !match(1+(t>>2)+(b<<2), 2) {
+					return dst, errCorrupted
+				}
+			}
+		}
+
+		// The number of literals copied after the match is encoded in the
+		// last but one byte of the match.
+		if t = int(src[i-2]) & 3; t == 0 {
+			state = stateLiteralRun
+			continue
+		}
+		if !literals(t) {
+			return dst, errCorrupted
+		}
+		t, state = -1, stateMatch
+	}
+}
+
+// Encode appends to dst the LZO1X stream compressing src.
+func Encode(dst, src []byte) []byte {
+	const (
+		tableBits = 14
+		tableSize = 1 << tableBits
+	)
+
+	// The table holds the positions (plus one) of the last sequence of four
+	// bytes seen with each hash, to search for matches in the input.
+	var table [tableSize]int32
+
+	hash := func(i int) uint32 {
+		return (binary.LittleEndian.Uint32(src[i:]) * 0x1E35A7BD) >> (32 - tableBits)
+	}
+
+	state := -1 // index of the byte holding the number of trailing literals
+	start := 0  // index of the first pending literal
+	i := 0
+
+	for i+4 <= len(src) {
+		h := hash(i)
+		j := int(table[h]) - 1
+		table[h] = int32(i + 1)
+
+		if j < 0 || i-j > m4MaxOffset || binary.LittleEndian.Uint32(src[i:]) != binary.LittleEndian.Uint32(src[j:]) {
+			i++
+			continue
+		}
+
+		n := 4
+		for i+n < len(src) && src[i+n] == src[j+n] {
+			n++
+		}
+
+		dst = appendLiterals(dst, src[start:i], state)
+		dst, state = appendMatch(dst, i-j, n)
+		i += n
+		start = i
+	}
+
+	dst = appendLiterals(dst, src[start:], state)
+	// The end of the stream is marked by a M4 match with a zero distance.
+	return append(dst, 16|1, 0, 0)
+}
+
+func appendLiterals(dst, literals []byte, state int) []byte {
+	n := len(literals)
+	switch {
+	case n == 0:
+		return dst
+	case state < 0 && n <= 238:
+		// The first literal run of the stream has a compact encoding.
+		dst = append(dst, byte(17+n))
+	case state >= 0 && n <= 3:
+		dst[state] |= byte(n)
+	case n <= 18:
+		dst = append(dst, byte(n-3))
+	default:
+		dst = append(dst, 0)
+		dst = appendLength(dst, n-3-15)
+	}
+	return append(dst, literals...)
+}
+
+func appendMatch(dst []byte, distance, n int) ([]byte, int) {
+	switch {
+	case distance <= m2MaxOffset && n <= m2MaxLength:
+		distance--
+		dst = append(dst, byte((n-1)<<5|(distance&7)<<2), byte(distance>>3))
+		return dst, len(dst) - 2
+
+	case distance <= m3MaxOffset:
+		distance--
+		if n <= m3MaxLength {
+			dst = append(dst, byte(32|(n-2)))
+		} else {
+			dst = append(dst, 32)
+			dst = appendLength(dst, n-2-31)
+		}
+
+	default:
+		distance -= 0x4000
+		t := byte(16 | (distance>>11)&8)
+		if n <= m4MaxLength {
+			dst = append(dst, t|byte(n-2))
+		} else {
+			dst = append(dst, t)
+			dst = appendLength(dst, n-2-7)
+		}
+		distance &= 0x3FFF
+	}
+
+	dst = append(dst, byte(distance<<2), byte(distance>>6))
+	return dst, len(dst) - 2
+}
+
+func appendLength(dst []byte, n int) []byte {
+	for n > 255 {
+		dst = append(dst, 0)
+		n -= 255
+	}
+	return append(dst, byte(n))
+}
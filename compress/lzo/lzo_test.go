@@ -0,0 +1,77 @@
+package lzo_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/segmentio/parquet-go/compress/lzo"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	tests := []struct {
+		scenario string
+		data     []byte
+	}{
+		{scenario: "empty"},
+		{scenario: "short", data: []byte("abc")},
+		{scenario: "repeated", data: bytes.Repeat([]byte("a"), 10000)},
+		{scenario: "text", data: bytes.Repeat([]byte("the quick brown fox jumps over the lazy dog. "), 500)},
+		{scenario: "incompressible", data: pseudoRandomBytes(100000)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			compressed := lzo.Encode(nil, test.data)
+			decompressed, err := lzo.Decode(nil, compressed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(test.data, decompressed) {
+				t.Errorf("content mismatch after compressing and decompressing:\n%q\n%q", test.data, decompressed)
+			}
+		})
+	}
+}
+
+func TestDecodeCorrupted(t *testing.T) {
+	compressed := lzo.Encode(nil, bytes.Repeat([]byte("0123456789"), 100))
+
+	for i := 0; i < len(compressed)-1; i++ {
+		if _, err := lzo.Decode(nil, compressed[:i]); err == nil {
+			t.Errorf("decoding the first %d bytes of the stream did not fail", i)
+		}
+	}
+}
+
+func TestReadRawStream(t *testing.T) {
+	// Pages written by other implementations may hold a LZO1X stream without
+	// the Hadoop framing.
+	data := bytes.Repeat([]byte("1234567890qwertyuiopasdfghjklzxcvbnm"), 100)
+
+	r, err := new(lzo.Codec).NewReader(bytes.NewReader(lzo.Encode(nil, data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	decompressed, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, decompressed) {
+		t.Errorf("content mismatch after decompressing:\n%q\n%q", data, decompressed)
+	}
+}
+
+func pseudoRandomBytes(n int) []byte {
+	b := make([]byte, n)
+	x := uint32(1)
+	for i := range b {
+		x ^= x << 13
+		x ^= x >> 17
+		x ^= x << 5
+		b[i] = byte(x)
+	}
+	return b
+}
@@ -2,6 +2,8 @@
 package zstd
 
 import (
+	"encoding/binary"
+	"errors"
 	"io"
 
 	"github.com/klauspost/compress/zstd"
@@ -38,6 +40,12 @@ const (
 type Codec struct {
 	Level       Level
 	Concurrency int
+
+	// Dictionary is a pre-trained zstd dictionary (e.g. produced by the
+	// "zstd --train" command) used to compress pages, which improves the
+	// compression ratio of many small pages with similar content. Readers
+	// must be configured with the same dictionary to decompress the pages.
+	Dictionary []byte
 }
 
 func (c *Codec) String() string {
@@ -49,9 +57,13 @@ func (c *Codec) CompressionCodec() format.CompressionCodec {
 }
 
 func (c *Codec) NewReader(r io.Reader) (compress.Reader, error) {
-	z, err := zstd.NewReader(r,
+	options := []zstd.DOption{
 		zstd.WithDecoderConcurrency(c.concurrency()),
-	)
+	}
+	if len(c.Dictionary) > 0 {
+		options = append(options, zstd.WithDecoderDicts(c.Dictionary))
+	}
+	z, err := zstd.NewReader(r, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -59,12 +71,16 @@ func (c *Codec) NewReader(r io.Reader) (compress.Reader, error) {
 }
 
 func (c *Codec) NewWriter(w io.Writer) (compress.Writer, error) {
-	z, err := zstd.NewWriter(nonNilWriter(w),
+	options := []zstd.EOption{
 		zstd.WithEncoderConcurrency(c.concurrency()),
 		zstd.WithEncoderLevel(c.level()),
 		zstd.WithZeroFrames(true),
 		zstd.WithEncoderCRC(false),
-	)
+	}
+	if len(c.Dictionary) > 0 {
+		options = append(options, zstd.WithEncoderDict(c.Dictionary))
+	}
+	z, err := zstd.NewWriter(nonNilWriter(w), options...)
 	if err != nil {
 		return nil, err
 	}
@@ -85,6 +101,26 @@ func (c *Codec) level() Level {
 	return DefaultLevel
 }
 
+// DictionaryID returns the identifier recorded in the header of a zstd
+// dictionary, which is also written to the frames compressed with it.
+//
+// The function returns an error if the dictionary is not in the zstd
+// dictionary format.
+func DictionaryID(dictionary []byte) (uint32, error) {
+	if len(dictionary) < 8 || binary.LittleEndian.Uint32(dictionary) != dictionaryMagic {
+		return 0, errInvalidDictionary
+	}
+	id := binary.LittleEndian.Uint32(dictionary[4:])
+	if id == 0 {
+		return 0, errInvalidDictionary
+	}
+	return id, nil
+}
+
+const dictionaryMagic = 0xEC30A437
+
+var errInvalidDictionary = errors.New("invalid zstd dictionary")
+
 type reader struct{ *zstd.Decoder }
 
 func (r reader) Close() error { r.Decoder.Close(); return nil }
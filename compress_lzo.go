@@ -0,0 +1,22 @@
+//go:build parquet.lzo
+
+package parquet
+
+import (
+	"github.com/segmentio/parquet-go/compress/lzo"
+	"github.com/segmentio/parquet-go/format"
+)
+
+// Lzo is the LZO parquet compression codec.
+//
+// The codec is only registered when the package is built with the parquet.lzo
+// build tag; it is deprecated by the parquet format but is still found in
+// files written by legacy Hadoop applications.
+var Lzo = lzo.Codec{
+	BlockSize: lzo.DefaultBlockSize,
+}
+
+func init() {
+	compressionCodecs[format.LZO] = &Lzo
+	compressedPageReaders[format.LZO].codec = &Lzo
+}
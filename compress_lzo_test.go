@@ -0,0 +1,58 @@
+//go:build parquet.lzo
+
+package parquet_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/format"
+)
+
+func TestLzoCompression(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	const numRows = 1000
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.Compression(&parquet.Lzo))
+	for i := 0; i < numRows; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Name: fmt.Sprint("row-", i%10)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, chunk := range f.Metadata().RowGroups[0].Columns {
+		if codec := chunk.MetaData.Codec; codec != format.LZO {
+			t.Errorf("wrong compression codec: want=%s got=%s", format.LZO, codec)
+		}
+	}
+
+	reader := parquet.NewReader(f)
+
+	for i := 0; ; i++ {
+		row := Row{}
+		if err := reader.Read(&row); err != nil {
+			if err == io.EOF && i == numRows {
+				break
+			}
+			t.Fatalf("reading row %d: %v", i, err)
+		}
+		if want := (Row{ID: int64(i), Name: fmt.Sprint("row-", i%10)}); row != want {
+			t.Fatalf("wrong row %d: want=%+v got=%+v", i, want, row)
+		}
+	}
+}
@@ -169,6 +169,7 @@ func (r *concatenatedPages) ReadPage() (Page, error) {
 			if err == nil || err != io.EOF {
 				return p, err
 			}
+			closePages(r.pages)
 			r.pages = nil
 		}
 		if r.index == len(r.column.chunks) {
@@ -182,6 +183,7 @@ func (r *concatenatedPages) ReadPage() (Page, error) {
 func (r *concatenatedPages) SeekToRow(rowIndex int64) error {
 	rowGroups := r.column.rowGroup.rowGroups
 	numRows := int64(0)
+	closePages(r.pages)
 	r.pages = nil
 	r.index = 0
 
@@ -201,3 +203,10 @@ func (r *concatenatedPages) SeekToRow(rowIndex int64) error {
 	}
 	return nil
 }
+
+func (r *concatenatedPages) Close() error {
+	err := closePages(r.pages)
+	r.pages = nil
+	r.index = len(r.column.chunks)
+	return err
+}
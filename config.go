@@ -3,6 +3,13 @@ package parquet
 import (
 	"fmt"
 	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/segmentio/parquet-go/compress"
+	"github.com/segmentio/parquet-go/compress/zstd"
+	"github.com/segmentio/parquet-go/encoding"
+	"github.com/segmentio/parquet-go/format"
 )
 
 const (
@@ -14,6 +21,9 @@ const (
 	DefaultDataPageStatistics   = false
 	DefaultSkipPageIndex        = false
 	DefaultSkipBloomFilters     = false
+	DefaultCSVComma             = ','
+	DefaultCSVPathSeparator     = "."
+	DefaultCSVListSeparator     = ";"
 )
 
 // The FileConfig type carries configuration options for parquet files.
@@ -27,8 +37,15 @@ const (
 //	})
 //
 type FileConfig struct {
-	SkipPageIndex    bool
-	SkipBloomFilters bool
+	SkipPageIndex            bool
+	SkipBloomFilters         bool
+	StrictValidation         bool
+	SkipCorrupted            func(error)
+	Observer                 *ReaderObserver
+	Decryption               *DecryptionConfig
+	MemoryLimit              int64
+	ZstdDictionaries         [][]byte
+	CompressionCodecResolver func(format.CompressionCodec) compress.Codec
 }
 
 // DefaultFileConfig returns a new FileConfig value initialized with the
@@ -61,14 +78,26 @@ func (c *FileConfig) Apply(options ...FileOption) {
 // ConfigureFile applies configuration options from c to config.
 func (c *FileConfig) ConfigureFile(config *FileConfig) {
 	*config = FileConfig{
-		SkipPageIndex:    config.SkipPageIndex,
-		SkipBloomFilters: config.SkipBloomFilters,
+		SkipPageIndex:            config.SkipPageIndex,
+		SkipBloomFilters:         config.SkipBloomFilters,
+		StrictValidation:         config.StrictValidation,
+		SkipCorrupted:            coalesceReport(c.SkipCorrupted, config.SkipCorrupted),
+		Observer:                 coalesceReaderObserver(c.Observer, config.Observer),
+		Decryption:               coalesceDecryption(c.Decryption, config.Decryption),
+		MemoryLimit:              coalesceInt64(c.MemoryLimit, config.MemoryLimit),
+		ZstdDictionaries:         coalesceZstdDictionaries(c.ZstdDictionaries, config.ZstdDictionaries),
+		CompressionCodecResolver: coalesceCompressionCodecResolver(c.CompressionCodecResolver, config.CompressionCodecResolver),
 	}
 }
 
 // Validate returns a non-nil error if the configuration of c is invalid.
 func (c *FileConfig) Validate() error {
-	return nil
+	const baseName = "parquet.(*FileConfig)."
+	return errorInvalidConfiguration(
+		validateDecryption(baseName+"Decryption", c.Decryption),
+		validateNonNegativeInt64(baseName+"MemoryLimit", c.MemoryLimit),
+		validateZstdDictionaries(baseName+"ZstdDictionaries", c.ZstdDictionaries),
+	)
 }
 
 // The ReaderConfig type carries configuration options for parquet readers.
@@ -81,7 +110,13 @@ func (c *FileConfig) Validate() error {
 //	})
 //
 type ReaderConfig struct {
-	Schema *Schema
+	Schema                   *Schema
+	StrictValidation         bool
+	SkipCorrupted            func(error)
+	Observer                 *ReaderObserver
+	MemoryLimit              int64
+	ZstdDictionaries         [][]byte
+	CompressionCodecResolver func(format.CompressionCodec) compress.Codec
 }
 
 // DefaultReaderConfig returns a new ReaderConfig value initialized with the
@@ -111,13 +146,23 @@ func (c *ReaderConfig) Apply(options ...ReaderOption) {
 // ConfigureReader applies configuration options from c to config.
 func (c *ReaderConfig) ConfigureReader(config *ReaderConfig) {
 	*config = ReaderConfig{
-		Schema: coalesceSchema(c.Schema, config.Schema),
+		Schema:                   coalesceSchema(c.Schema, config.Schema),
+		StrictValidation:         config.StrictValidation,
+		SkipCorrupted:            coalesceReport(c.SkipCorrupted, config.SkipCorrupted),
+		Observer:                 coalesceReaderObserver(c.Observer, config.Observer),
+		MemoryLimit:              coalesceInt64(c.MemoryLimit, config.MemoryLimit),
+		ZstdDictionaries:         coalesceZstdDictionaries(c.ZstdDictionaries, config.ZstdDictionaries),
+		CompressionCodecResolver: coalesceCompressionCodecResolver(c.CompressionCodecResolver, config.CompressionCodecResolver),
 	}
 }
 
 // Validate returns a non-nil error if the configuration of c is invalid.
 func (c *ReaderConfig) Validate() error {
-	return nil
+	const baseName = "parquet.(*ReaderConfig)."
+	return errorInvalidConfiguration(
+		validateNonNegativeInt64(baseName+"MemoryLimit", c.MemoryLimit),
+		validateZstdDictionaries(baseName+"ZstdDictionaries", c.ZstdDictionaries),
+	)
 }
 
 // The WriterConfig type carries configuration options for parquet writers.
@@ -130,17 +175,39 @@ func (c *ReaderConfig) Validate() error {
 //	})
 //
 type WriterConfig struct {
-	CreatedBy            string
-	ColumnPageBuffers    PageBufferPool
-	ColumnIndexSizeLimit int
-	PageBufferPool       PageBufferPool
-	PageBufferSize       int
-	DataPageVersion      int
-	DataPageStatistics   bool
-	KeyValueMetadata     map[string]string
-	Schema               *Schema
-	SortingColumns       []SortingColumn
-	BloomFilters         []BloomFilterColumn
+	CreatedBy              string
+	ColumnPageBuffers      PageBufferPool
+	ColumnIndexSizeLimit   int
+	PageBufferPool         PageBufferPool
+	PageBufferSize         int
+	DataPageSize           int
+	DataPageMaxValues      int
+	RowGroupTargetSize     int64
+	MaxRowsPerRowGroup     int64
+	RowGroupFlushInterval  time.Duration
+	PageEncodingBufferSize int
+	ColumnChunkBufferSize  int
+	FooterBufferSize       int
+	DataPageVersion        int
+	DataPageStatistics     bool
+	SchemaValidation       bool
+	LegacyNestedLayouts    bool
+	KeyValueMetadata       map[string]string
+	Schema                 *Schema
+	SortingColumns         []SortingColumn
+	BloomFilters           []BloomFilterColumn
+	DerivedColumns         []DerivedColumn
+	Encryption             *EncryptionConfig
+	Compression            compress.Codec
+	ColumnCompression      []ColumnCodec
+	ColumnEncoding         []ColumnEncodingConfig
+	ColumnTransforms       []ColumnTransformConfig
+	ColumnOverrides        []ColumnOverrideConfig
+	DictionaryMaxSize      int
+	DictionaryMaxValues    int
+	DictionaryLimits       []ColumnDictionaryLimit
+	Observer               *WriterObserver
+	OnRowGroupFlush        []func(*FlushedRowGroup) error
 }
 
 // DefaultWriterConfig returns a new WriterConfig value initialized with the
@@ -186,16 +253,38 @@ func (c *WriterConfig) ConfigureWriter(config *WriterConfig) {
 		}
 	}
 	*config = WriterConfig{
-		CreatedBy:            coalesceString(c.CreatedBy, config.CreatedBy),
-		ColumnPageBuffers:    coalescePageBufferPool(c.ColumnPageBuffers, config.ColumnPageBuffers),
-		ColumnIndexSizeLimit: coalesceInt(c.ColumnIndexSizeLimit, config.ColumnIndexSizeLimit),
-		PageBufferSize:       coalesceInt(c.PageBufferSize, config.PageBufferSize),
-		DataPageVersion:      coalesceInt(c.DataPageVersion, config.DataPageVersion),
-		DataPageStatistics:   config.DataPageStatistics,
-		KeyValueMetadata:     keyValueMetadata,
-		Schema:               coalesceSchema(c.Schema, config.Schema),
-		SortingColumns:       coalesceSortingColumns(c.SortingColumns, config.SortingColumns),
-		BloomFilters:         coalesceBloomFilters(c.BloomFilters, config.BloomFilters),
+		CreatedBy:              coalesceString(c.CreatedBy, config.CreatedBy),
+		ColumnPageBuffers:      coalescePageBufferPool(c.ColumnPageBuffers, config.ColumnPageBuffers),
+		ColumnIndexSizeLimit:   coalesceInt(c.ColumnIndexSizeLimit, config.ColumnIndexSizeLimit),
+		PageBufferSize:         coalesceInt(c.PageBufferSize, config.PageBufferSize),
+		DataPageSize:           coalesceInt(c.DataPageSize, config.DataPageSize),
+		DataPageMaxValues:      coalesceInt(c.DataPageMaxValues, config.DataPageMaxValues),
+		RowGroupTargetSize:     coalesceInt64(c.RowGroupTargetSize, config.RowGroupTargetSize),
+		MaxRowsPerRowGroup:     coalesceInt64(c.MaxRowsPerRowGroup, config.MaxRowsPerRowGroup),
+		RowGroupFlushInterval:  coalesceDuration(c.RowGroupFlushInterval, config.RowGroupFlushInterval),
+		PageEncodingBufferSize: coalesceInt(c.PageEncodingBufferSize, config.PageEncodingBufferSize),
+		ColumnChunkBufferSize:  coalesceInt(c.ColumnChunkBufferSize, config.ColumnChunkBufferSize),
+		FooterBufferSize:       coalesceInt(c.FooterBufferSize, config.FooterBufferSize),
+		DataPageVersion:        coalesceInt(c.DataPageVersion, config.DataPageVersion),
+		DataPageStatistics:     config.DataPageStatistics,
+		SchemaValidation:       config.SchemaValidation,
+		LegacyNestedLayouts:    config.LegacyNestedLayouts,
+		KeyValueMetadata:       keyValueMetadata,
+		Schema:                 coalesceSchema(c.Schema, config.Schema),
+		SortingColumns:         coalesceSortingColumns(c.SortingColumns, config.SortingColumns),
+		BloomFilters:           coalesceBloomFilters(c.BloomFilters, config.BloomFilters),
+		DerivedColumns:         coalesceDerivedColumns(c.DerivedColumns, config.DerivedColumns),
+		Encryption:             coalesceEncryption(c.Encryption, config.Encryption),
+		Compression:            coalesceCompression(c.Compression, config.Compression),
+		ColumnCompression:      coalesceColumnCompression(c.ColumnCompression, config.ColumnCompression),
+		ColumnEncoding:         coalesceColumnEncoding(c.ColumnEncoding, config.ColumnEncoding),
+		ColumnTransforms:       coalesceColumnTransforms(c.ColumnTransforms, config.ColumnTransforms),
+		ColumnOverrides:        coalesceColumnOverrides(c.ColumnOverrides, config.ColumnOverrides),
+		DictionaryMaxSize:      coalesceInt(c.DictionaryMaxSize, config.DictionaryMaxSize),
+		DictionaryMaxValues:    coalesceInt(c.DictionaryMaxValues, config.DictionaryMaxValues),
+		DictionaryLimits:       coalesceDictionaryLimits(c.DictionaryLimits, config.DictionaryLimits),
+		Observer:               coalesceWriterObserver(c.Observer, config.Observer),
+		OnRowGroupFlush:        coalesceRowGroupFlushFuncs(c.OnRowGroupFlush, config.OnRowGroupFlush),
 	}
 }
 
@@ -206,7 +295,26 @@ func (c *WriterConfig) Validate() error {
 		validateNotNil(baseName+"ColumnPageBuffers", c.ColumnPageBuffers),
 		validatePositiveInt(baseName+"ColumnIndexSizeLimit", c.ColumnIndexSizeLimit),
 		validatePositiveInt(baseName+"PageBufferSize", c.PageBufferSize),
+		validateNonNegativeInt(baseName+"DataPageSize", c.DataPageSize),
+		validateNonNegativeInt(baseName+"DataPageMaxValues", c.DataPageMaxValues),
+		validateNonNegativeInt64(baseName+"RowGroupTargetSize", c.RowGroupTargetSize),
+		validateNonNegativeInt64(baseName+"MaxRowsPerRowGroup", c.MaxRowsPerRowGroup),
+		validateNonNegativeInt64(baseName+"RowGroupFlushInterval", int64(c.RowGroupFlushInterval)),
+		validateNonNegativeInt(baseName+"PageEncodingBufferSize", c.PageEncodingBufferSize),
+		validateNonNegativeInt(baseName+"ColumnChunkBufferSize", c.ColumnChunkBufferSize),
+		validateNonNegativeInt(baseName+"FooterBufferSize", c.FooterBufferSize),
 		validateOneOfInt(baseName+"DataPageVersion", c.DataPageVersion, 1, 2),
+		validateEncryption(baseName+"Encryption", c.Encryption),
+		validateCompression(baseName+"Compression", c.Compression),
+		validateColumnCompression(baseName+"ColumnCompression", c.ColumnCompression),
+		validateColumnEncoding(baseName+"ColumnEncoding", c.ColumnEncoding),
+		validateColumnTransforms(baseName+"ColumnTransforms", c.ColumnTransforms),
+		validateColumnOverrides(baseName+"ColumnOverrides", c.ColumnOverrides),
+		validateNonNegativeInt(baseName+"DictionaryMaxSize", c.DictionaryMaxSize),
+		validateNonNegativeInt(baseName+"DictionaryMaxValues", c.DictionaryMaxValues),
+		validateDictionaryLimits(baseName+"DictionaryLimits", c.DictionaryLimits),
+		validateDerivedColumns(baseName+"DerivedColumns", c.DerivedColumns),
+		validateSchema(c.Schema, c.SchemaValidation),
 	)
 }
 
@@ -222,6 +330,9 @@ func (c *WriterConfig) Validate() error {
 type RowGroupConfig struct {
 	ColumnBufferSize int
 	SortingColumns   []SortingColumn
+	SortOnInsert     bool
+	KeyColumns       []string
+	KeyConflict      KeyConflict
 	Schema           *Schema
 }
 
@@ -249,6 +360,7 @@ func (c *RowGroupConfig) Validate() error {
 	const baseName = "parquet.(*RowGroupConfig)."
 	return errorInvalidConfiguration(
 		validatePositiveInt(baseName+"ColumnBufferSize", c.ColumnBufferSize),
+		validateOneOfInt(baseName+"KeyConflict", int(c.KeyConflict), int(ReplaceRow), int(KeepRow)),
 	)
 }
 
@@ -259,21 +371,224 @@ func (c *RowGroupConfig) Apply(options ...RowGroupOption) {
 }
 
 func (c *RowGroupConfig) ConfigureRowGroup(config *RowGroupConfig) {
+	keyConflict := coalesceKeyConflict(c.KeyConflict, config.KeyConflict)
+	if c.KeyColumns != nil {
+		// The conflict is configured along with the key columns, so it must
+		// override the previous one even when it is the zero ReplaceRow.
+		keyConflict = c.KeyConflict
+	}
 	*config = RowGroupConfig{
 		ColumnBufferSize: coalesceInt(c.ColumnBufferSize, config.ColumnBufferSize),
 		SortingColumns:   coalesceSortingColumns(c.SortingColumns, config.SortingColumns),
+		SortOnInsert:     c.SortOnInsert || config.SortOnInsert,
+		KeyColumns:       coalesceStrings(c.KeyColumns, config.KeyColumns),
+		KeyConflict:      keyConflict,
 		Schema:           coalesceSchema(c.Schema, config.Schema),
 	}
 }
 
+// The CSVConfig type carries configuration options for CSV writers and readers.
+//
+// CSVConfig implements the CSVOption interface so it can be used directly as
+// argument to the NewCSVWriter or NewCSVReader functions when needed, for
+// example:
+//
+//	writer := parquet.NewCSVWriter(output, schema, &parquet.CSVConfig{
+//		Comma: '\t',
+//	})
+//
+type CSVConfig struct {
+	Comma         rune
+	SkipHeader    bool
+	PathSeparator string
+	ListSeparator string
+	ColumnParsers map[string]CSVParseFunc
+}
+
+// DefaultCSVConfig returns a new CSVConfig value initialized with the default
+// CSV configuration.
+func DefaultCSVConfig() *CSVConfig {
+	return &CSVConfig{
+		Comma:         DefaultCSVComma,
+		PathSeparator: DefaultCSVPathSeparator,
+		ListSeparator: DefaultCSVListSeparator,
+	}
+}
+
+// NewCSVConfig constructs a new CSV configuration applying the options passed
+// as arguments.
+//
+// The function returns an non-nil error if some of the options carried invalid
+// configuration values.
+func NewCSVConfig(options ...CSVOption) (*CSVConfig, error) {
+	config := DefaultCSVConfig()
+	config.Apply(options...)
+	return config, config.Validate()
+}
+
+// Apply applies the given list of options to c.
+func (c *CSVConfig) Apply(options ...CSVOption) {
+	for _, opt := range options {
+		opt.ConfigureCSV(c)
+	}
+}
+
+// ConfigureCSV applies configuration options from c to config.
+func (c *CSVConfig) ConfigureCSV(config *CSVConfig) {
+	columnParsers := config.ColumnParsers
+	if len(c.ColumnParsers) > 0 {
+		if columnParsers == nil {
+			columnParsers = make(map[string]CSVParseFunc, len(c.ColumnParsers))
+		}
+		for k, v := range c.ColumnParsers {
+			columnParsers[k] = v
+		}
+	}
+	*config = CSVConfig{
+		Comma:         coalesceRune(c.Comma, config.Comma),
+		SkipHeader:    c.SkipHeader || config.SkipHeader,
+		PathSeparator: coalesceString(c.PathSeparator, config.PathSeparator),
+		ListSeparator: coalesceString(c.ListSeparator, config.ListSeparator),
+		ColumnParsers: columnParsers,
+	}
+}
+
+// Validate returns a non-nil error if the configuration of c is invalid.
+func (c *CSVConfig) Validate() error {
+	const baseName = "parquet.(*CSVConfig)."
+	return errorInvalidConfiguration(
+		validateCSVComma(baseName+"Comma", c.Comma),
+		validateNotEmptyString(baseName+"ListSeparator", c.ListSeparator),
+	)
+}
+
+// The JSONConfig type carries configuration options for JSON readers.
+//
+// JSONConfig implements the JSONOption interface so it can be used directly
+// as argument to the NewJSONReader function when needed, for example:
+//
+//	reader := parquet.NewJSONReader(input, schema, &parquet.JSONConfig{
+//		DisallowUnknownFields: true,
+//	})
+//
+type JSONConfig struct {
+	DisallowUnknownFields bool
+	ZeroMissingFields     bool
+	StrictTypes           bool
+}
+
+// DefaultJSONConfig returns a new JSONConfig value initialized with the
+// default JSON configuration.
+func DefaultJSONConfig() *JSONConfig {
+	return &JSONConfig{}
+}
+
+// NewJSONConfig constructs a new JSON configuration applying the options
+// passed as arguments.
+//
+// The function returns an non-nil error if some of the options carried invalid
+// configuration values.
+func NewJSONConfig(options ...JSONOption) (*JSONConfig, error) {
+	config := DefaultJSONConfig()
+	config.Apply(options...)
+	return config, config.Validate()
+}
+
+// Apply applies the given list of options to c.
+func (c *JSONConfig) Apply(options ...JSONOption) {
+	for _, opt := range options {
+		opt.ConfigureJSON(c)
+	}
+}
+
+// ConfigureJSON applies configuration options from c to config.
+func (c *JSONConfig) ConfigureJSON(config *JSONConfig) {
+	*config = JSONConfig{
+		DisallowUnknownFields: c.DisallowUnknownFields || config.DisallowUnknownFields,
+		ZeroMissingFields:     c.ZeroMissingFields || config.ZeroMissingFields,
+		StrictTypes:           c.StrictTypes || config.StrictTypes,
+	}
+}
+
+// Validate returns a non-nil error if the configuration of c is invalid.
+func (c *JSONConfig) Validate() error {
+	return nil
+}
+
+// The ValidationConfig type carries configuration options for the validation
+// of parquet files.
+//
+// ValidationConfig implements the ValidationOption interface so it can be used
+// directly as argument to the ValidateFile function when needed, for example:
+//
+//	report, err := parquet.ValidateFile(input, size, &parquet.ValidationConfig{
+//		DecodePages: true,
+//	})
+//
+type ValidationConfig struct {
+	DecodePages bool
+	Decryption  *DecryptionConfig
+}
+
+// DefaultValidationConfig returns a new ValidationConfig value initialized with
+// the default validation configuration.
+func DefaultValidationConfig() *ValidationConfig {
+	return &ValidationConfig{}
+}
+
+// NewValidationConfig constructs a new validation configuration applying the
+// options passed as arguments.
+//
+// The function returns an non-nil error if some of the options carried invalid
+// configuration values.
+func NewValidationConfig(options ...ValidationOption) (*ValidationConfig, error) {
+	config := DefaultValidationConfig()
+	config.Apply(options...)
+	return config, config.Validate()
+}
+
+// Apply applies the given list of options to c.
+func (c *ValidationConfig) Apply(options ...ValidationOption) {
+	for _, opt := range options {
+		opt.ConfigureValidation(c)
+	}
+}
+
+// ConfigureValidation applies configuration options from c to config.
+func (c *ValidationConfig) ConfigureValidation(config *ValidationConfig) {
+	*config = ValidationConfig{
+		DecodePages: c.DecodePages || config.DecodePages,
+		Decryption:  coalesceDecryption(c.Decryption, config.Decryption),
+	}
+}
+
+// Validate returns a non-nil error if the configuration of c is invalid.
+func (c *ValidationConfig) Validate() error {
+	const baseName = "parquet.(*ValidationConfig)."
+	return errorInvalidConfiguration(
+		validateDecryption(baseName+"Decryption", c.Decryption),
+	)
+}
+
 // FileOption is an interface implemented by types that carry configuration
 // options for parquet files.
 type FileOption interface {
 	ConfigureFile(*FileConfig)
 }
 
+// ValidationOption is an interface implemented by types that carry
+// configuration options for the validation of parquet files.
+type ValidationOption interface {
+	ConfigureValidation(*ValidationConfig)
+}
+
 // ReaderOption is an interface implemented by types that carry configuration
 // options for parquet readers.
+//
+// Options which are both file and reader options, such as StrictValidation or
+// MemoryLimit, apply to the file that readers open when they are given an
+// io.ReaderAt. They have no effect on readers given a *File, programs should
+// pass them to OpenFile instead.
 type ReaderOption interface {
 	ConfigureReader(*ReaderConfig)
 }
@@ -290,6 +605,18 @@ type RowGroupOption interface {
 	ConfigureRowGroup(*RowGroupConfig)
 }
 
+// CSVOption is an interface implemented by types that carry configuration
+// options for CSV writers and readers.
+type CSVOption interface {
+	ConfigureCSV(*CSVConfig)
+}
+
+// JSONOption is an interface implemented by types that carry configuration
+// options for JSON readers.
+type JSONOption interface {
+	ConfigureJSON(*JSONConfig)
+}
+
 // SkipPageIndex is a file configuration option which when set to true, prevents
 // automatically reading the page index when opening a parquet file. This is
 // useful as an optimization when programs know that they will not need to
@@ -300,6 +627,150 @@ func SkipPageIndex(skip bool) FileOption {
 	return fileOption(func(config *FileConfig) { config.SkipPageIndex = skip })
 }
 
+// StrictValidation is a file and reader configuration option which enables
+// thorough consistency checks of parquet files, for programs that use readers
+// as data quality gates.
+//
+// When enabled, the metadata of files is verified when they are opened: the
+// offsets, sizes, and statistics of column chunks, and the page index. Pages
+// are verified when they are read: the repetition and definition levels must
+// be within the bounds of the column, the number of values, nulls, and rows
+// decoded must match the page headers, and the minimum values of statistics
+// must not be greater than the maximum values. Errors describe the row group,
+// column, and page where the inconsistency was found, and wrap ErrCorrupted.
+//
+// Defaults to false.
+func StrictValidation(enabled bool) interface {
+	FileOption
+	ReaderOption
+} {
+	return strictValidation(enabled)
+}
+
+// SkipCorrupted is a file and reader configuration option which enables a
+// best-effort read mode, where data that cannot be read is skipped instead of
+// aborting, so a single corrupted column chunk does not make the rest of the
+// file unrecoverable. The report function is called with a *CorruptedDataError
+// describing each part of the file that was skipped.
+//
+// When opening files, unreadable page indexes and bloom filters are discarded,
+// and the pages of column chunks which fail their checksum or cannot be decoded
+// are skipped. Pages can only be skipped individually if the data of the page
+// could be read or the file has a page index; otherwise the remaining pages of
+// the column chunk are skipped.
+//
+// Readers skip whole row groups when rows cannot be read from them, since the
+// values of the other columns would not be aligned anymore if pages were
+// skipped. For the same reason, files opened with this option should not be
+// passed to NewReader; the reader opens the file itself when it is given an
+// io.ReaderAt, discarding unreadable page indexes and bloom filters but never
+// skipping individual pages. Rows which were read from a row group before the corruption was
+// encountered remain visible to the program. The row groups skipped by readers
+// and the number of rows that could not be read are also reported to the
+// OnRowGroupSkipped function of the ReaderObserver, if any.
+//
+// Defaults to nil, which disables skipping corrupted data.
+func SkipCorrupted(report func(error)) interface {
+	FileOption
+	ReaderOption
+} {
+	return skipCorrupted(report)
+}
+
+// MemoryLimit is a file and reader configuration option which bounds the
+// memory held by the readers of a file, so programs serving many concurrent
+// queries can limit the memory used by each of them.
+//
+// The limit accounts for the compressed and uncompressed buffers of the pages
+// being read from each column chunk, and for the dictionaries loaded by the
+// readers. Pages are read one at a time per column chunk, so the memory in use
+// grows with the number of column chunks read concurrently. When reading a
+// page would exceed the limit, the read fails with a *MemoryLimitError which
+// wraps ErrMemoryLimitExceeded; the error is not treated as corrupted data by
+// the SkipCorrupted option. The memory is released when the pages of column
+// chunks are closed, which readers do when moving to the next row group.
+//
+// Defaults to zero, which means that the memory is not limited.
+func MemoryLimit(size int64) interface {
+	FileOption
+	ReaderOption
+} {
+	return memoryLimit(size)
+}
+
+// ZstdDictionaries is a file and reader configuration option which provides
+// the pre-trained dictionaries needed to decompress the pages of files written
+// with zstd codecs configured with a dictionary (see zstd.Codec).
+//
+// Writers record the identifier of the dictionary in the key/value metadata
+// of column chunks, under the "zstd.dictionary.id" key. Opening a file fails
+// if one of its column chunks was compressed with a dictionary which is not
+// passed to this option.
+//
+// Defaults to nil, which means that files compressed with zstd dictionaries
+// cannot be opened.
+func ZstdDictionaries(dictionaries ...[]byte) interface {
+	FileOption
+	ReaderOption
+} {
+	return zstdDictionaries(dictionaries)
+}
+
+// ResolveCompressionCodecs is a file and reader configuration option which
+// registers a function called to resolve the compression codecs of column
+// chunks that the package does not support, for example proprietary codecs
+// identified by values which are not defined by the parquet format.
+//
+// The function is called once per unsupported codec when the file is opened;
+// it returns nil if it does not know the codec either, in which case reading
+// the pages compressed with the codec fails. Codecs supported by the package
+// are never passed to the function.
+//
+// Defaults to nil, which means that only the codecs of the package are used.
+func ResolveCompressionCodecs(resolve func(format.CompressionCodec) compress.Codec) interface {
+	FileOption
+	ReaderOption
+} {
+	return compressionCodecResolver(resolve)
+}
+
+// ObserveReader is a file and reader configuration option which registers
+// functions called to report on the activity of readers: the bytes read from
+// files, the pages read and skipped, and the time spent decompressing pages.
+//
+// Readers pass the option to OpenFile when they open the file themselves,
+// programs passing a *File to NewReader should use it when calling OpenFile
+// instead.
+//
+// Defaults to nil, which means that the reader activity is not reported.
+func ObserveReader(observer *ReaderObserver) interface {
+	FileOption
+	ReaderOption
+} {
+	return &readerObserverOption{observer}
+}
+
+// Decryption creates a configuration option which enables opening encrypted
+// parquet files.
+//
+// Defaults to nil, which means encrypted files cannot be opened.
+func Decryption(decryption *DecryptionConfig) interface {
+	FileOption
+	ValidationOption
+} {
+	return &decryptionOption{decryption}
+}
+
+// DecodePages is a validation configuration option which enables decoding the
+// values of all pages when validating parquet files, verifying that the levels
+// and number of values match the page headers.
+//
+// Defaults to false, which means that only the metadata, page headers, and
+// checksums are verified.
+func DecodePages(enabled bool) ValidationOption {
+	return validationOption(func(config *ValidationConfig) { config.DecodePages = enabled })
+}
+
 // PageBufferSize configures the size of column page buffers on parquet writers.
 //
 // Note that the page buffer size refers to the in-memory buffers where pages
@@ -313,6 +784,127 @@ func PageBufferSize(size int) WriterOption {
 	return writerOption(func(config *WriterConfig) { config.PageBufferSize = size })
 }
 
+// DataPageSize configures the target size of data pages on parquet writers.
+//
+// Unlike PageBufferSize, the data page size is an estimate of the size of the
+// pages after encoding and before compression: the writer flushes the values
+// buffered for a column to a new page when they reach the target size. Smaller
+// pages make the page index more selective (some query engines prefer pages of
+// around 64 KiB), at the expense of more metadata in the files.
+//
+// Pages are still flushed when the page buffers are full, so targets larger
+// than the page buffer size have no effect.
+//
+// Defaults to zero, which means the size of pages is only limited by the page
+// buffer size.
+func DataPageSize(size int) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.DataPageSize = size })
+}
+
+// DataPageMaxValues configures the maximum number of values in data pages on
+// parquet writers.
+//
+// Limiting the number of values per page makes the page index more selective
+// and bounds the memory needed by readers to decode a single page, which is
+// mostly useful for columns of small values that would otherwise fit in very
+// large numbers within the page buffers. Pages always contain whole rows, so
+// pages of repeated columns may exceed the limit when a single row has more
+// values than the limit.
+//
+// Defaults to zero, which means the number of values in pages is only limited
+// by the page buffer and data page sizes.
+func DataPageMaxValues(numValues int) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.DataPageMaxValues = numValues })
+}
+
+// RowGroupTargetSize configures the size at which parquet writers automatically
+// flush row groups, in bytes. The size of a row group is estimated from the
+// encoded and compressed pages, the dictionaries, and the values buffered in
+// memory, so the row groups of the output files land close to the target
+// (distributed query engines usually expect row groups of 128 to 512 MiB),
+// regardless of the width of rows.
+//
+// The size is only checked when rows are written individually (e.g. with
+// Write, WriteRow, or ReadRowsFrom); writing whole row groups with
+// WriteRowGroup produces one row group in the output file for each one.
+//
+// Defaults to zero, which means row groups are only flushed when the Flush
+// method is called or the writer is closed.
+func RowGroupTargetSize(size int64) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.RowGroupTargetSize = size })
+}
+
+// MaxRowsPerRowGroup configures the maximum number of rows in row groups
+// written by parquet writers, which automatically flush row groups when they
+// reach this number of rows. This is useful to programs which need to predict
+// the row group that a row was written to (e.g. to seek to it).
+//
+// Unlike RowGroupTargetSize, the limit also applies to row groups written
+// with WriteRowGroup, which are split into multiple row groups in the output
+// file when they have more rows than the limit.
+//
+// Defaults to zero, which means the number of rows is not limited.
+func MaxRowsPerRowGroup(numRows int64) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.MaxRowsPerRowGroup = numRows })
+}
+
+// RowGroupFlushInterval configures the maximum time that parquet writers hold
+// rows in the row group being written; when the interval has elapsed since the
+// first row of the row group was written, the writer automatically flushes the
+// row group. Combined with RowGroupTargetSize or MaxRowsPerRowGroup, this bounds
+// the latency of streaming writers regardless of the rate of rows.
+//
+// The interval is checked when rows are written, the writer does not flush row
+// groups in the background; programs that need the rows to be flushed when no
+// more rows are being written should call Flush. Like with RowGroupTargetSize,
+// the interval only applies to rows written individually, not to row groups
+// written with WriteRowGroup.
+//
+// Defaults to zero, which means row groups are not flushed based on time.
+func RowGroupFlushInterval(interval time.Duration) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.RowGroupFlushInterval = interval })
+}
+
+// PageEncodingBufferSize configures the size of the scratch buffers that
+// parquet writers encode and compress pages into before writing them to the
+// output.
+//
+// The writer preallocates buffers of this size, and releases them at the end
+// of each row group if they had to grow larger to hold a page, which bounds
+// the memory retained by idle or long-lived writers.
+//
+// Defaults to zero, which means the buffers grow on demand and are retained
+// for the lifetime of the writer.
+func PageEncodingBufferSize(size int) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.PageEncodingBufferSize = size })
+}
+
+// ColumnChunkBufferSize configures the size of the buffers that parquet writers
+// acquire from the ColumnPageBuffers pool to stage the pages of column chunks
+// until row groups are flushed.
+//
+// In-memory buffers are preallocated to this size when they are acquired, and
+// those which grew larger are not returned to the pool when they are released,
+// so the pool does not retain buffers sized for the largest page ever written.
+// The option has no effect on buffers that are not held in memory, such as
+// those of pools created by NewFileBufferPool.
+//
+// Defaults to zero, which means buffers are always returned to the pool.
+func ColumnChunkBufferSize(size int) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.ColumnChunkBufferSize = size })
+}
+
+// FooterBufferSize configures the size of the buffer that parquet writers
+// serialize the file metadata into when they are closed.
+//
+// The buffer is retained across calls to Reset unless it had to grow larger to
+// hold the footer.
+//
+// Defaults to zero, which means a new buffer is allocated for each file.
+func FooterBufferSize(size int) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.FooterBufferSize = size })
+}
+
 // CreatedBy creates a configuration option which sets the name of the
 // application that created a parquet file.
 //
@@ -357,6 +949,32 @@ func DataPageStatistics(enabled bool) WriterOption {
 	return writerOption(func(config *WriterConfig) { config.DataPageStatistics = enabled })
 }
 
+// SchemaValidation creates a configuration option which defines whether the
+// schema of the parquet file is validated before writing rows to it. When
+// enabled, configuring a writer with an invalid schema returns the errors
+// reported by Schema.Validate, instead of failing when rows are encoded.
+//
+// Defaults to false.
+func SchemaValidation(enabled bool) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.SchemaValidation = enabled })
+}
+
+// LegacyNestedLayouts creates a configuration option which defines whether LIST
+// and MAP columns are written with the legacy layouts expected by old readers
+// (e.g. Hive deployments predating the three-level list layout of the parquet
+// specification). Lists are written as a repeated column named "array" holding
+// the elements, and the repeated group of maps is named "map".
+//
+// Lists of optional elements cannot be represented with the legacy layout, and
+// configuring a writer with a schema containing such lists returns an error.
+// The column paths of other options (e.g. ColumnCompression) must refer to the
+// columns of the legacy layout.
+//
+// Defaults to false.
+func LegacyNestedLayouts(enabled bool) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.LegacyNestedLayouts = enabled })
+}
+
 // KeyValueMetadata creates a configuration option which adds key/value metadata
 // to add to the metadata of parquet files.
 //
@@ -392,6 +1010,229 @@ func BloomFilters(filters ...BloomFilterColumn) WriterOption {
 	return writerOption(func(config *WriterConfig) { config.BloomFilters = filters })
 }
 
+// DerivedColumns creates a configuration option which defines columns that
+// parquet writers compute from the rows they write, and append to the schema
+// of the file.
+//
+// The schema of the writer remains the one of the rows passed to its methods,
+// only the files that it produces contain the derived columns. For example,
+// this writer produces files with a date column computed from the timestamp
+// of rows:
+//
+//	writer := parquet.NewWriter(output, schema,
+//		parquet.DerivedColumns(
+//			parquet.Derive("date", parquet.Date(), func(row parquet.Row) parquet.Value {
+//				t := time.Unix(0, row[timestampColumn].Int64())
+//				return parquet.ValueOf(int32(t.Unix() / 86400))
+//			}),
+//		),
+//	)
+func DerivedColumns(columns ...DerivedColumn) WriterOption {
+	columns = append([]DerivedColumn{}, columns...)
+	return writerOption(func(config *WriterConfig) { config.DerivedColumns = columns })
+}
+
+// Encryption creates a configuration option which enables modular encryption
+// of the parquet files produced by writers.
+//
+// Defaults to nil, which means files are not encrypted.
+func Encryption(encryption *EncryptionConfig) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.Encryption = encryption })
+}
+
+// ObserveWriter creates a configuration option which registers functions that
+// writers call to report on their activity: the pages written to each column,
+// the size and flush duration of row groups, and dictionary fallbacks.
+//
+// Defaults to nil, which means that the writer activity is not reported.
+func ObserveWriter(observer *WriterObserver) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.Observer = observer })
+}
+
+// OnRowGroupFlush creates a configuration option which registers a function
+// called after each row group is written to the output, with the metadata of
+// the row group. The option may be passed multiple times to register multiple
+// functions, which are called in order.
+//
+// The functions are called synchronously, whether row groups are flushed
+// explicitly or automatically (e.g. when reaching RowGroupTargetSize), which
+// allows programs to report progress, build manifests of the files, or apply
+// backpressure to the producers of rows by blocking. An error returned by a
+// function is returned by the writer method that caused the flush; the row
+// group has then already been written to the output.
+//
+// The metadata passed to the functions must not be retained or modified, it
+// shares memory with the footer of the file being written.
+func OnRowGroupFlush(fn func(*FlushedRowGroup) error) WriterOption {
+	return writerOption(func(config *WriterConfig) {
+		config.OnRowGroupFlush = append(config.OnRowGroupFlush, fn)
+	})
+}
+
+// Compression creates a configuration option which defines the compression
+// codec of columns that do not declare one in the parquet schema.
+//
+// Defaults to nil, which means those columns are not compressed.
+func Compression(codec compress.Codec) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.Compression = codec })
+}
+
+// ColumnCompression creates a configuration option which defines the
+// compression codec of the column at the given path, overriding the codec
+// declared in the parquet schema and the one set by the Compression option.
+//
+// This option is additive, it may be used multiple times to configure the
+// compression of more than one column. Paths which do not match a leaf column
+// of the schema are ignored.
+func ColumnCompression(codec compress.Codec, path ...string) WriterOption {
+	column := ColumnCodec{Path: append([]string{}, path...), Codec: codec}
+	return writerOption(func(config *WriterConfig) {
+		config.ColumnCompression = append(config.ColumnCompression, column)
+	})
+}
+
+// ColumnTransform creates a configuration option which applies a
+// transformation to the values of the column at the given path before they
+// are written, for example to redact personal information:
+//
+//	writer := parquet.NewWriter(output,
+//		parquet.ColumnTransform(parquet.HashValues(key), "email"),
+//		parquet.ColumnTransform(parquet.MaskValues("***"), "address", "street"),
+//		parquet.ColumnTransform(parquet.TruncateValues(3), "zip"),
+//	)
+//
+// Transformations apply to all rows written to the writer, including rows of
+// row groups passed to WriteRowGroup, which are then rewritten one row at a
+// time instead of copying their pages.
+//
+// This option is additive, it may be used multiple times to configure the
+// transformation of more than one column. Paths which do not match a leaf
+// column of the schema are ignored.
+func ColumnTransform(transform ValueTransform, path ...string) WriterOption {
+	column := ColumnTransformConfig{Path: append([]string{}, path...), Transform: transform}
+	return writerOption(func(config *WriterConfig) {
+		config.ColumnTransforms = append(config.ColumnTransforms, column)
+	})
+}
+
+// ColumnOverride creates a configuration option which rewrites the node at the
+// given path of the schema of rows written to the writer, which allows changing
+// the properties of columns in the files without modifying the Go types that
+// the schema was generated from (e.g. struct tags owned by another package):
+//
+//	writer := parquet.NewWriter(output,
+//		parquet.ColumnOverride(parquet.Optional, "nickname"),
+//		parquet.ColumnOverride(parquet.OverrideType(parquet.Enum().Type()), "status"),
+//	)
+//
+// Overrides may make required columns optional, change the type of leaf
+// columns to another type of the same kind, or add encodings, compression
+// codecs, or field ids to the nodes. Writers return an error when the schema
+// is configured if an override makes other changes, since the rows could not
+// be written to the file. The ColumnCompression and ColumnEncoding options are
+// usually simpler to use to change the compression and encoding of columns.
+//
+// This option is additive, it may be used multiple times to configure the
+// override of more than one node; overrides of the same path are applied in
+// order. Paths which do not match a node of the schema are ignored.
+func ColumnOverride(override NodeOverride, path ...string) WriterOption {
+	column := ColumnOverrideConfig{Path: append([]string{}, path...), Override: override}
+	return writerOption(func(config *WriterConfig) {
+		config.ColumnOverrides = append(config.ColumnOverrides, column)
+	})
+}
+
+// ColumnEncoding creates a configuration option which forces the encoding of
+// the column at the given path, overriding the encoding declared in the parquet
+// schema (e.g. to disable dictionary encoding of a column).
+//
+// This option is additive, it may be used multiple times to configure the
+// encoding of more than one column. Writers panic if the encoding cannot be
+// applied to the type of the column. Paths which do not match a leaf column of
+// the schema are ignored.
+func ColumnEncoding(enc encoding.Encoding, path ...string) WriterOption {
+	column := ColumnEncodingConfig{Path: append([]string{}, path...), Encoding: enc}
+	return writerOption(func(config *WriterConfig) {
+		config.ColumnEncoding = append(config.ColumnEncoding, column)
+	})
+}
+
+// DisableColumnEncoding creates a configuration option which prevents writers
+// from using the given encoding on the column at the given path, for example:
+//
+//	// Never use dictionary encoding on the "blob" column.
+//	parquet.DisableColumnEncoding(&parquet.RLEDictionary, "blob")
+//
+// The writer then uses the next encoding declared in the parquet schema, or
+// the default encoding of the column type.
+//
+// This option is additive, it may be used multiple times to disable more than
+// one encoding.
+func DisableColumnEncoding(enc encoding.Encoding, path ...string) WriterOption {
+	column := ColumnEncodingConfig{Path: append([]string{}, path...), Encoding: enc, Disabled: true}
+	return writerOption(func(config *WriterConfig) {
+		config.ColumnEncoding = append(config.ColumnEncoding, column)
+	})
+}
+
+// DisableDictionary creates a configuration option which turns off dictionary
+// encoding of the column at the given path, even if it was enabled in the
+// parquet schema. This is useful for columns holding unique values (e.g. UUIDs
+// or hashes) where the dictionary only wastes memory.
+//
+// The option is equivalent to disabling the PLAIN_DICTIONARY and
+// RLE_DICTIONARY encodings with DisableColumnEncoding.
+func DisableDictionary(path ...string) WriterOption {
+	path = append([]string{}, path...)
+	return writerOption(func(config *WriterConfig) {
+		config.ColumnEncoding = append(config.ColumnEncoding,
+			ColumnEncodingConfig{Path: path, Encoding: &PlainDictionary, Disabled: true},
+			ColumnEncodingConfig{Path: path, Encoding: &RLEDictionary, Disabled: true},
+		)
+	})
+}
+
+// DictionaryMaxSize creates a configuration option which defines the maximum
+// size of dictionary pages, in bytes. When the dictionary of a column grows
+// past this size, the writer stops adding values to it and falls back to a
+// non-dictionary encoding for the rest of the row group, which puts a bound on
+// the memory used by dictionaries of high-cardinality columns.
+//
+// The limit is checked after each row is written, so dictionaries may exceed
+// it by the size of the values of one row.
+//
+// Defaults to zero, which means dictionaries are not limited in size.
+func DictionaryMaxSize(size int) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.DictionaryMaxSize = size })
+}
+
+// DictionaryMaxValues creates a configuration option which defines the maximum
+// number of distinct values in dictionaries. Like with DictionaryMaxSize, the
+// writer falls back to a non-dictionary encoding for the rest of the row group
+// when the limit is exceeded.
+//
+// Defaults to zero, which means the number of values is not limited.
+func DictionaryMaxValues(count int) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.DictionaryMaxValues = count })
+}
+
+// DictionaryLimits creates a configuration option which defines the limits of
+// the dictionary of the column at the given path, overriding the limits set by
+// the DictionaryMaxSize and DictionaryMaxValues options. Zero means no limit.
+//
+// This option is additive, it may be used multiple times to configure the
+// dictionary limits of more than one column.
+func DictionaryLimits(maxSize, maxValues int, path ...string) WriterOption {
+	limit := ColumnDictionaryLimit{
+		Path:      append([]string{}, path...),
+		MaxSize:   maxSize,
+		MaxValues: maxValues,
+	}
+	return writerOption(func(config *WriterConfig) {
+		config.DictionaryLimits = append(config.DictionaryLimits, limit)
+	})
+}
+
 // ColumnBufferSize creates a configuration option which defines the size of
 // row group column buffers.
 //
@@ -400,6 +1241,53 @@ func ColumnBufferSize(size int) RowGroupOption {
 	return rowGroupOption(func(config *RowGroupConfig) { config.ColumnBufferSize = size })
 }
 
+// SortOnInsert creates a configuration option which defines whether buffers
+// keep their rows sorted by the sorting columns as they are written.
+//
+// Writing a row costs a single comparison with the previous row, rows written
+// out of order start a new sorted run. The runs are merged when the Rows method
+// of the buffer is called, which costs a number of comparisons proportional to
+// the number of rows times the logarithm of the number of runs, and a single
+// pass rewriting the columns; the buffer never needs to be fully sorted before
+// being written to a file, which makes this mode best suited to mostly ordered
+// inputs. Equal rows remain in the order they were written in.
+//
+// Until Rows is called, the columns of the buffer hold the rows in the order
+// they were written. Since merging the runs mutates the buffer, Rows must be
+// called once before the buffer is read from multiple goroutines.
+//
+// Rows are not reordered when values are written directly to the column
+// buffers.
+//
+// Defaults to false.
+func SortOnInsert(enabled bool) RowGroupOption {
+	return rowGroupOption(func(config *RowGroupConfig) { config.SortOnInsert = enabled })
+}
+
+// KeyColumns creates a configuration option which defines the columns that
+// identify rows in buffers, and how rows with a key that already exists in the
+// buffer are handled.
+//
+// Columns are designated by their dot-separated paths in the schema, and must
+// not be repeated. Rows where a key column is null are keyed by the null value.
+// When conflict is ReplaceRow, the buffer only retains the last row written
+// with each key, which is useful to maintain snapshots of records receiving
+// updates; when conflict is KeepRow, the rows written with a key that already
+// exists are dropped.
+//
+// Replaced rows are not counted by the Len and NumRows methods of the buffer,
+// but remain in its columns until they are removed by a call to Rows, which
+// mutates the buffer like with SortOnInsert.
+//
+// Keys are not checked when values are written directly to the column buffers.
+func KeyColumns(conflict KeyConflict, columns ...string) RowGroupOption {
+	columns = append([]string{}, columns...)
+	return rowGroupOption(func(config *RowGroupConfig) {
+		config.KeyColumns = columns
+		config.KeyConflict = conflict
+	})
+}
+
 // SortingColumns creates a configuration option which defines the sorting order
 // of columns in a row group.
 //
@@ -418,6 +1306,93 @@ func SortingColumns(columns ...SortingColumn) interface {
 	return sortingColumns(columns)
 }
 
+// CSVComma creates a configuration option which sets the field delimiter of
+// CSV records.
+//
+// Defaults to ','.
+func CSVComma(comma rune) CSVOption {
+	return csvOption(func(config *CSVConfig) { config.Comma = comma })
+}
+
+// CSVSkipHeader creates a configuration option which defines whether the CSV
+// header naming the columns is omitted.
+//
+// Defaults to false.
+func CSVSkipHeader(skip bool) CSVOption {
+	return csvOption(func(config *CSVConfig) { config.SkipHeader = skip })
+}
+
+// CSVPathSeparator creates a configuration option which sets the separator
+// used to join the path of nested columns when generating CSV column names,
+// for example "address.city".
+//
+// Defaults to ".".
+func CSVPathSeparator(separator string) CSVOption {
+	return csvOption(func(config *CSVConfig) { config.PathSeparator = separator })
+}
+
+// CSVListSeparator creates a configuration option which sets the separator
+// placed between the values of repeated columns when they are flattened into
+// a single CSV field.
+//
+// Defaults to ";".
+func CSVListSeparator(separator string) CSVOption {
+	return csvOption(func(config *CSVConfig) { config.ListSeparator = separator })
+}
+
+// CSVColumnParser creates a configuration option which installs a custom
+// function to parse the fields of a CSV column into parquet values. The column
+// is identified by its name, which is the path of the column joined with the
+// path separator.
+//
+// This option is additive, it may be used multiple times to set parsers on
+// more than one column.
+//
+// By default, fields are parsed according to the type of their column.
+func CSVColumnParser(column string, parse CSVParseFunc) CSVOption {
+	return csvOption(func(config *CSVConfig) {
+		if config.ColumnParsers == nil {
+			config.ColumnParsers = map[string]CSVParseFunc{column: parse}
+		} else {
+			config.ColumnParsers[column] = parse
+		}
+	})
+}
+
+// JSONDisallowUnknownFields creates a configuration option which defines
+// whether JSON readers return an error when the input contains fields that
+// are not present in the schema.
+//
+// Defaults to false, unknown fields are ignored.
+func JSONDisallowUnknownFields(disallow bool) JSONOption {
+	return jsonOption(func(config *JSONConfig) { config.DisallowUnknownFields = disallow })
+}
+
+// JSONZeroMissingFields creates a configuration option which defines whether
+// required columns that are missing or null in the JSON input are set to the
+// zero value of their type instead of causing the read to fail.
+//
+// Missing optional and repeated columns are always treated as null or empty.
+//
+// Defaults to false.
+func JSONZeroMissingFields(zero bool) JSONOption {
+	return jsonOption(func(config *JSONConfig) { config.ZeroMissingFields = zero })
+}
+
+// JSONStrictTypes creates a configuration option which disables type coercion
+// in JSON readers. When enabled, JSON values must match the type of their
+// column; for example, numeric columns only accept JSON numbers and string
+// columns only accept JSON strings.
+//
+// By default, JSON readers coerce values when possible, for example parsing
+// strings holding numbers into numeric columns, or formatting numbers and
+// booleans into string columns.
+//
+// Defaults to false.
+func JSONStrictTypes(strict bool) JSONOption {
+	return jsonOption(func(config *JSONConfig) { config.StrictTypes = strict })
+}
+
 type sortingColumns []SortingColumn
 
 func (columns sortingColumns) ConfigureRowGroup(config *RowGroupConfig) {
@@ -436,6 +1411,80 @@ type readerOption func(*ReaderConfig)
 
 func (opt readerOption) ConfigureReader(config *ReaderConfig) { opt(config) }
 
+type strictValidation bool
+
+func (opt strictValidation) ConfigureFile(config *FileConfig) {
+	config.StrictValidation = bool(opt)
+}
+
+func (opt strictValidation) ConfigureReader(config *ReaderConfig) {
+	config.StrictValidation = bool(opt)
+}
+
+type skipCorrupted func(error)
+
+func (opt skipCorrupted) ConfigureFile(config *FileConfig) {
+	config.SkipCorrupted = opt
+}
+
+func (opt skipCorrupted) ConfigureReader(config *ReaderConfig) {
+	config.SkipCorrupted = opt
+}
+
+type memoryLimit int64
+
+func (opt memoryLimit) ConfigureFile(config *FileConfig) {
+	config.MemoryLimit = int64(opt)
+}
+
+func (opt memoryLimit) ConfigureReader(config *ReaderConfig) {
+	config.MemoryLimit = int64(opt)
+}
+
+type zstdDictionaries [][]byte
+
+func (opt zstdDictionaries) ConfigureFile(config *FileConfig) {
+	config.ZstdDictionaries = opt
+}
+
+func (opt zstdDictionaries) ConfigureReader(config *ReaderConfig) {
+	config.ZstdDictionaries = opt
+}
+
+type compressionCodecResolver func(format.CompressionCodec) compress.Codec
+
+func (opt compressionCodecResolver) ConfigureFile(config *FileConfig) {
+	config.CompressionCodecResolver = opt
+}
+
+func (opt compressionCodecResolver) ConfigureReader(config *ReaderConfig) {
+	config.CompressionCodecResolver = opt
+}
+
+type readerObserverOption struct{ observer *ReaderObserver }
+
+func (opt *readerObserverOption) ConfigureFile(config *FileConfig) {
+	config.Observer = opt.observer
+}
+
+func (opt *readerObserverOption) ConfigureReader(config *ReaderConfig) {
+	config.Observer = opt.observer
+}
+
+type decryptionOption struct{ decryption *DecryptionConfig }
+
+func (opt *decryptionOption) ConfigureFile(config *FileConfig) {
+	config.Decryption = opt.decryption
+}
+
+func (opt *decryptionOption) ConfigureValidation(config *ValidationConfig) {
+	config.Decryption = opt.decryption
+}
+
+type validationOption func(*ValidationConfig)
+
+func (opt validationOption) ConfigureValidation(config *ValidationConfig) { opt(config) }
+
 type writerOption func(*WriterConfig)
 
 func (opt writerOption) ConfigureWriter(config *WriterConfig) { opt(config) }
@@ -444,6 +1493,14 @@ type rowGroupOption func(*RowGroupConfig)
 
 func (opt rowGroupOption) ConfigureRowGroup(config *RowGroupConfig) { opt(config) }
 
+type csvOption func(*CSVConfig)
+
+func (opt csvOption) ConfigureCSV(config *CSVConfig) { opt(config) }
+
+type jsonOption func(*JSONConfig)
+
+func (opt jsonOption) ConfigureJSON(config *JSONConfig) { opt(config) }
+
 func coalesceInt(i1, i2 int) int {
 	if i1 != 0 {
 		return i1
@@ -458,6 +1515,20 @@ func coalesceInt64(i1, i2 int64) int64 {
 	return i2
 }
 
+func coalesceDuration(d1, d2 time.Duration) time.Duration {
+	if d1 != 0 {
+		return d1
+	}
+	return d2
+}
+
+func coalesceRune(r1, r2 rune) rune {
+	if r1 != 0 {
+		return r1
+	}
+	return r2
+}
+
 func coalesceString(s1, s2 string) string {
 	if s1 != "" {
 		return s1
@@ -465,6 +1536,13 @@ func coalesceString(s1, s2 string) string {
 	return s2
 }
 
+func coalesceStrings(s1, s2 []string) []string {
+	if s1 != nil {
+		return s1
+	}
+	return s2
+}
+
 func coalesceBytes(b1, b2 []byte) []byte {
 	if b1 != nil {
 		return b1
@@ -493,6 +1571,13 @@ func coalesceSortingColumns(s1, s2 []SortingColumn) []SortingColumn {
 	return s2
 }
 
+func coalesceKeyConflict(k1, k2 KeyConflict) KeyConflict {
+	if k1 != 0 {
+		return k1
+	}
+	return k2
+}
+
 func coalesceBloomFilters(f1, f2 []BloomFilterColumn) []BloomFilterColumn {
 	if f1 != nil {
 		return f1
@@ -500,6 +1585,111 @@ func coalesceBloomFilters(f1, f2 []BloomFilterColumn) []BloomFilterColumn {
 	return f2
 }
 
+func coalesceDerivedColumns(c1, c2 []DerivedColumn) []DerivedColumn {
+	if c1 != nil {
+		return c1
+	}
+	return c2
+}
+
+func coalesceCompression(c1, c2 compress.Codec) compress.Codec {
+	if c1 != nil {
+		return c1
+	}
+	return c2
+}
+
+func coalesceColumnCompression(c1, c2 []ColumnCodec) []ColumnCodec {
+	if c1 != nil {
+		return c1
+	}
+	return c2
+}
+
+func coalesceColumnEncoding(e1, e2 []ColumnEncodingConfig) []ColumnEncodingConfig {
+	if e1 != nil {
+		return e1
+	}
+	return e2
+}
+
+func coalesceColumnTransforms(t1, t2 []ColumnTransformConfig) []ColumnTransformConfig {
+	if t1 != nil {
+		return t1
+	}
+	return t2
+}
+
+func coalesceColumnOverrides(o1, o2 []ColumnOverrideConfig) []ColumnOverrideConfig {
+	if o1 != nil {
+		return o1
+	}
+	return o2
+}
+
+func coalesceDictionaryLimits(l1, l2 []ColumnDictionaryLimit) []ColumnDictionaryLimit {
+	if l1 != nil {
+		return l1
+	}
+	return l2
+}
+
+func coalesceEncryption(e1, e2 *EncryptionConfig) *EncryptionConfig {
+	if e1 != nil {
+		return e1
+	}
+	return e2
+}
+
+func coalesceWriterObserver(o1, o2 *WriterObserver) *WriterObserver {
+	if o1 != nil {
+		return o1
+	}
+	return o2
+}
+
+func coalesceRowGroupFlushFuncs(f1, f2 []func(*FlushedRowGroup) error) []func(*FlushedRowGroup) error {
+	if f1 != nil {
+		return f1
+	}
+	return f2
+}
+
+func coalesceReaderObserver(o1, o2 *ReaderObserver) *ReaderObserver {
+	if o1 != nil {
+		return o1
+	}
+	return o2
+}
+
+func coalesceDecryption(d1, d2 *DecryptionConfig) *DecryptionConfig {
+	if d1 != nil {
+		return d1
+	}
+	return d2
+}
+
+func coalesceZstdDictionaries(d1, d2 [][]byte) [][]byte {
+	if d1 != nil {
+		return d1
+	}
+	return d2
+}
+
+func coalesceReport(f1, f2 func(error)) func(error) {
+	if f1 != nil {
+		return f1
+	}
+	return f2
+}
+
+func coalesceCompressionCodecResolver(f1, f2 func(format.CompressionCodec) compress.Codec) func(format.CompressionCodec) compress.Codec {
+	if f1 != nil {
+		return f1
+	}
+	return f2
+}
+
 func validatePositiveInt(optionName string, optionValue int) error {
 	if optionValue > 0 {
 		return nil
@@ -507,6 +1697,20 @@ func validatePositiveInt(optionName string, optionValue int) error {
 	return errorInvalidOptionValue(optionName, optionValue)
 }
 
+func validateNonNegativeInt(optionName string, optionValue int) error {
+	if optionValue >= 0 {
+		return nil
+	}
+	return errorInvalidOptionValue(optionName, optionValue)
+}
+
+func validateNonNegativeInt64(optionName string, optionValue int64) error {
+	if optionValue >= 0 {
+		return nil
+	}
+	return errorInvalidOptionValue(optionName, optionValue)
+}
+
 func validatePositiveInt64(optionName string, optionValue int64) error {
 	if optionValue > 0 {
 		return nil
@@ -523,6 +1727,141 @@ func validateOneOfInt(optionName string, optionValue int, supportedValues ...int
 	return errorInvalidOptionValue(optionName, optionValue)
 }
 
+func validateNotEmptyString(optionName string, optionValue string) error {
+	if optionValue != "" {
+		return nil
+	}
+	return errorInvalidOptionValue(optionName, optionValue)
+}
+
+func validateCSVComma(optionName string, optionValue rune) error {
+	switch optionValue {
+	case 0, '"', '\r', '\n', utf8.RuneError:
+		return errorInvalidOptionValue(optionName, optionValue)
+	}
+	return nil
+}
+
+func validateColumnCompression(optionName string, codecs []ColumnCodec) error {
+	for i, c := range codecs {
+		if len(c.Path) == 0 {
+			return errorInvalidOptionValue(fmt.Sprintf("%s[%d].Path", optionName, i), c.Path)
+		}
+		if c.Codec == nil {
+			return errorInvalidOptionValue(fmt.Sprintf("%s[%d].Codec", optionName, i), c.Codec)
+		}
+		if err := validateCompression(fmt.Sprintf("%s[%d].Codec", optionName, i), c.Codec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateCompression(optionName string, codec compress.Codec) error {
+	if _, err := zstdDictionaryIDOf(codec); err != nil {
+		return fmt.Errorf("invalid option value: %s: %w", optionName, err)
+	}
+	return nil
+}
+
+func validateColumnTransforms(optionName string, transforms []ColumnTransformConfig) error {
+	for i, t := range transforms {
+		if len(t.Path) == 0 {
+			return errorInvalidOptionValue(fmt.Sprintf("%s[%d].Path", optionName, i), t.Path)
+		}
+		if t.Transform == nil {
+			return errorInvalidOptionValue(fmt.Sprintf("%s[%d].Transform", optionName, i), "nil")
+		}
+	}
+	return nil
+}
+
+func validateColumnOverrides(optionName string, overrides []ColumnOverrideConfig) error {
+	for i, o := range overrides {
+		if len(o.Path) == 0 {
+			return errorInvalidOptionValue(fmt.Sprintf("%s[%d].Path", optionName, i), o.Path)
+		}
+		if o.Override == nil {
+			return errorInvalidOptionValue(fmt.Sprintf("%s[%d].Override", optionName, i), "nil")
+		}
+	}
+	return nil
+}
+
+func validateColumnEncoding(optionName string, encodings []ColumnEncodingConfig) error {
+	for i, e := range encodings {
+		if len(e.Path) == 0 {
+			return errorInvalidOptionValue(fmt.Sprintf("%s[%d].Path", optionName, i), e.Path)
+		}
+		if e.Encoding == nil {
+			return errorInvalidOptionValue(fmt.Sprintf("%s[%d].Encoding", optionName, i), e.Encoding)
+		}
+	}
+	return nil
+}
+
+func validateDictionaryLimits(optionName string, limits []ColumnDictionaryLimit) error {
+	for i, l := range limits {
+		optionName := fmt.Sprintf("%s[%d]", optionName, i)
+		if len(l.Path) == 0 {
+			return errorInvalidOptionValue(optionName+".Path", l.Path)
+		}
+		if err := validateNonNegativeInt(optionName+".MaxSize", l.MaxSize); err != nil {
+			return err
+		}
+		if err := validateNonNegativeInt(optionName+".MaxValues", l.MaxValues); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateDerivedColumns(optionName string, columns []DerivedColumn) error {
+	names := make(map[string]struct{}, len(columns))
+	for i, c := range columns {
+		optionName := fmt.Sprintf("%s[%d]", optionName, i)
+		name, node := c.Name(), c.Node()
+		if _, exists := names[name]; exists || name == "" {
+			return errorInvalidOptionValue(optionName+".Name", name)
+		}
+		if node == nil || !isLeaf(node) || node.Repeated() {
+			return errorInvalidOptionValue(optionName+".Node", node)
+		}
+		names[name] = struct{}{}
+	}
+	return nil
+}
+
+func validateZstdDictionaries(optionName string, dictionaries [][]byte) error {
+	for _, dictionary := range dictionaries {
+		if _, err := zstd.DictionaryID(dictionary); err != nil {
+			return fmt.Errorf("invalid option value: %s: %w", optionName, err)
+		}
+	}
+	return nil
+}
+
+func validateEncryption(optionName string, config *EncryptionConfig) error {
+	if config == nil {
+		return nil
+	}
+	return config.validate(optionName + ".")
+}
+
+func validateDecryption(optionName string, config *DecryptionConfig) error {
+	if config == nil {
+		return nil
+	}
+	return config.validate(optionName + ".")
+}
+
+func validateSchema(schema *Schema, enabled bool) error {
+	if schema == nil || !enabled {
+		return nil
+	}
+	return schema.Validate()
+}
+
 func validateNotNil(optionName string, optionValue interface{}) error {
 	if optionValue != nil {
 		return nil
@@ -575,4 +1914,6 @@ var (
 	_ ReaderOption   = (*ReaderConfig)(nil)
 	_ WriterOption   = (*WriterConfig)(nil)
 	_ RowGroupOption = (*RowGroupConfig)(nil)
+	_ CSVOption      = (*CSVConfig)(nil)
+	_ JSONOption     = (*JSONConfig)(nil)
 )
@@ -3,7 +3,12 @@ package parquet
 import (
 	"fmt"
 	"io"
+	"math"
 	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/segmentio/parquet-go/format"
 )
 
 // ConvertError is an error type returned by calls to Convert when the conversion
@@ -91,6 +96,151 @@ func Convert(to, from Node) (conv Conversion, err error) {
 	return c, nil
 }
 
+// ConvertCompatibility classifies how rows are affected by the conversion from
+// one schema to another.
+type ConvertCompatibility int
+
+const (
+	// ConvertSafe indicates that the conversion retains all values.
+	ConvertSafe ConvertCompatibility = iota
+	// ConvertLossy indicates that the conversion is supported but may discard
+	// values, lose precision, or fail for values which do not fit in the target
+	// column.
+	ConvertLossy
+	// ConvertImpossible indicates that the conversion is not supported.
+	ConvertImpossible
+)
+
+// String returns a human-readable representation of c.
+func (c ConvertCompatibility) String() string {
+	switch c {
+	case ConvertSafe:
+		return "safe"
+	case ConvertLossy:
+		return "lossy"
+	case ConvertImpossible:
+		return "impossible"
+	default:
+		return fmt.Sprintf("ConvertCompatibility(%d)", int(c))
+	}
+}
+
+// ConvertIssue is a difference between the source and target schemas of a
+// conversion, classified by its impact on the converted rows.
+type ConvertIssue struct {
+	SchemaDifference
+	Compatibility ConvertCompatibility
+	Reason        string
+}
+
+// String returns a human-readable representation of i.
+func (i *ConvertIssue) String() string {
+	return fmt.Sprintf("%s: %s (%s)", i.Compatibility, &i.SchemaDifference, i.Reason)
+}
+
+// CanConvert reports whether rows of the schema from can be converted to the
+// schema to with Convert, returning the classification of each difference
+// between the schemas (see CompareSchemas) and the least compatible of them.
+//
+// Convert fails when the result is ConvertImpossible, otherwise it succeeds,
+// though rows may fail to convert when the result is ConvertLossy and some of
+// their values cannot be represented in the target schema.
+func CanConvert(to, from Node) (ConvertCompatibility, []ConvertIssue) {
+	compatibility := ConvertSafe
+	diffs := CompareSchemas(from, to)
+	issues := make([]ConvertIssue, len(diffs))
+
+	for i, diff := range diffs {
+		issues[i].SchemaDifference = diff
+		issues[i].Compatibility, issues[i].Reason = convertCompatibilityOf(&diff)
+		if issues[i].Compatibility > compatibility {
+			compatibility = issues[i].Compatibility
+		}
+	}
+
+	return compatibility, issues
+}
+
+func convertCompatibilityOf(diff *SchemaDifference) (ConvertCompatibility, string) {
+	switch diff.Kind {
+	case ColumnAdded:
+		return ConvertSafe, "the column is filled with null or zero values"
+	case ColumnRemoved:
+		return ConvertLossy, "the values of the column are discarded"
+	case ColumnRenamed:
+		return ConvertLossy, "columns are matched by name, the values of the column are discarded"
+	case RepetitionChanged:
+		return ConvertImpossible, fmt.Sprintf("cannot convert from %s to %s column", repetitionOf(diff.Old), repetitionOf(diff.New))
+	}
+
+	from, to := diff.Old, diff.New
+	switch leafFrom, leafTo := isLeaf(from), isLeaf(to); {
+	case leafFrom && !leafTo:
+		return ConvertImpossible, "cannot convert from leaf to group column"
+	case !leafFrom && leafTo:
+		return ConvertImpossible, "cannot convert from group to leaf column"
+	case !leafFrom && !leafTo:
+		return ConvertSafe, "the logical type of the group column changed"
+	}
+
+	fromType, toType := from.Type(), to.Type()
+	switch {
+	case isIntegerWidening(fromType, toType):
+		if integerTypeFits(fromType, toType) {
+			return ConvertSafe, "the integer values are widened"
+		}
+		return ConvertLossy, "the integer values may not fit in the target type"
+	case !typesAreEqual(from, to):
+		return ConvertImpossible, fmt.Sprintf("unsupported type conversion from %s to %s", fromType, toType)
+	case fromType.Kind() == FixedLenByteArray && fromType.Length() != toType.Length():
+		return ConvertImpossible, fmt.Sprintf("cannot convert fixed length byte arrays of size %d to %d", fromType.Length(), toType.Length())
+	}
+
+	if fromUnit, toUnit := timeUnitOf(fromType), timeUnitOf(toType); fromUnit != 0 && toUnit != 0 && fromUnit != toUnit {
+		if fromUnit < toUnit {
+			return ConvertLossy, "the time values are truncated to a coarser unit"
+		}
+		return ConvertLossy, "the time values may overflow in a finer unit"
+	}
+
+	if k := fromType.Kind(); (k == Int32 || k == Int64) && isIntegerType(fromType) && isIntegerType(toType) && !integerTypeFits(fromType, toType) {
+		return ConvertLossy, "the integer values are copied without checking the range of the target type"
+	}
+
+	if fromDecimal, toDecimal := decimalTypeOf(fromType), decimalTypeOf(toType); fromDecimal != nil || toDecimal != nil {
+		if fromDecimal == nil || toDecimal == nil || fromDecimal.Scale != toDecimal.Scale || fromDecimal.Precision > toDecimal.Precision {
+			return ConvertLossy, "the unscaled values are copied without conversion to the target decimal type"
+		}
+	}
+
+	return ConvertSafe, "the values are copied unchanged"
+}
+
+// integerTypeFits returns true if all values of the integer type from can be
+// represented by the integer type to.
+func integerTypeFits(from, to Type) bool {
+	fromBits, fromSigned := integerTypeBitWidthOf(from)
+	toBits, toSigned := integerTypeBitWidthOf(to)
+	switch {
+	case fromSigned == toSigned:
+		return fromBits <= toBits
+	case toSigned:
+		return fromBits < toBits
+	default:
+		return false
+	}
+}
+
+func integerTypeBitWidthOf(t Type) (bitWidth int, signed bool) {
+	if lt := t.LogicalType(); lt != nil && lt.Integer != nil {
+		return int(lt.Integer.BitWidth), lt.Integer.IsSigned
+	}
+	if t.Kind() == Int32 {
+		return 32, true
+	}
+	return 64, true
+}
+
 type convertFunc func(Row, Row, levels) (Row, Row, error)
 
 type convertNode struct {
@@ -106,6 +256,13 @@ func (c convertNode) child(name string) convertNode {
 }
 
 func convert(to, from convertNode, columns []int16) (int16, int16, convertFunc) {
+	switch {
+	case isList(to.node) && isList(from.node):
+		from.node = standardListOf(from.node)
+	case isMap(to.node) && isMap(from.node):
+		from.node = standardMapOf(from.node)
+	}
+
 	switch {
 	case from.node.Optional():
 		if to.node.Optional() {
@@ -139,6 +296,45 @@ func convert(to, from convertNode, columns []int16) (int16, int16, convertFunc)
 	}
 }
 
+// standardListOf returns a view of the LIST column node using the three-level
+// layout of the parquet spec, so columns of files written with legacy layouts
+// are matched by name with the .list.element columns of the target schema.
+func standardListOf(node Node) Node {
+	if list := node.ChildByName("list"); list != nil && list.ChildByName("element") != nil {
+		return node
+	}
+	elem := findListElement(node)
+	if elem == nil {
+		return node
+	}
+	list := Node(Group{"list": Repeated(Group{"element": elem})})
+	switch {
+	case node.Optional():
+		list = Optional(list)
+	case node.Repeated():
+		list = Repeated(list)
+	}
+	return list
+}
+
+// standardMapOf returns a view of the MAP column node where the repeated group
+// is named key_value, as files written by older implementations may use other
+// names.
+func standardMapOf(node Node) Node {
+	if node.ChildByName("key_value") != nil {
+		return node
+	}
+	keyValue := findMapKeyValue(node)
+	if keyValue == nil {
+		return node
+	}
+	m := Node(Group{"key_value": keyValue})
+	if node.Optional() {
+		m = Optional(m)
+	}
+	return m
+}
+
 func convertError(to, from convertNode, reason string) *ConvertError {
 	return &ConvertError{Reason: reason, Path: from.path, From: from.node, To: to.node}
 }
@@ -150,7 +346,12 @@ func convertFuncOfOptional(to, from convertNode, columns []int16) (int16, int16,
 
 	toColumnIndex, fromColumnIndex, conv := convert(to, from, columns)
 	return toColumnIndex, fromColumnIndex, func(dst, src Row, levels levels) (Row, Row, error) {
-		levels.definitionLevel++
+		// The optional columns of the source and target schemas are nested the
+		// same way, the definition level is only incremented when the source
+		// value is defined at this level to preserve null values.
+		if len(src) > 0 && src[0].definitionLevel > levels.definitionLevel {
+			levels.definitionLevel++
+		}
 		return conv(dst, src, levels)
 	}
 }
@@ -165,13 +366,27 @@ func convertFuncOfRepeated(to, from convertNode, columns []int16) (int16, int16,
 	return toColumnIndex, fromColumnIndex, func(dst, src Row, levels levels) (Row, Row, error) {
 		var err error
 
+		if len(src) == 0 || src[0].columnIndex != srcColumnIndex {
+			return dst, src, nil
+		}
+		// Empty repeated columns are represented by a single null value which
+		// is not defined at this level.
+		if src[0].definitionLevel <= levels.definitionLevel {
+			return conv(dst, src, levels)
+		}
+
 		levels.repetitionDepth++
 		levels.definitionLevel++
 
-		for len(src) > 0 && src[0].columnIndex == srcColumnIndex {
+		for {
 			if dst, src, err = conv(dst, src, levels); err != nil {
 				break
 			}
+			// Values repeated at a lower depth belong to the next element of a
+			// parent repeated column, or to the next row.
+			if len(src) == 0 || src[0].columnIndex != srcColumnIndex || src[0].repetitionLevel < levels.repetitionDepth {
+				break
+			}
 			levels.repetitionLevel = levels.repetitionDepth
 		}
 
@@ -181,7 +396,8 @@ func convertFuncOfRepeated(to, from convertNode, columns []int16) (int16, int16,
 
 //go:noinline
 func convertFuncOfLeaf(to, from convertNode, columns []int16) (int16, int16, convertFunc) {
-	if !typesAreEqual(to.node, from.node) {
+	fromType, toType := from.node.Type(), to.node.Type()
+	if !typesAreEqual(to.node, from.node) && !isIntegerWidening(fromType, toType) {
 		panic(convertError(to, from, fmt.Sprintf("unsupported type conversion from %s to %s for parquet column", from.node.Type(), to.node.Type())))
 	}
 
@@ -189,11 +405,21 @@ func convertFuncOfLeaf(to, from convertNode, columns []int16) (int16, int16, con
 	dstColumnIndex := ^to.columnIndex
 	columns[to.columnIndex] = from.columnIndex
 
+	// Values are converted when the types differ, for example when widening
+	// integers or when reading nanosecond timestamps into a microsecond column.
+	needsConversion := !typesAreEqual(to.node, from.node) || !timeUnitsAreEqual(from.node, to.node)
+
 	return to.columnIndex + 1, from.columnIndex + 1, func(dst, src Row, levels levels) (Row, Row, error) {
 		if len(src) == 0 || src[0].columnIndex != srcColumnIndex {
 			return dst, src, convertError(to, from, "no value found in row for parquet column")
 		}
 		v := src[0]
+		if needsConversion {
+			var err error
+			if v, err = ConvertValue(v, fromType, toType); err != nil {
+				return dst, src, convertError(to, from, err.Error())
+			}
+		}
 		v.repetitionLevel = levels.repetitionLevel
 		v.definitionLevel = levels.definitionLevel
 		v.columnIndex = dstColumnIndex
@@ -302,7 +528,294 @@ func repetitionsAreEqual(node1, node2 Node) bool {
 }
 
 func leafNodesAreEqual(node1, node2 Node) bool {
-	return typesAreEqual(node1, node2) && repetitionsAreEqual(node1, node2)
+	return typesAreEqual(node1, node2) && repetitionsAreEqual(node1, node2) && timeUnitsAreEqual(node1, node2)
+}
+
+func timeUnitsAreEqual(node1, node2 Node) bool {
+	unit1 := timeUnitOf(node1.Type())
+	unit2 := timeUnitOf(node2.Type())
+	return unit1 == 0 || unit2 == 0 || unit1 == unit2
+}
+
+// isIntegerWidening returns true if values of 32 bits integer columns of type
+// from can be widened to 64 bits integer columns of type to.
+func isIntegerWidening(from, to Type) bool {
+	return from.Kind() == Int32 && to.Kind() == Int64 && isIntegerType(from) && isIntegerType(to)
+}
+
+// isIntegerType returns true if t is a plain INT32 or INT64 type, or has the
+// INT logical type.
+func isIntegerType(t Type) bool {
+	lt := t.LogicalType()
+	return lt == nil || lt.Integer != nil
+}
+
+// timeUnitOf returns the unit of values in columns of TIME and TIMESTAMP
+// logical types, or zero for other types.
+func timeUnitOf(t Type) time.Duration {
+	switch tt := t.(type) {
+	case *timeType:
+		return timeUnitDuration(&tt.Unit)
+	case *timestampType:
+		return timeUnitDuration(&tt.Unit)
+	default:
+		return 0
+	}
+}
+
+// ConvertValue converts v, a value of type from, to a value of type to. The
+// repetition level, definition level, and column index of v are retained.
+//
+// The conversion takes the logical types into account in addition to the
+// kinds of values (see Value.Convert):
+//
+//   - Integers are interpreted as unsigned when the source type is an unsigned
+//     INT logical type, and must fit in the bit width and signedness of the
+//     target type when it has the INT logical type.
+//   - TIME and TIMESTAMP values are scaled when the units of the types differ,
+//     rounding down when the target unit is coarser.
+//   - Values converted to the UTF8 logical type (see String) must hold valid
+//     UTF-8 sequences.
+//   - Values converted to FIXED_LEN_BYTE_ARRAY types must have the length of
+//     the target type.
+//   - Integers and floating point numbers converted to the DECIMAL logical
+//     type are scaled to the scale of the target, and must fit in its
+//     precision. Floating point numbers are rounded to the nearest decimal.
+//     Decimals of INT32 and INT64 types are supported.
+//   - DECIMAL values are scaled back when converted to other numeric types, the
+//     conversion to integers fails if the decimal has a fractional part.
+//
+// The function returns an error wrapping ErrValueOutOfRange if the value cannot
+// be represented by the target type. Null values are returned unchanged.
+func ConvertValue(v Value, from, to Type) (Value, error) {
+	if v.IsNull() {
+		return v, nil
+	}
+	r, err := convertValue(v, from, to)
+	if err != nil {
+		return v, fmt.Errorf("cannot convert parquet value %v of type %s to %s: %w", v, from, to, err)
+	}
+	r.repetitionLevel = v.repetitionLevel
+	r.definitionLevel = v.definitionLevel
+	r.columnIndex = v.columnIndex
+	return r, nil
+}
+
+func convertValue(v Value, from, to Type) (Value, error) {
+	fromDecimal := decimalTypeOf(from)
+	toDecimal := decimalTypeOf(to)
+
+	switch {
+	case fromDecimal != nil || toDecimal != nil:
+		return convertDecimal(v, from, to, fromDecimal, toDecimal)
+
+	case isStringType(to):
+		switch v.Kind() {
+		case ByteArray, FixedLenByteArray:
+			if !utf8.Valid(v.ByteArray()) {
+				return v, fmt.Errorf("invalid UTF-8 sequence")
+			}
+		}
+		return v.Convert(to.Kind())
+	}
+
+	switch v.Kind() {
+	case Int32, Int64:
+		n, ok := integerOf(v, from)
+		if !ok {
+			return v, ErrValueOutOfRange
+		}
+		if fromUnit, toUnit := timeUnitOf(from), timeUnitOf(to); fromUnit != 0 && toUnit != 0 && fromUnit != toUnit {
+			if n, ok = convertTimeUnit(n, fromUnit, toUnit); !ok {
+				return v, ErrValueOutOfRange
+			}
+		}
+		return makeInteger(n, to)
+
+	case Float, Double:
+		switch to.Kind() {
+		case Int32, Int64:
+			i, err := v.Convert(Int64)
+			if err != nil {
+				return v, err
+			}
+			return makeInteger(i.Int64(), to)
+		}
+
+	case ByteArray, FixedLenByteArray:
+		// Values of FIXED_LEN_BYTE_ARRAY types must have the exact length of
+		// the type, or they would corrupt the columns they are written to.
+		if to.Kind() == FixedLenByteArray && int(v.u64) != to.Length() {
+			return v, ErrValueOutOfRange
+		}
+	}
+
+	return v.Convert(to.Kind())
+}
+
+// convertDecimal converts v from or to a DECIMAL type. Either of fromDecimal
+// or toDecimal may be nil when the type is not a decimal.
+func convertDecimal(v Value, from, to Type, fromDecimal, toDecimal *format.DecimalType) (Value, error) {
+	fromScale := 0
+	if fromDecimal != nil {
+		fromScale = int(fromDecimal.Scale)
+	}
+
+	if toDecimal == nil {
+		n, ok := integerOf(v, from)
+		if !ok {
+			return v, fmt.Errorf("unsupported decimal conversion")
+		}
+		switch to.Kind() {
+		case Float, Double:
+			f := float64(n) / math.Pow10(fromScale)
+			return makeValueDouble(f).Convert(to.Kind())
+		}
+		if n, ok = scaleDecimal(n, fromScale, 0); !ok {
+			return v, ErrValueOutOfRange
+		}
+		return makeInteger(n, to)
+	}
+
+	switch to.Kind() {
+	case Int32, Int64:
+	default:
+		return v, fmt.Errorf("unsupported decimal conversion")
+	}
+
+	var n int64
+	var ok bool
+	switch v.Kind() {
+	case Float, Double:
+		f := v.Double()
+		if v.Kind() == Float {
+			f = float64(v.Float())
+		}
+		f = math.Round(f * math.Pow10(int(toDecimal.Scale)))
+		if !(f >= math.MinInt64 && f < maxInt64AsFloat) {
+			return v, ErrValueOutOfRange
+		}
+		n = int64(f)
+	default:
+		if n, ok = integerOf(v, from); !ok {
+			return v, fmt.Errorf("unsupported decimal conversion")
+		}
+		if n, ok = scaleDecimal(n, fromScale, int(toDecimal.Scale)); !ok {
+			return v, ErrValueOutOfRange
+		}
+	}
+
+	if p := int(toDecimal.Precision); p < len(powersOf10) && (n <= -powersOf10[p] || n >= powersOf10[p]) {
+		return v, ErrValueOutOfRange
+	}
+	return makeInteger(n, to)
+}
+
+// powersOf10 holds the powers of 10 which can be represented by int64.
+var powersOf10 = [...]int64{
+	1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9,
+	1e10, 1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18,
+}
+
+// scaleDecimal converts the unscaled decimal n from one scale to another. The
+// function returns false if the result overflows, or if the conversion to a
+// smaller scale would discard non-zero digits.
+func scaleDecimal(n int64, from, to int) (int64, bool) {
+	switch {
+	case from < to:
+		if to-from >= len(powersOf10) {
+			return n, n == 0
+		}
+		m := powersOf10[to-from]
+		if n > math.MaxInt64/m || n < math.MinInt64/m {
+			return n, false
+		}
+		return n * m, true
+	case from > to:
+		if from-to >= len(powersOf10) {
+			return 0, n == 0
+		}
+		d := powersOf10[from-to]
+		return n / d, n%d == 0
+	default:
+		return n, true
+	}
+}
+
+// integerOf returns the integer held by v, a value of type t. Values of types
+// with the unsigned INT logical type are zero-extended. The function returns
+// false if v is not an integer, or does not fit in a signed 64 bits integer.
+func integerOf(v Value, t Type) (int64, bool) {
+	unsigned := isUnsignedIntType(t)
+	switch v.Kind() {
+	case Int32:
+		if unsigned {
+			return int64(uint32(v.Int32())), true
+		}
+		return int64(v.Int32()), true
+	case Int64:
+		n := v.Int64()
+		return n, !unsigned || n >= 0
+	default:
+		return 0, false
+	}
+}
+
+// makeInteger returns a value of type t holding the integer n, checking that
+// it fits in the kind of t and the bit width and signedness of its INT logical
+// type, if any.
+func makeInteger(n int64, t Type) (Value, error) {
+	if lt := t.LogicalType(); lt != nil && lt.Integer != nil {
+		bitWidth := uint(lt.Integer.BitWidth)
+		if lt.Integer.IsSigned {
+			if bitWidth < 64 && (n < -(1<<(bitWidth-1)) || n >= 1<<(bitWidth-1)) {
+				return Value{}, ErrValueOutOfRange
+			}
+		} else {
+			if n < 0 || (bitWidth < 64 && n >= 1<<bitWidth) {
+				return Value{}, ErrValueOutOfRange
+			}
+			if t.Kind() == Int32 {
+				return makeValueInt32(int32(uint32(n))), nil
+			}
+		}
+	}
+	return makeValueInt64(n).Convert(t.Kind())
+}
+
+func decimalTypeOf(t Type) *format.DecimalType {
+	if lt := t.LogicalType(); lt != nil {
+		return lt.Decimal
+	}
+	return nil
+}
+
+func isStringType(t Type) bool {
+	lt := t.LogicalType()
+	return lt != nil && lt.UTF8 != nil
+}
+
+func isUnsignedIntType(t Type) bool {
+	lt := t.LogicalType()
+	return lt != nil && lt.Integer != nil && !lt.Integer.IsSigned
+}
+
+// convertTimeUnit converts n from one unit of time to another, rounding down
+// when the target unit is coarser than the source. The function returns false
+// if the result overflows.
+func convertTimeUnit(n int64, from, to time.Duration) (int64, bool) {
+	if from < to {
+		d := int64(to / from)
+		if n%d < 0 {
+			n -= d
+		}
+		return n / d, true
+	}
+	m := int64(from / to)
+	if n > math.MaxInt64/m || n < math.MinInt64/m {
+		return n, false
+	}
+	return n * m, true
 }
 
 func groupNodesAreEqual(node1, node2 Node) bool {
@@ -453,6 +966,16 @@ func maskMissingRowGroupColumns(r RowGroup, numColumns int, conv Conversion) Row
 		}
 	}
 
+	// The values of columns nested in repeated groups drive the conversion of
+	// the repeated groups, the missing column pages do not have the repetition
+	// and definition levels required to do so, therefore these columns are
+	// never masked.
+	forEachLeafColumnOf(r.Schema(), func(leaf leafColumn) {
+		if leaf.maxRepetitionLevel > 0 && int(leaf.columnIndex) < len(columns) {
+			columns[leaf.columnIndex] = r.Column(int(leaf.columnIndex))
+		}
+	})
+
 	return &rowGroup{
 		schema:  r.Schema(),
 		numRows: numRows,
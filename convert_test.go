@@ -1,8 +1,10 @@
 package parquet_test
 
 import (
+	"math"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/segmentio/parquet-go"
 )
@@ -97,6 +99,59 @@ var conversionTests = [...]struct {
 			Names []string
 		}{ID: 1, Names: []string{}},
 	},
+
+	{
+		scenario: "empty and nested repeated columns",
+		from: struct {
+			ID     uint64
+			Names  []string
+			Matrix [][]int32
+		}{ID: 1, Names: []string{}, Matrix: [][]int32{{1, 2}, {}, {3}}},
+		to: struct {
+			Names  []string
+			Matrix [][]int32
+		}{Names: []string{}, Matrix: [][]int32{{1, 2}, {}, {3}}},
+	},
+
+	{
+		scenario: "null optional column",
+		from:     struct{ Name *string }{Name: nil},
+		to:       struct{ Name *string }{Name: nil},
+	},
+
+	{
+		scenario: "widen signed integer",
+		from:     struct{ X int32 }{X: math.MinInt32},
+		to:       struct{ X int64 }{X: math.MinInt32},
+	},
+
+	{
+		scenario: "widen unsigned integer",
+		from:     struct{ X uint32 }{X: math.MaxUint32},
+		to:       struct{ X uint64 }{X: math.MaxUint32},
+	},
+
+	{
+		scenario: "timestamp unit",
+		from: struct {
+			After  int64 `parquet:"after,timestamp(nanos)"`
+			Before int64 `parquet:"before,timestamp(nanos)"`
+		}{After: 1500000001, Before: -1},
+		to: struct {
+			After  int64 `parquet:"after,timestamp(micros)"`
+			Before int64 `parquet:"before,timestamp(micros)"`
+		}{After: 1500000, Before: -1},
+	},
+
+	{
+		scenario: "timestamp unit of time values",
+		from: struct {
+			Time time.Time `parquet:"time,timestamp(millis)"`
+		}{Time: time.Date(2022, time.March, 14, 10, 30, 0, 123e6, time.UTC)},
+		to: struct {
+			Time time.Time `parquet:"time,timestamp(nanos)"`
+		}{Time: time.Date(2022, time.March, 14, 10, 30, 0, 123e6, time.UTC)},
+	},
 }
 
 func TestConvert(t *testing.T) {
@@ -130,3 +185,254 @@ func TestConvert(t *testing.T) {
 }
 
 func newString(s string) *string { return &s }
+
+func TestConvertValue(t *testing.T) {
+	tests := []struct {
+		scenario string
+		value    parquet.Value
+		from     parquet.Type
+		to       parquet.Type
+		want     parquet.Value
+		err      bool
+	}{
+		{
+			scenario: "unsigned INT32 to INT64",
+			value:    parquet.ValueOf(int32(-1)),
+			from:     parquet.Uint(32).Type(),
+			to:       parquet.Int64Type,
+			want:     parquet.ValueOf(int64(math.MaxUint32)),
+		},
+		{
+			scenario: "INT64 to INT(8) overflow",
+			value:    parquet.ValueOf(int64(128)),
+			from:     parquet.Int64Type,
+			to:       parquet.Int(8).Type(),
+			err:      true,
+		},
+		{
+			scenario: "signed to unsigned overflow",
+			value:    parquet.ValueOf(int64(-1)),
+			from:     parquet.Int64Type,
+			to:       parquet.Uint(64).Type(),
+			err:      true,
+		},
+		{
+			scenario: "FLOAT to DOUBLE",
+			value:    parquet.ValueOf(float32(1.5)),
+			from:     parquet.FloatType,
+			to:       parquet.DoubleType,
+			want:     parquet.ValueOf(float64(1.5)),
+		},
+		{
+			scenario: "BYTE_ARRAY to STRING",
+			value:    parquet.ValueOf([]byte("hello")),
+			from:     parquet.ByteArrayType,
+			to:       parquet.String().Type(),
+			want:     parquet.ValueOf("hello"),
+		},
+		{
+			scenario: "BYTE_ARRAY to STRING invalid",
+			value:    parquet.ValueOf([]byte{0xff}),
+			from:     parquet.ByteArrayType,
+			to:       parquet.String().Type(),
+			err:      true,
+		},
+		{
+			scenario: "BYTE_ARRAY to FIXED_LEN_BYTE_ARRAY",
+			value:    parquet.ValueOf([]byte("abc")),
+			from:     parquet.ByteArrayType,
+			to:       parquet.FixedLenByteArrayType(3),
+			want:     parquet.ValueOf([3]byte{'a', 'b', 'c'}),
+		},
+		{
+			scenario: "BYTE_ARRAY to FIXED_LEN_BYTE_ARRAY length mismatch",
+			value:    parquet.ValueOf([]byte("abc")),
+			from:     parquet.ByteArrayType,
+			to:       parquet.FixedLenByteArrayType(16),
+			err:      true,
+		},
+		{
+			scenario: "TIMESTAMP units",
+			value:    parquet.ValueOf(int64(1500)),
+			from:     parquet.Timestamp(parquet.Microsecond).Type(),
+			to:       parquet.Timestamp(parquet.Millisecond).Type(),
+			want:     parquet.ValueOf(int64(1)),
+		},
+		{
+			scenario: "TIMESTAMP units overflow",
+			value:    parquet.ValueOf(int64(math.MaxInt64 / 10)),
+			from:     parquet.Timestamp(parquet.Millisecond).Type(),
+			to:       parquet.Timestamp(parquet.Nanosecond).Type(),
+			err:      true,
+		},
+		{
+			scenario: "INT32 to DECIMAL",
+			value:    parquet.ValueOf(int32(42)),
+			from:     parquet.Int32Type,
+			to:       parquet.Decimal(2, 9, parquet.Int64Type).Type(),
+			want:     parquet.ValueOf(int64(4200)),
+		},
+		{
+			scenario: "DOUBLE to DECIMAL",
+			value:    parquet.ValueOf(float64(1.005)),
+			from:     parquet.DoubleType,
+			to:       parquet.Decimal(2, 9, parquet.Int32Type).Type(),
+			want:     parquet.ValueOf(int32(100)),
+		},
+		{
+			scenario: "DECIMAL precision overflow",
+			value:    parquet.ValueOf(int64(1000)),
+			from:     parquet.Int64Type,
+			to:       parquet.Decimal(2, 4, parquet.Int64Type).Type(),
+			err:      true,
+		},
+		{
+			scenario: "DECIMAL scale",
+			value:    parquet.ValueOf(int64(12300)),
+			from:     parquet.Decimal(4, 18, parquet.Int64Type).Type(),
+			to:       parquet.Decimal(2, 18, parquet.Int32Type).Type(),
+			want:     parquet.ValueOf(int32(123)),
+		},
+		{
+			scenario: "DECIMAL scale with fraction",
+			value:    parquet.ValueOf(int64(12345)),
+			from:     parquet.Decimal(4, 18, parquet.Int64Type).Type(),
+			to:       parquet.Decimal(2, 18, parquet.Int64Type).Type(),
+			err:      true,
+		},
+		{
+			scenario: "DECIMAL to DOUBLE",
+			value:    parquet.ValueOf(int64(-250)),
+			from:     parquet.Decimal(2, 18, parquet.Int64Type).Type(),
+			to:       parquet.DoubleType,
+			want:     parquet.ValueOf(float64(-2.5)),
+		},
+		{
+			scenario: "DECIMAL to INT64",
+			value:    parquet.ValueOf(int64(300)),
+			from:     parquet.Decimal(2, 18, parquet.Int64Type).Type(),
+			to:       parquet.Int64Type,
+			want:     parquet.ValueOf(int64(3)),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			v, err := parquet.ConvertValue(test.value, test.from, test.to)
+			if test.err {
+				if err == nil {
+					t.Fatalf("converting %v from %s to %s did not fail", test.value, test.from, test.to)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !parquet.Equal(v, test.want) {
+				t.Errorf("wrong converted value: want=%v got=%v", test.want, v)
+			}
+		})
+	}
+}
+
+func TestCanConvert(t *testing.T) {
+	type Base struct {
+		ID    int32   `parquet:"id"`
+		Name  string  `parquet:"name"`
+		Score float64 `parquet:"score"`
+	}
+
+	tests := []struct {
+		scenario string
+		to       interface{}
+		want     parquet.ConvertCompatibility
+	}{
+		{
+			scenario: "same schema",
+			to:       new(Base),
+			want:     parquet.ConvertSafe,
+		},
+
+		{
+			scenario: "added column",
+			to: new(struct {
+				ID      int32   `parquet:"id"`
+				Name    string  `parquet:"name"`
+				Score   float64 `parquet:"score"`
+				Country string  `parquet:"country"`
+			}),
+			want: parquet.ConvertSafe,
+		},
+
+		{
+			scenario: "integer widening",
+			to: new(struct {
+				ID    int64   `parquet:"id"`
+				Name  string  `parquet:"name"`
+				Score float64 `parquet:"score"`
+			}),
+			want: parquet.ConvertSafe,
+		},
+
+		{
+			scenario: "removed column",
+			to: new(struct {
+				ID   int32  `parquet:"id"`
+				Name string `parquet:"name"`
+			}),
+			want: parquet.ConvertLossy,
+		},
+
+		{
+			scenario: "unsigned integer narrowing",
+			to: new(struct {
+				ID    uint32  `parquet:"id"`
+				Name  string  `parquet:"name"`
+				Score float64 `parquet:"score"`
+			}),
+			want: parquet.ConvertLossy,
+		},
+
+		{
+			scenario: "incompatible types",
+			to: new(struct {
+				ID    int32   `parquet:"id"`
+				Name  string  `parquet:"name"`
+				Score float32 `parquet:"score"`
+			}),
+			want: parquet.ConvertImpossible,
+		},
+
+		{
+			scenario: "required to optional",
+			to: new(struct {
+				ID    int32   `parquet:"id"`
+				Name  string  `parquet:"name,optional"`
+				Score float64 `parquet:"score"`
+			}),
+			want: parquet.ConvertImpossible,
+		},
+	}
+
+	from := parquet.SchemaOf(new(Base))
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			to := parquet.SchemaOf(test.to)
+			got, issues := parquet.CanConvert(to, from)
+			if got != test.want {
+				t.Errorf("wrong compatibility: want=%s got=%s\n%v", test.want, got, issues)
+			}
+			for _, issue := range issues {
+				if issue.Compatibility > got {
+					t.Errorf("issue is less compatible than the result: %s", &issue)
+				}
+			}
+
+			_, err := parquet.Convert(to, from)
+			if impossible := got == parquet.ConvertImpossible; impossible != (err != nil) {
+				t.Errorf("compatibility does not match the result of Convert: compatibility=%s err=%v", got, err)
+			}
+		})
+	}
+}
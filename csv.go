@@ -0,0 +1,345 @@
+package parquet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CSVWriter is a row writer which formats parquet rows as CSV records.
+//
+// Each leaf column of the schema is mapped to a CSV field, nested columns are
+// named by joining the elements of their path with the configured path
+// separator. Values of repeated columns are written to a single field,
+// separated by the configured list separator. Null values produce empty
+// fields.
+//
+// Values are formatted according to the logical type of their column, for
+// example timestamps are written as RFC 3339 strings and decimals are scaled
+// using the precision declared in the schema.
+//
+// CSVWriter implements the RowWriterWithSchema interface, which makes it
+// possible to use it as destination of a call to CopyRows to export the
+// content of parquet files:
+//
+//	rowGroup := file.RowGroup(0)
+//	w := parquet.NewCSVWriter(os.Stdout, rowGroup.Schema())
+//	if _, err := parquet.CopyRows(w, rowGroup.Rows()); err != nil {
+//		...
+//	}
+//	if err := w.Flush(); err != nil {
+//		...
+//	}
+//
+type CSVWriter struct {
+	output  *csv.Writer
+	config  *CSVConfig
+	schema  *Schema
+	columns []csvColumn
+	record  []string
+	fields  [][]byte
+	counts  []int
+	values  []Value
+	started bool
+}
+
+type csvColumn struct {
+	name string
+	typ  Type
+}
+
+// NewCSVWriter constructs a CSV writer which writes records to output, using
+// the schema to determine the name and type of columns.
+//
+// The function panics if the configuration options are invalid.
+func NewCSVWriter(output io.Writer, schema *Schema, options ...CSVOption) *CSVWriter {
+	config, err := NewCSVConfig(options...)
+	if err != nil {
+		panic(err)
+	}
+
+	w := &CSVWriter{
+		output: csv.NewWriter(output),
+		config: config,
+		schema: schema,
+	}
+	w.output.Comma = config.Comma
+
+	forEachLeafColumnOf(schema, func(leaf leafColumn) {
+		w.columns = append(w.columns, csvColumn{
+			name: strings.Join(leaf.path, config.PathSeparator),
+			typ:  leaf.node.Type(),
+		})
+	})
+
+	w.record = make([]string, len(w.columns))
+	w.fields = make([][]byte, len(w.columns))
+	w.counts = make([]int, len(w.columns))
+	return w
+}
+
+// Schema returns the schema of rows written to w.
+func (w *CSVWriter) Schema() *Schema { return w.schema }
+
+// Write deconstructs the Go value passed as argument into a row and writes it
+// as a CSV record.
+func (w *CSVWriter) Write(row interface{}) error {
+	w.values = w.schema.Deconstruct(w.values[:0], row)
+	return w.WriteRow(w.values)
+}
+
+// WriteRow writes a parquet row as a CSV record.
+func (w *CSVWriter) WriteRow(row Row) error {
+	if !w.started {
+		w.started = true
+
+		if !w.config.SkipHeader {
+			for i, c := range w.columns {
+				w.record[i] = c.name
+			}
+			if err := w.output.Write(w.record); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := range w.fields {
+		w.fields[i] = w.fields[i][:0]
+		w.counts[i] = 0
+	}
+
+	for _, v := range row {
+		columnIndex := v.Column()
+		if columnIndex < 0 || columnIndex >= len(w.columns) {
+			return fmt.Errorf("cannot write value of column %d to CSV record with %d fields", columnIndex, len(w.columns))
+		}
+		if v.IsNull() {
+			continue
+		}
+		field := w.fields[columnIndex]
+		if w.counts[columnIndex] != 0 {
+			field = append(field, w.config.ListSeparator...)
+		}
+		w.fields[columnIndex] = appendValueText(field, w.columns[columnIndex].typ, v)
+		w.counts[columnIndex]++
+	}
+
+	for i, field := range w.fields {
+		w.record[i] = string(field)
+	}
+
+	return w.output.Write(w.record)
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (w *CSVWriter) Flush() error {
+	w.output.Flush()
+	return w.output.Error()
+}
+
+// CSVParseFunc is the signature of functions used to parse CSV fields into
+// parquet values.
+//
+// The returned value must be of the kind expected by the column that the
+// function is installed on, its levels and column index are set by the CSV
+// reader.
+type CSVParseFunc func(field string) (Value, error)
+
+// CSVReader is a row reader which parses CSV records into parquet rows.
+//
+// Unless the header was disabled in the configuration, the first record of
+// the input is expected to hold the names of columns, which are matched with
+// the leaf columns of the schema (joining the column path with the configured
+// path separator). When the header is skipped, the fields of each record are
+// assigned to the leaf columns of the schema in order.
+//
+// Empty fields produce null values on columns that have optional or repeated
+// levels. Fields of repeated columns are split on the configured list
+// separator, producing one value per element.
+//
+// Columns may be nested in required groups, but not in optional or repeated
+// groups since the fields of a CSV record cannot express whether the group or
+// only the column was null, nor which element of a repeated group a value is
+// part of.
+//
+// CSVReader implements the RowReaderWithSchema interface, which makes it
+// possible to use it as source of a call to CopyRows to write CSV data to a
+// parquet file without going through intermediary Go values:
+//
+//	r := parquet.NewCSVReader(input, schema)
+//	w := parquet.NewWriter(output, schema)
+//	if _, err := parquet.CopyRows(w, r); err != nil {
+//		...
+//	}
+//	if err := w.Close(); err != nil {
+//		...
+//	}
+//
+type CSVReader struct {
+	input   *csv.Reader
+	config  *CSVConfig
+	schema  *Schema
+	columns []csvReaderColumn
+	started bool
+	line    int64
+}
+
+type csvReaderColumn struct {
+	name               string
+	parse              CSVParseFunc
+	field              int
+	maxRepetitionLevel int16
+	maxDefinitionLevel int16
+	columnIndex        int16
+}
+
+// NewCSVReader constructs a CSV reader which parses records from input into
+// rows of the given schema.
+//
+// The function panics if the configuration options are invalid, or if the
+// schema has columns nested in optional or repeated groups.
+func NewCSVReader(input io.Reader, schema *Schema, options ...CSVOption) *CSVReader {
+	config, err := NewCSVConfig(options...)
+	if err != nil {
+		panic(err)
+	}
+
+	r := &CSVReader{
+		input:  csv.NewReader(input),
+		config: config,
+		schema: schema,
+	}
+	r.input.Comma = config.Comma
+	r.input.FieldsPerRecord = -1
+
+	forEachLeafColumnOf(schema, func(leaf leafColumn) {
+		name := strings.Join(leaf.path, config.PathSeparator)
+		// Columns which are only nested in required groups have at most one
+		// definition level, for being optional or repeated themselves.
+		maxDefinitionLevel := int16(0)
+		if leaf.node.Optional() || leaf.node.Repeated() {
+			maxDefinitionLevel = 1
+		}
+		if leaf.maxDefinitionLevel != maxDefinitionLevel {
+			panic(fmt.Sprintf("cannot read CSV records into parquet column %q nested in an optional or repeated group", name))
+		}
+		parse := config.ColumnParsers[name]
+		if parse == nil {
+			columnType := leaf.node.Type()
+			parse = func(field string) (Value, error) { return parseValueText(columnType, field) }
+		}
+		r.columns = append(r.columns, csvReaderColumn{
+			name:               name,
+			parse:              parse,
+			field:              len(r.columns),
+			maxRepetitionLevel: leaf.maxRepetitionLevel,
+			maxDefinitionLevel: leaf.maxDefinitionLevel,
+			columnIndex:        leaf.columnIndex,
+		})
+	})
+
+	return r
+}
+
+// Schema returns the schema of rows read from r.
+func (r *CSVReader) Schema() *Schema { return r.schema }
+
+// ReadRow reads the next CSV record and appends its values to row.
+//
+// The method returns io.EOF when there are no more records to read.
+func (r *CSVReader) ReadRow(row Row) (Row, error) {
+	if !r.started {
+		r.started = true
+
+		if !r.config.SkipHeader {
+			header, err := r.read()
+			if err != nil {
+				return row, err
+			}
+			if err := r.mapHeader(header); err != nil {
+				return row, err
+			}
+		}
+	}
+
+	record, err := r.read()
+	if err != nil {
+		return row, err
+	}
+
+	for i := range r.columns {
+		c := &r.columns[i]
+		field := ""
+		if c.field >= 0 && c.field < len(record) {
+			field = record[c.field]
+		}
+
+		if field == "" && c.maxDefinitionLevel > 0 {
+			row = append(row, Value{
+				columnIndex: ^c.columnIndex,
+			})
+			continue
+		}
+
+		if c.maxRepetitionLevel == 0 {
+			v, err := c.parse(field)
+			if err != nil {
+				return row, fmt.Errorf("line %d: column %q: %w", r.line, c.name, err)
+			}
+			v.definitionLevel = c.maxDefinitionLevel
+			v.columnIndex = ^c.columnIndex
+			row = append(row, v)
+			continue
+		}
+
+		var repetitionLevel int16
+		for _, elem := range strings.Split(field, r.config.ListSeparator) {
+			v, err := c.parse(elem)
+			if err != nil {
+				return row, fmt.Errorf("line %d: column %q: %w", r.line, c.name, err)
+			}
+			v.repetitionLevel = repetitionLevel
+			v.definitionLevel = c.maxDefinitionLevel
+			v.columnIndex = ^c.columnIndex
+			row = append(row, v)
+			repetitionLevel = c.maxRepetitionLevel
+		}
+	}
+
+	return row, nil
+}
+
+func (r *CSVReader) read() ([]string, error) {
+	record, err := r.input.Read()
+	if err == nil {
+		r.line++
+	}
+	return record, err
+}
+
+func (r *CSVReader) mapHeader(header []string) error {
+	fields := make(map[string]int, len(header))
+	for i, name := range header {
+		fields[name] = i
+	}
+
+	for i := range r.columns {
+		c := &r.columns[i]
+		field, ok := fields[c.name]
+		if !ok {
+			if c.maxDefinitionLevel == 0 {
+				return fmt.Errorf("CSV header is missing required column %q", c.name)
+			}
+			field = -1
+		}
+		c.field = field
+	}
+
+	return nil
+}
+
+var (
+	_ RowWriterWithSchema = (*CSVWriter)(nil)
+	_ RowReaderWithSchema = (*CSVReader)(nil)
+)
@@ -0,0 +1,208 @@
+package parquet_test
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestCSVWriter(t *testing.T) {
+	type address struct {
+		City string `parquet:"city"`
+		Zip  *int32 `parquet:"zip,optional"`
+	}
+
+	type person struct {
+		Name    string   `parquet:"name"`
+		Age     int32    `parquet:"age"`
+		Score   int64    `parquet:"score,decimal(2:10)"`
+		Born    int32    `parquet:"born,date"`
+		Seen    int64    `parquet:"seen,timestamp"`
+		Tags    []string `parquet:"tags"`
+		Address address  `parquet:"address"`
+	}
+
+	zip := int32(94110)
+	rows := []person{
+		{
+			Name:    "Luke",
+			Age:     42,
+			Score:   -1234,
+			Born:    10,
+			Seen:    time.Date(2022, 4, 1, 12, 30, 0, 0, time.UTC).UnixMilli(),
+			Tags:    []string{"a", "b"},
+			Address: address{City: "SF", Zip: &zip},
+		},
+		{
+			Name:  "Han, Solo",
+			Age:   7,
+			Score: 5,
+			Seen:  0,
+		},
+	}
+
+	schema := parquet.SchemaOf(rows[0])
+	output := new(strings.Builder)
+	writer := parquet.NewCSVWriter(output, schema, parquet.CSVPathSeparator("/"))
+
+	for _, row := range rows {
+		if err := writer.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `address/city,address/zip,age,born,name,score,seen,tags
+SF,94110,42,1970-01-11,Luke,-12.34,2022-04-01T12:30:00Z,a;b
+,,7,1970-01-01,"Han, Solo",0.05,1970-01-01T00:00:00Z,
+`
+	if got := output.String(); got != want {
+		t.Errorf("wrong CSV output:\nwant:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	type event struct {
+		ID     int64    `parquet:"id"`
+		Name   string   `parquet:"name"`
+		Amount int32    `parquet:"amount,decimal(2:9)"`
+		Day    int32    `parquet:"day,date"`
+		Valid  bool     `parquet:"valid"`
+		Score  *int32   `parquet:"score,optional"`
+		Tags   []string `parquet:"tags"`
+	}
+
+	const input = `name,id,day,amount,valid,score,tags,extra
+hello,1,2022-04-01,-12.5,yes,10,a;b;c,ignored
+"world, again",2,1970-01-02,0.01,no,,,
+`
+
+	parseYesNo := func(field string) (parquet.Value, error) {
+		switch field {
+		case "yes":
+			return parquet.ValueOf(true), nil
+		case "no":
+			return parquet.ValueOf(false), nil
+		default:
+			return parquet.Value{}, errors.New("invalid boolean")
+		}
+	}
+
+	schema := parquet.SchemaOf(event{})
+	reader := parquet.NewCSVReader(strings.NewReader(input), schema,
+		parquet.CSVColumnParser("valid", parseYesNo),
+	)
+	buffer := parquet.NewBuffer(schema)
+
+	if _, err := parquet.CopyRows(buffer, reader); err != nil {
+		t.Fatal(err)
+	}
+
+	score := int32(10)
+	want := []event{
+		{ID: 1, Name: "hello", Amount: -1250, Day: 19083, Valid: true, Score: &score, Tags: []string{"a", "b", "c"}},
+		{ID: 2, Name: "world, again", Amount: 1, Day: 1, Valid: false, Tags: []string{}},
+	}
+
+	rows := buffer.Rows()
+	for i := range want {
+		row, err := rows.ReadRow(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := event{}
+		if err := schema.Reconstruct(&got, row); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("row %d mismatch:\nwant: %+v\ngot:  %+v", i, want[i], got)
+		}
+	}
+
+	if _, err := rows.ReadRow(nil); err != io.EOF {
+		t.Errorf("expected io.EOF after the last row but got %v", err)
+	}
+}
+
+func TestCSVReaderMissingRequiredColumn(t *testing.T) {
+	type row struct {
+		ID int64 `parquet:"id"`
+	}
+
+	reader := parquet.NewCSVReader(strings.NewReader("name\nhello\n"), parquet.SchemaOf(row{}))
+
+	if _, err := reader.ReadRow(nil); err == nil {
+		t.Error("expected an error when the CSV header is missing a required column")
+	}
+}
+
+func TestCSVReaderNestedColumns(t *testing.T) {
+	type address struct {
+		City string `parquet:"city"`
+		Zip  *int32 `parquet:"zip,optional"`
+	}
+
+	t.Run("required group", func(t *testing.T) {
+		type person struct {
+			Name    string  `parquet:"name"`
+			Address address `parquet:"address"`
+		}
+
+		schema := parquet.SchemaOf(person{})
+		reader := parquet.NewCSVReader(strings.NewReader("name,address.city,address.zip\nLuke,SF,\n"), schema,
+			parquet.CSVPathSeparator("."),
+		)
+
+		row, err := reader.ReadRow(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := person{}
+		if err := schema.Reconstruct(&got, row); err != nil {
+			t.Fatal(err)
+		}
+		if want := (person{Name: "Luke", Address: address{City: "SF"}}); !reflect.DeepEqual(got, want) {
+			t.Errorf("wrong row:\nwant: %+v\ngot:  %+v", want, got)
+		}
+	})
+
+	for _, test := range []struct {
+		scenario string
+		model    interface{}
+	}{
+		{
+			scenario: "optional group",
+			model: struct {
+				Address *address `parquet:"address,optional"`
+			}{},
+		},
+		{
+			scenario: "repeated group",
+			model: struct {
+				Addresses []address `parquet:"addresses"`
+			}{},
+		},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("creating a CSV reader for columns nested in an optional or repeated group did not panic")
+				}
+			}()
+			parquet.NewCSVReader(strings.NewReader(""), parquet.SchemaOf(test.model))
+		})
+	}
+}
+
+func TestCSVConfigValidate(t *testing.T) {
+	if _, err := parquet.NewCSVConfig(parquet.CSVComma('"')); err == nil {
+		t.Error("expected an error when using a double quote as CSV delimiter")
+	}
+}
@@ -0,0 +1,73 @@
+package parquet
+
+import (
+	"reflect"
+	"time"
+)
+
+// CivilDate represents a calendar date, independently of any time zone.
+//
+// Struct fields of type CivilDate are written to columns of DATE logical type,
+// which is also the case of time.Time fields tagged with the date option:
+//
+//	type Order struct {
+//		Placed  parquet.CivilDate `parquet:"placed"`
+//		Shipped time.Time         `parquet:"shipped,date"`
+//	}
+//
+// time.Time values read from DATE columns are set to midnight in the UTC
+// location.
+type CivilDate struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// CivilDateOf returns the date on which t occurs, in the location of t.
+func CivilDateOf(t time.Time) CivilDate {
+	y, m, d := t.Date()
+	return CivilDate{Year: y, Month: m, Day: d}
+}
+
+// In returns the time at midnight of the date d in the given location.
+func (d CivilDate) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// String returns d formatted in the YYYY-MM-DD format.
+func (d CivilDate) String() string {
+	return d.In(time.UTC).Format("2006-01-02")
+}
+
+const secondsPerDay = 24 * 3600
+
+func (d CivilDate) daysSinceEpoch() int32 {
+	return int32(d.In(time.UTC).Unix() / secondsPerDay)
+}
+
+func civilDateOfDays(days int32) CivilDate {
+	return CivilDateOf(time.Unix(int64(days)*secondsPerDay, 0).UTC())
+}
+
+var civilDateType = reflect.TypeOf(CivilDate{})
+
+// dateValueOf returns the value of a DATE column representing the time.Time or
+// CivilDate held in value.
+func dateValueOf(value reflect.Value) Value {
+	d, ok := value.Interface().(CivilDate)
+	if !ok {
+		d = CivilDateOf(value.Interface().(time.Time))
+	}
+	return makeValueInt32(d.daysSinceEpoch())
+}
+
+// assignDateValue sets the time.Time or CivilDate held in dst to the date that
+// v represents in a DATE column.
+func assignDateValue(dst reflect.Value, v Value) {
+	d := civilDateOfDays(v.Int32())
+	if dst.Type() == civilDateType {
+		dst.Set(reflect.ValueOf(d))
+	} else {
+		dst.Set(reflect.ValueOf(d.In(time.UTC)))
+	}
+}
@@ -0,0 +1,147 @@
+package parquet
+
+import "fmt"
+
+// DerivedColumn is an interface representing columns computed from the rows
+// written to parquet files, configured on writers with the DerivedColumns
+// option.
+//
+// Derived columns are appended to the schema of the rows written to a writer
+// to produce the schema of the file; a typical use case is adding a partition
+// column (e.g. a date) computed from a timestamp field of the rows.
+type DerivedColumn interface {
+	// Returns the name of the derived column, which must be unique in the
+	// schema of the file.
+	Name() string
+
+	// Returns the parquet node of the derived column, which must be an
+	// optional or required leaf node.
+	Node() Node
+
+	// Computes the value of the derived column for a row of the given schema.
+	// The levels and column index of the returned value are set by the writer.
+	//
+	// A null value may only be returned if the node of the derived column is
+	// optional.
+	Derive(schema *Schema, row Row) (Value, error)
+}
+
+// Derive constructs a derived column named name, with values of the parquet
+// type of node computed by calling derive on the rows written to the file.
+func Derive(name string, node Node, derive func(Row) Value) DerivedColumn {
+	return &derivedColumn{name: name, node: node, derive: derive}
+}
+
+type derivedColumn struct {
+	name   string
+	node   Node
+	derive func(Row) Value
+}
+
+func (c *derivedColumn) Name() string { return c.name }
+
+func (c *derivedColumn) Node() Node { return c.node }
+
+func (c *derivedColumn) Derive(_ *Schema, row Row) (Value, error) { return c.derive(row), nil }
+
+// deriveSchema returns the schema of files produced from rows of the given
+// schema with the derived columns appended to it.
+func deriveSchema(schema *Schema, columns []DerivedColumn) (*Schema, error) {
+	group := make(Group, schema.NumChildren()+len(columns))
+	for _, name := range schema.ChildNames() {
+		group[name] = schema.ChildByName(name)
+	}
+	for _, c := range columns {
+		name := c.Name()
+		if _, exists := group[name]; exists {
+			return nil, fmt.Errorf("derived column %q conflicts with a column of the same name in the parquet schema", name)
+		}
+		group[name] = c.Node()
+	}
+	return NewSchema(schema.Name(), group), nil
+}
+
+// derivedColumnWriter is a row writer which computes the values of derived
+// columns and writes the rows with the derived values to a parquet writer.
+type derivedColumnWriter struct {
+	writer  RowWriter
+	schema  *Schema
+	conv    Conversion
+	columns []derivedColumnState
+	row     Row
+}
+
+type derivedColumnState struct {
+	column             DerivedColumn
+	columnIndex        int16
+	maxDefinitionLevel int16
+}
+
+func newDerivedColumnWriter(writer RowWriter, schema, fileSchema *Schema, columns []DerivedColumn) (*derivedColumnWriter, error) {
+	conv, err := Convert(fileSchema, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &derivedColumnWriter{
+		writer:  writer,
+		schema:  schema,
+		conv:    conv,
+		columns: make([]derivedColumnState, len(columns)),
+	}
+
+	leaves := make(map[string]leafColumn, len(columns))
+	forEachLeafColumnOf(fileSchema, func(leaf leafColumn) {
+		leaves[leaf.path[0]] = leaf
+	})
+
+	for i, c := range columns {
+		leaf := leaves[c.Name()]
+		w.columns[i] = derivedColumnState{
+			column:             c,
+			columnIndex:        leaf.columnIndex,
+			maxDefinitionLevel: leaf.maxDefinitionLevel,
+		}
+	}
+	return w, nil
+}
+
+func (w *derivedColumnWriter) WriteRow(row Row) error {
+	defer func() {
+		clearValues(w.row)
+	}()
+
+	var err error
+	// The conversion produces a placeholder value for each derived column,
+	// which is then replaced by the derived value.
+	if w.row, err = w.conv.Convert(w.row[:0], row); err != nil {
+		return err
+	}
+
+	for i := range w.columns {
+		c := &w.columns[i]
+		v, err := c.column.Derive(w.schema, row)
+		if err != nil {
+			return fmt.Errorf("computing derived column %q: %w", c.column.Name(), err)
+		}
+		if v.IsNull() {
+			if c.maxDefinitionLevel == 0 {
+				return fmt.Errorf("computing derived column %q: null value in required column", c.column.Name())
+			}
+			v = Value{}
+		} else {
+			v.repetitionLevel = 0
+			v.definitionLevel = c.maxDefinitionLevel
+		}
+		v.columnIndex = ^c.columnIndex
+
+		for j := range w.row {
+			if w.row[j].Column() == int(c.columnIndex) {
+				w.row[j] = v
+				break
+			}
+		}
+	}
+
+	return w.writer.WriteRow(w.row)
+}
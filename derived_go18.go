@@ -0,0 +1,31 @@
+//go:build go1.18
+
+package parquet
+
+// DeriveFrom is like Derive but the function computing the values of the
+// derived column receives the rows reconstructed into Go values of type T,
+// which must be a struct type compatible with the schema of the writer.
+//
+// Reconstructing the rows has a cost comparable to the one of writing them,
+// programs with strict performance requirements should prefer using Derive.
+func DeriveFrom[T any](name string, node Node, derive func(*T) Value) DerivedColumn {
+	return &genericDerivedColumn[T]{name: name, node: node, derive: derive}
+}
+
+type genericDerivedColumn[T any] struct {
+	name   string
+	node   Node
+	derive func(*T) Value
+}
+
+func (c *genericDerivedColumn[T]) Name() string { return c.name }
+
+func (c *genericDerivedColumn[T]) Node() Node { return c.node }
+
+func (c *genericDerivedColumn[T]) Derive(schema *Schema, row Row) (Value, error) {
+	value := new(T)
+	if err := schema.Reconstruct(value, row); err != nil {
+		return Value{}, err
+	}
+	return c.derive(value), nil
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 
 	"github.com/segmentio/parquet-go/encoding"
+	"github.com/segmentio/parquet-go/encoding/plain"
 	"github.com/segmentio/parquet-go/internal/bits"
 )
 
@@ -57,6 +58,30 @@ type Dictionary interface {
 	Page() BufferedPage
 }
 
+// ColumnDictionaryLimit configures the limits of the dictionary of a column on
+// parquet writers. Zero values mean that the dictionary is not limited.
+type ColumnDictionaryLimit struct {
+	// The path of the leaf column that the limits apply to.
+	Path []string
+
+	// The maximum size of the dictionary page, in bytes.
+	MaxSize int
+
+	// The maximum number of distinct values in the dictionary.
+	MaxValues int
+}
+
+// searchDictionaryLimit returns the dictionary limits configured for the column
+// at the given path, or the default limits if there were none.
+func searchDictionaryLimit(limits []ColumnDictionaryLimit, path columnPath, maxSize, maxValues int) (int, int) {
+	for i := len(limits) - 1; i >= 0; i-- {
+		if path.equal(limits[i].Path) {
+			return limits[i].MaxSize, limits[i].MaxValues
+		}
+	}
+	return maxSize, maxValues
+}
+
 func dictCap(bufferSize, valueItemSize int) int {
 	indexItemSize := 4 + valueItemSize + mapSizeOverheadPerItem
 	return atLeastOne(bufferSize / (valueItemSize + indexItemSize))
@@ -66,6 +91,10 @@ type byteArrayDictionary struct {
 	byteArrayPage
 	typ   Type
 	index map[string]int32
+	// When set, the memory holding the dictionary values is never overwritten
+	// after being exposed, which allows values to be converted to Go strings
+	// without being copied.
+	interned bool
 }
 
 func newByteArrayDictionary(typ Type, columnIndex int16, bufferSize int) *byteArrayDictionary {
@@ -85,6 +114,11 @@ func readByteArrayDictionary(typ Type, columnIndex int16, numValues int, decoder
 			values:      encoding.MakeByteArrayList(atLeastOne(numValues)),
 			columnIndex: columnIndex,
 		},
+		// Dictionaries read from parquet files are shared by all the pages of
+		// a column chunk, reconstructing string values from the dictionary
+		// memory means that a single Go string is used for each distinct value
+		// instead of allocating one for each row.
+		interned: true,
 	}
 
 	for {
@@ -106,12 +140,21 @@ func (d *byteArrayDictionary) Type() Type { return newIndexedType(d.typ, d) }
 func (d *byteArrayDictionary) Len() int { return d.values.Len() }
 
 func (d *byteArrayDictionary) Index(i int32) Value {
-	return makeValueBytes(ByteArray, d.values.Index(int(i)))
+	v := makeValueBytes(ByteArray, d.values.Index(int(i)))
+	v.interned = d.interned
+	return v
 }
 
 func (d *byteArrayDictionary) Insert(indexes []int32, values []Value) {
 	_ = indexes[:len(values)]
+	d.initIndex()
+
+	for i, v := range values {
+		indexes[i] = d.insert(v.ByteArray())
+	}
+}
 
+func (d *byteArrayDictionary) initIndex() {
 	if d.index == nil {
 		index := int32(0)
 		d.index = make(map[string]int32, d.values.Cap())
@@ -121,20 +164,17 @@ func (d *byteArrayDictionary) Insert(indexes []int32, values []Value) {
 			return true
 		})
 	}
+}
 
-	for i, v := range values {
-		value := v.ByteArray()
-
-		index, exists := d.index[string(value)]
-		if !exists {
-			d.values.Push(value)
-			index = int32(d.values.Len() - 1)
-			stringValue := bits.BytesToString(d.values.Index(int(index)))
-			d.index[stringValue] = index
-		}
-
-		indexes[i] = index
+func (d *byteArrayDictionary) insert(value []byte) int32 {
+	index, exists := d.index[string(value)]
+	if !exists {
+		d.values.Push(value)
+		index = int32(d.values.Len() - 1)
+		stringValue := bits.BytesToString(d.values.Index(int(index)))
+		d.index[stringValue] = index
 	}
+	return index
 }
 
 func (d *byteArrayDictionary) Lookup(indexes []int32, values []Value) {
@@ -165,7 +205,13 @@ func (d *byteArrayDictionary) Bounds(indexes []int32) (min, max Value) {
 }
 
 func (d *byteArrayDictionary) Reset() {
-	d.values.Reset()
+	if d.interned {
+		// Values of the dictionary may still be referenced by Go strings, the
+		// memory must not be reused.
+		d.values = encoding.MakeByteArrayList(d.values.Cap())
+	} else {
+		d.values.Reset()
+	}
 	d.index = nil
 }
 
@@ -309,7 +355,11 @@ func newIndexedType(typ Type, dict Dictionary) *indexedType {
 }
 
 func (t *indexedType) NewColumnBuffer(columnIndex, bufferSize int) ColumnBuffer {
-	return newIndexedColumnBuffer(t.dict, t, makeColumnIndex(columnIndex), bufferSize)
+	col := newIndexedColumnBuffer(t.dict, t, makeColumnIndex(columnIndex), bufferSize)
+	if d, ok := t.dict.(typedDictionary); ok {
+		return d.newIndexedColumnBuffer(col)
+	}
+	return col
 }
 
 func (t *indexedType) NewColumnReader(columnIndex, bufferSize int) ColumnReader {
@@ -357,15 +407,21 @@ func (page *indexedPage) Slice(i, j int64) BufferedPage {
 
 func (page *indexedPage) Size() int64 { return sizeOfInt32(page.values) }
 
-func (page *indexedPage) RepetitionLevels() []int8 { return nil }
+func (page *indexedPage) RepetitionLevels() []int16 { return nil }
 
-func (page *indexedPage) DefinitionLevels() []int8 { return nil }
+func (page *indexedPage) DefinitionLevels() []int16 { return nil }
 
 func (page *indexedPage) WriteTo(e encoding.Encoder) error {
 	return e.EncodeInt32(page.values)
 }
 
-func (page *indexedPage) Values() ValueReader { return &indexedPageReader{page: page} }
+func (page *indexedPage) Values() ValueReader {
+	r := &indexedPageReader{page: page}
+	if d, ok := page.dict.(typedDictionary); ok {
+		return d.newIndexedPageReader(r)
+	}
+	return r
+}
 
 func (page *indexedPage) Buffer() BufferedPage { return page }
 
@@ -390,6 +446,19 @@ func (r *indexedPageReader) ReadValues(values []Value) (n int, err error) {
 	return n, err
 }
 
+func (r *indexedPageReader) peekIndexes(limit int) ([]int32, error) {
+	indexes := r.page.values[r.offset:]
+	if len(indexes) == 0 {
+		return nil, io.EOF
+	}
+	if len(indexes) > limit {
+		indexes = indexes[:limit]
+	}
+	return indexes, nil
+}
+
+func (r *indexedPageReader) discardIndexes(n int) { r.offset += n }
+
 type indexedColumnBuffer struct {
 	indexedPage
 	typ Type
@@ -406,7 +475,9 @@ func newIndexedColumnBuffer(dict Dictionary, typ Type, columnIndex int16, buffer
 	}
 }
 
-func (col *indexedColumnBuffer) Clone() ColumnBuffer {
+func (col *indexedColumnBuffer) Clone() ColumnBuffer { return col.clone() }
+
+func (col *indexedColumnBuffer) clone() *indexedColumnBuffer {
 	return &indexedColumnBuffer{
 		indexedPage: indexedPage{
 			dict:        col.dict,
@@ -448,8 +519,15 @@ func (col *indexedColumnBuffer) Swap(i, j int) {
 }
 
 func (col *indexedColumnBuffer) WriteValues(values []Value) (int, error) {
+	col.dict.Insert(col.grow(len(values)), values)
+	return len(values), nil
+}
+
+// grow extends the column by n indexes, returning the slice of indexes that
+// were added.
+func (col *indexedColumnBuffer) grow(n int) []int32 {
 	i := len(col.values)
-	j := len(col.values) + len(values)
+	j := len(col.values) + n
 
 	if j <= cap(col.values) {
 		col.values = col.values[:j]
@@ -459,8 +537,7 @@ func (col *indexedColumnBuffer) WriteValues(values []Value) (int, error) {
 		col.values = colValues
 	}
 
-	col.dict.Insert(col.values[i:], values)
-	return len(values), nil
+	return col.values[i:]
 }
 
 func (col *indexedColumnBuffer) WriteRow(row Row) error {
@@ -496,13 +573,17 @@ type indexedColumnReader struct {
 	columnIndex int16
 }
 
-func newIndexedColumnReader(dict Dictionary, typ Type, columnIndex int16, bufferSize int) *indexedColumnReader {
-	return &indexedColumnReader{
+func newIndexedColumnReader(dict Dictionary, typ Type, columnIndex int16, bufferSize int) ColumnReader {
+	r := &indexedColumnReader{
 		dict:        dict,
 		typ:         typ,
 		buffer:      make([]int32, 0, atLeastOne(bufferSize)),
 		columnIndex: ^columnIndex,
 	}
+	if d, ok := dict.(typedDictionary); ok {
+		return d.newIndexedColumnReader(r)
+	}
+	return r
 }
 
 func (r *indexedColumnReader) Type() Type { return r.typ }
@@ -560,6 +641,157 @@ func (r *indexedColumnReader) Reset(decoder encoding.Decoder) {
 	r.offset = 0
 }
 
+func (r *indexedColumnReader) peekIndexes(limit int) ([]int32, error) {
+	if r.offset == len(r.buffer) {
+		buffer := r.buffer[:cap(r.buffer)]
+		n, err := r.decoder.DecodeInt32(buffer)
+		if n == 0 {
+			if err == nil {
+				err = io.ErrNoProgress
+			}
+			return nil, err
+		}
+		r.buffer = buffer[:n]
+		r.offset = 0
+	}
+	indexes := r.buffer[r.offset:]
+	if len(indexes) > limit {
+		indexes = indexes[:limit]
+	}
+	return indexes, nil
+}
+
+func (r *indexedColumnReader) discardIndexes(n int) { r.offset += n }
+
+// typedDictionary is implemented by dictionaries which allow reading and
+// writing the values of indexed pages and columns as arrays of Go values, with
+// readers and column buffers implementing interfaces like ByteArrayReader.
+type typedDictionary interface {
+	newIndexedPageReader(*indexedPageReader) ValueReader
+	newIndexedColumnReader(*indexedColumnReader) ColumnReader
+	newIndexedColumnBuffer(*indexedColumnBuffer) ColumnBuffer
+}
+
+// indexReader is the interface shared by readers of dictionary indexes, used to
+// implement the typed readers of indexed pages and columns once.
+type indexReader interface {
+	// Returns up to limit indexes without consuming them, or io.EOF when all
+	// indexes have been read.
+	peekIndexes(limit int) ([]int32, error)
+	// Consumes the first n indexes returned by the last call to peekIndexes.
+	discardIndexes(n int)
+}
+
+func errIndexOutOfBounds(index int32, dictLen int) error {
+	return fmt.Errorf("reading value from indexed page: index out of bounds: %d/%d", index, dictLen)
+}
+
+func (d *byteArrayDictionary) newIndexedPageReader(r *indexedPageReader) ValueReader {
+	return &indexedByteArrayPageReader{indexedPageReader: r, dict: d}
+}
+
+func (d *byteArrayDictionary) newIndexedColumnReader(r *indexedColumnReader) ColumnReader {
+	return &indexedByteArrayColumnReader{indexedColumnReader: r, dict: d}
+}
+
+func (d *byteArrayDictionary) newIndexedColumnBuffer(col *indexedColumnBuffer) ColumnBuffer {
+	return &indexedByteArrayColumnBuffer{indexedColumnBuffer: col, dict: d}
+}
+
+type indexedByteArrayColumnBuffer struct {
+	*indexedColumnBuffer
+	dict *byteArrayDictionary
+}
+
+func (col *indexedByteArrayColumnBuffer) Clone() ColumnBuffer {
+	return &indexedByteArrayColumnBuffer{indexedColumnBuffer: col.clone(), dict: col.dict}
+}
+
+func (col *indexedByteArrayColumnBuffer) WriteRequired(values []byte) (int, error) {
+	return col.WriteByteArrays(values)
+}
+
+func (col *indexedByteArrayColumnBuffer) WriteByteArrays(values []byte) (int, error) {
+	col.dict.initIndex()
+	n := 0
+	err := plain.RangeByteArrays(values, func(v []byte) error {
+		col.values = append(col.values, col.dict.insert(v))
+		n++
+		return nil
+	})
+	return n, err
+}
+
+type indexedByteArrayPageReader struct {
+	*indexedPageReader
+	dict *byteArrayDictionary
+}
+
+func (r *indexedByteArrayPageReader) ReadRequired(values []byte) (int, error) {
+	return r.ReadByteArrays(values)
+}
+
+func (r *indexedByteArrayPageReader) ReadByteArrays(values []byte) (int, error) {
+	n, err := readIndexedByteArrays(r.indexedPageReader, r.dict, values)
+	if err == nil && r.offset == len(r.page.values) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+type indexedByteArrayColumnReader struct {
+	*indexedColumnReader
+	dict *byteArrayDictionary
+}
+
+func (r *indexedByteArrayColumnReader) ReadRequired(values []byte) (int, error) {
+	return r.ReadByteArrays(values)
+}
+
+func (r *indexedByteArrayColumnReader) ReadByteArrays(values []byte) (int, error) {
+	return readIndexedByteArrays(r.indexedColumnReader, r.dict, values)
+}
+
+// readIndexedByteArrays writes the dictionary values of the indexes read from r
+// to values, using the PLAIN encoding. The semantics are the same as the
+// ReadByteArrays method of parquet.ByteArrayReader.
+func readIndexedByteArrays(r indexReader, dict *byteArrayDictionary, values []byte) (c int, err error) {
+	n, dictLen := 0, dict.values.Len()
+	for {
+		limit := (len(values) - n) / plain.ByteArrayLengthSize
+		if limit == 0 {
+			limit = 1
+		}
+		indexes, err := r.peekIndexes(limit)
+		if err != nil {
+			return c, err
+		}
+		i := 0
+		for _, index := range indexes {
+			if index < 0 || int(index) >= dictLen {
+				r.discardIndexes(i)
+				return c, errIndexOutOfBounds(index, dictLen)
+			}
+			b := dict.values.Index(int(index))
+			if plain.ByteArrayLengthSize+len(b) > len(values)-n {
+				break
+			}
+			plain.PutByteArrayLength(values[n:], len(b))
+			n += plain.ByteArrayLengthSize
+			n += copy(values[n:], b)
+			i++
+		}
+		r.discardIndexes(i)
+		c += i
+		if i < len(indexes) {
+			if c == 0 && len(values) > 0 {
+				err = io.ErrShortBuffer
+			}
+			return c, err
+		}
+	}
+}
+
 type indexedColumnIndex struct{ col *indexedColumnBuffer }
 
 func (index indexedColumnIndex) NumPages() int       { return 1 }
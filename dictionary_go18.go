@@ -69,26 +69,39 @@ func (d *dictionary[T]) Index(index int32) Value {
 
 func (d *dictionary[T]) Insert(indexes []int32, values []Value) {
 	_ = indexes[:len(values)]
+	d.initIndex()
 
+	for i, v := range values {
+		indexes[i] = d.insert(d.class.value(v))
+	}
+}
+
+func (d *dictionary[T]) insertRequired(indexes []int32, values []T) {
+	_ = indexes[:len(values)]
+	d.initIndex()
+
+	for i, v := range values {
+		indexes[i] = d.insert(v)
+	}
+}
+
+func (d *dictionary[T]) initIndex() {
 	if d.index == nil {
 		d.index = make(map[T]int32, cap(d.values))
 		for i, v := range d.values {
 			d.index[v] = int32(i)
 		}
 	}
+}
 
-	for i, v := range values {
-		value := d.class.value(v)
-
-		index, exists := d.index[value]
-		if !exists {
-			index = int32(len(d.values))
-			d.values = append(d.values, value)
-			d.index[value] = index
-		}
-
-		indexes[i] = index
+func (d *dictionary[T]) insert(value T) int32 {
+	index, exists := d.index[value]
+	if !exists {
+		index = int32(len(d.values))
+		d.values = append(d.values, value)
+		d.index[value] = index
 	}
+	return index
 }
 
 func (d *dictionary[T]) Lookup(indexes []int32, values []Value) {
@@ -128,3 +141,72 @@ func (d *dictionary[T]) Reset() {
 func (d *dictionary[T]) Page() BufferedPage {
 	return &d.page
 }
+
+func (d *dictionary[T]) newIndexedPageReader(r *indexedPageReader) ValueReader {
+	return &indexedPageReaderOf[T]{indexedPageReader: r, dict: d}
+}
+
+func (d *dictionary[T]) newIndexedColumnReader(r *indexedColumnReader) ColumnReader {
+	return &indexedColumnReaderOf[T]{indexedColumnReader: r, dict: d}
+}
+
+func (d *dictionary[T]) newIndexedColumnBuffer(col *indexedColumnBuffer) ColumnBuffer {
+	return &indexedColumnBufferOf[T]{indexedColumnBuffer: col, dict: d}
+}
+
+type indexedColumnBufferOf[T primitive] struct {
+	*indexedColumnBuffer
+	dict *dictionary[T]
+}
+
+func (col *indexedColumnBufferOf[T]) Clone() ColumnBuffer {
+	return &indexedColumnBufferOf[T]{indexedColumnBuffer: col.clone(), dict: col.dict}
+}
+
+func (col *indexedColumnBufferOf[T]) WriteRequired(values []T) (int, error) {
+	col.dict.insertRequired(col.grow(len(values)), values)
+	return len(values), nil
+}
+
+type indexedPageReaderOf[T primitive] struct {
+	*indexedPageReader
+	dict *dictionary[T]
+}
+
+func (r *indexedPageReaderOf[T]) ReadRequired(values []T) (int, error) {
+	n, err := readIndexed(r.indexedPageReader, r.dict.values, values)
+	if err == nil && r.offset == len(r.page.values) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+type indexedColumnReaderOf[T primitive] struct {
+	*indexedColumnReader
+	dict *dictionary[T]
+}
+
+func (r *indexedColumnReaderOf[T]) ReadRequired(values []T) (int, error) {
+	return readIndexed(r.indexedColumnReader, r.dict.values, values)
+}
+
+// readIndexed writes the dictionary values of the indexes read from r to
+// values, returning io.EOF when all indexes have been read.
+func readIndexed[T primitive](r indexReader, dict, values []T) (n int, err error) {
+	for n < len(values) {
+		indexes, err := r.peekIndexes(len(values) - n)
+		if err != nil {
+			return n, err
+		}
+		for i, index := range indexes {
+			if index < 0 || int(index) >= len(dict) {
+				r.discardIndexes(i)
+				return n + i, errIndexOutOfBounds(index, len(dict))
+			}
+			values[n+i] = dict[index]
+		}
+		r.discardIndexes(len(indexes))
+		n += len(indexes)
+	}
+	return n, nil
+}
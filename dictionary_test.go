@@ -1,7 +1,10 @@
 package parquet_test
 
 import (
+	"bytes"
+	"fmt"
 	"math/rand"
+	"sort"
 	"testing"
 
 	"github.com/segmentio/parquet-go"
@@ -47,3 +50,82 @@ func randDictionaryPage(typ parquet.Type, values []parquet.Value) parquet.Buffer
 	buf.WriteValues(values)
 	return buf.Page()
 }
+
+func TestColumnChunkDictionary(t *testing.T) {
+	type Row struct {
+		Name  string `parquet:"name,dict"`
+		Value int64  `parquet:"value"`
+	}
+
+	const numRows = 1000
+	const numNames = 10
+
+	rows := make([]Row, numRows)
+	for i := range rows {
+		rows[i] = Row{Name: fmt.Sprintf("name-%d", i%numNames), Value: int64(i)}
+	}
+
+	buffer := parquet.NewBuffer()
+	for i := range rows {
+		if err := buffer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output := new(bytes.Buffer)
+	writer := parquet.NewWriter(output)
+	for i := range rows {
+		if err := writer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := parquet.OpenFile(bytes.NewReader(output.Bytes()), int64(output.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		scenario string
+		rowGroup parquet.RowGroup
+	}{
+		{scenario: "buffer", rowGroup: buffer},
+		{scenario: "file", rowGroup: f.RowGroup(0)},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			mapping := parquet.ColumnMappingOf(test.rowGroup.Schema())
+
+			dict, err := parquet.ColumnChunkDictionary(test.rowGroup.Column(mapping.ColumnIndex("name")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if dict == nil {
+				t.Fatal("dictionary encoded column has no dictionary")
+			}
+			if dict.Len() != numNames {
+				t.Fatalf("wrong number of values in dictionary: want=%d got=%d", numNames, dict.Len())
+			}
+
+			names := make([]string, dict.Len())
+			for i := range names {
+				names[i] = dict.Index(int32(i)).String()
+			}
+			sort.Strings(names)
+			for i, name := range names {
+				if want := fmt.Sprintf("name-%d", i); name != want {
+					t.Errorf("wrong dictionary value at index %d: want=%q got=%q", i, want, name)
+				}
+			}
+
+			dict, err = parquet.ColumnChunkDictionary(test.rowGroup.Column(mapping.ColumnIndex("value")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if dict != nil {
+				t.Errorf("column which is not dictionary encoded has a dictionary of %d values", dict.Len())
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package parquet
 
 import (
+	"fmt"
 	"sort"
+	"sync"
 
 	"github.com/segmentio/parquet-go/encoding"
 	"github.com/segmentio/parquet-go/encoding/bytestreamsplit"
@@ -51,10 +53,125 @@ var (
 		format.DeltaByteArray:       &DeltaByteArray,
 		format.ByteStreamSplit:      &ByteStreamSplit,
 	}
+
+	// Encodings registered by applications with RegisterEncoding, guarded by
+	// the mutex since lookups may happen concurrently with registrations.
+	registeredEncodingsMutex sync.RWMutex
+	registeredEncodings      map[format.Encoding]encoding.Encoding
 )
 
+// RegisterEncoding registers an encoding for the format.Encoding code returned
+// by its Encoding method, which is not supported by this package. Registered
+// encodings are returned by LookupEncoding, so the pages of parquet files that
+// use them can be read. Writers may use them like other encodings, by passing
+// them to Encoded or ColumnEncoding for example.
+//
+// The function is intended to support proprietary encodings, or encodings of
+// the parquet specification which are not yet implemented by the package. It
+// returns an error if the code is already supported by the package, or was
+// registered previously.
+func RegisterEncoding(e encoding.Encoding) error {
+	code := e.Encoding()
+	if code < 0 {
+		return fmt.Errorf("cannot register %s for invalid parquet encoding code %d", e, code)
+	}
+	if int(code) < len(encodings) && encodings[code] != nil {
+		return fmt.Errorf("cannot register %s for parquet encoding code %d: the code is already supported by %s", e, code, encodings[code])
+	}
+	registeredEncodingsMutex.Lock()
+	defer registeredEncodingsMutex.Unlock()
+	if r := registeredEncodings[code]; r != nil {
+		return fmt.Errorf("cannot register %s for parquet encoding code %d: the code was already registered by %s", e, code, r)
+	}
+	if registeredEncodings == nil {
+		registeredEncodings = make(map[format.Encoding]encoding.Encoding)
+	}
+	registeredEncodings[code] = e
+	return nil
+}
+
+// ColumnEncodingConfig configures the encoding of a column on parquet writers,
+// overriding the encoding selected from the parquet schema.
+type ColumnEncodingConfig struct {
+	// The path of the leaf column that the configuration applies to.
+	Path []string
+
+	// The encoding forced on the column, or excluded from the encodings that
+	// the column may use when Disabled is true.
+	Encoding encoding.Encoding
+
+	// When true, the encoding is never used on the column, the writer selects
+	// the next encoding declared in the schema or the default encoding of the
+	// column type instead.
+	Disabled bool
+}
+
+// selectColumnEncoding applies the encoding configuration of the column at the
+// given path to the encoding selected for it from the schema.
+//
+// PLAIN is used as a last resort if all other candidate encodings are disabled
+// since it can encode all parquet types.
+func selectColumnEncoding(configs []ColumnEncodingConfig, path columnPath, node Node, selected encoding.Encoding) (encoding.Encoding, error) {
+	forced := encoding.Encoding(nil)
+	disabled := []format.Encoding(nil)
+
+	for _, config := range configs {
+		if path.equal(config.Path) {
+			if config.Disabled {
+				disabled = append(disabled, config.Encoding.Encoding())
+			} else {
+				forced = config.Encoding
+			}
+		}
+	}
+
+	kind := node.Type().Kind()
+	isEnabled := func(e encoding.Encoding) bool {
+		for _, d := range disabled {
+			if d == e.Encoding() {
+				return false
+			}
+		}
+		return true
+	}
+	// The encodings of columns opened from parquet files include those of the
+	// repetition and definition levels (e.g. RLE), which may not be able to
+	// encode the column values, so they are skipped. Encodings configured by
+	// the program are not: they are validated when applied to the schema with
+	// Encoded, or below for the encoding forced on the column.
+	isCandidate := func(e encoding.Encoding) bool {
+		return isEnabled(e) && e.CanEncode(format.Type(kind))
+	}
+
+	if forced != nil {
+		if !forced.CanEncode(format.Type(kind)) {
+			return nil, fmt.Errorf("cannot apply %s to column %q of type %s", forced.Encoding(), path, kind)
+		}
+		if isEnabled(forced) {
+			return forced, nil
+		}
+	}
+
+	if isCandidate(selected) {
+		return selected, nil
+	}
+	for _, e := range node.Encoding() {
+		if isCandidate(e) {
+			return e, nil
+		}
+	}
+	if kind == ByteArray && isEnabled(&DeltaLengthByteArray) {
+		return &DeltaLengthByteArray, nil
+	}
+	return &Plain, nil
+}
+
 func isDictionaryEncoding(encoding encoding.Encoding) bool {
-	switch encoding.Encoding() {
+	return isDictionaryFormat(encoding.Encoding())
+}
+
+func isDictionaryFormat(encoding format.Encoding) bool {
+	switch encoding {
 	case format.PlainDictionary, format.RLEDictionary:
 		return true
 	default:
@@ -64,14 +181,21 @@ func isDictionaryEncoding(encoding encoding.Encoding) bool {
 
 // LookupEncoding returns the parquet encoding associated with the given code.
 //
-// The function never returns nil. If the encoding is not supported,
-// encoding.NotSupported is returned.
+// The function never returns nil. If the encoding is not supported by the
+// package and was not registered with RegisterEncoding, encoding.NotSupported
+// is returned.
 func LookupEncoding(enc format.Encoding) encoding.Encoding {
 	if enc >= 0 && int(enc) < len(encodings) {
 		if e := encodings[enc]; e != nil {
 			return e
 		}
 	}
+	registeredEncodingsMutex.RLock()
+	e := registeredEncodings[enc]
+	registeredEncodingsMutex.RUnlock()
+	if e != nil {
+		return e
+	}
 	return encoding.NotSupported{}
 }
 
@@ -0,0 +1,807 @@
+package parquet
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/segmentio/encoding/thrift"
+	"github.com/segmentio/parquet-go/format"
+)
+
+// The EncryptionConfig type carries the configuration of parquet modular
+// encryption on the write path.
+//
+// Files written with an encryption configuration use the AES_GCM_V1 algorithm
+// and the encrypted footer mode of the parquet specification: the file metadata,
+// the page headers, the pages, the page indexes, and the bloom filters are all
+// encrypted, and the file starts and ends with the "PARE" magic bytes instead
+// of "PAR1".
+//
+// By default, all columns are encrypted with the footer key. Sensitive columns
+// can be given their own keys with ColumnKeys, so that reading them requires
+// access to keys that other readers of the file do not need.
+//
+// Reference: https://github.com/apache/parquet-format/blob/master/Encryption.md
+type EncryptionConfig struct {
+	// The key used to encrypt the file footer and the column chunks, it must
+	// be 16, 24, or 32 bytes long to select AES-128, AES-192, or AES-256.
+	FooterKey []byte
+
+	// Opaque metadata stored in the file to help readers retrieve the footer
+	// key (e.g. a key identifier). The metadata is not encrypted.
+	FooterKeyMetadata []byte
+
+	// An optional key management integration used to generate the metadata
+	// of the footer and column keys which have none configured, typically by
+	// wrapping the keys with a master key held by a key management service.
+	KeyWrapper EncryptionKeyWrapper
+
+	// An optional prefix added to the additional authenticated data of all
+	// encrypted modules, which can be used to bind the file to its context
+	// (e.g. a table name) and protect against file swapping attacks.
+	AADPrefix []byte
+
+	// When true, the AAD prefix is not stored in the file and readers must
+	// supply it to decrypt the file.
+	SupplyAADPrefix bool
+
+	// The list of columns encrypted with their own keys instead of the footer
+	// key. Writers panic if one of the paths does not match a leaf column of
+	// their schema, rather than leaving the column unprotected.
+	ColumnKeys []ColumnKey
+
+	// When true, the columns which are not listed in ColumnKeys are written
+	// in plaintext instead of being encrypted with the footer key. The file
+	// footer remains encrypted.
+	PlaintextColumns bool
+}
+
+// EncryptionKeyWrapper is an interface implemented by key management systems
+// to produce the metadata stored in files alongside the keys that they were
+// encrypted with.
+//
+// The key metadata is opaque to the parquet package, it may be a key
+// identifier, or the key itself encrypted with a master key (envelope
+// encryption); a DecryptionKeyRetriever is then given the metadata to recover
+// the key when reading the file.
+type EncryptionKeyWrapper interface {
+	// Returns the metadata to store in the file to retrieve the given key.
+	//
+	// The method is called once per key when the first file is written, errors
+	// are returned by the writer methods which write the file footer.
+	WrapKey(key []byte) (keyMetadata []byte, err error)
+}
+
+// EncryptionKeyWrapperFunc is an implementation of EncryptionKeyWrapper for
+// functions.
+type EncryptionKeyWrapperFunc func(key []byte) ([]byte, error)
+
+// WrapKey calls f(key).
+func (f EncryptionKeyWrapperFunc) WrapKey(key []byte) ([]byte, error) { return f(key) }
+
+// ColumnKey associates an encryption key with a column.
+type ColumnKey struct {
+	// The path of the leaf column that the key is used for.
+	Path []string
+
+	// The key used to encrypt the column, it must be 16, 24, or 32 bytes long.
+	Key []byte
+
+	// Opaque metadata stored in the file to help readers retrieve the key.
+	KeyMetadata []byte
+}
+
+func (c *EncryptionConfig) validate(baseName string) error {
+	if err := validateEncryptionKey(baseName+"FooterKey", c.FooterKey); err != nil {
+		return err
+	}
+	paths := make(map[string]struct{}, len(c.ColumnKeys))
+	for i, columnKey := range c.ColumnKeys {
+		optionName := fmt.Sprintf("%sColumnKeys[%d]", baseName, i)
+		path := columnPath(columnKey.Path)
+		if len(path) == 0 {
+			return fmt.Errorf("invalid option value: %s.Path: the column path must not be empty", optionName)
+		}
+		if _, exists := paths[path.String()]; exists {
+			return fmt.Errorf("invalid option value: %s.Path: multiple keys configured for column %q", optionName, path)
+		}
+		paths[path.String()] = struct{}{}
+		if err := validateEncryptionKey(optionName+".Key", columnKey.Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateEncryptionKey(optionName string, key []byte) error {
+	switch len(key) {
+	case 16, 24, 32:
+		return nil
+	}
+	// Do not include the key in the error message, it would risk leaking it
+	// to logs.
+	return fmt.Errorf("invalid option value: %s: AES keys must be 16, 24, or 32 bytes long but the key has %d bytes", optionName, len(key))
+}
+
+// Module types defined by the parquet specification, they are part of the
+// additional authenticated data of each encrypted module.
+const (
+	footerModule               = 0
+	columnMetaDataModule       = 1
+	dataPageModule             = 2
+	dictionaryPageModule       = 3
+	dataPageHeaderModule       = 4
+	dictionaryPageHeaderModule = 5
+	columnIndexModule          = 6
+	offsetIndexModule          = 7
+	bloomFilterHeaderModule    = 8
+	bloomFilterBitsetModule    = 9
+)
+
+const (
+	encryptionMagic      = "PARE"
+	encryptionNonceSize  = 12
+	encryptionTagSize    = 16
+	encryptionLengthSize = 4
+	aadFileUniqueSize    = 8
+)
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptModule appends the encrypted form of plaintext to dst, which is made
+// of a 4 bytes little-endian length, the nonce, the ciphertext, and the tag.
+func encryptModule(dst []byte, aead cipher.AEAD, aad, plaintext []byte) ([]byte, error) {
+	offset := len(dst)
+	dst = append(dst, 0, 0, 0, 0)
+	dst = append(dst, make([]byte, encryptionNonceSize)...)
+	nonce := dst[offset+encryptionLengthSize:]
+	if _, err := rand.Read(nonce); err != nil {
+		return dst[:offset], fmt.Errorf("generating parquet encryption nonce: %w", err)
+	}
+	dst = aead.Seal(dst, nonce, plaintext, aad)
+	binary.LittleEndian.PutUint32(dst[offset:], uint32(len(dst)-(offset+encryptionLengthSize)))
+	return dst, nil
+}
+
+// moduleAAD appends to dst the additional authenticated data of a module. The
+// ordinals are only included for the module types that carry them; pageOrdinal
+// must be negative for modules which are not data pages or data page headers.
+func moduleAAD(dst, fileAAD []byte, moduleType byte, rowGroupOrdinal, columnOrdinal, pageOrdinal int) []byte {
+	dst = append(dst, fileAAD...)
+	dst = append(dst, moduleType)
+	if moduleType != footerModule {
+		dst = appendInt16(dst, rowGroupOrdinal)
+		dst = appendInt16(dst, columnOrdinal)
+		if pageOrdinal >= 0 {
+			dst = appendInt16(dst, pageOrdinal)
+		}
+	}
+	return dst
+}
+
+func appendInt16(b []byte, v int) []byte {
+	return append(b, byte(v), byte(v>>8))
+}
+
+// fileEncryptor holds the state used by writers to encrypt the modules of a
+// parquet file.
+type fileEncryptor struct {
+	footer    cipher.AEAD
+	footerKey []byte
+	keyMeta   []byte
+	// The key wrapper generating missing key metadata, and whether it was
+	// already called.
+	wrapper   EncryptionKeyWrapper
+	wrapped   bool
+	aadPrefix []byte
+	fileAAD   []byte
+	supplyAAD bool
+	plaintext bool
+	// Keys of columns encrypted with their own keys, indexed by path, and
+	// ciphers of each column indexed by ordinal (nil for plaintext columns).
+	columnKeys map[string]*columnEncryptionKey
+	columns    []cipher.AEAD
+	// Ordinal of the row group that pages are being written to; it is the
+	// number of row groups already written to the file.
+	rowGroup   int
+	aad        []byte
+	ciphertext []byte
+}
+
+func newFileEncryptor(config *EncryptionConfig) (*fileEncryptor, error) {
+	footer, err := newAESGCM(config.FooterKey)
+	if err != nil {
+		return nil, err
+	}
+	e := &fileEncryptor{
+		footer:     footer,
+		footerKey:  config.FooterKey,
+		keyMeta:    config.FooterKeyMetadata,
+		wrapper:    config.KeyWrapper,
+		aadPrefix:  config.AADPrefix,
+		supplyAAD:  config.SupplyAADPrefix,
+		plaintext:  config.PlaintextColumns,
+		columnKeys: make(map[string]*columnEncryptionKey, len(config.ColumnKeys)),
+	}
+	for _, columnKey := range config.ColumnKeys {
+		c, err := newAESGCM(columnKey.Key)
+		if err != nil {
+			return nil, err
+		}
+		e.columnKeys[columnPath(columnKey.Path).String()] = &columnEncryptionKey{
+			cipher: c,
+			key:    columnKey.Key,
+			crypto: format.EncryptionWithColumnKey{
+				PathInSchema: columnKey.Path,
+				KeyMetadata:  columnKey.KeyMetadata,
+			},
+		}
+	}
+	return e, nil
+}
+
+type columnEncryptionKey struct {
+	cipher cipher.AEAD
+	key    []byte
+	// The crypto metadata is shared by all chunks of the column so the key
+	// metadata can be generated after the columns were added.
+	crypto format.EncryptionWithColumnKey
+	used   bool
+}
+
+// addColumn registers the next column of the file, returning its crypto
+// metadata and whether the column is encrypted. Columns must be added in the
+// order that they are written in the row groups.
+func (e *fileEncryptor) addColumn(path columnPath) (format.ColumnCryptoMetaData, bool) {
+	if columnKey := e.columnKeys[path.String()]; columnKey != nil {
+		columnKey.used = true
+		e.columns = append(e.columns, columnKey.cipher)
+		return format.ColumnCryptoMetaData{
+			EncryptionWithColumnKey: &columnKey.crypto,
+		}, true
+	}
+	if e.plaintext {
+		e.columns = append(e.columns, nil)
+		return format.ColumnCryptoMetaData{}, false
+	}
+	e.columns = append(e.columns, e.footer)
+	return format.ColumnCryptoMetaData{
+		EncryptionWithFooterKey: &format.EncryptionWithFooterKey{},
+	}, true
+}
+
+// checkColumnKeys returns an error if some of the column keys were configured
+// for columns which do not exist.
+func (e *fileEncryptor) checkColumnKeys() error {
+	for path, columnKey := range e.columnKeys {
+		if !columnKey.used {
+			return fmt.Errorf("encryption key configured for column %q which does not exist in the parquet schema", path)
+		}
+	}
+	return nil
+}
+
+// wrapKeys generates the metadata of keys which have none configured, using the
+// key wrapper of the encryption configuration.
+func (e *fileEncryptor) wrapKeys() error {
+	if e.wrapper == nil || e.wrapped {
+		return nil
+	}
+	if e.keyMeta == nil {
+		keyMetadata, err := e.wrapper.WrapKey(e.footerKey)
+		if err != nil {
+			return fmt.Errorf("wrapping parquet footer key: %w", err)
+		}
+		e.keyMeta = keyMetadata
+	}
+	for path, columnKey := range e.columnKeys {
+		if columnKey.crypto.KeyMetadata == nil {
+			keyMetadata, err := e.wrapper.WrapKey(columnKey.key)
+			if err != nil {
+				return fmt.Errorf("wrapping parquet encryption key of column %q: %w", path, err)
+			}
+			columnKey.crypto.KeyMetadata = keyMetadata
+		}
+	}
+	e.wrapped = true
+	return nil
+}
+
+// encrypted returns true if the column at the given ordinal is encrypted.
+func (e *fileEncryptor) encrypted(columnOrdinal int) bool {
+	return e.columns[columnOrdinal] != nil
+}
+
+// encryptColumnMetaData moves the metadata of a column chunk encrypted with
+// its own key to an encrypted module, since it would otherwise be visible to
+// readers which only have the footer key.
+func (e *fileEncryptor) encryptColumnMetaData(chunk *format.ColumnChunk, rowGroupOrdinal, columnOrdinal int) error {
+	if chunk.CryptoMetadata.EncryptionWithColumnKey == nil {
+		return nil
+	}
+	b, err := thrift.Marshal(new(thrift.CompactProtocol), &chunk.MetaData)
+	if err != nil {
+		return err
+	}
+	b, err = e.encryptBytes(b, columnMetaDataModule, rowGroupOrdinal, columnOrdinal, -1)
+	if err != nil {
+		return err
+	}
+	chunk.EncryptedColumnMetadata = append([]byte{}, b...)
+	chunk.MetaData = format.ColumnMetaData{}
+	return nil
+}
+
+// reset must be called each time the encryptor starts being used for a new
+// file, a new unique file identifier is then lazily generated when the first
+// module of the file gets encrypted.
+func (e *fileEncryptor) reset() {
+	e.rowGroup = 0
+	e.fileAAD = e.fileAAD[:0]
+}
+
+func (e *fileEncryptor) init() error {
+	if len(e.fileAAD) == 0 {
+		e.fileAAD = append(e.fileAAD, e.aadPrefix...)
+		e.fileAAD = append(e.fileAAD, make([]byte, aadFileUniqueSize)...)
+		if _, err := rand.Read(e.fileAAD[len(e.aadPrefix):]); err != nil {
+			e.fileAAD = e.fileAAD[:0]
+			return fmt.Errorf("generating parquet encryption file identifier: %w", err)
+		}
+	}
+	return nil
+}
+
+func (e *fileEncryptor) encryptionAlgorithm() format.EncryptionAlgorithm {
+	algorithm := &format.AesGcmV1{
+		AadFileUnique:   e.fileAAD[len(e.aadPrefix):],
+		SupplyAadPrefix: e.supplyAAD,
+	}
+	if !e.supplyAAD {
+		algorithm.AadPrefix = e.aadPrefix
+	}
+	return format.EncryptionAlgorithm{AesGcmV1: algorithm}
+}
+
+// encrypt replaces the content of buffer with its encrypted form.
+func (e *fileEncryptor) encrypt(buffer *bytes.Buffer, moduleType byte, rowGroupOrdinal, columnOrdinal, pageOrdinal int) error {
+	b, err := e.encryptBytes(buffer.Bytes(), moduleType, rowGroupOrdinal, columnOrdinal, pageOrdinal)
+	if err != nil {
+		return err
+	}
+	buffer.Reset()
+	buffer.Write(b)
+	return nil
+}
+
+// encryptBytes returns the encrypted form of plaintext. The returned slice
+// remains valid until the next call to one of the encryption methods.
+func (e *fileEncryptor) encryptBytes(plaintext []byte, moduleType byte, rowGroupOrdinal, columnOrdinal, pageOrdinal int) ([]byte, error) {
+	if rowGroupOrdinal > math.MaxInt16 || columnOrdinal > math.MaxInt16 || pageOrdinal > math.MaxInt16 {
+		return nil, fmt.Errorf("cannot encrypt parquet module: ordinals are limited to %d (row group=%d column=%d page=%d)",
+			math.MaxInt16, rowGroupOrdinal, columnOrdinal, pageOrdinal)
+	}
+	if err := e.init(); err != nil {
+		return nil, err
+	}
+	e.aad = moduleAAD(e.aad[:0], e.fileAAD, moduleType, rowGroupOrdinal, columnOrdinal, pageOrdinal)
+	var err error
+	e.ciphertext, err = encryptModule(e.ciphertext[:0], e.columns[columnOrdinal], e.aad, plaintext)
+	return e.ciphertext, err
+}
+
+// encryptFooter appends the footer of an encrypted file to dst, which is made
+// of the crypto metadata followed by the encrypted file metadata.
+func (e *fileEncryptor) encryptFooter(dst []byte, fileMetaData *format.FileMetaData) ([]byte, error) {
+	if err := e.init(); err != nil {
+		return dst, err
+	}
+	cryptoMetaData, err := thrift.Marshal(new(thrift.CompactProtocol), &format.FileCryptoMetaData{
+		EncryptionAlgorithm: e.encryptionAlgorithm(),
+		KeyMetadata:         e.keyMeta,
+	})
+	if err != nil {
+		return dst, err
+	}
+	plaintext, err := thrift.Marshal(new(thrift.CompactProtocol), fileMetaData)
+	if err != nil {
+		return dst, err
+	}
+	e.aad = moduleAAD(e.aad[:0], e.fileAAD, footerModule, 0, 0, -1)
+	dst = append(dst, cryptoMetaData...)
+	return encryptModule(dst, e.footer, e.aad, plaintext)
+}
+
+// The DecryptionConfig type carries the configuration used to open encrypted
+// parquet files.
+//
+// Both the encrypted footer and plaintext footer modes of the specification
+// are supported, as well as the AES_GCM_V1 and AES_GCM_CTR_V1 algorithms.
+type DecryptionConfig struct {
+	// The key used to decrypt the file footer and the columns encrypted with
+	// the footer key. When nil, the key is obtained by passing the footer key
+	// metadata to KeyRetriever.
+	FooterKey []byte
+
+	// The key management integration used to retrieve the keys of the file
+	// from the key metadata stored in the file. It is required to read columns
+	// which were encrypted with their own keys.
+	KeyRetriever DecryptionKeyRetriever
+
+	// The AAD prefix that the file was written with, which must be supplied
+	// when it was not stored in the file. If the file does contain an AAD
+	// prefix, it must match this one.
+	AADPrefix []byte
+}
+
+// DecryptionKeyRetriever is an interface implemented by key management systems
+// to resolve the keys needed to decrypt parquet files.
+type DecryptionKeyRetriever interface {
+	// Returns the key that the given key metadata was generated for. The
+	// metadata may be empty if none was stored in the file.
+	RetrieveKey(keyMetadata []byte) (key []byte, err error)
+}
+
+// DecryptionKeyRetrieverFunc is an implementation of DecryptionKeyRetriever
+// for functions.
+type DecryptionKeyRetrieverFunc func(keyMetadata []byte) ([]byte, error)
+
+// RetrieveKey calls f(keyMetadata).
+func (f DecryptionKeyRetrieverFunc) RetrieveKey(keyMetadata []byte) ([]byte, error) {
+	return f(keyMetadata)
+}
+
+func (c *DecryptionConfig) validate(baseName string) error {
+	if c.FooterKey != nil {
+		return validateEncryptionKey(baseName+"FooterKey", c.FooterKey)
+	}
+	if c.KeyRetriever == nil {
+		return fmt.Errorf("invalid option value: %sKeyRetriever: a key retriever is required when no footer key is configured", baseName)
+	}
+	return nil
+}
+
+func (c *DecryptionConfig) retrieveKey(keyMetadata []byte) ([]byte, error) {
+	if c.KeyRetriever == nil {
+		return nil, fmt.Errorf("no key retriever configured to retrieve the key of metadata %q", keyMetadata)
+	}
+	key, err := c.KeyRetriever.RetrieveKey(keyMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving parquet encryption key: %w", err)
+	}
+	return key, validateEncryptionKey("retrieved key", key)
+}
+
+// moduleCipher holds the ciphers used to decrypt modules encrypted with the
+// same key.
+type moduleCipher struct {
+	block cipher.Block
+	gcm   cipher.AEAD
+}
+
+func newModuleCipher(key []byte) (*moduleCipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &moduleCipher{block: block, gcm: gcm}, nil
+}
+
+// decrypt decrypts the module passed as argument in place, the returned slice
+// shares the backing array of module.
+func (c *moduleCipher) decrypt(module, aad []byte, ctr bool) ([]byte, error) {
+	if len(module) < encryptionLengthSize+encryptionNonceSize {
+		return nil, fmt.Errorf("encrypted parquet module is too short: %d bytes", len(module))
+	}
+	length := int(binary.LittleEndian.Uint32(module))
+	if length != len(module)-encryptionLengthSize {
+		return nil, fmt.Errorf("encrypted parquet module length mismatch: %d != %d", length, len(module)-encryptionLengthSize)
+	}
+	nonce := module[encryptionLengthSize : encryptionLengthSize+encryptionNonceSize]
+	ciphertext := module[encryptionLengthSize+encryptionNonceSize:]
+
+	if ctr {
+		// In AES_GCM_CTR_V1, the counter is made of the nonce followed by
+		// a 32 bits big-endian block counter starting at 1.
+		iv := [aes.BlockSize]byte{15: 1}
+		copy(iv[:], nonce)
+		cipher.NewCTR(c.block, iv[:]).XORKeyStream(ciphertext, ciphertext)
+		return ciphertext, nil
+	}
+
+	plaintext, err := c.gcm.Open(ciphertext[:0], nonce, ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting parquet module: %w", err)
+	}
+	return plaintext, nil
+}
+
+// verify checks that the signature of a plaintext footer matches its content.
+func (c *moduleCipher) verify(footer, signature, aad []byte) bool {
+	nonce, tag := signature[:encryptionNonceSize], signature[encryptionNonceSize:]
+	sealed := c.gcm.Seal(nil, nonce, footer, aad)
+	return subtle.ConstantTimeCompare(sealed[len(sealed)-encryptionTagSize:], tag) == 1
+}
+
+// fileDecryptor holds the state used to decrypt the modules of a parquet file.
+type fileDecryptor struct {
+	config  *DecryptionConfig
+	fileAAD []byte
+	ctr     bool
+	footer  *moduleCipher
+	keys    map[string]*moduleCipher
+	errors  map[string]error
+}
+
+func newFileDecryptor(config *DecryptionConfig, algorithm *format.EncryptionAlgorithm, footerKeyMetadata []byte) (*fileDecryptor, error) {
+	if config == nil {
+		return nil, fmt.Errorf("parquet file is encrypted but no decryption configuration was given")
+	}
+
+	var aadPrefix, aadFileUnique []byte
+	var supplyAADPrefix, ctr bool
+	switch {
+	case algorithm.AesGcmV1 != nil:
+		aadPrefix = algorithm.AesGcmV1.AadPrefix
+		aadFileUnique = algorithm.AesGcmV1.AadFileUnique
+		supplyAADPrefix = algorithm.AesGcmV1.SupplyAadPrefix
+	case algorithm.AesGcmCtrV1 != nil:
+		aadPrefix = algorithm.AesGcmCtrV1.AadPrefix
+		aadFileUnique = algorithm.AesGcmCtrV1.AadFileUnique
+		supplyAADPrefix = algorithm.AesGcmCtrV1.SupplyAadPrefix
+		ctr = true
+	default:
+		return nil, fmt.Errorf("parquet file is encrypted with an unsupported algorithm")
+	}
+
+	switch {
+	case supplyAADPrefix:
+		if config.AADPrefix == nil {
+			return nil, fmt.Errorf("parquet file was encrypted with an AAD prefix which must be supplied to decrypt it")
+		}
+		aadPrefix = config.AADPrefix
+	case config.AADPrefix != nil && !bytes.Equal(config.AADPrefix, aadPrefix):
+		return nil, fmt.Errorf("AAD prefix of the parquet file does not match the configured AAD prefix")
+	}
+
+	d := &fileDecryptor{
+		config:  config,
+		fileAAD: append(append([]byte{}, aadPrefix...), aadFileUnique...),
+		ctr:     ctr,
+		keys:    make(map[string]*moduleCipher),
+		errors:  make(map[string]error),
+	}
+
+	footerKey := config.FooterKey
+	if footerKey == nil {
+		var err error
+		if footerKey, err = config.retrieveKey(footerKeyMetadata); err != nil {
+			return nil, fmt.Errorf("retrieving parquet footer key: %w", err)
+		}
+	}
+	footer, err := newModuleCipher(footerKey)
+	if err != nil {
+		return nil, err
+	}
+	d.footer = footer
+	return d, nil
+}
+
+// decryptFooter decrypts the footer of a file in the encrypted footer mode,
+// which starts with the crypto metadata followed by the encrypted module of
+// the file metadata.
+func decryptFooter(config *DecryptionConfig, footer []byte) (*fileDecryptor, []byte, error) {
+	r := bytes.NewReader(footer)
+	cryptoMetaData := format.FileCryptoMetaData{}
+	if err := thrift.NewDecoder(new(thrift.CompactProtocol).NewReader(r)).Decode(&cryptoMetaData); err != nil {
+		return nil, nil, fmt.Errorf("reading parquet file crypto metadata: %w", err)
+	}
+	d, err := newFileDecryptor(config, &cryptoMetaData.EncryptionAlgorithm, cryptoMetaData.KeyMetadata)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The footer is always encrypted with AES-GCM, even in AES_GCM_CTR_V1.
+	module := footer[len(footer)-r.Len():]
+	metadata, err := d.footer.decrypt(module, d.aad(footerModule, 0, 0, -1), false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decrypting parquet file metadata: %w", err)
+	}
+	return d, metadata, nil
+}
+
+// verifyFooter checks the signature of a footer in the plaintext footer mode,
+// which is made of the nonce and tag of the file metadata encrypted with the
+// footer key.
+func (d *fileDecryptor) verifyFooter(footer []byte) error {
+	if len(footer) < encryptionNonceSize+encryptionTagSize {
+		return fmt.Errorf("parquet file footer is too short to contain a signature")
+	}
+	i := len(footer) - (encryptionNonceSize + encryptionTagSize)
+	if !d.footer.verify(footer[:i], footer[i:], d.aad(footerModule, 0, 0, -1)) {
+		return fmt.Errorf("signature of the parquet file footer does not match its content")
+	}
+	return nil
+}
+
+func (d *fileDecryptor) aad(moduleType byte, rowGroupOrdinal, columnOrdinal, pageOrdinal int) []byte {
+	return moduleAAD(make([]byte, 0, len(d.fileAAD)+7), d.fileAAD, moduleType, rowGroupOrdinal, columnOrdinal, pageOrdinal)
+}
+
+// columnCipher returns the cipher used to decrypt the modules of the given
+// column chunk, or nil if the column chunk is not encrypted.
+func (d *fileDecryptor) columnCipher(chunk *format.ColumnChunk) (*moduleCipher, error) {
+	switch crypto := &chunk.CryptoMetadata; {
+	case crypto.EncryptionWithFooterKey != nil:
+		return d.footer, nil
+	case crypto.EncryptionWithColumnKey != nil:
+		keyMetadata := crypto.EncryptionWithColumnKey.KeyMetadata
+		if c := d.keys[string(keyMetadata)]; c != nil {
+			return c, nil
+		}
+		if err := d.errors[string(keyMetadata)]; err != nil {
+			return nil, err
+		}
+		key, err := d.config.retrieveKey(keyMetadata)
+		if err != nil {
+			err = fmt.Errorf("retrieving key of column %q: %w", columnPath(crypto.EncryptionWithColumnKey.PathInSchema), err)
+			d.errors[string(keyMetadata)] = err
+			return nil, err
+		}
+		c, err := newModuleCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		d.keys[string(keyMetadata)] = c
+		return c, nil
+	default:
+		return nil, nil
+	}
+}
+
+// decryptColumns creates the decryptors of all column chunks in metadata, and
+// decrypts the metadata of column chunks encrypted with their own keys. The
+// decryptors of column chunks that are not encrypted are nil.
+//
+// When the key of a column cannot be retrieved, the error is reported when
+// reading the column instead of failing to open the file, so programs can read
+// the other columns without having access to all the keys.
+func (d *fileDecryptor) decryptColumns(metadata *format.FileMetaData) ([][]*columnDecryptor, error) {
+	decryptors := make([][]*columnDecryptor, len(metadata.RowGroups))
+
+	for i := range metadata.RowGroups {
+		columns := metadata.RowGroups[i].Columns
+		decryptors[i] = make([]*columnDecryptor, len(columns))
+
+		for j := range columns {
+			chunk := &columns[j]
+			if chunk.CryptoMetadata.EncryptionWithFooterKey == nil && chunk.CryptoMetadata.EncryptionWithColumnKey == nil {
+				continue
+			}
+			c, err := d.columnCipher(chunk)
+			decryptors[i][j] = &columnDecryptor{
+				file:     d,
+				cipher:   c,
+				err:      err,
+				rowGroup: i,
+				column:   j,
+			}
+
+			if c != nil && chunk.EncryptedColumnMetadata != nil {
+				// The decryption happens in place, work on a copy to leave
+				// the file metadata intact.
+				module := append([]byte{}, chunk.EncryptedColumnMetadata...)
+				b, err := c.decrypt(module, d.aad(columnMetaDataModule, i, j, -1), false)
+				if err != nil {
+					return nil, fmt.Errorf("decrypting metadata of column %d in row group %d: %w", j, i, err)
+				}
+				chunk.MetaData = format.ColumnMetaData{}
+				if err := thrift.Unmarshal(new(thrift.CompactProtocol), b, &chunk.MetaData); err != nil {
+					return nil, fmt.Errorf("decoding metadata of column %d in row group %d: %w", j, i, err)
+				}
+			}
+		}
+	}
+
+	return decryptors, nil
+}
+
+// columnDecryptor decrypts the modules of a column chunk.
+type columnDecryptor struct {
+	file   *fileDecryptor
+	cipher *moduleCipher
+	// The error which occurred retrieving the key of the column, it is
+	// returned when attempting to decrypt modules of the column.
+	err      error
+	rowGroup int
+	column   int
+}
+
+// decrypt decrypts the given module in place. The page ordinal must be
+// negative for modules which are neither data pages nor data page headers.
+func (d *columnDecryptor) decrypt(module []byte, moduleType byte, pageOrdinal int) ([]byte, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	ctr := d.file.ctr && (moduleType == dataPageModule || moduleType == dictionaryPageModule)
+	return d.cipher.decrypt(module, d.file.aad(moduleType, d.rowGroup, d.column, pageOrdinal), ctr)
+}
+
+// readModule reads an encrypted module from r and decrypts it. The buffer is
+// used to hold the module and may be grown, it is returned along with the
+// decrypted content.
+//
+// The length of the module is read from the file, limit is the maximum size
+// that it may have (including the length) based on the section of the file
+// that it was read from, so corrupted lengths do not cause large allocations.
+func (d *columnDecryptor) readModule(r io.Reader, buffer []byte, limit int64, moduleType byte, pageOrdinal int) (plaintext, newBuffer []byte, err error) {
+	if d.err != nil {
+		// Fail before reading, the metadata of the column may be missing
+		// which would otherwise cause the column to appear empty.
+		return nil, buffer, d.err
+	}
+	var length [encryptionLengthSize]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, buffer, err
+	}
+	size := encryptionLengthSize + int64(binary.LittleEndian.Uint32(length[:]))
+	if size > limit {
+		return nil, buffer, fmt.Errorf("encrypted module of %d bytes exceeds the %d bytes of its file section: %w", size, limit, ErrCorrupted)
+	}
+	if int64(cap(buffer)) < size {
+		buffer = make([]byte, size)
+	}
+	buffer = buffer[:size]
+	copy(buffer, length[:])
+	if _, err := io.ReadFull(r, buffer[encryptionLengthSize:]); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, buffer, err
+	}
+	plaintext, err = d.decrypt(buffer, moduleType, pageOrdinal)
+	return plaintext, buffer, err
+}
+
+// readBloomFilter reads the encrypted bloom filter of a column chunk at the
+// given offset, which is made of the header module followed by the bitset
+// module. The bitset is loaded in memory since it cannot be read lazily from
+// the file.
+func (d *columnDecryptor) readBloomFilter(r io.ReaderAt, offset, size int64) (*bloomFilter, error) {
+	section := io.NewSectionReader(r, offset, size)
+
+	b, _, err := d.readModule(section, nil, size, bloomFilterHeaderModule, -1)
+	if err != nil {
+		return nil, err
+	}
+	header := format.BloomFilterHeader{}
+	if err := thrift.Unmarshal(new(thrift.CompactProtocol), b, &header); err != nil {
+		return nil, err
+	}
+
+	headerSize, _ := section.Seek(0, io.SeekCurrent)
+	bitset, _, err := d.readModule(section, nil, size-headerSize, bloomFilterBitsetModule, -1)
+	if err != nil {
+		return nil, err
+	}
+	if len(bitset) != int(header.NumBytes) {
+		return nil, fmt.Errorf("bloom filter bitset size mismatch: %d != %d", len(bitset), header.NumBytes)
+	}
+	return newBloomFilter(bytes.NewReader(bitset), 0, &header), nil
+}
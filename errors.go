@@ -1,6 +1,9 @@
 package parquet
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrCorrupted is an error returned by the Err method of ColumnPages
@@ -29,4 +32,76 @@ var (
 	// ErrSeekOutOfRange is an error returned when seeking to a row index which
 	// is less than the first row of a page.
 	ErrSeekOutOfRange = errors.New("seek to row index out of page range")
+
+	// ErrMemoryLimitExceeded is an error returned when reading pages from a
+	// file would exceed the limit configured with the MemoryLimit option.
+	ErrMemoryLimitExceeded = errors.New("parquet memory limit exceeded")
+
+	// ErrValueOutOfRange is an error returned when converting a value to a kind
+	// or type which cannot represent it, for example when converting an INT64
+	// value which does not fit in 32 bits to INT32.
+	ErrValueOutOfRange = errors.New("parquet value out of range")
 )
+
+// CorruptedDataError is the type of errors reported to the callback of the
+// SkipCorrupted option when a reader skips data which could not be read.
+type CorruptedDataError struct {
+	// Index of the row group that the data was skipped from, or -1 if the
+	// error was not related to a specific row group.
+	RowGroup int
+	// Index of the column that the data was skipped from, or -1 if the whole
+	// row group was skipped.
+	Column int
+	// Index of the page that was skipped, or -1 if the error was not related to
+	// a specific page.
+	Page int
+	// The error which caused the data to be skipped.
+	Err error
+}
+
+// Error satisfies the error interface.
+func (e *CorruptedDataError) Error() string {
+	switch {
+	case e.RowGroup < 0:
+		return fmt.Sprintf("skipped corrupted parquet data: %v", e.Err)
+	case e.Column < 0:
+		return fmt.Sprintf("skipped corrupted parquet row group %d: %v", e.RowGroup, e.Err)
+	case e.Page < 0:
+		return fmt.Sprintf("skipped corrupted parquet column %d in row group %d: %v", e.Column, e.RowGroup, e.Err)
+	default:
+		return fmt.Sprintf("skipped corrupted parquet page %d of column %d in row group %d: %v", e.Page, e.Column, e.RowGroup, e.Err)
+	}
+}
+
+// Unwrap returns the underlying error.
+func (e *CorruptedDataError) Unwrap() error { return e.Err }
+
+// MemoryLimitError is the type of errors returned when reading a page would
+// make the memory held by the readers of a file exceed the limit configured
+// with the MemoryLimit option. The error wraps ErrMemoryLimitExceeded.
+type MemoryLimitError struct {
+	// The memory limit configured on the file, in bytes.
+	Limit int64
+	// The memory held by the readers of the file when the page was read.
+	InUse int64
+	// The memory that the page needed to be read.
+	Size int64
+	// Location of the page that could not be read, the page index is -1 for
+	// dictionary pages.
+	RowGroup int
+	Column   int
+	Page     int
+}
+
+// Error satisfies the error interface.
+func (e *MemoryLimitError) Error() string {
+	page := fmt.Sprintf("page %d", e.Page)
+	if e.Page < 0 {
+		page = "dictionary page"
+	}
+	return fmt.Sprintf("reading %s of column %d in row group %d requires %d bytes with %d/%d bytes in use: %v",
+		page, e.Column, e.RowGroup, e.Size, e.InUse, e.Limit, ErrMemoryLimitExceeded)
+}
+
+// Unwrap returns ErrMemoryLimitExceeded.
+func (e *MemoryLimitError) Unwrap() error { return ErrMemoryLimitExceeded }
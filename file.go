@@ -9,9 +9,12 @@ import (
 	"hash/crc32"
 	"io"
 	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/segmentio/encoding/thrift"
+	"github.com/segmentio/parquet-go/compress"
 	"github.com/segmentio/parquet-go/encoding"
 	"github.com/segmentio/parquet-go/format"
 )
@@ -33,6 +36,26 @@ type File struct {
 	columnIndexes []format.ColumnIndex
 	offsetIndexes []format.OffsetIndex
 	rowGroups     []fileRowGroup
+	rowOffsets    []int64
+	decryption    *fileDecryptor
+	decryptors    [][]*columnDecryptor
+	strict        bool
+	skipCorrupted func(error)
+	observer      *ReaderObserver
+	memory        *memoryBudget
+	// Decompressors of the zstd dictionaries configured on the file, by
+	// dictionary identifier.
+	zstdDictionaries map[uint32]*compressedPageReaderPool
+	// Decompressors of the codecs returned by the CompressionCodecResolver
+	// of the file configuration, by compression codec. The codecs are resolved
+	// when opening the file, the map is read-only after that.
+	resolveCodec   func(format.CompressionCodec) compress.Codec
+	resolvedCodecs map[format.CompressionCodec]*compressedPageReaderPool
+
+	// Bloom filters loaded in memory by MayContain, guarded by the mutex since
+	// the file may be probed from multiple goroutines.
+	bloomFiltersMutex sync.Mutex
+	bloomFilters      map[*fileColumnChunk]*bloomFilter
 }
 
 // OpenFile opens a parquet file and reads the content between offset 0 and the given
@@ -41,43 +64,112 @@ type File struct {
 // Only the parquet magic bytes and footer are read, column chunks and other
 // parts of the file are left untouched; this means that successfully opening
 // a file does not validate that the pages have valid checksums.
+//
+// Encrypted files can only be opened when the Decryption option is passed to
+// provide the keys.
 func OpenFile(r io.ReaderAt, size int64, options ...FileOption) (*File, error) {
 	b := make([]byte, 8)
-	f := &File{reader: r, size: size}
 	c, err := NewFileConfig(options...)
 	if err != nil {
 		return nil, err
 	}
+	if c.Observer != nil && c.Observer.OnRead != nil {
+		r = &observedReaderAt{reader: r, observer: c.Observer}
+	}
+	f := &File{reader: r, size: size}
+	f.strict = c.StrictValidation
+	f.skipCorrupted = c.SkipCorrupted
+	f.observer = c.Observer
+	f.resolveCodec = c.CompressionCodecResolver
+	if c.MemoryLimit > 0 {
+		f.memory = &memoryBudget{limit: c.MemoryLimit}
+	}
+	if len(c.ZstdDictionaries) > 0 {
+		if f.zstdDictionaries, err = newZstdDictionaryPools(c.ZstdDictionaries); err != nil {
+			return nil, err
+		}
+	}
 
 	if _, err := r.ReadAt(b[:4], 0); err != nil {
 		return nil, fmt.Errorf("reading magic header of parquet file: %w", err)
 	}
-	if string(b[:4]) != "PAR1" {
+	magic := string(b[:4])
+	if magic != "PAR1" && magic != encryptionMagic {
 		return nil, fmt.Errorf("invalid magic header of parquet file: %q", b[:4])
 	}
 
 	if _, err := r.ReadAt(b[:8], size-8); err != nil {
 		return nil, fmt.Errorf("reading magic footer of parquet file: %w", err)
 	}
-	if string(b[4:8]) != "PAR1" {
+	if string(b[4:8]) != magic {
 		return nil, fmt.Errorf("invalid magic footer of parquet file: %q", b[4:8])
 	}
 
 	footerSize := int64(binary.LittleEndian.Uint32(b[:4]))
-	section := acquireBufferedSectionReader(r, size-(footerSize+8), footerSize)
+	footerOffset := size - (footerSize + 8)
+	if footerOffset < 4 {
+		return nil, fmt.Errorf("invalid footer size of parquet file: %d: %w", footerSize, ErrCorrupted)
+	}
+	section := acquireBufferedSectionReader(r, footerOffset, footerSize)
 	decoder := thrift.NewDecoder(f.protocol.NewReader(section))
 	defer releaseBufferedSectionReader(section)
 
-	if err := decoder.Decode(&f.metadata); err != nil {
-		return nil, fmt.Errorf("reading parquet file metadata: %w", err)
+	if magic == encryptionMagic {
+		// In the encrypted footer mode, the footer starts with the crypto
+		// metadata, followed by the encrypted file metadata.
+		footer := make([]byte, footerSize)
+		if _, err := r.ReadAt(footer, footerOffset); err != nil {
+			return nil, fmt.Errorf("reading parquet file footer: %w", err)
+		}
+		decryption, metadata, err := decryptFooter(c.Decryption, footer)
+		if err != nil {
+			return nil, err
+		}
+		if err := thrift.Unmarshal(&f.protocol, metadata, &f.metadata); err != nil {
+			return nil, fmt.Errorf("reading parquet file metadata: %w", err)
+		}
+		f.decryption = decryption
+	} else {
+		if err := decoder.Decode(&f.metadata); err != nil {
+			return nil, fmt.Errorf("reading parquet file metadata: %w", err)
+		}
+		if algorithm := &f.metadata.EncryptionAlgorithm; algorithm.AesGcmV1 != nil || algorithm.AesGcmCtrV1 != nil {
+			// In the plaintext footer mode, the file metadata is followed by
+			// a signature made with the footer key.
+			decryption, err := newFileDecryptor(c.Decryption, algorithm, f.metadata.FooterSigningKeyMetadata)
+			if err != nil {
+				return nil, err
+			}
+			footer := make([]byte, footerSize)
+			if _, err := r.ReadAt(footer, footerOffset); err != nil {
+				return nil, fmt.Errorf("reading parquet file footer: %w", err)
+			}
+			if err := decryption.verifyFooter(footer); err != nil {
+				return nil, err
+			}
+			f.decryption = decryption
+		}
 	}
 	if len(f.metadata.Schema) == 0 {
 		return nil, ErrMissingRootColumn
 	}
 
+	if f.decryption != nil {
+		if f.decryptors, err = f.decryption.decryptColumns(&f.metadata); err != nil {
+			return nil, fmt.Errorf("opening encrypted columns of parquet file: %w", err)
+		}
+	}
+
+	f.resolveCodecs()
+
 	if !c.SkipPageIndex {
 		if f.columnIndexes, f.offsetIndexes, err = f.readPageIndex(section, decoder); err != nil {
-			return nil, fmt.Errorf("reading page index of parquet file: %w", err)
+			err = fmt.Errorf("reading page index of parquet file: %w", err)
+			if f.skipCorrupted == nil {
+				return nil, err
+			}
+			f.reportCorrupted(-1, -1, -1, err)
+			f.columnIndexes, f.offsetIndexes = nil, nil
 		}
 	}
 
@@ -91,7 +183,22 @@ func OpenFile(r io.ReaderAt, size int64, options ...FileOption) (*File, error) {
 
 	f.rowGroups = make([]fileRowGroup, len(f.metadata.RowGroups))
 	for i := range f.rowGroups {
-		f.rowGroups[i].init(f, schema, columns, &f.metadata.RowGroups[i])
+		f.rowGroups[i].init(f, schema, columns, i, &f.metadata.RowGroups[i])
+	}
+
+	if err := f.openZstdDictionaries(); err != nil {
+		return nil, err
+	}
+
+	f.rowOffsets = make([]int64, len(f.rowGroups)+1)
+	for i := range f.rowGroups {
+		f.rowOffsets[i+1] = f.rowOffsets[i] + f.metadata.RowGroups[i].NumRows
+	}
+
+	if f.strict {
+		if err := f.validateMetadata(); err != nil {
+			return nil, err
+		}
 	}
 
 	if !c.SkipBloomFilters {
@@ -106,11 +213,27 @@ func OpenFile(r io.ReaderAt, size int64, options ...FileOption) (*File, error) {
 			for j := range g.columns {
 				c := &g.columns[j]
 
-				if offset := c.chunk.MetaData.BloomFilterOffset; offset > 0 {
+				if offset := c.chunk.MetaData.BloomFilterOffset; offset > 0 && c.decryption != nil {
+					if c.decryption.err != nil {
+						continue // the column key is not available
+					}
+					if c.bloomFilter, err = c.decryption.readBloomFilter(r, offset, f.size-offset); err != nil {
+						err = fmt.Errorf("reading bloom filter of column %d in row group %d: %w", j, i, err)
+						if f.skipCorrupted == nil {
+							return nil, err
+						}
+						f.reportCorrupted(i, j, -1, err)
+						c.bloomFilter = nil
+					}
+				} else if offset > 0 {
 					s.Seek(offset, io.SeekStart)
 					h = format.BloomFilterHeader{}
 					if err := d.Decode(&h); err != nil {
-						return nil, err
+						if f.skipCorrupted == nil {
+							return nil, err
+						}
+						f.reportCorrupted(i, j, -1, fmt.Errorf("reading bloom filter of column %d in row group %d: %w", j, i, err))
+						continue
 					}
 					offset, _ = s.Seek(0, io.SeekCurrent)
 					c.bloomFilter = newBloomFilter(r, offset, &h)
@@ -179,6 +302,11 @@ func (f *File) readPageIndex(section *bufferedSectionReader, decoder *thrift.Dec
 	numColumnChunks := len(f.metadata.RowGroups) * len(f.metadata.RowGroups[0].Columns)
 	columnIndexes := make([]format.ColumnIndex, 0, numColumnChunks)
 	offsetIndexes := make([]format.OffsetIndex, 0, numColumnChunks)
+
+	if f.decryption != nil {
+		return f.readEncryptedPageIndex(columnIndexes, offsetIndexes)
+	}
+
 	section.Reset(f.reader, indexOffset, indexLength)
 
 	for i := range f.metadata.RowGroups {
@@ -206,6 +334,62 @@ func (f *File) readPageIndex(section *bufferedSectionReader, decoder *thrift.Dec
 	return columnIndexes, offsetIndexes, nil
 }
 
+// readEncryptedPageIndex reads the page index of an encrypted file, where each
+// index of the encrypted columns is stored in its own module.
+func (f *File) readEncryptedPageIndex(columnIndexes []format.ColumnIndex, offsetIndexes []format.OffsetIndex) ([]format.ColumnIndex, []format.OffsetIndex, error) {
+	var buffer []byte
+
+	readIndex := func(v interface{}, moduleType byte, offset int64, length int32, rowGroup, column int) error {
+		if offset < 4 || length <= 0 || offset > f.size-int64(length) {
+			return fmt.Errorf("invalid offset and length of page index: %d+%d: %w", offset, length, ErrCorrupted)
+		}
+		if cap(buffer) < int(length) {
+			buffer = make([]byte, length)
+		}
+		buffer = buffer[:length]
+		if _, err := f.reader.ReadAt(buffer, offset); err != nil {
+			return err
+		}
+		b := buffer
+		if d := f.decryptors[rowGroup][column]; d != nil {
+			if d.err != nil {
+				// The column key is not available, leave the index empty;
+				// the error is reported when reading the column.
+				return nil
+			}
+			var err error
+			if b, err = d.decrypt(buffer, moduleType, -1); err != nil {
+				return err
+			}
+		}
+		return thrift.Unmarshal(&f.protocol, b, v)
+	}
+
+	for i := range f.metadata.RowGroups {
+		for j := range f.metadata.RowGroups[i].Columns {
+			chunk := &f.metadata.RowGroups[i].Columns[j]
+			columnIndexes = append(columnIndexes, format.ColumnIndex{})
+			n := len(columnIndexes) - 1
+			if err := readIndex(&columnIndexes[n], columnIndexModule, chunk.ColumnIndexOffset, chunk.ColumnIndexLength, i, j); err != nil {
+				return nil, nil, fmt.Errorf("reading column index %d of row group %d: %w", j, i, err)
+			}
+		}
+	}
+
+	for i := range f.metadata.RowGroups {
+		for j := range f.metadata.RowGroups[i].Columns {
+			chunk := &f.metadata.RowGroups[i].Columns[j]
+			offsetIndexes = append(offsetIndexes, format.OffsetIndex{})
+			n := len(offsetIndexes) - 1
+			if err := readIndex(&offsetIndexes[n], offsetIndexModule, chunk.OffsetIndexOffset, chunk.OffsetIndexLength, i, j); err != nil {
+				return nil, nil, fmt.Errorf("reading offset index %d of row group %d: %w", j, i, err)
+			}
+		}
+	}
+
+	return columnIndexes, offsetIndexes, nil
+}
+
 // NumRows returns the number of rows in the file.
 func (f *File) NumRows() int64 { return f.metadata.NumRows }
 
@@ -215,9 +399,259 @@ func (f *File) NumRowGroups() int { return len(f.rowGroups) }
 // RowGroup returns the row group at the given index in f.
 func (f *File) RowGroup(i int) RowGroup { return &f.rowGroups[i] }
 
+// RowGroups returns the list of row groups in f.
+//
+// Each row group exposes its schema, number of rows, column chunks, and a row
+// reader scoped to the group. The row groups are independent of each other,
+// programs can distribute them to multiple goroutines to process a file
+// concurrently, as long as the io.ReaderAt that the file was opened with
+// supports concurrent reads (which is the case of *os.File for example).
+//
+// The returned slice is allocated on each call, the program may modify it.
+func (f *File) RowGroups() []RowGroup {
+	rowGroups := make([]RowGroup, len(f.rowGroups))
+	for i := range f.rowGroups {
+		rowGroups[i] = &f.rowGroups[i]
+	}
+	return rowGroups
+}
+
+// RowGroupOffsets returns the cumulative row offsets of the row groups in f:
+// the element at index i is the global index of the first row of row group i,
+// and the last element is the total number of rows in the row groups.
+//
+// The returned slice has NumRowGroups()+1 elements, it is shared with f and
+// must be treated as read-only by the program.
+func (f *File) RowGroupOffsets() []int64 { return f.rowOffsets }
+
+// RowGroupForRow translates a global row index into the index of the row group
+// which contains it and the offset of the row within this row group.
+//
+// The method returns false if the row index is out of range.
+func (f *File) RowGroupForRow(rowIndex int64) (rowGroup int, offset int64, ok bool) {
+	if rowIndex < 0 || rowIndex >= f.rowOffsets[len(f.rowOffsets)-1] {
+		return -1, 0, false
+	}
+	rowGroup = sort.Search(len(f.rowGroups), func(i int) bool {
+		return f.rowOffsets[i+1] > rowIndex
+	})
+	return rowGroup, rowIndex - f.rowOffsets[rowGroup], true
+}
+
+// MayContain tests whether the column at the given path may contain value in
+// any of the row groups of f, using the bloom filters of the column chunks.
+//
+// The path is the dot-separated list of column names from the root of the
+// schema to the leaf column (e.g. "a.b.c"). The value must be of the kind of
+// the column type. Row groups without a bloom filter for the column may always
+// contain the value, as well as null values since they are not recorded in the
+// filters.
+//
+// The filters are read from the file the first time they are needed and kept
+// in memory, so that subsequent probes do not have to read them again.
+func (f *File) MayContain(columnPath string, value Value) (bool, error) {
+	found, err := f.MayContainValues(columnPath, []Value{value})
+	if err != nil {
+		return false, err
+	}
+	return found[0], nil
+}
+
+// MayContainValues is like MayContain but tests multiple values at once,
+// returning a slice where each element indicates whether the value at the same
+// index may be contained in the column.
+func (f *File) MayContainValues(columnPath string, values []Value) ([]bool, error) {
+	column, err := f.leafColumn(columnPath)
+	if err != nil {
+		return nil, err
+	}
+
+	kind := column.Type().Kind()
+	for _, v := range values {
+		if !v.IsNull() && v.Kind() != kind {
+			return nil, fmt.Errorf("cannot probe column %q of type %s with value of kind %s", columnPath, column.Type(), v.Kind())
+		}
+	}
+
+	found := make([]bool, len(values))
+	remain := len(values)
+
+	for i := range f.rowGroups {
+		if remain == 0 {
+			break
+		}
+		c := &f.rowGroups[i].columns[column.index]
+		filter, err := f.loadBloomFilter(c)
+		if err != nil {
+			return nil, fmt.Errorf("reading bloom filter of column %q in row group %d: %w", columnPath, i, err)
+		}
+		for j, v := range values {
+			if found[j] {
+				continue
+			}
+			if filter == nil || v.IsNull() {
+				found[j] = true
+			} else if found[j], err = filter.Check(v); err != nil {
+				return nil, err
+			}
+			if found[j] {
+				remain--
+			}
+		}
+	}
+
+	return found, nil
+}
+
+// ColumnStats carries the statistics of a column aggregated over all the row
+// groups of a file.
+type ColumnStats struct {
+	// Number of values in the column, including null values.
+	NumValues int64
+	// Number of null values in the column.
+	NullCount int64
+	// Lower and upper bounds of the non-null values of the column. The bounds
+	// are null if the column contains only null values, or if the bounds of
+	// some of the row groups are unknown.
+	//
+	// When the bounds are read from the column index, writers may have
+	// truncated the bounds of byte array columns, in which case the bounds
+	// may not be values that actually exist in the column.
+	MinValue Value
+	MaxValue Value
+}
+
+// ColumnStats returns statistics of the column at the given path merged over
+// all the row groups of f, which allows making decisions about the content of
+// the whole file without having to inspect each row group.
+//
+// The path is the dot-separated list of column names from the root of the
+// schema to the leaf column (e.g. "a.b.c").
+//
+// The null counts are read from the statistics of the column chunks, and so
+// are the bounds when the column chunks have them. Otherwise the bounds are
+// computed from the column index if the page index of the file was loaded, in
+// which case the bounds of byte array columns may be prefixes truncated by the
+// writer (see ColumnIndexSizeLimit) rather than values of the column. The
+// deprecated min and max fields of column chunk statistics are ignored since
+// their ordering may not match the ordering of the column type.
+func (f *File) ColumnStats(columnPath string) (ColumnStats, error) {
+	column, err := f.leafColumn(columnPath)
+	if err != nil {
+		return ColumnStats{}, err
+	}
+
+	typ := column.Type()
+	stats := ColumnStats{}
+	hasBounds := true
+
+	for i := range f.rowGroups {
+		c := &f.rowGroups[i].columns[column.index]
+		statistics := &c.chunk.MetaData.Statistics
+		numValues := c.NumValues()
+		nullCount := statistics.NullCount
+		minValue, maxValue := Value{}, Value{}
+
+		if statistics.MinValue != nil && statistics.MaxValue != nil {
+			if minValue, err = parseValue(typ.Kind(), statistics.MinValue); err != nil {
+				return ColumnStats{}, fmt.Errorf("reading min value of column %q in row group %d: %w", columnPath, i, err)
+			}
+			if maxValue, err = parseValue(typ.Kind(), statistics.MaxValue); err != nil {
+				return ColumnStats{}, fmt.Errorf("reading max value of column %q in row group %d: %w", columnPath, i, err)
+			}
+		} else if columnIndex := c.ColumnIndex(); columnIndex != nil {
+			for j, n := 0, columnIndex.NumPages(); j < n; j++ {
+				if !columnIndex.NullPage(j) {
+					minValue = minValueOf(typ, minValue, columnIndex.MinValue(j))
+					maxValue = maxValueOf(typ, maxValue, columnIndex.MaxValue(j))
+				}
+			}
+		}
+
+		stats.NumValues += numValues
+		stats.NullCount += nullCount
+
+		if minValue.IsNull() || maxValue.IsNull() {
+			// Row groups with non-null values but no bounds make the bounds
+			// of the whole file unknown.
+			if numValues > nullCount {
+				hasBounds = false
+			}
+			continue
+		}
+		stats.MinValue = minValueOf(typ, stats.MinValue, minValue)
+		stats.MaxValue = maxValueOf(typ, stats.MaxValue, maxValue)
+	}
+
+	if !hasBounds {
+		stats.MinValue, stats.MaxValue = Value{}, Value{}
+	}
+	return stats, nil
+}
+
+func minValueOf(typ Type, a, b Value) Value {
+	if a.IsNull() || (!b.IsNull() && typ.Compare(b, a) < 0) {
+		return b
+	}
+	return a
+}
+
+func maxValueOf(typ Type, a, b Value) Value {
+	if a.IsNull() || (!b.IsNull() && typ.Compare(b, a) > 0) {
+		return b
+	}
+	return a
+}
+
+func (f *File) leafColumn(columnPath string) (*Column, error) {
+	column := f.root
+	for _, name := range strings.Split(columnPath, ".") {
+		if column = column.Column(name); column == nil {
+			return nil, fmt.Errorf("column %q not found in parquet file", columnPath)
+		}
+	}
+	if column.index < 0 {
+		return nil, fmt.Errorf("column %q is not a leaf column", columnPath)
+	}
+	return column, nil
+}
+
+func (f *File) loadBloomFilter(c *fileColumnChunk) (*bloomFilter, error) {
+	if c.bloomFilter == nil {
+		return nil, nil
+	}
+
+	f.bloomFiltersMutex.Lock()
+	defer f.bloomFiltersMutex.Unlock()
+
+	if filter := f.bloomFilters[c]; filter != nil {
+		return filter, nil
+	}
+
+	data := make([]byte, c.bloomFilter.Size())
+	if n, err := c.bloomFilter.ReadAt(data, 0); n < len(data) {
+		return nil, err
+	}
+	filter := &bloomFilter{
+		SectionReader: *io.NewSectionReader(bytes.NewReader(data), 0, int64(len(data))),
+		hash:          c.bloomFilter.hash,
+		check:         c.bloomFilter.check,
+	}
+	if f.bloomFilters == nil {
+		f.bloomFilters = make(map[*fileColumnChunk]*bloomFilter)
+	}
+	f.bloomFilters[c] = filter
+	return filter, nil
+}
+
 // Root returns the root column of f.
 func (f *File) Root() *Column { return f.root }
 
+// Metadata returns the metadata of f, as decoded from the file footer.
+//
+// The returned value must be treated as read-only by the program.
+func (f *File) Metadata() *format.FileMetaData { return &f.metadata }
+
 // Size returns the size of f (in bytes).
 func (f *File) Size() int64 { return f.size }
 
@@ -260,16 +694,104 @@ func (f *File) Lookup(key string) (value string, ok bool) {
 	return lookupKeyValueMetadata(f.metadata.KeyValueMetadata, key)
 }
 
+// openZstdDictionaries configures the column chunks compressed with zstd
+// dictionaries to decompress pages with the dictionaries passed to the
+// ZstdDictionaries option.
+func (f *File) openZstdDictionaries() error {
+	for i := range f.rowGroups {
+		for j := range f.rowGroups[i].columns {
+			c := &f.rowGroups[i].columns[j]
+			id, err := zstdDictionaryIDOfColumnChunk(c.chunk)
+			if err != nil {
+				return fmt.Errorf("opening column %d in row group %d: %w", j, i, err)
+			}
+			if id == 0 {
+				continue
+			}
+			pool := f.zstdDictionaries[id]
+			if pool == nil {
+				return fmt.Errorf("column %d in row group %d is compressed with zstd dictionary %d, which must be passed to the ZstdDictionaries option", j, i, id)
+			}
+			c.decompressors, c.zstdDictionaryID = pool, id
+		}
+	}
+	return nil
+}
+
+// resolveCodecs consults the codec resolver of the file configuration for the
+// codecs of column chunks which are not supported by the package. The codecs
+// are all resolved when opening the file so the decompressors can then be
+// looked up concurrently by the goroutines reading columns of the file.
+func (f *File) resolveCodecs() {
+	if f.resolveCodec == nil {
+		return
+	}
+	for i := range f.metadata.RowGroups {
+		for j := range f.metadata.RowGroups[i].Columns {
+			codec := f.metadata.RowGroups[i].Columns[j].MetaData.Codec
+			if _, ok := f.resolvedCodecs[codec]; ok {
+				continue
+			}
+			pool := compressedPageReaderPoolOf(codec)
+			if _, unsupported := pool.codec.(*unsupported); !unsupported {
+				continue
+			}
+			if c := f.resolveCodec(codec); c != nil {
+				pool = &compressedPageReaderPool{codec: c}
+			}
+			if f.resolvedCodecs == nil {
+				f.resolvedCodecs = make(map[format.CompressionCodec]*compressedPageReaderPool)
+			}
+			f.resolvedCodecs[codec] = pool
+		}
+	}
+}
+
+// compressedPageReaderPoolOf returns the pool of readers decompressing pages
+// with codec, using the codecs of the file configuration resolved when the
+// file was opened for codecs that are not supported by the package.
+func (f *File) compressedPageReaderPoolOf(codec format.CompressionCodec) *compressedPageReaderPool {
+	if pool, ok := f.resolvedCodecs[codec]; ok {
+		return pool
+	}
+	return compressedPageReaderPoolOf(codec)
+}
+
 func (f *File) hasIndexes() bool {
 	return f.columnIndexes != nil && f.offsetIndexes != nil
 }
 
+func (f *File) reportCorrupted(rowGroup, column, page int, err error) {
+	f.skipCorrupted(&CorruptedDataError{
+		RowGroup: rowGroup,
+		Column:   column,
+		Page:     page,
+		Err:      err,
+	})
+}
+
 var (
 	_ io.ReaderAt = (*File)(nil)
 
 	bufferedSectionReaderPool sync.Pool
+
+	// Pool of buffers holding the compressed data of pages read from files.
+	pageDataPool sync.Pool
 )
 
+func acquirePageData() []byte {
+	if b, _ := pageDataPool.Get().(*[]byte); b != nil {
+		return (*b)[:0]
+	}
+	return []byte{}
+}
+
+func releasePageData(data []byte) {
+	if cap(data) > 0 {
+		pageDataPool.Put(&data)
+	}
+}
+
 type bufferedSectionReader struct {
 	section io.SectionReader
 	bufio.Reader
@@ -331,24 +853,32 @@ type fileRowGroup struct {
 	sorting  []SortingColumn
 }
 
-func (g *fileRowGroup) init(file *File, schema *Schema, columns []*Column, rowGroup *format.RowGroup) {
+func (g *fileRowGroup) init(file *File, schema *Schema, columns []*Column, index int, rowGroup *format.RowGroup) {
 	g.schema = schema
 	g.rowGroup = rowGroup
 	g.columns = make([]fileColumnChunk, len(rowGroup.Columns))
 	g.sorting = make([]SortingColumn, len(rowGroup.SortingColumns))
 
+	// The leaf columns are ordered by name, which may differ from the order
+	// that column chunks were written in the file, so the chunks are looked up
+	// from the columns instead of using the row group metadata directly.
 	for i := range g.columns {
 		c := fileColumnChunk{
-			file:     file,
-			column:   columns[i],
-			rowGroup: rowGroup,
-			chunk:    &rowGroup.Columns[i],
+			file:          file,
+			column:        columns[i],
+			rowGroup:      rowGroup,
+			rowGroupIndex: index,
+			chunk:         columns[i].chunks[index],
+			decompressors: file.compressedPageReaderPoolOf(columns[i].chunks[index].MetaData.Codec),
 		}
 
 		if file.hasIndexes() {
-			j := (int(rowGroup.Ordinal) * len(columns)) + i
-			c.columnIndex = &file.columnIndexes[j]
-			c.offsetIndex = &file.offsetIndexes[j]
+			c.columnIndex = columns[i].columnIndex[index]
+			c.offsetIndex = columns[i].offsetIndex[index]
+		}
+
+		if file.decryption != nil {
+			c.decryption = file.decryptors[index][columns[i].chunkIndex]
 		}
 
 		g.columns[i] = c
@@ -363,6 +893,18 @@ func (g *fileRowGroup) init(file *File, schema *Schema, columns []*Column, rowGr
 	}
 }
 
+// withContext returns a copy of g where the pages of column chunks check the
+// context before reading each page, and before each read from the file.
+func (g *fileRowGroup) withContext(context *readContext) *fileRowGroup {
+	c := *g
+	c.columns = make([]fileColumnChunk, len(g.columns))
+	for i := range g.columns {
+		c.columns[i] = g.columns[i]
+		c.columns[i].context = context
+	}
+	return &c
+}
+
 func (g *fileRowGroup) Schema() *Schema                 { return g.schema }
 func (g *fileRowGroup) NumRows() int64                  { return g.rowGroup.NumRows }
 func (g *fileRowGroup) NumColumns() int                 { return len(g.columns) }
@@ -381,13 +923,22 @@ func (s *fileSortingColumn) Descending() bool { return s.descending }
 func (s *fileSortingColumn) NullsFirst() bool { return s.nullsFirst }
 
 type fileColumnChunk struct {
-	file        *File
-	column      *Column
-	bloomFilter *bloomFilter
-	rowGroup    *format.RowGroup
-	columnIndex *format.ColumnIndex
-	offsetIndex *format.OffsetIndex
-	chunk       *format.ColumnChunk
+	file          *File
+	column        *Column
+	bloomFilter   *bloomFilter
+	rowGroup      *format.RowGroup
+	rowGroupIndex int
+	columnIndex   *format.ColumnIndex
+	offsetIndex   *format.OffsetIndex
+	chunk         *format.ColumnChunk
+	decryption    *columnDecryptor
+	// The decompressors of the column chunk pages, and the identifier of the
+	// zstd dictionary that the pages were compressed with, if any.
+	decompressors    *compressedPageReaderPool
+	zstdDictionaryID uint32
+	// The context of the reads in progress on the Reader that the column chunk
+	// was bound to, nil if it was not bound to a reader.
+	context *readContext
 }
 
 func (c *fileColumnChunk) Type() Type {
@@ -410,14 +961,23 @@ func (c *fileColumnChunk) setPagesOn(r *filePages) {
 		column:     c.column,
 		columnType: c.column.Type(),
 		codec:      c.chunk.MetaData.Codec,
+		zstdDict:   c.zstdDictionaryID,
+		decompress: c.decompressors,
+		strict:     c.file.strict,
+		observer:   c.file.observer,
 	}
+	r.numValues, r.seeked = 0, false
 	r.baseOffset = c.chunk.MetaData.DataPageOffset
 	r.dataOffset = r.baseOffset
 	if c.chunk.MetaData.DictionaryPageOffset != 0 {
 		r.baseOffset = c.chunk.MetaData.DictionaryPageOffset
 		r.dictOffset = r.baseOffset
 	}
-	r.section = io.NewSectionReader(c.file, r.baseOffset, c.chunk.MetaData.TotalCompressedSize)
+	var file io.ReaderAt = c.file
+	if c.context != nil {
+		file = &contextReaderAt{reader: file, context: c.context}
+	}
+	r.section = io.NewSectionReader(file, r.baseOffset, c.chunk.MetaData.TotalCompressedSize)
 	r.rbuf = bufio.NewReaderSize(r.section, defaultReadBufferSize)
 	r.section.Seek(r.dataOffset-r.baseOffset, io.SeekStart)
 	r.decoder.Reset(r.protocol.NewReader(r.rbuf))
@@ -448,6 +1008,91 @@ func (c *fileColumnChunk) NumValues() int64 {
 	return c.chunk.MetaData.NumValues
 }
 
+func (c *fileColumnChunk) dictionary() (Dictionary, error) {
+	r := new(filePages)
+	defer r.Close()
+	c.setPagesOn(r)
+	if r.dictOffset == 0 {
+		return nil, nil
+	}
+	if err := r.readDictionary(); err != nil {
+		return nil, err
+	}
+	return r.dictionary, nil
+}
+
+func (c *fileColumnChunk) pageInfos() *filePageInfoReader {
+	r := new(filePageInfoReader)
+	c.setPagesOn(&r.pages)
+	// Start from the dictionary page, if any.
+	r.pages.section.Seek(0, io.SeekStart)
+	r.pages.rbuf.Reset(r.pages.section)
+	return r
+}
+
+type filePageInfoReader struct {
+	pages    filePages
+	numPages int
+}
+
+func (r *filePageInfoReader) ReadPageInfo() (PageInfo, error) {
+	p := &r.pages
+	c := p.column
+	dictionary := r.numPages == 0 && p.dictOffset != 0
+
+	offset := r.offset()
+	header := new(format.PageHeader)
+	if err := p.decodePageHeader(header, dictionary); err != nil {
+		if err != io.EOF {
+			err = fmt.Errorf("decoding header of page %d of column %q: %w", p.page.index, columnPath(c.column.Path()), err)
+		}
+		return PageInfo{}, err
+	}
+	headerSize := r.offset() - offset
+
+	if _, err := p.rbuf.Discard(int(header.CompressedPageSize)); err != nil {
+		return PageInfo{}, fmt.Errorf("skipping data of page %d of column %q: %w", p.page.index, columnPath(c.column.Path()), err)
+	}
+	r.numPages++
+
+	info := PageInfo{
+		Header:           pageHeaderOf(header),
+		Offset:           offset,
+		HeaderSize:       headerSize,
+		CompressedSize:   int64(header.CompressedPageSize),
+		UncompressedSize: int64(header.UncompressedPageSize),
+		CRC:              uint32(header.CRC),
+	}
+
+	if !dictionary {
+		// The page index is the ordinal of data pages used to decrypt the
+		// headers of encrypted columns.
+		p.page.index++
+
+		if stats := pageStatistics(header); stats != nil {
+			var err error
+			kind := c.column.Type().Kind()
+			if stats.MinValue != nil {
+				if info.MinValue, err = parseValue(kind, stats.MinValue); err != nil {
+					return info, fmt.Errorf("reading min value of page %d of column %q: %w", p.page.index-1, columnPath(c.column.Path()), err)
+				}
+			}
+			if stats.MaxValue != nil {
+				if info.MaxValue, err = parseValue(kind, stats.MaxValue); err != nil {
+					return info, fmt.Errorf("reading max value of page %d of column %q: %w", p.page.index-1, columnPath(c.column.Path()), err)
+				}
+			}
+		}
+	}
+
+	return info, nil
+}
+
+func (r *filePageInfoReader) offset() int64 {
+	position, _ := r.pages.section.Seek(0, io.SeekCurrent)
+	return r.pages.baseOffset + position - int64(r.pages.rbuf.Buffered())
+}
+
 type filePages struct {
 	column     *fileColumnChunk
 	protocol   thrift.CompactProtocol
@@ -461,19 +1106,55 @@ type filePages struct {
 
 	// This buffer holds compressed pages in memory when they are read; we need
 	// to read whole pages because we have to compute the checksum prior to
-	// exposing the page to the application.
+	// exposing the page to the application. The buffer is acquired from a pool
+	// when the first page is read and returned to it when r is closed, so it
+	// is reused across column chunks and files.
 	compressedPageData []byte
 
 	page filePage
 	skip int64
+	trim bool
+
+	// The dictionary of the column chunk, it is only set on pages which are
+	// dictionary encoded since writers may fall back to other encodings in the
+	// same column chunk (e.g. when the dictionary grew too large).
+	dictionary     Dictionary
+	dictionaryType Type
+
+	// Buffer used to find row boundaries when seeking in v1 data pages of
+	// repeated columns.
+	values []Value
+
+	// Buffer holding the encrypted page headers of encrypted columns.
+	encryptedPageHeader []byte
+
+	// Number of values read from the data pages, compared to the column chunk
+	// metadata in strict mode when the reader was not seeked past the first
+	// row.
+	numValues int64
+	seeked    bool
+
+	// State used to skip corrupted pages when the SkipCorrupted option was
+	// set on the file: dataRead indicates whether the data of the last page
+	// was fully read, and corrupted is set when the remaining pages of the
+	// column chunk cannot be located.
+	dataRead  bool
+	corrupted bool
+
+	// Memory reserved on the budget of the file for the buffers of the last
+	// page that was read and for the dictionary, when the MemoryLimit option
+	// was set.
+	pageMemory       int64
+	dictionaryMemory int64
 }
 
-func (r *filePages) readPage() (*filePage, error) {
+func (r *filePages) readPage(dictionary bool) (*filePage, error) {
 	h := &r.page.header
 	h.Type = 0
 	h.UncompressedPageSize = 0
 	h.CompressedPageSize = 0
 	h.CRC = 0
+	r.dataRead = false
 
 	if h.DataPageHeader != nil {
 		*h.DataPageHeader = format.DataPageHeader{}
@@ -488,18 +1169,25 @@ func (r *filePages) readPage() (*filePage, error) {
 		*h.DataPageHeaderV2 = format.DataPageHeaderV2{}
 	}
 
-	if err := r.decoder.Decode(h); err != nil {
+	if err := r.decodePageHeader(h, dictionary); err != nil {
 		if err != io.EOF {
 			err = fmt.Errorf("decoding page header: %w", err)
 		}
 		if r.page.values != nil {
-			r.page.values.release()
+			releaseFilePageValueReaderState(r.page.values)
 			r.page.values = nil
 		}
 		return nil, err
 	}
 
+	if err := r.reserveMemory(dictionary); err != nil {
+		return nil, err
+	}
+
 	compressedPageSize := int(r.page.header.CompressedPageSize)
+	if r.compressedPageData == nil {
+		r.compressedPageData = acquirePageData()
+	}
 	if cap(r.compressedPageData) < compressedPageSize {
 		r.compressedPageData = make([]byte, compressedPageSize)
 	} else {
@@ -510,6 +1198,7 @@ func (r *filePages) readPage() (*filePage, error) {
 	if err != nil {
 		return nil, fmt.Errorf("reading page %d of column %q", r.page.index, r.page.columnPath())
 	}
+	r.dataRead = true
 
 	if r.page.header.CRC != 0 {
 		headerChecksum := uint32(r.page.header.CRC)
@@ -521,9 +1210,8 @@ func (r *filePages) readPage() (*filePage, error) {
 			// be practical. Depending on how the pages are consumed,
 			// missing rows may cause unpredictable behaviors in algorithms.
 			//
-			// For now, we assume these errors to be fatal, but we may
-			// revisit later and improve error handling to be more resilient
-			// to data corruption.
+			// These errors are fatal by default, programs can opt into
+			// skipping the corrupted pages with the SkipCorrupted option.
 			return nil, fmt.Errorf("crc32 checksum mismatch in page %d of column %q: 0x%08X != 0x%08X: %w",
 				r.page.index,
 				r.page.columnPath(),
@@ -534,16 +1222,88 @@ func (r *filePages) readPage() (*filePage, error) {
 		}
 	}
 
-	r.page.data.Reset(r.compressedPageData)
+	pageData := r.compressedPageData
+	if d := r.column.decryption; d != nil {
+		moduleType, pageOrdinal := byte(dataPageModule), r.page.index
+		if dictionary {
+			moduleType, pageOrdinal = dictionaryPageModule, -1
+		}
+		pageData, err = d.decrypt(pageData, moduleType, pageOrdinal)
+		if err != nil {
+			return nil, fmt.Errorf("decrypting page %d of column %q: %w", r.page.index, r.page.columnPath(), err)
+		}
+		// The page is exposed in its decrypted form, the header is updated to
+		// describe it since the size and checksum applied to the encrypted
+		// module.
+		h.CompressedPageSize = int32(len(pageData))
+		h.CRC = 0
+	}
+
+	r.page.data.Reset(pageData)
+
+	if !dictionary {
+		r.page.dictionary, r.page.columnType = nil, r.page.column.Type()
+		if r.dictionary != nil && isDictionaryFormat(r.page.PageHeader().Encoding()) {
+			r.page.dictionary, r.page.columnType = r.dictionary, r.dictionaryType
+		}
+	}
 
 	if r.column.columnIndex != nil {
 		err = r.page.parseColumnIndex(r.column.columnIndex)
 	} else {
 		err = r.page.parseStatistics()
 	}
+	if err == nil && r.page.strict && !dictionary {
+		err = r.page.validateBounds()
+	}
 	return &r.page, err
 }
 
+// reserveMemory reserves the memory needed to read the page whose header was
+// just decoded on the budget of the file, in place of the memory reserved for
+// the previous page.
+func (r *filePages) reserveMemory(dictionary bool) error {
+	m := r.column.file.memory
+	if m == nil {
+		return nil
+	}
+	h := &r.page.header
+	size := int64(h.CompressedPageSize) + int64(h.UncompressedPageSize)
+	if inUse, ok := m.reserve(&r.pageMemory, size); !ok {
+		page := r.page.index
+		if dictionary {
+			page = -1
+		}
+		return &MemoryLimitError{
+			Limit:    m.limit,
+			InUse:    inUse,
+			Size:     size,
+			RowGroup: r.column.rowGroupIndex,
+			Column:   r.column.Column(),
+			Page:     page,
+		}
+	}
+	return nil
+}
+
+func (r *filePages) decodePageHeader(h *format.PageHeader, dictionary bool) (err error) {
+	d := r.column.decryption
+	if d == nil {
+		return r.decoder.Decode(h)
+	}
+	moduleType, pageOrdinal := byte(dataPageHeaderModule), r.page.index
+	if dictionary {
+		moduleType, pageOrdinal = dictionaryPageHeaderModule, -1
+	}
+	var b []byte
+	limit := r.column.chunk.MetaData.TotalCompressedSize
+	b, r.encryptedPageHeader, err = d.readModule(r.rbuf, r.encryptedPageHeader, limit, moduleType, pageOrdinal)
+	if err != nil {
+		return err
+	}
+	return thrift.Unmarshal(&r.protocol, b, h)
+}
+
 func (r *filePages) readDictionary() error {
 	currentOffset, _ := r.section.Seek(0, io.SeekCurrent)
 	defer func() {
@@ -556,14 +1316,14 @@ func (r *filePages) readDictionary() error {
 	}
 	r.rbuf.Reset(r.section)
 
-	p, err := r.readPage()
+	p, err := r.readPage(true)
 	if err != nil {
 		return err
 	}
 
-	page := acquireCompressedPageReader(p.codec, &p.data)
+	page := acquireCompressedPageReader(p.decompress, &p.data)
 	enc := r.page.header.DictionaryPageHeader.Encoding
-	dec := LookupEncoding(enc).NewDecoder(page)
+	dec := LookupEncoding(enc).NewDecoder(observeDecompression(r.page.observer, p.codec, page))
 
 	columnIndex := r.column.Column()
 	numValues := int(p.NumValues())
@@ -573,28 +1333,69 @@ func (r *filePages) readDictionary() error {
 	if err != nil {
 		return fmt.Errorf("reading dictionary of column %q: %w", p.columnPath(), err)
 	}
-	r.page.dictionary = dict
-	r.page.columnType = dict.Type()
+	r.dictionary = dict
+	r.dictionaryType = dict.Type()
+	// The dictionary retains the memory reserved for its page, the next page
+	// read makes a new reservation.
+	r.dictionaryMemory, r.pageMemory = r.pageMemory, 0
 	return nil
 }
 
 func (r *filePages) ReadPage() (Page, error) {
-	if r.page.dictionary == nil && r.dictOffset > 0 {
+	if r.corrupted {
+		return nil, io.EOF
+	}
+	if r.dictionary == nil && r.dictOffset > 0 {
 		if err := r.readDictionary(); err != nil {
-			return nil, err
+			if r.column.file.skipCorrupted == nil || errors.Is(err, ErrMemoryLimitExceeded) || r.column.context.err() != nil {
+				return nil, err
+			}
+			r.column.file.reportCorrupted(r.column.rowGroupIndex, r.column.Column(), -1, err)
+			r.corrupted = true
+			return nil, io.EOF
 		}
 	}
 	for {
-		p, err := r.readPage()
+		// The context is checked before each page, including the pages which
+		// are skipped when seeking, so long scans can be cancelled.
+		if err := r.column.context.err(); err != nil {
+			return nil, err
+		}
+		p, err := r.readPage(false)
 		if err != nil {
+			if err != io.EOF && r.column.file.skipCorrupted != nil && !errors.Is(err, ErrMemoryLimitExceeded) && r.column.context.err() == nil {
+				if r.skipCorruptedPage(err) {
+					continue
+				}
+				err = io.EOF
+			}
+			if err == io.EOF && r.page.strict && !r.seeked {
+				if verr := r.validateNumValues(); verr != nil {
+					err = verr
+				}
+			}
 			return nil, err
 		}
 		p.index++
-		if r.skip == 0 {
+		r.numValues += p.NumValues()
+		if r.skip == 0 && !r.trim {
+			r.observePage(p)
 			return p, nil
 		}
+		if p.header.Type == format.DataPage && p.column.maxRepetitionLevel > 0 {
+			page, err := r.seekRepeatedPageV1(p)
+			if page != nil {
+				r.observePage(p)
+			}
+			if page != nil || err != nil {
+				return page, err
+			}
+			r.observePagesSkipped(1)
+			continue
+		}
 		numRows := p.NumRows()
 		if numRows > r.skip {
+			r.observePage(p)
 			seek := r.skip
 			r.skip = 0
 			if seek > 0 {
@@ -603,13 +1404,89 @@ func (r *filePages) ReadPage() (Page, error) {
 			return p, nil
 		}
 		r.skip -= numRows
+		r.observePagesSkipped(1)
 	}
 }
 
+func (r *filePages) observePage(p *filePage) {
+	if o := p.observer; o != nil && o.OnPage != nil {
+		o.OnPage(ReaderPageStats{
+			RowGroup:         r.column.rowGroupIndex,
+			Column:           r.column.Column(),
+			Path:             p.column.Path(),
+			PageType:         p.header.Type,
+			Encoding:         p.PageHeader().Encoding(),
+			NumValues:        p.NumValues(),
+			CompressedSize:   int64(p.header.CompressedPageSize),
+			UncompressedSize: int64(p.header.UncompressedPageSize),
+		})
+	}
+}
+
+func (r *filePages) observePagesSkipped(numPages int) {
+	if o := r.page.observer; o != nil && o.OnPagesSkipped != nil && numPages > 0 {
+		o.OnPagesSkipped(r.page.column.Path(), numPages)
+	}
+}
+
+// seekRepeatedPageV1 positions the reader on the row that it was seeked to in
+// p, which must be a v1 data page of a repeated column.
+//
+// The headers of v1 data pages do not record the number of rows they contain,
+// and rows may span multiple pages, so the repetition levels must be decoded
+// to find where rows start. The method returns a nil page if the row does not
+// start in p.
+func (r *filePages) seekRepeatedPageV1(p *filePage) (Page, error) {
+	values := r.values[:0]
+	defer func() {
+		clearValues(values)
+		r.values = values[:0]
+	}()
+
+	reader := p.Values()
+	for {
+		if len(values) == cap(values) {
+			values = append(values, make([]Value, defaultValueBufferSize)...)[:len(values)]
+		}
+		n, err := reader.ReadValues(values[len(values):cap(values)])
+		values = values[:len(values)+n]
+		if err != nil {
+			if err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	numRows := int64(0)
+	for i, v := range values {
+		if v.repetitionLevel == 0 {
+			if numRows == r.skip {
+				r.skip, r.trim = 0, false
+				c := p.column
+				b := newRepeatedColumnBuffer(c.Type().NewColumnBuffer(p.Column(), len(values)-i), c.maxRepetitionLevel, c.maxDefinitionLevel, nullsGoLast)
+				if _, err := b.WriteValues(values[i:]); err != nil {
+					return nil, err
+				}
+				return b.Page(), nil
+			}
+			numRows++
+		}
+	}
+
+	// The next page may start with the end of the last row of this page,
+	// which must be trimmed since it belongs to a row that was skipped.
+	r.skip -= numRows
+	r.trim = true
+	return nil, nil
+}
+
 func (r *filePages) SeekToRow(rowIndex int64) (err error) {
+	r.numValues, r.seeked = 0, rowIndex != 0
 	if r.column.offsetIndex == nil {
 		_, err = r.section.Seek(r.dataOffset-r.baseOffset, io.SeekStart)
 		r.skip = rowIndex
+		r.trim = false
 		r.page.index = 0
 	} else {
 		pages := r.column.offsetIndex.PageLocations
@@ -621,24 +1498,120 @@ func (r *filePages) SeekToRow(rowIndex int64) (err error) {
 		}
 		_, err = r.section.Seek(pages[index].Offset-r.baseOffset, io.SeekStart)
 		r.skip = rowIndex - pages[index].FirstRowIndex
+		r.trim = false
+		r.observePagesSkipped(index - r.page.index)
 		r.page.index = index
 	}
 	r.rbuf.Reset(r.section)
 	return err
 }
 
+// Close releases the buffers held by r, including the decompressor of the
+// current page. The reader must not be used after being closed, and the data
+// of the last page read from it must not be retained since its buffer may be
+// reused to read pages of other column chunks and files.
+func (r *filePages) Close() error {
+	if r.page.values != nil {
+		releaseFilePageValueReaderState(r.page.values)
+		r.page.values = nil
+	}
+	releasePageData(r.compressedPageData)
+	r.compressedPageData = nil
+	r.encryptedPageHeader = nil
+	r.corrupted = true // makes ReadPage return io.EOF
+	if m := r.column.file.memory; m != nil {
+		m.release(&r.pageMemory)
+		m.release(&r.dictionaryMemory)
+	}
+	return nil
+}
+
+// memoryBudget tracks the memory held by the readers of a file configured with
+// the MemoryLimit option. Column chunks may be read from multiple goroutines,
+// so the memory in use is updated atomically.
+type memoryBudget struct {
+	inUse int64 // first field for 64 bits alignment of atomic operations
+	limit int64
+}
+
+// reserve replaces the amount of memory held in *reserved by size, returning
+// false and the memory in use if the limit would be exceeded.
+func (m *memoryBudget) reserve(reserved *int64, size int64) (int64, bool) {
+	delta := size - *reserved
+	for {
+		inUse := atomic.LoadInt64(&m.inUse)
+		if delta > 0 && inUse+delta > m.limit {
+			return inUse, false
+		}
+		if atomic.CompareAndSwapInt64(&m.inUse, inUse, inUse+delta) {
+			*reserved = size
+			return inUse + delta, true
+		}
+	}
+}
+
+func (m *memoryBudget) release(reserved *int64) {
+	atomic.AddInt64(&m.inUse, -*reserved)
+	*reserved = 0
+}
+
+// skipCorruptedPage reports the error that occurred when reading the current
+// page and positions r on the next page. The method returns false if the next
+// page could not be located, in which case the remaining pages of the column
+// chunk are skipped.
+func (r *filePages) skipCorruptedPage(err error) bool {
+	index := r.page.index
+	r.column.file.reportCorrupted(r.column.rowGroupIndex, r.column.Column(), index, err)
+	// The values of skipped pages are missing from the column chunk, so the
+	// number of values cannot be verified in strict mode anymore.
+	r.seeked = true
+
+	if r.column.offsetIndex != nil {
+		pages := r.column.offsetIndex.PageLocations
+		next := index + 1
+		if next >= len(pages) {
+			r.corrupted = true
+			return false
+		}
+		if _, err := r.section.Seek(pages[next].Offset-r.baseOffset, io.SeekStart); err != nil {
+			r.corrupted = true
+			return false
+		}
+		r.rbuf.Reset(r.section)
+		if index >= 0 && r.skip > 0 {
+			r.skip -= pages[next].FirstRowIndex - pages[index].FirstRowIndex
+			if r.skip < 0 {
+				r.skip = 0
+			}
+		}
+		r.page.index = next
+		return true
+	}
+
+	if !r.dataRead {
+		r.corrupted = true
+		return false
+	}
+	r.page.index++
+	return true
+}
+
 type filePage struct {
 	column     *Column
 	columnType Type
 	dictionary Dictionary
 
-	codec  format.CompressionCodec
-	header format.PageHeader
-	data   bytes.Reader
+	codec      format.CompressionCodec
+	zstdDict   uint32
+	decompress *compressedPageReaderPool
+	header     format.PageHeader
+	data       bytes.Reader
 
 	index    int
 	minValue Value
 	maxValue Value
+	strict   bool
+	observer *ReaderObserver
 
 	// This field caches the state used when reading values from the page.
 	// We allocate it separately to avoid creating it if the Values method
@@ -656,11 +1629,15 @@ var (
 )
 
 func (p *filePage) statistics() *format.Statistics {
-	switch p.header.Type {
+	return pageStatistics(&p.header)
+}
+
+func pageStatistics(h *format.PageHeader) *format.Statistics {
+	switch h.Type {
 	case format.DataPageV2:
-		return &p.header.DataPageHeaderV2.Statistics
+		return &h.DataPageHeaderV2.Statistics
 	case format.DataPage:
-		return &p.header.DataPageHeader.Statistics
+		return &h.DataPageHeader.Statistics
 	default:
 		return nil
 	}
@@ -766,6 +1743,12 @@ func (p *filePage) NumRows() int64 {
 	switch p.header.Type {
 	case format.DataPageV2:
 		return int64(p.header.DataPageHeaderV2.NumRows)
+	case format.DataPage:
+		// Without repetition levels, each value is a row.
+		if p.column.maxRepetitionLevel == 0 {
+			return int64(p.header.DataPageHeader.NumValues)
+		}
+		return 0
 	default:
 		return 0
 	}
@@ -805,16 +1788,31 @@ func (p *filePage) Size() int64 {
 
 func (p *filePage) Values() ValueReader {
 	if p.values == nil {
-		p.values = new(filePageValueReaderState)
+		p.values = acquireFilePageValueReaderState()
+	}
+	if p.values.dictionary != p.dictionary {
+		// The column reader depends on whether the page is dictionary encoded.
+		p.values.dictionary = p.dictionary
+		p.values.reader = nil
 	}
-	if err := p.values.init(p.columnType, p.column, p.codec, p.PageHeader(), &p.data); err != nil {
+	p.values.observer = p.observer
+	if err := p.values.init(p.columnType, p.column, p.codec, p.decompress, p.PageHeader(), &p.data); err != nil {
 		return &errorValueReader{err: err}
 	}
+	if p.strict {
+		return newStrictValueReader(p.values.reader, p)
+	}
 	return p.values.reader
 }
 
 func (p *filePage) Buffer() BufferedPage {
 	bufferedPage := p.column.Type().NewColumnBuffer(p.Column(), int(p.Size()))
+	switch {
+	case p.column.maxRepetitionLevel > 0:
+		bufferedPage = newRepeatedColumnBuffer(bufferedPage, p.column.maxRepetitionLevel, p.column.maxDefinitionLevel, nullsGoLast)
+	case p.column.maxDefinitionLevel > 0:
+		bufferedPage = newOptionalColumnBuffer(bufferedPage, p.column.maxDefinitionLevel, nullsGoLast)
+	}
 	_, err := CopyValues(bufferedPage, p.Values())
 	if err != nil {
 		return &errorPage{err: err, columnIndex: p.Column()}
@@ -823,15 +1821,19 @@ func (p *filePage) Buffer() BufferedPage {
 }
 
 func (p *filePage) PageHeader() PageHeader {
-	switch p.header.Type {
+	return pageHeaderOf(&p.header)
+}
+
+func pageHeaderOf(h *format.PageHeader) PageHeader {
+	switch h.Type {
 	case format.DataPageV2:
-		return DataPageHeaderV2{p.header.DataPageHeaderV2}
+		return DataPageHeaderV2{h.DataPageHeaderV2}
 	case format.DataPage:
-		return DataPageHeaderV1{p.header.DataPageHeader}
+		return DataPageHeaderV1{h.DataPageHeader}
 	case format.DictionaryPage:
-		return DictionaryPageHeader{p.header.DictionaryPageHeader}
+		return DictionaryPageHeader{h.DictionaryPageHeader}
 	default:
-		return unknownPageHeader{&p.header}
+		return unknownPageHeader{h}
 	}
 }
 
@@ -841,8 +1843,14 @@ func (p *filePage) PageSize() int64 { return int64(p.header.CompressedPageSize)
 
 func (p *filePage) CRC() uint32 { return uint32(p.header.CRC) }
 
+func (p *filePage) compressionCodec() format.CompressionCodec { return p.codec }
+
+func (p *filePage) zstdDictionaryID() uint32 { return p.zstdDict }
+
 type filePageValueReaderState struct {
-	reader ColumnReader
+	reader     ColumnReader
+	dictionary Dictionary
+	observer   *ReaderObserver
 
 	v1 struct {
 		repetitions dataPageLevelV1
@@ -878,7 +1886,34 @@ func (s *filePageValueReaderState) release() {
 	}
 }
 
-func (s *filePageValueReaderState) init(columnType Type, column *Column, codec format.CompressionCodec, header PageHeader, data *bytes.Reader) (err error) {
+// The states of page value readers are pooled so the decoders and the buffers
+// holding the decompressed levels of pages are reused across column chunks and
+// files instead of being allocated for every column chunk that gets read.
+var filePageValueReaderStatePool sync.Pool
+
+func acquireFilePageValueReaderState() *filePageValueReaderState {
+	s, _ := filePageValueReaderStatePool.Get().(*filePageValueReaderState)
+	if s == nil {
+		s = new(filePageValueReaderState)
+	}
+	return s
+}
+
+func releaseFilePageValueReaderState(s *filePageValueReaderState) {
+	s.release()
+	// The column reader depends on the type of the column, it is recreated
+	// when the state is used to read the pages of another column.
+	s.reader = nil
+	s.dictionary = nil
+	s.observer = nil
+	s.v1.repetitions.reset()
+	s.v1.definitions.reset()
+	s.v2.repetitions.reset()
+	s.v2.definitions.reset()
+	filePageValueReaderStatePool.Put(s)
+}
+
+func (s *filePageValueReaderState) init(columnType Type, column *Column, codec format.CompressionCodec, decompress *compressedPageReaderPool, header PageHeader, data *bytes.Reader) (err error) {
 	var repetitionLevels io.Reader
 	var definitionLevels io.Reader
 	var pageHeader DataPageHeader
@@ -891,8 +1926,8 @@ func (s *filePageValueReaderState) init(columnType Type, column *Column, codec f
 			return fmt.Errorf("initializing v2 reader for page of column %q: %w", columnPath(column.Path()), err)
 		}
 		if h.IsCompressed(codec) {
-			s.page.compressed = makeCompressedPage(s.page.compressed, codec, data)
-			pageData = s.page.compressed
+			s.page.compressed = makeCompressedPage(s.page.compressed, decompress, data)
+			pageData = observeDecompression(s.observer, codec, s.page.compressed)
 		} else {
 			pageData = data
 		}
@@ -900,8 +1935,8 @@ func (s *filePageValueReaderState) init(columnType Type, column *Column, codec f
 
 	case DataPageHeaderV1:
 		if h.IsCompressed(codec) {
-			s.page.compressed = makeCompressedPage(s.page.compressed, codec, data)
-			pageData = s.page.compressed
+			s.page.compressed = makeCompressedPage(s.page.compressed, decompress, data)
+			pageData = observeDecompression(s.observer, codec, s.page.compressed)
 		} else {
 			pageData = data
 		}
@@ -1052,13 +2087,13 @@ func (lvl *dataPageLevelV2) setDataPageV2Section(file io.ReaderAt, dataPageOffse
 	lvl.section = *io.NewSectionReader(file, dataPageOffset, dataPageLength)
 }
 
-func makeCompressedPage(page *compressedPageReader, codec format.CompressionCodec, compressed io.Reader) *compressedPageReader {
+func makeCompressedPage(page *compressedPageReader, pool *compressedPageReaderPool, compressed io.Reader) *compressedPageReader {
 	if page == nil {
-		page = acquireCompressedPageReader(codec, compressed)
+		page = acquireCompressedPageReader(pool, compressed)
 	} else {
-		if page.codec != codec {
+		if page.pool != pool {
 			releaseCompressedPageReader(page)
-			page = acquireCompressedPageReader(codec, compressed)
+			page = acquireCompressedPageReader(pool, compressed)
 		} else {
 			page.Reset(compressed)
 		}
@@ -0,0 +1,57 @@
+package parquet
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/segmentio/parquet-go/format"
+)
+
+func TestReadEncryptedPageIndexCorrupted(t *testing.T) {
+	footerKey := []byte("0123456789abcdef")
+
+	buffer := new(bytes.Buffer)
+	writer := NewWriter(buffer, Encryption(&EncryptionConfig{FooterKey: footerKey}))
+	if err := writer.Write(&struct{ Name string }{Name: "Luke"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The offsets and lengths are stored in the encrypted footer, so they are
+	// changed after opening the file rather than in the file content.
+	for _, test := range [...]struct {
+		scenario string
+		corrupt  func(chunk *format.ColumnChunk)
+	}{
+		{
+			scenario: "negative column index length",
+			corrupt:  func(chunk *format.ColumnChunk) { chunk.ColumnIndexLength = -1 },
+		},
+		{
+			scenario: "zero offset index offset",
+			corrupt:  func(chunk *format.ColumnChunk) { chunk.OffsetIndexOffset = 0 },
+		},
+		{
+			scenario: "column index past the end of the file",
+			corrupt:  func(chunk *format.ColumnChunk) { chunk.ColumnIndexLength = int32(buffer.Len()) },
+		},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			f, err := OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()),
+				Decryption(&DecryptionConfig{FooterKey: footerKey}),
+				SkipPageIndex(true),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			test.corrupt(&f.metadata.RowGroups[0].Columns[0])
+
+			if _, _, err := f.readEncryptedPageIndex(nil, nil); !errors.Is(err, ErrCorrupted) {
+				t.Fatalf("expected a corruption error but got %v", err)
+			}
+		})
+	}
+}
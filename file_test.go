@@ -1,12 +1,19 @@
 package parquet_test
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"strings"
 	"testing"
 
+	"github.com/segmentio/encoding/thrift"
 	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress"
+	"github.com/segmentio/parquet-go/format"
 )
 
 var fixtureFiles = [...]string{
@@ -47,6 +54,502 @@ func TestOpenFile(t *testing.T) {
 	}
 }
 
+func TestFileReadRows(t *testing.T) {
+	for _, path := range fixtureFiles {
+		t.Run(path, func(t *testing.T) {
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			s, err := f.Stat()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p, err := parquet.OpenFile(f, s.Size())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			numRows := int64(0)
+			for i := 0; i < p.NumRowGroups(); i++ {
+				rows := p.RowGroup(i).Rows()
+				for {
+					_, err := rows.ReadRow(nil)
+					if err != nil {
+						if err != io.EOF {
+							t.Fatalf("reading row %d of row group %d: %v", numRows, i, err)
+						}
+						break
+					}
+					numRows++
+				}
+			}
+
+			if numRows != p.NumRows() {
+				t.Errorf("wrong number of rows: want=%d got=%d", p.NumRows(), numRows)
+			}
+		})
+	}
+}
+
+func TestFileMultipleRowGroups(t *testing.T) {
+	type Row struct {
+		A int64  `parquet:"a"`
+		B string `parquet:"b"`
+	}
+
+	const numRows = 100
+	const rowsPerRowGroup = 25
+	rows := make([]Row, numRows)
+	for i := range rows {
+		rows[i] = Row{A: int64(i), B: fmt.Sprintf("%03d", i)}
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.MaxRowsPerRowGroup(rowsPerRowGroup))
+	for i := range rows {
+		if err := writer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("page index", func(t *testing.T) {
+		f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rowGroups := f.RowGroups()
+		if len(rowGroups) != numRows/rowsPerRowGroup {
+			t.Fatalf("wrong number of row groups: want=%d got=%d", numRows/rowsPerRowGroup, len(rowGroups))
+		}
+
+		for i, rowGroup := range rowGroups {
+			chunk := rowGroup.Column(0)
+			columnIndex := chunk.ColumnIndex()
+			minValue := parquet.ValueOf(int64(i * rowsPerRowGroup))
+			maxValue := parquet.ValueOf(int64((i+1)*rowsPerRowGroup - 1))
+			if !parquet.Equal(columnIndex.MinValue(0), minValue) || !parquet.Equal(columnIndex.MaxValue(columnIndex.NumPages()-1), maxValue) {
+				t.Errorf("wrong column index of row group %d: min=%v max=%v", i, columnIndex.MinValue(0), columnIndex.MaxValue(columnIndex.NumPages()-1))
+			}
+			if offset := chunk.OffsetIndex().Offset(0); offset != f.Metadata().RowGroups[i].Columns[0].MetaData.DataPageOffset {
+				t.Errorf("wrong offset index of row group %d: page offset %d", i, offset)
+			}
+		}
+	})
+
+	t.Run("columns not ordered by name", func(t *testing.T) {
+		// Swap the two columns in the file metadata so that the column chunks
+		// are not in the same order as the leaf columns of the schema. The page
+		// index is dropped since its layout follows the original order.
+		data := rewriteFooter(t, buffer.Bytes(), func(metadata *format.FileMetaData) {
+			metadata.Schema[1], metadata.Schema[2] = metadata.Schema[2], metadata.Schema[1]
+			for i := range metadata.RowGroups {
+				columns := metadata.RowGroups[i].Columns
+				columns[0], columns[1] = columns[1], columns[0]
+				for j := range columns {
+					columns[j].ColumnIndexOffset, columns[j].ColumnIndexLength = 0, 0
+					columns[j].OffsetIndexOffset, columns[j].OffsetIndexLength = 0, 0
+				}
+			}
+		})
+
+		f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reader := parquet.NewReader(f)
+		for i := range rows {
+			row := Row{}
+			if err := reader.Read(&row); err != nil {
+				t.Fatalf("reading row %d: %v", i, err)
+			}
+			if row != rows[i] {
+				t.Fatalf("wrong row at index %d: want=%+v got=%+v", i, rows[i], row)
+			}
+		}
+	})
+}
+
+func TestFileRowGroups(t *testing.T) {
+	type Row struct {
+		ID   int64    `parquet:"id"`
+		Tags []string `parquet:"tags"`
+	}
+
+	const numRowGroups = 8
+	const numRows = 1000
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.MaxRowsPerRowGroup(numRows))
+	for i := 0; i < numRowGroups*numRows; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Tags: []string{"a", "b", "c"}[:i%4]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rowGroups := f.RowGroups()
+	if len(rowGroups) != numRowGroups {
+		t.Fatalf("wrong number of row groups: want=%d got=%d", numRowGroups, len(rowGroups))
+	}
+
+	// Each row group is read by a separate goroutine.
+	errs := make(chan error, len(rowGroups))
+	for i, rowGroup := range rowGroups {
+		go func(i int, rowGroup parquet.RowGroup) {
+			errs <- func() error {
+				if n := rowGroup.NumRows(); n != numRows {
+					return fmt.Errorf("wrong number of rows in row group %d: %d", i, n)
+				}
+				if n := rowGroup.NumColumns(); n != 2 {
+					return fmt.Errorf("wrong number of columns in row group %d: %d", i, n)
+				}
+				reader := parquet.NewRowGroupReader(rowGroup)
+				for j := 0; j < numRows; j++ {
+					var row Row
+					if err := reader.Read(&row); err != nil {
+						return fmt.Errorf("reading row %d of row group %d: %w", j, i, err)
+					}
+					if id := i*numRows + j; row.ID != int64(id) || len(row.Tags) != id%4 {
+						return fmt.Errorf("row %d of row group %d mismatch: %+v", j, i, row)
+					}
+				}
+				return nil
+			}()
+		}(i, rowGroup)
+	}
+
+	for range rowGroups {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestColumnChunkValueReader(t *testing.T) {
+	type Row struct {
+		ID   int64    `parquet:"id"`
+		Tags []string `parquet:"tags"`
+	}
+
+	const numRows = 1000
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.DataPageMaxValues(100))
+	for i := 0; i < numRows; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Tags: []string{"a", "b", "c"}[:i%4]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rowGroup := f.RowGroup(0)
+	mapping := parquet.ColumnMappingOf(rowGroup.Schema())
+
+	t.Run("sum", func(t *testing.T) {
+		values := parquet.NewColumnChunkValueReader(rowGroup.Column(mapping.ColumnIndex("id")))
+		sum, count := int64(0), 0
+		buf := make([]parquet.Value, 7)
+		for {
+			n, err := values.ReadValues(buf)
+			for _, v := range buf[:n] {
+				sum += v.Int64()
+				count++
+			}
+			if err != nil {
+				if err != io.EOF {
+					t.Fatal(err)
+				}
+				break
+			}
+		}
+		if count != numRows {
+			t.Errorf("wrong number of values: want=%d got=%d", numRows, count)
+		}
+		if want := int64(numRows * (numRows - 1) / 2); sum != want {
+			t.Errorf("wrong sum of values: want=%d got=%d", want, sum)
+		}
+	})
+
+	t.Run("levels", func(t *testing.T) {
+		values := parquet.NewColumnChunkValueReader(rowGroup.Column(mapping.ColumnIndex("tags")))
+		rowIndex, rowValues := -1, 0
+		checkRow := func() {
+			if rowIndex < 0 {
+				return
+			}
+			want := rowIndex % 4
+			if want == 0 {
+				want = 1 // empty lists are represented by a null value
+			}
+			if rowValues != want {
+				t.Errorf("wrong number of values in row %d: want=%d got=%d", rowIndex, want, rowValues)
+			}
+		}
+		buf := make([]parquet.Value, 7)
+		for {
+			n, err := values.ReadValues(buf)
+			for _, v := range buf[:n] {
+				if v.RepetitionLevel() == 0 {
+					checkRow()
+					rowIndex, rowValues = rowIndex+1, 0
+				}
+				if isNull, wantNull := v.DefinitionLevel() == 0, rowIndex%4 == 0; isNull != wantNull {
+					t.Fatalf("wrong definition level for value of row %d: %d", rowIndex, v.DefinitionLevel())
+				}
+				rowValues++
+			}
+			if err != nil {
+				if err != io.EOF {
+					t.Fatal(err)
+				}
+				break
+			}
+		}
+		checkRow()
+		if rowIndex+1 != numRows {
+			t.Errorf("wrong number of rows: want=%d got=%d", numRows, rowIndex+1)
+		}
+	})
+
+	t.Run("seek", func(t *testing.T) {
+		values := parquet.NewColumnChunkValueReader(rowGroup.Column(mapping.ColumnIndex("id")))
+		for _, rowIndex := range []int64{0, 1, 99, 100, 101, 550, numRows - 1} {
+			if err := values.(parquet.RowSeeker).SeekToRow(rowIndex); err != nil {
+				t.Fatal(err)
+			}
+			buf := make([]parquet.Value, 1)
+			if _, err := values.ReadValues(buf); err != nil {
+				t.Fatalf("reading value at row %d: %v", rowIndex, err)
+			}
+			if buf[0].Int64() != rowIndex {
+				t.Errorf("wrong value after seeking to row %d: %d", rowIndex, buf[0].Int64())
+			}
+		}
+	})
+}
+
+func TestFilePagesReuseBuffers(t *testing.T) {
+	type Row struct {
+		ID   int64    `parquet:"id"`
+		Tags []string `parquet:"tags"`
+	}
+
+	const numRows = 1000
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer,
+		parquet.Compression(&parquet.Snappy),
+		parquet.DataPageVersion(1),
+		parquet.DataPageMaxValues(100),
+		parquet.MaxRowsPerRowGroup(numRows/4),
+	)
+	for i := 0; i < numRows; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Tags: []string{"a", "b", "c"}[:i%4]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readValues := func(chunk parquet.ColumnChunk) []parquet.Value {
+		pages := chunk.Pages()
+		defer pages.(io.Closer).Close()
+
+		var values []parquet.Value
+		buf := make([]parquet.Value, 7)
+		for {
+			p, err := pages.ReadPage()
+			if err != nil {
+				if err != io.EOF {
+					t.Fatal(err)
+				}
+				return values
+			}
+			r := p.Values()
+			for {
+				n, err := r.ReadValues(buf)
+				for _, v := range buf[:n] {
+					values = append(values, v.Clone())
+				}
+				if err != nil {
+					if err != io.EOF {
+						t.Fatal(err)
+					}
+					break
+				}
+			}
+		}
+	}
+
+	// The buffers released when closing the pages of a column chunk are
+	// reused to read the next one, alternating between columns of different
+	// types must not affect the values read.
+	var chunks []parquet.ColumnChunk
+	for _, rowGroup := range f.RowGroups() {
+		for i := 0; i < rowGroup.NumColumns(); i++ {
+			chunks = append(chunks, rowGroup.Column(i))
+		}
+	}
+	want := make([][]parquet.Value, len(chunks))
+	for i, chunk := range chunks {
+		want[i] = readValues(chunk)
+	}
+	for i := len(chunks) - 1; i >= 0; i-- {
+		got := readValues(chunks[i])
+		if len(got) != len(want[i]) {
+			t.Fatalf("wrong number of values in column chunk %d: want=%d got=%d", i, len(want[i]), len(got))
+		}
+		for j := range got {
+			if !parquet.Equal(got[j], want[i][j]) || got[j].DefinitionLevel() != want[i][j].DefinitionLevel() {
+				t.Fatalf("wrong value at index %d of column chunk %d: want=%v got=%v", j, i, want[i][j], got[j])
+			}
+		}
+	}
+	if n := len(want[0]); n != numRows/4 {
+		t.Errorf("wrong number of values in the first column chunk: want=%d got=%d", numRows/4, n)
+	}
+}
+
+func TestFilePagesValuesOutliveClose(t *testing.T) {
+	type Row struct {
+		Name string `parquet:"name"`
+	}
+
+	const numRows = 1000
+
+	for _, codec := range []compress.Codec{&parquet.Uncompressed, &parquet.Snappy} {
+		t.Run(codec.String(), func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			writer := parquet.NewWriter(buffer,
+				parquet.Compression(codec),
+				parquet.MaxRowsPerRowGroup(numRows/4),
+			)
+			for i := 0; i < numRows; i++ {
+				if err := writer.Write(&Row{Name: fmt.Sprintf("name-%04d", i)}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The values are not cloned, they must remain valid after the
+			// pages are closed and other column chunks are read.
+			var values []parquet.Value
+			for _, rowGroup := range f.RowGroups() {
+				pages := rowGroup.Column(0).Pages()
+				for {
+					p, err := pages.ReadPage()
+					if err != nil {
+						if err != io.EOF {
+							t.Fatal(err)
+						}
+						break
+					}
+					n := len(values)
+					values = append(values, make([]parquet.Value, p.NumValues())...)
+					if _, err := p.Values().ReadValues(values[n:]); err != nil && err != io.EOF {
+						t.Fatal(err)
+					}
+				}
+				if err := pages.(io.Closer).Close(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for i, value := range values {
+				if want := fmt.Sprintf("name-%04d", i); value.String() != want {
+					t.Fatalf("wrong value at index %d: want=%q got=%q", i, want, value)
+				}
+			}
+		})
+	}
+}
+
+func TestFileSeekToRow(t *testing.T) {
+	for _, path := range fixtureFiles {
+		t.Run(path, func(t *testing.T) {
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			s, err := f.Stat()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p, err := parquet.OpenFile(f, s.Size())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rowGroup := p.RowGroup(0)
+			numRows := rowGroup.NumRows()
+			want := make([]parquet.Row, 0, numRows)
+			rows := rowGroup.Rows()
+			for {
+				row, err := rows.ReadRow(nil)
+				if err != nil {
+					if err != io.EOF {
+						t.Fatal(err)
+					}
+					break
+				}
+				want = append(want, row)
+			}
+
+			for _, rowIndex := range []int64{0, 1, numRows / 3, numRows / 2, numRows - 1} {
+				rows := rowGroup.Rows()
+				if err := rows.SeekToRow(rowIndex); err != nil {
+					t.Fatal(err)
+				}
+				row, err := rows.ReadRow(nil)
+				if err != nil {
+					t.Fatalf("reading row %d: %v", rowIndex, err)
+				}
+				if !row.Equal(want[rowIndex]) {
+					t.Errorf("row %d mismatch after seeking", rowIndex)
+				}
+			}
+		})
+	}
+}
+
 func printColumns(t *testing.T, col *parquet.Column, indent string) {
 	t.Logf("%s%s", indent, strings.Join(col.Path(), "."))
 	indent += ". "
@@ -92,3 +595,1255 @@ func TestFileKeyValueMetadata(t *testing.T) {
 		}
 	}
 }
+
+func TestOpenEncryptedFile(t *testing.T) {
+	type Record struct {
+		Name  string `parquet:"name,dict"`
+		Email string `parquet:"email"`
+		Score int64  `parquet:"score"`
+		Tags  []string
+	}
+
+	footerKey := []byte("0123456789abcdef")
+	aadPrefix := []byte("table/people")
+	columnKey := []byte("fedcba9876543210")
+	keys := testKeyRetriever{"footer": footerKey, "email": columnKey}
+	kms := testKeyRetriever{}
+
+	records := make([]Record, 1000)
+	for i := range records {
+		records[i] = Record{
+			Name:  fmt.Sprintf("name-%d", i%7),
+			Email: fmt.Sprintf("email-%d@example.com", i),
+			Score: int64(i),
+			Tags:  []string{"a", "b", "c"}[:i%4],
+		}
+	}
+
+	for _, test := range [...]struct {
+		scenario   string
+		encryption *parquet.EncryptionConfig
+		decryption *parquet.DecryptionConfig
+		options    []parquet.WriterOption
+	}{
+		{
+			scenario:   "footer key",
+			encryption: &parquet.EncryptionConfig{FooterKey: footerKey},
+			decryption: &parquet.DecryptionConfig{FooterKey: footerKey},
+		},
+		{
+			scenario:   "data page v1",
+			encryption: &parquet.EncryptionConfig{FooterKey: footerKey},
+			decryption: &parquet.DecryptionConfig{FooterKey: footerKey},
+			options:    []parquet.WriterOption{parquet.DataPageVersion(1)},
+		},
+		{
+			scenario:   "key retriever",
+			encryption: &parquet.EncryptionConfig{FooterKey: footerKey, FooterKeyMetadata: []byte("k1")},
+			decryption: &parquet.DecryptionConfig{
+				KeyRetriever: parquet.DecryptionKeyRetrieverFunc(func(keyMetadata []byte) ([]byte, error) {
+					if string(keyMetadata) != "k1" {
+						return nil, fmt.Errorf("unknown key: %q", keyMetadata)
+					}
+					return footerKey, nil
+				}),
+			},
+		},
+		{
+			scenario:   "stored aad prefix",
+			encryption: &parquet.EncryptionConfig{FooterKey: footerKey, AADPrefix: aadPrefix},
+			decryption: &parquet.DecryptionConfig{FooterKey: footerKey},
+		},
+		{
+			scenario:   "supplied aad prefix",
+			encryption: &parquet.EncryptionConfig{FooterKey: footerKey, AADPrefix: aadPrefix, SupplyAADPrefix: true},
+			decryption: &parquet.DecryptionConfig{FooterKey: footerKey, AADPrefix: aadPrefix},
+		},
+		{
+			scenario:   "bloom filters",
+			encryption: &parquet.EncryptionConfig{FooterKey: footerKey},
+			decryption: &parquet.DecryptionConfig{FooterKey: footerKey},
+			options:    []parquet.WriterOption{parquet.BloomFilters(parquet.SplitBlockFilter("email"))},
+		},
+		{
+			scenario: "column keys",
+			encryption: &parquet.EncryptionConfig{
+				FooterKey:         footerKey,
+				FooterKeyMetadata: []byte("footer"),
+				ColumnKeys: []parquet.ColumnKey{
+					{Path: []string{"email"}, Key: columnKey, KeyMetadata: []byte("email")},
+				},
+			},
+			decryption: &parquet.DecryptionConfig{KeyRetriever: keys},
+		},
+		{
+			scenario: "plaintext columns",
+			encryption: &parquet.EncryptionConfig{
+				FooterKey:         footerKey,
+				FooterKeyMetadata: []byte("footer"),
+				ColumnKeys: []parquet.ColumnKey{
+					{Path: []string{"email"}, Key: columnKey, KeyMetadata: []byte("email")},
+				},
+				PlaintextColumns: true,
+			},
+			decryption: &parquet.DecryptionConfig{KeyRetriever: keys},
+			options:    []parquet.WriterOption{parquet.BloomFilters(parquet.SplitBlockFilter("email"))},
+		},
+		{
+			scenario: "key wrapper",
+			encryption: &parquet.EncryptionConfig{
+				FooterKey:  footerKey,
+				KeyWrapper: kms,
+				ColumnKeys: []parquet.ColumnKey{
+					{Path: []string{"email"}, Key: columnKey},
+				},
+			},
+			decryption: &parquet.DecryptionConfig{KeyRetriever: kms},
+		},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			writer := parquet.NewWriter(buffer, append(test.options,
+				parquet.PageBufferSize(1024),
+				parquet.Encryption(test.encryption),
+			)...)
+			for i := range records {
+				if err := writer.Write(&records[i]); err != nil {
+					t.Fatal(err)
+				}
+				if i == len(records)/2 {
+					if err := writer.Flush(); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			input := bytes.NewReader(buffer.Bytes())
+			if _, err := parquet.OpenFile(input, input.Size()); err == nil {
+				t.Fatal("opening an encrypted file without decryption configuration must fail")
+			}
+
+			f, err := parquet.OpenFile(input, input.Size(), parquet.Decryption(test.decryption))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n := f.NumRowGroups(); n != 2 {
+				t.Fatalf("wrong number of row groups: want=2 got=%d", n)
+			}
+			if len(f.ColumnIndexes()) == 0 || len(f.OffsetIndexes()) == 0 {
+				t.Error("the page index of the encrypted file was not read")
+			}
+
+			reader := parquet.NewReader(f)
+			for i := range records {
+				var record Record
+				if err := reader.Read(&record); err != nil {
+					t.Fatalf("reading row %d: %v", i, err)
+				}
+				if record.Name != records[i].Name ||
+					record.Email != records[i].Email ||
+					record.Score != records[i].Score ||
+					len(record.Tags) != len(records[i].Tags) {
+					t.Fatalf("row %d mismatch:\nwant = %+v\ngot  = %+v", i, records[i], record)
+				}
+			}
+
+			if err := reader.SeekToRow(int64(len(records) - 10)); err != nil {
+				t.Fatal(err)
+			}
+			var record Record
+			if err := reader.Read(&record); err != nil {
+				t.Fatal(err)
+			}
+			if record.Score != int64(len(records)-10) {
+				t.Errorf("wrong row after seeking: want=%d got=%d", len(records)-10, record.Score)
+			}
+
+			switch test.scenario {
+			case "bloom filters", "plaintext columns":
+				bloomFilter := f.RowGroup(0).Column(1).BloomFilter()
+				if bloomFilter == nil {
+					t.Fatal("the bloom filter of the encrypted column was not read")
+				}
+				if ok, err := bloomFilter.Check(parquet.ValueOf(records[0].Email)); err != nil {
+					t.Fatal(err)
+				} else if !ok {
+					t.Errorf("bloom filter does not contain %q", records[0].Email)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenEncryptedFileErrors(t *testing.T) {
+	footerKey := []byte("0123456789abcdef")
+	aadPrefix := []byte("table/people")
+
+	write := func(config *parquet.EncryptionConfig) []byte {
+		buffer := new(bytes.Buffer)
+		writer := parquet.NewWriter(buffer, parquet.Encryption(config))
+		if err := writer.Write(&struct{ Name string }{Name: "Luke"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buffer.Bytes()
+	}
+
+	tampered := write(&parquet.EncryptionConfig{FooterKey: footerKey})
+	tampered[len(tampered)-20] ^= 1
+
+	for _, test := range [...]struct {
+		scenario   string
+		file       []byte
+		decryption *parquet.DecryptionConfig
+	}{
+		{
+			scenario:   "wrong key",
+			file:       write(&parquet.EncryptionConfig{FooterKey: footerKey}),
+			decryption: &parquet.DecryptionConfig{FooterKey: []byte("fedcba9876543210")},
+		},
+		{
+			scenario:   "missing aad prefix",
+			file:       write(&parquet.EncryptionConfig{FooterKey: footerKey, AADPrefix: aadPrefix, SupplyAADPrefix: true}),
+			decryption: &parquet.DecryptionConfig{FooterKey: footerKey},
+		},
+		{
+			scenario:   "wrong aad prefix",
+			file:       write(&parquet.EncryptionConfig{FooterKey: footerKey, AADPrefix: aadPrefix, SupplyAADPrefix: true}),
+			decryption: &parquet.DecryptionConfig{FooterKey: footerKey, AADPrefix: []byte("table/other")},
+		},
+		{
+			scenario:   "mismatching aad prefix",
+			file:       write(&parquet.EncryptionConfig{FooterKey: footerKey, AADPrefix: aadPrefix}),
+			decryption: &parquet.DecryptionConfig{FooterKey: footerKey, AADPrefix: []byte("table/other")},
+		},
+		{
+			scenario:   "tampered footer",
+			file:       tampered,
+			decryption: &parquet.DecryptionConfig{FooterKey: footerKey},
+		},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			input := bytes.NewReader(test.file)
+			if _, err := parquet.OpenFile(input, input.Size(), parquet.Decryption(test.decryption)); err == nil {
+				t.Fatal("expected an error but the file was opened")
+			}
+		})
+	}
+}
+
+func TestOpenEncryptedFileCorruptedModuleLength(t *testing.T) {
+	footerKey := []byte("0123456789abcdef")
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.Encryption(&parquet.EncryptionConfig{FooterKey: footerKey}))
+	if err := writer.Write(&struct{ Name string }{Name: "Luke"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The header of the first page starts right after the magic bytes, its
+	// length is changed to a value larger than the column chunk.
+	data := buffer.Bytes()
+	binary.LittleEndian.PutUint32(data[4:], 0xFFFFFFF0)
+
+	f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)),
+		parquet.Decryption(&parquet.DecryptionConfig{FooterKey: footerKey}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := readAllValues(f); !errors.Is(err, parquet.ErrCorrupted) {
+		t.Fatalf("reading the file did not report the corruption: %v", err)
+	}
+}
+
+func TestOpenFileCorruptedFooterSize(t *testing.T) {
+	footerKey := []byte("0123456789abcdef")
+
+	for _, test := range [...]struct {
+		scenario string
+		options  []parquet.WriterOption
+	}{
+		{scenario: "plaintext"},
+		{
+			scenario: "encrypted",
+			options:  []parquet.WriterOption{parquet.Encryption(&parquet.EncryptionConfig{FooterKey: footerKey})},
+		},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			writer := parquet.NewWriter(buffer, test.options...)
+			if err := writer.Write(&struct{ Name string }{Name: "Luke"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			// The footer size precedes the magic bytes at the end of the file,
+			// it is changed to a value larger than the file.
+			data := buffer.Bytes()
+			binary.LittleEndian.PutUint32(data[len(data)-8:], 0xFFFFFFF0)
+
+			_, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)),
+				parquet.Decryption(&parquet.DecryptionConfig{FooterKey: footerKey}),
+			)
+			if !errors.Is(err, parquet.ErrCorrupted) {
+				t.Fatalf("expected a corruption error but got %v", err)
+			}
+		})
+	}
+}
+
+func TestOpenEncryptedFileMissingColumnKey(t *testing.T) {
+	type Record struct {
+		Name  string `parquet:"name"`
+		Email string `parquet:"email"`
+		Score int64  `parquet:"score"`
+	}
+
+	footerKey := []byte("0123456789abcdef")
+	columnKey := []byte("fedcba9876543210")
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.Encryption(&parquet.EncryptionConfig{
+		FooterKey:         footerKey,
+		FooterKeyMetadata: []byte("footer"),
+		ColumnKeys: []parquet.ColumnKey{
+			{Path: []string{"email"}, Key: columnKey, KeyMetadata: []byte("email")},
+		},
+	}))
+	for i := 0; i < 10; i++ {
+		if err := writer.Write(&Record{Name: fmt.Sprintf("name-%d", i), Email: "luke@example.com", Score: int64(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	input := bytes.NewReader(buffer.Bytes())
+	f, err := parquet.OpenFile(input, input.Size(), parquet.Decryption(&parquet.DecryptionConfig{
+		KeyRetriever: testKeyRetriever{"footer": footerKey},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The email column is sorted first in the schema of the file.
+	if _, err := f.RowGroup(0).Column(0).Pages().ReadPage(); err == nil {
+		t.Error("reading a column without access to its key must fail")
+	}
+
+	type Public struct {
+		Name  string `parquet:"name"`
+		Score int64  `parquet:"score"`
+	}
+	reader := parquet.NewReader(f, parquet.SchemaOf(Public{}))
+	for i := 0; i < 10; i++ {
+		var row Public
+		if err := reader.Read(&row); err != nil {
+			t.Fatalf("reading row %d: %v", i, err)
+		}
+		if row.Score != int64(i) || row.Name != fmt.Sprintf("name-%d", i) {
+			t.Fatalf("row %d mismatch: %+v", i, row)
+		}
+	}
+}
+
+type testKeyRetriever map[string][]byte
+
+func (keys testKeyRetriever) RetrieveKey(keyMetadata []byte) ([]byte, error) {
+	key, ok := keys[string(keyMetadata)]
+	if !ok {
+		return nil, fmt.Errorf("unknown key: %q", keyMetadata)
+	}
+	return key, nil
+}
+
+// WrapKey implements parquet.EncryptionKeyWrapper, generating key identifiers
+// to emulate a key management service.
+func (keys testKeyRetriever) WrapKey(key []byte) ([]byte, error) {
+	keyMetadata := fmt.Sprintf("key-%d", len(keys))
+	keys[keyMetadata] = key
+	return []byte(keyMetadata), nil
+}
+
+func TestFileStrictValidation(t *testing.T) {
+	for _, path := range fixtureFiles {
+		t.Run(path, func(t *testing.T) {
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			s, err := f.Stat()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p, err := parquet.OpenFile(f, s.Size(), parquet.StrictValidation(true))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := readAllValues(p); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name,optional"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	for i := 0; i < 100; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Name: fmt.Sprintf("row-%d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		scenario string
+		corrupt  func(*format.FileMetaData)
+		onOpen   bool
+	}{
+		{
+			scenario: "column chunk statistics with min greater than max",
+			corrupt: func(metadata *format.FileMetaData) {
+				stats := &metadata.RowGroups[0].Columns[0].MetaData.Statistics
+				stats.MinValue = make([]byte, 8)
+				stats.MaxValue = make([]byte, 8)
+				binary.LittleEndian.PutUint64(stats.MinValue, 99)
+			},
+			onOpen: true,
+		},
+
+		{
+			scenario: "column chunk exceeding the file size",
+			corrupt: func(metadata *format.FileMetaData) {
+				metadata.RowGroups[0].Columns[1].MetaData.TotalCompressedSize += 1e6
+			},
+			onOpen: true,
+		},
+
+		{
+			scenario: "column chunk with the wrong number of values",
+			corrupt: func(metadata *format.FileMetaData) {
+				metadata.RowGroups[0].Columns[1].MetaData.NumValues++
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			data := rewriteFooter(t, buffer.Bytes(), test.corrupt)
+
+			if _, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data))); err != nil {
+				t.Fatal("opening the file without strict validation:", err)
+			}
+
+			f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)), parquet.StrictValidation(true))
+			if test.onOpen {
+				if !errors.Is(err, parquet.ErrCorrupted) {
+					t.Fatalf("opening the file did not report the corruption: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := readAllValues(f); !errors.Is(err, parquet.ErrCorrupted) {
+				t.Fatalf("reading the file did not report the corruption: %v", err)
+			}
+		})
+	}
+}
+
+func rewriteFooter(t *testing.T, data []byte, rewrite func(*format.FileMetaData)) []byte {
+	footerLength := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footerOffset := len(data) - (footerLength + 8)
+
+	metadata := format.FileMetaData{}
+	if err := thrift.Unmarshal(new(thrift.CompactProtocol), data[footerOffset:len(data)-8], &metadata); err != nil {
+		t.Fatal(err)
+	}
+	rewrite(&metadata)
+
+	footer, err := thrift.Marshal(new(thrift.CompactProtocol), &metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := append([]byte{}, data[:footerOffset]...)
+	b = append(b, footer...)
+	b = append(b, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(b[len(b)-4:], uint32(len(footer)))
+	return append(b, "PAR1"...)
+}
+
+func readAllValues(f *parquet.File) error {
+	values := make([]parquet.Value, 64)
+
+	for _, rowGroup := range f.RowGroups() {
+		for i := 0; i < rowGroup.NumColumns(); i++ {
+			pages := rowGroup.Column(i).Pages()
+			for {
+				p, err := pages.ReadPage()
+				if err != nil {
+					if err == io.EOF {
+						break
+					}
+					return err
+				}
+				r := p.Values()
+				for {
+					_, err := r.ReadValues(values)
+					if err != nil {
+						if err == io.EOF {
+							break
+						}
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func TestValidateFile(t *testing.T) {
+	for _, path := range fixtureFiles {
+		t.Run(path, func(t *testing.T) {
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			s, err := f.Stat()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			report, err := parquet.ValidateFile(f, s.Size(), parquet.DecodePages(true))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := report.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if report.NumPages == 0 || report.NumValues == 0 {
+				t.Errorf("no pages were validated: %+v", report)
+			}
+		})
+	}
+
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.MaxRowsPerRowGroup(10), parquet.DataPageMaxValues(5))
+	for i := 0; i < 30; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Name: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	offsetIndex := f.RowGroups()[1].Column(1).OffsetIndex()
+
+	// Flip the last byte of the second page of the "name" column in the second
+	// row group, and corrupt the statistics of the "id" column in the third.
+	data := append([]byte{}, buffer.Bytes()...)
+	data[offsetIndex.Offset(1)+offsetIndex.CompressedPageSize(1)-1] ^= 0xFF
+	data = rewriteFooter(t, data, func(metadata *format.FileMetaData) {
+		stats := &metadata.RowGroups[2].Columns[0].MetaData.Statistics
+		stats.MinValue = make([]byte, 8)
+		stats.MaxValue = make([]byte, 8)
+		binary.LittleEndian.PutUint64(stats.MinValue, 99)
+	})
+
+	report, err := parquet.ValidateFile(bytes.NewReader(data), int64(len(data)), parquet.DecodePages(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Valid() {
+		t.Fatal("corrupted file reported as valid")
+	}
+	t.Log(report.Err())
+
+	if report.NumRowGroups != 3 || report.NumRows != 30 || report.NumColumnChunks != 6 {
+		t.Errorf("wrong file layout in report: %+v", report)
+	}
+
+	type location struct{ rowGroup, column, page int }
+	want := []location{{1, 1, 1}, {2, 0, -1}}
+	got := make([]location, len(report.Issues))
+	for i, issue := range report.Issues {
+		got[i] = location{issue.RowGroup, issue.Column, issue.Page}
+		if !errors.Is(issue, parquet.ErrCorrupted) {
+			t.Errorf("issue does not wrap ErrCorrupted: %v", issue)
+		}
+	}
+	if fmt.Sprint(want) != fmt.Sprint(got) {
+		t.Errorf("wrong issues reported: want=%v got=%v", want, got)
+	}
+}
+
+func TestPageInfoReader(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name,dict"`
+	}
+
+	const numRows = 100
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.DataPageMaxValues(numRows/4), parquet.DataPageStatistics(true))
+	for i := 0; i < numRows; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Name: fmt.Sprint(i % 10)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rowGroup := f.RowGroups()[0]
+
+	for i, chunk := range f.Metadata().RowGroups[0].Columns {
+		column := rowGroup.Column(i)
+		pages := parquet.NewPageInfoReader(column)
+		bounds := column.Pages()
+
+		var infos []parquet.PageInfo
+		for {
+			info, err := pages.ReadPageInfo()
+			if err != nil {
+				if err == io.EOF {
+					break
+				}
+				t.Fatal(err)
+			}
+			infos = append(infos, info)
+		}
+
+		offset := chunk.MetaData.DataPageOffset
+		if chunk.MetaData.DictionaryPageOffset != 0 {
+			offset = chunk.MetaData.DictionaryPageOffset
+			if infos[0].Header.PageType() != format.DictionaryPage {
+				t.Errorf("column %d: first page is not the dictionary page: %s", i, infos[0].Header.PageType())
+			}
+		}
+
+		numValues := int64(0)
+		for j, info := range infos {
+			if info.Offset != offset {
+				t.Errorf("column %d: page %d: wrong offset: want=%d got=%d", i, j, offset, info.Offset)
+			}
+			if info.CRC == 0 {
+				t.Errorf("column %d: page %d: missing checksum", i, j)
+			}
+			offset += info.HeaderSize + info.CompressedSize
+
+			if info.Header.PageType() == format.DictionaryPage {
+				continue
+			}
+			numValues += info.Header.NumValues()
+
+			page, err := bounds.ReadPage()
+			if err != nil {
+				t.Fatal(err)
+			}
+			minValue, maxValue := page.Bounds()
+			if !parquet.Equal(info.MinValue, minValue) || !parquet.Equal(info.MaxValue, maxValue) {
+				t.Errorf("column %d: page %d: wrong bounds: want=[%v:%v] got=[%v:%v]", i, j, minValue, maxValue, info.MinValue, info.MaxValue)
+			}
+		}
+
+		if numValues != numRows {
+			t.Errorf("column %d: wrong number of values: want=%d got=%d", i, numRows, numValues)
+		}
+		if size := offset - infos[0].Offset; size != chunk.MetaData.TotalCompressedSize {
+			t.Errorf("column %d: wrong size of pages: want=%d got=%d", i, chunk.MetaData.TotalCompressedSize, size)
+		}
+	}
+
+	if _, err := parquet.NewPageInfoReader(parquet.NewBuffer(parquet.SchemaOf(Row{})).Column(0)).ReadPageInfo(); err == nil {
+		t.Error("reading page headers of a buffer did not return an error")
+	}
+}
+
+func TestPageLocations(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name,dict"`
+	}
+
+	const numRows = 100
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.DataPageMaxValues(numRows/4))
+	for i := 0; i < numRows; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Name: fmt.Sprint(i % 10)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rowGroup := f.RowGroups()[0]
+
+	for i := 0; i < rowGroup.NumColumns(); i++ {
+		column := rowGroup.Column(i)
+		locations := parquet.PageLocations(column.OffsetIndex())
+		if len(locations) != 4 {
+			t.Fatalf("column %d: wrong number of pages: want=4 got=%d", i, len(locations))
+		}
+
+		offset, length, ok := parquet.ColumnChunkByteRange(column)
+		if !ok {
+			t.Fatalf("column %d: column chunk has no byte range", i)
+		}
+		if end := locations[len(locations)-1].End(); end != offset+length {
+			t.Errorf("column %d: last page does not end the column chunk: want=%d got=%d", i, offset+length, end)
+		}
+
+		pages := parquet.NewPageInfoReader(column)
+		j := 0
+		for {
+			info, err := pages.ReadPageInfo()
+			if err != nil {
+				if err == io.EOF {
+					break
+				}
+				t.Fatal(err)
+			}
+			if info.Header.PageType() == format.DictionaryPage {
+				if info.Offset != offset || info.Offset+info.HeaderSize+info.CompressedSize != locations[0].Offset {
+					t.Errorf("column %d: dictionary page is not located before the first data page", i)
+				}
+				continue
+			}
+			want := parquet.PageLocation{
+				Offset:             info.Offset,
+				CompressedPageSize: info.HeaderSize + info.CompressedSize,
+				FirstRowIndex:      int64(j * numRows / 4),
+			}
+			if locations[j] != want {
+				t.Errorf("column %d: page %d: wrong location: want=%+v got=%+v", i, j, want, locations[j])
+			}
+			j++
+		}
+	}
+
+	if parquet.PageLocations(nil) != nil {
+		t.Error("page locations of a nil offset index are not nil")
+	}
+	if _, _, ok := parquet.ColumnChunkByteRange(parquet.NewBuffer(parquet.SchemaOf(Row{})).Column(0)); ok {
+		t.Error("buffer column chunk has a byte range")
+	}
+}
+
+func TestColumnIndexPages(t *testing.T) {
+	type Row struct {
+		ID    int64   `parquet:"id"`
+		Value *string `parquet:"value,optional"`
+	}
+
+	const numRows = 100
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.DataPageMaxValues(numRows/4))
+	for i := 0; i < numRows; i++ {
+		row := &Row{ID: int64(i)}
+		if i >= numRows/4 {
+			value := fmt.Sprintf("%03d", numRows-i)
+			row.Value = &value
+		}
+		if err := writer.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rowGroup := f.RowGroups()[0]
+
+	idIndex := rowGroup.Column(0).ColumnIndex()
+	if order := parquet.ColumnIndexBoundaryOrder(idIndex); order != format.Ascending {
+		t.Errorf("wrong boundary order of the id column: want=%s got=%s", format.Ascending, order)
+	}
+	idPages := parquet.ColumnIndexPages(idIndex)
+	if len(idPages) != 4 {
+		t.Fatalf("wrong number of pages in the id column: want=4 got=%d", len(idPages))
+	}
+	for i, page := range idPages {
+		want := parquet.ColumnIndexPage{
+			MinValue: parquet.ValueOf(int64(i * numRows / 4)),
+			MaxValue: parquet.ValueOf(int64((i+1)*numRows/4 - 1)),
+		}
+		if page.NullPage || page.NullCount != 0 || !parquet.Equal(page.MinValue, want.MinValue) || !parquet.Equal(page.MaxValue, want.MaxValue) {
+			t.Errorf("wrong page %d in the id column: want=%+v got=%+v", i, want, page)
+		}
+	}
+
+	valuePages := parquet.ColumnIndexPages(rowGroup.Column(1).ColumnIndex())
+	if len(valuePages) != 4 {
+		t.Fatalf("wrong number of pages in the value column: want=4 got=%d", len(valuePages))
+	}
+	if page := valuePages[0]; !page.NullPage || page.NullCount != numRows/4 || !page.MinValue.IsNull() || !page.MaxValue.IsNull() {
+		t.Errorf("first page of the value column is not a null page: %+v", page)
+	}
+	if page := valuePages[1]; page.NullPage || page.MinValue.String() != "051" || page.MaxValue.String() != "075" {
+		t.Errorf("wrong second page in the value column: %+v", page)
+	}
+
+	if parquet.ColumnIndexPages(nil) != nil {
+		t.Error("pages of a nil column index are not nil")
+	}
+	if order := parquet.ColumnIndexBoundaryOrder(nil); order != format.Unordered {
+		t.Errorf("wrong boundary order of a nil column index: %s", order)
+	}
+}
+
+func TestFileRowGroupForRow(t *testing.T) {
+	type Row struct {
+		ID int64 `parquet:"id"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	// Row groups of 3, 5, and 2 rows.
+	for _, n := range []int{3, 5, 2} {
+		for i := 0; i < n; i++ {
+			if err := writer.Write(&Row{ID: int64(i)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := writer.Flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	offsets := f.RowGroupOffsets()
+	if want := []int64{0, 3, 8, 10}; fmt.Sprint(offsets) != fmt.Sprint(want) {
+		t.Errorf("wrong row group offsets: want=%v got=%v", want, offsets)
+	}
+
+	for _, test := range []struct {
+		rowIndex int64
+		rowGroup int
+		offset   int64
+		ok       bool
+	}{
+		{rowIndex: -1, rowGroup: -1},
+		{rowIndex: 0, rowGroup: 0, offset: 0, ok: true},
+		{rowIndex: 2, rowGroup: 0, offset: 2, ok: true},
+		{rowIndex: 3, rowGroup: 1, offset: 0, ok: true},
+		{rowIndex: 7, rowGroup: 1, offset: 4, ok: true},
+		{rowIndex: 8, rowGroup: 2, offset: 0, ok: true},
+		{rowIndex: 9, rowGroup: 2, offset: 1, ok: true},
+		{rowIndex: 10, rowGroup: -1},
+	} {
+		rowGroup, offset, ok := f.RowGroupForRow(test.rowIndex)
+		if rowGroup != test.rowGroup || offset != test.offset || ok != test.ok {
+			t.Errorf("row %d: want=(%d,%d,%t) got=(%d,%d,%t)",
+				test.rowIndex, test.rowGroup, test.offset, test.ok, rowGroup, offset, ok)
+		}
+	}
+}
+
+func TestFileMayContain(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer,
+		parquet.BloomFilters(parquet.SplitBlockFilter("id")),
+	)
+	// Two row groups holding the ids [0,100) and [100,200).
+	for i := 0; i < 200; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Name: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+		if i == 99 {
+			if err := writer.Flush(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []int64{0, 99, 100, 199} {
+		found, err := f.MayContain("id", parquet.ValueOf(id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found {
+			t.Errorf("value %d was not found in the bloom filters", id)
+		}
+	}
+
+	values := make([]parquet.Value, 0, 100)
+	for id := int64(1000); id < 1100; id++ {
+		values = append(values, parquet.ValueOf(id))
+	}
+	found, err := f.MayContainValues("id", values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	numFalsePositives := 0
+	for _, ok := range found {
+		if ok {
+			numFalsePositives++
+		}
+	}
+	if numFalsePositives > 10 {
+		t.Errorf("too many false positives: %d/%d", numFalsePositives, len(values))
+	}
+
+	// Columns without bloom filters may contain any value.
+	if found, err := f.MayContain("name", parquet.ValueOf("nope")); err != nil {
+		t.Fatal(err)
+	} else if !found {
+		t.Error("column without bloom filters must be reported to possibly contain the value")
+	}
+
+	if _, err := f.MayContain("nope", parquet.ValueOf(int64(0))); err == nil {
+		t.Error("expected an error when probing a column that does not exist")
+	}
+	if _, err := f.MayContain("id", parquet.ValueOf("0")); err == nil {
+		t.Error("expected an error when probing a column with a value of the wrong kind")
+	}
+}
+
+func TestFileColumnStats(t *testing.T) {
+	type Row struct {
+		ID      int64   `parquet:"id"`
+		Score   *int32  `parquet:"score,optional"`
+		Comment *string `parquet:"comment,optional"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	// Two row groups holding the ids [0,100) and [100,200), scores are only
+	// set on even ids.
+	for i := 0; i < 200; i++ {
+		row := &Row{ID: int64(i)}
+		if i%2 == 0 {
+			score := int32(1000 - i)
+			row.Score = &score
+		}
+		if err := writer.Write(row); err != nil {
+			t.Fatal(err)
+		}
+		if i == 99 {
+			if err := writer.Flush(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := f.NumRowGroups(); n != 2 {
+		t.Fatalf("wrong number of row groups: %d", n)
+	}
+
+	for _, test := range []struct {
+		column string
+		want   parquet.ColumnStats
+	}{
+		{
+			column: "id",
+			want: parquet.ColumnStats{
+				NumValues: 200,
+				MinValue:  parquet.ValueOf(int64(0)),
+				MaxValue:  parquet.ValueOf(int64(199)),
+			},
+		},
+		{
+			column: "score",
+			want: parquet.ColumnStats{
+				NumValues: 200,
+				NullCount: 100,
+				MinValue:  parquet.ValueOf(int32(802)),
+				MaxValue:  parquet.ValueOf(int32(1000)),
+			},
+		},
+		{
+			column: "comment",
+			want: parquet.ColumnStats{
+				NumValues: 200,
+				NullCount: 200,
+			},
+		},
+	} {
+		stats, err := f.ColumnStats(test.column)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.NumValues != test.want.NumValues || stats.NullCount != test.want.NullCount ||
+			!parquet.Equal(stats.MinValue, test.want.MinValue) || !parquet.Equal(stats.MaxValue, test.want.MaxValue) {
+			t.Errorf("wrong statistics for column %q:\nwant = %+v\ngot  = %+v", test.column, test.want, stats)
+		}
+	}
+
+	if _, err := f.ColumnStats("missing"); err == nil {
+		t.Error("expected an error for a column which does not exist")
+	}
+
+	// Without the page index the statistics are read from the column chunks.
+	f, err = parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()), parquet.SkipPageIndex(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := f.ColumnStats("score")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.NumValues != 200 || stats.NullCount != 100 ||
+		!parquet.Equal(stats.MinValue, parquet.ValueOf(int32(802))) || !parquet.Equal(stats.MaxValue, parquet.ValueOf(int32(1000))) {
+		t.Errorf("wrong statistics without page index: %+v", stats)
+	}
+}
+
+func TestFileColumnStatsNotTruncated(t *testing.T) {
+	type Row struct {
+		Name string `parquet:"name"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.ColumnIndexSizeLimit(4))
+	for _, name := range []string{"banana", "apple-pie", "cherry"} {
+		if err := writer.Write(&Row{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := f.ColumnStats("name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if min, max := stats.MinValue.String(), stats.MaxValue.String(); min != "apple-pie" || max != "cherry" {
+		t.Errorf("wrong bounds: want=[apple-pie,cherry] got=[%s,%s]", min, max)
+	}
+}
+
+func TestFilePagesWithDictionary(t *testing.T) {
+	type Row struct {
+		Color string `parquet:"color,dict"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.DataPageMaxValues(10))
+	colors := []string{"red", "green", "blue"}
+	for i := 0; i < 30; i++ {
+		if err := writer.Write(&Row{Color: colors[i%len(colors)]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pages := parquet.PagesWithDictionary(f.RowGroup(0).Column(0).Pages())
+
+	readPages := func() (dictionary parquet.Page, numDataPages int) {
+		t.Helper()
+		err := forEachPage(pages, func(page parquet.Page) error {
+			if dictionary == nil {
+				dictionary = page
+				return nil
+			}
+			if page.Dictionary() == nil || page.Dictionary().Page() != dictionary {
+				return fmt.Errorf("data page %d does not refer to the dictionary page", numDataPages)
+			}
+			numDataPages++
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dictionary, numDataPages
+	}
+
+	dictionary, numDataPages := readPages()
+	if n := dictionary.NumValues(); n != int64(len(colors)) {
+		t.Errorf("wrong number of values in dictionary page: want=%d got=%d", len(colors), n)
+	}
+	if numDataPages != 3 {
+		t.Errorf("wrong number of data pages: want=3 got=%d", numDataPages)
+	}
+
+	if err := pages.SeekToRow(10); err != nil {
+		t.Fatal(err)
+	}
+	if _, numDataPages = readPages(); numDataPages != 2 {
+		t.Errorf("wrong number of data pages after seeking: want=2 got=%d", numDataPages)
+	}
+}
+
+func TestFileRowIterator(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	// Two row groups, to exercise iteration across row group boundaries.
+	for i := 0; i < 10; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Name: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+		if i == 4 {
+			if err := writer.Flush(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	it := f.RowIterator()
+	defer it.Close()
+
+	if err := it.Scan(new(Row)); err == nil {
+		t.Error("expected an error when calling Scan before Next")
+	}
+
+	n := 0
+	for it.Next() {
+		row := Row{}
+		if err := it.Scan(&row); err != nil {
+			t.Fatal(err)
+		}
+		if want := (Row{ID: int64(n), Name: fmt.Sprint(n)}); row != want {
+			t.Errorf("wrong row at index %d: want=%+v got=%+v", n, want, row)
+		}
+		n++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n != 10 {
+		t.Errorf("wrong number of rows: want=10 got=%d", n)
+	}
+
+	if it.Next() {
+		t.Error("Next returned true after the end of the iteration")
+	}
+	if err := it.Scan(new(Row)); err == nil {
+		t.Error("expected an error when calling Scan after the iterator was closed")
+	}
+	if err := it.Close(); err != nil {
+		t.Errorf("closing the iterator twice: %v", err)
+	}
+}
+
+func TestRowIteratorCloseEarly(t *testing.T) {
+	type Row struct {
+		ID int64 `parquet:"id"`
+	}
+
+	buffer := parquet.NewBuffer()
+	for i := 0; i < 3; i++ {
+		if err := buffer.Write(&Row{ID: int64(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	it := parquet.NewRowIterator(buffer)
+	if !it.Next() {
+		t.Fatalf("expected at least one row: %v", it.Err())
+	}
+	if err := it.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if it.Next() {
+		t.Error("Next returned true after the iterator was closed")
+	}
+	if row := it.Row(); row != nil {
+		t.Errorf("Row returned a non-nil row after the iterator was closed: %v", row)
+	}
+}
@@ -0,0 +1,462 @@
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/segmentio/parquet-go/format"
+)
+
+// This file contains the consistency checks applied to parquet files when the
+// StrictValidation option is enabled.
+
+func (f *File) validateMetadata() error {
+	for i := range f.rowGroups {
+		g := &f.rowGroups[i]
+
+		if g.rowGroup.NumRows < 0 {
+			return fmt.Errorf("row group %d has a negative number of rows: %d: %w", i, g.rowGroup.NumRows, ErrCorrupted)
+		}
+
+		for j := range g.columns {
+			if err := g.columns[j].validateMetadata(); err != nil {
+				return fmt.Errorf("row group %d: column %q: %w", i, columnPath(g.columns[j].column.Path()), err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *fileColumnChunk) validateMetadata() error {
+	if c.decryption != nil && c.decryption.err != nil {
+		return nil // the column key is not available
+	}
+
+	metadata := &c.chunk.MetaData
+	columnType := c.column.Type()
+	fail := func(msg string, args ...interface{}) error {
+		return fmt.Errorf("%s: %w", fmt.Sprintf(msg, args...), ErrCorrupted)
+	}
+
+	if metadata.NumValues < 0 {
+		return fail("negative number of values: %d", metadata.NumValues)
+	}
+	if metadata.TotalCompressedSize < 0 {
+		return fail("negative compressed size: %d", metadata.TotalCompressedSize)
+	}
+
+	chunkOffset := metadata.DataPageOffset
+	if metadata.DictionaryPageOffset != 0 {
+		if metadata.DictionaryPageOffset > metadata.DataPageOffset {
+			return fail("dictionary page offset %d is after the data page offset %d", metadata.DictionaryPageOffset, metadata.DataPageOffset)
+		}
+		chunkOffset = metadata.DictionaryPageOffset
+	}
+	chunkEnd := chunkOffset + metadata.TotalCompressedSize
+	if chunkOffset < 4 || chunkEnd > c.file.size {
+		return fail("column chunk at [%d:%d] exceeds the bounds of the file of size %d", chunkOffset, chunkEnd, c.file.size)
+	}
+
+	if stats := &metadata.Statistics; stats.MinValue != nil && stats.MaxValue != nil {
+		if err := validateBounds(columnType, stats.MinValue, stats.MaxValue); err != nil {
+			return fail("invalid column chunk statistics: %v", err)
+		}
+	}
+	if nullCount := metadata.Statistics.NullCount; nullCount < 0 || nullCount > metadata.NumValues {
+		return fail("null count %d out of range of the %d values of the column chunk", nullCount, metadata.NumValues)
+	}
+
+	if c.offsetIndex != nil {
+		pages := c.offsetIndex.PageLocations
+
+		for i := range pages {
+			page := &pages[i]
+
+			if page.Offset < chunkOffset || page.Offset+int64(page.CompressedPageSize) > chunkEnd {
+				return fail("page %d at [%d:%d] exceeds the bounds of the column chunk at [%d:%d]", i, page.Offset, page.Offset+int64(page.CompressedPageSize), chunkOffset, chunkEnd)
+			}
+			if page.CompressedPageSize <= 0 {
+				return fail("page %d has an invalid compressed size: %d", i, page.CompressedPageSize)
+			}
+
+			if i == 0 {
+				if page.FirstRowIndex != 0 {
+					return fail("first page starts at row %d instead of 0", page.FirstRowIndex)
+				}
+			} else {
+				prev := &pages[i-1]
+				if page.Offset < prev.Offset+int64(prev.CompressedPageSize) {
+					return fail("page %d at offset %d overlaps with page %d at [%d:%d]", i, page.Offset, i-1, prev.Offset, prev.Offset+int64(prev.CompressedPageSize))
+				}
+				if page.FirstRowIndex <= prev.FirstRowIndex {
+					return fail("page %d starts at row %d which is not after the first row %d of page %d", i, page.FirstRowIndex, prev.FirstRowIndex, i-1)
+				}
+			}
+
+			if page.FirstRowIndex >= c.rowGroup.NumRows {
+				return fail("page %d starts at row %d beyond the %d rows of the row group", i, page.FirstRowIndex, c.rowGroup.NumRows)
+			}
+		}
+	}
+
+	if c.columnIndex != nil {
+		index := c.columnIndex
+		numPages := len(index.NullPages)
+
+		if len(index.MinValues) != numPages || len(index.MaxValues) != numPages {
+			return fail("column index has %d null pages, %d min values, and %d max values", numPages, len(index.MinValues), len(index.MaxValues))
+		}
+		if len(index.NullCounts) != 0 && len(index.NullCounts) != numPages {
+			return fail("column index has %d null pages but %d null counts", numPages, len(index.NullCounts))
+		}
+		if c.offsetIndex != nil && len(c.offsetIndex.PageLocations) != numPages {
+			return fail("column index has %d pages but the offset index has %d", numPages, len(c.offsetIndex.PageLocations))
+		}
+
+		var prevMin, prevMax Value
+		var prevPage = -1
+
+		for i := 0; i < numPages; i++ {
+			if index.NullPages[i] {
+				continue
+			}
+			if err := validateBounds(columnType, index.MinValues[i], index.MaxValues[i]); err != nil {
+				return fail("invalid column index of page %d: %v", i, err)
+			}
+
+			minValue, _ := parseValue(columnType.Kind(), index.MinValues[i])
+			maxValue, _ := parseValue(columnType.Kind(), index.MaxValues[i])
+
+			if prevPage >= 0 {
+				var ordered bool
+				switch index.BoundaryOrder {
+				case format.Ascending:
+					ordered = columnType.Compare(prevMin, minValue) <= 0 && columnType.Compare(prevMax, maxValue) <= 0
+				case format.Descending:
+					ordered = columnType.Compare(prevMin, minValue) >= 0 && columnType.Compare(prevMax, maxValue) >= 0
+				default:
+					ordered = true
+				}
+				if !ordered {
+					return fail("bounds of page %d are not in %s order with page %d", i, index.BoundaryOrder, prevPage)
+				}
+			}
+
+			prevMin, prevMax, prevPage = minValue, maxValue, i
+		}
+	}
+
+	return nil
+}
+
+func validateBounds(typ Type, min, max []byte) error {
+	kind := typ.Kind()
+	minValue, err := parseValue(kind, min)
+	if err != nil {
+		return fmt.Errorf("decoding min value: %w", err)
+	}
+	maxValue, err := parseValue(kind, max)
+	if err != nil {
+		return fmt.Errorf("decoding max value: %w", err)
+	}
+	if typ.Compare(minValue, maxValue) > 0 {
+		return fmt.Errorf("min value %v is greater than max value %v", minValue, maxValue)
+	}
+	return nil
+}
+
+func (p *filePage) validateBounds() error {
+	if p.minValue.IsNull() || p.maxValue.IsNull() {
+		return nil
+	}
+	if p.columnType.Compare(p.minValue, p.maxValue) > 0 {
+		return fmt.Errorf("page %d of column %q has a min value %v greater than its max value %v: %w",
+			p.index, p.columnPath(), p.minValue, p.maxValue, ErrCorrupted)
+	}
+	return nil
+}
+
+// strictValueReader wraps the value readers of pages to verify that the levels
+// and counts of decoded values are consistent with the page header.
+type strictValueReader struct {
+	values     ValueReader
+	column     *Column
+	pageIndex  int
+	pageType   format.PageType
+	numValues  int64
+	numNulls   int64
+	numRows    int64
+	readValues int64
+	readNulls  int64
+	readRows   int64
+}
+
+func newStrictValueReader(values ValueReader, p *filePage) *strictValueReader {
+	return &strictValueReader{
+		values:    values,
+		column:    p.column,
+		pageIndex: p.index - 1, // incremented when the page was returned
+		pageType:  p.header.Type,
+		numValues: p.NumValues(),
+		numNulls:  p.NumNulls(),
+		numRows:   p.NumRows(),
+	}
+}
+
+func (r *strictValueReader) ReadValues(values []Value) (int, error) {
+	n, err := r.values.ReadValues(values)
+
+	maxRepetitionLevel := r.column.maxRepetitionLevel
+	maxDefinitionLevel := r.column.maxDefinitionLevel
+
+	for i, v := range values[:n] {
+		switch {
+		case v.repetitionLevel < 0 || v.repetitionLevel > maxRepetitionLevel:
+			return i, r.errorf("value %d has repetition level %d out of range [0:%d]", r.readValues, v.repetitionLevel, maxRepetitionLevel)
+		case v.definitionLevel < 0 || v.definitionLevel > maxDefinitionLevel:
+			return i, r.errorf("value %d has definition level %d out of range [0:%d]", r.readValues, v.definitionLevel, maxDefinitionLevel)
+		case r.pageType == format.DataPageV2 && r.readValues == 0 && v.repetitionLevel != 0:
+			return i, r.errorf("first value has repetition level %d but pages must start on row boundaries", v.repetitionLevel)
+		}
+		if v.definitionLevel < maxDefinitionLevel {
+			r.readNulls++
+		}
+		if v.repetitionLevel == 0 {
+			r.readRows++
+		}
+		r.readValues++
+	}
+
+	if r.readValues > r.numValues {
+		return n, r.errorf("%d values were decoded but the page header declares %d", r.readValues, r.numValues)
+	}
+
+	if err == io.EOF {
+		if r.readValues != r.numValues {
+			return n, r.errorf("%d values were decoded but the page header declares %d", r.readValues, r.numValues)
+		}
+		if r.pageType == format.DataPageV2 {
+			if r.readNulls != r.numNulls {
+				return n, r.errorf("%d null values were decoded but the page header declares %d", r.readNulls, r.numNulls)
+			}
+			if r.readRows != r.numRows {
+				return n, r.errorf("%d rows were decoded but the page header declares %d", r.readRows, r.numRows)
+			}
+		}
+	}
+
+	return n, err
+}
+
+func (r *strictValueReader) errorf(msg string, args ...interface{}) error {
+	return fmt.Errorf("page %d of column %q: %s: %w", r.pageIndex, columnPath(r.column.Path()), fmt.Sprintf(msg, args...), ErrCorrupted)
+}
+
+func (r *filePages) validateNumValues() error {
+	if numValues := r.column.chunk.MetaData.NumValues; r.numValues != numValues {
+		return fmt.Errorf("column %q has %d values in its pages but the column chunk metadata declares %d: %w",
+			r.page.columnPath(), r.numValues, numValues, ErrCorrupted)
+	}
+	return nil
+}
+
+// ValidationReport is the type of values returned by ValidateFile, describing
+// the content of a parquet file and the integrity issues found in it.
+type ValidationReport struct {
+	// Number of row groups, rows, and column chunks in the file.
+	NumRowGroups    int
+	NumRows         int64
+	NumColumnChunks int
+	// Number of data pages which were read, and number of values that were
+	// decoded when the DecodePages option was enabled.
+	NumPages  int64
+	NumValues int64
+	// The list of issues found in the file, empty if the file is valid.
+	Issues []*ValidationIssue
+}
+
+// Valid returns true if no issues were found in the file.
+func (r *ValidationReport) Valid() bool { return len(r.Issues) == 0 }
+
+// Err returns an error combining the issues of the report, or nil if the file
+// is valid.
+func (r *ValidationReport) Err() error {
+	if len(r.Issues) == 0 {
+		return nil
+	}
+	messages := make([]string, len(r.Issues))
+	for i, issue := range r.Issues {
+		messages[i] = issue.Error()
+	}
+	return fmt.Errorf("%d issues found in parquet file:\n%s", len(r.Issues), strings.Join(messages, "\n"))
+}
+
+// ValidationIssue describes an integrity issue found by ValidateFile.
+type ValidationIssue struct {
+	// Index of the row group where the issue was found, or -1 if the issue
+	// concerns the whole file.
+	RowGroup int
+	// Index and path of the column where the issue was found, or -1 and nil
+	// if the issue concerns a whole row group.
+	Column int
+	Path   []string
+	// Index of the page where the issue was found, or -1 if the issue concerns
+	// a whole column chunk.
+	Page int
+	// The error describing the issue.
+	Err error
+}
+
+// Error satisfies the error interface.
+func (i *ValidationIssue) Error() string {
+	switch {
+	case i.RowGroup < 0:
+		return i.Err.Error()
+	case i.Column < 0:
+		return fmt.Sprintf("row group %d: %v", i.RowGroup, i.Err)
+	case i.Page < 0:
+		return fmt.Sprintf("row group %d: column %q: %v", i.RowGroup, columnPath(i.Path), i.Err)
+	default:
+		return fmt.Sprintf("row group %d: column %q: page %d: %v", i.RowGroup, columnPath(i.Path), i.Page, i.Err)
+	}
+}
+
+// Unwrap returns the underlying error.
+func (i *ValidationIssue) Unwrap() error { return i.Err }
+
+// ValidateFile verifies the integrity of the parquet file of the given size
+// read from r, and returns a report of the issues that were found.
+//
+// The function verifies the consistency of the file metadata and page index,
+// then reads the pages of all column chunks to verify their headers and
+// checksums. When the DecodePages option is enabled, the values of all pages
+// are also decoded, verifying their levels and counts against the page
+// headers and column chunk metadata.
+//
+// Issues found in the file do not cause the function to return an error, they
+// are listed in the report; the validation continues with the next page or
+// column chunk when possible. A non-nil error is returned only if the file
+// could not be opened at all, for example because its footer is unreadable.
+func ValidateFile(r io.ReaderAt, size int64, options ...ValidationOption) (*ValidationReport, error) {
+	config, err := NewValidationConfig(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	report := new(ValidationReport)
+	f, err := OpenFile(r, size,
+		Decryption(config.Decryption),
+		SkipCorrupted(func(err error) {
+			e := err.(*CorruptedDataError)
+			report.addIssue(e.RowGroup, e.Column, nil, e.Page, e.Err)
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	f.strict = true
+
+	report.NumRowGroups = len(f.rowGroups)
+	numRows := int64(0)
+	for i := range f.rowGroups {
+		numRows += f.rowGroups[i].rowGroup.NumRows
+	}
+	report.NumRows = f.metadata.NumRows
+	if numRows != f.metadata.NumRows {
+		report.addIssue(-1, -1, nil, -1, fmt.Errorf("the file has %d rows but its row groups have %d: %w", f.metadata.NumRows, numRows, ErrCorrupted))
+	}
+
+	var values []Value
+	if config.DecodePages {
+		values = make([]Value, defaultValueBufferSize)
+	}
+
+	for i := range f.rowGroups {
+		g := &f.rowGroups[i]
+
+		if g.rowGroup.NumRows < 0 {
+			report.addIssue(i, -1, nil, -1, fmt.Errorf("negative number of rows: %d: %w", g.rowGroup.NumRows, ErrCorrupted))
+		}
+
+		for j := range g.columns {
+			c := &g.columns[j]
+			report.NumColumnChunks++
+
+			if c.decryption != nil && c.decryption.err != nil {
+				report.addIssue(i, j, c.column.Path(), -1, c.decryption.err)
+				continue
+			}
+			if err := c.validateMetadata(); err != nil {
+				report.addIssue(i, j, c.column.Path(), -1, err)
+				continue
+			}
+			report.validateColumnChunk(c, values)
+		}
+	}
+
+	// Errors reported while opening the file or skipping pages do not carry
+	// the column paths.
+	for _, issue := range report.Issues {
+		if issue.Path == nil && issue.RowGroup >= 0 && issue.Column >= 0 {
+			issue.Path = f.rowGroups[issue.RowGroup].columns[issue.Column].column.Path()
+		}
+	}
+
+	return report, nil
+}
+
+func (r *ValidationReport) validateColumnChunk(c *fileColumnChunk, values []Value) {
+	pages := new(filePages)
+	defer pages.Close()
+	c.setPagesOn(pages)
+
+	numRows := int64(0)
+	for {
+		p, err := pages.ReadPage()
+		if err != nil {
+			if err != io.EOF {
+				r.addIssue(c.rowGroupIndex, c.Column(), c.column.Path(), -1, err)
+			}
+			break
+		}
+		r.NumPages++
+
+		if values == nil {
+			continue
+		}
+
+		reader := p.Values()
+		for {
+			n, err := reader.ReadValues(values)
+			for _, v := range values[:n] {
+				if v.repetitionLevel == 0 {
+					numRows++
+				}
+			}
+			r.NumValues += int64(n)
+			if err != nil {
+				if err != io.EOF {
+					r.addIssue(c.rowGroupIndex, c.Column(), c.column.Path(), pages.page.index-1, err)
+					pages.seeked = true
+				}
+				break
+			}
+		}
+	}
+
+	if values != nil && !pages.seeked && numRows != c.rowGroup.NumRows {
+		r.addIssue(c.rowGroupIndex, c.Column(), c.column.Path(), -1,
+			fmt.Errorf("%d rows were decoded but the row group has %d: %w", numRows, c.rowGroup.NumRows, ErrCorrupted))
+	}
+}
+
+func (r *ValidationReport) addIssue(rowGroup, column int, path []string, page int, err error) {
+	r.Issues = append(r.Issues, &ValidationIssue{
+		RowGroup: rowGroup,
+		Column:   column,
+		Path:     path,
+		Page:     page,
+		Err:      err,
+	})
+}
@@ -217,6 +217,90 @@ type BsonType struct{}
 
 func (t *BsonType) String() string { return "BSON" }
 
+// Embedded Variant logical type annotation
+//
+// Semi-structured values encoded in the Variant binary format, as a pair of
+// metadata and value byte arrays, optionally shredded into a typed_value
+// column.
+//
+// Allowed for groups
+type VariantType struct {
+	SpecificationVersion int8 `thrift:"1,optional"`
+}
+
+func (t *VariantType) String() string {
+	if t.SpecificationVersion == 0 {
+		return "VARIANT"
+	}
+	return fmt.Sprintf("VARIANT(%d)", t.SpecificationVersion)
+}
+
+// Interpolation algorithm of the edges of GEOGRAPHY values, the edges are
+// interpolated as geodesic curves on the spheroid of the CRS.
+type EdgeInterpolationAlgorithm int32
+
+const (
+	Spherical EdgeInterpolationAlgorithm = 0
+	Vincenty  EdgeInterpolationAlgorithm = 1
+	Thomas    EdgeInterpolationAlgorithm = 2
+	Andoyer   EdgeInterpolationAlgorithm = 3
+	Karney    EdgeInterpolationAlgorithm = 4
+)
+
+func (a EdgeInterpolationAlgorithm) String() string {
+	switch a {
+	case Spherical:
+		return "SPHERICAL"
+	case Vincenty:
+		return "VINCENTY"
+	case Thomas:
+		return "THOMAS"
+	case Andoyer:
+		return "ANDOYER"
+	case Karney:
+		return "KARNEY"
+	default:
+		return "EdgeInterpolationAlgorithm(?)"
+	}
+}
+
+// Embedded Geometry logical type annotation
+//
+// Geospatial features in the Well-Known Binary (WKB) format, with edges
+// interpolated as straight lines in a planar coordinate system. The CRS
+// defaults to "OGC:CRS84" when empty.
+//
+// Allowed for physical types: BINARY
+type GeometryType struct {
+	CRS string `thrift:"1,optional"`
+}
+
+func (t *GeometryType) String() string {
+	if t.CRS == "" {
+		return "GEOMETRY"
+	}
+	return fmt.Sprintf("GEOMETRY(%s)", t.CRS)
+}
+
+// Embedded Geography logical type annotation
+//
+// Geospatial features in the Well-Known Binary (WKB) format, with edges
+// interpolated on the surface of the spheroid of the CRS. The CRS defaults to
+// "OGC:CRS84" when empty, the algorithm defaults to Spherical.
+//
+// Allowed for physical types: BINARY
+type GeographyType struct {
+	CRS       string                     `thrift:"1,optional"`
+	Algorithm EdgeInterpolationAlgorithm `thrift:"2,optional"`
+}
+
+func (t *GeographyType) String() string {
+	if t.CRS == "" && t.Algorithm == Spherical {
+		return "GEOGRAPHY"
+	}
+	return fmt.Sprintf("GEOGRAPHY(%s,%s)", t.CRS, t.Algorithm)
+}
+
 // LogicalType annotations to replace ConvertedType.
 //
 // To maintain compatibility, implementations using LogicalType for a
@@ -244,6 +328,11 @@ type LogicalType struct { // union
 	Json    *JsonType `thrift:"12"` // use ConvertedType JSON
 	Bson    *BsonType `thrift:"13"` // use ConvertedType BSON
 	UUID    *UUIDType `thrift:"14"` // no compatible ConvertedType
+
+	// 15: reserved for Float16
+	Variant   *VariantType   `thrift:"16"` // no compatible ConvertedType
+	Geometry  *GeometryType  `thrift:"17"` // no compatible ConvertedType
+	Geography *GeographyType `thrift:"18"` // no compatible ConvertedType
 }
 
 func (t *LogicalType) String() string {
@@ -274,6 +363,12 @@ func (t *LogicalType) String() string {
 		return t.Bson.String()
 	case t.UUID != nil:
 		return t.UUID.String()
+	case t.Variant != nil:
+		return t.Variant.String()
+	case t.Geometry != nil:
+		return t.Geometry.String()
+	case t.Geography != nil:
+		return t.Geography.String()
 	default:
 		return ""
 	}
@@ -742,6 +837,38 @@ type ColumnMetaData struct {
 
 	// Byte offset from beginning of file to Bloom filter data.
 	BloomFilterOffset int64 `thrift:"14,optional"`
+
+	// 15: reserved for the size of the Bloom filter
+	// 16: reserved for size statistics
+
+	// Optional statistics specific to GEOMETRY and GEOGRAPHY columns.
+	GeospatialStatistics *GeospatialStatistics `thrift:"17,optional"`
+}
+
+// Bounding box of GEOMETRY or GEOGRAPHY values.
+//
+// The X and Y values are the longitude and latitude when the CRS is geographic.
+// For GEOGRAPHY values, XMin may be greater than XMax when the bounding box
+// crosses the antimeridian.
+type BoundingBox struct {
+	XMin float64  `thrift:"1,required"`
+	XMax float64  `thrift:"2,required"`
+	YMin float64  `thrift:"3,required"`
+	YMax float64  `thrift:"4,required"`
+	ZMin *float64 `thrift:"5,optional"`
+	ZMax *float64 `thrift:"6,optional"`
+	MMin *float64 `thrift:"7,optional"`
+	MMax *float64 `thrift:"8,optional"`
+}
+
+// Statistics of GEOMETRY and GEOGRAPHY columns.
+type GeospatialStatistics struct {
+	// Bounding box of the values of the column.
+	BBox *BoundingBox `thrift:"1,optional"`
+
+	// Geospatial type codes of the values of the column, as defined by the
+	// WKB format (e.g. 1 for Point, 1001 for Point Z).
+	GeospatialTypes []int32 `thrift:"2,optional"`
 }
 
 type EncryptionWithFooterKey struct{}
@@ -36,3 +36,68 @@ func TestMarshalUnmarshalSchemaMetadata(t *testing.T) {
 		t.Logf("found:\n%#v", decoded)
 	}
 }
+
+func TestMarshalUnmarshalGeospatialMetadata(t *testing.T) {
+	protocol := &thrift.CompactProtocol{}
+	byteArray := format.ByteArray
+	zmin, zmax := -10.0, 8848.0
+	metadata := &format.FileMetaData{
+		Version: 1,
+		Schema: []format.SchemaElement{
+			{
+				Name:        "root",
+				NumChildren: 2,
+			},
+			{
+				Name:        "geometry",
+				Type:        &byteArray,
+				LogicalType: &format.LogicalType{Geometry: &format.GeometryType{CRS: "EPSG:4326"}},
+			},
+			{
+				Name:        "geography",
+				Type:        &byteArray,
+				LogicalType: &format.LogicalType{Geography: &format.GeographyType{Algorithm: format.Karney}},
+			},
+		},
+		RowGroups: []format.RowGroup{
+			{
+				Columns: []format.ColumnChunk{
+					{
+						MetaData: format.ColumnMetaData{
+							Type:         format.ByteArray,
+							Encoding:     []format.Encoding{format.Plain},
+							PathInSchema: []string{"geometry"},
+							GeospatialStatistics: &format.GeospatialStatistics{
+								BBox: &format.BoundingBox{
+									XMin: -180,
+									XMax: 180,
+									YMin: -90,
+									YMax: 90,
+									ZMin: &zmin,
+									ZMax: &zmax,
+								},
+								GeospatialTypes: []int32{1, 1003},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := thrift.Marshal(protocol, metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := &format.FileMetaData{}
+	if err := thrift.Unmarshal(protocol, b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(metadata, decoded) {
+		t.Error("values mismatch:")
+		t.Logf("expected:\n%#v", metadata)
+		t.Logf("found:\n%#v", decoded)
+	}
+}
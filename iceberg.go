@@ -0,0 +1,270 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"math"
+	"unicode/utf8"
+
+	"github.com/segmentio/parquet-go/format"
+)
+
+// IcebergMetrics carries the metrics of the columns of a parquet file in the
+// form expected by the data file entries of Iceberg manifests.
+//
+// The maps are keyed by the field ids of the leaf columns, columns which have
+// no field id are not reported.
+//
+// https://iceberg.apache.org/spec/#manifests
+type IcebergMetrics struct {
+	// Number of rows in the file.
+	RecordCount int64
+	// Number of values (including nulls) and null values of each column.
+	ValueCounts     map[int]int64
+	NullValueCounts map[int]int64
+	// Lower and upper bounds of the values of each column, serialized with the
+	// single-value binary serialization of Iceberg. Bounds are not reported
+	// for columns which only contain nulls, are nested in repeated columns, or
+	// have types which Iceberg cannot represent (e.g. unsigned integers).
+	LowerBounds map[int][]byte
+	UpperBounds map[int][]byte
+}
+
+// IcebergMetrics returns the metrics of the columns of the file written by w,
+// computed from the statistics collected by the writer on the pages that it
+// wrote; the method is intended to be called after closing the writer so the
+// metrics cover all the rows of the file.
+//
+// Bounds of string and binary columns are truncated to truncateLength unicode
+// code points or bytes, which corresponds to the truncate(N) metrics mode of
+// Iceberg tables (Iceberg defaults to truncate(16)). Upper bounds of truncated
+// values are incremented to remain greater than the values of the column, and
+// omitted when no such value exists. Bounds are not truncated when the length
+// is zero or negative.
+func (w *Writer) IcebergMetrics(truncateLength int) IcebergMetrics {
+	metrics := IcebergMetrics{
+		ValueCounts:     make(map[int]int64),
+		NullValueCounts: make(map[int]int64),
+		LowerBounds:     make(map[int][]byte),
+		UpperBounds:     make(map[int][]byte),
+	}
+	if w.writer == nil {
+		return metrics
+	}
+
+	for _, rowGroup := range w.writer.rowGroups {
+		metrics.RecordCount += rowGroup.NumRows
+	}
+
+	forEachLeafColumnOf(w.config.Schema, func(leaf leafColumn) {
+		id := fieldIDOf(leaf.node)
+		if id == 0 {
+			return
+		}
+		stats := &w.writer.columns[leaf.columnIndex].fileStats
+		metrics.ValueCounts[id] = stats.numValues
+		metrics.NullValueCounts[id] = stats.numNulls
+
+		if leaf.maxRepetitionLevel > 0 || stats.minValue.IsNull() || stats.maxValue.IsNull() {
+			return
+		}
+		typ := leaf.node.Type()
+		lower, lowerOK := icebergBoundOf(typ, stats.minValue, false, truncateLength)
+		upper, upperOK := icebergBoundOf(typ, stats.maxValue, true, truncateLength)
+		if lowerOK {
+			metrics.LowerBounds[id] = lower
+		}
+		if upperOK {
+			metrics.UpperBounds[id] = upper
+		}
+	})
+
+	return metrics
+}
+
+// icebergBoundOf serializes a lower or upper bound of a column of type t with
+// the single-value binary serialization of Iceberg. The function returns false
+// if the bound cannot be represented.
+//
+// https://iceberg.apache.org/spec/#binary-single-value-serialization
+func icebergBoundOf(t Type, v Value, upper bool, truncateLength int) ([]byte, bool) {
+	if lt := t.LogicalType(); lt != nil {
+		switch {
+		case lt.UTF8 != nil, lt.Enum != nil, lt.Json != nil:
+			return icebergTruncateString(v.ByteArray(), upper, truncateLength)
+
+		case lt.Bson != nil:
+			return icebergTruncateBinary(v.ByteArray(), upper, truncateLength)
+
+		case lt.UUID != nil:
+			return copyBytes(v.ByteArray()), true
+
+		case lt.Decimal != nil:
+			switch t.Kind() {
+			case Int32:
+				return icebergDecimal(int64(v.Int32())), true
+			case Int64:
+				return icebergDecimal(v.Int64()), true
+			default:
+				return icebergTrimDecimal(v.ByteArray()), true
+			}
+
+		case lt.Integer != nil:
+			if !lt.Integer.IsSigned {
+				return nil, false
+			}
+
+		case lt.Time != nil:
+			micros, ok := icebergMicros(t.Kind(), v, &lt.Time.Unit, upper)
+			if !ok {
+				return nil, false
+			}
+			return icebergUint64(uint64(micros)), true
+
+		case lt.Timestamp != nil:
+			// Iceberg represents nanosecond timestamps with the timestamp_ns
+			// type, other timestamps are expressed in microseconds.
+			if lt.Timestamp.Unit.Nanos != nil {
+				break
+			}
+			micros, _ := icebergMicros(t.Kind(), v, &lt.Timestamp.Unit, upper)
+			return icebergUint64(uint64(micros)), true
+
+		case lt.Date != nil:
+		default:
+			return nil, false
+		}
+	}
+
+	switch t.Kind() {
+	case Boolean:
+		if v.Boolean() {
+			return []byte{1}, true
+		}
+		return []byte{0}, true
+	case Int32:
+		return icebergUint32(uint32(v.Int32())), true
+	case Int64:
+		return icebergUint64(uint64(v.Int64())), true
+	case Float:
+		f := v.Float()
+		if math.IsNaN(float64(f)) {
+			return nil, false
+		}
+		return icebergUint32(math.Float32bits(f)), true
+	case Double:
+		f := v.Double()
+		if math.IsNaN(f) {
+			return nil, false
+		}
+		return icebergUint64(math.Float64bits(f)), true
+	case ByteArray:
+		return icebergTruncateBinary(v.ByteArray(), upper, truncateLength)
+	case FixedLenByteArray:
+		return copyBytes(v.ByteArray()), true
+	default:
+		return nil, false
+	}
+}
+
+// icebergMicros converts a TIME or TIMESTAMP value to microseconds, rounding
+// nanoseconds down for lower bounds and up for upper bounds.
+func icebergMicros(kind Kind, v Value, unit *format.TimeUnit, upper bool) (int64, bool) {
+	var n int64
+	if kind == Int32 {
+		n = int64(v.Int32())
+	} else {
+		n = v.Int64()
+	}
+	switch {
+	case unit.Millis != nil:
+		return n * 1000, true
+	case unit.Micros != nil:
+		return n, true
+	case unit.Nanos != nil:
+		micros := n / 1000
+		if r := n % 1000; r < 0 && !upper {
+			micros--
+		} else if r > 0 && upper {
+			micros++
+		}
+		return micros, true
+	default:
+		return 0, false
+	}
+}
+
+func icebergUint32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, v)
+	return b
+}
+
+func icebergUint64(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, v)
+	return b
+}
+
+// icebergDecimal returns the minimal big-endian two's complement representation
+// of the unscaled value of a decimal.
+func icebergDecimal(unscaled int64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(unscaled))
+	return icebergTrimDecimal(b)
+}
+
+// icebergTrimDecimal removes the leading bytes of a big-endian two's complement
+// integer which only carry its sign.
+func icebergTrimDecimal(b []byte) []byte {
+	for len(b) > 1 && ((b[0] == 0x00 && b[1]&0x80 == 0) || (b[0] == 0xFF && b[1]&0x80 != 0)) {
+		b = b[1:]
+	}
+	return copyBytes(b)
+}
+
+func icebergTruncateString(s []byte, upper bool, truncateLength int) ([]byte, bool) {
+	if truncateLength <= 0 || utf8.RuneCount(s) <= truncateLength {
+		return copyBytes(s), true
+	}
+
+	n := 0
+	for i := 0; i < truncateLength; i++ {
+		_, size := utf8.DecodeRune(s[n:])
+		n += size
+	}
+	prefix := s[:n]
+	if !upper {
+		return copyBytes(prefix), true
+	}
+
+	// Increment the last code point that can be incremented, dropping the ones
+	// after it, so the truncated value remains greater than the original.
+	for len(prefix) > 0 {
+		r, size := utf8.DecodeLastRune(prefix)
+		prefix = prefix[:len(prefix)-size]
+		for r++; r <= utf8.MaxRune; r++ {
+			if utf8.ValidRune(r) {
+				return utf8.AppendRune(copyBytes(prefix), r), true
+			}
+		}
+	}
+	return nil, false
+}
+
+func icebergTruncateBinary(b []byte, upper bool, truncateLength int) ([]byte, bool) {
+	if truncateLength <= 0 || len(b) <= truncateLength {
+		return copyBytes(b), true
+	}
+	if !upper {
+		return copyBytes(b[:truncateLength]), true
+	}
+
+	prefix := copyBytes(b[:truncateLength])
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xFF {
+			prefix[i]++
+			return prefix[:i+1], true
+		}
+	}
+	return nil, false
+}
@@ -25,3 +25,7 @@ func BytesToSlice[T any](data []byte) []T {
 func BytesToString(data []byte) string {
 	return *(*string)(unsafe.Pointer(&data))
 }
+
+func StringToBytes(data string) []byte {
+	return unsafe.Slice(*(**byte)(unsafe.Pointer(&data)), len(data))
+}
@@ -0,0 +1,791 @@
+package parquet
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/segmentio/parquet-go/deprecated"
+	"github.com/segmentio/parquet-go/format"
+)
+
+// JSONWriter is a row writer which formats parquet rows as JSON objects, one
+// object per line (a format often referred to as JSON Lines or NDJSON).
+//
+// Rows are reconstructed using the schema of the writer: groups become JSON
+// objects, repeated fields and LIST columns become JSON arrays, and MAP
+// columns become JSON objects keyed by the text representation of their keys.
+// Null values are written as JSON null.
+//
+// Leaf values are formatted according to the logical type of their column;
+// strings, enums, UUIDs, dates, times, and timestamps are written as JSON
+// strings, decimals as JSON numbers, and columns of the JSON logical type are
+// embedded as-is. Binary values with no logical type are encoded in base64,
+// matching the behavior of the encoding/json package for []byte values.
+//
+// JSONWriter implements the RowWriterWithSchema interface, which makes it
+// possible to use it as destination of a call to CopyRows:
+//
+//	rowGroup := file.RowGroup(0)
+//	w := parquet.NewJSONWriter(os.Stdout, rowGroup.Schema())
+//	if _, err := parquet.CopyRows(w, rowGroup.Rows()); err != nil {
+//		...
+//	}
+//
+type JSONWriter struct {
+	output    io.Writer
+	schema    *Schema
+	buffer    []byte
+	values    []Value
+	formatter rowJSONFormatter
+}
+
+// NewJSONWriter constructs a JSON writer which writes rows of the given schema
+// to output.
+func NewJSONWriter(output io.Writer, schema *Schema) *JSONWriter {
+	return &JSONWriter{
+		output:    output,
+		schema:    schema,
+		formatter: makeRowJSONFormatter(int(numLeafColumnsOf(schema))),
+	}
+}
+
+// Schema returns the schema of rows written to w.
+func (w *JSONWriter) Schema() *Schema { return w.schema }
+
+// Write deconstructs the Go value passed as argument into a row and writes it
+// as a JSON object.
+func (w *JSONWriter) Write(row interface{}) error {
+	w.values = w.schema.Deconstruct(w.values[:0], row)
+	return w.WriteRow(w.values)
+}
+
+// WriteRow writes a parquet row as a JSON object followed by a new line.
+func (w *JSONWriter) WriteRow(row Row) error {
+	b, err := w.formatter.formatRow(w.buffer[:0], w.schema, row)
+	w.buffer = b
+	if err != nil {
+		return err
+	}
+	w.buffer = append(w.buffer, '\n')
+	_, err = w.output.Write(w.buffer)
+	return err
+}
+
+// rowJSONFormatter reconstructs the nested structure of parquet rows to
+// format them as JSON objects.
+//
+// The formatter groups the values of each row by column, then walks the
+// schema, consuming values from the column cursors as it descends into the
+// nodes. Repetition and definition levels are used to determine where lists
+// begin and end, and which values are null.
+type rowJSONFormatter struct {
+	buffer  []byte
+	columns [][]Value
+	cursors [][]Value
+	err     error
+}
+
+func makeRowJSONFormatter(numColumns int) rowJSONFormatter {
+	return rowJSONFormatter{
+		columns: make([][]Value, numColumns),
+		cursors: make([][]Value, numColumns),
+	}
+}
+
+// formatRow appends the JSON representation of row to b, using the given
+// schema to reconstruct the nested structure of the row.
+func (r *rowJSONFormatter) formatRow(b []byte, schema Node, row Row) ([]byte, error) {
+	for i := range r.columns {
+		r.columns[i] = r.columns[i][:0]
+	}
+
+	for _, v := range row {
+		columnIndex := v.Column()
+		if columnIndex < 0 || columnIndex >= len(r.columns) {
+			return b, fmt.Errorf("cannot format value of column %d in row of schema with %d columns", columnIndex, len(r.columns))
+		}
+		r.columns[columnIndex] = append(r.columns[columnIndex], v)
+	}
+
+	copy(r.cursors, r.columns)
+	r.buffer, r.err = b, nil
+	r.formatGroup(schema, 0, levels{})
+	b, err := r.buffer, r.err
+	r.buffer, r.err = nil, nil
+
+	if err == nil {
+		for i, values := range r.cursors {
+			if len(values) != 0 {
+				err = fmt.Errorf("%d values remain unused in column %d after formatting row as JSON", len(values), i)
+				break
+			}
+		}
+	}
+
+	for i := range r.columns {
+		clearValues(r.columns[i])
+		r.cursors[i] = nil
+	}
+	return b, err
+}
+
+func (r *rowJSONFormatter) peek(columnIndex int) (Value, bool) {
+	if values := r.cursors[columnIndex]; len(values) > 0 {
+		return values[0], true
+	}
+	if r.err == nil {
+		r.err = fmt.Errorf("row is missing values for column %d", columnIndex)
+	}
+	return Value{}, false
+}
+
+func (r *rowJSONFormatter) next(columnIndex int) (Value, bool) {
+	v, ok := r.peek(columnIndex)
+	if ok {
+		r.cursors[columnIndex] = r.cursors[columnIndex][1:]
+	}
+	return v, ok
+}
+
+// skip consumes the value representing a null or empty node in each leaf
+// column of the node starting at columnIndex.
+func (r *rowJSONFormatter) skip(node Node, columnIndex int) {
+	for i, n := columnIndex, columnIndex+int(numLeafColumnsOf(node)); i < n; i++ {
+		r.next(i)
+	}
+}
+
+func (r *rowJSONFormatter) format(node Node, columnIndex int, lvls levels) {
+	switch {
+	case node.Optional():
+		lvls.definitionLevel++
+		if v, ok := r.peek(columnIndex); ok && v.definitionLevel < lvls.definitionLevel {
+			r.buffer = append(r.buffer, "null"...)
+			r.skip(node, columnIndex)
+			return
+		}
+		r.formatRequired(node, columnIndex, lvls)
+
+	case node.Repeated():
+		r.formatRepeated(node, columnIndex, lvls, func(lvls levels) {
+			r.formatRequired(node, columnIndex, lvls)
+		})
+
+	default:
+		r.formatRequired(node, columnIndex, lvls)
+	}
+}
+
+func (r *rowJSONFormatter) formatRequired(node Node, columnIndex int, lvls levels) {
+	switch {
+	case isLeaf(node):
+		r.formatLeaf(node, columnIndex)
+
+	case isList(node):
+		list := node.ChildByName("list")
+		elem := listElementOf(node)
+		r.formatRepeated(list, columnIndex, lvls, func(lvls levels) {
+			r.format(elem, columnIndex, lvls)
+		})
+
+	case isMap(node):
+		keyValue := mapKeyValueOf(node)
+		r.formatMap(keyValue, columnIndex, lvls)
+
+	default:
+		r.formatGroup(node, columnIndex, lvls)
+	}
+}
+
+func (r *rowJSONFormatter) formatGroup(node Node, columnIndex int, lvls levels) {
+	r.buffer = append(r.buffer, '{')
+
+	for i, name := range node.ChildNames() {
+		if i != 0 {
+			r.buffer = append(r.buffer, ',')
+		}
+		child := node.ChildByName(name)
+		r.buffer = appendJSONString(r.buffer, name)
+		r.buffer = append(r.buffer, ':')
+		r.format(child, columnIndex, lvls)
+		columnIndex += int(numLeafColumnsOf(child))
+	}
+
+	r.buffer = append(r.buffer, '}')
+}
+
+func (r *rowJSONFormatter) formatRepeated(node Node, columnIndex int, lvls levels, formatElem func(levels)) {
+	lvls.repetitionDepth++
+	lvls.definitionLevel++
+
+	r.buffer = append(r.buffer, '[')
+
+	if v, ok := r.peek(columnIndex); ok && v.definitionLevel < lvls.definitionLevel {
+		r.skip(node, columnIndex)
+	} else {
+		for i := 0; r.err == nil; i++ {
+			if i != 0 {
+				r.buffer = append(r.buffer, ',')
+			}
+			formatElem(lvls)
+			lvls.repetitionLevel = lvls.repetitionDepth
+
+			if !r.continues(columnIndex, lvls) {
+				break
+			}
+		}
+	}
+
+	r.buffer = append(r.buffer, ']')
+}
+
+func (r *rowJSONFormatter) formatMap(keyValue Node, columnIndex int, lvls levels) {
+	// The key is a required leaf which always comes first in the key_value
+	// group, its column is immediately followed by the columns of the value.
+	key := keyValue.ChildByName("key")
+	value := keyValue.ChildByName("value")
+	keyColumnIndex, valueColumnIndex := columnIndex, columnIndex+1
+
+	lvls.repetitionDepth++
+	lvls.definitionLevel++
+
+	r.buffer = append(r.buffer, '{')
+
+	if v, ok := r.peek(columnIndex); ok && v.definitionLevel < lvls.definitionLevel {
+		r.skip(keyValue, columnIndex)
+	} else {
+		for i := 0; r.err == nil; i++ {
+			if i != 0 {
+				r.buffer = append(r.buffer, ',')
+			}
+
+			k, _ := r.next(keyColumnIndex)
+			if k.Kind() == ByteArray {
+				r.buffer = appendJSONString(r.buffer, unsafeBytesToString(k.ByteArray()))
+			} else {
+				r.buffer = appendJSONString(r.buffer, string(appendValueText(nil, key.Type(), k)))
+			}
+
+			r.buffer = append(r.buffer, ':')
+			r.format(value, valueColumnIndex, lvls)
+
+			if !r.continues(columnIndex, lvls) {
+				break
+			}
+		}
+	}
+
+	r.buffer = append(r.buffer, '}')
+}
+
+// continues returns true if the next value of the column at columnIndex is
+// part of the current repeated sequence.
+func (r *rowJSONFormatter) continues(columnIndex int, lvls levels) bool {
+	values := r.cursors[columnIndex]
+	return len(values) > 0 && values[0].repetitionLevel == lvls.repetitionDepth
+}
+
+func (r *rowJSONFormatter) formatLeaf(node Node, columnIndex int) {
+	if v, ok := r.next(columnIndex); ok {
+		r.buffer = appendValueJSON(r.buffer, node.Type(), v)
+	}
+}
+
+// appendValueJSON appends the JSON representation of v to b, honoring the
+// logical type of t.
+func appendValueJSON(b []byte, t Type, v Value) []byte {
+	if v.IsNull() {
+		return append(b, "null"...)
+	}
+
+	if lt := t.LogicalType(); lt != nil {
+		switch {
+		case lt.Json != nil:
+			if data := v.ByteArray(); json.Valid(data) {
+				return append(b, data...)
+			}
+			return appendJSONString(b, unsafeBytesToString(v.ByteArray()))
+
+		case lt.Bson != nil:
+			return appendJSONBase64(b, v.ByteArray())
+
+		case lt.Decimal != nil, lt.Integer != nil:
+			return appendValueText(b, t, v)
+
+		default:
+			return appendJSONString(b, string(appendValueText(nil, t, v)))
+		}
+	}
+
+	switch v.Kind() {
+	case Boolean, Int32, Int64, Int96:
+		return appendValueText(b, t, v)
+	case Float:
+		return appendJSONFloat(b, float64(v.Float()), 32)
+	case Double:
+		return appendJSONFloat(b, v.Double(), 64)
+	default:
+		return appendJSONBase64(b, v.ByteArray())
+	}
+}
+
+func appendJSONFloat(b []byte, f float64, bitSize int) []byte {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		// JSON has no representation for these values, we fallback to
+		// quoting them so the output remains valid.
+		b = append(b, '"')
+		b = strconv.AppendFloat(b, f, 'g', -1, bitSize)
+		return append(b, '"')
+	}
+	return strconv.AppendFloat(b, f, 'g', -1, bitSize)
+}
+
+func appendJSONBase64(b []byte, data []byte) []byte {
+	n := base64.StdEncoding.EncodedLen(len(data))
+	b = append(b, '"')
+	i := len(b)
+	b = append(b, make([]byte, n)...)
+	base64.StdEncoding.Encode(b[i:], data)
+	return append(b, '"')
+}
+
+func appendJSONString(b []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	b = append(b, '"')
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				b = append(b, '\\', c)
+			case c == '\n':
+				b = append(b, '\\', 'n')
+			case c == '\r':
+				b = append(b, '\\', 'r')
+			case c == '\t':
+				b = append(b, '\\', 't')
+			case c < 0x20:
+				b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			default:
+				b = append(b, c)
+			}
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, `\ufffd`...)
+		} else {
+			b = append(b, s[i:i+size]...)
+		}
+		i += size
+	}
+
+	return append(b, '"')
+}
+
+// JSONReader is a row reader which parses a stream of JSON objects into
+// parquet rows.
+//
+// The input is expected to be a sequence of JSON objects separated by white
+// spaces, which is the case of newline-delimited JSON streams. Each object is
+// converted to a row of the reader's schema: JSON objects map to groups and
+// MAP columns, JSON arrays map to repeated fields and LIST columns, and null
+// or missing fields produce null values on optional columns.
+//
+// Leaf values are converted according to the type of their column; strings
+// holding RFC 3339 timestamps, dates, or UUIDs are parsed into columns of the
+// corresponding logical types, and binary columns with no logical type expect
+// base64 strings, matching the output of JSONWriter. The handling of missing
+// and unknown fields, and the type coercion rules can be configured with
+// JSONOption values.
+//
+// JSONReader implements the RowReaderWithSchema interface, which makes it
+// possible to use it as source of a call to CopyRows to write JSON streams to
+// parquet files:
+//
+//	r := parquet.NewJSONReader(input, schema)
+//	w := parquet.NewWriter(output, schema)
+//	if _, err := parquet.CopyRows(w, r); err != nil {
+//		...
+//	}
+//
+type JSONReader struct {
+	input  *json.Decoder
+	config *JSONConfig
+	schema *Schema
+	index  int64
+}
+
+// NewJSONReader constructs a JSON reader which parses objects from input into
+// rows of the given schema.
+//
+// The function panics if the configuration options are invalid.
+func NewJSONReader(input io.Reader, schema *Schema, options ...JSONOption) *JSONReader {
+	config, err := NewJSONConfig(options...)
+	if err != nil {
+		panic(err)
+	}
+	r := &JSONReader{
+		input:  json.NewDecoder(input),
+		config: config,
+		schema: schema,
+	}
+	r.input.UseNumber()
+	return r
+}
+
+// Schema returns the schema of rows read from r.
+func (r *JSONReader) Schema() *Schema { return r.schema }
+
+// ReadRow reads the next JSON object and appends its values to row.
+//
+// The method returns io.EOF when there are no more objects to read.
+func (r *JSONReader) ReadRow(row Row) (Row, error) {
+	var object interface{}
+
+	if err := r.input.Decode(&object); err != nil {
+		if err == io.EOF {
+			return row, err
+		}
+		return row, fmt.Errorf("row %d: %w", r.index, err)
+	}
+
+	p := &rowJSONParser{config: r.config, row: row}
+	p.parseGroup(r.schema, 0, levels{}, object)
+	if p.err != nil {
+		return row, fmt.Errorf("row %d: %w", r.index, p.err)
+	}
+
+	r.index++
+	return p.row, nil
+}
+
+// rowJSONParser converts values decoded from JSON into parquet row values,
+// generating repetition and definition levels as it descends into the schema.
+//
+// The values are produced in the same order as the one used when
+// deconstructing Go values into rows.
+type rowJSONParser struct {
+	config *JSONConfig
+	row    Row
+	path   columnPath
+	err    error
+}
+
+func (p *rowJSONParser) fail(err error) {
+	if p.err == nil {
+		if len(p.path) != 0 {
+			err = fmt.Errorf("%s: %w", p.path, err)
+		}
+		p.err = err
+	}
+}
+
+// null appends null values to the leaf columns of node, using the levels
+// passed as argument.
+func (p *rowJSONParser) null(node Node, columnIndex int, lvls levels) {
+	for i, n := columnIndex, columnIndex+int(numLeafColumnsOf(node)); i < n; i++ {
+		p.row = append(p.row, Value{
+			repetitionLevel: lvls.repetitionLevel,
+			definitionLevel: lvls.definitionLevel,
+			columnIndex:     ^int16(i),
+		})
+	}
+}
+
+func (p *rowJSONParser) parse(node Node, columnIndex int, lvls levels, value interface{}, found bool) {
+	switch {
+	case node.Optional():
+		if value == nil {
+			p.null(node, columnIndex, lvls)
+			return
+		}
+		lvls.definitionLevel++
+		p.parseRequired(node, columnIndex, lvls, value, true)
+
+	case node.Repeated():
+		p.parseRepeated(node, columnIndex, lvls, value, func(lvls levels, elem interface{}) {
+			p.parseRequired(node, columnIndex, lvls, elem, true)
+		})
+
+	default:
+		p.parseRequired(node, columnIndex, lvls, value, found)
+	}
+}
+
+func (p *rowJSONParser) parseRequired(node Node, columnIndex int, lvls levels, value interface{}, found bool) {
+	switch {
+	case isLeaf(node):
+		p.parseLeaf(node, columnIndex, lvls, value, found)
+
+	case isList(node):
+		elem := listElementOf(node)
+		p.parseRepeated(node.ChildByName("list"), columnIndex, lvls, value, func(lvls levels, value interface{}) {
+			p.parse(elem, columnIndex, lvls, value, true)
+		})
+
+	case isMap(node):
+		p.parseMap(mapKeyValueOf(node), columnIndex, lvls, value)
+
+	default:
+		if value == nil && !found && !p.config.ZeroMissingFields {
+			p.fail(fmt.Errorf("missing required group"))
+			return
+		}
+		p.parseGroup(node, columnIndex, lvls, value)
+	}
+}
+
+func (p *rowJSONParser) parseGroup(node Node, columnIndex int, lvls levels, value interface{}) {
+	object, ok := value.(map[string]interface{})
+	if !ok && value != nil {
+		p.fail(fmt.Errorf("cannot parse JSON %s as group", jsonTypeOf(value)))
+		return
+	}
+
+	if p.config.DisallowUnknownFields {
+		for name := range object {
+			if node.ChildByName(name) == nil {
+				p.fail(fmt.Errorf("unknown field %q", name))
+				return
+			}
+		}
+	}
+
+	for _, name := range node.ChildNames() {
+		child := node.ChildByName(name)
+		field, found := object[name]
+		p.path = append(p.path, name)
+		p.parse(child, columnIndex, lvls, field, found)
+		p.path = p.path[:len(p.path)-1]
+		columnIndex += int(numLeafColumnsOf(child))
+		if p.err != nil {
+			return
+		}
+	}
+}
+
+func (p *rowJSONParser) parseRepeated(node Node, columnIndex int, lvls levels, value interface{}, parseElem func(levels, interface{})) {
+	var array []interface{}
+
+	switch v := value.(type) {
+	case nil:
+	case []interface{}:
+		array = v
+	default:
+		if p.config.StrictTypes {
+			p.fail(fmt.Errorf("cannot parse JSON %s as repeated field", jsonTypeOf(value)))
+			return
+		}
+		array = []interface{}{v}
+	}
+
+	if len(array) == 0 {
+		p.null(node, columnIndex, lvls)
+		return
+	}
+
+	lvls.repetitionDepth++
+	lvls.definitionLevel++
+
+	for _, elem := range array {
+		parseElem(lvls, elem)
+		lvls.repetitionLevel = lvls.repetitionDepth
+	}
+}
+
+func (p *rowJSONParser) parseMap(keyValue Node, columnIndex int, lvls levels, value interface{}) {
+	object, ok := value.(map[string]interface{})
+	if !ok && value != nil {
+		p.fail(fmt.Errorf("cannot parse JSON %s as map", jsonTypeOf(value)))
+		return
+	}
+
+	if len(object) == 0 {
+		p.null(keyValue, columnIndex, lvls)
+		return
+	}
+
+	keys := make([]string, 0, len(object))
+	for k := range object {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	keyType := keyValue.ChildByName("key").Type()
+	valueNode := keyValue.ChildByName("value")
+
+	lvls.repetitionDepth++
+	lvls.definitionLevel++
+
+	for _, k := range keys {
+		key, err := parseValueText(keyType, k)
+		if err != nil {
+			p.fail(err)
+			return
+		}
+		key.repetitionLevel = lvls.repetitionLevel
+		key.definitionLevel = lvls.definitionLevel
+		key.columnIndex = ^int16(columnIndex)
+		p.row = append(p.row, key)
+		p.parse(valueNode, columnIndex+1, lvls, object[k], true)
+		lvls.repetitionLevel = lvls.repetitionDepth
+	}
+}
+
+func (p *rowJSONParser) parseLeaf(node Node, columnIndex int, lvls levels, value interface{}, found bool) {
+	typ := node.Type()
+
+	var v Value
+	var err error
+
+	if value == nil {
+		if !p.config.ZeroMissingFields {
+			if found {
+				p.fail(fmt.Errorf("null value for required column"))
+			} else {
+				p.fail(fmt.Errorf("missing value for required column"))
+			}
+			return
+		}
+		v = zeroValueOf(typ)
+	} else if v, err = p.parseValue(typ, value); err != nil {
+		p.fail(err)
+		return
+	}
+
+	v.repetitionLevel = lvls.repetitionLevel
+	v.definitionLevel = lvls.definitionLevel
+	v.columnIndex = ^int16(columnIndex)
+	p.row = append(p.row, v)
+}
+
+func (p *rowJSONParser) parseValue(t Type, value interface{}) (Value, error) {
+	lt := t.LogicalType()
+
+	if lt != nil && lt.Json != nil {
+		// Columns of the JSON logical type may hold any JSON value, strings
+		// are stored as-is since they most likely already contain JSON.
+		if s, ok := value.(string); ok {
+			return makeValueString(t.Kind(), s), nil
+		}
+		b, err := json.Marshal(value)
+		return makeValueBytes(t.Kind(), b), err
+	}
+
+	switch v := value.(type) {
+	case bool:
+		if t.Kind() == Boolean {
+			return makeValueBoolean(v), nil
+		}
+		if !p.config.StrictTypes && t.Kind() == ByteArray {
+			return makeValueString(ByteArray, strconv.FormatBool(v)), nil
+		}
+
+	case json.Number:
+		switch {
+		case lt != nil && (lt.Date != nil || lt.Time != nil || lt.Timestamp != nil):
+			// Numbers are interpreted as the raw value of temporal columns,
+			// in the unit that they were declared with.
+			return parseValueText(&intNumberType{t}, v.String())
+		case lt != nil && (lt.UTF8 != nil || lt.Enum != nil):
+			if !p.config.StrictTypes {
+				return makeValueString(t.Kind(), v.String()), nil
+			}
+		default:
+			switch t.Kind() {
+			case Int32, Int64, Int96, Float, Double:
+				return parseValueText(t, v.String())
+			}
+		}
+
+	case string:
+		switch {
+		case lt != nil:
+			if lt.UTF8 != nil || lt.Enum != nil || !p.config.StrictTypes {
+				return parseValueText(t, v)
+			}
+			switch {
+			case lt.Date != nil, lt.Time != nil, lt.Timestamp != nil, lt.UUID != nil:
+				return parseValueText(t, v)
+			}
+		case t.Kind() == ByteArray || t.Kind() == FixedLenByteArray:
+			b, err := base64.StdEncoding.DecodeString(v)
+			if err != nil {
+				if p.config.StrictTypes {
+					return Value{}, fmt.Errorf("cannot decode base64 string: %w", err)
+				}
+				b = []byte(v)
+			}
+			if t.Kind() == FixedLenByteArray && len(b) != t.Length() {
+				return Value{}, fmt.Errorf("cannot parse %d bytes into %s value", len(b), t)
+			}
+			return makeValueBytes(t.Kind(), b), nil
+		case !p.config.StrictTypes:
+			return parseValueText(t, v)
+		}
+	}
+
+	return Value{}, fmt.Errorf("cannot parse JSON %s into %s column", jsonTypeOf(value), t)
+}
+
+// intNumberType is used to strip the logical type of temporal columns when
+// parsing JSON numbers, so the numbers are interpreted as raw integers.
+type intNumberType struct{ Type }
+
+func (t *intNumberType) LogicalType() *format.LogicalType { return nil }
+
+func jsonTypeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func zeroValueOf(t Type) Value {
+	switch kind := t.Kind(); kind {
+	case Boolean:
+		return makeValueBoolean(false)
+	case Int32:
+		return makeValueInt32(0)
+	case Int64:
+		return makeValueInt64(0)
+	case Int96:
+		return makeValueInt96(deprecated.Int96{})
+	case Float:
+		return makeValueFloat(0)
+	case Double:
+		return makeValueDouble(0)
+	case FixedLenByteArray:
+		return makeValueBytes(kind, make([]byte, t.Length()))
+	default:
+		return makeValueBytes(kind, nil)
+	}
+}
+
+var (
+	_ RowWriterWithSchema = (*JSONWriter)(nil)
+	_ RowReaderWithSchema = (*JSONReader)(nil)
+)
@@ -0,0 +1,260 @@
+package parquet_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestJSONWriter(t *testing.T) {
+	type contact struct {
+		Kind  string  `parquet:"kind,enum"`
+		Value *string `parquet:"value,optional"`
+	}
+
+	type person struct {
+		Name     string           `parquet:"name"`
+		Age      *int32           `parquet:"age,optional"`
+		Balance  int64            `parquet:"balance,decimal(2:18)"`
+		Born     int32            `parquet:"born,date"`
+		Seen     int64            `parquet:"seen,timestamp"`
+		Data     []byte           `parquet:"data"`
+		Scores   []float64        `parquet:"scores,list"`
+		Contacts []contact        `parquet:"contacts"`
+		Labels   map[string]int64 `parquet:"labels"`
+	}
+
+	age := int32(42)
+	email := "luke@example.com"
+	rows := []person{
+		{
+			Name:    "Luke \"Skywalker\"",
+			Age:     &age,
+			Balance: 12345,
+			Born:    365,
+			Seen:    1000,
+			Data:    []byte{0, 1, 2},
+			Scores:  []float64{0.5, 1},
+			Contacts: []contact{
+				{Kind: "email", Value: &email},
+				{Kind: "phone"},
+			},
+			Labels: map[string]int64{"a": 1, "b": 2},
+		},
+		{
+			Name: "Leia",
+		},
+	}
+
+	want := []string{
+		`{
+			"age": 42,
+			"balance": 123.45,
+			"born": "1971-01-01",
+			"contacts": [
+				{"kind": "email", "value": "luke@example.com"},
+				{"kind": "phone", "value": null}
+			],
+			"data": "AAEC",
+			"labels": {"a": 1, "b": 2},
+			"name": "Luke \"Skywalker\"",
+			"scores": [0.5, 1],
+			"seen": "1970-01-01T00:00:01Z"
+		}`,
+		`{
+			"age": null,
+			"balance": 0,
+			"born": "1970-01-01",
+			"contacts": [],
+			"data": "",
+			"labels": {},
+			"name": "Leia",
+			"scores": [],
+			"seen": "1970-01-01T00:00:00Z"
+		}`,
+	}
+
+	schema := parquet.SchemaOf(rows[0])
+	buffer := parquet.NewBuffer(schema)
+	for _, row := range rows {
+		if err := buffer.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, test := range []struct {
+		scenario string
+		write    func(*parquet.JSONWriter) error
+	}{
+		{
+			scenario: "write go values",
+			write: func(w *parquet.JSONWriter) error {
+				for _, row := range rows {
+					if err := w.Write(row); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+
+		{
+			scenario: "copy rows from buffer",
+			write: func(w *parquet.JSONWriter) error {
+				_, err := parquet.CopyRows(w, buffer.Rows())
+				return err
+			},
+		},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			output := new(strings.Builder)
+			if err := test.write(parquet.NewJSONWriter(output, schema)); err != nil {
+				t.Fatal(err)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+			if len(lines) != len(want) {
+				t.Fatalf("wrong number of lines: want=%d got=%d\n%s", len(want), len(lines), output)
+			}
+
+			for i, line := range lines {
+				var got, exp interface{}
+				if err := json.Unmarshal([]byte(line), &got); err != nil {
+					t.Fatalf("line %d is not valid JSON: %v\n%s", i, err, line)
+				}
+				if err := json.Unmarshal([]byte(want[i]), &exp); err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got, exp) {
+					t.Errorf("line %d mismatch:\nwant: %s\ngot:  %s", i, want[i], line)
+				}
+			}
+		})
+	}
+}
+
+func TestJSONReader(t *testing.T) {
+	type contact struct {
+		Kind  string  `parquet:"kind,enum"`
+		Value *string `parquet:"value,optional"`
+	}
+
+	type event struct {
+		ID       int64            `parquet:"id"`
+		Name     string           `parquet:"name"`
+		Amount   int64            `parquet:"amount,decimal(2:18)"`
+		Day      int32            `parquet:"day,date"`
+		Time     int64            `parquet:"time,timestamp"`
+		Data     []byte           `parquet:"data"`
+		Scores   []float64        `parquet:"scores,list"`
+		Contacts []contact        `parquet:"contacts"`
+		Labels   map[string]int64 `parquet:"labels"`
+		Score    *float32         `parquet:"score,optional"`
+	}
+
+	const input = `{"id":1,"name":"hello","amount":12.34,"day":"1971-01-01","time":"1970-01-01T00:00:01Z","data":"AAEC","scores":[0.5,1],"contacts":[{"kind":"email","value":"a@b.c"},{"kind":"phone"}],"labels":{"a":1,"b":2},"score":0.25}
+{"id":"2","name":42,"amount":"0.01","day":1,"time":2000,"scores":[],"labels":{},"extra":true}
+`
+	email := "a@b.c"
+	score := float32(0.25)
+	want := []event{
+		{
+			ID:       1,
+			Name:     "hello",
+			Amount:   1234,
+			Day:      365,
+			Time:     1000,
+			Data:     []byte{0, 1, 2},
+			Scores:   []float64{0.5, 1},
+			Contacts: []contact{{Kind: "email", Value: &email}, {Kind: "phone"}},
+			Labels:   map[string]int64{"a": 1, "b": 2},
+			Score:    &score,
+		},
+		{
+			ID:       2,
+			Name:     "42",
+			Amount:   1,
+			Day:      1,
+			Time:     2000,
+			Data:     []byte{},
+			Scores:   []float64{},
+			Contacts: []contact{},
+			Labels:   map[string]int64{},
+		},
+	}
+
+	schema := parquet.SchemaOf(event{})
+	buffer := parquet.NewBuffer(schema)
+	reader := parquet.NewJSONReader(strings.NewReader(input), schema, parquet.JSONZeroMissingFields(true))
+
+	if _, err := parquet.CopyRows(buffer, reader); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := buffer.Rows()
+	for i := range want {
+		row, err := rows.ReadRow(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := event{}
+		if err := schema.Reconstruct(&got, row); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("row %d mismatch:\nwant: %+v\ngot:  %+v", i, want[i], got)
+		}
+	}
+}
+
+func TestJSONReaderErrors(t *testing.T) {
+	type row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	tests := []struct {
+		scenario string
+		input    string
+		options  []parquet.JSONOption
+	}{
+		{
+			scenario: "missing required field",
+			input:    `{"id":1}`,
+		},
+
+		{
+			scenario: "null required field",
+			input:    `{"id":1,"name":null}`,
+		},
+
+		{
+			scenario: "unknown field",
+			input:    `{"id":1,"name":"a","other":true}`,
+			options:  []parquet.JSONOption{parquet.JSONDisallowUnknownFields(true)},
+		},
+
+		{
+			scenario: "strict types",
+			input:    `{"id":"1","name":"a"}`,
+			options:  []parquet.JSONOption{parquet.JSONStrictTypes(true)},
+		},
+
+		{
+			scenario: "invalid number",
+			input:    `{"id":1.5,"name":"a"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			reader := parquet.NewJSONReader(strings.NewReader(test.input), parquet.SchemaOf(row{}), test.options...)
+			if _, err := reader.ReadRow(nil); err == nil {
+				t.Error("expected an error but got none")
+			}
+		})
+	}
+}
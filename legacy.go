@@ -0,0 +1,104 @@
+package parquet
+
+import (
+	"fmt"
+
+	"github.com/segmentio/parquet-go/deprecated"
+)
+
+// legacySchemaOf returns a schema with the same columns as schema, where LIST
+// and MAP columns use the layouts expected by parquet readers that predate the
+// three-level layout of the parquet specification:
+//
+//	<list-repetition> group <name> (LIST) {
+//		repeated <element-type> array;
+//	}
+//
+//	<map-repetition> group <name> (MAP) {
+//		repeated group map (MAP_KEY_VALUE) {
+//			required <key-type> key;
+//			<value-repetition> <value-type> value;
+//		}
+//	}
+//
+// Both schemas produce the same rows since the repetition and definition levels
+// of the columns are unchanged, which is why lists of optional elements cannot
+// be represented with the legacy layout.
+func legacySchemaOf(schema *Schema) (*Schema, error) {
+	root, err := legacyNodeOf(schema, nil)
+	if err != nil {
+		return nil, err
+	}
+	return NewSchema(schema.Name(), root), nil
+}
+
+func legacyNodeOf(node Node, path columnPath) (Node, error) {
+	if isLeaf(node) {
+		return node, nil
+	}
+
+	var group Group
+	var err error
+	switch {
+	case isList(node):
+		elem := listElementOf(node)
+		if !elem.Required() {
+			return nil, fmt.Errorf("LIST column %q has optional elements which cannot be written with the legacy layout", path)
+		}
+		if elem, err = legacyNodeOf(elem, path.append("array")); err == nil {
+			group = Group{"array": Repeated(elem)}
+		}
+	case isMap(node):
+		var entries Group
+		if entries, err = legacyGroupOf(mapKeyValueOf(node), path.append("map")); err == nil {
+			group = Group{"map": Repeated(&legacyGroup{Group: entries, typ: mapKeyValueType{}})}
+		}
+	default:
+		group, err = legacyGroupOf(node, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	legacy := Node(&legacyGroup{Group: group, typ: node.Type()})
+	switch {
+	case node.Optional():
+		legacy = Optional(legacy)
+	case node.Repeated():
+		legacy = Repeated(legacy)
+	}
+	if id := fieldIDOf(node); id != 0 {
+		legacy = FieldID(legacy, id)
+	}
+	return legacy, nil
+}
+
+func legacyGroupOf(node Node, path columnPath) (Group, error) {
+	names := node.ChildNames()
+	group := make(Group, len(names))
+	for _, name := range names {
+		child, err := legacyNodeOf(node.ChildByName(name), path.append(name))
+		if err != nil {
+			return nil, err
+		}
+		group[name] = child
+	}
+	return group, nil
+}
+
+// legacyGroup is a group retaining the logical type of the node it was
+// converted from.
+type legacyGroup struct {
+	Group
+	typ Type
+}
+
+func (g *legacyGroup) Type() Type { return g.typ }
+
+// mapKeyValueType is the type of the repeated group of legacy MAP columns,
+// which older readers expect to be annotated with MAP_KEY_VALUE.
+type mapKeyValueType struct{ groupType }
+
+func (mapKeyValueType) ConvertedType() *deprecated.ConvertedType {
+	return &convertedTypes[deprecated.MapKeyValue]
+}
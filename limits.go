@@ -7,26 +7,26 @@ import (
 
 const (
 	// MaxColumnDepth is the maximum column depth supported by this package.
-	MaxColumnDepth = math.MaxInt8
+	MaxColumnDepth = math.MaxInt16
 
 	// MaxColumnIndex is the maximum column index supported by this package.
 	MaxColumnIndex = math.MaxInt16
 
 	// MaxRepetitionLevel is the maximum repetition level supported by this package.
-	MaxRepetitionLevel = math.MaxInt8
+	MaxRepetitionLevel = math.MaxInt16
 
 	// MaxDefinitionLevel is the maximum definition level supported by this package.
-	MaxDefinitionLevel = math.MaxInt8
+	MaxDefinitionLevel = math.MaxInt16
 )
 
-func makeRepetitionLevel(i int) int8 {
+func makeRepetitionLevel(i int) int16 {
 	checkIndexRange("repetition level", i, 0, MaxRepetitionLevel)
-	return int8(i)
+	return int16(i)
 }
 
-func makeDefinitionLevel(i int) int8 {
+func makeDefinitionLevel(i int) int16 {
 	checkIndexRange("definition level", i, 0, MaxDefinitionLevel)
-	return int8(i)
+	return int16(i)
 }
 
 func makeColumnIndex(i int) int16 {
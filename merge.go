@@ -205,6 +205,41 @@ type columnSortFunc struct {
 	compare     SortFunc
 }
 
+// columnSortFuncsOf returns the functions comparing the values of the leaf
+// columns of schema listed in sorting, in the same order. Sorting columns which
+// do not match a leaf column of the schema are ignored.
+func columnSortFuncsOf(schema Node, sorting []SortingColumn) []columnSortFunc {
+	sortFuncs := make([]columnSortFunc, len(sorting))
+	matched := make([]bool, len(sorting))
+
+	forEachLeafColumnOf(schema, func(leaf leafColumn) {
+		if sortingIndex := searchSortingColumn(sorting, leaf.path); sortingIndex < len(sorting) {
+			matched[sortingIndex] = true
+			sortFuncs[sortingIndex] = columnSortFunc{
+				columnIndex: leaf.columnIndex,
+				compare: sortFuncOf(
+					leaf.node.Type(),
+					&SortConfig{
+						MaxRepetitionLevel: int(leaf.maxRepetitionLevel),
+						MaxDefinitionLevel: int(leaf.maxDefinitionLevel),
+						Descending:         sorting[sortingIndex].Descending(),
+						NullsFirst:         sorting[sortingIndex].NullsFirst(),
+					},
+				),
+			}
+		}
+	})
+
+	i := 0
+	for j := range sortFuncs {
+		if matched[j] {
+			sortFuncs[i] = sortFuncs[j]
+			i++
+		}
+	}
+	return sortFuncs[:i]
+}
+
 type bufferedRowGroupCursor struct {
 	reader  Rows
 	rowbuf  Row
@@ -3,6 +3,7 @@ package parquet
 import (
 	"reflect"
 	"sort"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 
@@ -220,6 +221,34 @@ func (n *compressedNode) Compression() []compress.Codec {
 	return dedupeSortedCodecs(compression)
 }
 
+// FieldID wraps the node passed as argument to assign it the given field id.
+//
+// Field ids are written to the schema of parquet files; table formats such as
+// Iceberg use them to identify columns independently of their names. A zero id
+// means that the node has no field id.
+func FieldID(node Node, id int) Node { return &fieldIDNode{wrappedNode: wrap(node), id: id} }
+
+type fieldIDNode struct {
+	wrappedNode
+	id int
+}
+
+func (n *fieldIDNode) ID() int { return n.id }
+
+// fieldIDOf returns the field id of node, or zero if it has none.
+func fieldIDOf(node Node) int {
+	for {
+		if n, ok := node.(interface{ ID() int }); ok {
+			return n.ID()
+		}
+		w, ok := node.(WrappedNode)
+		if !ok {
+			return 0
+		}
+		node = w.Unwrap()
+	}
+}
+
 // Optional wraps the given node to make it optional.
 func Optional(node Node) Node { return &optionalNode{wrap(node)} }
 
@@ -248,7 +277,7 @@ type requiredNode struct{ wrappedNode }
 func (req *requiredNode) Optional() bool       { return false }
 func (req *requiredNode) Repeated() bool       { return false }
 func (req *requiredNode) Required() bool       { return true }
-func (req *requiredNode) GoType() reflect.Type { return unwrap(req).GoType() }
+func (req *requiredNode) GoType() reflect.Type { return req.Node.GoType() }
 
 type node struct{}
 
@@ -443,6 +472,11 @@ func isMap(node Node) bool {
 	return logicalType != nil && logicalType.Map != nil
 }
 
+func isVariant(node Node) bool {
+	logicalType := node.Type().LogicalType()
+	return logicalType != nil && logicalType.Variant != nil
+}
+
 func numLeafColumnsOf(node Node) int16 {
 	return makeColumnIndex(numLeafColumns(node, 0))
 }
@@ -466,19 +500,54 @@ func numLeafColumns(node Node, columnIndex int) int {
 }
 
 func listElementOf(node Node) Node {
-	if !isLeaf(node) {
-		if list := node.ChildByName("list"); list != nil {
-			if elem := list.ChildByName("element"); elem != nil {
-				return elem
-			}
-		}
+	if elem := findListElement(node); elem != nil {
+		return elem
 	}
 	panic("node with logical type LIST is not composed of a repeated .list.element")
 }
 
+// findListElement returns the node of the elements of a LIST column, or nil if
+// the node does not have a single repeated child column.
+//
+// Files written by older parquet implementations may use different names than
+// .list.element, or only two levels where the repeated column holds the list
+// elements. The backward-compatibility rules of the parquet spec are applied
+// to determine which column represents the elements, see:
+// https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#backward-compatibility-rules
+func findListElement(node Node) Node {
+	names := node.ChildNames()
+	if len(names) != 1 {
+		return nil
+	}
+	list := node.ChildByName(names[0])
+	if list == nil || !list.Repeated() {
+		return nil
+	}
+	switch elems := list.ChildNames(); {
+	case len(elems) == 1 && names[0] != "array" && !strings.HasSuffix(names[0], "_tuple"):
+		return list.ChildByName(elems[0])
+	default:
+		// The repeated column is a primitive, a group of multiple columns, or
+		// a group named array or <list-name>_tuple; it holds the elements,
+		// which are always required in those layouts. The name of the list is
+		// not known here so any _tuple suffix is accepted.
+		return Required(list)
+	}
+}
+
 func mapKeyValueOf(node Node) Node {
-	if !isLeaf(node) && (node.Required() || node.Optional()) {
-		if keyValue := node.ChildByName("key_value"); keyValue != nil && !isLeaf(keyValue) && keyValue.Repeated() {
+	if elem := findMapKeyValue(node); elem != nil {
+		return elem
+	}
+	panic("node with logical type MAP is not composed of a repeated .key_value group with key and value fields")
+}
+
+// findMapKeyValue returns the repeated group holding the keys and values of a
+// MAP column, or nil if the node does not have this layout. The group may have
+// a different name than key_value in files written by older implementations.
+func findMapKeyValue(node Node) Node {
+	if names := node.ChildNames(); len(names) == 1 && (node.Required() || node.Optional()) {
+		if keyValue := node.ChildByName(names[0]); keyValue != nil && !isLeaf(keyValue) && keyValue.Repeated() {
 			k := keyValue.ChildByName("key")
 			v := keyValue.ChildByName("value")
 			if k != nil && v != nil && k.Required() {
@@ -486,7 +555,7 @@ func mapKeyValueOf(node Node) Node {
 			}
 		}
 	}
-	panic("node with logical type MAP is not composed of a repeated .key_value group with key and value fields")
+	return nil
 }
 
 func encodingAndCompressionOf(node Node) (encoding.Encoding, compress.Codec) {
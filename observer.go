@@ -0,0 +1,142 @@
+package parquet
+
+import (
+	"io"
+	"time"
+
+	"github.com/segmentio/parquet-go/format"
+)
+
+// WriterObserver carries functions called by writers to report on their
+// activity, for example to export metrics about the files they produce. Any
+// of the functions may be nil, in which case the corresponding events are not
+// reported.
+//
+// The functions are called synchronously by the writer methods, they must
+// return quickly to avoid slowing down writes.
+type WriterObserver struct {
+	// Called when a page is written to the buffer of a column chunk, including
+	// dictionary pages.
+	OnPage func(WriterPageStats)
+	// Called after a row group was flushed to the output.
+	OnRowGroup func(WriterRowGroupStats)
+	// Called when a column falls back from dictionary encoding to its fallback
+	// encoding because the dictionary exceeded its limits.
+	OnDictionaryFallback func(path []string)
+}
+
+// WriterPageStats describes a page written by a parquet writer.
+type WriterPageStats struct {
+	// Index and path of the column that the page was written to.
+	Column int
+	Path   []string
+	// Type and encoding of the page.
+	PageType format.PageType
+	Encoding format.Encoding
+	// Number of values in the page, or number of entries of dictionary pages.
+	NumValues int64
+	// Size of the page including its header, before and after compression.
+	UncompressedSize int64
+	CompressedSize   int64
+}
+
+// WriterRowGroupStats describes a row group flushed by a parquet writer.
+type WriterRowGroupStats struct {
+	// Index of the row group in the file, and number of rows it contains.
+	RowGroup int
+	NumRows  int64
+	// Size of the row group before and after compression.
+	UncompressedSize int64
+	CompressedSize   int64
+	// Time spent flushing the row group to the output.
+	FlushDuration time.Duration
+	// Statistics of each column chunk of the row group.
+	Columns []WriterColumnStats
+}
+
+// WriterColumnStats describes a column chunk flushed by a parquet writer.
+type WriterColumnStats struct {
+	// Path of the column.
+	Path []string
+	// Number of values and data pages in the column chunk.
+	NumValues int64
+	NumPages  int
+	// Size of the column chunk before and after compression.
+	UncompressedSize int64
+	CompressedSize   int64
+}
+
+// ReaderObserver carries functions called when reading parquet files to report
+// on the reader activity, for example to verify that seeking within column
+// chunks avoids reading unnecessary data. Any of the functions may be nil, in
+// which case the corresponding events are not reported.
+//
+// The functions are called synchronously by the readers, they must return
+// quickly and be safe to call concurrently if the file is read from multiple
+// goroutines.
+type ReaderObserver struct {
+	// Called with the number of bytes read from the underlying io.ReaderAt
+	// of the file, including the footer, page index, and bloom filters.
+	OnRead func(n int64)
+	// Called when a data page is returned by the page reader of a column chunk.
+	OnPage func(ReaderPageStats)
+	// Called when pages of a column chunk are skipped while seeking to a row,
+	// with the number of pages that were skipped. Pages skipped using the
+	// offset index are not read from the file, others are read and discarded.
+	OnPagesSkipped func(path []string, numPages int)
+	// Called when page data is decompressed, with the compression codec, the
+	// number of bytes produced, and the time spent in the decompression.
+	OnDecompress func(codec format.CompressionCodec, n int64, duration time.Duration)
+	// Called when a reader configured with the SkipCorrupted option skips the
+	// rest of a row group because rows could not be read from it, with the
+	// index of the row group and the number of rows that were skipped.
+	OnRowGroupSkipped func(rowGroup int, numRows int64)
+}
+
+// ReaderPageStats describes a page read from a parquet file.
+type ReaderPageStats struct {
+	// Index of the row group and column that the page was read from, and path
+	// of the column.
+	RowGroup int
+	Column   int
+	Path     []string
+	// Type and encoding of the page.
+	PageType format.PageType
+	Encoding format.Encoding
+	// Number of values in the page.
+	NumValues int64
+	// Size of the page data, before and after decompression.
+	CompressedSize   int64
+	UncompressedSize int64
+}
+
+type observedReaderAt struct {
+	reader   io.ReaderAt
+	observer *ReaderObserver
+}
+
+func (r *observedReaderAt) ReadAt(b []byte, off int64) (int, error) {
+	n, err := r.reader.ReadAt(b, off)
+	r.observer.OnRead(int64(n))
+	return n, err
+}
+
+func observeDecompression(observer *ReaderObserver, codec format.CompressionCodec, r io.Reader) io.Reader {
+	if observer == nil || observer.OnDecompress == nil || codec == format.Uncompressed {
+		return r
+	}
+	return &observedDecompression{reader: r, codec: codec, observer: observer}
+}
+
+type observedDecompression struct {
+	reader   io.Reader
+	codec    format.CompressionCodec
+	observer *ReaderObserver
+}
+
+func (r *observedDecompression) Read(b []byte) (int, error) {
+	start := time.Now()
+	n, err := r.reader.Read(b)
+	r.observer.OnDecompress(r.codec, int64(n), time.Since(start))
+	return n, err
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/segmentio/parquet-go/format"
 )
 
+// OffsetIndex is the data structure representing offset indexes, which record
+// the location and first row of each page of a column chunk.
 type OffsetIndex interface {
 	// NumPages returns the number of pages in the offset index.
 	NumPages() int
@@ -13,7 +15,7 @@ type OffsetIndex interface {
 	Offset(int) int64
 
 	// CompressedPageSize returns the size of the page at the given index
-	// (in bytes).
+	// (in bytes), including the page header.
 	CompressedPageSize(int) int64
 
 	// FirstRowIndex returns the the first row in the page at the given index.
@@ -23,6 +25,57 @@ type OffsetIndex interface {
 	FirstRowIndex(int) int64
 }
 
+// PageLocation describes the location of a page in a parquet file, as recorded
+// in the offset index of its column chunk.
+type PageLocation struct {
+	// Offset of the page header from the beginning of the file.
+	Offset int64
+	// Size of the page, including its header (in bytes).
+	CompressedPageSize int64
+	// Index of the first row of the page in its row group.
+	FirstRowIndex int64
+}
+
+// End returns the offset of the first byte after the page.
+func (loc PageLocation) End() int64 { return loc.Offset + loc.CompressedPageSize }
+
+// PageLocations returns the locations of all the pages of an offset index.
+//
+// Programs scheduling reads of the pages in a column chunk can use it to issue
+// precise byte-range requests, for example to read the pages containing a
+// range of rows:
+//
+//	pages := parquet.PageLocations(columnChunk.OffsetIndex())
+//	first := sort.Search(len(pages), func(i int) bool { return pages[i].FirstRowIndex > rowIndex }) - 1
+//	...
+//
+// The function returns nil if the offset index is nil.
+func PageLocations(index OffsetIndex) []PageLocation {
+	if index == nil {
+		return nil
+	}
+	if i, ok := index.(*fileOffsetIndex); ok {
+		locations := make([]PageLocation, len(i.PageLocations))
+		for j, loc := range i.PageLocations {
+			locations[j] = PageLocation{
+				Offset:             loc.Offset,
+				CompressedPageSize: int64(loc.CompressedPageSize),
+				FirstRowIndex:      loc.FirstRowIndex,
+			}
+		}
+		return locations
+	}
+	locations := make([]PageLocation, index.NumPages())
+	for j := range locations {
+		locations[j] = PageLocation{
+			Offset:             index.Offset(j),
+			CompressedPageSize: index.CompressedPageSize(j),
+			FirstRowIndex:      index.FirstRowIndex(j),
+		}
+	}
+	return locations
+}
+
 type emptyOffsetIndex struct{}
 
 func (emptyOffsetIndex) NumPages() int                { return 0 }
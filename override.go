@@ -0,0 +1,244 @@
+package parquet
+
+import "fmt"
+
+// NodeOverride is the signature of functions rewriting nodes of the schema of
+// rows written to parquet files, configured with the ColumnOverride writer
+// option.
+//
+// Functions like Optional, or OverrideType, may be used as overrides.
+type NodeOverride func(Node) Node
+
+// ColumnOverrideConfig carries the override applied to the node at the given
+// path of the schema.
+type ColumnOverrideConfig struct {
+	// The path of the node that the override applies to, which may be a leaf
+	// column or a group.
+	Path []string
+
+	// The function rewriting the node.
+	Override NodeOverride
+}
+
+// OverrideType returns a node override replacing the type of leaf nodes by
+// typ, retaining their repetition, encodings, compression codecs, and field
+// id. The type must have the same kind as the original type of the node, for
+// example to annotate a string column with the ENUM logical type:
+//
+//	parquet.ColumnOverride(parquet.OverrideType(parquet.Enum().Type()), "status")
+func OverrideType(typ Type) NodeOverride {
+	return func(node Node) Node { return &typedNode{wrappedNode: wrap(node), typ: typ} }
+}
+
+type typedNode struct {
+	wrappedNode
+	typ Type
+}
+
+func (n *typedNode) Type() Type { return n.typ }
+
+// overriddenGroupNode is a wrapper of group nodes substituting some of their
+// children, so the properties of the group (e.g. its logical type) are
+// retained.
+type overriddenGroupNode struct {
+	wrappedNode
+	children map[string]Node
+}
+
+func (n *overriddenGroupNode) ChildByName(name string) Node {
+	if child, ok := n.children[name]; ok {
+		return child
+	}
+	return n.Node.ChildByName(name)
+}
+
+// overrideSchema returns the schema of files produced from rows of the given
+// schema with the column overrides applied to it.
+func overrideSchema(schema *Schema, overrides []ColumnOverrideConfig) *Schema {
+	root := overrideNode(schema, nil, overrides)
+	if root == Node(schema) {
+		return schema
+	}
+	return NewSchema(schema.Name(), root)
+}
+
+func overrideNode(node Node, path columnPath, overrides []ColumnOverrideConfig) Node {
+	if !isLeaf(node) {
+		var children map[string]Node
+		for _, name := range node.ChildNames() {
+			child := node.ChildByName(name)
+			if overridden := overrideNode(child, path.append(name), overrides); overridden != child {
+				if children == nil {
+					children = make(map[string]Node)
+				}
+				children[name] = overridden
+			}
+		}
+		if children != nil {
+			node = &overriddenGroupNode{wrappedNode: wrap(node), children: children}
+		}
+	}
+	if len(path) > 0 {
+		for _, override := range overrides {
+			if path.equal(override.Path) {
+				node = override.Override(node)
+			}
+		}
+	}
+	return node
+}
+
+// MakeAllOptional returns a schema derived from schema where all required
+// columns and groups are optional, which may be used to write rows where any
+// of the columns may be absent, for example in change data capture streams.
+//
+// LIST, MAP, and VARIANT groups are made optional but their content is
+// unchanged, the elements of lists, keys and values of maps, and fields of
+// variants retain their repetition. Repeated columns are unchanged.
+func MakeAllOptional(schema *Schema) *Schema {
+	return RequiredOnly(schema)
+}
+
+// RequiredOnly is like MakeAllOptional but the nodes at the given paths and
+// their parent groups retain their repetition, for example to derive a schema
+// where only the columns of a primary key are required:
+//
+//	schema := parquet.RequiredOnly(parquet.SchemaOf(new(Row)), []string{"id"})
+//
+// The paths may designate leaf columns or groups, paths which do not exist in
+// the schema are ignored.
+func RequiredOnly(schema *Schema, paths ...[]string) *Schema {
+	root, changed := makeOptionalNode(schema, nil, paths)
+	if !changed {
+		return schema
+	}
+	return NewSchema(schema.Name(), root)
+}
+
+// makeOptionalNode returns node with its required descendants made optional,
+// except the nodes at the required paths and their parents. The function
+// returns false if the node was not changed.
+func makeOptionalNode(node Node, path columnPath, required [][]string) (Node, bool) {
+	retained := false
+	for _, p := range required {
+		if len(p) >= len(path) && path.equal(p[:len(path)]) {
+			if len(p) == len(path) {
+				return node, false
+			}
+			retained = true
+		}
+	}
+
+	changed := false
+	if !isLeaf(node) && !isList(node) && !isMap(node) && !isVariant(node) {
+		var children map[string]Node
+		for _, name := range node.ChildNames() {
+			if child, ok := makeOptionalNode(node.ChildByName(name), path.append(name), required); ok {
+				if children == nil {
+					children = make(map[string]Node)
+				}
+				children[name] = child
+			}
+		}
+		if children != nil {
+			node, changed = &overriddenGroupNode{wrappedNode: wrap(node), children: children}, true
+		}
+	}
+
+	if len(path) > 0 && !retained && node.Required() {
+		node, changed = Optional(node), true
+	}
+	return node, changed
+}
+
+// definitionLevelsOfOverrides appends to levels, for each leaf column of the
+// nodes, the definition levels of the source node at which the required nodes
+// made optional by overrides are defined. The function returns an error if the
+// overrides made changes that cannot be applied to rows of the source node.
+func definitionLevelsOfOverrides(from, to Node, path columnPath, definitionLevel int16, optional []int16, levels [][]int16) ([][]int16, error) {
+	switch {
+	case from.Required() && to.Optional():
+		optional = append(optional[:len(optional):len(optional)], definitionLevel)
+	case from.Optional() != to.Optional() || from.Repeated() != to.Repeated():
+		return nil, fmt.Errorf("cannot override repetition of column %q from %s to %s", path, repetitionOf(from), repetitionOf(to))
+	}
+	if !from.Required() {
+		definitionLevel++
+	}
+
+	switch {
+	case isLeaf(from) != isLeaf(to):
+		return nil, fmt.Errorf("cannot override column %q with a node of a different structure", path)
+	case isLeaf(from):
+		if from.Type().Kind() != to.Type().Kind() {
+			return nil, fmt.Errorf("cannot override type of column %q from %s to %s", path, from.Type(), to.Type())
+		}
+		return append(levels, optional), nil
+	case !stringsAreEqual(from.ChildNames(), to.ChildNames()):
+		return nil, fmt.Errorf("cannot override column %q with a node of a different structure", path)
+	}
+
+	var err error
+	for _, name := range from.ChildNames() {
+		levels, err = definitionLevelsOfOverrides(from.ChildByName(name), to.ChildByName(name), path.append(name), definitionLevel, optional, levels)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return levels, nil
+}
+
+func repetitionOf(node Node) string {
+	switch {
+	case node.Optional():
+		return "optional"
+	case node.Repeated():
+		return "repeated"
+	default:
+		return "required"
+	}
+}
+
+// columnOverrideWriter is a row writer which adjusts the definition levels of
+// values in columns that were made optional by column overrides, and writes
+// the rows to a parquet writer.
+type columnOverrideWriter struct {
+	writer RowWriter
+	levels [][]int16
+	row    Row
+}
+
+// newColumnOverrideWriter returns a row writer adjusting the definition levels
+// of rows written to writer with the levels computed by
+// definitionLevelsOfOverrides, or writer itself if the rows do not need to be
+// rewritten.
+func newColumnOverrideWriter(writer RowWriter, levels [][]int16) RowWriter {
+	for _, optional := range levels {
+		if len(optional) > 0 {
+			return &columnOverrideWriter{writer: writer, levels: levels}
+		}
+	}
+	return writer
+}
+
+func (w *columnOverrideWriter) WriteRow(row Row) error {
+	defer func() {
+		clearValues(w.row)
+	}()
+
+	w.row = append(w.row[:0], row...)
+
+	for i := range w.row {
+		v := &w.row[i]
+		definitionLevel := v.definitionLevel
+		for _, level := range w.levels[v.Column()] {
+			// The node made optional is defined when its parent is, which is
+			// always the case in the source schema where it was required.
+			if definitionLevel >= level {
+				v.definitionLevel++
+			}
+		}
+	}
+
+	return w.writer.WriteRow(w.row)
+}
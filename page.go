@@ -74,8 +74,8 @@ type BufferedPage interface {
 	//
 	// The returned slices may be empty when the page has no repetition or
 	// definition levels.
-	RepetitionLevels() []int8
-	DefinitionLevels() []int8
+	RepetitionLevels() []int16
+	DefinitionLevels() []int16
 
 	// Writes the page to the given encoder.
 	WriteTo(encoding.Encoder) error
@@ -162,7 +162,7 @@ func sizeOfBytes(data []byte) int64 { return 1 * int64(len(data)) }
 
 func sizeOfBool(data []bool) int64 { return 1 * int64(len(data)) }
 
-func sizeOfInt8(data []int8) int64 { return 1 * int64(len(data)) }
+func sizeOfInt16(data []int16) int64 { return 2 * int64(len(data)) }
 
 func sizeOfInt32(data []int32) int64 { return 4 * int64(len(data)) }
 
@@ -174,7 +174,7 @@ func sizeOfFloat32(data []float32) int64 { return 4 * int64(len(data)) }
 
 func sizeOfFloat64(data []float64) int64 { return 8 * int64(len(data)) }
 
-func forEachPageSlice(page BufferedPage, wantSize int64, do func(BufferedPage) error) error {
+func forEachPageSlice(page BufferedPage, wantSize, wantValues int64, do func(BufferedPage) error) error {
 	numRows := page.NumRows()
 	if numRows == 0 {
 		return nil
@@ -182,6 +182,15 @@ func forEachPageSlice(page BufferedPage, wantSize int64, do func(BufferedPage) e
 
 	pageSize := page.Size()
 	numPages := (pageSize + (wantSize - 1)) / wantSize
+	if wantValues > 0 {
+		numValues := page.NumValues()
+		if n := (numValues + (wantValues - 1)) / wantValues; n > numPages {
+			numPages = n
+		}
+	}
+	if numPages > numRows {
+		numPages = numRows
+	}
 	rowIndex := int64(0)
 	if numPages < 2 {
 		return do(page)
@@ -220,8 +229,8 @@ func (page *errorPage) Bounds() (min, max Value)       { return }
 func (page *errorPage) Clone() BufferedPage            { return page }
 func (page *errorPage) Slice(i, j int64) BufferedPage  { return page }
 func (page *errorPage) Size() int64                    { return 0 }
-func (page *errorPage) RepetitionLevels() []int8       { return nil }
-func (page *errorPage) DefinitionLevels() []int8       { return nil }
+func (page *errorPage) RepetitionLevels() []int16      { return nil }
+func (page *errorPage) DefinitionLevels() []int16      { return nil }
 func (page *errorPage) WriteTo(encoding.Encoder) error { return page.err }
 func (page *errorPage) Values() ValueReader            { return &errorValueReader{err: page.err} }
 func (page *errorPage) Buffer() BufferedPage           { return page }
@@ -230,21 +239,27 @@ func errPageBoundsOutOfRange(i, j, n int64) error {
 	return fmt.Errorf("page bounds out of range [%d:%d]: with length %d", i, j, n)
 }
 
-func countLevelsEqual(levels []int8, value int8) int {
-	return bits.CountByte(bits.Int8ToBytes(levels), byte(value))
+func countLevelsEqual(levels []int16, value int16) int {
+	n := 0
+	for _, level := range levels {
+		if level == value {
+			n++
+		}
+	}
+	return n
 }
 
-func countLevelsNotEqual(levels []int8, value int8) int {
+func countLevelsNotEqual(levels []int16, value int16) int {
 	return len(levels) - countLevelsEqual(levels, value)
 }
 
-func appendLevel(levels []int8, value int8, count int) []int8 {
+func appendLevel(levels []int16, value int16, count int) []int16 {
 	if count > 0 {
 		i := len(levels)
 		j := len(levels) + 1
 
 		if n := len(levels) + count; cap(levels) < n {
-			newLevels := make([]int8, n)
+			newLevels := make([]int16, n)
 			copy(newLevels, levels)
 			levels = newLevels
 		} else {
@@ -260,11 +275,11 @@ func appendLevel(levels []int8, value int8, count int) []int8 {
 
 type optionalPage struct {
 	base               BufferedPage
-	maxDefinitionLevel int8
-	definitionLevels   []int8
+	maxDefinitionLevel int16
+	definitionLevels   []int16
 }
 
-func newOptionalPage(base BufferedPage, maxDefinitionLevel int8, definitionLevels []int8) *optionalPage {
+func newOptionalPage(base BufferedPage, maxDefinitionLevel int16, definitionLevels []int16) *optionalPage {
 	return &optionalPage{
 		base:               base,
 		maxDefinitionLevel: maxDefinitionLevel,
@@ -300,7 +315,7 @@ func (page *optionalPage) Clone() BufferedPage {
 	return newOptionalPage(
 		page.base.Clone(),
 		page.maxDefinitionLevel,
-		append([]int8{}, page.definitionLevels...),
+		append([]int16{}, page.definitionLevels...),
 	)
 }
 
@@ -315,14 +330,14 @@ func (page *optionalPage) Slice(i, j int64) BufferedPage {
 }
 
 func (page *optionalPage) Size() int64 {
-	return page.base.Size() + sizeOfInt8(page.definitionLevels)
+	return page.base.Size() + sizeOfInt16(page.definitionLevels)
 }
 
-func (page *optionalPage) RepetitionLevels() []int8 {
+func (page *optionalPage) RepetitionLevels() []int16 {
 	return nil
 }
 
-func (page *optionalPage) DefinitionLevels() []int8 {
+func (page *optionalPage) DefinitionLevels() []int16 {
 	return page.definitionLevels
 }
 
@@ -389,13 +404,13 @@ func (r *optionalPageReader) ReadValues(values []Value) (n int, err error) {
 
 type repeatedPage struct {
 	base               BufferedPage
-	maxRepetitionLevel int8
-	maxDefinitionLevel int8
-	definitionLevels   []int8
-	repetitionLevels   []int8
+	maxRepetitionLevel int16
+	maxDefinitionLevel int16
+	definitionLevels   []int16
+	repetitionLevels   []int16
 }
 
-func newRepeatedPage(base BufferedPage, maxRepetitionLevel, maxDefinitionLevel int8, repetitionLevels, definitionLevels []int8) *repeatedPage {
+func newRepeatedPage(base BufferedPage, maxRepetitionLevel, maxDefinitionLevel int16, repetitionLevels, definitionLevels []int16) *repeatedPage {
 	return &repeatedPage{
 		base:               base,
 		maxRepetitionLevel: maxRepetitionLevel,
@@ -434,8 +449,8 @@ func (page *repeatedPage) Clone() BufferedPage {
 		page.base.Clone(),
 		page.maxRepetitionLevel,
 		page.maxDefinitionLevel,
-		append([]int8{}, page.repetitionLevels...),
-		append([]int8{}, page.definitionLevels...),
+		append([]int16{}, page.repetitionLevels...),
+		append([]int16{}, page.definitionLevels...),
 	)
 }
 
@@ -455,8 +470,10 @@ func (page *repeatedPage) Slice(i, j int64) BufferedPage {
 	rowIndex1 := int64(len(page.repetitionLevels))
 	rowIndex2 := int64(len(page.repetitionLevels))
 
-	for k, def := range page.repetitionLevels {
-		if def != page.maxRepetitionLevel {
+	// Rows start at values with a repetition level of zero, the nested levels
+	// of repetition are values of the same row.
+	for k, rep := range page.repetitionLevels {
+		if rep == 0 {
 			if rowIndex0 == i {
 				rowIndex1 = int64(k)
 			}
@@ -470,8 +487,10 @@ func (page *repeatedPage) Slice(i, j int64) BufferedPage {
 	numNulls1 := int64(countLevelsNotEqual(page.definitionLevels[:rowIndex1], page.maxDefinitionLevel))
 	numNulls2 := int64(countLevelsNotEqual(page.definitionLevels[rowIndex1:rowIndex2], page.maxDefinitionLevel))
 
-	i -= numNulls1
-	j = i + (rowIndex2 - (rowIndex1 + numNulls2))
+	// The base page only holds the non-null values, the bounds of the slice
+	// are translated from indexes of levels to indexes of values.
+	i = rowIndex1 - numNulls1
+	j = rowIndex2 - (numNulls1 + numNulls2)
 
 	return newRepeatedPage(
 		page.base.Slice(i, j),
@@ -483,14 +502,14 @@ func (page *repeatedPage) Slice(i, j int64) BufferedPage {
 }
 
 func (page *repeatedPage) Size() int64 {
-	return sizeOfInt8(page.repetitionLevels) + sizeOfInt8(page.definitionLevels) + page.base.Size()
+	return sizeOfInt16(page.repetitionLevels) + sizeOfInt16(page.definitionLevels) + page.base.Size()
 }
 
-func (page *repeatedPage) RepetitionLevels() []int8 {
+func (page *repeatedPage) RepetitionLevels() []int16 {
 	return page.repetitionLevels
 }
 
-func (page *repeatedPage) DefinitionLevels() []int8 {
+func (page *repeatedPage) DefinitionLevels() []int16 {
 	return page.definitionLevels
 }
 
@@ -517,12 +536,14 @@ func (r *repeatedPageReader) ReadValues(values []Value) (n int, err error) {
 		r.values = r.page.base.Values()
 	}
 	maxDefinitionLevel := r.page.maxDefinitionLevel
+	columnIndex := ^int16(r.page.Column())
 
 	for n < len(values) && r.offset < len(r.page.definitionLevels) {
 		for n < len(values) && r.offset < len(r.page.definitionLevels) && r.page.definitionLevels[r.offset] != maxDefinitionLevel {
 			values[n] = Value{
 				repetitionLevel: r.page.repetitionLevels[r.offset],
 				definitionLevel: r.page.definitionLevels[r.offset],
+				columnIndex:     columnIndex,
 			}
 			r.offset++
 			n++
@@ -638,9 +659,9 @@ func (page *byteArrayPage) Slice(i, j int64) BufferedPage {
 
 func (page *byteArrayPage) Size() int64 { return page.values.Size() }
 
-func (page *byteArrayPage) RepetitionLevels() []int8 { return nil }
+func (page *byteArrayPage) RepetitionLevels() []int16 { return nil }
 
-func (page *byteArrayPage) DefinitionLevels() []int8 { return nil }
+func (page *byteArrayPage) DefinitionLevels() []int16 { return nil }
 
 func (page *byteArrayPage) WriteTo(e encoding.Encoder) error { return e.EncodeByteArray(page.values) }
 
@@ -756,9 +777,9 @@ func (page *fixedLenByteArrayPage) Slice(i, j int64) BufferedPage {
 
 func (page *fixedLenByteArrayPage) Size() int64 { return sizeOfBytes(page.data) }
 
-func (page *fixedLenByteArrayPage) RepetitionLevels() []int8 { return nil }
+func (page *fixedLenByteArrayPage) RepetitionLevels() []int16 { return nil }
 
-func (page *fixedLenByteArrayPage) DefinitionLevels() []int8 { return nil }
+func (page *fixedLenByteArrayPage) DefinitionLevels() []int16 { return nil }
 
 func (page *fixedLenByteArrayPage) WriteTo(e encoding.Encoder) error {
 	return e.EncodeFixedLenByteArray(page.size, page.data)
@@ -59,6 +59,28 @@ func (pool *pageBufferPool) PutPageBuffer(buf io.ReadWriter) {
 	}
 }
 
+// sizedPageBufferPool wraps a page buffer pool to bound the size of in-memory
+// buffers that get retained by the pool.
+type sizedPageBufferPool struct {
+	pool PageBufferPool
+	size int
+}
+
+func (pool *sizedPageBufferPool) GetPageBuffer() io.ReadWriter {
+	buf := pool.pool.GetPageBuffer()
+	if b, _ := buf.(*bytes.Buffer); b != nil {
+		b.Grow(pool.size)
+	}
+	return buf
+}
+
+func (pool *sizedPageBufferPool) PutPageBuffer(buf io.ReadWriter) {
+	if b, _ := buf.(*bytes.Buffer); b != nil && b.Cap() > pool.size {
+		return // let the garbage collector reclaim oversized buffers
+	}
+	pool.pool.PutPageBuffer(buf)
+}
+
 type fileBufferPool struct {
 	err     error
 	tempdir string
@@ -91,9 +91,9 @@ func (page *booleanPage) Slice(i, j int64) BufferedPage {
 
 func (page *booleanPage) Size() int64 { return sizeOfBool(page.values) }
 
-func (page *booleanPage) RepetitionLevels() []int8 { return nil }
+func (page *booleanPage) RepetitionLevels() []int16 { return nil }
 
-func (page *booleanPage) DefinitionLevels() []int8 { return nil }
+func (page *booleanPage) DefinitionLevels() []int16 { return nil }
 
 func (page *booleanPage) WriteTo(e encoding.Encoder) error { return e.EncodeBoolean(page.values) }
 
@@ -178,9 +178,9 @@ func (page *int32Page) Slice(i, j int64) BufferedPage {
 
 func (page *int32Page) Size() int64 { return sizeOfInt32(page.values) }
 
-func (page *int32Page) RepetitionLevels() []int8 { return nil }
+func (page *int32Page) RepetitionLevels() []int16 { return nil }
 
-func (page *int32Page) DefinitionLevels() []int8 { return nil }
+func (page *int32Page) DefinitionLevels() []int16 { return nil }
 
 func (page *int32Page) WriteTo(e encoding.Encoder) error { return e.EncodeInt32(page.values) }
 
@@ -266,9 +266,9 @@ func (page *int64Page) Slice(i, j int64) BufferedPage {
 
 func (page *int64Page) Size() int64 { return sizeOfInt64(page.values) }
 
-func (page *int64Page) RepetitionLevels() []int8 { return nil }
+func (page *int64Page) RepetitionLevels() []int16 { return nil }
 
-func (page *int64Page) DefinitionLevels() []int8 { return nil }
+func (page *int64Page) DefinitionLevels() []int16 { return nil }
 
 func (page *int64Page) WriteTo(e encoding.Encoder) error { return e.EncodeInt64(page.values) }
 
@@ -356,9 +356,9 @@ func (page *int96Page) Slice(i, j int64) BufferedPage {
 
 func (page *int96Page) Size() int64 { return sizeOfInt96(page.values) }
 
-func (page *int96Page) RepetitionLevels() []int8 { return nil }
+func (page *int96Page) RepetitionLevels() []int16 { return nil }
 
-func (page *int96Page) DefinitionLevels() []int8 { return nil }
+func (page *int96Page) DefinitionLevels() []int16 { return nil }
 
 func (page *int96Page) WriteTo(e encoding.Encoder) error { return e.EncodeInt96(page.values) }
 
@@ -444,9 +444,9 @@ func (page *floatPage) Slice(i, j int64) BufferedPage {
 
 func (page *floatPage) Size() int64 { return sizeOfFloat32(page.values) }
 
-func (page *floatPage) RepetitionLevels() []int8 { return nil }
+func (page *floatPage) RepetitionLevels() []int16 { return nil }
 
-func (page *floatPage) DefinitionLevels() []int8 { return nil }
+func (page *floatPage) DefinitionLevels() []int16 { return nil }
 
 func (page *floatPage) WriteTo(e encoding.Encoder) error { return e.EncodeFloat(page.values) }
 
@@ -532,9 +532,9 @@ func (page *doublePage) Slice(i, j int64) BufferedPage {
 
 func (page *doublePage) Size() int64 { return sizeOfFloat64(page.values) }
 
-func (page *doublePage) RepetitionLevels() []int8 { return nil }
+func (page *doublePage) RepetitionLevels() []int16 { return nil }
 
-func (page *doublePage) DefinitionLevels() []int8 { return nil }
+func (page *doublePage) DefinitionLevels() []int16 { return nil }
 
 func (page *doublePage) WriteTo(e encoding.Encoder) error { return e.EncodeDouble(page.values) }
 
@@ -58,9 +58,9 @@ func (p *page[T]) Slice(i, j int64) BufferedPage {
 
 func (p *page[T]) Size() int64 { return int64(len(p.values)) * int64(sizeof[T]()) }
 
-func (p *page[T]) RepetitionLevels() []int8 { return nil }
+func (p *page[T]) RepetitionLevels() []int16 { return nil }
 
-func (p *page[T]) DefinitionLevels() []int8 { return nil }
+func (p *page[T]) DefinitionLevels() []int16 { return nil }
 
 func (p *page[T]) WriteTo(e encoding.Encoder) error { return p.class.encode(e, p.values) }
 
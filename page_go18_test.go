@@ -171,6 +171,64 @@ func testPageFixedLenByteArray(t *testing.T) {
 	})
 }
 
+func TestDictionaryPage(t *testing.T) {
+	t.Run("BOOLEAN", testDictionaryPageOf[bool])
+	t.Run("INT32", testDictionaryPageOf[int32])
+	t.Run("INT64", testDictionaryPageOf[int64])
+	t.Run("FLOAT", testDictionaryPageOf[float32])
+	t.Run("DOUBLE", testDictionaryPageOf[float64])
+	t.Run("BYTE_ARRAY", testDictionaryPageByteArray)
+}
+
+func testDictionaryPageOf[T plain.Type](t *testing.T) {
+	schema := parquet.SchemaOf(struct {
+		Value T `parquet:",dict"`
+	}{})
+	r := rand.New(rand.NewSource(0))
+
+	testPage(t, schema, pageTest[T]{
+		write: func(w parquet.ValueWriter) ([]T, error) {
+			values := []T{
+				0: randValue[T](r),
+				1: randValue[T](r),
+			}
+			values = append(values, values[0])
+			n, err := w.(parquet.RequiredWriter[T]).WriteRequired(values)
+			return values[:n], err
+		},
+		read: func(r parquet.ValueReader) ([]T, error) {
+			values := make([]T, 3)
+			n, err := r.(parquet.RequiredReader[T]).ReadRequired(values)
+			return values[:n], err
+		},
+	})
+}
+
+func testDictionaryPageByteArray(t *testing.T) {
+	schema := parquet.SchemaOf(struct {
+		Value []byte `parquet:",dict"`
+	}{})
+
+	testPage(t, schema, pageTest[byte]{
+		write: func(w parquet.ValueWriter) ([]byte, error) {
+			values := []byte{}
+			values = plain.AppendByteArray(values, []byte("A"))
+			values = plain.AppendByteArray(values, []byte("BC"))
+			values = plain.AppendByteArray(values, []byte("A"))
+			_, err := w.(parquet.RequiredWriter[byte]).WriteRequired(values)
+			return values, err
+		},
+		read: func(r parquet.ValueReader) ([]byte, error) {
+			values := make([]byte, 4+3*plain.ByteArrayLengthSize)
+			n, err := r.(parquet.RequiredReader[byte]).ReadRequired(values)
+			if n == 0 {
+				return values[:0], err
+			}
+			return values, err
+		},
+	})
+}
+
 type pageTest[T any] struct {
 	write func(parquet.ValueWriter) ([]T, error)
 	read  func(parquet.ValueReader) ([]T, error)
@@ -343,3 +401,38 @@ func TestRepeatedPageTrailingNulls(t *testing.T) {
 		t.Errorf("wrong number of rows read: got=%d want=%d", len(resultRows), len(records))
 	}
 }
+
+func TestRepeatedPageSliceNestedLevels(t *testing.T) {
+	type testStruct struct {
+		A [][]string `parquet:"a"`
+	}
+
+	s := parquet.SchemaOf(&testStruct{})
+
+	records := []*testStruct{
+		{A: [][]string{{"a", "b"}, {"c"}}},
+		{A: nil},
+		{A: [][]string{{"d"}}},
+		{A: [][]string{{}, {"e", "f"}}},
+	}
+
+	buf := parquet.NewBuffer(s)
+	for _, rec := range records {
+		if err := buf.WriteRow(s.Deconstruct(nil, rec)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page := buf.ColumnBuffer(0).Page()
+	for i, rec := range records {
+		want := s.Deconstruct(nil, rec)
+		got := make([]parquet.Value, len(want)+1)
+		n, err := page.Slice(int64(i), int64(i+1)).Values().ReadValues(got)
+		if err != nil && err != io.EOF {
+			t.Fatal(err)
+		}
+		if !parquet.Row(got[:n]).Equal(want) {
+			t.Errorf("wrong values in slice of row %d:\nwant = %+v\ngot  = %+v", i, want, got[:n])
+		}
+	}
+}
@@ -491,3 +491,38 @@ func TestRepeatedPageTrailingNulls(t *testing.T) {
 		t.Errorf("wrong number of rows read: got=%d want=%d", len(resultRows), len(records))
 	}
 }
+
+func TestRepeatedPageSliceNestedLevels(t *testing.T) {
+	type testStruct struct {
+		A [][]string `parquet:"a"`
+	}
+
+	s := parquet.SchemaOf(&testStruct{})
+
+	records := []*testStruct{
+		{A: [][]string{{"a", "b"}, {"c"}}},
+		{A: nil},
+		{A: [][]string{{"d"}}},
+		{A: [][]string{{}, {"e", "f"}}},
+	}
+
+	buf := parquet.NewBuffer(s)
+	for _, rec := range records {
+		if err := buf.WriteRow(s.Deconstruct(nil, rec)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page := buf.ColumnBuffer(0).Page()
+	for i, rec := range records {
+		want := s.Deconstruct(nil, rec)
+		got := make([]parquet.Value, len(want)+1)
+		n, err := page.Slice(int64(i), int64(i+1)).Values().ReadValues(got)
+		if err != nil && err != io.EOF {
+			t.Fatal(err)
+		}
+		if !parquet.Row(got[:n]).Equal(want) {
+			t.Errorf("wrong values in slice of row %d:\nwant = %+v\ngot  = %+v", i, want, got[:n])
+		}
+	}
+}
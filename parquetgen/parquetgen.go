@@ -0,0 +1,384 @@
+// Package parquetgen generates Go type declarations from parquet schemas.
+//
+// The generated types carry the struct tags expected by parquet.SchemaOf, so
+// values of these types can be written to and read from parquet files with
+// the parquet.Writer and parquet.Reader types.
+package parquetgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/segmentio/parquet-go"
+)
+
+// Generator generates Go source code declaring struct types that map to
+// parquet schemas.
+type Generator struct {
+	// Package is the name of the package that the generated code belongs to.
+	// Defaults to "main".
+	Package string
+
+	// When set to true, the generator emits getter methods for the fields of
+	// each generated struct type. Getters are safe to call on nil receivers,
+	// and dereference optional fields, returning the zero-value when the field
+	// is nil.
+	Accessors bool
+}
+
+// Generate returns the Go source code declaring a struct type named typeName
+// which maps to the given parquet schema, as well as the struct types that it
+// depends on to represent nested groups.
+//
+// The schema is typically a *parquet.Schema obtained from a parquet file or
+// from parquet.ParseSchema, but any group node is accepted.
+func (g *Generator) Generate(typeName string, schema parquet.Node) ([]byte, error) {
+	if !token.IsIdentifier(typeName) {
+		return nil, fmt.Errorf("invalid type name: %q", typeName)
+	}
+	if schema.NumChildren() == 0 {
+		return nil, fmt.Errorf("cannot generate struct type %s from a parquet schema with no columns", typeName)
+	}
+
+	packageName := g.Package
+	if packageName == "" {
+		packageName = "main"
+	}
+	if !token.IsIdentifier(packageName) {
+		return nil, fmt.Errorf("invalid package name: %q", packageName)
+	}
+
+	gen := &generator{
+		types:   make(map[string]bool),
+		imports: make(map[string]bool),
+	}
+	if err := gen.generateStruct(typeName, schema); err != nil {
+		return nil, err
+	}
+
+	b := new(bytes.Buffer)
+	b.WriteString("// Code generated by parquetgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(b, "package %s\n", packageName)
+
+	if len(gen.imports) != 0 {
+		imports := make([]string, 0, len(gen.imports))
+		for path := range gen.imports {
+			imports = append(imports, path)
+		}
+		sort.Strings(imports)
+		b.WriteString("\nimport (\n")
+		for _, path := range imports {
+			fmt.Fprintf(b, "\t%q\n", path)
+		}
+		b.WriteString(")\n")
+	}
+
+	for _, s := range gen.structs {
+		b.WriteString("\n")
+		s.writeTo(b)
+		if g.Accessors {
+			s.writeAccessorsTo(b)
+		}
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+type generator struct {
+	structs []*structType
+	types   map[string]bool
+	imports map[string]bool
+}
+
+type structType struct {
+	name   string
+	fields []structField
+}
+
+type structField struct {
+	name     string
+	goType   string
+	tag      string
+	optional bool
+}
+
+func (s *structType) writeTo(b *bytes.Buffer) {
+	fmt.Fprintf(b, "type %s struct {\n", s.name)
+	for _, f := range s.fields {
+		fmt.Fprintf(b, "\t%s %s `parquet:%s`\n", f.name, f.goType, strconv.Quote(f.tag))
+	}
+	b.WriteString("}\n")
+}
+
+func (s *structType) writeAccessorsTo(b *bytes.Buffer) {
+	for _, f := range s.fields {
+		returnType := f.goType
+		if f.optional {
+			returnType = strings.TrimPrefix(returnType, "*")
+		}
+
+		fmt.Fprintf(b, "\nfunc (x *%s) Get%s() %s {\n", s.name, f.name, returnType)
+		if f.optional {
+			fmt.Fprintf(b, "\tif x != nil && x.%s != nil {\n\t\treturn *x.%s\n\t}\n", f.name, f.name)
+			fmt.Fprintf(b, "\tvar zero %s\n\treturn zero\n", returnType)
+		} else {
+			fmt.Fprintf(b, "\tif x != nil {\n\t\treturn x.%s\n\t}\n", f.name)
+			fmt.Fprintf(b, "\tvar zero %s\n\treturn zero\n", returnType)
+		}
+		b.WriteString("}\n")
+	}
+}
+
+func (g *generator) generateStruct(name string, node parquet.Node) error {
+	if g.types[name] {
+		return fmt.Errorf("generated type name %s is used by multiple groups of the parquet schema", name)
+	}
+	g.types[name] = true
+
+	s := &structType{name: name}
+	g.structs = append(g.structs, s)
+
+	fieldNames := make(map[string]bool)
+
+	for _, columnName := range node.ChildNames() {
+		if strings.ContainsAny(columnName, ",\"`") {
+			return fmt.Errorf("column name %q of group %s cannot be represented in a parquet struct tag", columnName, name)
+		}
+
+		fieldName := exportedName(columnName)
+		for i := 2; fieldNames[fieldName]; i++ {
+			fieldName = exportedName(columnName) + strconv.Itoa(i)
+		}
+		fieldNames[fieldName] = true
+
+		field, err := g.generateField(name, fieldName, columnName, node.ChildByName(columnName))
+		if err != nil {
+			return err
+		}
+		s.fields = append(s.fields, field)
+	}
+
+	return nil
+}
+
+func (g *generator) generateField(parentName, fieldName, columnName string, node parquet.Node) (structField, error) {
+	field := structField{name: fieldName}
+	tags := []string{columnName}
+
+	goType, typeTags, err := g.goTypeOf(parentName+fieldName, node)
+	if err != nil {
+		return field, fmt.Errorf("%s.%s: %w", parentName, fieldName, err)
+	}
+
+	switch {
+	case node.Optional():
+		// Pointer types are mapped to optional columns by parquet.SchemaOf,
+		// but the tags selecting logical types only apply to non-pointer Go
+		// types; in that case, the optional tag is used instead and zero values
+		// represent nulls. Slices and maps are never wrapped in pointers since
+		// they already have a nil value.
+		if len(typeTags) != 0 || strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[") {
+			tags = append(tags, "optional")
+		} else {
+			goType = "*" + goType
+			field.optional = true
+		}
+	case node.Repeated():
+		goType = "[]" + goType
+	}
+
+	field.goType = goType
+	field.tag = strings.Join(append(tags, typeTags...), ",")
+	return field, nil
+}
+
+// goTypeOf returns the Go type used to represent values of the required form
+// of node, along with the struct tags needed to select its parquet type.
+func (g *generator) goTypeOf(name string, node parquet.Node) (string, []string, error) {
+	logicalType := node.Type().LogicalType()
+
+	if node.NumChildren() != 0 {
+		switch {
+		case logicalType != nil && logicalType.List != nil:
+			elem, err := listElementOf(node)
+			if err != nil {
+				return "", nil, err
+			}
+			elemType, elemTags, err := g.goTypeOf(name+"Element", elem)
+			if err != nil {
+				return "", nil, err
+			}
+			if len(elemTags) != 0 {
+				return "", nil, fmt.Errorf("list elements of type %s cannot be represented by a Go type", elem.Type())
+			}
+			if elem.Optional() {
+				elemType = "*" + elemType
+			}
+			return "[]" + elemType, []string{"list"}, nil
+
+		case logicalType != nil && logicalType.Map != nil:
+			key, value, err := mapKeyValueOf(node)
+			if err != nil {
+				return "", nil, err
+			}
+			if key.NumChildren() != 0 {
+				return "", nil, fmt.Errorf("map keys must be primitive types")
+			}
+			keyType, keyTags, err := g.goTypeOf(name+"Key", key)
+			if err != nil {
+				return "", nil, err
+			}
+			if keyType == "[]byte" {
+				keyType = "string"
+			}
+			valueType, valueTags, err := g.goTypeOf(name+"Value", value)
+			if err != nil {
+				return "", nil, err
+			}
+			if len(keyTags) != 0 || len(valueTags) != 0 {
+				return "", nil, fmt.Errorf("map entries of type %s and %s cannot be represented by Go types", key.Type(), value.Type())
+			}
+			switch {
+			case value.Optional():
+				valueType = "*" + valueType
+			case value.Repeated():
+				valueType = "[]" + valueType
+			}
+			return "map[" + keyType + "]" + valueType, nil, nil
+
+		default:
+			return name, nil, g.generateStruct(name, node)
+		}
+	}
+
+	typ := node.Type()
+
+	if logicalType != nil {
+		switch {
+		case logicalType.UTF8 != nil, logicalType.Json != nil:
+			return "string", nil, nil
+		case logicalType.Enum != nil:
+			return "string", []string{"enum"}, nil
+		case logicalType.UUID != nil:
+			g.imports["github.com/google/uuid"] = true
+			return "uuid.UUID", nil, nil
+		case logicalType.Date != nil:
+			return "int32", []string{"date"}, nil
+		case logicalType.Timestamp != nil:
+			if logicalType.Timestamp.Unit.Millis != nil {
+				return "int64", []string{"timestamp"}, nil
+			}
+			return "int64", nil, nil
+		case logicalType.Decimal != nil:
+			tag := fmt.Sprintf("decimal(%d:%d)", logicalType.Decimal.Scale, logicalType.Decimal.Precision)
+			switch typ.Kind() {
+			case parquet.Int32:
+				return "int32", []string{tag}, nil
+			case parquet.Int64:
+				return "int64", []string{tag}, nil
+			case parquet.FixedLenByteArray:
+				return fmt.Sprintf("[%d]byte", typ.Length()), []string{tag}, nil
+			}
+		case logicalType.Integer != nil:
+			bitWidth := logicalType.Integer.BitWidth
+			if logicalType.Integer.IsSigned {
+				return fmt.Sprintf("int%d", bitWidth), nil, nil
+			}
+			return fmt.Sprintf("uint%d", bitWidth), nil, nil
+		}
+	}
+
+	switch typ.Kind() {
+	case parquet.Boolean:
+		return "bool", nil, nil
+	case parquet.Int32:
+		return "int32", nil, nil
+	case parquet.Int64:
+		return "int64", nil, nil
+	case parquet.Int96:
+		g.imports["github.com/segmentio/parquet-go/deprecated"] = true
+		return "deprecated.Int96", nil, nil
+	case parquet.Float:
+		return "float32", nil, nil
+	case parquet.Double:
+		return "float64", nil, nil
+	case parquet.ByteArray:
+		return "[]byte", nil, nil
+	case parquet.FixedLenByteArray:
+		return fmt.Sprintf("[%d]byte", typ.Length()), nil, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported parquet type: %s", typ)
+	}
+}
+
+func listElementOf(node parquet.Node) (parquet.Node, error) {
+	if node.NumChildren() == 1 {
+		list := node.ChildByName(node.ChildNames()[0])
+		if list.Repeated() && list.NumChildren() == 1 {
+			return list.ChildByName(list.ChildNames()[0]), nil
+		}
+	}
+	return nil, fmt.Errorf("malformed LIST group")
+}
+
+func mapKeyValueOf(node parquet.Node) (key, value parquet.Node, err error) {
+	if node.NumChildren() == 1 {
+		keyValue := node.ChildByName(node.ChildNames()[0])
+		if keyValue.Repeated() && keyValue.NumChildren() == 2 {
+			key = keyValue.ChildByName("key")
+			value = keyValue.ChildByName("value")
+			if key != nil && value != nil {
+				return key, value, nil
+			}
+		}
+	}
+	return nil, nil, fmt.Errorf("malformed MAP group")
+}
+
+// exportedName converts a parquet column name to an exported Go identifier,
+// for example "user_id" becomes "UserID" and "first-name" becomes "FirstName".
+func exportedName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	b := new(strings.Builder)
+	for _, word := range words {
+		if upper := strings.ToUpper(word); commonInitialisms[upper] {
+			b.WriteString(upper)
+		} else {
+			r, size := utf8.DecodeRuneInString(word)
+			b.WriteRune(unicode.ToUpper(r))
+			b.WriteString(word[size:])
+		}
+	}
+
+	s := b.String()
+	if r, _ := utf8.DecodeRuneInString(s); !unicode.IsUpper(r) {
+		s = "X" + s
+	}
+	return s
+}
+
+var commonInitialisms = map[string]bool{
+	"API":  true,
+	"HTTP": true,
+	"ID":   true,
+	"IP":   true,
+	"JSON": true,
+	"SQL":  true,
+	"URI":  true,
+	"URL":  true,
+	"UUID": true,
+}
@@ -0,0 +1,136 @@
+package parquetgen_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/parquetgen"
+)
+
+func TestGenerate(t *testing.T) {
+	schema := parquet.NewSchema("event", parquet.Group{
+		"event_id": parquet.Int(64),
+		"name":     parquet.Optional(parquet.String()),
+		"kind":     parquet.Enum(),
+		"day":      parquet.Optional(parquet.Date()),
+		"amount":   parquet.Decimal(2, 10, parquet.Int64Type),
+		"payload":  parquet.Leaf(parquet.ByteArrayType),
+		"tags":     parquet.Repeated(parquet.String()),
+		"scores":   parquet.List(parquet.Leaf(parquet.DoubleType)),
+		"labels":   parquet.Map(parquet.String(), parquet.Int(32)),
+		"user": parquet.Optional(parquet.Group{
+			"id":    parquet.Uint(32),
+			"email": parquet.String(),
+		}),
+	})
+
+	const want = `// Code generated by parquetgen. DO NOT EDIT.
+
+package model
+
+type Event struct {
+	Amount  int64            ` + "`" + `parquet:"amount,decimal(2:10)"` + "`" + `
+	Day     int32            ` + "`" + `parquet:"day,optional,date"` + "`" + `
+	EventID int64            ` + "`" + `parquet:"event_id"` + "`" + `
+	Kind    string           ` + "`" + `parquet:"kind,enum"` + "`" + `
+	Labels  map[string]int32 ` + "`" + `parquet:"labels"` + "`" + `
+	Name    *string          ` + "`" + `parquet:"name"` + "`" + `
+	Payload []byte           ` + "`" + `parquet:"payload"` + "`" + `
+	Scores  []float64        ` + "`" + `parquet:"scores,list"` + "`" + `
+	Tags    []string         ` + "`" + `parquet:"tags"` + "`" + `
+	User    *EventUser       ` + "`" + `parquet:"user"` + "`" + `
+}
+
+type EventUser struct {
+	Email string ` + "`" + `parquet:"email"` + "`" + `
+	ID    uint32 ` + "`" + `parquet:"id"` + "`" + `
+}
+`
+
+	g := parquetgen.Generator{Package: "model"}
+	src, err := g.Generate("Event", schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(src) != want {
+		t.Errorf("generated code mismatch:\nwant:\n%s\ngot:\n%s", want, src)
+	}
+}
+
+func TestGenerateAccessors(t *testing.T) {
+	schema := parquet.NewSchema("row", parquet.Group{
+		"id":   parquet.Leaf(parquet.Int64Type),
+		"name": parquet.Optional(parquet.String()),
+	})
+
+	g := parquetgen.Generator{Accessors: true}
+	src, err := g.Generate("Row", schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"package main\n",
+		"func (x *Row) GetID() int64 {\n\tif x != nil {\n\t\treturn x.ID\n\t}",
+		"func (x *Row) GetName() string {\n\tif x != nil && x.Name != nil {\n\t\treturn *x.Name\n\t}",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated code does not contain %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateImports(t *testing.T) {
+	schema := parquet.NewSchema("row", parquet.Group{
+		"id":     parquet.UUID(),
+		"legacy": parquet.Leaf(parquet.Int96Type),
+	})
+
+	src, err := new(parquetgen.Generator).Generate("Row", schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `import (
+	"github.com/google/uuid"
+	"github.com/segmentio/parquet-go/deprecated"
+)`
+	if !strings.Contains(string(src), want) {
+		t.Errorf("generated code does not contain the expected imports:\n%s", src)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		scenario string
+		typeName string
+		schema   parquet.Node
+	}{
+		{
+			scenario: "invalid type name",
+			typeName: "not a name",
+			schema:   parquet.Group{"a": parquet.String()},
+		},
+
+		{
+			scenario: "empty schema",
+			typeName: "Row",
+			schema:   parquet.Group{},
+		},
+
+		{
+			scenario: "invalid column name",
+			typeName: "Row",
+			schema:   parquet.Group{"a,b": parquet.String()},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			if _, err := new(parquetgen.Generator).Generate(test.typeName, test.schema); err == nil {
+				t.Error("expected an error but got none")
+			}
+		})
+	}
+}
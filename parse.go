@@ -0,0 +1,537 @@
+package parquet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/segmentio/parquet-go/format"
+)
+
+// ParseSchema parses a parquet schema from its textual representation, which
+// is the message format produced by Print and used by other parquet tools,
+// for example:
+//
+//	message Person {
+//		required binary name (STRING);
+//		optional int32 age (INT(8,true));
+//		repeated group addresses {
+//			required binary city (STRING);
+//		}
+//	}
+//
+// The logical type annotations supported are STRING (and UTF8), ENUM, UUID,
+// JSON, BSON, GEOMETRY, GEOGRAPHY, DATE, TIME, TIMESTAMP, INT, DECIMAL, NULL,
+// LIST, MAP, and VARIANT. Field ids (e.g. "required int32 id = 1;") are assigned
+// to the nodes with FieldID.
+func ParseSchema(text string) (*Schema, error) {
+	p := &schemaParser{text: text}
+	p.next()
+
+	if !p.expectIdent("message") {
+		return nil, p.err
+	}
+
+	name := p.tok
+	if name == "{" {
+		name = ""
+	} else {
+		p.next()
+	}
+
+	root := p.parseGroupFields()
+	if p.err == nil && p.tok != "" {
+		p.errorf("unexpected token %q after the end of the message", p.tok)
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return NewSchema(name, root), nil
+}
+
+type schemaParser struct {
+	text string
+	tok  string
+	line int
+	err  error
+}
+
+func (p *schemaParser) errorf(msg string, args ...interface{}) {
+	if p.err == nil {
+		p.err = fmt.Errorf("parsing parquet schema: line %d: %s", p.line+1, fmt.Sprintf(msg, args...))
+	}
+}
+
+// next advances the parser to the next token of the input, setting p.tok to
+// the empty string when the end of the input was reached or an error occurred.
+func (p *schemaParser) next() {
+	if p.err != nil {
+		p.tok = ""
+		return
+	}
+
+	for len(p.text) > 0 {
+		c := rune(p.text[0])
+		if !unicode.IsSpace(c) {
+			break
+		}
+		if c == '\n' {
+			p.line++
+		}
+		p.text = p.text[1:]
+	}
+
+	if len(p.text) == 0 {
+		p.tok = ""
+		return
+	}
+
+	switch p.text[0] {
+	case '{', '}', '(', ')', ';', ',', '=':
+		p.tok, p.text = p.text[:1], p.text[1:]
+		return
+	}
+
+	i := strings.IndexFunc(p.text, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune("{}();,=", r)
+	})
+	if i < 0 {
+		i = len(p.text)
+	}
+	p.tok, p.text = p.text[:i], p.text[i:]
+}
+
+func (p *schemaParser) expect(tok string) bool {
+	if p.err != nil {
+		return false
+	}
+	if p.tok != tok {
+		if p.tok == "" {
+			p.errorf("expected %q but reached the end of the input", tok)
+		} else {
+			p.errorf("expected %q but found %q", tok, p.tok)
+		}
+		return false
+	}
+	p.next()
+	return true
+}
+
+func (p *schemaParser) expectIdent(ident string) bool {
+	if p.err == nil && !strings.EqualFold(p.tok, ident) {
+		p.errorf("expected %q but found %q", ident, p.tok)
+		return false
+	}
+	return p.expect(p.tok)
+}
+
+func (p *schemaParser) parseInt() int {
+	n, err := strconv.Atoi(p.tok)
+	if err != nil {
+		p.errorf("expected an integer but found %q", p.tok)
+		return 0
+	}
+	p.next()
+	return n
+}
+
+func (p *schemaParser) parseGroupFields() Group {
+	group := Group{}
+
+	if !p.expect("{") {
+		return group
+	}
+
+	for p.err == nil && p.tok != "}" {
+		name, node := p.parseField()
+		if p.err != nil {
+			break
+		}
+		if _, exists := group[name]; exists {
+			p.errorf("duplicate field %q", name)
+			break
+		}
+		group[name] = node
+	}
+
+	p.expect("}")
+	return group
+}
+
+func (p *schemaParser) parseField() (string, Node) {
+	repetition := strings.ToLower(p.tok)
+	switch repetition {
+	case "required", "optional", "repeated":
+		p.next()
+	default:
+		p.errorf("expected field repetition type but found %q", p.tok)
+		return "", nil
+	}
+
+	var name string
+	var node Node
+	var id int
+
+	if strings.EqualFold(p.tok, "group") {
+		p.next()
+		name = p.tok
+		p.next()
+		annotation := p.parseAnnotation()
+		id = p.parseFieldID()
+		group := p.parseGroupFields()
+
+		switch annotation {
+		case "":
+			node = group
+		case "LIST":
+			if group.NumChildren() != 1 {
+				p.errorf("LIST group %q must have exactly one child", name)
+			}
+			node = listNode{group}
+		case "MAP", "MAP_KEY_VALUE":
+			node = mapNode{group}
+		default:
+			if variant, args := splitOptionArgs(annotation); variant == "VARIANT" {
+				version, ok := parseVariantAnnotationArgs(args)
+				if !ok {
+					p.errorf("invalid annotation %q on group %q", annotation, name)
+				}
+				node = variantNode{Group: group, version: version}
+				break
+			}
+			p.errorf("invalid annotation %q on group %q", annotation, name)
+		}
+	} else {
+		typ := p.parsePhysicalType()
+		name = p.tok
+		p.next()
+		annotation := p.parseAnnotation()
+		id = p.parseFieldID()
+		p.expect(";")
+		node = p.makeLeaf(name, typ, annotation)
+	}
+
+	if p.err != nil {
+		return "", nil
+	}
+
+	switch repetition {
+	case "optional":
+		node = Optional(node)
+	case "repeated":
+		node = Repeated(node)
+	}
+	if id != 0 {
+		node = FieldID(node, id)
+	}
+	return name, node
+}
+
+func (p *schemaParser) parsePhysicalType() Type {
+	tok := strings.ToLower(p.tok)
+	p.next()
+
+	switch tok {
+	case "boolean":
+		return BooleanType
+	case "int32":
+		return Int32Type
+	case "int64":
+		return Int64Type
+	case "int96":
+		return Int96Type
+	case "float":
+		return FloatType
+	case "double":
+		return DoubleType
+	case "binary":
+		return ByteArrayType
+	case "fixed_len_byte_array":
+		p.expect("(")
+		size := p.parseInt()
+		p.expect(")")
+		if p.err == nil && size <= 0 {
+			p.errorf("invalid fixed_len_byte_array size: %d", size)
+		}
+		if p.err != nil {
+			return nil
+		}
+		return FixedLenByteArrayType(size)
+	default:
+		p.errorf("unknown physical type %q", tok)
+		return nil
+	}
+}
+
+// parseAnnotation parses an optional logical type annotation and returns it
+// in a normalized form, with its arguments separated by commas.
+func (p *schemaParser) parseAnnotation() string {
+	if p.err != nil || p.tok != "(" {
+		return ""
+	}
+	p.next()
+
+	annotation := strings.ToUpper(p.tok)
+	p.next()
+
+	if p.tok == "(" {
+		var args []string
+		for p.next(); p.err == nil && p.tok != ")"; p.next() {
+			if p.tok == "" {
+				p.errorf("unterminated annotation arguments")
+				return ""
+			}
+			args = append(args, p.tok)
+		}
+		p.expect(")")
+		annotation += "(" + strings.Join(args, "") + ")"
+	}
+
+	p.expect(")")
+	return annotation
+}
+
+func (p *schemaParser) parseFieldID() int {
+	if p.err == nil && p.tok == "=" {
+		p.next()
+		return p.parseInt()
+	}
+	return 0
+}
+
+func (p *schemaParser) makeLeaf(name string, typ Type, annotation string) Node {
+	if p.err != nil {
+		return nil
+	}
+
+	annotation, args := splitOptionArgs(annotation)
+	args = strings.TrimSuffix(strings.TrimPrefix(args, "("), ")")
+	kind := typ.Kind()
+
+	invalid := func() Node {
+		p.errorf("invalid annotation %s on field %q of type %s", annotation, name, typ)
+		return nil
+	}
+
+	switch annotation {
+	case "":
+		return Leaf(typ)
+
+	case "STRING", "UTF8":
+		if kind != ByteArray {
+			return invalid()
+		}
+		return String()
+
+	case "ENUM":
+		if kind != ByteArray {
+			return invalid()
+		}
+		return Enum()
+
+	case "JSON":
+		if kind != ByteArray {
+			return invalid()
+		}
+		return JSON()
+
+	case "BSON":
+		if kind != ByteArray {
+			return invalid()
+		}
+		return BSON()
+
+	case "UUID":
+		if kind != FixedLenByteArray || typ.Length() != 16 {
+			return invalid()
+		}
+		return UUID()
+
+	case "GEOMETRY":
+		if kind != ByteArray || strings.Contains(args, ",") {
+			return invalid()
+		}
+		return Geometry(args)
+
+	case "GEOGRAPHY":
+		if kind != ByteArray {
+			return invalid()
+		}
+		crs, algorithm, ok := parseGeographyAnnotationArgs(args)
+		if !ok {
+			return invalid()
+		}
+		return Geography(crs, algorithm)
+
+	case "DATE":
+		if kind != Int32 {
+			return invalid()
+		}
+		return Date()
+
+	case "INT":
+		parts := strings.Split(args, ",")
+		if len(parts) != 2 {
+			return invalid()
+		}
+		bitWidth, err1 := strconv.Atoi(parts[0])
+		signed, err2 := strconv.ParseBool(parts[1])
+		if err1 != nil || err2 != nil {
+			return invalid()
+		}
+		switch bitWidth {
+		case 8, 16, 32:
+			if kind != Int32 {
+				return invalid()
+			}
+		case 64:
+			if kind != Int64 {
+				return invalid()
+			}
+		default:
+			return invalid()
+		}
+		if signed {
+			return Int(bitWidth)
+		}
+		return Uint(bitWidth)
+
+	case "DECIMAL":
+		parts := strings.Split(args, ",")
+		if len(parts) != 2 {
+			return invalid()
+		}
+		a, err1 := strconv.Atoi(parts[0])
+		b, err2 := strconv.Atoi(parts[1])
+		if err1 != nil || err2 != nil {
+			return invalid()
+		}
+		// The package prints decimals as DECIMAL(scale,precision) while other
+		// tools use DECIMAL(precision,scale). The scale cannot be greater than
+		// the precision, which lets us accept both forms.
+		scale, precision := a, b
+		if scale > precision {
+			scale, precision = precision, scale
+		}
+		switch kind {
+		case Int32, Int64, FixedLenByteArray:
+			return Decimal(scale, precision, typ)
+		default:
+			return invalid()
+		}
+
+	case "TIME", "TIMESTAMP":
+		adjustedToUTC, unit, ok := parseTimeAnnotationArgs(args)
+		if !ok {
+			return invalid()
+		}
+		if annotation == "TIMESTAMP" {
+			if kind != Int64 {
+				return invalid()
+			}
+			return Leaf(&timestampType{IsAdjustedToUTC: adjustedToUTC, Unit: unit.TimeUnit()})
+		}
+		t := &timeType{IsAdjustedToUTC: adjustedToUTC, Unit: unit.TimeUnit()}
+		if t.Kind() != kind {
+			return invalid()
+		}
+		return Leaf(t)
+
+	case "TIMESTAMP_MILLIS", "TIMESTAMP_MICROS":
+		if kind != Int64 {
+			return invalid()
+		}
+		if annotation == "TIMESTAMP_MILLIS" {
+			return Timestamp(Millisecond)
+		}
+		return Timestamp(Microsecond)
+
+	case "NULL":
+		return Null(typ)
+
+	case "TIME_MILLIS":
+		if kind != Int32 {
+			return invalid()
+		}
+		return Time(Millisecond)
+
+	case "TIME_MICROS":
+		if kind != Int64 {
+			return invalid()
+		}
+		return Time(Microsecond)
+
+	default:
+		p.errorf("unknown annotation %s on field %q", annotation, name)
+		return nil
+	}
+}
+
+func parseTimeAnnotationArgs(args string) (adjustedToUTC bool, unit TimeUnit, ok bool) {
+	adjustedToUTC = true
+
+	for _, arg := range strings.Split(args, ",") {
+		i := strings.IndexByte(arg, '=')
+		if i < 0 {
+			return false, nil, false
+		}
+		k, v := arg[:i], arg[i+1:]
+
+		switch strings.ToLower(k) {
+		case "isadjustedtoutc":
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return false, nil, false
+			}
+			adjustedToUTC = b
+		case "unit":
+			switch strings.ToUpper(v) {
+			case "MILLIS":
+				unit = Millisecond
+			case "MICROS":
+				unit = Microsecond
+			case "NANOS":
+				unit = Nanosecond
+			default:
+				return false, nil, false
+			}
+		default:
+			return false, nil, false
+		}
+	}
+
+	return adjustedToUTC, unit, unit != nil
+}
+
+func parseVariantAnnotationArgs(args string) (version int8, ok bool) {
+	args = strings.TrimSuffix(strings.TrimPrefix(args, "("), ")")
+	if args == "" {
+		return 0, true
+	}
+	v, err := strconv.ParseInt(args, 10, 8)
+	if err != nil || v < 0 {
+		return 0, false
+	}
+	return int8(v), true
+}
+
+func parseGeographyAnnotationArgs(args string) (crs string, algorithm format.EdgeInterpolationAlgorithm, ok bool) {
+	if args == "" {
+		return "", format.Spherical, true
+	}
+	parts := strings.Split(args, ",")
+	if len(parts) > 2 {
+		return "", 0, false
+	}
+	crs = parts[0]
+	if len(parts) == 2 {
+		for a := format.Spherical; a <= format.Karney; a++ {
+			if strings.EqualFold(parts[1], a.String()) {
+				return crs, a, true
+			}
+		}
+		return "", 0, false
+	}
+	return crs, format.Spherical, true
+}
@@ -0,0 +1,184 @@
+package parquet_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/format"
+)
+
+func TestParseSchema(t *testing.T) {
+	tests := []struct {
+		scenario string
+		node     parquet.Node
+	}{
+		{
+			scenario: "primitive types",
+			node: parquet.Group{
+				"boolean": parquet.Leaf(parquet.BooleanType),
+				"int32":   parquet.Leaf(parquet.Int32Type),
+				"int64":   parquet.Leaf(parquet.Int64Type),
+				"int96":   parquet.Leaf(parquet.Int96Type),
+				"float":   parquet.Leaf(parquet.FloatType),
+				"double":  parquet.Leaf(parquet.DoubleType),
+				"binary":  parquet.Leaf(parquet.ByteArrayType),
+				"fixed":   parquet.Leaf(parquet.FixedLenByteArrayType(10)),
+			},
+		},
+
+		{
+			scenario: "logical types",
+			node: parquet.Group{
+				"string":    parquet.String(),
+				"enum":      parquet.Enum(),
+				"uuid":      parquet.UUID(),
+				"json":      parquet.JSON(),
+				"bson":      parquet.BSON(),
+				"date":      parquet.Date(),
+				"time":      parquet.Time(parquet.Millisecond),
+				"timestamp": parquet.Timestamp(parquet.Microsecond),
+				"int8":      parquet.Int(8),
+				"uint64":    parquet.Uint(64),
+				"decimal":   parquet.Decimal(2, 10, parquet.Int64Type),
+			},
+		},
+
+		{
+			scenario: "local times",
+			node: parquet.Group{
+				"time":      parquet.TimeAdjusted(parquet.Nanosecond, false),
+				"timestamp": parquet.TimestampAdjusted(parquet.Millisecond, false),
+			},
+		},
+
+		{
+			scenario: "geospatial types",
+			node: parquet.Group{
+				"geometry":       parquet.Geometry(""),
+				"geometry_crs":   parquet.Geometry("EPSG:4326"),
+				"geography":      parquet.Geography("", format.Spherical),
+				"geography_crs":  parquet.Geography("OGC:CRS84", format.Karney),
+				"geography_edge": parquet.Optional(parquet.Geography("", format.Vincenty)),
+			},
+		},
+
+		{
+			scenario: "variant types",
+			node: parquet.Group{
+				"variant":  parquet.Optional(parquet.Variant()),
+				"shredded": parquet.ShreddedVariant(parquet.Leaf(parquet.Int64Type)),
+			},
+		},
+
+		{
+			scenario: "null types",
+			node: parquet.Group{
+				"int32":  parquet.Optional(parquet.Null(parquet.Int32Type)),
+				"binary": parquet.Optional(parquet.Null(parquet.ByteArrayType)),
+			},
+		},
+
+		{
+			scenario: "field ids",
+			node: parquet.Group{
+				"id":   parquet.FieldID(parquet.Leaf(parquet.Int64Type), 1),
+				"name": parquet.FieldID(parquet.Optional(parquet.String()), 2),
+				"tags": parquet.FieldID(parquet.List(parquet.FieldID(parquet.String(), 4)), 3),
+			},
+		},
+
+		{
+			scenario: "nested groups",
+			node: parquet.Group{
+				"name": parquet.Optional(parquet.String()),
+				"tags": parquet.Repeated(parquet.String()),
+				"address": parquet.Optional(parquet.Group{
+					"city": parquet.String(),
+					"zip":  parquet.Optional(parquet.Leaf(parquet.Int32Type)),
+				}),
+				"scores": parquet.List(parquet.Leaf(parquet.DoubleType)),
+				"labels": parquet.Map(parquet.String(), parquet.Optional(parquet.Leaf(parquet.Int64Type))),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			want := new(strings.Builder)
+			if err := parquet.Print(want, "Test", test.node); err != nil {
+				t.Fatal(err)
+			}
+
+			schema, err := parquet.ParseSchema(want.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if schema.Name() != "Test" {
+				t.Errorf("wrong schema name: want=Test got=%s", schema.Name())
+			}
+
+			got := new(strings.Builder)
+			if err := parquet.Print(got, schema.Name(), schema); err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != want.String() {
+				t.Errorf("schema mismatch:\nwant:\n%s\ngot:\n%s", want, got)
+			}
+		})
+	}
+}
+
+func TestParseSchemaAlternativeForms(t *testing.T) {
+	const input = `message spark_schema {
+  optional binary name (UTF8) = 1;
+  required int64 created (TIMESTAMP_MILLIS);
+  required int64 updated (TIMESTAMP(isAdjustedToUTC=false,unit=NANOS));
+  required fixed_len_byte_array(8) amount (DECIMAL(18,2));
+}`
+
+	const want = `message spark_schema {
+	required fixed_len_byte_array(8) amount (DECIMAL(2,18));
+	required int64 created (TIMESTAMP(isAdjustedToUTC=true,unit=MILLIS));
+	optional binary name (STRING) = 1;
+	required int64 updated (TIMESTAMP(isAdjustedToUTC=false,unit=NANOS));
+}`
+
+	schema, err := parquet.ParseSchema(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := new(strings.Builder)
+	if err := parquet.Print(got, schema.Name(), schema); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want {
+		t.Errorf("schema mismatch:\nwant:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    string
+	}{
+		{"empty input", ``},
+		{"missing message", `Test { required int32 a; }`},
+		{"unterminated message", `message Test { required int32 a;`},
+		{"missing semicolon", `message Test { required int32 a }`},
+		{"unknown type", `message Test { required int128 a; }`},
+		{"unknown annotation", `message Test { required binary a (FOO); }`},
+		{"invalid annotation", `message Test { required int32 a (STRING); }`},
+		{"duplicate field", `message Test { required int32 a; required int64 a; }`},
+		{"trailing tokens", `message Test { required int32 a; } }`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			if _, err := parquet.ParseSchema(test.input); err == nil {
+				t.Error("expected an error but got none")
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package parquet
 
 import (
+	"fmt"
 	"io"
 	"strconv"
 	"strings"
@@ -42,6 +43,52 @@ func PrintIndent(w io.Writer, name string, node Node, pattern, newline string) e
 	return pw.err
 }
 
+// PrintRow writes to w a representation of row, which is expected to have
+// been produced by the given schema, with one line per value showing the path
+// of its column, its column index, definition level, repetition level, type,
+// and value, for example:
+//
+//	id         C:0 D:0 R:0 INT64 V:1
+//	name.first C:1 D:1 R:0 STRING V:"Luke"
+//	tags       C:2 D:1 R:0 STRING V:"jedi"
+//	tags       C:2 D:1 R:1 STRING V:"pilot"
+//
+// Types are shown with their logical type annotation, if any. Values of column
+// indexes which do not exist in the schema are shown with their kind, and a
+// question mark in place of their column path.
+func PrintRow(w io.Writer, schema *Schema, row Row) error {
+	var paths []string
+	var types []Type
+	forEachLeafColumnOf(schema, func(leaf leafColumn) {
+		paths = append(paths, leaf.path.String())
+		types = append(types, leaf.node.Type())
+	})
+
+	width := 1
+	for _, path := range paths {
+		if len(path) > width {
+			width = len(path)
+		}
+	}
+
+	pw := &printWriter{writer: w}
+	line := new(strings.Builder)
+	for _, v := range row {
+		path, typ := "?", v.Kind().String()
+		if c := v.Column(); c >= 0 && c < len(paths) {
+			path, typ = paths[c], types[c].String()
+		}
+		line.Reset()
+		line.WriteString(path)
+		line.WriteString(strings.Repeat(" ", width-len(path)+1))
+		fmt.Fprintf(line, "%+[1]c %+[1]d %+[1]r ", v)
+		v.formatKindAndValue(line, typ)
+		line.WriteString("\n")
+		pw.WriteString(line.String())
+	}
+	return pw.err
+}
+
 func printWithIndent(w io.StringWriter, name string, node Node, indent *printIndent) {
 	indent.writeTo(w)
 
@@ -90,6 +137,7 @@ func printWithIndent(w io.StringWriter, name string, node Node, indent *printInd
 			w.WriteString(")")
 		}
 
+		writeFieldID(w, node)
 		w.WriteString(";")
 	} else {
 		w.WriteString("group")
@@ -105,6 +153,7 @@ func printWithIndent(w io.StringWriter, name string, node Node, indent *printInd
 			w.WriteString(")")
 		}
 
+		writeFieldID(w, node)
 		w.WriteString(" {")
 		indent.writeNewLine(w)
 		indent.push()
@@ -120,6 +169,13 @@ func printWithIndent(w io.StringWriter, name string, node Node, indent *printInd
 	}
 }
 
+func writeFieldID(w io.StringWriter, node Node) {
+	if id := fieldIDOf(node); id != 0 {
+		w.WriteString(" = ")
+		w.WriteString(strconv.Itoa(id))
+	}
+}
+
 func annotationOf(node Node) string {
 	if logicalType := node.Type().LogicalType(); logicalType != nil {
 		return logicalType.String()
@@ -265,3 +265,42 @@ func TestPrint(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintRow(t *testing.T) {
+	type Name struct {
+		First string `parquet:"first,optional"`
+	}
+	type Row struct {
+		ID   int64    `parquet:"id"`
+		Name Name     `parquet:"name"`
+		Tags []string `parquet:"tags"`
+		Age  *int32   `parquet:"age,optional"`
+	}
+
+	schema := parquet.SchemaOf(new(Row))
+	row := schema.Deconstruct(nil, &Row{
+		ID:   1,
+		Name: Name{First: "Luke"},
+		Tags: []string{"jedi", "pilot"},
+	})
+
+	buffer := new(strings.Builder)
+	if err := parquet.PrintRow(buffer, schema, row); err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `age        C:0 D:0 R:0 V:<null>
+id         C:1 D:0 R:0 INT(64,true) V:1
+name.first C:2 D:1 R:0 STRING V:"Luke"
+tags       C:3 D:1 R:0 STRING V:"jedi"
+tags       C:3 D:1 R:1 STRING V:"pilot"
+`
+	if got := buffer.String(); got != want {
+		t.Errorf("wrong row representation:\nwant:\n%s\ngot:\n%s", want, got)
+	}
+
+	const wantString = `[{C:0 D:0 R:0 V:<null>} {C:1 D:0 R:0 K:INT64 V:1} {C:2 D:1 R:0 K:BYTE_ARRAY V:"Luke"} {C:3 D:1 R:0 K:BYTE_ARRAY V:"jedi"} {C:3 D:1 R:1 K:BYTE_ARRAY V:"pilot"}]`
+	if got := row.String(); got != wantString {
+		t.Errorf("wrong row string:\nwant: %s\ngot:  %s", wantString, got)
+	}
+}
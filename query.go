@@ -0,0 +1,736 @@
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// QueryFile runs a minimal SQL query on the rows of f, returning a reader
+// producing the rows matching the query.
+//
+// The query language supports a small subset of SQL, intended for ad-hoc
+// inspection of parquet files:
+//
+//	SELECT * | column [, column...] FROM name [WHERE condition] [LIMIT n]
+//
+// The columns selected must be top-level fields of the file schema, the
+// selection is applied using the same rules as the Convert function; the
+// columns of the result schema are therefore ordered by name. The name
+// following FROM designates the file and is not interpreted, it may be an
+// identifier or a quoted string.
+//
+// Conditions compare leaf columns to literal values with the =, !=, <>, <,
+// <=, > and >= operators, test column values with IS [NOT] NULL and IN (...),
+// and can be combined with AND, OR, NOT, and parenthesis. Nested columns are
+// referenced by joining the elements of their path with dots, repeated
+// columns cannot be used in conditions. Literal values are strings in single
+// quotes, numbers, and the TRUE and FALSE keywords; they are parsed according
+// to the logical type of the column they are compared to, for example
+// timestamps are expressed as RFC 3339 strings. Comparisons with null values
+// follow the SQL three-valued logic.
+//
+// Row groups are skipped when the column indexes of the file indicate that
+// none of their pages can match the condition, the remaining rows are
+// filtered as they are read.
+//
+//	rows, err := parquet.QueryFile(f, `SELECT id, name FROM users WHERE age >= 18 LIMIT 10`)
+//	if err != nil {
+//		...
+//	}
+//	defer rows.Close()
+//
+//	if _, err := parquet.CopyRows(output, rows); err != nil {
+//		...
+//	}
+//
+// The function returns an error if the query is malformed or does not match
+// the file schema.
+func QueryFile(f *File, query string) (QueryRows, error) {
+	schema := NewSchema(f.root.Name(), f.root)
+
+	p := queryParser{leaves: make(map[string]leafColumn)}
+	forEachLeafColumnOf(schema, func(leaf leafColumn) {
+		p.leaves[strings.Join(leaf.path, ".")] = leaf
+	})
+
+	q, err := p.parse(query)
+	if err != nil {
+		return nil, fmt.Errorf("parsing parquet query: %w", err)
+	}
+
+	r := &queryRows{
+		where:  q.where,
+		limit:  q.limit,
+		schema: schema,
+	}
+
+	if q.columns != nil {
+		paths := make([][]string, len(q.columns))
+		for i, name := range q.columns {
+			paths[i] = []string{name}
+		}
+		projection, err := schema.Select(paths...)
+		if err != nil {
+			return nil, fmt.Errorf("parquet query: %w", err)
+		}
+		conv, err := Convert(projection, schema)
+		if err != nil {
+			return nil, err
+		}
+		r.conv, r.schema = conv, conv.Schema()
+	}
+
+	for _, rowGroup := range f.RowGroups() {
+		if r.where == nil || r.where.mayMatch(rowGroup) {
+			r.rowGroups = append(r.rowGroups, rowGroup)
+		}
+	}
+
+	if r.where != nil {
+		r.values = make([]Value, numLeafColumnsOf(schema))
+	}
+	return r, nil
+}
+
+// QueryRows is the interface of values returned by QueryFile to read the rows
+// matching a query.
+//
+// The Close method must be called when the program does not need to read more
+// rows, to release the resources held by the reader.
+type QueryRows interface {
+	RowReaderWithSchema
+	io.Closer
+}
+
+type queryRows struct {
+	rowGroups []RowGroup
+	rows      Rows
+	where     queryExpr
+	conv      Conversion
+	schema    *Schema
+	limit     int64
+	count     int64
+	buffer    Row
+	values    []Value
+}
+
+func (r *queryRows) Schema() *Schema { return r.schema }
+
+func (r *queryRows) ReadRow(row Row) (Row, error) {
+	if r.limit >= 0 && r.count >= r.limit {
+		return row, io.EOF
+	}
+
+	for {
+		if r.rows == nil {
+			if len(r.rowGroups) == 0 {
+				return row, io.EOF
+			}
+			r.rows = r.rowGroups[0].Rows()
+			r.rowGroups = r.rowGroups[1:]
+		}
+
+		var err error
+		r.buffer, err = r.rows.ReadRow(r.buffer[:0])
+		if err != nil {
+			if err != io.EOF {
+				return row, err
+			}
+			if err := r.closeRows(); err != nil {
+				return row, err
+			}
+			continue
+		}
+
+		if r.where != nil {
+			for _, v := range r.buffer {
+				r.values[v.Column()] = v
+			}
+			if r.where.eval(r.values) != queryTrue {
+				continue
+			}
+		}
+
+		r.count++
+		if r.conv != nil {
+			return r.conv.Convert(row, r.buffer)
+		}
+		return append(row, r.buffer...), nil
+	}
+}
+
+func (r *queryRows) Close() error {
+	r.rowGroups = nil
+	clearValues(r.buffer)
+	clearValues(r.values)
+	return r.closeRows()
+}
+
+func (r *queryRows) closeRows() error {
+	rows := r.rows
+	r.rows = nil
+	if c, ok := rows.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// queryBool represents the result of evaluating query conditions, using the
+// three-valued logic of SQL.
+type queryBool int8
+
+const (
+	queryFalse queryBool = iota
+	queryTrue
+	queryNull
+)
+
+func (b queryBool) and(other queryBool) queryBool {
+	switch {
+	case b == queryFalse || other == queryFalse:
+		return queryFalse
+	case b == queryNull || other == queryNull:
+		return queryNull
+	default:
+		return queryTrue
+	}
+}
+
+func (b queryBool) or(other queryBool) queryBool {
+	switch {
+	case b == queryTrue || other == queryTrue:
+		return queryTrue
+	case b == queryNull || other == queryNull:
+		return queryNull
+	default:
+		return queryFalse
+	}
+}
+
+func (b queryBool) not() queryBool {
+	switch b {
+	case queryTrue:
+		return queryFalse
+	case queryFalse:
+		return queryTrue
+	default:
+		return queryNull
+	}
+}
+
+func makeQueryBool(b bool) queryBool {
+	if b {
+		return queryTrue
+	}
+	return queryFalse
+}
+
+// queryExpr is the interface implemented by query conditions.
+//
+// The eval method evaluates the condition on the values of a row, indexed by
+// column. The mayMatch method returns false if the column indexes of the row
+// group prove that none of its rows can match the condition.
+type queryExpr interface {
+	eval(values []Value) queryBool
+	mayMatch(rowGroup RowGroup) bool
+}
+
+type queryAnd struct{ left, right queryExpr }
+
+func (e *queryAnd) eval(values []Value) queryBool {
+	return e.left.eval(values).and(e.right.eval(values))
+}
+
+func (e *queryAnd) mayMatch(rowGroup RowGroup) bool {
+	return e.left.mayMatch(rowGroup) && e.right.mayMatch(rowGroup)
+}
+
+type queryOr struct{ left, right queryExpr }
+
+func (e *queryOr) eval(values []Value) queryBool {
+	return e.left.eval(values).or(e.right.eval(values))
+}
+
+func (e *queryOr) mayMatch(rowGroup RowGroup) bool {
+	return e.left.mayMatch(rowGroup) || e.right.mayMatch(rowGroup)
+}
+
+type queryNot struct{ expr queryExpr }
+
+func (e *queryNot) eval(values []Value) queryBool { return e.expr.eval(values).not() }
+
+// The column indexes only tell whether rows may match a condition, which
+// cannot be inverted, so negations never prune row groups.
+func (e *queryNot) mayMatch(RowGroup) bool { return true }
+
+type queryIsNull struct {
+	column leafColumn
+	not    bool
+}
+
+func (e *queryIsNull) eval(values []Value) queryBool {
+	return makeQueryBool(values[e.column.columnIndex].IsNull() != e.not)
+}
+
+func (e *queryIsNull) mayMatch(rowGroup RowGroup) bool {
+	columnIndex := rowGroup.Column(int(e.column.columnIndex)).ColumnIndex()
+	if columnIndex == nil {
+		return true
+	}
+	for i, n := 0, columnIndex.NumPages(); i < n; i++ {
+		if e.not {
+			if !columnIndex.NullPage(i) {
+				return true
+			}
+		} else if columnIndex.NullPage(i) || columnIndex.NullCount(i) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+type queryOp int8
+
+const (
+	queryEq queryOp = iota
+	queryNe
+	queryLt
+	queryLe
+	queryGt
+	queryGe
+)
+
+func (op queryOp) test(cmp int) bool {
+	switch op {
+	case queryEq:
+		return cmp == 0
+	case queryNe:
+		return cmp != 0
+	case queryLt:
+		return cmp < 0
+	case queryLe:
+		return cmp <= 0
+	case queryGt:
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+// mayMatch returns true if a value in the range [min, max] may satisfy the
+// comparison with the literal.
+func (op queryOp) mayMatch(typ Type, min, max, literal Value) bool {
+	switch op {
+	case queryEq:
+		return typ.Compare(min, literal) <= 0 && typ.Compare(max, literal) >= 0
+	case queryNe:
+		return typ.Compare(min, literal) != 0 || typ.Compare(max, literal) != 0
+	case queryLt:
+		return typ.Compare(min, literal) < 0
+	case queryLe:
+		return typ.Compare(min, literal) <= 0
+	case queryGt:
+		return typ.Compare(max, literal) > 0
+	default:
+		return typ.Compare(max, literal) >= 0
+	}
+}
+
+type queryCompare struct {
+	column   leafColumn
+	op       queryOp
+	literals []Value // multiple literals are matched with OR, used for IN
+}
+
+func (e *queryCompare) eval(values []Value) queryBool {
+	v := values[e.column.columnIndex]
+	if v.IsNull() {
+		return queryNull
+	}
+	typ := e.column.node.Type()
+	for _, literal := range e.literals {
+		if e.op.test(typ.Compare(v, literal)) {
+			return queryTrue
+		}
+	}
+	return queryFalse
+}
+
+func (e *queryCompare) mayMatch(rowGroup RowGroup) bool {
+	columnIndex := rowGroup.Column(int(e.column.columnIndex)).ColumnIndex()
+	if columnIndex == nil {
+		return true
+	}
+	typ := e.column.node.Type()
+	for i, n := 0, columnIndex.NumPages(); i < n; i++ {
+		if columnIndex.NullPage(i) {
+			continue
+		}
+		min, max := columnIndex.MinValue(i), columnIndex.MaxValue(i)
+		for _, literal := range e.literals {
+			if e.op.mayMatch(typ, min, max, literal) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type query struct {
+	columns []string // nil for SELECT *
+	where   queryExpr
+	limit   int64 // -1 when there is no limit
+}
+
+type queryTokenKind int8
+
+const (
+	queryEOF queryTokenKind = iota
+	queryIdentifier
+	queryKeyword
+	queryString
+	queryNumber
+	queryPunct
+)
+
+type queryToken struct {
+	kind queryTokenKind
+	text string
+	pos  int
+}
+
+var queryKeywords = map[string]bool{
+	"SELECT": true,
+	"FROM":   true,
+	"WHERE":  true,
+	"LIMIT":  true,
+	"AND":    true,
+	"OR":     true,
+	"NOT":    true,
+	"IS":     true,
+	"NULL":   true,
+	"IN":     true,
+	"TRUE":   true,
+	"FALSE":  true,
+}
+
+type queryParser struct {
+	leaves map[string]leafColumn
+	tokens []queryToken
+}
+
+func (p *queryParser) tokenize(s string) error {
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '\'' || c == '"' || c == '`':
+			// Single quotes delimit string literals, double quotes and
+			// backticks delimit identifiers. The delimiter is escaped by
+			// repeating it.
+			kind := queryIdentifier
+			if c == '\'' {
+				kind = queryString
+			}
+			text, start := []byte{}, i
+			for i++; ; i++ {
+				if i == len(s) {
+					return fmt.Errorf("unterminated quoted string at offset %d", start)
+				}
+				if s[i] == c {
+					if i+1 < len(s) && s[i+1] == c {
+						i++
+					} else {
+						break
+					}
+				}
+				text = append(text, s[i])
+			}
+			i++
+			p.tokens = append(p.tokens, queryToken{kind: kind, text: string(text), pos: start})
+
+		case c == '-' || c == '.' || (c >= '0' && c <= '9'):
+			start := i
+			for i++; i < len(s) && (isQueryIdentifierChar(s[i]) || ((s[i] == '+' || s[i] == '-') && (s[i-1] == 'e' || s[i-1] == 'E'))); i++ {
+			}
+			text := s[start:i]
+			if _, err := strconv.ParseFloat(text, 64); err != nil {
+				return fmt.Errorf("invalid number %q at offset %d", text, start)
+			}
+			p.tokens = append(p.tokens, queryToken{kind: queryNumber, text: text, pos: start})
+
+		case isQueryIdentifierChar(c):
+			start := i
+			for i++; i < len(s) && isQueryIdentifierChar(s[i]); i++ {
+			}
+			text := s[start:i]
+			kind := queryIdentifier
+			if keyword := strings.ToUpper(text); queryKeywords[keyword] {
+				kind, text = queryKeyword, keyword
+			}
+			p.tokens = append(p.tokens, queryToken{kind: kind, text: text, pos: start})
+
+		default:
+			start := i
+			switch {
+			case strings.HasPrefix(s[i:], "<="), strings.HasPrefix(s[i:], ">="),
+				strings.HasPrefix(s[i:], "!="), strings.HasPrefix(s[i:], "<>"):
+				i += 2
+			case strings.IndexByte(",()*=<>", c) >= 0:
+				i++
+			default:
+				return fmt.Errorf("unexpected character %q at offset %d", c, start)
+			}
+			p.tokens = append(p.tokens, queryToken{kind: queryPunct, text: s[start:i], pos: start})
+		}
+	}
+	return nil
+}
+
+func isQueryIdentifierChar(c byte) bool {
+	return c == '_' || c == '.' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func (p *queryParser) peek() queryToken {
+	if len(p.tokens) == 0 {
+		return queryToken{kind: queryEOF, pos: -1}
+	}
+	return p.tokens[0]
+}
+
+func (p *queryParser) next() queryToken {
+	t := p.peek()
+	if len(p.tokens) != 0 {
+		p.tokens = p.tokens[1:]
+	}
+	return t
+}
+
+// accept consumes the next token if it is a keyword or punctuation matching
+// text, returning whether it did.
+func (p *queryParser) accept(text string) bool {
+	if t := p.peek(); (t.kind == queryKeyword || t.kind == queryPunct) && t.text == text {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) expect(text string) error {
+	if !p.accept(text) {
+		return p.unexpected(text)
+	}
+	return nil
+}
+
+func (p *queryParser) unexpected(expected string) error {
+	t := p.peek()
+	if t.kind == queryEOF {
+		return fmt.Errorf("expected %s but reached the end of the query", expected)
+	}
+	return fmt.Errorf("expected %s but found %q at offset %d", expected, t.text, t.pos)
+}
+
+func (p *queryParser) parse(s string) (*query, error) {
+	if err := p.tokenize(s); err != nil {
+		return nil, err
+	}
+
+	q := &query{limit: -1}
+
+	if err := p.expect("SELECT"); err != nil {
+		return nil, err
+	}
+	if !p.accept("*") {
+		for {
+			t := p.peek()
+			if t.kind != queryIdentifier {
+				return nil, p.unexpected("column name")
+			}
+			p.next()
+			q.columns = append(q.columns, t.text)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+
+	if err := p.expect("FROM"); err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != queryIdentifier && t.kind != queryString {
+		return nil, p.unexpected("file name")
+	}
+	p.next()
+
+	if p.accept("WHERE") {
+		where, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		q.where = where
+	}
+
+	if p.accept("LIMIT") {
+		t := p.next()
+		limit, err := strconv.ParseInt(t.text, 10, 64)
+		if t.kind != queryNumber || err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid limit %q at offset %d", t.text, t.pos)
+		}
+		q.limit = limit
+	}
+
+	if p.peek().kind != queryEOF {
+		return nil, p.unexpected("end of query")
+	}
+	return q, nil
+}
+
+func (p *queryParser) parseOr() (queryExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &queryOr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (queryExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &queryAnd{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseNot() (queryExpr, error) {
+	if p.accept("NOT") {
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &queryNot{expr: expr}, nil
+	}
+	return p.parseCondition()
+}
+
+func (p *queryParser) parseCondition() (queryExpr, error) {
+	if p.accept("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	}
+
+	t := p.peek()
+	if t.kind != queryIdentifier {
+		return nil, p.unexpected("column name")
+	}
+	p.next()
+
+	column, ok := p.leaves[t.text]
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("column %q at offset %d is not a leaf column of the file schema", t.text, t.pos)
+	case column.maxRepetitionLevel > 0:
+		return nil, fmt.Errorf("column %q at offset %d is repeated and cannot be used in conditions", t.text, t.pos)
+	}
+
+	if p.accept("IS") {
+		not := p.accept("NOT")
+		if err := p.expect("NULL"); err != nil {
+			return nil, err
+		}
+		return &queryIsNull{column: column, not: not}, nil
+	}
+
+	not := p.accept("NOT")
+	if p.accept("IN") {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		expr := &queryCompare{column: column, op: queryEq}
+		for {
+			literal, err := p.parseLiteral(column)
+			if err != nil {
+				return nil, err
+			}
+			expr.literals = append(expr.literals, literal)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		if not {
+			return &queryNot{expr: expr}, nil
+		}
+		return expr, nil
+	}
+	if not {
+		return nil, p.unexpected("IN")
+	}
+
+	var op queryOp
+	switch p.next().text {
+	case "=":
+		op = queryEq
+	case "!=", "<>":
+		op = queryNe
+	case "<":
+		op = queryLt
+	case "<=":
+		op = queryLe
+	case ">":
+		op = queryGt
+	case ">=":
+		op = queryGe
+	default:
+		return nil, fmt.Errorf("expected comparison operator after column %q at offset %d", t.text, t.pos)
+	}
+
+	literal, err := p.parseLiteral(column)
+	if err != nil {
+		return nil, err
+	}
+	return &queryCompare{column: column, op: op, literals: []Value{literal}}, nil
+}
+
+func (p *queryParser) parseLiteral(column leafColumn) (Value, error) {
+	t := p.peek()
+	switch {
+	case t.kind == queryString, t.kind == queryNumber:
+	case t.kind == queryKeyword && (t.text == "TRUE" || t.text == "FALSE"):
+		t.text = strings.ToLower(t.text)
+	default:
+		return Value{}, p.unexpected("literal value")
+	}
+	p.next()
+	v, err := parseValueText(column.node.Type(), t.text)
+	if err != nil {
+		return Value{}, fmt.Errorf("literal at offset %d: %w", t.pos, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,230 @@
+package parquet_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestQueryFile(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+		Age  *int32 `parquet:"age,optional"`
+	}
+
+	// Four row groups of 10 rows each, every third row has a null age.
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	for i := 0; i < 40; i++ {
+		row := &Row{ID: int64(i), Name: fmt.Sprintf("name-%02d", i)}
+		if i%3 != 0 {
+			age := int32(i)
+			row.Age = &age
+		}
+		if err := writer.Write(row); err != nil {
+			t.Fatal(err)
+		}
+		if i%10 == 9 {
+			if err := writer.Flush(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rowGroupsRead := make(map[int]bool)
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()),
+		parquet.ObserveReader(&parquet.ReaderObserver{
+			OnPage: func(stats parquet.ReaderPageStats) { rowGroupsRead[stats.RowGroup] = true },
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		query     string
+		ids       []int64
+		rowGroups int
+	}{
+		{
+			query:     `SELECT * FROM file WHERE id >= 12 AND id < 15`,
+			ids:       []int64{12, 13, 14},
+			rowGroups: 1,
+		},
+		{
+			query:     `SELECT * FROM file WHERE id = 3 OR id = 35`,
+			ids:       []int64{3, 35},
+			rowGroups: 2,
+		},
+		{
+			query:     `SELECT * FROM "test.parquet" WHERE name IN ('name-01', 'name-21') LIMIT 1`,
+			ids:       []int64{1},
+			rowGroups: 1,
+		},
+		{
+			query:     `select * from file where age is null and id < 10`,
+			ids:       []int64{0, 3, 6, 9},
+			rowGroups: 1,
+		},
+		{
+			// Null ages are neither greater nor not greater than 5.
+			query:     `SELECT * FROM file WHERE NOT (age > 5) AND id NOT IN (1, 2)`,
+			ids:       []int64{4, 5},
+			rowGroups: 4,
+		},
+		{
+			query:     `SELECT * FROM file WHERE id > 100`,
+			rowGroups: 0,
+		},
+		{
+			query:     `SELECT * FROM file LIMIT 2`,
+			ids:       []int64{0, 1},
+			rowGroups: 1,
+		},
+	} {
+		t.Run(test.query, func(t *testing.T) {
+			for k := range rowGroupsRead {
+				delete(rowGroupsRead, k)
+			}
+
+			rows, err := parquet.QueryFile(f, test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rows.Close()
+
+			ids := []int64{}
+			for {
+				row, err := rows.ReadRow(nil)
+				if err != nil {
+					if err != io.EOF {
+						t.Fatal(err)
+					}
+					break
+				}
+				ids = append(ids, row[1].Int64()) // columns are age, id, name
+			}
+
+			if fmt.Sprint(ids) != fmt.Sprint(test.ids) && !(len(ids) == 0 && len(test.ids) == 0) {
+				t.Errorf("wrong ids: want=%v got=%v", test.ids, ids)
+			}
+			if len(rowGroupsRead) != test.rowGroups {
+				t.Errorf("wrong number of row groups read: want=%d got=%d", test.rowGroups, len(rowGroupsRead))
+			}
+		})
+	}
+
+	t.Run("projection", func(t *testing.T) {
+		rows, err := parquet.QueryFile(f, `SELECT name, id FROM file WHERE age >= 38`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		const want = `message Row {
+	required int64 id (INT(64,true));
+	required binary name (STRING);
+}`
+		if got := rows.Schema().String(); got != want {
+			t.Errorf("wrong schema:\nwant:\n%s\ngot:\n%s", want, got)
+		}
+
+		row, err := rows.ReadRow(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(row) != 2 || row[0].Int64() != 38 || row[1].String() != "name-38" {
+			t.Errorf("wrong row: %v", row)
+		}
+		if _, err := rows.ReadRow(nil); err != io.EOF {
+			t.Errorf("expected io.EOF after the last row, got %v", err)
+		}
+	})
+
+	for _, query := range []string{
+		``,
+		`SELECT`,
+		`SELECT * FROM`,
+		`SELECT nope FROM file`,
+		`SELECT * FROM file WHERE nope = 1`,
+		`SELECT * FROM file WHERE id = 'abc'`,
+		`SELECT * FROM file WHERE id = `,
+		`SELECT * FROM file WHERE (id = 1`,
+		`SELECT * FROM file WHERE id NOT 1`,
+		`SELECT * FROM file WHERE name = 'unterminated`,
+		`SELECT * FROM file LIMIT -1`,
+		`SELECT * FROM file LIMIT 1 extra`,
+	} {
+		if _, err := parquet.QueryFile(f, query); err == nil {
+			t.Errorf("expected an error for query %q", query)
+		}
+	}
+}
+
+func TestQueryFileIsNull(t *testing.T) {
+	type Row struct {
+		ID   int64   `parquet:"id"`
+		Name *string `parquet:"name,optional"`
+	}
+
+	// Three row groups of two rows each, the third row has a null name.
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.MaxRowsPerRowGroup(2))
+	for i := 1; i <= 6; i++ {
+		row := &Row{ID: int64(i)}
+		if i != 3 {
+			name := fmt.Sprintf("name-%d", i)
+			row.Name = &name
+		}
+		if err := writer.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(f.RowGroups()); n != 3 {
+		t.Fatalf("wrong number of row groups: want=3 got=%d", n)
+	}
+
+	queryIDs := func(query string) []int64 {
+		rows, err := parquet.QueryFile(f, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		ids := []int64{}
+		for {
+			row, err := rows.ReadRow(nil)
+			if err != nil {
+				if err != io.EOF {
+					t.Fatal(err)
+				}
+				return ids
+			}
+			ids = append(ids, row[0].Int64()) // columns are id, name
+		}
+	}
+
+	// Negations never prune row groups, so they yield the result of a full scan.
+	want := queryIDs(`SELECT * FROM file WHERE NOT (name IS NOT NULL)`)
+	if fmt.Sprint(want) != "[3]" {
+		t.Fatalf("wrong ids of the full scan: want=[3] got=%v", want)
+	}
+	if got := queryIDs(`SELECT * FROM file WHERE name IS NULL`); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("wrong ids: want=%v got=%v", want, got)
+	}
+}
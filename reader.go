@@ -1,6 +1,8 @@
 package parquet
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
@@ -31,6 +33,43 @@ type Reader struct {
 	read     reader
 	rowIndex int64
 	values   []Value
+
+	// When the SkipCorrupted option is set, the reader uses the number of
+	// rows in each row group to skip those which contain corrupted data.
+	skipCorrupted func(error)
+	rowGroupRows  []int64
+	observer      *ReaderObserver
+
+	// The context of the ReadContext or ReadRowContext call in progress, which
+	// the pages of the file check before they are read.
+	context readContext
+}
+
+// readContext holds the context of the reads in progress on a Reader, it is
+// shared with the column chunks of the file that the reader reads pages from.
+type readContext struct {
+	ctx context.Context
+}
+
+func (c *readContext) err() error {
+	if c == nil || c.ctx == nil {
+		return nil
+	}
+	return c.ctx.Err()
+}
+
+// contextReaderAt checks the context of reads before reading from the
+// underlying io.ReaderAt, so cancelled reads do not fetch more data.
+type contextReaderAt struct {
+	reader  io.ReaderAt
+	context *readContext
+}
+
+func (r *contextReaderAt) ReadAt(b []byte, off int64) (int, error) {
+	if err := r.context.err(); err != nil {
+		return 0, err
+	}
+	return r.reader.ReadAt(b, off)
 }
 
 // NewReader constructs a parquet reader reading rows from the given
@@ -57,38 +96,66 @@ type Reader struct {
 //	}
 //
 func NewReader(input io.ReaderAt, options ...ReaderOption) *Reader {
+	c, err := NewReaderConfig(options...)
+	if err != nil {
+		panic(err)
+	}
+
 	f, _ := input.(*File)
 	if f == nil {
 		n, err := sizeOf(input)
 		if err != nil {
 			panic(err)
 		}
-		if f, err = OpenFile(input, n); err != nil {
+		options := []FileOption{StrictValidation(c.StrictValidation)}
+		if c.Observer != nil {
+			options = append(options, ObserveReader(c.Observer))
+		}
+		if c.MemoryLimit != 0 {
+			options = append(options, MemoryLimit(c.MemoryLimit))
+		}
+		if c.ZstdDictionaries != nil {
+			options = append(options, ZstdDictionaries(c.ZstdDictionaries...))
+		}
+		if c.CompressionCodecResolver != nil {
+			options = append(options, ResolveCompressionCodecs(c.CompressionCodecResolver))
+		}
+		if c.SkipCorrupted != nil {
+			options = append(options, SkipCorrupted(c.SkipCorrupted))
+		}
+		if f, err = OpenFile(input, n, options...); err != nil {
 			panic(err)
 		}
-	}
-
-	c, err := NewReaderConfig(options...)
-	if err != nil {
-		panic(err)
+		// Corrupted page indexes and bloom filters were discarded when opening
+		// the file, but pages must not be skipped individually since the values
+		// of columns would not be aligned anymore; the reader skips whole row
+		// groups instead.
+		f.skipCorrupted = nil
 	}
 
 	column := f.Root()
 	schema := NewSchema(column.Name(), column)
 
 	r := &Reader{
-		file: reader{schema: schema},
+		file:          reader{schema: schema},
+		skipCorrupted: c.SkipCorrupted,
+		rowGroupRows:  make([]int64, f.NumRowGroups()),
+		observer:      c.Observer,
+	}
+
+	for i := range r.rowGroupRows {
+		r.rowGroupRows[i] = f.RowGroup(i).NumRows()
 	}
 
 	switch n := f.NumRowGroups(); n {
 	case 0:
 		r.file.rowGroup = newEmptyRowGroup(schema)
 	case 1:
-		r.file.rowGroup = f.RowGroup(0)
+		r.file.rowGroup = f.rowGroups[0].withContext(&r.context)
 	default:
 		rowGroups := make([]RowGroup, n)
 		for i := range rowGroups {
-			rowGroups[i] = f.RowGroup(i)
+			rowGroups[i] = f.rowGroups[i].withContext(&r.context)
 		}
 		// TODO: should we attempt to merge the row groups via MergeRowGroups
 		// to preserve the global order of sorting columns within the file?
@@ -121,6 +188,9 @@ func NewRowGroupReader(rowGroup RowGroup, options ...ReaderOption) *Reader {
 			schema:   rowGroup.Schema(),
 			rowGroup: rowGroup,
 		},
+		skipCorrupted: c.SkipCorrupted,
+		rowGroupRows:  []int64{rowGroup.NumRows()},
+		observer:      c.Observer,
 	}
 
 	r.read.init(r.file.schema, r.file.rowGroup)
@@ -172,6 +242,10 @@ func (r *Reader) Reset() {
 // Read reads the next row from r. The type of the row must match the schema
 // of the underlying parquet file or an error will be returned.
 //
+// String values of dictionary encoded columns are not copied for each row, they
+// share the memory of the column chunk dictionary; as long as one of the values
+// is referenced, the dictionary is retained in memory.
+//
 // The method returns io.EOF when no more rows can be read from r.
 func (r *Reader) Read(row interface{}) (err error) {
 	if rowType := dereference(reflect.TypeOf(row)); rowType.Kind() == reflect.Struct {
@@ -181,20 +255,98 @@ func (r *Reader) Read(row interface{}) (err error) {
 			}
 		}
 	}
+	return r.readRowValue(row)
+}
 
-	if err := r.read.SeekToRow(r.rowIndex); err != nil {
-		return err
+// ReadRows reads rows from r into dst, which must be a slice of Go values (or
+// a pointer to a slice), typically structs or pointers to structs. The method
+// fills up to len(dst) rows, allocating the values of nil pointers, and returns
+// the number of rows read.
+//
+// When dst is a pointer to a slice, the slice is truncated to the number of
+// rows read when the method returns.
+//
+// This is similar to calling Read for each element of the slice, but the cost
+// of validating the Go type of rows is paid once per call instead of once per
+// row.
+//
+// Like io.Reader, the method may return n > 0 with a non-nil error when the
+// end of the file was reached or reading a row failed. It returns io.EOF when
+// no more rows can be read from r.
+func (r *Reader) ReadRows(dst interface{}) (n int, err error) {
+	rows := reflect.ValueOf(dst)
+	if rows.Kind() == reflect.Ptr && rows.Elem().Kind() == reflect.Slice {
+		rows = rows.Elem()
+		defer func() { rows.Set(rows.Slice(0, n)) }()
+	}
+	if rows.Kind() != reflect.Slice {
+		return 0, fmt.Errorf("cannot read parquet rows into go value of type %T: not a slice", dst)
 	}
 
-	r.values, err = r.read.ReadRow(r.values[:0])
-	if err != nil {
-		return err
+	if rowType := dereference(rows.Type().Elem()); rowType.Kind() == reflect.Struct {
+		if r.seen != rowType {
+			if err := r.updateReadSchema(rowType); err != nil {
+				return 0, fmt.Errorf("cannot read parquet rows into go value of type %T: %w", dst, err)
+			}
+		}
+	}
+
+	for n < rows.Len() {
+		row := rows.Index(n)
+		if row.Kind() == reflect.Ptr {
+			if row.IsNil() {
+				row.Set(reflect.New(row.Type().Elem()))
+			}
+		} else {
+			row = row.Addr()
+		}
+		if err = r.readRowValue(row.Interface()); err != nil {
+			break
+		}
+		n++
+	}
+	return n, err
+}
+
+func (r *Reader) readRowValue(row interface{}) (err error) {
+	for {
+		if err = r.read.SeekToRow(r.rowIndex); err == nil {
+			r.values, err = r.read.ReadRow(r.values[:0])
+			if err == nil {
+				break
+			}
+		}
+		if !r.skipCorruptedRowGroup(err) {
+			return err
+		}
 	}
 
 	r.rowIndex++
 	return r.read.schema.Reconstruct(row, r.values)
 }
 
+// ReadContext is like Read but returns the context error if the context is
+// cancelled before the row was read, allowing long scans to be aborted.
+//
+// When the reader was created from a parquet file, the context is checked
+// before reading each page of the column chunks, including the pages skipped
+// when seeking, and before each read from the underlying io.ReaderAt; reads in
+// progress on the io.ReaderAt and the decoding of the current page are not
+// interrupted. The reader remains positioned on the row that could not be
+// read, which is read again by the next call.
+func (r *Reader) ReadContext(ctx context.Context, row interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.context.ctx = ctx
+	defer func() { r.context.ctx = nil }()
+	err := r.Read(row)
+	if err != nil && ctx.Err() != nil {
+		r.resetRows()
+	}
+	return err
+}
+
 func (r *Reader) updateReadSchema(rowType reflect.Type) error {
 	schema := schemaOf(rowType)
 
@@ -219,16 +371,84 @@ func (r *Reader) updateReadSchema(rowType reflect.Type) error {
 //
 // The method returns io.EOF when no more rows can be read from r.
 func (r *Reader) ReadRow(row Row) (Row, error) {
-	if err := r.file.SeekToRow(r.rowIndex); err != nil {
+	n := len(row)
+	for {
+		err := r.file.SeekToRow(r.rowIndex)
+		if err == nil {
+			row, err = r.file.ReadRow(row)
+			if err == nil {
+				r.rowIndex++
+				return row, nil
+			}
+		}
+		if !r.skipCorruptedRowGroup(err) {
+			return row, err
+		}
+		row = row[:n]
+	}
+}
+
+// ReadRowContext is like ReadRow but returns the context error if the context
+// is cancelled before the row was read, with the same semantics as ReadContext.
+func (r *Reader) ReadRowContext(ctx context.Context, row Row) (Row, error) {
+	if err := ctx.Err(); err != nil {
 		return row, err
 	}
-	row, err := r.file.ReadRow(row)
-	if err == nil {
-		r.rowIndex++
+	r.context.ctx = ctx
+	defer func() { r.context.ctx = nil }()
+	n := len(row)
+	row, err := r.ReadRow(row)
+	if err != nil && ctx.Err() != nil {
+		r.resetRows()
+		row = row[:n]
 	}
 	return row, err
 }
 
+// resetRows discards the state of the column readers, which is unknown after
+// an error, so the rows are recreated and seeked to the current row index when
+// the next row is read.
+func (r *Reader) resetRows() {
+	r.file.Reset()
+	r.read.Reset()
+}
+
+// skipCorruptedRowGroup is called when reading the current row failed with err.
+// If the reader was configured to skip corrupted data, the error is reported
+// and the reader is positioned on the first row of the next row group, and the
+// method returns true to indicate that the read should be retried.
+func (r *Reader) skipCorruptedRowGroup(err error) bool {
+	if r.skipCorrupted == nil || err == io.EOF || errors.Is(err, ErrMemoryLimitExceeded) || r.context.err() != nil {
+		return false
+	}
+
+	rowGroup, endOfRowGroup := 0, int64(0)
+	for rowGroup < len(r.rowGroupRows) {
+		endOfRowGroup += r.rowGroupRows[rowGroup]
+		if r.rowIndex < endOfRowGroup {
+			break
+		}
+		rowGroup++
+	}
+	if rowGroup == len(r.rowGroupRows) {
+		return false
+	}
+
+	r.skipCorrupted(&CorruptedDataError{
+		RowGroup: rowGroup,
+		Column:   -1,
+		Page:     -1,
+		Err:      err,
+	})
+	if r.observer != nil && r.observer.OnRowGroupSkipped != nil {
+		r.observer.OnRowGroupSkipped(rowGroup, endOfRowGroup-r.rowIndex)
+	}
+
+	r.resetRows()
+	r.rowIndex = endOfRowGroup
+	return true
+}
+
 // Schema returns the schema of rows read by r.
 func (r *Reader) Schema() *Schema { return r.file.schema }
 
@@ -2,6 +2,8 @@ package parquet_test
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"math"
@@ -9,10 +11,15 @@ import (
 	"reflect"
 	"testing"
 	"testing/quick"
+	"time"
+	"unsafe"
 
 	"github.com/google/uuid"
 	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress"
+	"github.com/segmentio/parquet-go/compress/gzip"
 	"github.com/segmentio/parquet-go/deprecated"
+	"github.com/segmentio/parquet-go/format"
 )
 
 type booleanColumn struct {
@@ -248,6 +255,34 @@ var readerTests = []struct {
 			MapOfRepeated map[utf8string][]utf8string
 		}{},
 	},
+
+	{
+		scenario: "lists of lists",
+		model: struct {
+			ListsOfLists [][]utf8string
+		}{},
+	},
+
+	{
+		scenario: "lists of lists of lists",
+		model: struct {
+			ListsOfListsOfLists [][][]int32
+		}{},
+	},
+
+	{
+		scenario: "repeated maps of repeated values",
+		model: struct {
+			RepeatedMapsOfRepeated []map[utf8string][]utf8string
+		}{},
+	},
+
+	{
+		scenario: "maps of maps",
+		model: struct {
+			MapsOfMaps map[utf8string]map[utf8string]int64
+		}{},
+	},
 }
 
 func TestReader(t *testing.T) {
@@ -407,3 +442,767 @@ func TestReaderSeekToRow(t *testing.T) {
 		}
 	}
 }
+
+func TestReaderInternDictionaryStrings(t *testing.T) {
+	type Row struct {
+		Name  string `parquet:"name,dict"`
+		Plain string `parquet:"plain"`
+	}
+
+	const numRows = 1000
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	for i := 0; i < numRows; i++ {
+		name := fmt.Sprintf("name-%d", i%10)
+		if err := writer.Write(&Row{Name: name, Plain: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	stringData := func(s string) uintptr {
+		return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	names := make(map[string]uintptr)
+
+	for i := 0; i < numRows; i++ {
+		var row Row
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("name-%d", i%10); row.Name != want || row.Plain != want {
+			t.Fatalf("row %d mismatch: %+v", i, row)
+		}
+		if data, ok := names[row.Name]; !ok {
+			names[row.Name] = stringData(row.Name)
+		} else if data != stringData(row.Name) {
+			t.Fatalf("value of row %d was not interned: %q", i, row.Name)
+		}
+	}
+}
+
+func TestReaderSkipCorrupted(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	const numRowGroups, rowsPerRowGroup = 3, 10
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer,
+		parquet.MaxRowsPerRowGroup(rowsPerRowGroup),
+		parquet.DataPageMaxValues(rowsPerRowGroup/2),
+	)
+	for i := 0; i < numRowGroups*rowsPerRowGroup; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Name: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(f.RowGroups()); n != numRowGroups {
+		t.Fatalf("wrong number of row groups: want=%d got=%d", numRowGroups, n)
+	}
+
+	// Flip the last byte of the first page of the "id" column in the second
+	// row group, which causes a checksum mismatch when reading the page.
+	page := f.RowGroups()[1].Column(0).OffsetIndex()
+	data := append([]byte{}, buffer.Bytes()...)
+	data[page.Offset(0)+page.CompressedPageSize(0)-1] ^= 0xFF
+
+	t.Run("reader", func(t *testing.T) {
+		reader := parquet.NewReader(bytes.NewReader(data))
+		for {
+			row := Row{}
+			if err := reader.Read(&row); err != nil {
+				if !errors.Is(err, parquet.ErrCorrupted) {
+					t.Fatalf("reading rows did not report the corruption: %v", err)
+				}
+				break
+			}
+		}
+
+		var reports []error
+		var skipped []string
+		reader = parquet.NewReader(bytes.NewReader(data),
+			parquet.SkipCorrupted(func(err error) {
+				reports = append(reports, err)
+			}),
+			parquet.ObserveReader(&parquet.ReaderObserver{
+				OnRowGroupSkipped: func(rowGroup int, numRows int64) {
+					skipped = append(skipped, fmt.Sprintf("%d:%d", rowGroup, numRows))
+				},
+			}),
+		)
+
+		var ids []int64
+		for {
+			row := Row{}
+			if err := reader.Read(&row); err != nil {
+				if err != io.EOF {
+					t.Fatal(err)
+				}
+				break
+			}
+			ids = append(ids, row.ID)
+		}
+
+		if len(ids) != 2*rowsPerRowGroup || ids[rowsPerRowGroup-1] != rowsPerRowGroup-1 || ids[rowsPerRowGroup] != 2*rowsPerRowGroup {
+			t.Errorf("wrong rows read from the file: %v", ids)
+		}
+
+		if len(reports) != 1 {
+			t.Fatalf("wrong number of errors reported: want=1 got=%d (%v)", len(reports), reports)
+		}
+		e := new(parquet.CorruptedDataError)
+		if !errors.As(reports[0], &e) || !errors.Is(e, parquet.ErrCorrupted) {
+			t.Fatalf("wrong error reported: %v", reports[0])
+		}
+		if e.RowGroup != 1 || e.Column != -1 || e.Page != -1 {
+			t.Errorf("wrong location of skipped data: row group=%d column=%d page=%d", e.RowGroup, e.Column, e.Page)
+		}
+		if want := []string{fmt.Sprintf("1:%d", rowsPerRowGroup)}; !reflect.DeepEqual(skipped, want) {
+			t.Errorf("wrong row groups skipped: want=%v got=%v", want, skipped)
+		}
+	})
+
+	t.Run("pages", func(t *testing.T) {
+		var reports []error
+		f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)), parquet.SkipCorrupted(func(err error) {
+			reports = append(reports, err)
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var values []parquet.Value
+		pages := f.RowGroups()[1].Column(0).Pages()
+		for {
+			p, err := pages.ReadPage()
+			if err != nil {
+				if err != io.EOF {
+					t.Fatal(err)
+				}
+				break
+			}
+			v := make([]parquet.Value, p.NumValues())
+			if _, err := p.Values().ReadValues(v); err != nil && err != io.EOF {
+				t.Fatal(err)
+			}
+			values = append(values, v...)
+		}
+
+		if len(values) != rowsPerRowGroup/2 || values[0].Int64() != rowsPerRowGroup+rowsPerRowGroup/2 {
+			t.Errorf("wrong values read from the column chunk: %v", values)
+		}
+
+		if len(reports) != 1 {
+			t.Fatalf("wrong number of errors reported: want=1 got=%d (%v)", len(reports), reports)
+		}
+		e := new(parquet.CorruptedDataError)
+		if !errors.As(reports[0], &e) || !errors.Is(e, parquet.ErrCorrupted) {
+			t.Fatalf("wrong error reported: %v", reports[0])
+		}
+		if e.RowGroup != 1 || e.Column != 0 || e.Page != 0 {
+			t.Errorf("wrong location of skipped data: row group=%d column=%d page=%d", e.RowGroup, e.Column, e.Page)
+		}
+	})
+
+	t.Run("page index", func(t *testing.T) {
+		// Overwrite the column index of the first column chunk, which cannot
+		// be decoded anymore when the file is opened.
+		chunk := &f.Metadata().RowGroups[0].Columns[0]
+		if chunk.ColumnIndexOffset == 0 || chunk.ColumnIndexLength == 0 {
+			t.Fatal("the first column chunk has no column index")
+		}
+		data := append([]byte{}, buffer.Bytes()...)
+		for i := chunk.ColumnIndexOffset; i < chunk.ColumnIndexOffset+int64(chunk.ColumnIndexLength); i++ {
+			data[i] = 0xFF
+		}
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("opening a file with a corrupted page index did not fail")
+				}
+			}()
+			parquet.NewReader(bytes.NewReader(data))
+		}()
+
+		var reports []error
+		reader := parquet.NewReader(bytes.NewReader(data), parquet.SkipCorrupted(func(err error) {
+			reports = append(reports, err)
+		}))
+
+		n := 0
+		for {
+			row := Row{}
+			if err := reader.Read(&row); err != nil {
+				if err != io.EOF {
+					t.Fatal(err)
+				}
+				break
+			}
+			if row.ID != int64(n) {
+				t.Fatalf("wrong row at index %d: %+v", n, row)
+			}
+			n++
+		}
+		if n != numRowGroups*rowsPerRowGroup {
+			t.Errorf("wrong number of rows: want=%d got=%d", numRowGroups*rowsPerRowGroup, n)
+		}
+
+		if len(reports) != 1 {
+			t.Fatalf("wrong number of errors reported: want=1 got=%d (%v)", len(reports), reports)
+		}
+		e := new(parquet.CorruptedDataError)
+		if !errors.As(reports[0], &e) || e.RowGroup != -1 {
+			t.Errorf("wrong error reported: %v", reports[0])
+		}
+	})
+}
+
+func TestReaderMemoryLimit(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	const numRowGroups, rowsPerRowGroup = 3, 10
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.MaxRowsPerRowGroup(rowsPerRowGroup))
+	for i := 0; i < numRowGroups*rowsPerRowGroup; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Name: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("within limit", func(t *testing.T) {
+		// Readers release the memory of row groups when they move to the next
+		// one, so the limit only needs to fit the pages of a single row group.
+		reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()), parquet.MemoryLimit(512))
+		n := 0
+		for {
+			row := Row{}
+			if err := reader.Read(&row); err != nil {
+				if err != io.EOF {
+					t.Fatal(err)
+				}
+				break
+			}
+			n++
+		}
+		if n != numRowGroups*rowsPerRowGroup {
+			t.Errorf("wrong number of rows: want=%d got=%d", numRowGroups*rowsPerRowGroup, n)
+		}
+	})
+
+	t.Run("limit exceeded", func(t *testing.T) {
+		var reports []error
+		reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()),
+			parquet.MemoryLimit(16),
+			parquet.SkipCorrupted(func(err error) { reports = append(reports, err) }),
+		)
+
+		err := reader.Read(new(Row))
+		if !errors.Is(err, parquet.ErrMemoryLimitExceeded) {
+			t.Fatalf("reading rows did not report exceeding the memory limit: %v", err)
+		}
+		var limitErr *parquet.MemoryLimitError
+		if !errors.As(err, &limitErr) {
+			t.Fatalf("wrong error type: %T", err)
+		}
+		if limitErr.Limit != 16 || limitErr.RowGroup != 0 || limitErr.Size <= 16 {
+			t.Errorf("wrong error: %+v", limitErr)
+		}
+		if len(reports) != 0 {
+			t.Errorf("memory limit errors must not be reported as corrupted data: %v", reports)
+		}
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		_, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()), parquet.MemoryLimit(-1))
+		if err == nil {
+			t.Fatal("opening a file with a negative memory limit did not fail")
+		}
+	})
+}
+
+// proprietaryCodec is a compression codec identified by a value which is not
+// defined by the parquet format.
+type proprietaryCodec struct{ gzip.Codec }
+
+const proprietaryCompressionCodec format.CompressionCodec = 100
+
+func (c *proprietaryCodec) String() string { return "PROPRIETARY" }
+
+func (c *proprietaryCodec) CompressionCodec() format.CompressionCodec {
+	return proprietaryCompressionCodec
+}
+
+func TestReaderResolveCompressionCodecs(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	const numRows = 100
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.Compression(&proprietaryCodec{}))
+	for i := 0; i < numRows; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Name: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("resolved", func(t *testing.T) {
+		resolved := make(map[format.CompressionCodec]int)
+		reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()),
+			parquet.ResolveCompressionCodecs(func(codec format.CompressionCodec) compress.Codec {
+				resolved[codec]++
+				if codec == proprietaryCompressionCodec {
+					return &proprietaryCodec{}
+				}
+				return nil
+			}),
+		)
+
+		for i := 0; i < numRows; i++ {
+			row := Row{}
+			if err := reader.Read(&row); err != nil {
+				t.Fatalf("reading row %d: %v", i, err)
+			}
+			if row.ID != int64(i) || row.Name != fmt.Sprint(i) {
+				t.Fatalf("wrong row %d: %+v", i, row)
+			}
+		}
+		if err := reader.Read(new(Row)); err != io.EOF {
+			t.Errorf("expected io.EOF after the last row: %v", err)
+		}
+		if len(resolved) != 1 || resolved[proprietaryCompressionCodec] != 1 {
+			t.Errorf("codecs must be resolved once: %v", resolved)
+		}
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()),
+			parquet.ResolveCompressionCodecs(func(format.CompressionCodec) compress.Codec { return &proprietaryCodec{} }),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The columns of a file may be read from multiple goroutines, which
+		// must not race on the codecs resolved by the file.
+		rowGroup := f.RowGroups()[0]
+		errs := make(chan error, rowGroup.NumColumns())
+		for i := 0; i < rowGroup.NumColumns(); i++ {
+			go func(column parquet.ColumnChunk) {
+				pages := column.Pages()
+				defer pages.(io.Closer).Close()
+				for {
+					p, err := pages.ReadPage()
+					if err != nil {
+						if err == io.EOF {
+							err = nil
+						}
+						errs <- err
+						return
+					}
+					if _, err := p.Values().ReadValues(make([]parquet.Value, p.NumValues())); err != nil && err != io.EOF {
+						errs <- err
+						return
+					}
+				}
+			}(rowGroup.Column(i))
+		}
+		for i := 0; i < rowGroup.NumColumns(); i++ {
+			if err := <-errs; err != nil {
+				t.Error(err)
+			}
+		}
+	})
+
+	t.Run("unresolved", func(t *testing.T) {
+		reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()),
+			parquet.ResolveCompressionCodecs(func(format.CompressionCodec) compress.Codec { return nil }),
+		)
+		if err := reader.Read(new(Row)); err == nil {
+			t.Error("reading pages compressed with an unknown codec did not fail")
+		}
+	})
+}
+
+func TestReaderObserver(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	const numRows, pageSize = 100, 10
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer,
+		parquet.Compression(&parquet.Snappy),
+		parquet.DataPageMaxValues(pageSize),
+	)
+	for i := 0; i < numRows; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Name: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		bytesRead    int64
+		pages        = make(map[string]int)
+		skipped      = make(map[string]int)
+		decompressed int64
+	)
+	observer := &parquet.ReaderObserver{
+		OnRead: func(n int64) { bytesRead += n },
+		OnPage: func(stats parquet.ReaderPageStats) {
+			if stats.RowGroup != 0 {
+				t.Errorf("wrong row group index: want=0 got=%d", stats.RowGroup)
+			}
+			if stats.NumValues != pageSize {
+				t.Errorf("wrong number of values in page: want=%d got=%d", pageSize, stats.NumValues)
+			}
+			pages[stats.Path[0]]++
+		},
+		OnPagesSkipped: func(path []string, numPages int) {
+			skipped[path[0]] += numPages
+		},
+		OnDecompress: func(codec format.CompressionCodec, n int64, duration time.Duration) {
+			if codec != format.Snappy {
+				t.Errorf("wrong compression codec: want=%s got=%s", format.Snappy, codec)
+			}
+			decompressed += n
+		},
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()), parquet.ObserveReader(observer))
+	if bytesRead == 0 {
+		t.Error("no bytes were reported read after opening the file")
+	}
+	if err := reader.SeekToRow(numRows - pageSize/2); err != nil {
+		t.Fatal(err)
+	}
+
+	n := 0
+	for {
+		row := Row{}
+		if err := reader.Read(&row); err != nil {
+			if err != io.EOF {
+				t.Fatal(err)
+			}
+			break
+		}
+		n++
+	}
+	if n != pageSize/2 {
+		t.Errorf("wrong number of rows read: want=%d got=%d", pageSize/2, n)
+	}
+
+	for _, column := range []string{"id", "name"} {
+		if pages[column] != 1 {
+			t.Errorf("wrong number of pages read from column %q: want=1 got=%d", column, pages[column])
+		}
+		if skipped[column] != numRows/pageSize-1 {
+			t.Errorf("wrong number of pages skipped in column %q: want=%d got=%d", column, numRows/pageSize-1, skipped[column])
+		}
+	}
+	if decompressed == 0 {
+		t.Error("no decompressed bytes were reported")
+	}
+	if size := int64(buffer.Len()); bytesRead >= size {
+		t.Errorf("seeking with the offset index should avoid reading the whole file: %d >= %d", bytesRead, size)
+	}
+}
+
+func TestReaderReadContext(t *testing.T) {
+	type Row struct {
+		ID int64 `parquet:"id"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	for i := 0; i < 10; i++ {
+		if err := writer.Write(&Row{ID: int64(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	for i := 0; i < 5; i++ {
+		row := Row{}
+		if err := reader.ReadContext(ctx, &row); err != nil {
+			t.Fatal(err)
+		}
+		if row.ID != int64(i) {
+			t.Fatalf("wrong row at index %d: got=%d", i, row.ID)
+		}
+	}
+
+	cancel()
+
+	if err := reader.ReadContext(ctx, &Row{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("reading with a cancelled context: want=%v got=%v", context.Canceled, err)
+	}
+	if _, err := reader.ReadRowContext(ctx, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("reading with a cancelled context: want=%v got=%v", context.Canceled, err)
+	}
+
+	// The reader was not advanced by the cancelled reads.
+	row, err := reader.ReadRowContext(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := row[0].Int64(); id != 5 {
+		t.Errorf("wrong row after cancellation: want=5 got=%d", id)
+	}
+}
+
+// cancelReaderAt cancels a context when data is first read from the file.
+type cancelReaderAt struct {
+	*bytes.Reader
+	cancel func()
+}
+
+func (r *cancelReaderAt) ReadAt(b []byte, off int64) (int, error) {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	return r.Reader.ReadAt(b, off)
+}
+
+func TestReaderReadContextCancelPages(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	for i := 0; i < 10; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Name: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	input := &cancelReaderAt{Reader: bytes.NewReader(buffer.Bytes())}
+	reader := parquet.NewReader(input, parquet.SkipCorrupted(func(err error) {
+		t.Errorf("cancellation reported as corrupted data: %v", err)
+	}))
+
+	// The context is cancelled when the pages of the first column are read,
+	// the pages of the second column must not be read anymore.
+	ctx, cancel := context.WithCancel(context.Background())
+	input.cancel = cancel
+
+	if err := reader.ReadContext(ctx, &Row{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("reading pages with a cancelled context: want=%v got=%v", context.Canceled, err)
+	}
+
+	input.cancel = nil
+	row := Row{}
+	if err := reader.Read(&row); err != nil {
+		t.Fatal(err)
+	}
+	if row.ID != 0 || row.Name != "0" {
+		t.Errorf("wrong row after cancellation: %+v", row)
+	}
+}
+
+func TestReaderReadRows(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	const numRows = 25
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	for i := 0; i < numRows; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Name: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("slice", func(t *testing.T) {
+		reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+		rows := make([]Row, 10)
+		var all []Row
+		for {
+			n, err := reader.ReadRows(rows)
+			all = append(all, rows[:n]...)
+			if err != nil {
+				if err != io.EOF {
+					t.Fatal(err)
+				}
+				break
+			}
+		}
+		if len(all) != numRows {
+			t.Fatalf("wrong number of rows: want=%d got=%d", numRows, len(all))
+		}
+		for i, row := range all {
+			if row.ID != int64(i) || row.Name != fmt.Sprint(i) {
+				t.Fatalf("wrong row at index %d: %+v", i, row)
+			}
+		}
+	})
+
+	t.Run("pointer to slice of pointers", func(t *testing.T) {
+		reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+		if err := reader.SeekToRow(20); err != nil {
+			t.Fatal(err)
+		}
+		rows := make([]*Row, 10)
+		n, err := reader.ReadRows(&rows)
+		if err != nil && err != io.EOF {
+			t.Fatal(err)
+		}
+		if n != 5 || len(rows) != 5 {
+			t.Fatalf("wrong number of rows: want=5 got=%d (len=%d)", n, len(rows))
+		}
+		for i, row := range rows {
+			if row.ID != int64(20+i) {
+				t.Fatalf("wrong row at index %d: %+v", i, row)
+			}
+		}
+	})
+
+	t.Run("not a slice", func(t *testing.T) {
+		reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+		if _, err := reader.ReadRows(&Row{}); err == nil {
+			t.Fatal("expected an error when reading rows into a value which is not a slice")
+		}
+	})
+}
+
+func TestReaderLegacyLists(t *testing.T) {
+	type strings struct {
+		Tags []string `parquet:"tags,list"`
+	}
+	type item struct {
+		Str string `parquet:"str"`
+	}
+	type items struct {
+		Tags []item `parquet:"tags,list"`
+	}
+	type pair struct {
+		A int64  `parquet:"a"`
+		B string `parquet:"b"`
+	}
+	type pairs struct {
+		Tags []pair `parquet:"tags,list"`
+	}
+
+	legacyList := func(name string, repeated parquet.Node) parquet.Node {
+		return parquet.Group{
+			"tags": annotatedGroup{
+				Group: parquet.Group{name: parquet.Repeated(repeated)},
+				typ:   parquet.List(parquet.String()).Type(),
+			},
+		}
+	}
+
+	tests := []struct {
+		scenario string
+		node     parquet.Node
+		value    interface{}
+	}{
+		{
+			scenario: "two-level list of primitive values",
+			node:     legacyList("array", parquet.String()),
+			value:    &strings{Tags: []string{"a", "b", "c"}},
+		},
+
+		{
+			scenario: "repeated group named array",
+			node:     legacyList("array", parquet.Group{"str": parquet.String()}),
+			value:    &items{Tags: []item{{Str: "a"}, {Str: "b"}}},
+		},
+
+		{
+			scenario: "repeated group named after the list",
+			node:     legacyList("tags_tuple", parquet.Group{"str": parquet.String()}),
+			value:    &items{Tags: []item{{Str: "a"}, {Str: "b"}}},
+		},
+
+		{
+			scenario: "repeated group with multiple columns",
+			node: legacyList("pair", parquet.Group{
+				"a": parquet.Leaf(parquet.Int64Type),
+				"b": parquet.String(),
+			}),
+			value: &pairs{Tags: []pair{{A: 1, B: "a"}, {A: 2, B: "b"}}},
+		},
+
+		{
+			scenario: "three-level list with non-standard names",
+			node:     legacyList("bag", parquet.Group{"array_element": parquet.String()}),
+			value:    &strings{Tags: []string{"a", "b", "c"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			// The rows of the Go values have the same levels and columns as
+			// rows of the legacy layouts, since list elements are required.
+			schema := parquet.SchemaOf(test.value)
+			buffer := new(bytes.Buffer)
+			writer := parquet.NewWriter(buffer, parquet.NewSchema("legacy", test.node))
+			if err := writer.WriteRow(schema.Deconstruct(nil, test.value)); err != nil {
+				t.Fatal(err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+			value := reflect.New(reflect.TypeOf(test.value).Elem())
+			if err := reader.Read(value.Interface()); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(value.Interface(), test.value) {
+				t.Errorf("wrong value read from legacy list:\nwant = %+v\ngot  = %+v", test.value, value.Interface())
+			}
+		})
+	}
+}
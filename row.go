@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 // Row represents a parquet row as a slice of values.
@@ -37,6 +41,82 @@ func (row Row) Equal(other Row) bool {
 	return true
 }
 
+// CompareRows compares the rows a and b of the given schema, returning a
+// negative value if a orders before b, a positive value if it orders after,
+// and zero if both rows are equivalent.
+//
+// The rows are compared by the values of the columns listed in sortColumns,
+// applying the direction and placement of null values that each of them
+// declares. Repeated columns are compared element by element, and rows with
+// fewer elements order first when all their elements are equal. Sorting
+// columns which do not match a leaf column of the schema are ignored. When
+// sortColumns is empty, the rows are compared by all their leaf columns in
+// ascending order.
+//
+// Values of the two rows must be ordered by column index, which is the case of
+// rows produced by the schema or read from parquet files.
+func CompareRows(schema *Schema, a, b Row, sortColumns []SortingColumn) int {
+	if len(sortColumns) == 0 {
+		sortColumns = nil
+		forEachLeafColumnOf(schema, func(leaf leafColumn) {
+			sortColumns = append(sortColumns, Ascending(leaf.path...))
+		})
+	}
+
+	for _, sorting := range columnSortFuncsOf(schema, sortColumns) {
+		values1 := a.columnValuesOf(sorting.columnIndex)
+		values2 := b.columnValuesOf(sorting.columnIndex)
+
+		var cmp int
+		if len(values1) == 0 || len(values2) == 0 {
+			// Rows missing values of a column cannot be passed to the sort
+			// functions, which expect at least one value (possibly null).
+			cmp = len(values1) - len(values2)
+		} else {
+			cmp = sorting.compare(values1, values2)
+		}
+		if cmp != 0 {
+			return cmp
+		}
+	}
+
+	return 0
+}
+
+// columnValuesOf returns the values of the column at the given index in row.
+func (row Row) columnValuesOf(columnIndex int16) []Value {
+	i := 0
+	for i < len(row) && row[i].Column() != int(columnIndex) {
+		i++
+	}
+	j := i
+	for j < len(row) && row[j].Column() == int(columnIndex) {
+		j++
+	}
+	return row[i:j]
+}
+
+// String returns a compact representation of the row, listing the column
+// index, definition level, repetition level, kind, and value of each of its
+// values, for example:
+//
+//	[{C:0 D:0 R:0 K:INT64 V:1} {C:1 D:1 R:0 K:BYTE_ARRAY V:"Luke"}]
+//
+// PrintRow may be used to also show the column paths and logical types of the
+// values.
+func (row Row) String() string {
+	s := new(strings.Builder)
+	s.WriteString("[")
+	for i, v := range row {
+		if i != 0 {
+			s.WriteString(" ")
+		}
+		fmt.Fprintf(s, "{%+v}", v)
+	}
+	s.WriteString("]")
+	return s.String()
+}
+
 func (row Row) startsWith(columnIndex int16) bool {
 	return len(row) > 0 && row[0].Column() == int(columnIndex)
 }
@@ -127,6 +207,23 @@ func (r *forwardRowSeeker) SeekToRow(rowIndex int64) error {
 	return fmt.Errorf("SeekToRow: %T does not implement parquet.RowSeeker: cannot seek backward from row %d to %d", r.rows, r.index, rowIndex)
 }
 
+// limitRowReader reads at most limit rows from an underlying row reader.
+type limitRowReader struct {
+	rows  RowReader
+	limit int64
+}
+
+func (r *limitRowReader) ReadRow(row Row) (Row, error) {
+	if r.limit <= 0 {
+		return row, io.EOF
+	}
+	row, err := r.rows.ReadRow(row)
+	if err == nil {
+		r.limit--
+	}
+	return row, err
+}
+
 // CopyRows copies rows from src to dst.
 //
 // The underlying types of src and dst are tested to determine if they expose
@@ -222,6 +319,10 @@ func errRowHasTooFewValues(numValues int64) error {
 	return fmt.Errorf("row has too few values to be written to the column: %d", numValues)
 }
 
+func errValuesStartInTheMiddleOfRow(repetitionLevel int16) error {
+	return fmt.Errorf("values written to an empty repeated column start in the middle of a row: repetition level %d", repetitionLevel)
+}
+
 func errRowHasTooManyValues(numValues int64) error {
 	return fmt.Errorf("row has too many values to be written to the column: %d", numValues)
 }
@@ -263,8 +364,15 @@ func limitRowValues(values []Value, rowCount int) []Value {
 	} else {
 		var row Row
 		var limit int
-		for len(values) > 0 {
-			row, values = splitRowValues(values)
+		tail := values
+		// The values may start with the continuation of a row, which is not
+		// counted since the row was started in a previous sequence of values.
+		if tail[0].repetitionLevel != 0 {
+			row, tail = splitRowValues(tail)
+			limit += len(row)
+		}
+		for ; rowCount > 0 && len(tail) > 0; rowCount-- {
+			row, tail = splitRowValues(tail)
 			limit += len(row)
 		}
 		values = values[:limit]
@@ -272,10 +380,13 @@ func limitRowValues(values []Value, rowCount int) []Value {
 	return values
 }
 
+// splitRowValues splits values before the start of the second row, returning
+// the values of the first row (or the continuation of a row if the values do
+// not start with a zero repetition level) and the remaining values.
 func splitRowValues(values []Value) (head, tail []Value) {
-	for i, v := range values {
-		if v.repetitionLevel == 0 {
-			return values[:i+1], values[i+1:]
+	for i := 1; i < len(values); i++ {
+		if values[i].repetitionLevel == 0 {
+			return values[:i], values[i:]
 		}
 	}
 	return values, nil
@@ -304,9 +415,9 @@ func forEachRepeatedRowOf(values []Value, do func(Row) error) error {
 // =============================================================================
 
 type levels struct {
-	repetitionDepth int8
-	repetitionLevel int8
-	definitionLevel int8
+	repetitionDepth int16
+	repetitionLevel int16
+	definitionLevel int16
 }
 
 type deconstructFunc func(Row, levels, reflect.Value) Row
@@ -398,7 +509,11 @@ func deconstructFuncOfMap(columnIndex int16, node Node) (int16, deconstructFunc)
 		v := elem.Field(1)
 
 		for _, key := range mapValue.MapKeys() {
-			k.Set(key.Convert(keyType))
+			convertedKey, err := convertMapKey(key, keyType)
+			if err != nil {
+				panic(err)
+			}
+			k.Set(convertedKey)
 			v.Set(mapValue.MapIndex(key).Convert(valueType))
 			row = deconstruct(row, levels, elem)
 			levels.repetitionLevel = levels.repetitionDepth
@@ -449,12 +564,24 @@ func deconstructFuncOfLeaf(columnIndex int16, node Node) (int16, deconstructFunc
 		panic("row cannot be deconstructed because it has more than 127 columns")
 	}
 	kind := node.Type().Kind()
+	unit := durationUnitOf(node.Type())
+	timestamp, _ := node.Type().(*timestampType)
+	_, date := node.Type().(*dateType)
 	valueColumnIndex := ^columnIndex
 	return columnIndex + 1, func(row Row, levels levels, value reflect.Value) Row {
 		v := Value{}
 
 		if value.IsValid() {
-			v = makeValue(kind, value)
+			switch {
+			case unit != 0 && value.Type() == durationType:
+				v = makeValueDuration(kind, time.Duration(value.Int()), unit)
+			case timestamp != nil && value.Type() == timeTime:
+				v = timestamp.timeValue(value.Interface().(time.Time))
+			case date && (value.Type() == timeTime || value.Type() == civilDateType):
+				v = dateValueOf(value)
+			default:
+				v = makeValue(kind, value)
+			}
 		}
 
 		v.repetitionLevel = levels.repetitionLevel
@@ -516,14 +643,18 @@ func reconstructFuncOfRepeated(columnIndex int16, node Node) (int16, reconstruct
 	nextColumnIndex, reconstruct := reconstructFuncOf(columnIndex, Required(node))
 	rowLength := nextColumnIndex - columnIndex
 	return nextColumnIndex, func(value reflect.Value, lvls levels, row Row) (Row, error) {
-		t := value.Type()
-		c := value.Cap()
+		// The number of elements is known ahead of time so the backing array
+		// can be allocated only once, and only if the capacity of the slice
+		// is not already large enough to hold them.
+		c := countRepeated(columnIndex, rowLength, lvls, row)
 		n := 0
-		if c > 0 {
+		switch {
+		case c > value.Cap():
+			value.Set(reflect.MakeSlice(value.Type(), c, c))
+		case value.IsNil():
+			value.Set(reflect.MakeSlice(value.Type(), 0, 0))
+		default:
 			value.Set(value.Slice(0, c))
-		} else {
-			c = 10
-			value.Set(reflect.MakeSlice(t, c, c))
 		}
 
 		defer func() {
@@ -531,11 +662,10 @@ func reconstructFuncOfRepeated(columnIndex int16, node Node) (int16, reconstruct
 		}()
 
 		return reconstructRepeated(columnIndex, rowLength, lvls, row, func(levels levels, row Row) (Row, error) {
-			if n == c {
-				c *= 2
-				newValue := reflect.MakeSlice(t, c, c)
-				reflect.Copy(newValue, value)
-				value.Set(newValue)
+			if n == value.Len() {
+				// Should not happen since the elements were counted, but we
+				// prefer growing the slice over panicking on malformed rows.
+				value.Set(reflect.Append(value, reflect.Zero(value.Type().Elem())))
 			}
 			row, err := reconstruct(value.Index(n), levels, row)
 			n++
@@ -544,6 +674,32 @@ func reconstructFuncOfRepeated(columnIndex int16, node Node) (int16, reconstruct
 	}
 }
 
+// countRepeated returns the number of elements of the repeated group made of
+// the rowLength columns starting at columnIndex in row. Elements start with a
+// value of the first column at the repetition depth of the group.
+func countRepeated(columnIndex, rowLength int16, levels levels, row Row) int {
+	if !row.startsWith(columnIndex) || row[0].definitionLevel <= levels.definitionLevel {
+		return 0
+	}
+	depth := levels.repetitionDepth + 1
+	count := 1
+	for _, v := range row[1:] {
+		c := int16(v.Column())
+		if c < columnIndex || c >= columnIndex+rowLength {
+			break
+		}
+		if c == columnIndex {
+			if v.repetitionLevel < depth {
+				break
+			}
+			if v.repetitionLevel == depth {
+				count++
+			}
+		}
+	}
+	return count
+}
+
 func reconstructRepeated(columnIndex, rowLength int16, levels levels, row Row, do func(levels, Row) (Row, error)) (Row, error) {
 	if !row.startsWith(columnIndex) {
 		return row, fmt.Errorf("row is missing repeated column %d", columnIndex)
@@ -590,23 +746,40 @@ func reconstructFuncOfMap(columnIndex int16, node Node) (int16, reconstructFunc)
 	keyValueZero := reflect.Zero(keyValueElem)
 	nextColumnIndex, reconstruct := reconstructFuncOf(columnIndex, schemaOf(keyValueElem))
 	rowLength := nextColumnIndex - columnIndex
+	// The key/value pairs are reconstructed in a scratch value before being
+	// inserted in the map. The pool holds pointers so putting them back does
+	// not allocate.
+	scratch := sync.Pool{
+		New: func() interface{} { return reflect.New(keyValueElem).Interface() },
+	}
 	return nextColumnIndex, func(mapValue reflect.Value, lvls levels, row Row) (Row, error) {
 		t := mapValue.Type()
 		k := t.Key()
 		v := t.Elem()
 
 		if mapValue.IsNil() {
-			mapValue.Set(reflect.MakeMap(t))
+			mapValue.Set(reflect.MakeMapWithSize(t, countRepeated(columnIndex, rowLength, lvls, row)))
 		}
 
-		elem := reflect.New(keyValueElem).Elem()
+		ptr := scratch.Get()
+		elem := reflect.ValueOf(ptr).Elem()
+		defer func() {
+			elem.Set(keyValueZero)
+			scratch.Put(ptr)
+		}()
+
 		return reconstructRepeated(columnIndex, rowLength, lvls, row, func(levels levels, row Row) (Row, error) {
 			row, err := reconstruct(elem, levels, row)
-			if err == nil {
-				mapValue.SetMapIndex(elem.Field(0).Convert(k), elem.Field(1).Convert(v))
-				elem.Set(keyValueZero)
+			if err != nil {
+				return row, err
 			}
-			return row, err
+			key, err := convertMapKey(elem.Field(0), k)
+			if err != nil {
+				return row, err
+			}
+			mapValue.SetMapIndex(key, elem.Field(1).Convert(v))
+			elem.Set(keyValueZero)
+			return row, nil
 		})
 	}
 }
@@ -648,10 +821,120 @@ func reconstructFuncOfGroup(columnIndex int16, node Node) (int16, reconstructFun
 
 //go:noinline
 func reconstructFuncOfLeaf(columnIndex int16, node Node) (int16, reconstructFunc) {
+	unit := durationUnitOf(node.Type())
+	timestamp, _ := node.Type().(*timestampType)
+	_, date := node.Type().(*dateType)
 	return columnIndex + 1, func(value reflect.Value, _ levels, row Row) (Row, error) {
 		if !row.startsWith(columnIndex) {
 			return row, fmt.Errorf("no values found in parquet row for column %d", columnIndex)
 		}
+		if !row[0].IsNull() {
+			switch {
+			case unit != 0 && value.Type() == durationType:
+				value.SetInt(int64(row[0].duration(unit)))
+				return row[1:], nil
+			case timestamp != nil && value.Type() == timeTime:
+				value.Set(reflect.ValueOf(timestamp.timeOf(row[0])))
+				return row[1:], nil
+			case date && (value.Type() == timeTime || value.Type() == civilDateType):
+				assignDateValue(value, row[0])
+				return row[1:], nil
+			}
+		}
 		return row[1:], assignValue(value, row[0])
 	}
 }
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeTime     = reflect.TypeOf(time.Time{})
+)
+
+// durationUnitOf returns the unit that time.Duration values must be converted
+// to when written to columns of type t, or zero if they are written unchanged.
+func durationUnitOf(t Type) time.Duration {
+	if tt, ok := t.(*timeType); ok {
+		if unit := timeUnitDuration(&tt.Unit); unit != time.Nanosecond {
+			return unit
+		}
+	}
+	return 0
+}
+
+// convertMapKey converts the map key v to type t. In addition to the
+// conversions supported by the reflect package, integers are converted to and
+// from their decimal representation when t or v are strings (like map keys of
+// the encoding/json package), and bytes are copied between strings, byte
+// slices, and byte arrays.
+func convertMapKey(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if v.Type() == t {
+		return v, nil
+	}
+
+	switch {
+	case t.Kind() == reflect.String && isIntegerKind(v.Kind()):
+		if isUnsignedKind(v.Kind()) {
+			return reflect.ValueOf(strconv.FormatUint(v.Uint(), 10)).Convert(t), nil
+		}
+		return reflect.ValueOf(strconv.FormatInt(v.Int(), 10)).Convert(t), nil
+
+	case v.Kind() == reflect.String && isIntegerKind(t.Kind()):
+		k := reflect.New(t).Elem()
+		if isUnsignedKind(t.Kind()) {
+			u, err := strconv.ParseUint(v.String(), 10, t.Bits())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("cannot convert map key %q to %s: %w", v.String(), t, err)
+			}
+			k.SetUint(u)
+		} else {
+			i, err := strconv.ParseInt(v.String(), 10, t.Bits())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("cannot convert map key %q to %s: %w", v.String(), t, err)
+			}
+			k.SetInt(i)
+		}
+		return k, nil
+
+	case isByteSequenceType(v.Type()) && isByteSequenceType(t):
+		b := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+		switch t.Kind() {
+		case reflect.String:
+			return reflect.ValueOf(string(b)).Convert(t), nil
+		case reflect.Slice:
+			return reflect.ValueOf(b).Convert(t), nil
+		default:
+			if len(b) != t.Len() {
+				return reflect.Value{}, fmt.Errorf("cannot convert map key of length %d to %s", len(b), t)
+			}
+			k := reflect.New(t).Elem()
+			reflect.Copy(k, reflect.ValueOf(b))
+			return k, nil
+		}
+
+	case v.Type().ConvertibleTo(t):
+		return v.Convert(t), nil
+
+	default:
+		return reflect.Value{}, fmt.Errorf("cannot convert map key of type %s to %s", v.Type(), t)
+	}
+}
+
+func isIntegerKind(k reflect.Kind) bool {
+	return (k >= reflect.Int && k <= reflect.Int64) || isUnsignedKind(k)
+}
+
+func isUnsignedKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isByteSequenceType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String:
+		return true
+	case reflect.Slice, reflect.Array:
+		return t.Elem().Kind() == reflect.Uint8
+	default:
+		return false
+	}
+}
@@ -203,24 +203,7 @@ func MergeRowGroups(rowGroups []RowGroup, options ...RowGroupOption) (RowGroup,
 		}
 	}
 
-	m.sortFuncs = make([]columnSortFunc, len(m.sorting))
-	forEachLeafColumnOf(schema, func(leaf leafColumn) {
-		if sortingIndex := searchSortingColumn(m.sorting, leaf.path); sortingIndex < len(m.sorting) {
-			m.sortFuncs[sortingIndex] = columnSortFunc{
-				columnIndex: leaf.columnIndex,
-				compare: sortFuncOf(
-					leaf.node.Type(),
-					&SortConfig{
-						MaxRepetitionLevel: int(leaf.maxRepetitionLevel),
-						MaxDefinitionLevel: int(leaf.maxDefinitionLevel),
-						Descending:         m.sorting[sortingIndex].Descending(),
-						NullsFirst:         m.sorting[sortingIndex].NullsFirst(),
-					},
-				),
-			}
-		}
-	})
-
+	m.sortFuncs = columnSortFuncsOf(schema, m.sorting)
 	return m, nil
 }
 
@@ -280,6 +263,18 @@ func (r *rowGroupRowReader) Schema() *Schema {
 	}
 }
 
+// Close releases the page readers and buffers held by r. The reader returns
+// io.EOF once closed.
+func (r *rowGroupRowReader) Close() (err error) {
+	for i := range r.columns {
+		if cerr := r.columns[i].close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	r.rowGroup, r.schema, r.columns = nil, nil, nil
+	return err
+}
+
 func (r *rowGroupRowReader) SeekToRow(rowIndex int64) error {
 	for i := range r.columns {
 		if err := r.columns[i].seekToRow(rowIndex); err != nil {
@@ -302,7 +297,7 @@ func (r *rowGroupRowReader) ReadRow(row Row) (Row, error) {
 		return row, io.EOF
 	}
 	n := len(row)
-	row, err := r.schema.readRow(row, 0, r.columns)
+	row, err := r.schema.lazyLoadFuncs().readRow(row, 0, r.columns)
 	if err == nil && len(row) == n {
 		err = io.EOF
 	}
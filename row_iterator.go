@@ -0,0 +1,143 @@
+package parquet
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// RowIterator is an iterator over the rows of a row group, modeled after the
+// database/sql.Rows type:
+//
+//	it := parquet.NewRowIterator(rowGroup)
+//	defer it.Close()
+//
+//	for it.Next() {
+//		row := RowType{}
+//		if err := it.Scan(&row); err != nil {
+//			...
+//		}
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// The page buffers and decompressors held by the iterator are released when
+// Next returns false or when Close is called, whichever happens first.
+type RowIterator struct {
+	rowGroup RowGroup
+	rows     Rows
+	row      Row
+	values   []Value
+	err      error
+	closed   bool
+
+	// State used to reconstruct rows into Go values in Scan, updated when the
+	// type of values changes.
+	seen   reflect.Type
+	schema *Schema
+	conv   Conversion
+}
+
+// NewRowIterator constructs an iterator over the rows of rowGroup.
+func NewRowIterator(rowGroup RowGroup) *RowIterator {
+	return &RowIterator{rowGroup: rowGroup}
+}
+
+// RowIterator constructs an iterator over all the rows of f.
+func (f *File) RowIterator() *RowIterator {
+	rowGroups := make([]RowGroup, len(f.rowGroups))
+	for i := range f.rowGroups {
+		rowGroups[i] = &f.rowGroups[i]
+	}
+	return NewRowIterator(concat(NewSchema(f.root.Name(), f.root), rowGroups))
+}
+
+var errScanWithoutNext = errors.New("parquet: Scan called without a successful call to Next")
+
+// Next advances the iterator to the next row, returning false when there are
+// no more rows or an error occurred. The Err method must be called after Next
+// returned false to distinguish between the two cases.
+func (it *RowIterator) Next() bool {
+	if it.closed {
+		return false
+	}
+	if it.rows == nil {
+		it.rows = it.rowGroup.Rows()
+	}
+	var err error
+	it.row, err = it.rows.ReadRow(it.row[:0])
+	if err != nil {
+		if err != io.EOF {
+			it.err = err
+		}
+		it.Close()
+		return false
+	}
+	return true
+}
+
+// Row returns the current row of the iterator. The row is only valid until the
+// next call to Next, programs that need to retain it must make a copy.
+func (it *RowIterator) Row() Row {
+	if it.closed {
+		return nil
+	}
+	return it.row
+}
+
+// Scan reconstructs the current row of the iterator into the Go value pointed
+// to by dst, which is typically a pointer to a struct.
+//
+// If the schema of dst differs from the schema of the row group, the row is
+// converted to the schema of dst, using the same rules as the Convert function.
+func (it *RowIterator) Scan(dst interface{}) error {
+	if it.closed || len(it.row) == 0 {
+		return errScanWithoutNext
+	}
+
+	if rowType := dereference(reflect.TypeOf(dst)); rowType != it.seen {
+		if rowType.Kind() != reflect.Struct {
+			return fmt.Errorf("cannot scan parquet row into go value of type %T", dst)
+		}
+		schema, conv := schemaOf(rowType), Conversion(nil)
+		if rowsSchema := it.rows.Schema(); !nodesAreEqual(schema, rowsSchema) {
+			var err error
+			if conv, err = Convert(schema, rowsSchema); err != nil {
+				return fmt.Errorf("cannot scan parquet row into go value of type %T: %w", dst, err)
+			}
+		}
+		it.seen, it.schema, it.conv = rowType, schema, conv
+	}
+
+	row := it.row
+	if it.conv != nil {
+		var err error
+		if it.values, err = it.conv.Convert(it.values[:0], row); err != nil {
+			return err
+		}
+		row = it.values
+	}
+	return it.schema.Reconstruct(dst, row)
+}
+
+// Err returns the error, if any, that was encountered during iteration.
+func (it *RowIterator) Err() error { return it.err }
+
+// Close releases the resources held by the iterator. Calling Close multiple
+// times is safe, Next returns false once the iterator was closed.
+func (it *RowIterator) Close() error {
+	if it.closed {
+		return nil
+	}
+	it.closed = true
+	clearValues(it.row)
+	clearValues(it.values)
+	it.row, it.values = nil, nil
+	if c, ok := it.rows.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+//go:build go1.23
+
+package parquet
+
+import (
+	"io"
+	"iter"
+)
+
+// Rows returns a sequence of the rows of f, which can be used in range loops:
+//
+//	for row, err := range f.Rows() {
+//		if err != nil {
+//			...
+//		}
+//		...
+//	}
+//
+// The rows are only valid until the next iteration of the loop, programs that
+// need to retain them must make a copy. The sequence stops after yielding the
+// first error, if any.
+func (f *File) Rows() iter.Seq2[Row, error] {
+	return allRows(f.RowIterator)
+}
+
+// Values returns a sequence of the values of the leaf column at columnIndex,
+// across all the row groups of f. See the Values function for details.
+func (f *File) Values(columnIndex int) iter.Seq2[Value, error] {
+	return func(yield func(Value, error) bool) {
+		for _, rowGroup := range f.RowGroups() {
+			if !yieldValues(rowGroup.Column(columnIndex), yield) {
+				return
+			}
+		}
+	}
+}
+
+// AllRows returns a sequence of the rows of rowGroup, with the same semantics
+// as the File.Rows method.
+func AllRows(rowGroup RowGroup) iter.Seq2[Row, error] {
+	return allRows(func() *RowIterator { return NewRowIterator(rowGroup) })
+}
+
+// All returns a sequence of the rows of rowGroup reconstructed into Go values
+// of type T, which must be a struct type:
+//
+//	for row, err := range parquet.All[RowType](rowGroup) {
+//		if err != nil {
+//			...
+//		}
+//		...
+//	}
+//
+// The sequence stops after yielding the first error, if any.
+func All[T any](rowGroup RowGroup) iter.Seq2[T, error] {
+	return allValues[T](func() *RowIterator { return NewRowIterator(rowGroup) })
+}
+
+// AllFile is like All but iterates over all the rows of f.
+func AllFile[T any](f *File) iter.Seq2[T, error] {
+	return allValues[T](f.RowIterator)
+}
+
+// Values returns a sequence of the values of column, without reconstructing
+// the rows that they belong to:
+//
+//	for value, err := range parquet.Values(rowGroup.Column(columnIndex)) {
+//		if err != nil {
+//			...
+//		}
+//		...
+//	}
+//
+// The values carry their repetition and definition levels, like the values
+// read with NewColumnChunkValueReader; they do not share memory with the pages
+// they were read from and remain valid after the iteration. The sequence stops
+// after yielding the first error, if any.
+func Values(column ColumnChunk) iter.Seq2[Value, error] {
+	return func(yield func(Value, error) bool) {
+		yieldValues(column, yield)
+	}
+}
+
+// yieldValues passes the values of column to yield, returning false if the
+// iteration was stopped, either by yield or because an error occurred.
+func yieldValues(column ColumnChunk, yield func(Value, error) bool) bool {
+	pages := column.Pages()
+	defer closePages(pages)
+
+	values := make([]Value, defaultValueBufferSize)
+	for {
+		p, err := pages.ReadPage()
+		if err != nil {
+			if err == io.EOF {
+				return true
+			}
+			yield(Value{}, err)
+			return false
+		}
+		reader := p.Values()
+		for {
+			n, err := reader.ReadValues(values)
+			for _, v := range values[:n] {
+				if !yield(v, nil) {
+					return false
+				}
+			}
+			if err != nil {
+				if err == io.EOF {
+					break
+				}
+				yield(Value{}, err)
+				return false
+			}
+		}
+	}
+}
+
+func allRows(newRowIterator func() *RowIterator) iter.Seq2[Row, error] {
+	return func(yield func(Row, error) bool) {
+		it := newRowIterator()
+		defer it.Close()
+
+		for it.Next() {
+			if !yield(it.Row(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+func allValues[T any](newRowIterator func() *RowIterator) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		it := newRowIterator()
+		defer it.Close()
+
+		for it.Next() {
+			var value T
+			if err := it.Scan(&value); err != nil {
+				yield(value, err)
+				return
+			}
+			if !yield(value, nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
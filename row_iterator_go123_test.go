@@ -0,0 +1,136 @@
+//go:build go1.23
+
+package parquet_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestAll(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	rows := []Row{
+		{ID: 0, Name: "A"},
+		{ID: 1, Name: "B"},
+		{ID: 2, Name: "C"},
+		{ID: 3, Name: "D"},
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	for i := range rows {
+		if err := writer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+		if i == 1 {
+			if err := writer.Flush(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("File.Rows", func(t *testing.T) {
+		n := 0
+		for row, err := range f.Rows() {
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(row) != 2 || row[0].Int64() != rows[n].ID || row[1].String() != rows[n].Name {
+				t.Errorf("wrong row at index %d: %v", n, row)
+			}
+			n++
+		}
+		if n != len(rows) {
+			t.Errorf("wrong number of rows: want=%d got=%d", len(rows), n)
+		}
+	})
+
+	t.Run("AllFile", func(t *testing.T) {
+		got := []Row{}
+		for row, err := range parquet.AllFile[Row](f) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, row)
+		}
+		if len(got) != len(rows) {
+			t.Fatalf("wrong number of rows: want=%d got=%d", len(rows), len(got))
+		}
+		for i := range rows {
+			if got[i] != rows[i] {
+				t.Errorf("wrong row at index %d: want=%+v got=%+v", i, rows[i], got[i])
+			}
+		}
+	})
+
+	t.Run("All", func(t *testing.T) {
+		rowGroup := f.RowGroups()[1]
+		got := []Row{}
+		for row, err := range parquet.All[Row](rowGroup) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, row)
+		}
+		if len(got) != 2 || got[0] != rows[2] || got[1] != rows[3] {
+			t.Errorf("wrong rows: %+v", got)
+		}
+	})
+
+	t.Run("File.Values", func(t *testing.T) {
+		n := 0
+		for value, err := range f.Values(1) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value.Column() != 1 || value.String() != rows[n].Name {
+				t.Errorf("wrong value at index %d: %+v", n, value)
+			}
+			n++
+		}
+		if n != len(rows) {
+			t.Errorf("wrong number of values: want=%d got=%d", len(rows), n)
+		}
+	})
+
+	t.Run("Values", func(t *testing.T) {
+		got := []int64{}
+		for value, err := range parquet.Values(f.RowGroups()[1].Column(0)) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, value.Int64())
+		}
+		if len(got) != 2 || got[0] != rows[2].ID || got[1] != rows[3].ID {
+			t.Errorf("wrong values: %v", got)
+		}
+	})
+
+	t.Run("AllRows/break", func(t *testing.T) {
+		n := 0
+		for _, err := range parquet.AllRows(f.RowGroups()[0]) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			n++
+			break
+		}
+		if n != 1 {
+			t.Errorf("wrong number of iterations: want=1 got=%d", n)
+		}
+	})
+}
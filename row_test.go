@@ -3,6 +3,7 @@ package parquet_test
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/segmentio/parquet-go"
@@ -432,6 +433,88 @@ func TestDeconstructionReconstruction(t *testing.T) {
 				},
 			},
 		},
+
+		{
+			scenario: "map with integer keys",
+			input: struct {
+				Scores map[int32]float64 `parquet:"scores"`
+			}{
+				Scores: map[int32]float64{7: 1.5},
+			},
+			values: [][]parquet.Value{
+				0: {parquet.ValueOf(int32(7)).Level(0, 1, 0)},
+				1: {parquet.ValueOf(1.5).Level(0, 1, 1)},
+			},
+		},
+
+		{
+			scenario: "map with time keys",
+			input: struct {
+				Events map[time.Time]string            `parquet:"events"`
+				Days   map[parquet.CivilDate]time.Time `parquet:"days"`
+			}{
+				Events: map[time.Time]string{time.Unix(1, 0).UTC(): "start"},
+				Days:   map[parquet.CivilDate]time.Time{{Year: 1970, Month: 1, Day: 2}: time.Unix(2, 0).UTC()},
+			},
+			values: [][]parquet.Value{
+				0: {parquet.ValueOf(int32(1)).Level(0, 1, 0)},
+				1: {parquet.ValueOf(int64(2e6)).Level(0, 1, 1)},
+				2: {parquet.ValueOf(int64(1e6)).Level(0, 1, 2)},
+				3: {parquet.ValueOf("start").Level(0, 1, 3)},
+			},
+		},
+
+		{
+			scenario: "lists of lists",
+			input: struct {
+				Matrix [][]int32 `parquet:"matrix"`
+			}{
+				Matrix: [][]int32{{1, 2}, {}, {3}},
+			},
+			values: [][]parquet.Value{
+				0: {
+					parquet.ValueOf(int32(1)).Level(0, 2, 0),
+					parquet.ValueOf(int32(2)).Level(2, 2, 0),
+					parquet.Value{}.Level(1, 1, 0),
+					parquet.ValueOf(int32(3)).Level(1, 2, 0),
+				},
+			},
+		},
+
+		{
+			scenario: "list of maps",
+			input: struct {
+				Attributes []map[string]int32 `parquet:"attributes"`
+			}{
+				Attributes: []map[string]int32{{"a": 1}, {}},
+			},
+			values: [][]parquet.Value{
+				0: {
+					parquet.ValueOf("a").Level(0, 2, 0),
+					parquet.Value{}.Level(1, 1, 0),
+				},
+				1: {
+					parquet.ValueOf(int32(1)).Level(0, 2, 1),
+					parquet.Value{}.Level(1, 1, 1),
+				},
+			},
+		},
+
+		{
+			scenario: "map of lists",
+			input: struct {
+				Tags map[string][]int32 `parquet:"tags"`
+			}{
+				Tags: map[string][]int32{"a": {1, 2}},
+			},
+			values: [][]parquet.Value{
+				0: {parquet.ValueOf("a").Level(0, 1, 0)},
+				1: {
+					parquet.ValueOf(int32(1)).Level(0, 2, 1),
+					parquet.ValueOf(int32(2)).Level(2, 2, 1),
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -509,6 +592,52 @@ func assertEqualValues(t *testing.T, columnIndex int, want, got []parquet.Value)
 	}
 }
 
+func TestDeconstructMapKeyConversion(t *testing.T) {
+	tests := []struct {
+		scenario string
+		schema   string
+		input    interface{}
+		key      parquet.Value
+	}{
+		{
+			scenario: "integer to string",
+			schema:   `message Test { required group m (MAP) { repeated group key_value { required binary key (STRING); required int64 value; } } }`,
+			input:    map[string]map[int]int64{"m": {-42: 1}},
+			key:      parquet.ValueOf("-42"),
+		},
+		{
+			scenario: "string to integer",
+			schema:   `message Test { required group m (MAP) { repeated group key_value { required int32 key; required int64 value; } } }`,
+			input:    map[string]map[string]int64{"m": {"42": 1}},
+			key:      parquet.ValueOf(int32(42)),
+		},
+		{
+			scenario: "string to fixed length byte array",
+			schema:   `message Test { required group m (MAP) { repeated group key_value { required fixed_len_byte_array(2) key; required int64 value; } } }`,
+			input:    map[string]map[string]int64{"m": {"xy": 1}},
+			key:      parquet.ValueOf([2]byte{'x', 'y'}),
+		},
+		{
+			scenario: "byte array to byte slice",
+			schema:   `message Test { required group m (MAP) { repeated group key_value { required binary key; required int64 value; } } }`,
+			input:    map[string]map[[2]byte]int64{"m": {{1, 2}: 1}},
+			key:      parquet.ValueOf([]byte{1, 2}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			schema, err := parquet.ParseSchema(test.schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			row := schema.Deconstruct(nil, test.input)
+			values := columnsOf(row)
+			assertEqualValues(t, 0, []parquet.Value{test.key.Level(0, 1, 0)}, values[0])
+		})
+	}
+}
+
 func BenchmarkDeconstruct(b *testing.B) {
 	row := &AddressBook{
 		Owner: "Julien Le Dem",
@@ -535,6 +664,61 @@ func BenchmarkDeconstruct(b *testing.B) {
 	}
 }
 
+func TestReconstructReusesCapacity(t *testing.T) {
+	type Row struct {
+		Tags     []string         `parquet:"tags"`
+		Contacts []Contact        `parquet:"contacts"`
+		Counts   map[string]int64 `parquet:"counts"`
+	}
+
+	row := &Row{
+		Tags: []string{"a", "b", "c"},
+		Contacts: []Contact{
+			{Name: "A", PhoneNumber: "1"},
+			{Name: "B"},
+		},
+		Counts: map[string]int64{"x": 1, "y": 2},
+	}
+
+	schema := parquet.SchemaOf(row)
+	values := schema.Deconstruct(nil, row)
+
+	buffer := Row{}
+	if err := schema.Reconstruct(&buffer, values); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(row, &buffer) {
+		t.Fatalf("rows mismatch:\nwant = %+v\ngot  = %+v", row, &buffer)
+	}
+	// The elements are counted before allocating the slices.
+	if cap(buffer.Tags) != len(row.Tags) || cap(buffer.Contacts) != len(row.Contacts) {
+		t.Errorf("slices were over-allocated: cap(tags)=%d cap(contacts)=%d", cap(buffer.Tags), cap(buffer.Contacts))
+	}
+
+	tags, contacts := &buffer.Tags[0], &buffer.Contacts[0]
+	buffer.Tags = buffer.Tags[:0]
+	buffer.Contacts = buffer.Contacts[:0]
+	buffer.Counts = nil
+
+	if err := schema.Reconstruct(&buffer, values); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(row, &buffer) {
+		t.Fatalf("rows mismatch:\nwant = %+v\ngot  = %+v", row, &buffer)
+	}
+	if &buffer.Tags[0] != tags || &buffer.Contacts[0] != contacts {
+		t.Error("the capacity of slices was not reused")
+	}
+
+	empty := Row{}
+	if err := schema.Reconstruct(&empty, schema.Deconstruct(nil, &Row{})); err != nil {
+		t.Fatal(err)
+	}
+	if empty.Tags == nil || len(empty.Tags) != 0 {
+		t.Errorf("empty repeated columns must be reconstructed as empty slices: %#v", empty.Tags)
+	}
+}
+
 func BenchmarkReconstruct(b *testing.B) {
 	row := &AddressBook{
 		Owner: "Julien Le Dem",
@@ -565,3 +749,112 @@ func BenchmarkReconstruct(b *testing.B) {
 		}
 	}
 }
+
+func TestCompareRows(t *testing.T) {
+	type Row struct {
+		Name  string   `parquet:"name"`
+		Age   *int     `parquet:"age,optional"`
+		Pets  []string `parquet:"pets"`
+		Score float64  `parquet:"score"`
+	}
+
+	age := func(v int) *int { return &v }
+	schema := parquet.SchemaOf(new(Row))
+	rowOf := func(row Row) parquet.Row { return schema.Deconstruct(nil, &row) }
+
+	tests := []struct {
+		scenario string
+		a, b     Row
+		sorting  []parquet.SortingColumn
+		want     int
+	}{
+		{
+			scenario: "equal rows",
+			a:        Row{Name: "Luke", Age: age(42), Pets: []string{"Ewok"}, Score: 1},
+			b:        Row{Name: "Luke", Age: age(42), Pets: []string{"Ewok"}, Score: 1},
+			want:     0,
+		},
+		{
+			scenario: "all columns",
+			a:        Row{Name: "Han", Score: 2},
+			b:        Row{Name: "Luke", Score: 1},
+			want:     -1,
+		},
+		{
+			scenario: "sorting column",
+			a:        Row{Name: "Han", Score: 2},
+			b:        Row{Name: "Luke", Score: 1},
+			sorting:  []parquet.SortingColumn{parquet.Ascending("score")},
+			want:     +1,
+		},
+		{
+			scenario: "descending",
+			a:        Row{Name: "Han"},
+			b:        Row{Name: "Luke"},
+			sorting:  []parquet.SortingColumn{parquet.Descending("name")},
+			want:     +1,
+		},
+		{
+			scenario: "nulls last",
+			a:        Row{Age: nil},
+			b:        Row{Age: age(1)},
+			sorting:  []parquet.SortingColumn{parquet.Ascending("age")},
+			want:     +1,
+		},
+		{
+			scenario: "nulls first",
+			a:        Row{Age: nil},
+			b:        Row{Age: age(1)},
+			sorting:  []parquet.SortingColumn{parquet.NullsFirst(parquet.Ascending("age"))},
+			want:     -1,
+		},
+		{
+			scenario: "repeated prefix",
+			a:        Row{Pets: []string{"Ewok"}},
+			b:        Row{Pets: []string{"Ewok", "Wookie"}},
+			sorting:  []parquet.SortingColumn{parquet.Ascending("pets")},
+			want:     -1,
+		},
+		{
+			scenario: "repeated elements",
+			a:        Row{Pets: []string{"Wookie"}},
+			b:        Row{Pets: []string{"Ewok", "Wookie"}},
+			sorting:  []parquet.SortingColumn{parquet.Ascending("pets")},
+			want:     +1,
+		},
+		{
+			scenario: "unknown column",
+			a:        Row{Name: "Han"},
+			b:        Row{Name: "Luke"},
+			sorting:  []parquet.SortingColumn{parquet.Ascending("height")},
+			want:     0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			a, b := rowOf(test.a), rowOf(test.b)
+
+			if cmp := parquet.CompareRows(schema, a, b, test.sorting); sign(cmp) != test.want {
+				t.Errorf("wrong comparison of a and b: want=%d got=%d", test.want, cmp)
+			}
+			if cmp := parquet.CompareRows(schema, b, a, test.sorting); sign(cmp) != -test.want {
+				t.Errorf("wrong comparison of b and a: want=%d got=%d", -test.want, cmp)
+			}
+			if equal := a.Equal(b); equal != (test.want == 0 && len(test.sorting) == 0) {
+				t.Errorf("wrong equality of rows: %t", equal)
+			}
+		})
+	}
+}
+
+func sign(cmp int) int {
+	switch {
+	case cmp < 0:
+		return -1
+	case cmp > 0:
+		return +1
+	default:
+		return 0
+	}
+}
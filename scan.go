@@ -0,0 +1,367 @@
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"unsafe"
+)
+
+// RowGroupScanner reads the rows of a row group into Go values.
+//
+// When the Go values are structs where all the fields are leaf columns (for
+// example strings, integers, or pointers to those), the scanner decodes the
+// values of each column in batches and assigns them directly to the fields of
+// the structs, instead of reconstructing the rows one at a time like Reader
+// does. Columns of the row group which are not fields of the structs are not
+// read.
+//
+// The scanner falls back to reading full rows when the structs have nested or
+// repeated fields, or when the types of the fields differ from the types of
+// the columns, in which case the rows are converted like they are by Reader.
+//
+//	for _, rowGroup := range f.RowGroups() {
+//		scanner := parquet.NewRowGroupScanner(rowGroup)
+//		for {
+//			n, err := scanner.Scan(rows)
+//			...
+//		}
+//		scanner.Close()
+//	}
+//
+// RowGroupScanner values are not safe to use concurrently from multiple
+// goroutines.
+type RowGroupScanner struct {
+	rowGroup RowGroup
+	rowType  reflect.Type
+	numRows  int64
+	rowIndex int64
+	schema   *Schema
+	// Set when the rows are scanned one column at a time.
+	fields       []scanField
+	valueByIndex func(reflect.Value, int) reflect.Value
+	rowPointers  []unsafe.Pointer
+	// Set when the rows are read in full and reconstructed.
+	rows Rows
+	row  Row
+}
+
+// scanField holds the state of scanning a column into a field of structs.
+type scanField struct {
+	index       int
+	column      int
+	pages       Pages
+	values      ValueReader
+	buffer      []Value
+	reconstruct reconstructFunc
+	// When the Go type of the field matches the column type, the values are
+	// written directly at the offset of the field in the struct by assign.
+	offset uintptr
+	assign func(unsafe.Pointer, *Value)
+}
+
+// NewRowGroupScanner constructs a scanner reading the rows of rowGroup.
+func NewRowGroupScanner(rowGroup RowGroup) *RowGroupScanner {
+	return &RowGroupScanner{rowGroup: rowGroup, numRows: rowGroup.NumRows()}
+}
+
+// Scan reads rows into dst, which must be a slice of structs or pointers to
+// structs (or a pointer to such a slice), filling up to len(dst) rows and
+// allocating the values of nil pointers. The method returns the number of rows
+// read.
+//
+// When dst is a pointer to a slice, the slice is truncated to the number of
+// rows read when the method returns.
+//
+// All calls to Scan must use slices of the same Go type. Like io.Reader, the
+// method may return n > 0 with a non-nil error. It returns io.EOF when all the
+// rows of the row group have been read.
+func (s *RowGroupScanner) Scan(dst interface{}) (n int, err error) {
+	rows := reflect.ValueOf(dst)
+	if rows.Kind() == reflect.Ptr && rows.Elem().Kind() == reflect.Slice {
+		rows = rows.Elem()
+		defer func() { rows.Set(rows.Slice(0, n)) }()
+	}
+	if rows.Kind() != reflect.Slice {
+		return 0, fmt.Errorf("cannot scan parquet rows into go value of type %T: not a slice", dst)
+	}
+
+	rowType := dereference(rows.Type().Elem())
+	if rowType.Kind() != reflect.Struct {
+		return 0, fmt.Errorf("cannot scan parquet rows into go value of type %T: not a slice of structs", dst)
+	}
+	switch s.rowType {
+	case nil:
+		if err := s.init(rowType); err != nil {
+			return 0, fmt.Errorf("cannot scan parquet rows into go value of type %T: %w", dst, err)
+		}
+	case rowType:
+	default:
+		return 0, fmt.Errorf("cannot scan parquet rows into go value of type %T after scanning rows of type %s", dst, s.rowType)
+	}
+
+	if remain := s.numRows - s.rowIndex; int64(rows.Len()) > remain {
+		rows = rows.Slice(0, int(remain))
+	}
+	if rows.Len() == 0 {
+		return 0, io.EOF
+	}
+
+	for i, numRows := 0, rows.Len(); i < numRows; i++ {
+		if row := rows.Index(i); row.Kind() == reflect.Ptr && row.IsNil() {
+			row.Set(reflect.New(rowType))
+		}
+	}
+
+	if s.fields != nil {
+		n, err = s.scanColumns(rows)
+	} else {
+		n, err = s.scanRows(rows)
+	}
+	s.rowIndex += int64(n)
+	if err == nil && s.rowIndex == s.numRows {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Close releases the resources held by the scanner.
+func (s *RowGroupScanner) Close() error {
+	var err error
+	for i := range s.fields {
+		if s.fields[i].pages != nil {
+			if e := closePages(s.fields[i].pages); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+	if c, ok := s.rows.(io.Closer); ok {
+		if e := c.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	s.fields, s.rows = nil, nil
+	s.rowIndex = s.numRows
+	return err
+}
+
+func (s *RowGroupScanner) init(rowType reflect.Type) error {
+	schema := schemaOf(rowType)
+	fileSchema := s.rowGroup.Schema()
+	s.rowType, s.schema = rowType, schema
+
+	if fields, ok := scanFieldsOf(schema, fileSchema); ok {
+		for i := range fields {
+			if f := &fields[i]; f.column >= 0 {
+				f.pages = s.rowGroup.Column(f.column).Pages()
+			}
+		}
+		names := schema.ChildNames()
+		s.fields = fields
+		s.valueByIndex = func(value reflect.Value, index int) reflect.Value {
+			return schema.ValueByName(value, names[index])
+		}
+		if n, ok := unwrap(schema.root).(IndexedNode); ok {
+			s.valueByIndex = n.ValueByIndex
+		}
+		return nil
+	}
+
+	rowGroup := s.rowGroup
+	if !nodesAreEqual(schema, fileSchema) {
+		conv, err := Convert(schema, fileSchema)
+		if err != nil {
+			return err
+		}
+		rowGroup = ConvertRowGroup(rowGroup, conv)
+	}
+	s.rows = rowGroup.Rows()
+	return nil
+}
+
+// scanFieldsOf returns the fields of the struct schema which can be scanned
+// from the columns of fileSchema. The function returns false if some of the
+// fields are not leaf columns, or have different types or repetitions than the
+// columns of the file.
+func scanFieldsOf(schema, fileSchema *Schema) ([]scanField, bool) {
+	columns := make(map[string]int)
+	forEachLeafColumnOf(fileSchema, func(leaf leafColumn) {
+		if len(leaf.path) == 1 {
+			columns[leaf.path[0]] = int(leaf.columnIndex)
+		}
+	})
+
+	names := schema.ChildNames()
+	fields := make([]scanField, len(names))
+
+	for i, name := range names {
+		node := schema.ChildByName(name)
+		if !isLeaf(node) || node.Repeated() {
+			return nil, false
+		}
+
+		column, ok := columns[name]
+		if !ok {
+			if fileSchema.ChildByName(name) != nil {
+				return nil, false
+			}
+			column = -1
+		} else if !leafNodesAreEqual(node, fileSchema.ChildByName(name)) {
+			return nil, false
+		}
+
+		_, reconstruct := reconstructFuncOf(0, node)
+		fields[i] = scanField{index: i, column: column, reconstruct: reconstruct}
+
+		if n, ok := unwrap(schema.root).(*structNode); ok && column >= 0 {
+			if index := n.fields[i].index; len(index) == 1 {
+				f := n.gotype.Field(index[0])
+				fields[i].offset = f.Offset
+				fields[i].assign = scanAssignFuncOf(node, f.Type)
+			}
+		}
+	}
+
+	return fields, true
+}
+
+// scanAssignFuncOf returns a function writing values of node to the memory of
+// Go values of type t, or nil if the values need to be converted by the
+// reconstruct function of the field.
+func scanAssignFuncOf(node Node, t reflect.Type) func(unsafe.Pointer, *Value) {
+	if node.Optional() || node.Repeated() || durationUnitOf(node.Type()) != 0 {
+		return nil
+	}
+
+	switch kind, goKind := node.Type().Kind(), t.Kind(); {
+	case kind == Boolean && goKind == reflect.Bool:
+		return func(p unsafe.Pointer, v *Value) { *(*bool)(p) = v.Boolean() }
+	case kind == Int32 && goKind == reflect.Int32:
+		return func(p unsafe.Pointer, v *Value) { *(*int32)(p) = v.Int32() }
+	case kind == Int32 && goKind == reflect.Uint32:
+		return func(p unsafe.Pointer, v *Value) { *(*uint32)(p) = uint32(v.Int32()) }
+	case kind == Int64 && goKind == reflect.Int64:
+		return func(p unsafe.Pointer, v *Value) { *(*int64)(p) = v.Int64() }
+	case kind == Int64 && goKind == reflect.Uint64:
+		return func(p unsafe.Pointer, v *Value) { *(*uint64)(p) = uint64(v.Int64()) }
+	case kind == Float && goKind == reflect.Float32:
+		return func(p unsafe.Pointer, v *Value) { *(*float32)(p) = v.Float() }
+	case kind == Float && goKind == reflect.Float64:
+		return func(p unsafe.Pointer, v *Value) { *(*float64)(p) = float64(v.Float()) }
+	case kind == Double && goKind == reflect.Float64:
+		return func(p unsafe.Pointer, v *Value) { *(*float64)(p) = v.Double() }
+	case kind == ByteArray && goKind == reflect.String:
+		return func(p unsafe.Pointer, v *Value) { *(*string)(p) = string(v.ByteArray()) }
+	default:
+		return nil
+	}
+}
+
+func (s *RowGroupScanner) scanColumns(rows reflect.Value) (int, error) {
+	numRows := rows.Len()
+	valueByIndex := s.valueByIndex
+
+	// The addresses of the rows are only computed once per batch so the values
+	// of columns with an assign function can be written without reflection.
+	rowPointers := s.rowPointers[:0]
+	if rows.Type().Elem().Kind() == reflect.Ptr {
+		for j := 0; j < numRows; j++ {
+			rowPointers = append(rowPointers, unsafe.Pointer(rows.Index(j).Pointer()))
+		}
+	} else {
+		base, size := unsafe.Pointer(rows.Pointer()), s.rowType.Size()
+		for j := 0; j < numRows; j++ {
+			rowPointers = append(rowPointers, unsafe.Pointer(uintptr(base)+uintptr(j)*size))
+		}
+	}
+	s.rowPointers = rowPointers
+
+	for i := range s.fields {
+		f := &s.fields[i]
+
+		if f.column < 0 {
+			for j := 0; j < numRows; j++ {
+				field := valueByIndex(reflect.Indirect(rows.Index(j)), f.index)
+				field.Set(reflect.Zero(field.Type()))
+			}
+			continue
+		}
+
+		if cap(f.buffer) < numRows {
+			f.buffer = make([]Value, numRows)
+		}
+
+		// The values are assigned before reading the next page because they
+		// may reference the buffers of the current page, which are reused.
+		for j := 0; j < numRows; {
+			values, err := f.readValues(f.buffer[:numRows-j])
+			if err != nil {
+				return 0, err
+			}
+			if f.assign != nil {
+				for k := range values {
+					f.assign(unsafe.Pointer(uintptr(rowPointers[j])+f.offset), &values[k])
+					j++
+				}
+				continue
+			}
+			for k := range values {
+				values[k].columnIndex = ^0
+				field := valueByIndex(reflect.Indirect(rows.Index(j)), f.index)
+				if _, err := f.reconstruct(field, levels{}, values[k:k+1]); err != nil {
+					return 0, err
+				}
+				j++
+			}
+		}
+	}
+
+	return numRows, nil
+}
+
+// readValues reads values of the current page of the column into buffer,
+// moving to the next page when all the values of the current page were read.
+func (f *scanField) readValues(buffer []Value) ([]Value, error) {
+	for {
+		if f.values == nil {
+			p, err := f.pages.ReadPage()
+			if err != nil {
+				if err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				return nil, fmt.Errorf("reading parquet column %d: %w", f.column, err)
+			}
+			f.values = p.Values()
+		}
+		n, err := f.values.ReadValues(buffer)
+		if err != nil {
+			if err != io.EOF {
+				return nil, fmt.Errorf("reading parquet column %d: %w", f.column, err)
+			}
+			f.values = nil
+		}
+		if n > 0 {
+			return buffer[:n], nil
+		}
+	}
+}
+
+func (s *RowGroupScanner) scanRows(rows reflect.Value) (n int, err error) {
+	for n < rows.Len() {
+		if s.row, err = s.rows.ReadRow(s.row[:0]); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			break
+		}
+		row := rows.Index(n)
+		if row.Kind() != reflect.Ptr {
+			row = row.Addr()
+		}
+		if err = s.schema.Reconstruct(row.Interface(), s.row); err != nil {
+			break
+		}
+		n++
+	}
+	return n, err
+}
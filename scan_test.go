@@ -0,0 +1,228 @@
+package parquet_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+)
+
+type scanRecord struct {
+	ID        int64     `parquet:"id"`
+	Name      string    `parquet:"name"`
+	Email     *string   `parquet:"email"`
+	Score     float64   `parquet:"score"`
+	Active    bool      `parquet:"active"`
+	Country   string    `parquet:"country,dict"`
+	CreatedAt time.Time `parquet:"created_at"`
+}
+
+func makeScanRecords(n int) []scanRecord {
+	records := make([]scanRecord, n)
+	for i := range records {
+		records[i] = scanRecord{
+			ID:        int64(i),
+			Name:      fmt.Sprintf("user-%d", i),
+			Score:     float64(i) / 4,
+			Active:    i%3 == 0,
+			Country:   []string{"FR", "US", "JP"}[i%3],
+			CreatedAt: time.Unix(int64(i), 0).UTC(),
+		}
+		if i%2 == 0 {
+			email := fmt.Sprintf("user-%d@example.com", i)
+			records[i].Email = &email
+		}
+	}
+	return records
+}
+
+func writeScanRecords(t testing.TB, records []scanRecord, options ...parquet.WriterOption) *parquet.File {
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, options...)
+	for i := range records {
+		if err := writer.Write(&records[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func scanFile(t *testing.T, f *parquet.File, rows interface{}, batchSize int) interface{} {
+	rowsType := reflect.TypeOf(rows)
+	result := reflect.MakeSlice(rowsType, 0, 0)
+
+	for _, rowGroup := range f.RowGroups() {
+		scanner := parquet.NewRowGroupScanner(rowGroup)
+		for {
+			batch := reflect.MakeSlice(rowsType, batchSize, batchSize)
+			n, err := scanner.Scan(batch.Interface())
+			result = reflect.AppendSlice(result, batch.Slice(0, n))
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := scanner.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return result.Interface()
+}
+
+func TestRowGroupScanner(t *testing.T) {
+	records := makeScanRecords(1000)
+	f := writeScanRecords(t, records, parquet.PageBufferSize(256), parquet.MaxRowsPerRowGroup(300))
+
+	for _, batchSize := range []int{1, 7, 100, 1000} {
+		t.Run(fmt.Sprintf("batch=%d", batchSize), func(t *testing.T) {
+			got := scanFile(t, f, []scanRecord{}, batchSize).([]scanRecord)
+			if !reflect.DeepEqual(got, records) {
+				t.Error("records scanned from the file do not match the records that were written")
+			}
+		})
+	}
+
+	t.Run("pointers", func(t *testing.T) {
+		got := scanFile(t, f, []*scanRecord{}, 64).([]*scanRecord)
+		if len(got) != len(records) {
+			t.Fatalf("wrong number of records: want=%d got=%d", len(records), len(got))
+		}
+		for i := range got {
+			if !reflect.DeepEqual(*got[i], records[i]) {
+				t.Fatalf("wrong record at index %d:\nwant: %+v\ngot:  %+v", i, records[i], *got[i])
+			}
+		}
+	})
+}
+
+func TestRowGroupScannerProjection(t *testing.T) {
+	type projection struct {
+		Name    string `parquet:"name"`
+		Email   *string
+		Missing int32 `parquet:"missing"`
+	}
+
+	records := makeScanRecords(10)
+	f := writeScanRecords(t, records)
+
+	scanner := parquet.NewRowGroupScanner(f.RowGroups()[0])
+	defer scanner.Close()
+
+	rows := make([]projection, len(records))
+	for i := range rows {
+		rows[i].Missing = 42
+	}
+	n, err := scanner.Scan(rows)
+	if err != io.EOF {
+		t.Fatalf("scanning all the rows did not return io.EOF: %v", err)
+	}
+	if n != len(records) {
+		t.Fatalf("wrong number of rows: want=%d got=%d", len(records), n)
+	}
+
+	for i, row := range rows {
+		if row.Name != records[i].Name {
+			t.Errorf("wrong name at index %d: want=%q got=%q", i, records[i].Name, row.Name)
+		}
+		if row.Email != nil {
+			t.Errorf("field which is not a column of the file was set at index %d: %q", i, *row.Email)
+		}
+		if row.Missing != 0 {
+			t.Errorf("missing column was not zeroed at index %d: %d", i, row.Missing)
+		}
+	}
+
+	if _, err := scanner.Scan(make([]scanRecord, 1)); err == nil {
+		t.Error("scanning rows of a different type did not fail")
+	}
+}
+
+func TestRowGroupScannerNestedRows(t *testing.T) {
+	type Contact struct {
+		Name  string `parquet:"name"`
+		Phone string `parquet:"phone,optional"`
+	}
+	type AddressBook struct {
+		Owner    string    `parquet:"owner"`
+		Contacts []Contact `parquet:"contacts"`
+	}
+
+	books := []AddressBook{
+		{Owner: "Luke", Contacts: []Contact{{Name: "Leia", Phone: "555"}, {Name: "Han"}}},
+		{Owner: "Leia"},
+		{Owner: "Han", Contacts: []Contact{{Name: "Chewie"}}},
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	for i := range books {
+		if err := writer.Write(&books[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := make([]AddressBook, len(books))
+	if n, err := parquet.NewReader(f).ReadRows(want); n != len(books) {
+		t.Fatalf("wrong number of rows read: want=%d got=%d (%v)", len(books), n, err)
+	}
+
+	got := scanFile(t, f, []AddressBook{}, 2).([]AddressBook)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong rows scanned from the file:\nwant: %+v\ngot:  %+v", want, got)
+	}
+}
+
+func BenchmarkRowGroupScanner(b *testing.B) {
+	const numRows = 10e3
+	records := makeScanRecords(numRows)
+	f := writeScanRecords(b, records)
+	rows := make([]scanRecord, 1000)
+
+	b.Run("Reader.ReadRows", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			reader := parquet.NewReader(f)
+			for {
+				if _, err := reader.ReadRows(rows); err != nil {
+					break
+				}
+			}
+		}
+		b.SetBytes(numRows)
+	})
+
+	b.Run("RowGroupScanner.Scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, rowGroup := range f.RowGroups() {
+				scanner := parquet.NewRowGroupScanner(rowGroup)
+				for {
+					if _, err := scanner.Scan(rows); err != nil {
+						break
+					}
+				}
+				scanner.Close()
+			}
+		}
+		b.SetBytes(numRows)
+	})
+}
@@ -1,12 +1,14 @@
 package parquet
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/segmentio/parquet-go/compress"
@@ -21,6 +23,7 @@ import (
 type Schema struct {
 	name        string
 	root        Node
+	funcs       sync.Once
 	deconstruct deconstructFunc
 	reconstruct reconstructFunc
 	readRow     columnReadRowFunc
@@ -53,10 +56,17 @@ type Schema struct {
 //	enum      | for string types, use the parquet ENUM logical type
 //	uuid      | for string and [16]byte types, use the parquet UUID logical type
 //	decimal   | for int32, int64 and [n]byte types, use the parquet DECIMAL logical type
-//	date      | for int32 types use the DATE logical type
-//	timestamp | for int64 types use the TIMESTAMP logical type with millisecond precision
+//	date      | for int32, time.Time and CivilDate types use the DATE logical type
+//	timestamp | for int64 and time.Time types use the TIMESTAMP logical type
+//	time      | for int32, int64 and time.Duration types use the TIME logical type
+//	depth     | for fields of recursive types, the number of times the field is unrolled
+//	id        | sets the field id of the parquet column, for example id(3)
 //
-// The date logical type is an int32 value of the number of days since the unix epoch
+// # The date logical type is an int32 value of the number of days since the unix epoch
+//
+// Dates are written from time.Time values in their location, and read back as
+// time.Time values at midnight in the UTC location. Fields of type CivilDate
+// use the DATE logical type without a date tag.
 //
 // The decimal tag must be followed by two integer parameters, the first integer
 // representing the scale and the second the precision; for example:
@@ -65,6 +75,51 @@ type Schema struct {
 //		Cost int64 `parquet:"cost,decimal(0:3)"`
 //	}
 //
+// The time tag may be followed by the unit of values, one of millis, micros, or
+// nanos. Int32 values are always expressed in milliseconds, the default unit
+// is microseconds for int64 values and nanoseconds for time.Duration values.
+// When the unit of a time.Duration field is not nanoseconds, its values are
+// converted to the unit when writing and back to durations when reading:
+//
+//	type Trip struct {
+//		Departure time.Duration `parquet:"departure,time(millis)"`
+//	}
+//
+// The timestamp tag may be followed by the unit of values, one of millis,
+// micros, or nanos, defaulting to milliseconds for int64 values and to
+// microseconds for time.Time values. The local parameter writes timestamps
+// that are not adjusted to UTC: time.Time values are written as their wall
+// clock reading, and read back in the local time zone. Parameters are
+// separated by colons:
+//
+//	type Event struct {
+//		Time    time.Time `parquet:"time,timestamp(millis)"`
+//		Created time.Time `parquet:"created,timestamp(micros:local)"`
+//	}
+//
+// Fields of type time.Time without a timestamp tag use the TIMESTAMP logical
+// type with microsecond precision, adjusted to UTC. Programs that need to
+// preserve the nanosecond precision of time.Time values must use the nanos
+// unit, for example:
+//
+//	type Span struct {
+//		Start time.Time  `parquet:"start,timestamp(nanos)"`
+//		End   *time.Time `parquet:"end,timestamp(nanos)"`
+//	}
+//
+// When reading timestamps into fields of a different unit, the values are
+// converted to the unit of the fields, truncating values of finer precision.
+//
+// Fields of recursive types must have a depth tag declaring how many levels of
+// the recursion are represented in the schema; deeper values are not written,
+// and read back as zero values. When types are mutually recursive, one of the
+// fields forming the cycle needs the tag:
+//
+//	type Tree struct {
+//		Name     string `parquet:"name"`
+//		Children []Tree `parquet:"children,depth(3)"`
+//	}
+//
 // Invalid combination of struct tags and Go types, or repeating options will
 // cause the function to panic.
 //
@@ -84,7 +139,7 @@ func schemaOf(model reflect.Type) *Schema {
 	if model.Kind() != reflect.Struct {
 		panic("cannot construct parquet schema from value of type " + model.String())
 	}
-	schema = NewSchema(model.Name(), nodeOf(model))
+	schema = NewSchema(model.Name(), nodeOf(model, new(structPath)))
 	if actual, loaded := cachedSchemas.LoadOrStore(model, schema); loaded {
 		schema = actual.(*Schema)
 	}
@@ -98,14 +153,23 @@ func schemaOf(model reflect.Type) *Schema {
 func NewSchema(name string, root Node) *Schema {
 	_ = numLeafColumnsOf(root)
 	return &Schema{
-		name:        name,
-		root:        root,
-		deconstruct: makeDeconstructFunc(root),
-		reconstruct: makeReconstructFunc(root),
-		readRow:     makeColumnReadRowFunc(root),
+		name: name,
+		root: root,
 	}
 }
 
+// lazyLoadFuncs constructs the functions used to convert Go values and rows of
+// the schema. They are not constructed in NewSchema so invalid schemas can be
+// reported by Validate rather than causing a panic.
+func (s *Schema) lazyLoadFuncs() *Schema {
+	s.funcs.Do(func() {
+		s.deconstruct = makeDeconstructFunc(s.root)
+		s.reconstruct = makeReconstructFunc(s.root)
+		s.readRow = makeColumnReadRowFunc(s.root)
+	})
+	return s
+}
+
 func dereference(t reflect.Type) reflect.Type {
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
@@ -115,7 +179,7 @@ func dereference(t reflect.Type) reflect.Type {
 
 func makeDeconstructFunc(node Node) (deconstruct deconstructFunc) {
 	if schema, _ := node.(*Schema); schema != nil {
-		return schema.deconstruct
+		return schema.lazyLoadFuncs().deconstruct
 	}
 	if !isLeaf(node) {
 		_, deconstruct = deconstructFuncOf(0, node)
@@ -125,7 +189,7 @@ func makeDeconstructFunc(node Node) (deconstruct deconstructFunc) {
 
 func makeReconstructFunc(node Node) (reconstruct reconstructFunc) {
 	if schema, _ := node.(*Schema); schema != nil {
-		return schema.reconstruct
+		return schema.lazyLoadFuncs().reconstruct
 	}
 	if !isLeaf(node) {
 		_, reconstruct = reconstructFuncOf(0, node)
@@ -156,6 +220,67 @@ func (s *Schema) ConfigureWriter(config *WriterConfig) { config.Schema = s }
 // String returns a parquet schema representation of s.
 func (s *Schema) String() string { return sprint(s.name, s.root) }
 
+// MarshalJSON returns a JSON representation of s, suitable for storing the
+// schema in registries or comparing schemas with diff tools. The output is
+// stable: nodes are listed in the order of the schema columns, and the same
+// schema always produces the same JSON document, for example:
+//
+//	{
+//	  "name": "AddressBook",
+//	  "fields": [
+//	    {"name": "contacts", "repetition": "REPEATED", "fields": [...]},
+//	    {"name": "id", "repetition": "OPTIONAL", "type": "FIXED_LEN_BYTE_ARRAY", "length": 16, "logicalType": "UUID", "fieldId": 2},
+//	    {"name": "owner", "repetition": "REQUIRED", "type": "BYTE_ARRAY", "logicalType": "STRING"}
+//	  ]
+//	}
+//
+// Leaf nodes have the "type" property holding their physical type, group nodes
+// have the "fields" property listing their children instead. The "length" of
+// FIXED_LEN_BYTE_ARRAY types, the "logicalType" annotation, and the "fieldId"
+// properties are omitted when they do not apply to a node.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	root := schemaJSONOf(s.name, s.root)
+	root.Repetition = ""
+	return json.Marshal(root)
+}
+
+type schemaJSON struct {
+	Name        string        `json:"name"`
+	Repetition  string        `json:"repetition,omitempty"`
+	Type        string        `json:"type,omitempty"`
+	Length      int           `json:"length,omitempty"`
+	LogicalType string        `json:"logicalType,omitempty"`
+	FieldID     int           `json:"fieldId,omitempty"`
+	Fields      *[]schemaJSON `json:"fields,omitempty"`
+}
+
+func schemaJSONOf(name string, node Node) schemaJSON {
+	s := schemaJSON{
+		Name:        name,
+		LogicalType: annotationOf(node),
+		FieldID:     fieldIDOf(node),
+	}
+	if repetition := fieldRepetitionTypeOf(node); repetition != nil {
+		s.Repetition = repetition.String()
+	}
+
+	if isLeaf(node) {
+		t := node.Type()
+		s.Type = t.Kind().String()
+		if t.Kind() == FixedLenByteArray {
+			s.Length = t.Length()
+		}
+	} else {
+		fields := make([]schemaJSON, 0, node.NumChildren())
+		for _, name := range node.ChildNames() {
+			fields = append(fields, schemaJSONOf(name, node.ChildByName(name)))
+		}
+		s.Fields = &fields
+	}
+
+	return s
+}
+
 // Name returns the name of s.
 func (s *Schema) Name() string { return s.name }
 
@@ -194,12 +319,121 @@ func (s *Schema) ValueByName(base reflect.Value, name string) reflect.Value {
 	return s.root.ValueByName(base, name)
 }
 
+// Select returns a schema containing only the columns of s at the given paths,
+// and the groups that they are nested in. The paths may designate leaf columns
+// or groups, in which case all the columns of the group are selected. The
+// selected nodes retain their repetition, logical types, and field ids.
+//
+// The schema may be used to read a subset of the columns of files written with
+// s, or as target of a conversion with Convert:
+//
+//	projection, err := schema.Select([]string{"id"}, []string{"address", "city"})
+//	if err != nil {
+//		...
+//	}
+//	reader := parquet.NewReader(file, projection)
+//
+// The method returns an error if one of the paths does not exist in s.
+func (s *Schema) Select(paths ...[]string) (*Schema, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("cannot select zero columns of parquet schema")
+	}
+	root, _, err := selectNode(s.root, nil, paths)
+	if err != nil {
+		return nil, err
+	}
+	return NewSchema(s.name, root), nil
+}
+
+// selectNode returns node with only the children at the given paths, which
+// must all have path as prefix. The function returns true if all the columns
+// of node were selected, in which case the node itself is returned.
+func selectNode(node Node, path columnPath, paths [][]string) (Node, bool, error) {
+	selected := make(map[string][][]string)
+
+	for _, p := range paths {
+		if len(p) == len(path) {
+			return node, true, nil
+		}
+		name := p[len(path)]
+		if isLeaf(node) || node.ChildByName(name) == nil {
+			return nil, false, fmt.Errorf("cannot select column %q which does not exist in the parquet schema", columnPath(p))
+		}
+		selected[name] = append(selected[name], p)
+	}
+
+	allSelected := len(selected) == node.NumChildren()
+	children := make(map[string]Node, len(selected))
+	for name, paths := range selected {
+		child, all, err := selectNode(node.ChildByName(name), path.append(name), paths)
+		if err != nil {
+			return nil, false, err
+		}
+		children[name] = child
+		allSelected = allSelected && all
+	}
+	if allSelected {
+		return node, true, nil
+	}
+
+	names := make([]string, 0, len(children))
+	for _, name := range node.ChildNames() {
+		if _, ok := children[name]; ok {
+			names = append(names, name)
+		}
+	}
+	return &selectedNode{Node: node, names: names, children: children}, false, nil
+}
+
+// selectedNode is a wrapper of group nodes retaining a subset of their
+// children, the properties of the group (e.g. its repetition and logical type)
+// are retained.
+//
+// The type does not implement WrappedNode since the children of the
+// underlying node, which may be indexed, differ from the selected ones.
+type selectedNode struct {
+	Node
+	names    []string
+	children map[string]Node
+}
+
+func (n *selectedNode) ID() int { return fieldIDOf(n.Node) }
+
+func (n *selectedNode) String() string { return sprint("", n) }
+
+func (n *selectedNode) NumChildren() int { return len(n.names) }
+
+func (n *selectedNode) ChildNames() []string { return n.names }
+
+func (n *selectedNode) ChildByName(name string) Node { return n.children[name] }
+
+func (n *selectedNode) Encoding() []encoding.Encoding {
+	encodings := make([]encoding.Encoding, 0, len(n.names))
+	for _, name := range n.names {
+		encodings = append(encodings, n.children[name].Encoding()...)
+	}
+	sortEncodings(encodings)
+	return dedupeSortedEncodings(encodings)
+}
+
+func (n *selectedNode) Compression() []compress.Codec {
+	codecs := make([]compress.Codec, 0, len(n.names))
+	for _, name := range n.names {
+		codecs = append(codecs, n.children[name].Compression()...)
+	}
+	sortCodecs(codecs)
+	return dedupeSortedCodecs(codecs)
+}
+
 // Deconstruct deconstructs a Go value and appends it to a row.
 //
 // The method panics is the structure of the go value does not match the
 // parquet schema.
 func (s *Schema) Deconstruct(row Row, value interface{}) Row {
-	v := reflect.ValueOf(value)
+	return s.deconstructValue(row, reflect.ValueOf(value))
+}
+
+func (s *Schema) deconstructValue(row Row, v reflect.Value) Row {
 	if v.Kind() == reflect.Ptr {
 		if v.IsNil() {
 			v = reflect.Value{}
@@ -207,8 +441,8 @@ func (s *Schema) Deconstruct(row Row, value interface{}) Row {
 			v = v.Elem()
 		}
 	}
-	if s.deconstruct != nil {
-		row = s.deconstruct(row, levels{}, v)
+	if deconstruct := s.lazyLoadFuncs().deconstruct; deconstruct != nil {
+		row = deconstruct(row, levels{}, v)
 	}
 	return row
 }
@@ -232,8 +466,8 @@ func (s *Schema) Reconstruct(value interface{}, row Row) error {
 		panic("cannot reconstruct row into nil pointer of type " + v.Type().String())
 	}
 	var err error
-	if s.reconstruct != nil {
-		row, err = s.reconstruct(v.Elem(), levels{}, row)
+	if reconstruct := s.lazyLoadFuncs().reconstruct; reconstruct != nil {
+		row, err = reconstruct(v.Elem(), levels{}, row)
 		if len(row) > 0 && err == nil {
 			err = fmt.Errorf("%d values remain unused after reconstructing go value of type %s from parquet row", len(row), v.Type())
 		}
@@ -261,25 +495,141 @@ type structNode struct {
 	names  []string
 }
 
-func structNodeOf(t reflect.Type) *structNode {
+func structNodeOf(t reflect.Type, path *structPath) *structNode {
 	// Collect struct fields first so we can order them before generating the
 	// column indexes.
 	fields := structFieldsOf(t)
 
 	s := &structNode{
 		gotype: t,
-		fields: make([]structField, len(fields)),
-		names:  make([]string, len(fields)),
+		fields: make([]structField, 0, len(fields)),
+		names:  make([]string, 0, len(fields)),
 	}
 
-	for i := range fields {
-		s.fields[i] = makeStructField(fields[i])
-		s.names[i] = fields[i].Name
+	path.types = append(path.types, t)
+	defer func() { path.types = path.types[:len(path.types)-1] }()
+
+	for _, f := range fields {
+		unroll, done := path.unroll(t, f)
+		if !unroll {
+			continue
+		}
+		s.fields = append(s.fields, makeStructField(f, path))
+		s.names = append(s.names, f.Name)
+		done()
 	}
 
 	return s
 }
 
+// structPath tracks the struct types that are being converted to parquet
+// nodes, and how many more times the fields of recursive types can be
+// unrolled.
+type structPath struct {
+	types []reflect.Type
+	depth map[recursiveField]int
+}
+
+type recursiveField struct {
+	gotype reflect.Type
+	name   string
+}
+
+// unroll returns whether the field f of the struct type t must be part of the
+// schema, and a function to call once the node of the field was generated.
+//
+// Fields which refer to one of the struct types being converted must have a
+// depth tag, they are unrolled as many times as the tag declares and omitted
+// from the schema beyond that. When types are mutually recursive, only one of
+// the fields forming the cycle needs a depth tag.
+func (p *structPath) unroll(t reflect.Type, f reflect.StructField) (bool, func()) {
+	cycle := p.cycleOf(f.Type)
+	if cycle < 0 {
+		return true, func() {}
+	}
+	key := recursiveField{gotype: t, name: f.Name}
+	depth, unrolling := p.depth[key]
+	if !unrolling {
+		var ok bool
+		if depth, ok = structFieldDepthOf(f); !ok {
+			if p.isUnrolling(cycle) {
+				return true, func() {}
+			}
+			throwInvalidStructField("struct field of recursive type has no depth tag", f)
+		}
+	}
+	if depth == 0 {
+		return false, nil
+	}
+	if p.depth == nil {
+		p.depth = make(map[recursiveField]int)
+	}
+	p.depth[key] = depth - 1
+	return true, func() {
+		if unrolling {
+			p.depth[key] = depth
+		} else {
+			delete(p.depth, key)
+		}
+	}
+}
+
+// cycleOf returns the position of the first struct type being converted that
+// values of type t hold, or -1 if t is not recursive.
+func (p *structPath) cycleOf(t reflect.Type) int {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return p.cycleOf(t.Elem())
+	case reflect.Map:
+		if i := p.cycleOf(t.Key()); i >= 0 {
+			return i
+		}
+		return p.cycleOf(t.Elem())
+	case reflect.Struct:
+		for i, s := range p.types {
+			if s == t {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// isUnrolling returns true if a field of the struct types being converted from
+// the given position is being unrolled.
+func (p *structPath) isUnrolling(cycle int) bool {
+	for key := range p.depth {
+		for _, t := range p.types[cycle:] {
+			if key.gotype == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// structFieldDepthOf returns the argument of the depth option in the parquet
+// tag of f.
+func structFieldDepthOf(f reflect.StructField) (depth int, ok bool) {
+	tag := f.Tag.Get("parquet")
+	_, tag = split(tag) // skip the field name
+
+	for tag != "" {
+		option := ""
+		option, tag = split(tag)
+		option, args := splitOptionArgs(option)
+
+		if option == "depth" {
+			depth, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(args, "("), ")"))
+			if err != nil || depth < 0 {
+				throwInvalidFieldTag(f, option+args)
+			}
+			return depth, true
+		}
+	}
+	return 0, false
+}
+
 func structFieldsOf(t reflect.Type) []reflect.StructField {
 	fields := appendStructFields(t, nil, nil)
 
@@ -336,7 +686,12 @@ func (s *structNode) NumChildren() int { return len(s.fields) }
 
 func (s *structNode) ChildNames() []string { return s.names }
 
-func (s *structNode) ChildByName(name string) Node { return s.ChildByIndex(s.indexOf(name)) }
+func (s *structNode) ChildByName(name string) Node {
+	if i := s.indexOf(name); i >= 0 {
+		return s.ChildByIndex(i)
+	}
+	return nil
+}
 
 func (s *structNode) ChildByIndex(index int) Node { return &s.fields[index] }
 
@@ -410,11 +765,12 @@ func throwInvalidStructField(msg string, field reflect.StructField) {
 	panic(msg + ": " + structFieldString(field))
 }
 
-func makeStructField(f reflect.StructField) structField {
+func makeStructField(f reflect.StructField, path *structPath) structField {
 	var (
 		field     = structField{index: f.Index}
 		optional  bool
 		list      bool
+		fieldID   int
 		encodings []encoding.Encoding
 		codecs    []compress.Codec
 	)
@@ -517,10 +873,25 @@ func makeStructField(f reflect.StructField) structField {
 					throwInvalidFieldTag(f, option)
 				}
 
+			case "id":
+				if fieldID != 0 {
+					throwInvalidStructField("struct field has multiple declaration of the id tag", f)
+				}
+				id, err := parseFieldIDArgs(args)
+				if err != nil {
+					throwInvalidFieldTag(f, option+args)
+				}
+				fieldID = id
+
+			case "depth":
+				// The depth of recursive fields is applied when generating
+				// the parent struct node, it only needs to be valid here.
+				structFieldDepthOf(f)
+
 			case "list":
 				switch f.Type.Kind() {
 				case reflect.Slice:
-					element = nodeOf(f.Type.Elem())
+					element = listElementNodeOf(f.Type.Elem(), path)
 					setNode(element)
 					setList()
 				default:
@@ -564,19 +935,57 @@ func makeStructField(f reflect.StructField) structField {
 
 				setNode(Decimal(scale, precision, baseType))
 			case "date":
-				switch f.Type.Kind() {
-				case reflect.Int32:
+				switch {
+				case f.Type.Kind() == reflect.Int32, f.Type == timeTime, f.Type == civilDateType:
 					setNode(Date())
 				default:
 					throwInvalidFieldTag(f, option)
 				}
 			case "timestamp":
+				unit, adjustedToUTC, err := parseTimestampArgs(args)
+				if err != nil {
+					throwInvalidFieldTag(f, option+args)
+				}
+				switch {
+				case f.Type == timeTime, f.Type == reflect.PtrTo(timeTime):
+					if unit == nil {
+						unit = Microsecond
+					}
+					// Pointers to time.Time values are optional, the same
+					// way they are when the field has no timestamp tag.
+					optional = optional || f.Type.Kind() == reflect.Ptr
+				case f.Type.Kind() == reflect.Int64:
+					if unit == nil {
+						unit = Millisecond
+					}
+				default:
+					throwInvalidFieldTag(f, option)
+				}
+				setNode(TimestampAdjusted(unit, adjustedToUTC))
+			case "time":
+				unit, err := parseTimeUnitArgs(args)
+				if err != nil {
+					throwInvalidFieldTag(f, option+args)
+				}
 				switch f.Type.Kind() {
+				case reflect.Int32:
+					// Only the millisecond unit fits in 32 bits.
+					if unit != nil && unit != Millisecond {
+						throwInvalidFieldTag(f, option+args)
+					}
+					unit = Millisecond
 				case reflect.Int64:
-					setNode(Timestamp(Millisecond))
+					if unit == nil {
+						if f.Type == reflect.TypeOf(time.Duration(0)) {
+							unit = Nanosecond
+						} else {
+							unit = Microsecond
+						}
+					}
 				default:
 					throwInvalidFieldTag(f, option)
 				}
+				setNode(Time(unit))
 			default:
 				throwUnknownFieldTag(f, option)
 			}
@@ -584,7 +993,7 @@ func makeStructField(f reflect.StructField) structField {
 	}
 
 	if field.Node == nil {
-		field.Node = nodeOf(f.Type)
+		field.Node = nodeOf(f.Type, path)
 	}
 
 	field.Node = Compressed(field.Node, codecs...)
@@ -598,15 +1007,23 @@ func makeStructField(f reflect.StructField) structField {
 		field.Node = Optional(field.Node)
 	}
 
+	if fieldID != 0 {
+		field.Node = FieldID(field.Node, fieldID)
+	}
+
 	return field
 }
 
-func nodeOf(t reflect.Type) Node {
+func nodeOf(t reflect.Type, path *structPath) Node {
 	switch t {
 	case reflect.TypeOf(deprecated.Int96{}):
 		return Leaf(Int96Type)
 	case reflect.TypeOf(uuid.UUID{}):
 		return UUID()
+	case timeTime:
+		return &goNode{wrappedNode: wrap(Timestamp(Microsecond)), gotype: t}
+	case civilDateType:
+		return &goNode{wrappedNode: wrap(Date()), gotype: t}
 	}
 
 	var n Node
@@ -636,13 +1053,19 @@ func nodeOf(t reflect.Type) Node {
 		n = String()
 
 	case reflect.Ptr:
-		n = Optional(nodeOf(t.Elem()))
+		if elem := t.Elem(); isRepeatedSliceType(elem) {
+			n = Optional(List(listElementNodeOf(elem.Elem(), path)))
+		} else {
+			n = Optional(nodeOf(elem, path))
+		}
 
 	case reflect.Slice:
 		if elem := t.Elem(); elem.Kind() == reflect.Uint8 { // []byte?
 			n = Leaf(ByteArrayType)
+		} else if isRepeatedSliceType(elem) {
+			n = List(listElementNodeOf(elem, path))
 		} else {
-			n = Repeated(nodeOf(elem))
+			n = Repeated(nodeOf(elem, path))
 		}
 
 	case reflect.Array:
@@ -651,10 +1074,10 @@ func nodeOf(t reflect.Type) Node {
 		}
 
 	case reflect.Map:
-		n = Map(nodeOf(t.Key()), nodeOf(t.Elem()))
+		n = Map(nodeOf(t.Key(), path), nodeOf(t.Elem(), path))
 
 	case reflect.Struct:
-		return structNodeOf(t)
+		return structNodeOf(t, path)
 	}
 
 	if n == nil {
@@ -664,6 +1087,22 @@ func nodeOf(t reflect.Type) Node {
 	return &goNode{wrappedNode: wrap(n), gotype: t}
 }
 
+// listElementNodeOf returns the node representing the elements of slices of
+// type t. Repeated columns cannot be nested directly in other repeated columns,
+// so slices of slices are represented by nested LIST groups.
+func listElementNodeOf(t reflect.Type, path *structPath) Node {
+	if isRepeatedSliceType(t) {
+		return List(listElementNodeOf(t.Elem(), path))
+	}
+	return nodeOf(t, path)
+}
+
+// isRepeatedSliceType returns true if t is a slice type represented by a
+// repeated column, which is the case of all slices except []byte.
+func isRepeatedSliceType(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8
+}
+
 func split(s string) (head, tail string) {
 	if i := strings.IndexByte(s, ','); i < 0 {
 		head = s
@@ -681,6 +1120,48 @@ func splitOptionArgs(s string) (option, args string) {
 	}
 }
 
+func parseTimeUnitArgs(args string) (unit TimeUnit, err error) {
+	if !strings.HasPrefix(args, "(") || !strings.HasSuffix(args, ")") {
+		return nil, fmt.Errorf("malformed time unit args: %s", args)
+	}
+	switch args = strings.TrimSuffix(strings.TrimPrefix(args, "("), ")"); args {
+	case "":
+		return nil, nil
+	case "millis":
+		return Millisecond, nil
+	case "micros":
+		return Microsecond, nil
+	case "nanos":
+		return Nanosecond, nil
+	default:
+		return nil, fmt.Errorf("malformed time unit args: (%s)", args)
+	}
+}
+
+func parseTimestampArgs(args string) (unit TimeUnit, adjustedToUTC bool, err error) {
+	if !strings.HasPrefix(args, "(") || !strings.HasSuffix(args, ")") {
+		return nil, false, fmt.Errorf("malformed timestamp args: %s", args)
+	}
+	adjustedToUTC = true
+	for _, arg := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(args, "("), ")"), ":") {
+		switch arg {
+		case "":
+		case "utc":
+			adjustedToUTC = true
+		case "local":
+			adjustedToUTC = false
+		default:
+			if unit != nil {
+				return nil, false, fmt.Errorf("malformed timestamp args: %s", args)
+			}
+			if unit, err = parseTimeUnitArgs("(" + arg + ")"); err != nil {
+				return nil, false, fmt.Errorf("malformed timestamp args: %s", args)
+			}
+		}
+	}
+	return unit, adjustedToUTC, nil
+}
+
 func parseDecimalArgs(args string) (scale, precision int, err error) {
 	if !strings.HasPrefix(args, "(") || !strings.HasSuffix(args, ")") {
 		return 0, 0, fmt.Errorf("malformed decimal args: %s", args)
@@ -702,6 +1183,20 @@ func parseDecimalArgs(args string) (scale, precision int, err error) {
 	return int(s), int(p), nil
 }
 
+func parseFieldIDArgs(args string) (int, error) {
+	if !strings.HasPrefix(args, "(") || !strings.HasSuffix(args, ")") {
+		return 0, fmt.Errorf("malformed field id args: %s", args)
+	}
+	id, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(args, "("), ")"), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	if id <= 0 {
+		return 0, fmt.Errorf("field id must be positive: %d", id)
+	}
+	return int(id), nil
+}
+
 type goNode struct {
 	wrappedNode
 	gotype reflect.Type
@@ -0,0 +1,204 @@
+package parquet
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SchemaDifferenceKind enumerates the kinds of differences reported by
+// CompareSchemas.
+type SchemaDifferenceKind int
+
+const (
+	// ColumnAdded is the kind of differences reporting a column which exists
+	// in the second schema but not in the first.
+	ColumnAdded SchemaDifferenceKind = iota
+	// ColumnRemoved is the kind of differences reporting a column which exists
+	// in the first schema but not in the second.
+	ColumnRemoved
+	// ColumnRetyped is the kind of differences reporting a column which has
+	// different physical or logical types in the two schemas, or which is a
+	// leaf in one schema and a group in the other.
+	ColumnRetyped
+	// ColumnRenamed is the kind of differences reporting a column which has
+	// different names in the two schemas. Columns are only detected as renamed
+	// when they have the same field id, columns without field ids are reported
+	// as removed and added instead.
+	ColumnRenamed
+	// RepetitionChanged is the kind of differences reporting a column which
+	// has different repetition types in the two schemas.
+	RepetitionChanged
+)
+
+// String returns a human-readable representation of k.
+func (k SchemaDifferenceKind) String() string {
+	switch k {
+	case ColumnAdded:
+		return "added"
+	case ColumnRemoved:
+		return "removed"
+	case ColumnRetyped:
+		return "retyped"
+	case ColumnRenamed:
+		return "renamed"
+	case RepetitionChanged:
+		return "repetition changed"
+	default:
+		return fmt.Sprintf("SchemaDifferenceKind(%d)", int(k))
+	}
+}
+
+// SchemaDifference represents a difference between two schemas reported by
+// CompareSchemas.
+type SchemaDifference struct {
+	// The kind of difference.
+	Kind SchemaDifferenceKind
+	// The path of the column in the first schema, nil if the column was added.
+	OldPath []string
+	// The path of the column in the second schema, nil if the column was
+	// removed.
+	NewPath []string
+	// The column in the first schema, nil if the column was added.
+	Old Node
+	// The column in the second schema, nil if the column was removed.
+	New Node
+}
+
+// Path returns the path of the column that the difference applies to, which
+// is the path in the second schema unless the column was removed.
+func (d *SchemaDifference) Path() []string {
+	if d.NewPath != nil {
+		return d.NewPath
+	}
+	return d.OldPath
+}
+
+// String returns a human-readable representation of d, for example:
+//
+//	retyped column "name": required int64 name (INT(64,true)) => required binary name (STRING)
+func (d *SchemaDifference) String() string {
+	switch d.Kind {
+	case ColumnAdded:
+		return fmt.Sprintf("added column %q: %s", columnPath(d.NewPath), describeNode(d.NewPath, d.New))
+	case ColumnRemoved:
+		return fmt.Sprintf("removed column %q: %s", columnPath(d.OldPath), describeNode(d.OldPath, d.Old))
+	case ColumnRenamed:
+		return fmt.Sprintf("renamed column %q to %q", columnPath(d.OldPath), columnPath(d.NewPath))
+	default:
+		return fmt.Sprintf("%s column %q: %s => %s", d.Kind, columnPath(d.Path()), describeNode(d.OldPath, d.Old), describeNode(d.NewPath, d.New))
+	}
+}
+
+// describeNode returns the one-line parquet schema representation of the
+// node at the given path.
+func describeNode(path []string, node Node) string {
+	name := ""
+	if len(path) > 0 {
+		name = path[len(path)-1]
+	}
+	if !isLeaf(node) {
+		return repetitionOf(node) + " group " + name
+	}
+	s := new(strings.Builder)
+	printWithIndent(&printWriter{writer: s}, name, node, &printIndent{})
+	return strings.TrimSuffix(s.String(), ";")
+}
+
+// CompareSchemas returns the list of differences between the schemas a and b,
+// which may be used to verify that changes to a schema are compatible with the
+// files or applications using it.
+//
+// The nodes of the two schemas are matched by name, or by field id when their
+// names differ. The differences are reported in the order of the columns of a,
+// followed by the columns only present in b. Columns which were added or
+// removed are reported once, the differences of their children are not listed.
+//
+// The function returns nil if the schemas are equivalent.
+func CompareSchemas(a, b Node) []SchemaDifference {
+	return compareSchemaNodes(nil, nil, nil, a, b)
+}
+
+func compareSchemaNodes(diffs []SchemaDifference, oldPath, newPath columnPath, a, b Node) []SchemaDifference {
+	if len(oldPath) > 0 && len(newPath) > 0 {
+		if oldPath[len(oldPath)-1] != newPath[len(newPath)-1] {
+			diffs = append(diffs, SchemaDifference{Kind: ColumnRenamed, OldPath: oldPath, NewPath: newPath, Old: a, New: b})
+		}
+		if repetitionOf(a) != repetitionOf(b) {
+			diffs = append(diffs, SchemaDifference{Kind: RepetitionChanged, OldPath: oldPath, NewPath: newPath, Old: a, New: b})
+		}
+	}
+
+	leafA, leafB := isLeaf(a), isLeaf(b)
+	if leafA || leafB {
+		if leafA != leafB || !schemaLeafTypesAreEqual(a.Type(), b.Type()) {
+			diffs = append(diffs, SchemaDifference{Kind: ColumnRetyped, OldPath: oldPath, NewPath: newPath, Old: a, New: b})
+		}
+		return diffs
+	}
+
+	if annotationOf(a) != annotationOf(b) {
+		diffs = append(diffs, SchemaDifference{Kind: ColumnRetyped, OldPath: oldPath, NewPath: newPath, Old: a, New: b})
+	}
+
+	namesA, namesB := a.ChildNames(), b.ChildNames()
+	inA := make(map[string]bool, len(namesA))
+	for _, name := range namesA {
+		inA[name] = true
+	}
+	matched := make(map[string]bool, len(namesB))
+	for _, name := range namesB {
+		matched[name] = false
+	}
+
+	// Children of b which have a field id but no match by name may be renamed
+	// children of a.
+	renamed := make(map[int]string)
+	for _, name := range namesB {
+		if inA[name] {
+			continue
+		}
+		if id := fieldIDOf(b.ChildByName(name)); id != 0 {
+			renamed[id] = name
+		}
+	}
+
+	for _, name := range namesA {
+		childA := a.ChildByName(name)
+		nameB, found := name, false
+		if _, found = matched[name]; !found {
+			if id := fieldIDOf(childA); id != 0 {
+				nameB, found = renamed[id]
+			}
+		}
+		if !found || matched[nameB] {
+			diffs = append(diffs, SchemaDifference{Kind: ColumnRemoved, OldPath: oldPath.append(name), Old: childA})
+			continue
+		}
+		matched[nameB] = true
+		diffs = compareSchemaNodes(diffs, oldPath.append(name), newPath.append(nameB), childA, b.ChildByName(nameB))
+	}
+
+	for _, name := range namesB {
+		if !matched[name] {
+			diffs = append(diffs, SchemaDifference{Kind: ColumnAdded, NewPath: newPath.append(name), New: b.ChildByName(name)})
+		}
+	}
+
+	return diffs
+}
+
+func schemaLeafTypesAreEqual(a, b Type) bool {
+	if a.Kind() != b.Kind() {
+		return false
+	}
+	if a.Kind() == FixedLenByteArray && a.Length() != b.Length() {
+		return false
+	}
+	lta, ltb := a.LogicalType(), b.LogicalType()
+	switch {
+	case lta == nil || ltb == nil:
+		return lta == ltb
+	default:
+		return lta.String() == ltb.String()
+	}
+}
@@ -1,9 +1,14 @@
 package parquet_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/format"
 )
 
 func TestSchemaOf(t *testing.T) {
@@ -29,6 +34,33 @@ func TestSchemaOf(t *testing.T) {
 }`,
 		},
 
+		{
+			value: new(struct {
+				Matrix [][]float32 `parquet:"matrix"`
+				Nested [][]string  `parquet:"nested,list,optional"`
+			}),
+			print: `message {
+	required group matrix (LIST) {
+		repeated group list {
+			required group element (LIST) {
+				repeated group list {
+					required float element;
+				}
+			}
+		}
+	}
+	optional group nested (LIST) {
+		repeated group list {
+			required group element (LIST) {
+				repeated group list {
+					required binary element (STRING);
+				}
+			}
+		}
+	}
+}`,
+		},
+
 		{
 			value: new(struct {
 				X float32
@@ -52,6 +84,99 @@ func TestSchemaOf(t *testing.T) {
 		required binary first_name (STRING);
 		required binary last_name (STRING);
 	}
+}`,
+		},
+
+		{
+			value: new(struct {
+				Millis int32         `parquet:"millis,time"`
+				Micros int64         `parquet:"micros,time"`
+				Nanos  int64         `parquet:"nanos,time(nanos)"`
+				Delay  time.Duration `parquet:"delay,time"`
+				Scaled time.Duration `parquet:"scaled,time(millis)"`
+			}),
+			print: `message {
+	required int64 delay (TIME(isAdjustedToUTC=true,unit=NANOS));
+	required int64 micros (TIME(isAdjustedToUTC=true,unit=MICROS));
+	required int32 millis (TIME(isAdjustedToUTC=true,unit=MILLIS));
+	required int64 nanos (TIME(isAdjustedToUTC=true,unit=NANOS));
+	required int32 scaled (TIME(isAdjustedToUTC=true,unit=MILLIS));
+}`,
+		},
+
+		{
+			value: new(struct {
+				Default time.Time  `parquet:"default"`
+				Millis  int64      `parquet:"millis,timestamp"`
+				Local   time.Time  `parquet:"local,timestamp(local)"`
+				Nanos   time.Time  `parquet:"nanos,timestamp(nanos:utc)"`
+				Pointer *time.Time `parquet:"pointer"`
+			}),
+			print: `message {
+	required int64 default (TIMESTAMP(isAdjustedToUTC=true,unit=MICROS));
+	required int64 local (TIMESTAMP(isAdjustedToUTC=false,unit=MICROS));
+	required int64 millis (TIMESTAMP(isAdjustedToUTC=true,unit=MILLIS));
+	required int64 nanos (TIMESTAMP(isAdjustedToUTC=true,unit=NANOS));
+	optional int64 pointer (TIMESTAMP(isAdjustedToUTC=true,unit=MICROS));
+}`,
+		},
+
+		{
+			value: new(struct {
+				Days  int32             `parquet:"days,date"`
+				Time  time.Time         `parquet:"time,date"`
+				Civil parquet.CivilDate `parquet:"civil"`
+			}),
+			print: `message {
+	required int32 civil (DATE);
+	required int32 days (DATE);
+	required int32 time (DATE);
+}`,
+		},
+
+		{
+			value: new(struct {
+				ID   int64    `parquet:"id,id(1)"`
+				Name *string  `parquet:"name,id(2)"`
+				Tags []string `parquet:"tags,list,id(3)"`
+			}),
+			print: `message {
+	required int64 id (INT(64,true)) = 1;
+	optional binary name (STRING) = 2;
+	required group tags (LIST) = 3 {
+		repeated group list {
+			required binary element (STRING);
+		}
+	}
+}`,
+		},
+
+		{
+			value: new(treeNode),
+			print: `message treeNode {
+	repeated group children {
+		repeated group children {
+			required binary name (STRING);
+		}
+		required binary name (STRING);
+	}
+	required binary name (STRING);
+}`,
+		},
+
+		{
+			value: new(department),
+			print: `message department {
+	optional group manager {
+		optional group department {
+			optional group manager {
+				required binary name (STRING);
+			}
+			required binary name (STRING);
+		}
+		required binary name (STRING);
+	}
+	required binary name (STRING);
 }`,
 		},
 	}
@@ -66,3 +191,556 @@ func TestSchemaOf(t *testing.T) {
 		})
 	}
 }
+
+type utf8Int32Type struct{ parquet.Type }
+
+func (utf8Int32Type) LogicalType() *format.LogicalType {
+	return &format.LogicalType{UTF8: new(format.StringType)}
+}
+
+// annotatedGroup is used to construct LIST and MAP columns with invalid layouts.
+type annotatedGroup struct {
+	parquet.Group
+	typ parquet.Type
+}
+
+func (g annotatedGroup) Type() parquet.Type { return g.typ }
+
+type duplicateNames struct{ parquet.Group }
+
+func (duplicateNames) ChildNames() []string { return []string{"a", "a"} }
+
+func TestSchemaValidate(t *testing.T) {
+	tests := []struct {
+		scenario string
+		node     parquet.Node
+		errors   []string
+	}{
+		{
+			scenario: "valid",
+			node: parquet.SchemaOf(struct {
+				Name   string            `parquet:"name"`
+				Tags   []string          `parquet:"tags,list"`
+				Labels map[string]string `parquet:"labels"`
+				Cost   int64             `parquet:"cost,decimal(0:3)"`
+			}{}),
+		},
+
+		{
+			scenario: "empty column name",
+			node:     parquet.Group{"": parquet.String()},
+			errors:   []string{`invalid parquet schema: group has a column with an empty name ""`},
+		},
+
+		{
+			scenario: "duplicate column names",
+			node:     parquet.Group{"group": duplicateNames{parquet.Group{"a": parquet.String()}}},
+			errors:   []string{`invalid parquet schema: group has multiple columns named "a" "group"`},
+		},
+
+		{
+			scenario: "logical type of wrong physical type",
+			node:     parquet.Group{"name": parquet.Leaf(utf8Int32Type{parquet.Int32Type})},
+			errors:   []string{`invalid parquet schema: STRING logical type cannot annotate INT32 values "name"`},
+		},
+
+		{
+			scenario: "required null column",
+			node: parquet.Group{
+				"a": parquet.Optional(parquet.Null(parquet.Int32Type)),
+				"b": parquet.Null(parquet.Int32Type),
+			},
+			errors: []string{`invalid parquet schema: NULL logical type must annotate optional columns "b"`},
+		},
+
+		{
+			scenario: "decimal precision out of range",
+			node: parquet.Group{
+				"a": parquet.Decimal(0, 10, parquet.Int32Type),
+				"b": parquet.Decimal(4, 3, parquet.Int64Type),
+			},
+			errors: []string{
+				`invalid parquet schema: DECIMAL precision must be between 1 and 9 for INT32 values but is 10 "a"`,
+				`invalid parquet schema: DECIMAL scale must be between 0 and the precision but is 4 "b"`,
+			},
+		},
+
+		{
+			scenario: "list without repeated column",
+			node: parquet.Group{
+				"list": annotatedGroup{
+					Group: parquet.Group{"list": parquet.String()},
+					typ:   parquet.List(parquet.String()).Type(),
+				},
+			},
+			errors: []string{`invalid parquet schema: LIST column must have a repeated child column but "list" is not repeated "list"`},
+		},
+
+		{
+			scenario: "repeated list",
+			node:     parquet.Group{"list": parquet.Repeated(parquet.List(parquet.String()))},
+			errors:   []string{`invalid parquet schema: LIST column must not be repeated "list"`},
+		},
+
+		{
+			scenario: "map with optional keys",
+			node: parquet.Group{
+				"map": annotatedGroup{
+					Group: parquet.Group{
+						"key_value": parquet.Repeated(parquet.Group{
+							"key":   parquet.Optional(parquet.String()),
+							"value": parquet.String(),
+						}),
+					},
+					typ: parquet.Map(parquet.String(), parquet.String()).Type(),
+				},
+			},
+			errors: []string{`invalid parquet schema: MAP key column must be required "map"`},
+		},
+
+		{
+			scenario: "variant",
+			node: parquet.Group{
+				"variant":  parquet.Optional(parquet.Variant()),
+				"shredded": parquet.ShreddedVariant(parquet.Leaf(parquet.Int64Type)),
+			},
+		},
+
+		{
+			scenario: "variant without metadata",
+			node: parquet.Group{
+				"variant": annotatedGroup{
+					Group: parquet.Group{"value": parquet.Leaf(parquet.ByteArrayType)},
+					typ:   parquet.Variant().Type(),
+				},
+			},
+			errors: []string{`invalid parquet schema: VARIANT group must have a column named "metadata" "variant"`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			schema, ok := test.node.(*parquet.Schema)
+			if !ok {
+				schema = parquet.NewSchema("test", test.node)
+			}
+
+			err := schema.Validate()
+			if len(test.errors) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			errs, ok := err.(parquet.SchemaErrors)
+			if !ok {
+				t.Fatalf("wrong error type: %T", err)
+			}
+			if len(errs) != len(test.errors) {
+				t.Fatalf("wrong number of errors: want=%d got=%d\n%v", len(test.errors), len(errs), err)
+			}
+			for i, want := range test.errors {
+				if got := errs[i].Error(); got != want {
+					t.Errorf("wrong error at index %d:\nwant = %s\ngot  = %s", i, want, got)
+				}
+			}
+		})
+	}
+}
+
+type treeNode struct {
+	Name     string     `parquet:"name"`
+	Children []treeNode `parquet:"children,depth(2)"`
+}
+
+type department struct {
+	Name    string    `parquet:"name"`
+	Manager *employee `parquet:"manager,optional"`
+}
+
+type employee struct {
+	Name       string      `parquet:"name"`
+	Department *department `parquet:"department,depth(1)"`
+}
+
+func TestSchemaOfInvalidTimeTag(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a time(micros) tag on an int32 field")
+		}
+	}()
+	parquet.SchemaOf(new(struct {
+		Time int32 `parquet:"time,time(micros)"`
+	}))
+}
+
+func TestSchemaOfRecursiveTypeWithoutDepth(t *testing.T) {
+	type list struct {
+		Value int64 `parquet:"value"`
+		Next  *list `parquet:"next"`
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a recursive field without a depth tag")
+		}
+	}()
+	parquet.SchemaOf(new(list))
+}
+
+func TestSchemaOfInvalidDepthTag(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a negative depth")
+		}
+	}()
+	parquet.SchemaOf(new(struct {
+		Children []treeNode `parquet:"children,depth(-1)"`
+	}))
+}
+
+func TestSchemaOfInvalidFieldIDTag(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a field id which is not positive")
+		}
+	}()
+	parquet.SchemaOf(new(struct {
+		ID int64 `parquet:"id,id(0)"`
+	}))
+}
+
+func TestSchemaMarshalJSON(t *testing.T) {
+	type Contact struct {
+		Name  string `parquet:"name"`
+		Phone string `parquet:"phone,optional"`
+	}
+	type AddressBook struct {
+		Owner    string    `parquet:"owner"`
+		ID       [16]byte  `parquet:"id,id(2)"`
+		Score    float64   `parquet:"score,optional"`
+		Tags     []string  `parquet:"tags,list"`
+		Contacts []Contact `parquet:"contacts"`
+	}
+
+	schema := parquet.SchemaOf(new(AddressBook))
+	b, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `{"name":"AddressBook","fields":[` +
+		`{"name":"contacts","repetition":"REPEATED","fields":[` +
+		`{"name":"name","repetition":"REQUIRED","type":"BYTE_ARRAY","logicalType":"STRING"},` +
+		`{"name":"phone","repetition":"OPTIONAL","type":"BYTE_ARRAY","logicalType":"STRING"}]},` +
+		`{"name":"id","repetition":"REQUIRED","type":"FIXED_LEN_BYTE_ARRAY","length":16,"fieldId":2},` +
+		`{"name":"owner","repetition":"REQUIRED","type":"BYTE_ARRAY","logicalType":"STRING"},` +
+		`{"name":"score","repetition":"OPTIONAL","type":"DOUBLE"},` +
+		`{"name":"tags","repetition":"REQUIRED","logicalType":"LIST","fields":[` +
+		`{"name":"list","repetition":"REPEATED","fields":[` +
+		`{"name":"element","repetition":"REQUIRED","type":"BYTE_ARRAY","logicalType":"STRING"}]}]}]}`
+
+	if got := string(b); got != want {
+		t.Errorf("wrong JSON representation of the schema:\nwant: %s\ngot:  %s", want, got)
+	}
+}
+
+func TestCompareSchemas(t *testing.T) {
+	type Before struct {
+		ID      int64   `parquet:"id"`
+		Name    string  `parquet:"name"`
+		Email   string  `parquet:"email,id(3)"`
+		Score   float32 `parquet:"score"`
+		Deleted bool    `parquet:"deleted"`
+	}
+	type After struct {
+		ID      int64   `parquet:"id"`
+		Name    string  `parquet:"name,optional"`
+		Mail    string  `parquet:"mail,id(3)"`
+		Score   float64 `parquet:"score"`
+		Country string  `parquet:"country"`
+	}
+
+	a := parquet.SchemaOf(new(Before))
+	b := parquet.SchemaOf(new(After))
+
+	if diffs := parquet.CompareSchemas(a, a); len(diffs) != 0 {
+		t.Errorf("comparing a schema to itself reported differences: %v", diffs)
+	}
+
+	want := []string{
+		`removed column "deleted": required boolean deleted`,
+		`renamed column "email" to "mail"`,
+		`repetition changed column "name": required binary name (STRING) => optional binary name (STRING)`,
+		`retyped column "score": required float score => required double score`,
+		`added column "country": required binary country (STRING)`,
+	}
+
+	diffs := parquet.CompareSchemas(a, b)
+	if len(diffs) != len(want) {
+		t.Fatalf("wrong number of differences: want=%d got=%d\n%v", len(want), len(diffs), diffs)
+	}
+	for i, diff := range diffs {
+		if got := diff.String(); got != want[i] {
+			t.Errorf("wrong difference at index %d:\nwant: %s\ngot:  %s", i, want[i], got)
+		}
+	}
+
+	if kind := diffs[1].Kind; kind != parquet.ColumnRenamed {
+		t.Errorf("wrong kind of difference: want=%s got=%s", parquet.ColumnRenamed, kind)
+	}
+	if path := diffs[4].Path(); len(path) != 1 || path[0] != "country" {
+		t.Errorf("wrong path of added column: %q", path)
+	}
+}
+
+func TestSchemaBuiltFromNodes(t *testing.T) {
+	schema := parquet.NewSchema("Event", parquet.Group{
+		"id":      parquet.FieldID(parquet.Uint(64), 1),
+		"name":    parquet.FieldID(parquet.Optional(parquet.String()), 2),
+		"kind":    parquet.Enum(),
+		"key":     parquet.UUID(),
+		"payload": parquet.Optional(parquet.JSON()),
+		"amount":  parquet.Decimal(2, 18, parquet.Int64Type),
+		"day":     parquet.Date(),
+		"time":    parquet.TimeAdjusted(parquet.Millisecond, false),
+		"created": parquet.Timestamp(parquet.Nanosecond),
+		"tags":    parquet.List(parquet.String()),
+		"labels":  parquet.Map(parquet.String(), parquet.Optional(parquet.Int(16))),
+		"source": parquet.Optional(parquet.Group{
+			"host": parquet.String(),
+			"port": parquet.Int(32),
+		}),
+	})
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, schema)
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fileSchema := parquet.NewSchema("Event", f.Root())
+	if want, got := schema.String(), fileSchema.String(); want != got {
+		t.Errorf("schema mismatch after writing the file:\nwant:\n%s\ngot:\n%s", want, got)
+	}
+	if diffs := parquet.CompareSchemas(schema, fileSchema); len(diffs) != 0 {
+		t.Errorf("schema differences after writing the file: %v", diffs)
+	}
+}
+
+func TestMakeAllOptional(t *testing.T) {
+	type Address struct {
+		City string `parquet:"city"`
+		Zip  int32  `parquet:"zip"`
+	}
+	type Record struct {
+		ID      int64            `parquet:"id"`
+		Name    string           `parquet:"name"`
+		Address Address          `parquet:"address"`
+		Tags    []string         `parquet:"tags,list"`
+		Labels  map[string]int64 `parquet:"labels"`
+	}
+
+	schema := parquet.SchemaOf(new(Record))
+	record := Record{
+		ID:      1,
+		Address: Address{City: "Paris"},
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]int64{"x": 42},
+	}
+
+	tests := []struct {
+		scenario string
+		schema   *parquet.Schema
+		print    string
+	}{
+		{
+			scenario: "all optional",
+			schema:   parquet.MakeAllOptional(schema),
+			print: `message Record {
+	optional group address {
+		optional binary city (STRING);
+		optional int32 zip (INT(32,true));
+	}
+	optional int64 id (INT(64,true));
+	optional group labels (MAP) {
+		repeated group key_value {
+			required binary key (STRING);
+			required int64 value (INT(64,true));
+		}
+	}
+	optional binary name (STRING);
+	optional group tags (LIST) {
+		repeated group list {
+			required binary element (STRING);
+		}
+	}
+}`,
+		},
+
+		{
+			scenario: "required only",
+			schema:   parquet.RequiredOnly(schema, []string{"id"}, []string{"address", "city"}),
+			print: `message Record {
+	required group address {
+		required binary city (STRING);
+		optional int32 zip (INT(32,true));
+	}
+	required int64 id (INT(64,true));
+	optional group labels (MAP) {
+		repeated group key_value {
+			required binary key (STRING);
+			required int64 value (INT(64,true));
+		}
+	}
+	optional binary name (STRING);
+	optional group tags (LIST) {
+		repeated group list {
+			required binary element (STRING);
+		}
+	}
+}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			if got := test.schema.String(); got != test.print {
+				t.Errorf("wrong schema:\nwant:\n%s\ngot:\n%s", test.print, got)
+			}
+
+			buffer := new(bytes.Buffer)
+			writer := parquet.NewWriter(buffer, test.schema)
+			if err := writer.Write(&record); err != nil {
+				t.Fatal(err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()), test.schema)
+			row, err := reader.ReadRow(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got Record
+			if err := test.schema.Reconstruct(&got, row); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, record) {
+				t.Errorf("wrong record read back:\nwant: %+v\ngot:  %+v", record, got)
+			}
+		})
+	}
+}
+
+func TestSchemaSelect(t *testing.T) {
+	type Address struct {
+		City string `parquet:"city"`
+		Zip  int32  `parquet:"zip"`
+	}
+	type Contact struct {
+		Name  string `parquet:"name"`
+		Phone string `parquet:"phone,optional"`
+	}
+	type Record struct {
+		ID       int64     `parquet:"id,id(1)"`
+		Name     string    `parquet:"name"`
+		Address  *Address  `parquet:"address,id(2)"`
+		Tags     []string  `parquet:"tags,list"`
+		Contacts []Contact `parquet:"contacts"`
+	}
+
+	schema := parquet.SchemaOf(new(Record))
+	projection, err := schema.Select(
+		[]string{"id"},
+		[]string{"address", "city"},
+		[]string{"tags"},
+		[]string{"contacts", "phone"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `message Record {
+	optional group address = 2 {
+		required binary city (STRING);
+	}
+	repeated group contacts {
+		optional binary phone (STRING);
+	}
+	required int64 id (INT(64,true)) = 1;
+	required group tags (LIST) {
+		repeated group list {
+			required binary element (STRING);
+		}
+	}
+}`
+	if got := projection.String(); got != want {
+		t.Errorf("wrong projection:\nwant:\n%s\ngot:\n%s", want, got)
+	}
+
+	all, err := schema.Select([]string{"address", "city"}, []string{"address", "zip"}, []string{"name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node := all.ChildByName("address"); node != schema.ChildByName("address") {
+		t.Error("selecting all the columns of a group did not retain the group")
+	}
+
+	for _, paths := range [][][]string{
+		{{"nope"}},
+		{{"id", "nope"}},
+		{{"address", "country"}},
+		{},
+	} {
+		if _, err := schema.Select(paths...); err == nil {
+			t.Errorf("selecting %q did not fail", paths)
+		}
+	}
+
+	record := Record{
+		ID:       1,
+		Name:     "Luke",
+		Address:  &Address{City: "Tatooine", Zip: 42},
+		Tags:     []string{"jedi"},
+		Contacts: []Contact{{Name: "Leia", Phone: "555"}},
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, schema)
+	if err := writer.Write(&record); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()), projection)
+	row, err := reader.ReadRow(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(row) != 4 {
+		t.Fatalf("wrong number of values in projected row: want=4 got=%d\n%s", len(row), row)
+	}
+
+	var got Record
+	if err := projection.Reconstruct(&got, row); err != nil {
+		t.Fatal(err)
+	}
+	expect := Record{
+		ID:       1,
+		Address:  &Address{City: "Tatooine"},
+		Tags:     []string{"jedi"},
+		Contacts: []Contact{{Phone: "555"}},
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("wrong record read with the projection:\nwant: %+v\ngot:  %+v", expect, got)
+	}
+}
@@ -0,0 +1,276 @@
+package parquet
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/segmentio/parquet-go/format"
+)
+
+// SchemaError is an error type describing an illegal construct found in a
+// parquet schema by a call to Schema.Validate.
+type SchemaError struct {
+	Reason string
+	Path   []string
+	Node   Node
+}
+
+// Error satisfies the error interface.
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("invalid parquet schema: %s %q", e.Reason, columnPath(e.Path))
+}
+
+// SchemaErrors is the type of errors returned by Schema.Validate, it lists all
+// the illegal constructs found in a schema.
+type SchemaErrors []*SchemaError
+
+// Error satisfies the error interface.
+func (errs SchemaErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Validate checks that the schema does not contain illegal constructs which
+// would prevent writing or reading parquet files, for example:
+//
+//   - groups with duplicate or empty column names
+//   - columns which are not exactly one of optional, repeated, or required
+//   - logical types annotating physical types that they cannot represent
+//   - LIST and MAP columns which do not follow the layout of the parquet spec
+//
+// When the schema is invalid, the method returns a SchemaErrors value listing
+// all the errors that were found.
+//
+// Programs constructing schemas dynamically can use this method to report
+// errors early, instead of failing deep inside the encoding of rows; writers
+// can be configured to validate their schema with the SchemaValidation option.
+func (s *Schema) Validate() error {
+	var errs SchemaErrors
+	validateNode(&errs, s.root, nil, true)
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateNode(errs *SchemaErrors, node Node, path columnPath, root bool) {
+	fail := func(reason string, args ...interface{}) {
+		*errs = append(*errs, &SchemaError{
+			Reason: fmt.Sprintf(reason, args...),
+			Path:   path,
+			Node:   node,
+		})
+	}
+
+	if !root {
+		repetitions := 0
+		for _, ok := range []bool{node.Optional(), node.Repeated(), node.Required()} {
+			if ok {
+				repetitions++
+			}
+		}
+		if repetitions != 1 {
+			fail("column must be exactly one of optional, repeated, or required")
+		}
+	}
+
+	switch {
+	case isList(node):
+		validateList(node, fail)
+	case isMap(node):
+		validateMap(node, fail)
+	case isVariant(node):
+		validateVariant(node, fail)
+	}
+
+	if isLeaf(node) {
+		if !root {
+			validateLeaf(node, fail)
+		}
+		return
+	}
+
+	names := node.ChildNames()
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		if name == "" {
+			fail("group has a column with an empty name")
+			continue
+		}
+		if _, exists := seen[name]; exists {
+			fail("group has multiple columns named %q", name)
+			continue
+		}
+		seen[name] = struct{}{}
+
+		child := node.ChildByName(name)
+		if child == nil {
+			fail("group has no column named %q", name)
+			continue
+		}
+		validateNode(errs, child, path.append(name), false)
+	}
+}
+
+func validateList(node Node, fail func(string, ...interface{})) {
+	if node.Repeated() {
+		fail("LIST column must not be repeated")
+	}
+	names := node.ChildNames()
+	if len(names) != 1 {
+		fail("LIST column must have exactly one child column but has %d", len(names))
+		return
+	}
+	if list := node.ChildByName(names[0]); list != nil && !list.Repeated() {
+		fail("LIST column must have a repeated child column but %q is not repeated", names[0])
+	}
+}
+
+func validateMap(node Node, fail func(string, ...interface{})) {
+	if node.Repeated() {
+		fail("MAP column must not be repeated")
+	}
+	names := node.ChildNames()
+	if len(names) != 1 {
+		fail("MAP column must have exactly one child column but has %d", len(names))
+		return
+	}
+	keyValue := node.ChildByName(names[0])
+	if keyValue == nil {
+		return
+	}
+	if !keyValue.Repeated() || isLeaf(keyValue) {
+		fail("MAP column must have a repeated group child column but %q is not", names[0])
+		return
+	}
+	if n := keyValue.NumChildren(); n > 2 {
+		fail("MAP key/value group must have at most two columns but has %d", n)
+	}
+	if key := keyValue.ChildByName("key"); key == nil {
+		fail("MAP key/value group must have a column named \"key\"")
+	} else if !key.Required() {
+		fail("MAP key column must be required")
+	}
+}
+
+func validateVariant(node Node, fail func(string, ...interface{})) {
+	if node.Repeated() {
+		fail("VARIANT column must not be repeated")
+	}
+	if isLeaf(node) {
+		fail("VARIANT logical type must annotate a group")
+		return
+	}
+	if metadata := node.ChildByName("metadata"); metadata == nil {
+		fail("VARIANT group must have a column named \"metadata\"")
+	} else if !metadata.Required() || !isLeaf(metadata) || metadata.Type().Kind() != ByteArray {
+		fail("VARIANT metadata column must be a required binary column")
+	}
+	value := node.ChildByName("value")
+	if value != nil && (!isLeaf(value) || value.Type().Kind() != ByteArray) {
+		fail("VARIANT value column must be a binary column")
+	}
+	typedValue := node.ChildByName("typed_value")
+	if value == nil && typedValue == nil {
+		fail("VARIANT group must have a column named \"value\" or \"typed_value\"")
+	}
+	for _, name := range node.ChildNames() {
+		switch name {
+		case "metadata", "value", "typed_value":
+		default:
+			fail("VARIANT group has unexpected column named %q", name)
+		}
+	}
+}
+
+func validateLeaf(node Node, fail func(string, ...interface{})) {
+	typ := node.Type()
+	logicalType := typ.LogicalType()
+	if logicalType == nil || typ.PhysicalType() == nil {
+		return
+	}
+
+	kind := typ.Kind()
+	invalid := func() {
+		fail("%s logical type cannot annotate %s values", logicalType, kind)
+	}
+
+	switch lt := logicalType; {
+	case lt.UTF8 != nil, lt.Enum != nil, lt.Json != nil, lt.Bson != nil, lt.Geometry != nil, lt.Geography != nil:
+		if kind != ByteArray {
+			invalid()
+		}
+
+	case lt.Unknown != nil:
+		if node.Required() {
+			fail("NULL logical type must annotate optional columns")
+		}
+
+	case lt.UUID != nil:
+		if kind != FixedLenByteArray || typ.Length() != 16 {
+			fail("UUID logical type must annotate 16 bytes fixed length byte arrays")
+		}
+
+	case lt.Date != nil:
+		if kind != Int32 {
+			invalid()
+		}
+
+	case lt.Time != nil:
+		if lt.Time.Unit.Millis != nil {
+			if kind != Int32 {
+				invalid()
+			}
+		} else if kind != Int64 {
+			invalid()
+		}
+
+	case lt.Timestamp != nil:
+		if kind != Int64 {
+			invalid()
+		}
+
+	case lt.Integer != nil:
+		switch lt.Integer.BitWidth {
+		case 8, 16, 32:
+			if kind != Int32 {
+				invalid()
+			}
+		case 64:
+			if kind != Int64 {
+				invalid()
+			}
+		default:
+			fail("INT logical type has invalid bit width %d", lt.Integer.BitWidth)
+		}
+
+	case lt.Decimal != nil:
+		validateDecimal(lt.Decimal, kind, typ.Length(), invalid, fail)
+	}
+}
+
+func validateDecimal(decimal *format.DecimalType, kind Kind, length int, invalid func(), fail func(string, ...interface{})) {
+	maxPrecision := int32(math.MaxInt32)
+	switch kind {
+	case Int32:
+		maxPrecision = 9
+	case Int64:
+		maxPrecision = 18
+	case FixedLenByteArray:
+		maxPrecision = int32(math.Floor(float64(8*length-1) * math.Log10(2)))
+	case ByteArray:
+	default:
+		invalid()
+		return
+	}
+	if decimal.Precision < 1 || decimal.Precision > maxPrecision {
+		fail("DECIMAL precision must be between 1 and %d for %s values but is %d", maxPrecision, kind, decimal.Precision)
+	}
+	if decimal.Scale < 0 || decimal.Scale > decimal.Precision {
+		fail("DECIMAL scale must be between 0 and the precision but is %d", decimal.Scale)
+	}
+}
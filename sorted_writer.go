@@ -0,0 +1,219 @@
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// SortedWriter is a writer producing parquet files where all rows are sorted
+// according to the sorting columns of the writer configuration, regardless of
+// the order in which they were written.
+//
+// Rows are buffered in memory until the buffer reaches the maximum number of
+// rows that the writer was configured with, at which point they are sorted and
+// written to a temporary parquet file. When the writer is closed, the sorted
+// runs are merged into the output file. The memory footprint of the writer is
+// therefore bounded by the size of the buffer, which allows sorting data sets
+// that would not fit in memory, at the cost of writing them to disk twice.
+//
+// This example showcases how to sort rows by ascending ids:
+//
+//	writer := parquet.NewSortedWriter(output, 1e6,
+//		parquet.SortingColumns(parquet.Ascending("id")),
+//	)
+//	for _, row := range rows {
+//		if err := writer.Write(row); err != nil {
+//			...
+//		}
+//	}
+//	if err := writer.Close(); err != nil {
+//		...
+//	}
+//
+// The temporary files are created in the default directory for temporary
+// files (see os.TempDir), and removed when the writer is closed.
+type SortedWriter struct {
+	output  io.Writer
+	config  *WriterConfig
+	maxRows int64
+	buffer  *Buffer
+	values  []Value
+	runs    []*os.File
+	// Set when Close was called, subsequent calls return the same error.
+	closed   bool
+	closeErr error
+}
+
+// NewSortedWriter constructs a writer which sorts rows in runs of up to
+// maxBufferedRows before writing them to output.
+//
+// The function panics if the writer configuration is invalid, if it has no
+// sorting columns, or if maxBufferedRows is not a positive number.
+func NewSortedWriter(output io.Writer, maxBufferedRows int64, options ...WriterOption) *SortedWriter {
+	config, err := NewWriterConfig(options...)
+	if err != nil {
+		panic(err)
+	}
+	if len(config.SortingColumns) == 0 {
+		panic("cannot create a sorted parquet writer without sorting columns")
+	}
+	if maxBufferedRows <= 0 {
+		panic(fmt.Sprintf("maximum number of buffered rows of sorted parquet writer must be positive: %d", maxBufferedRows))
+	}
+	w := &SortedWriter{
+		output:  output,
+		config:  config,
+		maxRows: maxBufferedRows,
+	}
+	if config.Schema != nil {
+		w.configure(config.Schema)
+	}
+	return w
+}
+
+func (w *SortedWriter) configure(schema *Schema) {
+	w.buffer = NewBuffer(schema, SortingColumns(w.config.SortingColumns...))
+}
+
+// Close sorts the rows that were written to w, writes them to the output, and
+// writes the parquet footer. The temporary files created by w are removed,
+// whether the method succeeds or not.
+//
+// Calling Close more than once has no effect, the subsequent calls return
+// the error returned by the first one. Rows cannot be written after the writer
+// was closed.
+func (w *SortedWriter) Close() error {
+	if !w.closed {
+		w.closed = true
+		w.closeErr = w.close()
+	}
+	return w.closeErr
+}
+
+func (w *SortedWriter) close() error {
+	defer w.removeRuns()
+
+	if w.buffer == nil {
+		return nil
+	}
+
+	schema := w.buffer.Schema()
+	sort.Sort(w.buffer)
+	rowGroups := []RowGroup{w.buffer}
+
+	for _, run := range w.runs {
+		stat, err := run.Stat()
+		if err != nil {
+			return err
+		}
+		f, err := OpenFile(run, stat.Size())
+		if err != nil {
+			return fmt.Errorf("opening sorted run of parquet rows: %w", err)
+		}
+		rowGroups = append(rowGroups, f.RowGroups()...)
+	}
+
+	merged, err := MergeRowGroups(rowGroups, schema, SortingColumns(w.config.SortingColumns...))
+	if err != nil {
+		return err
+	}
+
+	output := NewWriter(w.output, w.config, schema)
+	if _, err := output.ReadRowsFrom(merged.Rows()); err != nil {
+		return err
+	}
+	if err := output.Close(); err != nil {
+		return err
+	}
+	w.buffer.Reset()
+	return nil
+}
+
+// Write writes a row to w. If no schema was passed to NewSortedWriter, it is
+// deducted from the Go type of the first row written.
+func (w *SortedWriter) Write(row interface{}) error {
+	if w.closed {
+		return io.ErrClosedPipe
+	}
+	if w.buffer == nil {
+		w.configure(SchemaOf(row))
+	}
+	defer func() {
+		clearValues(w.values)
+	}()
+	w.values = w.buffer.Schema().Deconstruct(w.values[:0], row)
+	return w.WriteRow(w.values)
+}
+
+// WriteRow writes a row to w.
+//
+// The SortedWriter must have been given a schema when NewSortedWriter was
+// called, otherwise the structure of the rows cannot be determined.
+func (w *SortedWriter) WriteRow(row Row) error {
+	if w.closed {
+		return io.ErrClosedPipe
+	}
+	if w.buffer == nil {
+		return ErrRowGroupSchemaMissing
+	}
+	if err := w.buffer.WriteRow(row); err != nil {
+		return err
+	}
+	if w.buffer.NumRows() >= w.maxRows {
+		return w.spill()
+	}
+	return nil
+}
+
+// Schema returns the schema of rows written by w.
+//
+// The returned value will be nil if no schema has yet been configured on w.
+func (w *SortedWriter) Schema() *Schema {
+	if w.buffer == nil {
+		return nil
+	}
+	return w.buffer.Schema()
+}
+
+// spill sorts the buffered rows and writes them to a temporary file. The file
+// is removed right away if the rows could not be written to it.
+func (w *SortedWriter) spill() (err error) {
+	sort.Sort(w.buffer)
+
+	f, err := os.CreateTemp("", "parquet-sort-*")
+	if err != nil {
+		return fmt.Errorf("creating temporary file to sort parquet rows: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	run := NewWriter(f, w.buffer.Schema(), SortingColumns(w.config.SortingColumns...))
+	if _, err := run.WriteRowGroup(w.buffer); err != nil {
+		return fmt.Errorf("writing sorted run of parquet rows: %w", err)
+	}
+	if err := run.Close(); err != nil {
+		return fmt.Errorf("writing sorted run of parquet rows: %w", err)
+	}
+
+	w.runs = append(w.runs, f)
+	w.buffer.Reset()
+	return nil
+}
+
+func (w *SortedWriter) removeRuns() {
+	for _, run := range w.runs {
+		run.Close()
+		os.Remove(run.Name())
+	}
+	w.runs = nil
+}
+
+var (
+	_ RowWriterWithSchema = (*SortedWriter)(nil)
+)
@@ -0,0 +1,144 @@
+package parquet_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestSortedWriter(t *testing.T) {
+	type Row struct {
+		ID    int64  `parquet:"id"`
+		Value string `parquet:"value"`
+	}
+
+	const numRows = 1000
+
+	tmpdir := t.TempDir()
+	t.Setenv("TMPDIR", tmpdir)
+
+	for _, maxBufferedRows := range []int64{numRows * 2, numRows, 99} {
+		buffer := new(bytes.Buffer)
+		writer := parquet.NewSortedWriter(buffer, maxBufferedRows,
+			parquet.SortingColumns(parquet.Ascending("id")),
+		)
+
+		prng := rand.New(rand.NewSource(0))
+		for _, i := range prng.Perm(numRows) {
+			if err := writer.Write(&Row{ID: int64(i), Value: string(rune('A' + i%26))}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if files, _ := filepath.Glob(filepath.Join(tmpdir, "*")); len(files) != 0 {
+			t.Errorf("temporary files were not removed: %v", files)
+		}
+
+		f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, rowGroup := range f.RowGroups() {
+			sorting := rowGroup.SortingColumns()
+			if len(sorting) != 1 || sorting[0].Path()[0] != "id" {
+				t.Errorf("wrong sorting columns in output row group: %v", sorting)
+			}
+		}
+
+		reader := parquet.NewReader(f)
+		for i := int64(0); ; i++ {
+			row := Row{}
+			if err := reader.Read(&row); err != nil {
+				if err != io.EOF {
+					t.Fatal(err)
+				}
+				if i != numRows {
+					t.Errorf("wrong number of rows: want=%d got=%d", numRows, i)
+				}
+				break
+			}
+			if row.ID != i || row.Value != string(rune('A'+i%26)) {
+				t.Fatalf("row at index %d is out of order: %+v", i, row)
+			}
+		}
+	}
+}
+
+type errorWriter struct{ err error }
+
+func (w errorWriter) Write([]byte) (int, error) { return 0, w.err }
+
+func TestSortedWriterClose(t *testing.T) {
+	type Row struct {
+		ID int64 `parquet:"id"`
+	}
+
+	tmpdir := t.TempDir()
+	t.Setenv("TMPDIR", tmpdir)
+
+	write := func(t *testing.T, output io.Writer) *parquet.SortedWriter {
+		writer := parquet.NewSortedWriter(output, 10, parquet.SortingColumns(parquet.Descending("id")))
+		for i := 0; i < 25; i++ {
+			if err := writer.Write(&Row{ID: int64(i)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if files, _ := filepath.Glob(filepath.Join(tmpdir, "*")); len(files) != 2 {
+			t.Fatalf("wrong number of temporary files: want=2 got=%d", len(files))
+		}
+		return writer
+	}
+
+	checkTempFiles := func(t *testing.T) {
+		if files, _ := filepath.Glob(filepath.Join(tmpdir, "*")); len(files) != 0 {
+			t.Errorf("temporary files were not removed: %v", files)
+		}
+	}
+
+	t.Run("twice", func(t *testing.T) {
+		buffer := new(bytes.Buffer)
+		writer := write(t, buffer)
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		size := buffer.Len()
+		if err := writer.Close(); err != nil {
+			t.Fatalf("closing the writer twice: %v", err)
+		}
+		if buffer.Len() != size {
+			t.Errorf("the second call to Close wrote to the output: %d != %d", buffer.Len(), size)
+		}
+		if err := writer.Write(&Row{ID: 42}); err == nil {
+			t.Error("expected an error when writing after Close")
+		}
+		checkTempFiles(t)
+
+		f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := f.NumRows(); n != 25 {
+			t.Errorf("wrong number of rows: want=25 got=%d", n)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		writeErr := errors.New("write error")
+		writer := write(t, errorWriter{writeErr})
+		if err := writer.Close(); !errors.Is(err, writeErr) {
+			t.Fatalf("expected the output error but got %v", err)
+		}
+		if err := writer.Close(); !errors.Is(err, writeErr) {
+			t.Fatalf("expected the output error again but got %v", err)
+		}
+		checkTempFiles(t)
+	})
+}
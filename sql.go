@@ -0,0 +1,336 @@
+package parquet
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SQLReader is a row reader which reads the result set of a SQL query into
+// parquet rows.
+//
+// The schema of rows is derived from the column types of the result set, each
+// column of the result set being mapped to a top-level leaf column of the
+// schema:
+//
+//	bool               BOOLEAN
+//	int8, int16, int32 INT(32)
+//	int, int64, uint32 INT(64)
+//	float32            FLOAT
+//	float64            DOUBLE
+//	string             STRING
+//	[]byte             BYTE_ARRAY
+//	time.Time          TIMESTAMP(MICROS)
+//
+// The sql.Null* types map to the same parquet types as the Go type that they
+// wrap. When the driver does not report a Go type for a column, the database
+// type name is used to pick the closest mapping, and columns of unknown types
+// are converted to strings.
+//
+// Columns which may contain NULL values, or for which the driver does not
+// report whether they are nullable, are declared optional in the schema.
+//
+// SQLReader implements the RowReaderWithSchema interface, which makes it
+// possible to use it as source of a call to CopyRows. The WriteSQLRows
+// function offers a shortcut for the common case of writing the result set of
+// a query to a parquet file:
+//
+//	rows, err := db.QueryContext(ctx, query)
+//	if err != nil {
+//		...
+//	}
+//	defer rows.Close()
+//
+//	if _, err := parquet.WriteSQLRows(output, rows); err != nil {
+//		...
+//	}
+//
+type SQLReader struct {
+	rows    *sql.Rows
+	schema  *Schema
+	columns []sqlColumn
+	dest    []interface{}
+}
+
+type sqlColumn struct {
+	name               string
+	value              sqlColumnValue
+	columnIndex        int16
+	maxDefinitionLevel int16
+}
+
+// NewSQLReader constructs a reader of parquet rows from the result set of a
+// SQL query.
+//
+// The function returns an error if the column types of the result set cannot
+// be retrieved, or if multiple columns of the result set have the same name.
+// The reader does not close rows, the program remains responsible for it.
+func NewSQLReader(rows *sql.Rows) (*SQLReader, error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	group := make(Group, len(columnTypes))
+	for _, columnType := range columnTypes {
+		name := columnType.Name()
+		if _, exists := group[name]; exists {
+			return nil, fmt.Errorf("SQL result set has multiple columns named %q", name)
+		}
+		node := sqlNodeOf(columnType)
+		if nullable, ok := columnType.Nullable(); nullable || !ok {
+			node = Optional(node)
+		}
+		group[name] = node
+	}
+
+	r := &SQLReader{
+		rows:    rows,
+		schema:  NewSchema("sql", group),
+		columns: make([]sqlColumn, len(columnTypes)),
+		dest:    make([]interface{}, len(columnTypes)),
+	}
+
+	leaves := make(map[string]leafColumn, len(columnTypes))
+	forEachLeafColumnOf(r.schema, func(leaf leafColumn) {
+		leaves[leaf.path[0]] = leaf
+	})
+
+	for i, columnType := range columnTypes {
+		leaf := leaves[columnType.Name()]
+		value := sqlColumnValueOf(leaf.node.Type())
+		r.columns[i] = sqlColumn{
+			name:               columnType.Name(),
+			value:              value,
+			columnIndex:        leaf.columnIndex,
+			maxDefinitionLevel: leaf.maxDefinitionLevel,
+		}
+		r.dest[i] = value
+	}
+
+	// The SQL columns are scanned in the order of the result set, but values
+	// must be produced in the order of the parquet columns.
+	sort.Slice(r.columns, func(i, j int) bool {
+		return r.columns[i].columnIndex < r.columns[j].columnIndex
+	})
+	return r, nil
+}
+
+// Schema returns the schema of rows read from r.
+func (r *SQLReader) Schema() *Schema { return r.schema }
+
+// ReadRow reads the next row of the result set and appends its values to row.
+//
+// The method returns io.EOF when there are no more rows to read.
+func (r *SQLReader) ReadRow(row Row) (Row, error) {
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return row, err
+		}
+		return row, io.EOF
+	}
+
+	if err := r.rows.Scan(r.dest...); err != nil {
+		return row, err
+	}
+
+	for i := range r.columns {
+		c := &r.columns[i]
+		v, ok := c.value.value()
+		if !ok {
+			if c.maxDefinitionLevel == 0 {
+				return row, fmt.Errorf("SQL column %q: NULL value in non-nullable column", c.name)
+			}
+			row = append(row, Value{columnIndex: ^c.columnIndex})
+			continue
+		}
+		v.definitionLevel = c.maxDefinitionLevel
+		v.columnIndex = ^c.columnIndex
+		row = append(row, v)
+	}
+
+	return row, nil
+}
+
+// WriteSQLRows writes the result set of a SQL query to a parquet file written
+// to output, with a schema derived from the column types of the result set.
+//
+// The function returns the number of rows written. It does not close rows,
+// the program remains responsible for it.
+func WriteSQLRows(output io.Writer, rows *sql.Rows, options ...WriterOption) (int64, error) {
+	r, err := NewSQLReader(rows)
+	if err != nil {
+		return 0, err
+	}
+	w := NewWriter(output, append(options, r.Schema())...)
+	n, err := CopyRows(w, r)
+	if err != nil {
+		return n, err
+	}
+	return n, w.Close()
+}
+
+var (
+	sqlNullBool    = reflect.TypeOf(sql.NullBool{})
+	sqlNullByte    = reflect.TypeOf(sql.NullByte{})
+	sqlNullInt16   = reflect.TypeOf(sql.NullInt16{})
+	sqlNullInt32   = reflect.TypeOf(sql.NullInt32{})
+	sqlNullInt64   = reflect.TypeOf(sql.NullInt64{})
+	sqlNullFloat64 = reflect.TypeOf(sql.NullFloat64{})
+	sqlNullString  = reflect.TypeOf(sql.NullString{})
+	sqlNullTime    = reflect.TypeOf(sql.NullTime{})
+	sqlRawBytes    = reflect.TypeOf(sql.RawBytes{})
+)
+
+func sqlNodeOf(columnType *sql.ColumnType) Node {
+	switch t := columnType.ScanType(); t {
+	case nil:
+	case sqlNullBool:
+		return Leaf(BooleanType)
+	case sqlNullByte, sqlNullInt16, sqlNullInt32:
+		return Int(32)
+	case sqlNullInt64:
+		return Int(64)
+	case sqlNullFloat64:
+		return Leaf(DoubleType)
+	case sqlNullString:
+		return String()
+	case sqlNullTime, timeTime:
+		return Timestamp(Microsecond)
+	case sqlRawBytes:
+		return Leaf(ByteArrayType)
+	default:
+		switch t.Kind() {
+		case reflect.Bool:
+			return Leaf(BooleanType)
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+			return Int(32)
+		case reflect.Int, reflect.Int64, reflect.Uint32, reflect.Uint, reflect.Uint64:
+			return Int(64)
+		case reflect.Float32:
+			return Leaf(FloatType)
+		case reflect.Float64:
+			return Leaf(DoubleType)
+		case reflect.String:
+			return String()
+		case reflect.Slice:
+			if t.Elem().Kind() == reflect.Uint8 {
+				return Leaf(ByteArrayType)
+			}
+		}
+	}
+
+	// The driver did not report a Go type that we know how to map, fallback
+	// to guessing the type from the name of the database type.
+	switch typeName := strings.ToUpper(columnType.DatabaseTypeName()); {
+	case strings.Contains(typeName, "BOOL"):
+		return Leaf(BooleanType)
+	case strings.Contains(typeName, "INT"):
+		return Int(64)
+	case strings.Contains(typeName, "FLOAT"),
+		strings.Contains(typeName, "DOUBLE"),
+		strings.Contains(typeName, "REAL"):
+		return Leaf(DoubleType)
+	case strings.Contains(typeName, "BLOB"),
+		strings.Contains(typeName, "BINARY"),
+		strings.Contains(typeName, "BYTEA"):
+		return Leaf(ByteArrayType)
+	case strings.Contains(typeName, "TIMESTAMP"),
+		strings.Contains(typeName, "DATETIME"):
+		return Timestamp(Microsecond)
+	default:
+		return String()
+	}
+}
+
+// sqlColumnValue is implemented by the types used as scan destinations of
+// SQL columns, the value method converts the scanned value to a parquet value
+// and returns false if it was NULL.
+type sqlColumnValue interface {
+	sql.Scanner
+	value() (Value, bool)
+}
+
+func sqlColumnValueOf(t Type) sqlColumnValue {
+	switch t.Kind() {
+	case Boolean:
+		return new(sqlBoolValue)
+	case Int32:
+		return new(sqlInt32Value)
+	case Int64:
+		if logicalType := t.LogicalType(); logicalType != nil && logicalType.Timestamp != nil {
+			return new(sqlTimestampValue)
+		}
+		return new(sqlInt64Value)
+	case Float:
+		return new(sqlFloatValue)
+	case Double:
+		return new(sqlDoubleValue)
+	default:
+		if logicalType := t.LogicalType(); logicalType != nil && logicalType.UTF8 != nil {
+			return new(sqlStringValue)
+		}
+		return new(sqlBytesValue)
+	}
+}
+
+type sqlBoolValue struct{ sql.NullBool }
+
+func (v *sqlBoolValue) value() (Value, bool) { return makeValueBoolean(v.Bool), v.Valid }
+
+type sqlInt32Value struct{ sql.NullInt32 }
+
+func (v *sqlInt32Value) value() (Value, bool) { return makeValueInt32(v.Int32), v.Valid }
+
+type sqlInt64Value struct{ sql.NullInt64 }
+
+func (v *sqlInt64Value) value() (Value, bool) { return makeValueInt64(v.Int64), v.Valid }
+
+type sqlFloatValue struct{ sql.NullFloat64 }
+
+func (v *sqlFloatValue) value() (Value, bool) { return makeValueFloat(float32(v.Float64)), v.Valid }
+
+type sqlDoubleValue struct{ sql.NullFloat64 }
+
+func (v *sqlDoubleValue) value() (Value, bool) { return makeValueDouble(v.Float64), v.Valid }
+
+type sqlStringValue struct{ sql.NullString }
+
+func (v *sqlStringValue) value() (Value, bool) {
+	return makeValueString(ByteArray, v.String), v.Valid
+}
+
+type sqlTimestampValue struct{ sql.NullTime }
+
+func (v *sqlTimestampValue) value() (Value, bool) {
+	return makeValueInt64(v.Time.UnixNano() / int64(time.Microsecond)), v.Valid
+}
+
+type sqlBytesValue struct {
+	data  []byte
+	valid bool
+}
+
+func (v *sqlBytesValue) Scan(src interface{}) error {
+	switch b := src.(type) {
+	case nil:
+		v.data, v.valid = v.data[:0], false
+	case []byte:
+		v.data, v.valid = append(v.data[:0], b...), true
+	case string:
+		v.data, v.valid = append(v.data[:0], b...), true
+	default:
+		return fmt.Errorf("cannot convert SQL value of type %T to parquet byte array", src)
+	}
+	return nil
+}
+
+func (v *sqlBytesValue) value() (Value, bool) { return makeValueBytes(ByteArray, v.data), v.valid }
+
+var (
+	_ RowReaderWithSchema = (*SQLReader)(nil)
+)
@@ -0,0 +1,203 @@
+package parquet_test
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+)
+
+// sqlTestDriver is a minimal database/sql driver serving a fixed result set
+// registered under the query string of sqlTestDriverQueries.
+type sqlTestDriver struct{}
+
+type sqlTestColumn struct {
+	name     string
+	scanType reflect.Type
+	typeName string
+	nullable bool
+}
+
+type sqlTestResult struct {
+	columns []sqlTestColumn
+	rows    [][]driver.Value
+}
+
+var sqlTestDriverQueries = map[string]sqlTestResult{}
+
+func init() { sql.Register("parquet-test", sqlTestDriver{}) }
+
+func (sqlTestDriver) Open(string) (driver.Conn, error) { return sqlTestConn{}, nil }
+
+type sqlTestConn struct{}
+
+func (sqlTestConn) Prepare(query string) (driver.Stmt, error) { return sqlTestStmt{query}, nil }
+func (sqlTestConn) Close() error                              { return nil }
+func (sqlTestConn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+
+type sqlTestStmt struct{ query string }
+
+func (sqlTestStmt) Close() error                               { return nil }
+func (sqlTestStmt) NumInput() int                              { return 0 }
+func (sqlTestStmt) Exec([]driver.Value) (driver.Result, error) { return nil, driver.ErrSkip }
+func (s sqlTestStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &sqlTestRows{result: sqlTestDriverQueries[s.query]}, nil
+}
+
+type sqlTestRows struct {
+	result sqlTestResult
+	index  int
+}
+
+func (r *sqlTestRows) Columns() []string {
+	names := make([]string, len(r.result.columns))
+	for i, c := range r.result.columns {
+		names[i] = c.name
+	}
+	return names
+}
+
+func (r *sqlTestRows) Close() error { return nil }
+
+func (r *sqlTestRows) Next(dest []driver.Value) error {
+	if r.index == len(r.result.rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.rows[r.index])
+	r.index++
+	return nil
+}
+
+func (r *sqlTestRows) ColumnTypeScanType(i int) reflect.Type {
+	return r.result.columns[i].scanType
+}
+
+func (r *sqlTestRows) ColumnTypeDatabaseTypeName(i int) string {
+	return r.result.columns[i].typeName
+}
+
+func (r *sqlTestRows) ColumnTypeNullable(i int) (nullable, ok bool) {
+	return r.result.columns[i].nullable, true
+}
+
+func TestWriteSQLRows(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 30, 0, 123456000, time.UTC)
+
+	sqlTestDriverQueries["SELECT * FROM users"] = sqlTestResult{
+		columns: []sqlTestColumn{
+			{name: "id", scanType: reflect.TypeOf(int64(0)), typeName: "BIGINT"},
+			{name: "name", scanType: reflect.TypeOf(""), typeName: "VARCHAR"},
+			{name: "email", scanType: reflect.TypeOf(sql.NullString{}), typeName: "VARCHAR", nullable: true},
+			{name: "score", scanType: reflect.TypeOf(float64(0)), typeName: "DOUBLE"},
+			{name: "active", scanType: reflect.TypeOf(false), typeName: "BOOLEAN"},
+			{name: "created_at", scanType: reflect.TypeOf(time.Time{}), typeName: "TIMESTAMP"},
+			{name: "data", typeName: "BLOB", nullable: true},
+		},
+		rows: [][]driver.Value{
+			{int64(1), "Luke", "luke@example.com", 1.5, true, now, []byte("A")},
+			{int64(2), "Leia", nil, 2.5, false, now.Add(time.Hour), nil},
+		},
+	}
+
+	db, err := sql.Open("parquet-test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT * FROM users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	buffer := new(bytes.Buffer)
+	n, err := parquet.WriteSQLRows(buffer, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("wrong number of rows written: want=2 got=%d", n)
+	}
+
+	type User struct {
+		ID        int64   `parquet:"id"`
+		Name      string  `parquet:"name"`
+		Email     *string `parquet:"email,optional"`
+		Score     float64 `parquet:"score"`
+		Active    bool    `parquet:"active"`
+		CreatedAt int64   `parquet:"created_at"`
+		Data      []byte  `parquet:"data,optional"`
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+
+	const want = `message sql {
+	required boolean active;
+	required int64 created_at (TIMESTAMP(isAdjustedToUTC=true,unit=MICROS));
+	optional binary data;
+	optional binary email (STRING);
+	required int64 id (INT(64,true));
+	required binary name (STRING);
+	required double score;
+}`
+	if got := reader.Schema().String(); got != want {
+		t.Errorf("wrong schema:\nwant:\n%s\ngot:\n%s", want, got)
+	}
+
+	users := make([]User, 2)
+	for i := range users {
+		if err := reader.Read(&users[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if users[0].Email == nil || *users[0].Email != "luke@example.com" {
+		t.Errorf("wrong email of first user: %v", users[0].Email)
+	}
+	if users[1].Email != nil {
+		t.Errorf("expected null email for second user: %q", *users[1].Email)
+	}
+	if users[0].ID != 1 || users[0].Name != "Luke" || users[0].Score != 1.5 || !users[0].Active {
+		t.Errorf("wrong first user: %+v", users[0])
+	}
+	if users[1].ID != 2 || users[1].Name != "Leia" || users[1].Score != 2.5 || users[1].Active {
+		t.Errorf("wrong second user: %+v", users[1])
+	}
+	if users[0].CreatedAt != now.UnixNano()/1e3 {
+		t.Errorf("wrong timestamp of first user: want=%d got=%d", now.UnixNano()/1e3, users[0].CreatedAt)
+	}
+	if string(users[0].Data) != "A" || users[1].Data != nil {
+		t.Errorf("wrong data: %q %q", users[0].Data, users[1].Data)
+	}
+}
+
+func TestNewSQLReaderDuplicateColumns(t *testing.T) {
+	sqlTestDriverQueries["SELECT id, id FROM users"] = sqlTestResult{
+		columns: []sqlTestColumn{
+			{name: "id", scanType: reflect.TypeOf(int64(0))},
+			{name: "id", scanType: reflect.TypeOf(int64(0))},
+		},
+	}
+
+	db, err := sql.Open("parquet-test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT id, id FROM users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	if _, err := parquet.NewSQLReader(rows); err == nil {
+		t.Error("expected an error when the result set has duplicate column names")
+	}
+}
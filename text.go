@@ -0,0 +1,283 @@
+package parquet
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/parquet-go/deprecated"
+	"github.com/segmentio/parquet-go/format"
+)
+
+// appendValueText appends the human-readable representation of v to b,
+// honoring the logical type of t when the column has one (e.g. timestamps
+// are formatted as RFC 3339 strings, decimals are scaled, etc...).
+//
+// Null values do not append anything to b.
+func appendValueText(b []byte, t Type, v Value) []byte {
+	if v.IsNull() {
+		return b
+	}
+
+	if lt := t.LogicalType(); lt != nil {
+		switch {
+		case lt.Date != nil:
+			return time.Unix(int64(v.Int32())*86400, 0).UTC().AppendFormat(b, "2006-01-02")
+
+		case lt.Time != nil:
+			d := timeUnitDuration(&lt.Time.Unit)
+			t := time.Unix(0, v.Int64()*int64(d)).UTC()
+			switch d {
+			case time.Millisecond:
+				return t.AppendFormat(b, "15:04:05.000")
+			case time.Microsecond:
+				return t.AppendFormat(b, "15:04:05.000000")
+			default:
+				return t.AppendFormat(b, "15:04:05.000000000")
+			}
+
+		case lt.Timestamp != nil:
+			d := timeUnitDuration(&lt.Timestamp.Unit)
+			t := time.Unix(0, v.Int64()*int64(d)).UTC()
+			if lt.Timestamp.IsAdjustedToUTC {
+				return t.AppendFormat(b, time.RFC3339Nano)
+			}
+			return t.AppendFormat(b, "2006-01-02T15:04:05.999999999")
+
+		case lt.Decimal != nil:
+			return appendDecimalText(b, v, int(lt.Decimal.Scale))
+
+		case lt.UUID != nil:
+			if u, err := uuid.FromBytes(v.ByteArray()); err == nil {
+				return append(b, u.String()...)
+			}
+
+		case lt.Integer != nil:
+			if !lt.Integer.IsSigned {
+				if v.Kind() == Int32 {
+					return strconv.AppendUint(b, uint64(uint32(v.Int32())), 10)
+				}
+				return strconv.AppendUint(b, uint64(v.Int64()), 10)
+			}
+		}
+	}
+
+	switch v.Kind() {
+	case Boolean:
+		return strconv.AppendBool(b, v.Boolean())
+	case Int32:
+		return strconv.AppendInt(b, int64(v.Int32()), 10)
+	case Int64:
+		return strconv.AppendInt(b, v.Int64(), 10)
+	case Float:
+		return strconv.AppendFloat(b, float64(v.Float()), 'g', -1, 32)
+	case Double:
+		return strconv.AppendFloat(b, v.Double(), 'g', -1, 64)
+	case Int96:
+		return append(b, v.Int96().String()...)
+	default:
+		return append(b, v.ByteArray()...)
+	}
+}
+
+func appendDecimalText(b []byte, v Value, scale int) []byte {
+	var unscaled *big.Int
+
+	switch v.Kind() {
+	case Int32:
+		unscaled = big.NewInt(int64(v.Int32()))
+	case Int64:
+		unscaled = big.NewInt(v.Int64())
+	default:
+		// FIXED_LEN_BYTE_ARRAY and BYTE_ARRAY decimals are stored as big-endian
+		// two's complement integers.
+		data := v.ByteArray()
+		unscaled = new(big.Int).SetBytes(data)
+		if len(data) > 0 && data[0]&0x80 != 0 {
+			unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(8*len(data))))
+		}
+	}
+
+	digits := unscaled.String()
+	if scale <= 0 {
+		return append(b, digits...)
+	}
+
+	if digits[0] == '-' {
+		b = append(b, '-')
+		digits = digits[1:]
+	}
+
+	for len(digits) <= scale {
+		digits = "0" + digits
+	}
+
+	i := len(digits) - scale
+	b = append(b, digits[:i]...)
+	b = append(b, '.')
+	return append(b, digits[i:]...)
+}
+
+// parseValueText parses the human-readable representation of a value of type
+// t from s. It is the inverse of appendValueText.
+func parseValueText(t Type, s string) (Value, error) {
+	v, err := parseValueTextOf(t, s)
+	if err != nil {
+		return Value{}, fmt.Errorf("cannot parse %q as %s value: %w", s, t, err)
+	}
+	return v, nil
+}
+
+func parseValueTextOf(t Type, s string) (Value, error) {
+	if lt := t.LogicalType(); lt != nil {
+		switch {
+		case lt.Date != nil:
+			d, err := time.Parse("2006-01-02", s)
+			if err != nil {
+				return Value{}, err
+			}
+			return makeValueInt32(int32(d.Unix() / 86400)), nil
+
+		case lt.Time != nil:
+			d, err := time.Parse("15:04:05.999999999", s)
+			if err != nil {
+				return Value{}, err
+			}
+			n := int64(d.Sub(time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)))
+			n /= int64(timeUnitDuration(&lt.Time.Unit))
+			if t.Kind() == Int32 {
+				return makeValueInt32(int32(n)), nil
+			}
+			return makeValueInt64(n), nil
+
+		case lt.Timestamp != nil:
+			layout := time.RFC3339Nano
+			if !lt.Timestamp.IsAdjustedToUTC {
+				layout = "2006-01-02T15:04:05.999999999"
+			}
+			d, err := time.Parse(layout, s)
+			if err != nil {
+				return Value{}, err
+			}
+			return makeValueInt64(d.UnixNano() / int64(timeUnitDuration(&lt.Timestamp.Unit))), nil
+
+		case lt.Decimal != nil:
+			return parseDecimalText(t, s, int(lt.Decimal.Scale))
+
+		case lt.UUID != nil:
+			u, err := uuid.Parse(s)
+			if err != nil {
+				return Value{}, err
+			}
+			return makeValueBytes(FixedLenByteArray, u[:]), nil
+
+		case lt.Integer != nil:
+			if !lt.Integer.IsSigned {
+				u, err := strconv.ParseUint(s, 10, int(lt.Integer.BitWidth))
+				if err != nil {
+					return Value{}, err
+				}
+				if t.Kind() == Int32 {
+					return makeValueInt32(int32(u)), nil
+				}
+				return makeValueInt64(int64(u)), nil
+			}
+		}
+	}
+
+	switch kind := t.Kind(); kind {
+	case Boolean:
+		b, err := strconv.ParseBool(s)
+		return makeValueBoolean(b), err
+	case Int32:
+		i, err := strconv.ParseInt(s, 10, 32)
+		return makeValueInt32(int32(i)), err
+	case Int64:
+		i, err := strconv.ParseInt(s, 10, 64)
+		return makeValueInt64(i), err
+	case Int96:
+		i, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return Value{}, fmt.Errorf("invalid integer")
+		}
+		b := i.Bytes()
+		if len(b) > 12 || i.Sign() < 0 {
+			return Value{}, fmt.Errorf("integer out of range")
+		}
+		var i96 deprecated.Int96
+		for j := range b {
+			k := len(b) - (j + 1)
+			i96[j/4] |= uint32(b[k]) << (8 * uint(j%4))
+		}
+		return makeValueInt96(i96), nil
+	case Float:
+		f, err := strconv.ParseFloat(s, 32)
+		return makeValueFloat(float32(f)), err
+	case Double:
+		f, err := strconv.ParseFloat(s, 64)
+		return makeValueDouble(f), err
+	case FixedLenByteArray:
+		if len(s) != t.Length() {
+			return Value{}, fmt.Errorf("length mismatch: %d != %d", len(s), t.Length())
+		}
+		return makeValueString(kind, s), nil
+	default:
+		return makeValueString(kind, s), nil
+	}
+}
+
+func parseDecimalText(t Type, s string, scale int) (Value, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Value{}, fmt.Errorf("invalid decimal")
+	}
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)))
+	if !r.IsInt() {
+		return Value{}, fmt.Errorf("too many digits after the decimal point")
+	}
+	unscaled := r.Num()
+
+	switch kind := t.Kind(); kind {
+	case Int32:
+		if !unscaled.IsInt64() || unscaled.Int64() != int64(int32(unscaled.Int64())) {
+			return Value{}, fmt.Errorf("decimal out of range")
+		}
+		return makeValueInt32(int32(unscaled.Int64())), nil
+	case Int64:
+		if !unscaled.IsInt64() {
+			return Value{}, fmt.Errorf("decimal out of range")
+		}
+		return makeValueInt64(unscaled.Int64()), nil
+	default:
+		// Encode the unscaled value as a big-endian two's complement integer.
+		size := t.Length()
+		if kind == ByteArray {
+			size = unscaled.BitLen()/8 + 1
+		}
+		bitLen := unscaled.BitLen()
+		if unscaled.Sign() < 0 {
+			bitLen = new(big.Int).Not(unscaled).BitLen()
+			unscaled = new(big.Int).Add(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(8*size)))
+		}
+		if bitLen >= 8*size {
+			return Value{}, fmt.Errorf("decimal out of range")
+		}
+		b := unscaled.Bytes()
+		data := make([]byte, size)
+		copy(data[size-len(b):], b)
+		return makeValueBytes(kind, data), nil
+	}
+}
+
+func timeUnitDuration(unit *format.TimeUnit) time.Duration {
+	switch {
+	case unit.Millis != nil:
+		return time.Millisecond
+	case unit.Micros != nil:
+		return time.Microsecond
+	default:
+		return time.Nanosecond
+	}
+}
@@ -0,0 +1,75 @@
+package parquet
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Transcode writes all the rows of the parquet file src to dst, returning the
+// number of rows that were written.
+//
+// The function is useful to change the compression, encodings, sort order, or
+// encryption of existing parquet files. When the schema of the file matches the
+// one of the writer, the row groups of the file are preserved and their pages
+// are copied without being decoded if they were encoded and compressed the
+// same way as the writer's columns. Otherwise, the rows are converted to the
+// schema of the writer.
+//
+// If sorting columns were configured on dst, row groups that were not sorted
+// the same way are sorted in memory before being written. If dst has limits on
+// the size or number of rows of row groups, the rows are written one at a time
+// and the writer decides where row groups are flushed.
+//
+// The writer is not closed by the function, which allows programs to write the
+// rows of multiple files to the same output.
+func Transcode(dst *Writer, src *File) (int64, error) {
+	numRows := int64(0)
+
+	for i, n := 0, src.NumRowGroups(); i < n; i++ {
+		rowGroup, err := transcodeRowGroup(dst, src.RowGroup(i))
+		if err != nil {
+			return numRows, fmt.Errorf("transcoding row group %d: %w", i, err)
+		}
+
+		var written int64
+		if dst.writer.hasRowGroupLimits() {
+			written, err = dst.ReadRowsFrom(rowGroup.Rows())
+		} else {
+			written, err = dst.WriteRowGroup(rowGroup)
+		}
+		numRows += written
+		if err != nil {
+			return numRows, fmt.Errorf("transcoding row group %d: %w", i, err)
+		}
+	}
+
+	return numRows, nil
+}
+
+func transcodeRowGroup(dst *Writer, rowGroup RowGroup) (RowGroup, error) {
+	if dst.schema == nil {
+		if err := dst.configureFrom(rowGroup.Schema()); err != nil {
+			return nil, err
+		}
+	}
+
+	if !nodesAreEqual(dst.schema, rowGroup.Schema()) {
+		conv, err := Convert(dst.schema, rowGroup.Schema())
+		if err != nil {
+			return nil, err
+		}
+		rowGroup = ConvertRowGroup(rowGroup, conv)
+	}
+
+	sorting := dst.config.SortingColumns
+	if len(sorting) == 0 || sortingColumnsHavePrefix(rowGroup.SortingColumns(), sorting) {
+		return rowGroup, nil
+	}
+
+	buffer := NewBuffer(dst.schema, SortingColumns(sorting...))
+	if _, err := CopyRows(bufferWriter{buffer}, rowGroup.Rows()); err != nil {
+		return nil, err
+	}
+	sort.Sort(buffer)
+	return buffer, nil
+}
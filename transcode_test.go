@@ -0,0 +1,220 @@
+package parquet_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/format"
+)
+
+type transcodeRow struct {
+	ID    int64    `parquet:"id,zstd"`
+	Name  string   `parquet:"name,dict,zstd"`
+	Tags  []string `parquet:"tags,zstd"`
+	Value float64  `parquet:"value,zstd"`
+}
+
+func makeTranscodeFile(t *testing.T, numRowGroups, numRows int) *parquet.File {
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	for i := 0; i < numRowGroups; i++ {
+		for j := 0; j < numRows; j++ {
+			// IDs are written in descending order so sorting the rows has an
+			// effect on the output.
+			id := numRowGroups*numRows - (i*numRows + j)
+			row := transcodeRow{
+				ID:    int64(id),
+				Name:  fmt.Sprintf("name-%d", id%10),
+				Tags:  []string{"a", "b", "c"}[:id%4],
+				Value: float64(id) / 2,
+			}
+			if err := writer.Write(&row); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := writer.Flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func readTranscodeRows(t *testing.T, f *parquet.File) []transcodeRow {
+	reader := parquet.NewReader(f)
+	rows := make([]transcodeRow, 0, f.NumRows())
+	for {
+		var row transcodeRow
+		if err := reader.Read(&row); err != nil {
+			if err == io.EOF {
+				return rows
+			}
+			t.Fatal(err)
+		}
+		rows = append(rows, row)
+	}
+}
+
+func TestTranscode(t *testing.T) {
+	const numRowGroups = 3
+	const numRows = 500
+
+	footerKey := []byte("0123456789012345")
+
+	tests := []struct {
+		scenario     string
+		options      []parquet.WriterOption
+		decryption   *parquet.DecryptionConfig
+		numRowGroups int
+		codec        format.CompressionCodec
+		sorted       bool
+	}{
+		{
+			scenario:     "copy",
+			numRowGroups: numRowGroups,
+			codec:        format.Zstd,
+		},
+
+		{
+			scenario: "compression",
+			options: []parquet.WriterOption{
+				parquet.ColumnCompression(&parquet.Snappy, "id"),
+				parquet.ColumnCompression(&parquet.Snappy, "name"),
+				parquet.ColumnCompression(&parquet.Snappy, "tags"),
+				parquet.ColumnCompression(&parquet.Snappy, "value"),
+			},
+			numRowGroups: numRowGroups,
+			codec:        format.Snappy,
+		},
+
+		{
+			scenario: "sorting",
+			options: []parquet.WriterOption{
+				parquet.SortingColumns(parquet.Ascending("id")),
+			},
+			numRowGroups: numRowGroups,
+			codec:        format.Zstd,
+			sorted:       true,
+		},
+
+		{
+			scenario: "encryption",
+			options: []parquet.WriterOption{
+				parquet.Encryption(&parquet.EncryptionConfig{FooterKey: footerKey}),
+			},
+			decryption:   &parquet.DecryptionConfig{FooterKey: footerKey},
+			numRowGroups: numRowGroups,
+			codec:        format.Zstd,
+		},
+
+		{
+			scenario: "row group limits",
+			options: []parquet.WriterOption{
+				parquet.MaxRowsPerRowGroup(numRowGroups * numRows / 2),
+			},
+			numRowGroups: 2,
+			codec:        format.Zstd,
+		},
+	}
+
+	src := makeTranscodeFile(t, numRowGroups, numRows)
+	want := readTranscodeRows(t, src)
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			writer := parquet.NewWriter(buffer, test.options...)
+
+			n, err := parquet.Transcode(writer, src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != src.NumRows() {
+				t.Errorf("wrong number of rows transcoded: want=%d got=%d", src.NumRows(), n)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()), parquet.Decryption(test.decryption))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rowGroups := f.Metadata().RowGroups
+			if len(rowGroups) != test.numRowGroups {
+				t.Errorf("wrong number of row groups: want=%d got=%d", test.numRowGroups, len(rowGroups))
+			}
+			for _, rowGroup := range rowGroups {
+				for _, column := range rowGroup.Columns {
+					if column.MetaData.Codec != test.codec {
+						t.Errorf("wrong codec for column %v: want=%s got=%s", column.MetaData.PathInSchema, test.codec, column.MetaData.Codec)
+					}
+				}
+			}
+
+			got := readTranscodeRows(t, f)
+			if len(got) != len(want) {
+				t.Fatalf("wrong number of rows: want=%d got=%d", len(want), len(got))
+			}
+
+			for i, row := range got {
+				wantRow := want[i]
+				if test.sorted {
+					// Each row group is sorted in ascending order of IDs, the
+					// source row groups were in descending order.
+					rowGroup := i / numRows
+					wantRow = want[rowGroup*numRows+(numRows-1-i%numRows)]
+				}
+				if row.ID != wantRow.ID || row.Name != wantRow.Name || len(row.Tags) != len(wantRow.Tags) || row.Value != wantRow.Value {
+					t.Fatalf("row %d mismatch:\nwant = %+v\ngot  = %+v", i, wantRow, row)
+				}
+			}
+		})
+	}
+}
+
+func TestTranscodeConvertSchema(t *testing.T) {
+	type Row struct {
+		Name string  `parquet:"name"`
+		ID   int64   `parquet:"id"`
+		Note *string `parquet:"note,optional"`
+	}
+
+	src := makeTranscodeFile(t, 2, 100)
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.SchemaOf(Row{}))
+
+	if _, err := parquet.Transcode(writer, src); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewReader(f)
+	for i := 0; i < 200; i++ {
+		var row Row
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		id := 200 - i
+		if row.ID != int64(id) || row.Name != fmt.Sprintf("name-%d", id%10) || row.Note != nil {
+			t.Fatalf("row %d mismatch: %+v", i, row)
+		}
+	}
+}
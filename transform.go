@@ -0,0 +1,194 @@
+package parquet
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"unicode/utf8"
+)
+
+// ValueTransform is the signature of functions applied to the values of
+// columns written to parquet files, configured with the ColumnTransform
+// writer option.
+//
+// The function is only called with non-null values. It must return a value
+// of the same kind as its argument, or a null value if the column is
+// optional; the levels and column index of the returned value are set by the
+// writer.
+type ValueTransform func(Value) Value
+
+// ColumnTransformConfig carries the transformation applied to values of the
+// leaf column at the given path.
+type ColumnTransformConfig struct {
+	// The path of the leaf column that the transformation applies to.
+	Path []string
+
+	// The function applied to non-null values of the column.
+	Transform ValueTransform
+}
+
+// searchColumnTransform returns the transformation configured for the column
+// at the given path, or nil if there were none. When the path is configured
+// multiple times, the last transformation takes precedence.
+func searchColumnTransform(transforms []ColumnTransformConfig, path columnPath) ValueTransform {
+	for i := len(transforms) - 1; i >= 0; i-- {
+		if path.equal(transforms[i].Path) {
+			return transforms[i].Transform
+		}
+	}
+	return nil
+}
+
+// HashValues returns a transformation replacing values by a hash of their
+// content, which is typically used to redact personal information while
+// retaining the ability to join or group on the column.
+//
+// When key is not empty, the values are hashed with HMAC-SHA256 using the
+// key, which prevents recovering values from their hash by brute force.
+// Otherwise, the SHA-256 hash of values is used.
+//
+// Byte arrays are replaced by the hexadecimal representation of the hash,
+// fixed length byte arrays, 32 and 64 bits integers by the leading bytes of
+// the hash. Values of other types are replaced by nulls.
+func HashValues(key []byte) ValueTransform {
+	key = append([]byte{}, key...)
+	newHash := sha256.New
+	if len(key) != 0 {
+		newHash = func() hash.Hash { return hmac.New(sha256.New, key) }
+	}
+	// The transformation may be shared by writers used concurrently, so no
+	// state is retained across calls.
+	return func(v Value) Value {
+		var b [8]byte
+		var sum [sha256.Size]byte
+		h := newHash()
+
+		switch v.Kind() {
+		case ByteArray, FixedLenByteArray:
+			h.Write(v.ByteArray())
+		case Int32, Int64:
+			h.Write(v.AppendBytes(b[:0]))
+		default:
+			return Value{}
+		}
+		h.Sum(sum[:0])
+
+		switch v.Kind() {
+		case ByteArray:
+			return makeValueString(ByteArray, hex.EncodeToString(sum[:]))
+		case FixedLenByteArray:
+			data := make([]byte, len(v.ByteArray()))
+			for i := 0; i < len(data); i += copy(data[i:], sum[:]) {
+			}
+			return makeValueBytes(FixedLenByteArray, data)
+		case Int32:
+			return makeValueInt32(int32(binary.LittleEndian.Uint32(sum[:4])))
+		default:
+			return makeValueInt64(int64(binary.LittleEndian.Uint64(sum[:8])))
+		}
+	}
+}
+
+// MaskValues returns a transformation replacing byte arrays by mask, and
+// values of other types by the zero value of their type.
+func MaskValues(mask string) ValueTransform {
+	return func(v Value) Value {
+		switch v.Kind() {
+		case ByteArray:
+			return makeValueString(ByteArray, mask)
+		case FixedLenByteArray, Int96:
+			return makeValueBytes(v.Kind(), make([]byte, len(v.ByteArray())))
+		default:
+			return Value{kind: v.kind}
+		}
+	}
+}
+
+// TruncateValues returns a transformation truncating byte arrays to at most
+// n bytes, without splitting UTF-8 sequences. Values of other types are left
+// unchanged.
+func TruncateValues(n int) ValueTransform {
+	if n < 0 {
+		panic(fmt.Sprintf("cannot truncate parquet values to a negative length: %d", n))
+	}
+	return func(v Value) Value {
+		if v.Kind() != ByteArray {
+			return v
+		}
+		b := v.ByteArray()
+		if len(b) <= n {
+			return v
+		}
+		i := n
+		for i > 0 && !utf8.RuneStart(b[i]) {
+			i--
+		}
+		return makeValueBytes(ByteArray, b[:i])
+	}
+}
+
+// columnTransformWriter is a row writer which applies the column
+// transformations configured on a parquet writer to the rows it writes.
+type columnTransformWriter struct {
+	writer  RowWriter
+	columns []columnTransform
+	row     Row
+}
+
+type columnTransform struct {
+	path               columnPath
+	transform          ValueTransform
+	kind               Kind
+	optional           bool
+	maxDefinitionLevel int16
+}
+
+func newColumnTransformWriter(writer RowWriter, schema *Schema, transforms []ColumnTransformConfig) *columnTransformWriter {
+	w := &columnTransformWriter{writer: writer}
+	forEachLeafColumnOf(schema, func(leaf leafColumn) {
+		w.columns = append(w.columns, columnTransform{
+			path:               leaf.path,
+			transform:          searchColumnTransform(transforms, leaf.path),
+			kind:               leaf.node.Type().Kind(),
+			optional:           leaf.node.Optional(),
+			maxDefinitionLevel: leaf.maxDefinitionLevel,
+		})
+	})
+	return w
+}
+
+func (w *columnTransformWriter) WriteRow(row Row) error {
+	defer func() {
+		clearValues(w.row)
+	}()
+
+	w.row = append(w.row[:0], row...)
+
+	for i, v := range w.row {
+		c := &w.columns[v.Column()]
+		if c.transform == nil || v.IsNull() {
+			continue
+		}
+
+		t := c.transform(v)
+		switch {
+		case t.IsNull():
+			if !c.optional {
+				return fmt.Errorf("transforming column %q: null value in non-optional column", c.path)
+			}
+			t.definitionLevel = c.maxDefinitionLevel - 1
+		case t.Kind() != c.kind:
+			return fmt.Errorf("transforming column %q: value of kind %s in column of kind %s", c.path, t.Kind(), c.kind)
+		default:
+			t.definitionLevel = v.definitionLevel
+		}
+		t.repetitionLevel = v.repetitionLevel
+		t.columnIndex = v.columnIndex
+		w.row[i] = t
+	}
+
+	return w.writer.WriteRow(w.row)
+}
@@ -0,0 +1,134 @@
+package parquet_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestColumnTransform(t *testing.T) {
+	type Address struct {
+		Street string `parquet:"street"`
+		City   string `parquet:"city"`
+	}
+
+	type Person struct {
+		Name    string  `parquet:"name"`
+		Email   string  `parquet:"email"`
+		Phone   *string `parquet:"phone,optional"`
+		ID      int64   `parquet:"id"`
+		Address Address `parquet:"address"`
+	}
+
+	phone := "555-0100"
+	people := []Person{
+		{Name: "Émile Zola", Email: "emile@example.com", Phone: &phone, ID: 1, Address: Address{Street: "1 rue de Paris", City: "Paris"}},
+		{Name: "Luke", Email: "luke@example.com", ID: 2, Address: Address{Street: "2 Tatooine Way", City: "Mos Eisley"}},
+	}
+
+	key := []byte("secret")
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer,
+		parquet.ColumnTransform(parquet.HashValues(key), "email"),
+		parquet.ColumnTransform(parquet.HashValues(nil), "id"),
+		parquet.ColumnTransform(parquet.MaskValues("***"), "address", "street"),
+		parquet.ColumnTransform(parquet.TruncateValues(2), "name"),
+		parquet.ColumnTransform(func(parquet.Value) parquet.Value { return parquet.Value{} }, "phone"),
+	)
+
+	if err := writer.Write(&people[0]); err != nil {
+		t.Fatal(err)
+	}
+	// Rows of row groups must be transformed as well, instead of having their
+	// pages copied to the output.
+	rowGroup := parquet.NewBuffer(writer.Schema())
+	if err := rowGroup.Write(&people[1]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.WriteRowGroup(rowGroup); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	for i, person := range people {
+		got := Person{}
+		if err := reader.Read(&got); err != nil {
+			t.Fatal(err)
+		}
+
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(person.Email))
+		if want := hex.EncodeToString(mac.Sum(nil)); got.Email != want {
+			t.Errorf("wrong hashed email at index %d: want=%q got=%q", i, want, got.Email)
+		}
+		if got.ID == person.ID || got.ID == 0 {
+			t.Errorf("id at index %d was not hashed: %d", i, got.ID)
+		}
+		if got.Address.Street != "***" {
+			t.Errorf("wrong masked street at index %d: %q", i, got.Address.Street)
+		}
+		if got.Address.City != person.Address.City {
+			t.Errorf("wrong city at index %d: want=%q got=%q", i, person.Address.City, got.Address.City)
+		}
+		if got.Phone != nil {
+			t.Errorf("phone at index %d was not redacted: %q", i, *got.Phone)
+		}
+	}
+}
+
+func TestColumnTransformErrors(t *testing.T) {
+	type Row struct {
+		Name string `parquet:"name"`
+	}
+
+	for _, test := range []struct {
+		scenario  string
+		transform parquet.ValueTransform
+	}{
+		{
+			scenario:  "null value in required column",
+			transform: func(parquet.Value) parquet.Value { return parquet.Value{} },
+		},
+		{
+			scenario:  "value of the wrong kind",
+			transform: func(parquet.Value) parquet.Value { return parquet.ValueOf(int64(1)) },
+		},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			writer := parquet.NewWriter(new(bytes.Buffer), parquet.ColumnTransform(test.transform, "name"))
+			if err := writer.Write(&Row{Name: "A"}); err == nil {
+				t.Error("expected an error but got none")
+			}
+		})
+	}
+
+	if _, err := parquet.NewWriterConfig(parquet.ColumnTransform(nil, "name")); err == nil {
+		t.Error("expected an error when configuring a nil transformation")
+	}
+}
+
+func TestTruncateValues(t *testing.T) {
+	for _, test := range []struct {
+		input  string
+		n      int
+		output string
+	}{
+		{input: "hello", n: 10, output: "hello"},
+		{input: "hello", n: 3, output: "hel"},
+		{input: "héllo", n: 2, output: "h"},
+		{input: "héllo", n: 3, output: "hé"},
+		{input: "héllo", n: 0, output: ""},
+	} {
+		v := parquet.TruncateValues(test.n)(parquet.ValueOf(test.input))
+		if got := v.String(); got != test.output {
+			t.Errorf("truncating %q to %d bytes: want=%q got=%q", test.input, test.n, test.output, got)
+		}
+	}
+}
@@ -629,6 +629,115 @@ func (t *bsonType) ReadDictionary(columnIndex, numValues int, decoder encoding.D
 	return readByteArrayDictionary(t, makeColumnIndex(columnIndex), numValues, decoder)
 }
 
+// Geometry constructs a leaf node of GEOMETRY logical type, holding features in
+// the Well-Known Binary (WKB) format. The coordinate reference system defaults
+// to OGC:CRS84 when crs is empty.
+//
+// https://github.com/apache/parquet-format/blob/master/Geospatial.md
+func Geometry(crs string) Node { return Leaf(&geometryType{CRS: crs}) }
+
+type geometryType format.GeometryType
+
+func (t *geometryType) String() string { return (*format.GeometryType)(t).String() }
+
+func (t *geometryType) Kind() Kind { return ByteArray }
+
+func (t *geometryType) Length() int { return 0 }
+
+func (t *geometryType) Compare(a, b Value) int {
+	return bytes.Compare(a.ByteArray(), b.ByteArray())
+}
+
+func (t *geometryType) ColumnOrder() *format.ColumnOrder {
+	return &typeDefinedColumnOrder
+}
+
+func (t *geometryType) PhysicalType() *format.Type {
+	return &physicalTypes[ByteArray]
+}
+
+func (t *geometryType) LogicalType() *format.LogicalType {
+	return &format.LogicalType{Geometry: (*format.GeometryType)(t)}
+}
+
+func (t *geometryType) ConvertedType() *deprecated.ConvertedType { return nil }
+
+func (t *geometryType) NewColumnIndexer(sizeLimit int) ColumnIndexer {
+	return newByteArrayColumnIndexer(sizeLimit)
+}
+
+func (t *geometryType) NewDictionary(columnIndex, bufferSize int) Dictionary {
+	return newByteArrayDictionary(t, makeColumnIndex(columnIndex), bufferSize)
+}
+
+func (t *geometryType) NewColumnBuffer(columnIndex, bufferSize int) ColumnBuffer {
+	return newByteArrayColumnBuffer(t, makeColumnIndex(columnIndex), bufferSize)
+}
+
+func (t *geometryType) NewColumnReader(columnIndex, bufferSize int) ColumnReader {
+	return newByteArrayColumnReader(t, makeColumnIndex(columnIndex), bufferSize)
+}
+
+func (t *geometryType) ReadDictionary(columnIndex, numValues int, decoder encoding.Decoder) (Dictionary, error) {
+	return readByteArrayDictionary(t, makeColumnIndex(columnIndex), numValues, decoder)
+}
+
+// Geography constructs a leaf node of GEOGRAPHY logical type, holding features
+// in the Well-Known Binary (WKB) format with edges interpolated on a spheroid
+// using the given algorithm. The coordinate reference system defaults to
+// OGC:CRS84 when crs is empty.
+//
+// https://github.com/apache/parquet-format/blob/master/Geospatial.md
+func Geography(crs string, algorithm format.EdgeInterpolationAlgorithm) Node {
+	return Leaf(&geographyType{CRS: crs, Algorithm: algorithm})
+}
+
+type geographyType format.GeographyType
+
+func (t *geographyType) String() string { return (*format.GeographyType)(t).String() }
+
+func (t *geographyType) Kind() Kind { return ByteArray }
+
+func (t *geographyType) Length() int { return 0 }
+
+func (t *geographyType) Compare(a, b Value) int {
+	return bytes.Compare(a.ByteArray(), b.ByteArray())
+}
+
+func (t *geographyType) ColumnOrder() *format.ColumnOrder {
+	return &typeDefinedColumnOrder
+}
+
+func (t *geographyType) PhysicalType() *format.Type {
+	return &physicalTypes[ByteArray]
+}
+
+func (t *geographyType) LogicalType() *format.LogicalType {
+	return &format.LogicalType{Geography: (*format.GeographyType)(t)}
+}
+
+func (t *geographyType) ConvertedType() *deprecated.ConvertedType { return nil }
+
+func (t *geographyType) NewColumnIndexer(sizeLimit int) ColumnIndexer {
+	return newByteArrayColumnIndexer(sizeLimit)
+}
+
+func (t *geographyType) NewDictionary(columnIndex, bufferSize int) Dictionary {
+	return newByteArrayDictionary(t, makeColumnIndex(columnIndex), bufferSize)
+}
+
+func (t *geographyType) NewColumnBuffer(columnIndex, bufferSize int) ColumnBuffer {
+	return newByteArrayColumnBuffer(t, makeColumnIndex(columnIndex), bufferSize)
+}
+
+func (t *geographyType) NewColumnReader(columnIndex, bufferSize int) ColumnReader {
+	return newByteArrayColumnReader(t, makeColumnIndex(columnIndex), bufferSize)
+}
+
+func (t *geographyType) ReadDictionary(columnIndex, numValues int, decoder encoding.Decoder) (Dictionary, error) {
+	return readByteArrayDictionary(t, makeColumnIndex(columnIndex), numValues, decoder)
+}
+
 // Date constructs a leaf node of DATE logical type.
 //
 // https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#date
@@ -696,9 +805,18 @@ func (u *nanosecond) TimeUnit() format.TimeUnit {
 
 // Time constructs a leaf node of TIME logical type.
 //
+// The times are adjusted to UTC, use TimeAdjusted to construct columns of local
+// times instead.
+//
 // https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#time
 func Time(unit TimeUnit) Node {
-	return Leaf(&timeType{IsAdjustedToUTC: true, Unit: unit.TimeUnit()})
+	return TimeAdjusted(unit, true)
+}
+
+// TimeAdjusted constructs a leaf node of TIME logical type, with the
+// isAdjustedToUTC flag set to adjustedToUTC.
+func TimeAdjusted(unit TimeUnit, adjustedToUTC bool) Node {
+	return Leaf(&timeType{IsAdjustedToUTC: adjustedToUTC, Unit: unit.TimeUnit()})
 }
 
 type timeType format.TimeType
@@ -768,9 +886,24 @@ func (t *timeType) ConvertedType() *deprecated.ConvertedType {
 
 // Timestamp constructs of leaf node of TIMESTAMP logical type.
 //
+// The timestamps are adjusted to UTC, use TimestampAdjusted to construct
+// columns of local timestamps instead.
+//
 // https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#timestamp
 func Timestamp(unit TimeUnit) Node {
-	return Leaf(&timestampType{IsAdjustedToUTC: true, Unit: unit.TimeUnit()})
+	return TimestampAdjusted(unit, true)
+}
+
+// TimestampAdjusted constructs a leaf node of TIMESTAMP logical type, with the
+// isAdjustedToUTC flag set to adjustedToUTC.
+//
+// When adjustedToUTC is true, the values represent instants which are read back
+// as time.Time values in the UTC location. When adjustedToUTC is false, the
+// values represent the wall clock reading of the time.Time values they were
+// written from, regardless of their location, and are read back in the local
+// time zone.
+func TimestampAdjusted(unit TimeUnit, adjustedToUTC bool) Node {
+	return Leaf(&timestampType{IsAdjustedToUTC: adjustedToUTC, Unit: unit.TimeUnit()})
 }
 
 type timestampType format.TimestampType
@@ -802,6 +935,32 @@ func (t *timestampType) ConvertedType() *deprecated.ConvertedType {
 	}
 }
 
+// timeValue returns the value representing tm in columns of type t.
+func (t *timestampType) timeValue(tm time.Time) Value {
+	if !t.IsAdjustedToUTC {
+		// Local timestamps record the wall clock reading of tm, which is the
+		// time in the UTC location shifted by the offset of its time zone.
+		_, offset := tm.Zone()
+		tm = tm.Add(time.Duration(offset) * time.Second)
+	}
+	unit := timeUnitDuration(&t.Unit)
+	perSecond := int64(time.Second / unit)
+	return makeValueInt64(tm.Unix()*perSecond + int64(tm.Nanosecond())/int64(unit))
+}
+
+// timeOf returns the time.Time represented by v in columns of type t, in the
+// UTC location if t is adjusted to UTC, and the local time zone otherwise.
+func (t *timestampType) timeOf(v Value) time.Time {
+	unit := timeUnitDuration(&t.Unit)
+	perSecond := int64(time.Second / unit)
+	n := v.Int64()
+	tm := time.Unix(n/perSecond, (n%perSecond)*int64(unit)).UTC()
+	if !t.IsAdjustedToUTC {
+		tm = time.Date(tm.Year(), tm.Month(), tm.Day(), tm.Hour(), tm.Minute(), tm.Second(), tm.Nanosecond(), time.Local)
+	}
+	return tm
+}
+
 // List constructs a node of LIST logical type.
 //
 // https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#lists
@@ -913,44 +1072,140 @@ func (t *mapType) ReadDictionary(int, int, encoding.Decoder) (Dictionary, error)
 	panic("cannot read dictionary from parquet MAP type")
 }
 
-type nullType format.NullType
+// Variant constructs a node of VARIANT logical type, holding semi-structured
+// values encoded in the Variant binary format as a pair of metadata and value
+// byte arrays.
+//
+// https://github.com/apache/parquet-format/blob/master/VariantEncoding.md
+func Variant() Node {
+	return variantNode{Group: Group{
+		"metadata": Leaf(ByteArrayType),
+		"value":    Leaf(ByteArrayType),
+	}}
+}
 
-func (t *nullType) String() string { return (*format.NullType)(t).String() }
+// ShreddedVariant constructs a node of VARIANT logical type where values may
+// be shredded into the typed_value column, the value column then only holds
+// the values which could not be represented by typedValue.
+//
+// https://github.com/apache/parquet-format/blob/master/VariantShredding.md
+func ShreddedVariant(typedValue Node) Node {
+	return variantNode{Group: Group{
+		"metadata":    Leaf(ByteArrayType),
+		"value":       Optional(Leaf(ByteArrayType)),
+		"typed_value": Optional(typedValue),
+	}}
+}
+
+type variantNode struct {
+	Group
+	version int8
+}
+
+func (n variantNode) Type() Type { return &variantType{SpecificationVersion: n.version} }
 
-func (t *nullType) Kind() Kind { panic("cannot call Kind on parquet NULL type") }
+type variantType format.VariantType
 
-func (t *nullType) Length() int { return 0 }
+func (t *variantType) String() string { return (*format.VariantType)(t).String() }
 
-func (t *nullType) Compare(Value, Value) int { panic("cannot compare values on parquet NULL type") }
+func (t *variantType) Kind() Kind { panic("cannot call Kind on parquet VARIANT type") }
+
+func (t *variantType) Length() int { return 0 }
+
+func (t *variantType) Compare(Value, Value) int {
+	panic("cannot compare values on parquet VARIANT type")
+}
 
-func (t *nullType) ColumnOrder() *format.ColumnOrder { return nil }
+func (t *variantType) ColumnOrder() *format.ColumnOrder { return nil }
 
-func (t *nullType) PhysicalType() *format.Type { return nil }
+func (t *variantType) PhysicalType() *format.Type { return nil }
+
+func (t *variantType) LogicalType() *format.LogicalType {
+	return &format.LogicalType{Variant: (*format.VariantType)(t)}
+}
+
+func (t *variantType) ConvertedType() *deprecated.ConvertedType { return nil }
+
+func (t *variantType) NewColumnIndexer(int) ColumnIndexer {
+	panic("create create column indexer from parquet VARIANT type")
+}
+
+func (t *variantType) NewDictionary(int, int) Dictionary {
+	panic("cannot create dictionary from parquet VARIANT type")
+}
+
+func (t *variantType) NewColumnBuffer(int, int) ColumnBuffer {
+	panic("cannot create column buffer from parquet VARIANT type")
+}
+
+func (t *variantType) NewColumnReader(int, int) ColumnReader {
+	panic("cannot create column reader from parquet VARIANT type")
+}
+
+func (t *variantType) ReadDictionary(int, int, encoding.Decoder) (Dictionary, error) {
+	panic("cannot read dictionary from parquet VARIANT type")
+}
+
+// Null constructs a leaf node of NULL logical type, for columns of the given
+// underlying type which only contain null values. Query engines commonly
+// write such columns as optional int32 for typed NULL literals.
+//
+// https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#unknown-always-null
+func Null(typ Type) Node { return Leaf(&nullType{Type: typ}) }
+
+type nullType struct {
+	null format.NullType
+	Type
+}
+
+func (t *nullType) String() string { return t.null.String() }
 
 func (t *nullType) LogicalType() *format.LogicalType {
-	return &format.LogicalType{Unknown: (*format.NullType)(t)}
+	return &format.LogicalType{Unknown: &t.null}
 }
 
 func (t *nullType) ConvertedType() *deprecated.ConvertedType { return nil }
 
-func (t *nullType) NewColumnIndexer(int) ColumnIndexer {
-	panic("create create column indexer from parquet NULL type")
+// unsupportedType is the type of columns that use a non-standard type or one
+// from a newer version of the format than this package supports.
+type unsupportedType struct{}
+
+func (t *unsupportedType) String() string { return "UNSUPPORTED" }
+
+func (t *unsupportedType) Kind() Kind { panic("cannot call Kind on unsupported parquet type") }
+
+func (t *unsupportedType) Length() int { return 0 }
+
+func (t *unsupportedType) Compare(Value, Value) int {
+	panic("cannot compare values on unsupported parquet type")
+}
+
+func (t *unsupportedType) ColumnOrder() *format.ColumnOrder { return nil }
+
+func (t *unsupportedType) PhysicalType() *format.Type { return nil }
+
+func (t *unsupportedType) LogicalType() *format.LogicalType { return nil }
+
+func (t *unsupportedType) ConvertedType() *deprecated.ConvertedType { return nil }
+
+func (t *unsupportedType) NewColumnIndexer(int) ColumnIndexer {
+	panic("cannot create column indexer from unsupported parquet type")
 }
 
-func (t *nullType) NewDictionary(int, int) Dictionary {
-	panic("cannot create dictionary from parquet NULL type")
+func (t *unsupportedType) NewDictionary(int, int) Dictionary {
+	panic("cannot create dictionary from unsupported parquet type")
 }
 
-func (t *nullType) NewColumnBuffer(int, int) ColumnBuffer {
-	panic("cannot create column buffer from parquet NULL type")
+func (t *unsupportedType) NewColumnBuffer(int, int) ColumnBuffer {
+	panic("cannot create column buffer from unsupported parquet type")
 }
 
-func (t *nullType) NewColumnReader(int, int) ColumnReader {
-	panic("cannot create column reader from parquet NULL type")
+func (t *unsupportedType) NewColumnReader(int, int) ColumnReader {
+	panic("cannot create column reader from unsupported parquet type")
 }
 
-func (t *nullType) ReadDictionary(int, int, encoding.Decoder) (Dictionary, error) {
-	panic("cannot read dictionary from parquet NULL type")
+func (t *unsupportedType) ReadDictionary(int, int, encoding.Decoder) (Dictionary, error) {
+	panic("cannot read dictionary from unsupported parquet type")
 }
 
 type groupType struct{}
@@ -8,6 +8,7 @@ import (
 	"math"
 	"reflect"
 	"strconv"
+	"time"
 	"unsafe"
 
 	"github.com/google/uuid"
@@ -33,14 +34,27 @@ type Value struct {
 	u64 uint64
 	// type
 	kind int8 // XOR(Kind) so the zero-value is <null>
+	// set on byte arrays referencing memory that is never overwritten, which
+	// can be converted to Go strings without being copied
+	interned bool
 	// levels
-	definitionLevel int8
-	repetitionLevel int8
+	definitionLevel int16
+	repetitionLevel int16
 	columnIndex     int16 // XOR so the zero-value is -1
 }
 
 // ValueReader is an interface implemented by types that support reading
 // batches of values.
+//
+// It is the common interface to read values from the various sources of the
+// package, which allows generic code to process values in batches regardless
+// of where they come from:
+//
+//   - pages expose their values with the Values method of Page,
+//   - column chunks, including the column buffers of a Buffer, are read with
+//     NewColumnChunkValueReader,
+//   - CopyValues copies values from a ValueReader to a ValueWriter, such as
+//     a column buffer.
 type ValueReader interface {
 	// Read values into the buffer passed as argument and return the number of
 	// values read. When all values have been read, the error will be io.EOF.
@@ -294,6 +308,13 @@ func makeValueInt96(value deprecated.Int96) Value {
 	}
 }
 
+func makeValueDuration(kind Kind, value, unit time.Duration) Value {
+	if kind == Int32 {
+		return makeValueInt32(int32(value / unit))
+	}
+	return makeValueInt64(int64(value / unit))
+}
+
 func makeValueFloat(value float32) Value {
 	return Value{
 		kind: ^int8(Float),
@@ -365,6 +386,15 @@ func (v Value) Float() float32 { return math.Float32frombits(uint32(v.u64)) }
 // Double returns v as a float64, assuming the underlying type is DOUBLE.
 func (v Value) Double() float64 { return math.Float64frombits(v.u64) }
 
+// duration returns v as a time.Duration, assuming the underlying type is INT32
+// or INT64 and holds a number of the given time unit.
+func (v Value) duration(unit time.Duration) time.Duration {
+	if v.Kind() == Int32 {
+		return time.Duration(v.Int32()) * unit
+	}
+	return time.Duration(v.Int64()) * unit
+}
+
 // ByteArray returns v as a []byte, assuming the underlying type is either
 // BYTE_ARRAY or FIXED_LEN_BYTE_ARRAY.
 //
@@ -458,7 +488,11 @@ func (v Value) Format(w fmt.State, r rune) {
 		case ByteArray, FixedLenByteArray:
 			fmt.Fprintf(w, "%q", v.ByteArray())
 		default:
-			fmt.Fprintf(w, `"%s"`, v)
+			if v.IsNull() {
+				io.WriteString(w, "<null>")
+			} else {
+				fmt.Fprintf(w, `"%s"`, v)
+			}
 		}
 
 	case 's':
@@ -487,7 +521,8 @@ func (v Value) Format(w fmt.State, r rune) {
 	case 'v':
 		switch {
 		case w.Flag('+'):
-			fmt.Fprintf(w, "%+[1]c %+[1]d %+[1]r %+[1]s", v)
+			fmt.Fprintf(w, "%+[1]c %+[1]d %+[1]r ", v)
+			v.formatKindAndValue(w, "K:"+v.Kind().String())
 		case w.Flag('#'):
 			fmt.Fprintf(w, "parquet.Value{%+[1]c, %+[1]d, %+[1]r, %+[1]s}", v)
 		default:
@@ -496,6 +531,22 @@ func (v Value) Format(w fmt.State, r rune) {
 	}
 }
 
+// formatKindAndValue writes the kind of v followed by its value to w, byte
+// arrays are quoted to show their boundaries and non-printable bytes. The kind
+// is omitted for null values.
+func (v Value) formatKindAndValue(w io.Writer, kind string) {
+	if !v.IsNull() {
+		io.WriteString(w, kind)
+		io.WriteString(w, " ")
+	}
+	switch v.Kind() {
+	case ByteArray, FixedLenByteArray:
+		fmt.Fprintf(w, "%+q", v)
+	default:
+		fmt.Fprintf(w, "%+s", v)
+	}
+}
+
 // String returns a string representation of v.
 func (v Value) String() string {
 	switch v.Kind() {
@@ -510,7 +561,7 @@ func (v Value) String() string {
 	case Float:
 		return strconv.FormatFloat(float64(v.Float()), 'g', -1, 32)
 	case Double:
-		return strconv.FormatFloat(v.Double(), 'g', -1, 32)
+		return strconv.FormatFloat(v.Double(), 'g', -1, 64)
 	case ByteArray, FixedLenByteArray:
 		// As an optimizations for the common case of using String on UTF8
 		// columns we convert the byte array to a string without copying the
@@ -549,6 +600,121 @@ func (v Value) Clone() Value {
 	return v
 }
 
+// Convert returns v converted to a value of the given kind, retaining its
+// repetition level, definition level, and column index.
+//
+// The conversions between integer and floating point kinds fail with an error
+// wrapping ErrValueOutOfRange if the value cannot be represented by the target
+// kind: integers must fit in the target size, integers converted to floating
+// point numbers must be represented exactly, and floating point numbers
+// converted to integers must not have a fractional part. DOUBLE values may
+// lose precision when converted to FLOAT, but must fit in its range.
+//
+// BOOLEAN values convert to integers as 0 and 1, and the reverse conversion
+// only accepts these two integers. BYTE_ARRAY and FIXED_LEN_BYTE_ARRAY values
+// convert to each other and share their underlying byte array. Null values are
+// returned unchanged.
+func (v Value) Convert(kind Kind) (Value, error) {
+	if v.IsNull() || v.Kind() == kind {
+		return v, nil
+	}
+	r, err := convertValueKind(v, kind)
+	if err != nil {
+		return v, fmt.Errorf("cannot convert parquet value %v of kind %s to %s: %w", v, v.Kind(), kind, err)
+	}
+	r.repetitionLevel = v.repetitionLevel
+	r.definitionLevel = v.definitionLevel
+	r.columnIndex = v.columnIndex
+	return r, nil
+}
+
+// maxInt64AsFloat is 2^63, the value that math.MaxInt64 rounds to when it is
+// converted to a floating point number, and the first one which cannot be
+// represented by int64.
+const maxInt64AsFloat = float64(1 << 63)
+
+// floatIsInt64 returns true if the floating point number f represents the
+// integer n exactly.
+func floatIsInt64(f float64, n int64) bool {
+	return f < maxInt64AsFloat && int64(f) == n
+}
+
+func convertValueKind(v Value, kind Kind) (Value, error) {
+	switch from := v.Kind(); from {
+	case Boolean:
+		switch kind {
+		case Int32:
+			return makeValueInt32(int32(v.u64)), nil
+		case Int64:
+			return makeValueInt64(int64(v.u64)), nil
+		}
+
+	case Int32, Int64:
+		n := v.Int64()
+		if from == Int32 {
+			n = int64(v.Int32())
+		}
+		switch kind {
+		case Boolean:
+			if n == 0 || n == 1 {
+				return makeValueBoolean(n == 1), nil
+			}
+			return v, ErrValueOutOfRange
+		case Int32:
+			if n < math.MinInt32 || n > math.MaxInt32 {
+				return v, ErrValueOutOfRange
+			}
+			return makeValueInt32(int32(n)), nil
+		case Int64:
+			return makeValueInt64(n), nil
+		case Float:
+			if f := float32(n); !floatIsInt64(float64(f), n) {
+				return v, ErrValueOutOfRange
+			} else {
+				return makeValueFloat(f), nil
+			}
+		case Double:
+			if f := float64(n); !floatIsInt64(f, n) {
+				return v, ErrValueOutOfRange
+			} else {
+				return makeValueDouble(f), nil
+			}
+		}
+
+	case Float, Double:
+		f := v.Double()
+		if from == Float {
+			f = float64(v.Float())
+		}
+		switch kind {
+		case Int32:
+			if f != math.Trunc(f) || f < math.MinInt32 || f > math.MaxInt32 {
+				return v, ErrValueOutOfRange
+			}
+			return makeValueInt32(int32(f)), nil
+		case Int64:
+			if f != math.Trunc(f) || f < math.MinInt64 || f >= maxInt64AsFloat {
+				return v, ErrValueOutOfRange
+			}
+			return makeValueInt64(int64(f)), nil
+		case Float:
+			if math.Abs(f) > math.MaxFloat32 && !math.IsInf(f, 0) {
+				return v, ErrValueOutOfRange
+			}
+			return makeValueFloat(float32(f)), nil
+		case Double:
+			return makeValueDouble(f), nil
+		}
+
+	case ByteArray, FixedLenByteArray:
+		switch kind {
+		case ByteArray, FixedLenByteArray:
+			return makeValueByteArray(kind, v.ptr, int(v.u64)), nil
+		}
+	}
+	return v, fmt.Errorf("unsupported conversion")
+}
+
 func makeInt96(bits []byte) (i96 deprecated.Int96) {
 	return deprecated.Int96{
 		2: binary.LittleEndian.Uint32(bits[8:12]),
@@ -581,10 +747,24 @@ func assignValue(dst reflect.Value, src Value) error {
 	case Int32:
 		v := int64(src.Int32())
 		switch dstKind {
-		case reflect.Int8, reflect.Int16, reflect.Int32:
-			dst.SetInt(int64(v))
+		case reflect.Int8, reflect.Int16:
+			if dst.OverflowInt(v) {
+				return errIntegerOutOfRange(srcKind, v, dst.Type())
+			}
+			dst.SetInt(v)
 			return nil
-		case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		case reflect.Int32:
+			dst.SetInt(v)
+			return nil
+		case reflect.Uint8, reflect.Uint16:
+			if v < 0 || dst.OverflowUint(uint64(v)) {
+				return errIntegerOutOfRange(srcKind, v, dst.Type())
+			}
+			dst.SetUint(uint64(v))
+			return nil
+		case reflect.Uint32:
+			// Unsigned 32 bits values greater than math.MaxInt32 are stored
+			// as negative INT32 values.
 			dst.SetUint(uint64(v))
 			return nil
 		default:
@@ -595,9 +775,18 @@ func assignValue(dst reflect.Value, src Value) error {
 		v := src.Int64()
 		switch dstKind {
 		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
+			if dst.OverflowInt(v) {
+				return errIntegerOutOfRange(srcKind, v, dst.Type())
+			}
 			dst.SetInt(v)
 			return nil
-		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint, reflect.Uintptr:
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+			if dst.OverflowUint(uint64(v)) {
+				return errIntegerOutOfRange(srcKind, v, dst.Type())
+			}
+			dst.SetUint(uint64(v))
+			return nil
+		case reflect.Uint64, reflect.Uint, reflect.Uintptr:
 			dst.SetUint(uint64(v))
 			return nil
 		default:
@@ -631,7 +820,11 @@ func assignValue(dst reflect.Value, src Value) error {
 		v := src.ByteArray()
 		switch dstKind {
 		case reflect.String:
-			dst.SetString(string(v))
+			if src.interned {
+				dst.SetString(unsafeBytesToString(v))
+			} else {
+				dst.SetString(string(v))
+			}
 			return nil
 		case reflect.Slice:
 			if dst.Type().Elem().Kind() == reflect.Uint8 {
@@ -674,6 +867,10 @@ func assignValue(dst reflect.Value, src Value) error {
 	return fmt.Errorf("cannot assign parquet value of type %s to go value of type %s", srcKind.String(), dst.Type())
 }
 
+func errIntegerOutOfRange(kind Kind, value int64, dstType reflect.Type) error {
+	return fmt.Errorf("cannot assign parquet value %d of type %s to go value of type %s: integer out of range", value, kind, dstType)
+}
+
 func parseValue(kind Kind, data []byte) (val Value, err error) {
 	switch kind {
 	case Boolean:
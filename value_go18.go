@@ -22,6 +22,11 @@ type RequiredReader[T plain.Type] interface {
 
 // RequiredWriter is a parameterized interface implemented by ValueWriter
 // instances which allows writing arrays of Go values of the type parameter T.
+//
+// The column buffers of required columns implement this interface, including
+// dictionary encoded columns, which allows programs producing data by columns
+// to write to a Buffer without constructing rows. The values written to the
+// columns of a buffer must represent the same number of rows.
 type RequiredWriter[T plain.Type] interface {
 	// Write values from the data slice, returning the number of values written,
 	// or an error if less than len(data) values were written.
@@ -1,6 +1,7 @@
 package parquet_test
 
 import (
+	"errors"
 	"math"
 	"testing"
 	"unsafe"
@@ -9,7 +10,12 @@ import (
 )
 
 func TestSizeOfValue(t *testing.T) {
-	t.Logf("sizeof(parquet.Value) = %d", unsafe.Sizeof(parquet.Value{}))
+	size := unsafe.Sizeof(parquet.Value{})
+	t.Logf("sizeof(parquet.Value) = %d", size)
+
+	if unsafe.Sizeof(uintptr(0)) == 8 && size != 24 {
+		t.Errorf("wrong size of parquet.Value: want=24 got=%d", size)
+	}
 }
 
 func BenchmarkValueAppend(b *testing.B) {
@@ -90,3 +96,56 @@ func TestValueClone(t *testing.T) {
 		})
 	}
 }
+
+func TestValueConvert(t *testing.T) {
+	tests := []struct {
+		scenario string
+		value    parquet.Value
+		kind     parquet.Kind
+		want     parquet.Value
+		err      bool
+	}{
+		{scenario: "INT32 to INT64", value: parquet.ValueOf(int32(-1)), kind: parquet.Int64, want: parquet.ValueOf(int64(-1))},
+		{scenario: "INT64 to INT32", value: parquet.ValueOf(int64(math.MaxInt32)), kind: parquet.Int32, want: parquet.ValueOf(int32(math.MaxInt32))},
+		{scenario: "INT64 to INT32 overflow", value: parquet.ValueOf(int64(math.MaxInt32 + 1)), kind: parquet.Int32, err: true},
+		{scenario: "INT32 to FLOAT", value: parquet.ValueOf(int32(1 << 24)), kind: parquet.Float, want: parquet.ValueOf(float32(1 << 24))},
+		{scenario: "INT32 to FLOAT inexact", value: parquet.ValueOf(int32(1<<24 + 1)), kind: parquet.Float, err: true},
+		{scenario: "INT64 to DOUBLE overflow", value: parquet.ValueOf(int64(math.MaxInt64)), kind: parquet.Double, err: true},
+		{scenario: "FLOAT to DOUBLE", value: parquet.ValueOf(float32(0.5)), kind: parquet.Double, want: parquet.ValueOf(float64(0.5))},
+		{scenario: "DOUBLE to FLOAT", value: parquet.ValueOf(float64(0.1)), kind: parquet.Float, want: parquet.ValueOf(float32(0.1))},
+		{scenario: "DOUBLE to FLOAT overflow", value: parquet.ValueOf(float64(math.MaxFloat64)), kind: parquet.Float, err: true},
+		{scenario: "DOUBLE to INT64", value: parquet.ValueOf(float64(-42)), kind: parquet.Int64, want: parquet.ValueOf(int64(-42))},
+		{scenario: "DOUBLE to INT64 fraction", value: parquet.ValueOf(float64(1.5)), kind: parquet.Int64, err: true},
+		{scenario: "DOUBLE to INT64 overflow", value: parquet.ValueOf(float64(1 << 63)), kind: parquet.Int64, err: true},
+		{scenario: "BOOLEAN to INT32", value: parquet.ValueOf(true), kind: parquet.Int32, want: parquet.ValueOf(int32(1))},
+		{scenario: "INT32 to BOOLEAN overflow", value: parquet.ValueOf(int32(2)), kind: parquet.Boolean, err: true},
+		{scenario: "BYTE_ARRAY to FIXED_LEN_BYTE_ARRAY", value: parquet.ValueOf("abc"), kind: parquet.FixedLenByteArray, want: parquet.ValueOf([3]byte{'a', 'b', 'c'})},
+		{scenario: "BYTE_ARRAY to INT32", value: parquet.ValueOf("abc"), kind: parquet.Int32, err: true},
+		{scenario: "null", value: parquet.ValueOf(nil), kind: parquet.Int64, want: parquet.ValueOf(nil)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			v, err := test.value.Level(1, 2, 3).Convert(test.kind)
+			if test.err {
+				if err == nil {
+					t.Fatalf("converting %v to %s did not fail", test.value, test.kind)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !parquet.Equal(v, test.want) {
+				t.Errorf("wrong converted value: want=%v got=%v", test.want, v)
+			}
+			if v.RepetitionLevel() != 1 || v.DefinitionLevel() != 2 || v.Column() != 3 {
+				t.Errorf("levels were not retained: %d/%d/%d", v.RepetitionLevel(), v.DefinitionLevel(), v.Column())
+			}
+		})
+	}
+
+	if _, err := parquet.ValueOf(int64(1 << 40)).Convert(parquet.Int32); !errors.Is(err, parquet.ErrValueOutOfRange) {
+		t.Errorf("converting an overflowing value did not report an out of range value: %v", err)
+	}
+}
@@ -2,11 +2,15 @@ package parquet
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"fmt"
 	"hash/crc32"
 	"io"
+	"reflect"
 	"sort"
+	"strconv"
+	"time"
 
 	"github.com/segmentio/encoding/thrift"
 	"github.com/segmentio/parquet-go/compress"
@@ -45,6 +49,12 @@ type Writer struct {
 	schema *Schema
 	writer *writer
 	values []Value
+	// The writer that rows are passed to, which differs from the underlying
+	// writer when derived columns or column transformations are configured.
+	rows RowWriter
+	// The Go type that the schema was inferred from, when no schema was given
+	// to NewWriter.
+	rowType reflect.Type
 }
 
 // NewWriter constructs a parquet writer writing a file to the given io.Writer.
@@ -74,24 +84,88 @@ func NewWriter(output io.Writer, options ...WriterOption) *Writer {
 		config: config,
 	}
 	if config.Schema != nil {
-		w.configure(config.Schema)
+		if err := w.configure(config.Schema); err != nil {
+			panic(err)
+		}
 	}
 	return w
 }
 
-func (w *Writer) configure(schema *Schema) {
-	if schema != nil {
-		w.config.Schema = schema
-		w.schema = schema
-		w.writer = newWriter(w.output, w.config)
+func (w *Writer) configure(schema *Schema) error {
+	if schema == nil {
+		return nil
+	}
+	// When column overrides or derived columns are configured, the schema of
+	// the file differs from the schema of rows written to w.
+	overriddenSchema := schema
+	overrideLevels := [][]int16(nil)
+	if len(w.config.ColumnOverrides) > 0 {
+		overriddenSchema = overrideSchema(schema, w.config.ColumnOverrides)
+		var err error
+		if overrideLevels, err = definitionLevelsOfOverrides(schema, overriddenSchema, nil, 0, nil, nil); err != nil {
+			return err
+		}
+	}
+	fileSchema := overriddenSchema
+	if len(w.config.DerivedColumns) > 0 {
+		var err error
+		if fileSchema, err = deriveSchema(overriddenSchema, w.config.DerivedColumns); err != nil {
+			return err
+		}
+	}
+	// The legacy layouts of nested columns only rename columns of the file,
+	// rows written to w are passed as-is to the underlying writer.
+	w.config.Schema = fileSchema
+	if w.config.LegacyNestedLayouts {
+		var err error
+		if w.config.Schema, err = legacySchemaOf(fileSchema); err != nil {
+			return err
+		}
 	}
+	w.schema = schema
+	w.writer = newWriter(w.output, w.config)
+	w.rows = w.writer
+	if len(w.config.ColumnTransforms) > 0 {
+		w.rows = newColumnTransformWriter(w.rows, fileSchema, w.config.ColumnTransforms)
+	}
+	if fileSchema != overriddenSchema {
+		derived, err := newDerivedColumnWriter(w.rows, overriddenSchema, fileSchema, w.config.DerivedColumns)
+		if err != nil {
+			return err
+		}
+		w.rows = derived
+	}
+	if overriddenSchema != schema {
+		w.rows = newColumnOverrideWriter(w.rows, overrideLevels)
+	}
+	return nil
+}
+
+// writesRowsDirectly returns true if rows written to w can be passed to the
+// underlying writer without being rewritten.
+func (w *Writer) writesRowsDirectly() bool { return w.rows == RowWriter(w.writer) }
+
+// configureFrom configures w with a schema that was not validated by the
+// writer configuration, e.g. when it is derived from the rows being written.
+func (w *Writer) configureFrom(schema *Schema) error {
+	if err := validateSchema(schema, w.config.SchemaValidation); err != nil {
+		return err
+	}
+	return w.configure(schema)
 }
 
 // Close must be called after all values were produced to the writer in order to
 // flush all buffers and write the parquet footer.
 func (w *Writer) Close() error {
+	return w.CloseContext(context.Background())
+}
+
+// CloseContext is like Close but aborts writing the last row group when the
+// context is cancelled, in which case the context error is returned and the
+// parquet footer is not written.
+func (w *Writer) CloseContext(ctx context.Context) error {
 	if w.writer != nil {
-		return w.writer.close()
+		return w.writer.close(ctx)
 	}
 	return nil
 }
@@ -102,12 +176,56 @@ func (w *Writer) Close() error {
 // if the application needs to limit the size of row groups or wants to produce
 // multiple row groups per file.
 func (w *Writer) Flush() error {
+	return w.FlushContext(context.Background())
+}
+
+// FlushContext is like Flush but checks the context between column chunks of
+// the row group, returning the context error if it was cancelled.
+//
+// When the flush is aborted the buffered rows are discarded and the output may
+// contain a partially written row group; the writer must be reset before it
+// can be reused.
+func (w *Writer) FlushContext(ctx context.Context) error {
 	if w.writer != nil {
-		return w.writer.flush()
+		return w.writer.flush(ctx)
 	}
 	return nil
 }
 
+// FlushRowGroup is like Flush but returns the metadata of the row group that
+// was written to the output, which allows programs to build external indexes
+// or table format manifests as they write files.
+//
+// The method returns nil if there were no buffered rows to flush.
+func (w *Writer) FlushRowGroup() (*FlushedRowGroup, error) {
+	return w.FlushRowGroupContext(context.Background())
+}
+
+// FlushRowGroupContext is like FlushRowGroup but checks the context between
+// column chunks of the row group, with the same semantics as FlushContext.
+func (w *Writer) FlushRowGroupContext(ctx context.Context) (*FlushedRowGroup, error) {
+	if w.writer == nil {
+		return nil, nil
+	}
+	numRows, err := w.writer.writeRowGroup(ctx, nil, nil)
+	if numRows == 0 {
+		return nil, err
+	}
+	return w.writer.flushedRowGroup(), err
+}
+
+// FlushedRowGroup describes a row group flushed by a parquet writer.
+type FlushedRowGroup struct {
+	// Index of the row group in the file.
+	Index int
+	// Metadata of the row group as written in the footer of the file, which
+	// carries the offset and size of the row group and of its column chunks,
+	// and the number of rows.
+	RowGroup format.RowGroup
+	// Statistics of the leaf columns of the row group, indexed by column.
+	Columns []ColumnStats
+}
+
 // Reset clears the state of the writer without flushing any of the buffers,
 // and setting the output to the io.Writer passed as argument, allowing the
 // writer to be reused to produce another parquet file.
@@ -124,18 +242,86 @@ func (w *Writer) Reset(output io.Writer) {
 // The method uses the parquet schema configured on w to traverse the Go value
 // and decompose it into a set of columns and values. If no schema were passed
 // to NewWriter, it is deducted from the Go type of the row, which then have to
-// be a struct or pointer to struct.
+// be a struct or pointer to struct; the method then returns an error if later
+// rows have types that do not produce the same schema.
 func (w *Writer) Write(row interface{}) error {
-	if w.schema == nil {
-		w.configure(SchemaOf(row))
+	schema := w.schema
+	if schema == nil || w.rowType != nil {
+		var err error
+		if schema, err = w.schemaOf(dereference(reflect.TypeOf(row))); err != nil {
+			return err
+		}
 	}
 	defer func() {
 		clearValues(w.values)
 	}()
-	w.values = w.schema.Deconstruct(w.values[:0], row)
+	w.values = schema.Deconstruct(w.values[:0], row)
 	return w.WriteRow(w.values)
 }
 
+// schemaOf returns the schema used to deconstruct Go values of type t into rows
+// written to w. If no schema was configured on w, it is inferred from t; later
+// values must then have types with the same schema.
+func (w *Writer) schemaOf(t reflect.Type) (*Schema, error) {
+	if w.schema == nil {
+		if err := w.configureFrom(schemaOf(t)); err != nil {
+			return nil, err
+		}
+		w.rowType = t
+	}
+	if w.rowType == nil || w.rowType == t {
+		return w.schema, nil
+	}
+	// The type differs from the one that the schema was inferred from, but it
+	// may still produce the same rows (e.g. a struct with the same fields).
+	if schema := schemaOf(t); nodesAreEqual(schema, w.schema) {
+		return schema, nil
+	}
+	return nil, fmt.Errorf("cannot write go value of type %s to parquet writer with schema inferred from type %s", t, w.rowType)
+}
+
+// WriteRows writes the rows held in a slice of Go values (or pointer to a
+// slice), typically structs or pointers to structs, returning the number of
+// rows written.
+//
+// The method is a convenience for calling Write on each element of the slice;
+// the schema used to deconstruct the elements is resolved once for the whole
+// slice instead of once per row. When a schema was given to NewWriter, the
+// elements must be structs (or pointers to structs) with the same schema.
+func (w *Writer) WriteRows(rows interface{}) (int, error) {
+	v := reflect.ValueOf(rows)
+	if v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Slice {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice {
+		return 0, fmt.Errorf("cannot write parquet rows from go value of type %T: not a slice", rows)
+	}
+	if v.Len() == 0 {
+		return 0, nil
+	}
+	t := dereference(v.Type().Elem())
+	if w.schema != nil && w.rowType == nil {
+		if t.Kind() != reflect.Struct || !nodesAreEqual(schemaOf(t), w.schema) {
+			return 0, fmt.Errorf("cannot write go values of type %s to parquet writer with schema %s", t, w.schema.Name())
+		}
+	}
+	schema, err := w.schemaOf(t)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		clearValues(w.values)
+	}()
+
+	for i, n := 0, v.Len(); i < n; i++ {
+		w.values = schema.deconstructValue(w.values[:0], v.Index(i))
+		if err := w.WriteRow(w.values); err != nil {
+			return i, err
+		}
+	}
+	return v.Len(), nil
+}
+
 // WriteRow is called to write another row to the parquet file.
 //
 // The Writer must have been given a schema when NewWriter was called, otherwise
@@ -143,7 +329,12 @@ func (w *Writer) Write(row interface{}) error {
 //
 // The row is expected to contain values for each column of the writer's schema,
 // in the order produced by the parquet.(*Schema).Deconstruct method.
-func (w *Writer) WriteRow(row Row) error { return w.writer.WriteRow(row) }
+func (w *Writer) WriteRow(row Row) error {
+	if err := w.rows.WriteRow(row); err != nil {
+		return err
+	}
+	return w.writer.checkRowGroupLimits()
+}
 
 // WriteRowGroup writes a row group to the parquet file.
 //
@@ -152,25 +343,59 @@ func (w *Writer) WriteRow(row Row) error { return w.writer.WriteRow(row) }
 //
 // The content of the row group is flushed to the writer; after the method
 // returns successfully, the row group will be empty and in ready to be reused.
+// Row groups with more rows than configured by MaxRowsPerRowGroup are split
+// into multiple row groups.
+//
+// This is the most efficient way to write data that is already organized in
+// columns (e.g. in a parquet.Buffer, or in another parquet file) since whole
+// pages are written instead of individual rows. Pages read from parquet files
+// are copied without being decoded when their encoding and compression codec
+// match those of the writer's columns.
 func (w *Writer) WriteRowGroup(rowGroup RowGroup) (int64, error) {
 	rowGroupSchema := rowGroup.Schema()
 	switch {
 	case rowGroupSchema == nil:
 		return 0, ErrRowGroupSchemaMissing
 	case w.schema == nil:
-		w.configure(rowGroupSchema)
+		if err := w.configureFrom(rowGroupSchema); err != nil {
+			return 0, err
+		}
 	case !nodesAreEqual(w.schema, rowGroupSchema):
 		return 0, ErrRowGroupSchemaMismatch
 	}
-	if err := w.writer.flush(); err != nil {
+	if err := w.writer.flush(context.Background()); err != nil {
 		return 0, err
 	}
 	w.writer.configureBloomFilters(rowGroup)
-	n, err := CopyRows(w.writer, rowGroup.Rows())
+	// The file schema is used to locate the sorting columns of the row group,
+	// since column indexes are shifted when derived columns are configured.
+	sortingColumns := rowGroup.SortingColumns()
+
+	if maxRows := w.writer.rowGroupMaxRows; maxRows > 0 && rowGroup.NumRows() > maxRows {
+		// The row group is split into row groups of the maximum size, each
+		// one is flushed before reading the rows of the next.
+		rows, written := rowGroup.Rows(), int64(0)
+		for written < rowGroup.NumRows() {
+			n, err := CopyRows(w.rows, &limitRowReader{rows: rows, limit: maxRows})
+			written += n
+			if err != nil {
+				return written, err
+			}
+			if n == 0 {
+				break
+			}
+			if _, err := w.writer.writeRowGroup(context.Background(), w.config.Schema, sortingColumns); err != nil {
+				return written, err
+			}
+		}
+		return written, nil
+	}
+
+	n, err := CopyRows(w.rows, rowGroup.Rows())
 	if err != nil {
 		return n, err
 	}
-	return w.writer.writeRowGroup(rowGroup.Schema(), rowGroup.SortingColumns())
+	return w.writer.writeRowGroup(context.Background(), w.config.Schema, sortingColumns)
 }
 
 // ReadRowsFrom reads rows from the reader passed as arguments and writes them
@@ -181,10 +406,18 @@ func (w *Writer) WriteRowGroup(rowGroup RowGroup) (int64, error) {
 func (w *Writer) ReadRowsFrom(rows RowReader) (written int64, err error) {
 	if w.schema == nil {
 		if r, ok := rows.(RowReaderWithSchema); ok {
-			w.configure(r.Schema())
+			if err := w.configureFrom(r.Schema()); err != nil {
+				return 0, err
+			}
 		}
 	}
-	written, w.values, err = copyRows(w.writer, rows, w.values[:0])
+	if w.writer.hasRowGroupLimits() || !w.writesRowsDirectly() {
+		// Rows are written one at a time so the row groups can be flushed when
+		// they reach their limits, or when they need to be rewritten.
+		written, w.values, err = copyRows(struct{ RowWriter }{w}, rows, w.values[:0])
+	} else {
+		written, w.values, err = copyRows(w.writer, rows, w.values[:0])
+	}
 	return written, err
 }
 
@@ -196,14 +429,21 @@ func (w *Writer) Schema() *Schema { return w.schema }
 type writer struct {
 	writer offsetTrackingWriter
 
-	createdBy string
-	metadata  []format.KeyValue
+	createdBy  string
+	metadata   []format.KeyValue
+	encryption *fileEncryptor
 
 	buffers struct {
 		header bytes.Buffer
 		page   bytes.Buffer
+		footer []byte
 	}
 
+	// The sizes of the page and footer buffers retained by the writer, zero
+	// means that they are not bounded.
+	pageBufferSize   int
+	footerBufferSize int
+
 	columns       []*writerColumn
 	columnChunk   []format.ColumnChunk
 	columnIndex   []format.ColumnIndex
@@ -216,6 +456,20 @@ type writer struct {
 	columnIndexes  [][]format.ColumnIndex
 	offsetIndexes  [][]format.OffsetIndex
 	sortingColumns []format.SortingColumn
+
+	// The size at which row groups are flushed, and the number of pages
+	// written when the size was last computed, since it only changes
+	// significantly when pages are written.
+	rowGroupTargetSize int64
+	rowGroupNumPages   int
+	rowGroupMaxRows    int64
+	// The time after which row groups are flushed, and the time at which the
+	// first row of the current row group was written (zero when empty).
+	rowGroupFlushInterval time.Duration
+	rowGroupStartTime     time.Time
+
+	observer        *WriterObserver
+	onRowGroupFlush []func(*FlushedRowGroup) error
 }
 
 func newWriter(output io.Writer, config *WriterConfig) *writer {
@@ -228,6 +482,30 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 	}
 	sortKeyValueMetadata(w.metadata)
 	w.sortingColumns = make([]format.SortingColumn, len(config.SortingColumns))
+	w.rowGroupTargetSize = config.RowGroupTargetSize
+	w.rowGroupMaxRows = config.MaxRowsPerRowGroup
+	w.rowGroupFlushInterval = config.RowGroupFlushInterval
+	w.observer = config.Observer
+	w.onRowGroupFlush = config.OnRowGroupFlush
+	w.pageBufferSize = config.PageEncodingBufferSize
+	w.footerBufferSize = config.FooterBufferSize
+	w.buffers.page.Grow(w.pageBufferSize)
+	w.buffers.footer = make([]byte, 0, w.footerBufferSize)
+
+	pool := config.ColumnPageBuffers
+	if config.ColumnChunkBufferSize > 0 {
+		pool = &sizedPageBufferPool{pool: pool, size: config.ColumnChunkBufferSize}
+	}
+
+	if config.Encryption != nil {
+		encryption, err := newFileEncryptor(config.Encryption)
+		if err != nil {
+			// The keys are validated with the writer configuration so this
+			// error is not expected to ever happen.
+			panic(err)
+		}
+		w.encryption = encryption
+	}
 
 	config.Schema.forEachNode(func(name string, node Node) {
 		nodeType := node.Type()
@@ -258,6 +536,7 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 			RepetitionType: repetitionType,
 			Name:           name,
 			NumChildren:    int32(node.NumChildren()),
+			FieldID:        int32(fieldIDOf(node)),
 			ConvertedType:  nodeType.ConvertedType(),
 			Scale:          scale,
 			Precision:      precision,
@@ -272,6 +551,19 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 
 	forEachLeafColumnOf(config.Schema, func(leaf leafColumn) {
 		encoding, compression := encodingAndCompressionOf(leaf.node)
+		if codec := searchColumnCodec(config.ColumnCompression, leaf.path); codec != nil {
+			compression = codec
+		} else if config.Compression != nil && len(leaf.node.Compression()) == 0 {
+			compression = config.Compression
+		}
+		encoding, err := selectColumnEncoding(config.ColumnEncoding, leaf.path, leaf.node, encoding)
+		if err != nil {
+			panic(err)
+		}
+		zstdDictionaryID, err := zstdDictionaryIDOf(compression)
+		if err != nil {
+			panic(err)
+		}
 		dictionary := Dictionary(nil)
 		columnType := leaf.node.Type()
 		columnIndex := int(leaf.columnIndex)
@@ -282,20 +574,24 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 		}
 
 		c := &writerColumn{
-			pool:               config.ColumnPageBuffers,
+			pool:               pool,
 			columnPath:         leaf.path,
 			columnType:         columnType,
 			columnIndex:        columnType.NewColumnIndexer(config.ColumnIndexSizeLimit),
 			columnFilter:       searchBloomFilterColumn(config.BloomFilters, leaf.path),
 			compression:        compression,
+			zstdDictionaryID:   zstdDictionaryID,
 			dictionary:         dictionary,
 			dataPageType:       dataPageType,
 			maxRepetitionLevel: leaf.maxRepetitionLevel,
 			maxDefinitionLevel: leaf.maxDefinitionLevel,
 			bufferIndex:        int32(leaf.columnIndex),
 			bufferSize:         int32(config.PageBufferSize),
+			dataPageSize:       int64(config.DataPageSize),
+			pageMaxValues:      int32(config.DataPageMaxValues),
 			writePageStats:     config.DataPageStatistics,
 			encodings:          make([]format.Encoding, 0, 3),
+			observer:           config.Observer,
 			// Data pages in version 2 can omit compression when dictionary
 			// encoding is employed; only the dictionary page needs to be
 			// compressed, the data pages are encoded with the hybrid
@@ -334,6 +630,32 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 		c.encodings = addEncoding(c.encodings, c.page.encoding)
 		sortPageEncodings(c.encodings)
 
+		if dictionary != nil {
+			maxSize, maxValues := searchDictionaryLimit(config.DictionaryLimits, leaf.path, config.DictionaryMaxSize, config.DictionaryMaxValues)
+			if maxSize > 0 || maxValues > 0 {
+				// The encoding used when the dictionary exceeds its limits is
+				// selected as if dictionary encodings were disabled.
+				fallbackEncoding, _ := selectColumnEncoding(append(config.ColumnEncoding[:len(config.ColumnEncoding):len(config.ColumnEncoding)],
+					ColumnEncodingConfig{Path: leaf.path, Encoding: &PlainDictionary, Disabled: true},
+					ColumnEncodingConfig{Path: leaf.path, Encoding: &RLEDictionary, Disabled: true},
+				), leaf.path, leaf.node, encoding)
+				c.fallback.maxSize = int64(maxSize)
+				c.fallback.maxValues = maxValues
+				c.fallback.columnType = leaf.node.Type()
+				c.fallback.encoding = fallbackEncoding
+				c.fallback.dictionary.columnType = c.columnType
+				c.fallback.dictionary.encoding = encoding
+				c.fallback.dictionary.encodings = c.encodings
+			}
+		}
+
+		if w.encryption != nil {
+			var encrypted bool
+			if c.cryptoMetadata, encrypted = w.encryption.addColumn(leaf.path); encrypted {
+				c.encryption = w.encryption
+			}
+		}
+
 		w.columns = append(w.columns, c)
 
 		if sortingIndex := searchSortingColumn(config.SortingColumns, leaf.path); sortingIndex < len(w.sortingColumns) {
@@ -350,6 +672,12 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 	w.offsetIndex = make([]format.OffsetIndex, len(w.columns))
 	w.columnOrders = make([]format.ColumnOrder, len(w.columns))
 
+	if w.encryption != nil {
+		if err := w.encryption.checkColumnKeys(); err != nil {
+			panic(err)
+		}
+	}
+
 	for i, c := range w.columns {
 		w.columnChunk[i] = format.ColumnChunk{
 			MetaData: format.ColumnMetaData{
@@ -357,9 +685,10 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 				Encoding:         c.encodings,
 				PathInSchema:     c.columnPath,
 				Codec:            c.compression.CompressionCodec(),
-				KeyValueMetadata: nil, // TODO
+				KeyValueMetadata: c.keyValueMetadata(),
 			},
 		}
+		w.columnChunk[i].CryptoMetadata = c.cryptoMetadata
 	}
 
 	for i, c := range w.columns {
@@ -376,8 +705,13 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 
 func (w *writer) reset(writer io.Writer) {
 	w.writer.Reset(writer)
+	if w.encryption != nil {
+		w.encryption.reset()
+	}
 	for _, c := range w.columns {
 		c.reset()
+		c.fileStats = columnFileStats{}
+		c.rowGroupStats = columnFileStats{}
 	}
 	for i := range w.rowGroups {
 		w.rowGroups[i] = format.RowGroup{}
@@ -391,21 +725,33 @@ func (w *writer) reset(writer io.Writer) {
 	w.rowGroups = w.rowGroups[:0]
 	w.columnIndexes = w.columnIndexes[:0]
 	w.offsetIndexes = w.offsetIndexes[:0]
+	w.rowGroupNumPages = 0
+	w.rowGroupStartTime = time.Time{}
+	w.releaseBuffers()
+}
+
+// releaseBuffers drops the scratch buffers that grew larger than the sizes
+// configured on the writer, so the memory they hold can be reclaimed.
+func (w *writer) releaseBuffers() {
+	if w.pageBufferSize > 0 && w.buffers.page.Cap() > w.pageBufferSize {
+		w.buffers.page = bytes.Buffer{}
+		w.buffers.page.Grow(w.pageBufferSize)
+	}
 }
 
-func (w *writer) close() error {
+func (w *writer) close(ctx context.Context) error {
 	defer w.writer.Reset(nil)
 	if err := w.writeFileHeader(); err != nil {
 		return err
 	}
-	if err := w.flush(); err != nil {
+	if err := w.flush(ctx); err != nil {
 		return err
 	}
 	return w.writeFileFooter()
 }
 
-func (w *writer) flush() error {
-	_, err := w.writeRowGroup(nil, nil)
+func (w *writer) flush(ctx context.Context) error {
+	_, err := w.writeRowGroup(ctx, nil, nil)
 	return err
 }
 
@@ -414,12 +760,19 @@ func (w *writer) writeFileHeader() error {
 		return io.ErrClosedPipe
 	}
 	if w.writer.offset == 0 {
-		_, err := w.writer.WriteString("PAR1")
+		_, err := w.writer.WriteString(w.magic())
 		return err
 	}
 	return nil
 }
 
+func (w *writer) magic() string {
+	if w.encryption != nil {
+		return encryptionMagic
+	}
+	return "PAR1"
+}
+
 func (w *writer) configureBloomFilters(rowGroup RowGroup) {
 	for i, c := range w.columns {
 		if c.columnFilter != nil {
@@ -441,15 +794,42 @@ func (w *writer) writeFileFooter() error {
 	// because the parquet format is backward compatible in this case. Older
 	// readers will simply ignore this section since they do not know how to
 	// decode its content, nor have loaded any metadata to reference it.
+	//
+	// When the file is encrypted, each index is encoded to a scratch buffer
+	// first, then written as an encrypted module.
 	protocol := new(thrift.CompactProtocol)
-	encoder := thrift.NewEncoder(protocol.NewWriter(&w.writer))
+	output := io.Writer(&w.writer)
+	if w.encryption != nil {
+		w.buffers.header.Reset()
+		output = &w.buffers.header
+	}
+	encoder := thrift.NewEncoder(protocol.NewWriter(output))
+
+	writeIndex := func(index interface{}, moduleType byte, rowGroup, column int) error {
+		if err := encoder.Encode(index); err != nil {
+			return err
+		}
+		if w.encryption == nil {
+			return nil
+		}
+		defer w.buffers.header.Reset()
+		b := w.buffers.header.Bytes()
+		if w.encryption.encrypted(column) {
+			var err error
+			if b, err = w.encryption.encryptBytes(b, moduleType, rowGroup, column, -1); err != nil {
+				return err
+			}
+		}
+		_, err := w.writer.Write(b)
+		return err
+	}
 
 	for i, columnIndexes := range w.columnIndexes {
 		rowGroup := &w.rowGroups[i]
 		for j := range columnIndexes {
 			column := &rowGroup.Columns[j]
 			column.ColumnIndexOffset = w.writer.offset
-			if err := encoder.Encode(&columnIndexes[j]); err != nil {
+			if err := writeIndex(&columnIndexes[j], columnIndexModule, i, j); err != nil {
 				return err
 			}
 			column.ColumnIndexLength = int32(w.writer.offset - column.ColumnIndexOffset)
@@ -461,7 +841,7 @@ func (w *writer) writeFileFooter() error {
 		for j := range offsetIndexes {
 			column := &rowGroup.Columns[j]
 			column.OffsetIndexOffset = w.writer.offset
-			if err := encoder.Encode(&offsetIndexes[j]); err != nil {
+			if err := writeIndex(&offsetIndexes[j], offsetIndexModule, i, j); err != nil {
 				return err
 			}
 			column.OffsetIndexLength = int32(w.writer.offset - column.OffsetIndexOffset)
@@ -473,7 +853,21 @@ func (w *writer) writeFileFooter() error {
 		numRows += w.rowGroups[rowGroupIndex].NumRows
 	}
 
-	footer, err := thrift.Marshal(new(thrift.CompactProtocol), &format.FileMetaData{
+	if w.encryption != nil {
+		if err := w.encryption.wrapKeys(); err != nil {
+			return err
+		}
+		for i := range w.rowGroups {
+			columns := w.rowGroups[i].Columns
+			for j := range columns {
+				if err := w.encryption.encryptColumnMetaData(&columns[j], i, j); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	metadata := &format.FileMetaData{
 		Version:          1,
 		Schema:           w.schemaElements,
 		NumRows:          numRows,
@@ -481,25 +875,39 @@ func (w *writer) writeFileFooter() error {
 		KeyValueMetadata: w.metadata,
 		CreatedBy:        w.createdBy,
 		ColumnOrders:     w.columnOrders,
-	})
+	}
+
+	var footer []byte
+	var err error
+	if w.encryption != nil {
+		footer, err = w.encryption.encryptFooter(w.buffers.footer[:0], metadata)
+	} else {
+		buffer := bytes.NewBuffer(w.buffers.footer[:0])
+		err = thrift.NewEncoder(protocol.NewWriter(buffer)).Encode(metadata)
+		footer = buffer.Bytes()
+	}
 	if err != nil {
 		return err
 	}
 
 	length := len(footer)
 	footer = append(footer, 0, 0, 0, 0)
-	footer = append(footer, "PAR1"...)
+	footer = append(footer, w.magic()...)
 	binary.LittleEndian.PutUint32(footer[length:], uint32(length))
 
+	if cap(footer) <= w.footerBufferSize {
+		w.buffers.footer = footer[:0]
+	}
 	_, err = w.writer.Write(footer)
 	return err
 }
 
-func (w *writer) writeRowGroup(rowGroupSchema *Schema, rowGroupSortingColumns []SortingColumn) (int64, error) {
+func (w *writer) writeRowGroup(ctx context.Context, rowGroupSchema *Schema, rowGroupSortingColumns []SortingColumn) (int64, error) {
 	numRows := w.columns[0].totalRowCount()
 	if numRows == 0 {
 		return 0, nil
 	}
+	start := time.Now()
 
 	defer func() {
 		for _, c := range w.columns {
@@ -508,9 +916,15 @@ func (w *writer) writeRowGroup(rowGroupSchema *Schema, rowGroupSortingColumns []
 		for i := range w.columnIndex {
 			w.columnIndex[i] = format.ColumnIndex{}
 		}
+		w.rowGroupNumPages = 0
+		w.rowGroupStartTime = time.Time{}
+		w.releaseBuffers()
 	}()
 
 	for _, c := range w.columns {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		if err := c.flush(); err != nil {
 			return 0, err
 		}
@@ -534,6 +948,9 @@ func (w *writer) writeRowGroup(rowGroupSchema *Schema, rowGroupSortingColumns []
 	}
 
 	for i, c := range w.columns {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		w.columnIndex[i] = format.ColumnIndex(c.columnIndex.ColumnIndex())
 
 		if c.dictionary != nil {
@@ -554,6 +971,8 @@ func (w *writer) writeRowGroup(rowGroupSchema *Schema, rowGroupSortingColumns []
 				return 0, fmt.Errorf("writing buffered pages of row group column %d: %w", i, err)
 			}
 		}
+
+		c.columnChunk.MetaData.Statistics = c.makeChunkStatistics()
 	}
 
 	totalByteSize := int64(0)
@@ -601,9 +1020,61 @@ func (w *writer) writeRowGroup(rowGroupSchema *Schema, rowGroupSortingColumns []
 
 	w.columnIndexes = append(w.columnIndexes, columnIndex)
 	w.offsetIndexes = append(w.offsetIndexes, offsetIndex)
+
+	if w.encryption != nil {
+		w.encryption.rowGroup = len(w.rowGroups)
+	}
+
+	if w.observer != nil && w.observer.OnRowGroup != nil {
+		stats := WriterRowGroupStats{
+			RowGroup:         len(w.rowGroups) - 1,
+			NumRows:          numRows,
+			UncompressedSize: totalByteSize,
+			CompressedSize:   totalCompressedSize,
+			FlushDuration:    time.Since(start),
+			Columns:          make([]WriterColumnStats, len(w.columns)),
+		}
+		for i, c := range w.columns {
+			stats.Columns[i] = WriterColumnStats{
+				Path:             c.columnPath,
+				NumValues:        c.columnChunk.MetaData.NumValues,
+				NumPages:         len(c.offsetIndex.PageLocations),
+				UncompressedSize: c.columnChunk.MetaData.TotalUncompressedSize,
+				CompressedSize:   c.columnChunk.MetaData.TotalCompressedSize,
+			}
+		}
+		w.observer.OnRowGroup(stats)
+	}
+
+	if len(w.onRowGroupFlush) > 0 {
+		rowGroup := w.flushedRowGroup()
+		for _, fn := range w.onRowGroupFlush {
+			if err := fn(rowGroup); err != nil {
+				return numRows, err
+			}
+		}
+	}
 	return numRows, nil
 }
 
+// flushedRowGroup returns the metadata of the last row group written by w.
+func (w *writer) flushedRowGroup() *FlushedRowGroup {
+	rowGroup := &FlushedRowGroup{
+		Index:    len(w.rowGroups) - 1,
+		RowGroup: w.rowGroups[len(w.rowGroups)-1],
+		Columns:  make([]ColumnStats, len(w.columns)),
+	}
+	for i, c := range w.columns {
+		rowGroup.Columns[i] = ColumnStats{
+			NumValues: c.rowGroupStats.numValues,
+			NullCount: c.rowGroupStats.numNulls,
+			MinValue:  c.rowGroupStats.minValue,
+			MaxValue:  c.rowGroupStats.maxValue,
+		}
+	}
+	return rowGroup
+}
+
 func (w *writer) WriteRow(row Row) error {
 	for i := range row {
 		c := w.columns[row[i].Column()]
@@ -619,6 +1090,56 @@ func (w *writer) WriteRow(row Row) error {
 	return nil
 }
 
+func (w *writer) hasRowGroupLimits() bool {
+	return w.rowGroupTargetSize > 0 || w.rowGroupMaxRows > 0 || w.rowGroupFlushInterval > 0
+}
+
+// checkRowGroupLimits flushes the row group if it reached the maximum number
+// of rows, the target size, or the flush interval configured on the writer.
+func (w *writer) checkRowGroupLimits() error {
+	if w.rowGroupFlushInterval > 0 {
+		now := time.Now()
+		if w.rowGroupStartTime.IsZero() {
+			w.rowGroupStartTime = now
+		} else if now.Sub(w.rowGroupStartTime) >= w.rowGroupFlushInterval {
+			return w.flush(context.Background())
+		}
+	}
+	if w.rowGroupMaxRows > 0 && w.columns[0].totalRowCount() >= w.rowGroupMaxRows {
+		return w.flush(context.Background())
+	}
+	if w.rowGroupTargetSize == 0 {
+		return nil
+	}
+	numPages := 0
+	for _, c := range w.columns {
+		numPages += len(c.offsetIndex.PageLocations)
+	}
+	if numPages == w.rowGroupNumPages {
+		return nil
+	}
+	w.rowGroupNumPages = numPages
+	if w.rowGroupSize() < w.rowGroupTargetSize {
+		return nil
+	}
+	return w.flush(context.Background())
+}
+
+// rowGroupSize returns an estimate of the size of the row group being written.
+func (w *writer) rowGroupSize() int64 {
+	size := int64(0)
+	for _, c := range w.columns {
+		size += c.columnChunk.MetaData.TotalCompressedSize
+		if c.columnBuffer != nil {
+			size += c.columnBuffer.Size()
+		}
+		if c.dictionary != nil {
+			size += c.dictionary.Page().Size()
+		}
+	}
+	return size
+}
+
 // The WriteValues method is intended to work in pair with WritePage to allow
 // programs to target writing values to specific columns of of the writer.
 func (w *writer) WriteValues(values []Value) (numValues int, err error) {
@@ -649,10 +1170,12 @@ type writerColumn struct {
 	columnFilter BloomFilterColumn
 	compression  compress.Codec
 	dictionary   Dictionary
+	// The identifier of the dictionary of zstd compression codecs, if any.
+	zstdDictionaryID uint32
 
 	dataPageType       format.PageType
-	maxRepetitionLevel int8
-	maxDefinitionLevel int8
+	maxRepetitionLevel int16
+	maxDefinitionLevel int16
 
 	levels struct {
 		encoder encoding.Encoder
@@ -686,20 +1209,59 @@ type writerColumn struct {
 		encoder plain.Encoder
 	}
 
-	numRows        int64
-	maxValues      int32
-	numValues      int32
+	// When the dictionary has limits, the column falls back to a non-dictionary
+	// encoding for the rest of the row group after they were exceeded. The
+	// dictionary is still written for the pages that reference it.
+	fallback struct {
+		active     bool
+		maxSize    int64
+		maxValues  int
+		columnType Type
+		encoding   encoding.Encoding
+		// Number of bloom filter pages buffered when the fallback happened,
+		// those are indexed pages which are represented by the dictionary.
+		numFilterPages int
+		// State of the column restored at the beginning of row groups.
+		dictionary struct {
+			columnType Type
+			encoding   encoding.Encoding
+			encodings  []format.Encoding
+		}
+	}
+
+	numRows   int64
+	maxValues int32
+	numValues int32
+	// Estimate of the encoded size of buffered values, and the size at which
+	// they are flushed to a page (zero if pages are not limited in size), as
+	// well as the maximum number of values in pages (zero if not limited).
+	pageSize       int64
+	dataPageSize   int64
+	pageMaxValues  int32
 	bufferIndex    int32
 	bufferSize     int32
 	writePageStats bool
 	isCompressed   bool
 	encodings      []format.Encoding
+	encryption     *fileEncryptor
+	cryptoMetadata format.ColumnCryptoMetaData
+	observer       *WriterObserver
+	// Statistics of the column across all the row groups of the file, and in
+	// the last row group that pages were written to.
+	fileStats     columnFileStats
+	rowGroupStats columnFileStats
 
 	columnChunk *format.ColumnChunk
 	offsetIndex *format.OffsetIndex
 }
 
 func (c *writerColumn) reset() {
+	if c.fallback.active {
+		c.fallback.active = false
+		c.fallback.numFilterPages = 0
+		c.columnBuffer = nil // recreated with the indexed column type
+		c.setEncoding(c.fallback.dictionary.columnType, c.fallback.dictionary.encoding, c.fallback.dictionary.encodings)
+	}
 	if c.columnBuffer != nil {
 		c.columnBuffer.Reset()
 	}
@@ -722,6 +1284,7 @@ func (c *writerColumn) reset() {
 	c.pages = c.pages[:0]
 	c.numRows = 0
 	c.numValues = 0
+	c.pageSize = 0
 	// Reset the fields of column chunks that change between row groups,
 	// but keep the ones that remain unchanged.
 	c.columnChunk.MetaData.NumValues = 0
@@ -752,18 +1315,67 @@ func (c *writerColumn) totalRowCount() int64 {
 }
 
 func (c *writerColumn) canFlush() bool {
-	return c.columnBuffer.Size() >= int64(c.bufferSize/2)
+	return c.columnBuffer != nil && c.columnBuffer.Size() >= int64(c.bufferSize/2)
 }
 
 func (c *writerColumn) flush() (err error) {
 	if c.numValues != 0 {
 		c.numValues = 0
+		c.pageSize = 0
 		defer c.columnBuffer.Reset()
 		_, err = c.writeBufferedPage(c.columnBuffer.Page())
 	}
 	return err
 }
 
+// checkDictionaryLimits switches the column to its fallback encoding if the
+// dictionary exceeded its limits. The buffered values reference the dictionary
+// so they are flushed to a page first.
+func (c *writerColumn) checkDictionaryLimits() error {
+	if c.fallback.encoding == nil || c.fallback.active {
+		return nil
+	}
+	maxSize, maxValues := c.fallback.maxSize, c.fallback.maxValues
+	if !(maxSize > 0 && c.dictionary.Page().Size() > maxSize) && !(maxValues > 0 && c.dictionary.Len() > maxValues) {
+		return nil
+	}
+	if err := c.flush(); err != nil {
+		return err
+	}
+	c.fallback.active = true
+	c.fallback.numFilterPages = len(c.filter)
+	c.columnBuffer = nil // recreated with the fallback column type
+	// The slice of encodings is shared with the metadata of previous row groups
+	// so it must not be modified in place.
+	encodings := addEncoding(c.encodings[:len(c.encodings):len(c.encodings)], c.fallback.encoding.Encoding())
+	sortPageEncodings(encodings)
+	c.setEncoding(c.fallback.columnType, c.fallback.encoding, encodings)
+
+	if c.observer != nil && c.observer.OnDictionaryFallback != nil {
+		c.observer.OnDictionaryFallback(c.columnPath)
+	}
+	return nil
+}
+
+func (c *writerColumn) setEncoding(columnType Type, enc encoding.Encoding, encodings []format.Encoding) {
+	c.columnType = columnType
+	c.page.encoder = enc.NewEncoder(nil)
+	c.page.encoding = enc.Encoding()
+	c.encodings = encodings
+	c.columnChunk.MetaData.Encoding = encodings
+	c.isCompressed = c.compression.CompressionCodec() != format.Uncompressed && (c.dataPageType != format.DataPageV2 || !isDictionaryEncoding(enc))
+}
+
+// activeDictionary returns the dictionary that values written to the column
+// are inserted into, which is nil if the column has fallen back to a
+// non-dictionary encoding.
+func (c *writerColumn) activeDictionary() Dictionary {
+	if c.fallback.active {
+		return nil
+	}
+	return c.dictionary
+}
+
 func (c *writerColumn) flushFilterPages() error {
 	if c.columnFilter != nil {
 		numValues := int64(0)
@@ -774,12 +1386,20 @@ func (c *writerColumn) flushFilterPages() error {
 			c.page.filter = c.newBloomFilterEncoder(numValues)
 		}
 
-		// If there is a dictionary, we need to only write the dictionary.
+		// If there is a dictionary, we need to only write the dictionary, and
+		// the pages written after falling back to a non-dictionary encoding.
+		filter := c.filter
 		if dict := c.dictionary; dict != nil {
-			return dict.Page().WriteTo(c.page.filter)
+			if err := dict.Page().WriteTo(c.page.filter); err != nil {
+				return err
+			}
+			if !c.fallback.active {
+				return nil
+			}
+			filter = filter[c.fallback.numFilterPages:]
 		}
 
-		for _, page := range c.filter {
+		for _, page := range filter {
 			if err := page.WriteTo(c.page.filter); err != nil {
 				return err
 			}
@@ -813,7 +1433,8 @@ func (c *writerColumn) newColumnBuffer() ColumnBuffer {
 }
 
 func (c *writerColumn) newBloomFilterEncoder(numRows int64) *bloomFilterEncoder {
-	const bitsPerValue = 10 // TODO: make this configurable
+	// Filters created with SizedSplitBlockFilter override these defaults.
+	const bitsPerValue = 10
 	return newBloomFilterEncoder(
 		c.columnFilter.NewFilter(numRows, bitsPerValue),
 		c.columnFilter.Hash(),
@@ -825,7 +1446,7 @@ func (c *writerColumn) WriteRow(row Row) error {
 		// Lazily create the row group column so we don't need to allocate it if
 		// rows are not written individually to the column.
 		c.columnBuffer = c.newColumnBuffer()
-		c.maxValues = int32(c.columnBuffer.Cap())
+		c.setMaxValues()
 	}
 
 	if c.numValues > 0 && c.numValues > (c.maxValues-int32(len(row))) {
@@ -838,24 +1459,86 @@ func (c *writerColumn) WriteRow(row Row) error {
 		return err
 	}
 	c.numValues += int32(len(row))
-	return nil
+	if err := c.checkDictionaryLimits(); err != nil {
+		return err
+	}
+	return c.checkPageSize(row)
 }
 
 func (c *writerColumn) WriteValues(values []Value) (numValues int, err error) {
 	if c.columnBuffer == nil {
 		c.columnBuffer = c.newColumnBuffer()
-		c.maxValues = int32(c.columnBuffer.Cap())
+		c.setMaxValues()
 	}
 	numValues, err = c.columnBuffer.WriteValues(values)
 	c.numValues += int32(numValues)
+	if err == nil {
+		err = c.checkDictionaryLimits()
+	}
+	if err == nil {
+		err = c.checkPageSize(values[:numValues])
+	}
 	return numValues, err
 }
 
+// setMaxValues sets the number of values that can be buffered before flushing
+// them to a page.
+func (c *writerColumn) setMaxValues() {
+	c.maxValues = int32(c.columnBuffer.Cap())
+	if c.pageMaxValues > 0 && c.pageMaxValues < c.maxValues {
+		c.maxValues = c.pageMaxValues
+	}
+}
+
+// checkPageSize flushes the buffered values to a page if their estimated size
+// reached the target data page size, or if they reached the maximum number of
+// values in data pages.
+func (c *writerColumn) checkPageSize(values []Value) error {
+	if c.pageMaxValues > 0 && c.numValues >= c.pageMaxValues {
+		return c.flush()
+	}
+	if c.dataPageSize == 0 || c.numValues == 0 {
+		return nil
+	}
+	if c.activeDictionary() != nil {
+		// Dictionary encoded pages contain the indexes of values.
+		c.pageSize += 4 * int64(len(values))
+	} else {
+		c.pageSize += plainSizeOfValues(values)
+	}
+	if c.pageSize < c.dataPageSize {
+		return nil
+	}
+	return c.flush()
+}
+
+// plainSizeOfValues returns the size of values when encoded with PLAIN.
+func plainSizeOfValues(values []Value) int64 {
+	size := int64(0)
+	for i := range values {
+		switch v := &values[i]; v.Kind() {
+		case Boolean:
+			size++ // over-estimated, booleans are bit-packed
+		case Int32, Float:
+			size += 4
+		case Int64, Double:
+			size += 8
+		case Int96:
+			size += 12
+		case ByteArray:
+			size += 4 + int64(len(v.ByteArray()))
+		case FixedLenByteArray:
+			size += int64(len(v.ByteArray()))
+		}
+	}
+	return size
+}
+
 func (c *writerColumn) WritePage(page Page) (numValues int64, err error) {
 	// Page write optimizations are only available the column is not reindexing
 	// the values. If a dictionary is present, the column needs to see each
 	// individual value in order to re-index them in the dictionary.
-	if c.dictionary == nil || c.dictionary == page.Dictionary() {
+	if dict := c.activeDictionary(); dict == nil || dict == page.Dictionary() {
 		// If the column had buffered values, we continue writing values from
 		// the page into the column buffer if it would have caused producing a
 		// page less than half the size of the target; if there were enough
@@ -876,11 +1559,18 @@ func (c *writerColumn) WritePage(page Page) (numValues int64, err error) {
 				// Buffered pages may be larger than the target page size on the
 				// column, in which case multiple pages get written by slicing
 				// the original page into sub-pages.
-				err = forEachPageSlice(p, int64(c.bufferSize), func(p BufferedPage) error {
+				pageSize := int64(c.bufferSize)
+				if c.dataPageSize > 0 && c.dataPageSize < pageSize {
+					pageSize = c.dataPageSize
+				}
+				err = forEachPageSlice(p, pageSize, int64(c.pageMaxValues), func(p BufferedPage) error {
 					n, err := c.writeBufferedPage(p)
 					numValues += n
 					return err
 				})
+				if err == nil {
+					err = c.checkDictionaryLimits()
+				}
 				return numValues, err
 
 			case CompressedPage:
@@ -889,7 +1579,13 @@ func (c *writerColumn) WritePage(page Page) (numValues int64, err error) {
 				// are being copied into a new file, they are simply copied to
 				// amortize the cost of decoding and re-encoding the pages, which
 				// often includes costly compression steps.
-				return c.writeCompressedPage(p)
+				//
+				// The pages must have been encoded and compressed the same way
+				// as the column would have, otherwise their values have to be
+				// re-encoded.
+				if c.canWriteCompressedPage(p) {
+					return c.writeCompressedPage(p)
+				}
 			}
 		}
 	}
@@ -914,17 +1610,39 @@ func (c *writerColumn) writePageValues(page ValueReader) (numValues int64, err e
 }
 
 func (c *writerColumn) writeBloomFilter(w io.Writer) error {
-	e := thrift.NewEncoder(c.header.protocol.NewWriter(w))
 	h := bloomFilterHeader(c.columnFilter)
 	b := c.page.filter.Bytes()
 	h.NumBytes = int32(len(b))
-	if err := e.Encode(&h); err != nil {
+
+	c.header.buffer.Reset()
+	if err := c.header.encoder.Encode(&h); err != nil {
+		return err
+	}
+	if c.encryption != nil {
+		if err := c.encrypt(c.header.buffer, bloomFilterHeaderModule, -1); err != nil {
+			return err
+		}
+	}
+	if _, err := w.Write(c.header.buffer.Bytes()); err != nil {
 		return err
 	}
+	if c.encryption != nil {
+		var err error
+		if b, err = c.encryption.encryptBytes(b, bloomFilterBitsetModule, c.encryption.rowGroup, int(c.bufferIndex), -1); err != nil {
+			return err
+		}
+	}
 	_, err := w.Write(b)
 	return err
 }
 
+// encrypt replaces the content of buffer with the encrypted module of the
+// given type. The page ordinal must be negative for modules that are neither
+// data pages nor data page headers.
+func (c *writerColumn) encrypt(buffer *bytes.Buffer, moduleType byte, pageOrdinal int) error {
+	return c.encryption.encrypt(buffer, moduleType, c.encryption.rowGroup, int(c.bufferIndex), pageOrdinal)
+}
+
 func (c *writerColumn) writeBufferedPage(page BufferedPage) (int64, error) {
 	numValues := page.NumValues()
 	if numValues == 0 {
@@ -939,15 +1657,15 @@ func (c *writerColumn) writeBufferedPage(page BufferedPage) (int64, error) {
 		if c.maxRepetitionLevel > 0 {
 			c.page.uncompressed.Reset(c.page.buffer)
 			c.levels.encoder.Reset(&c.page.uncompressed)
-			c.levels.encoder.SetBitWidth(bits.Len8(c.maxRepetitionLevel))
-			c.levels.encoder.EncodeInt8(page.RepetitionLevels())
+			c.levels.encoder.SetBitWidth(bits.Len16(c.maxRepetitionLevel))
+			c.levels.encoder.EncodeInt16(page.RepetitionLevels())
 			repetitionLevelsByteLength = int32(c.page.uncompressed.offset)
 		}
 		if c.maxDefinitionLevel > 0 {
 			c.page.uncompressed.Reset(c.page.buffer)
 			c.levels.encoder.Reset(&c.page.uncompressed)
-			c.levels.encoder.SetBitWidth(bits.Len8(c.maxDefinitionLevel))
-			c.levels.encoder.EncodeInt8(page.DefinitionLevels())
+			c.levels.encoder.SetBitWidth(bits.Len16(c.maxDefinitionLevel))
+			c.levels.encoder.EncodeInt16(page.DefinitionLevels())
 			definitionLevelsByteLength = int32(c.page.uncompressed.offset)
 		}
 	}
@@ -966,15 +1684,15 @@ func (c *writerColumn) writeBufferedPage(page BufferedPage) (int64, error) {
 		if c.maxRepetitionLevel > 0 {
 			c.levels.v1.Reset(&c.page.uncompressed)
 			c.levels.encoder.Reset(&c.levels.v1)
-			c.levels.encoder.SetBitWidth(bits.Len8(c.maxRepetitionLevel))
-			c.levels.encoder.EncodeInt8(page.RepetitionLevels())
+			c.levels.encoder.SetBitWidth(bits.Len16(c.maxRepetitionLevel))
+			c.levels.encoder.EncodeInt16(page.RepetitionLevels())
 			c.levels.v1.Close()
 		}
 		if c.maxDefinitionLevel > 0 {
 			c.levels.v1.Reset(&c.page.uncompressed)
 			c.levels.encoder.Reset(&c.levels.v1)
-			c.levels.encoder.SetBitWidth(bits.Len8(c.maxDefinitionLevel))
-			c.levels.encoder.EncodeInt8(page.DefinitionLevels())
+			c.levels.encoder.SetBitWidth(bits.Len16(c.maxDefinitionLevel))
+			c.levels.encoder.EncodeInt16(page.DefinitionLevels())
 			c.levels.v1.Close()
 		}
 	}
@@ -1003,6 +1721,13 @@ func (c *writerColumn) writeBufferedPage(page BufferedPage) (int64, error) {
 		}
 	}
 
+	pageOrdinal := len(c.offsetIndex.PageLocations)
+	if c.encryption != nil {
+		if err := c.encrypt(c.page.buffer, dataPageModule, pageOrdinal); err != nil {
+			return 0, err
+		}
+	}
+
 	c.header.buffer.Reset()
 	levelsByteLength := repetitionLevelsByteLength + definitionLevelsByteLength
 	uncompressedPageSize := c.page.uncompressed.offset + int64(levelsByteLength)
@@ -1042,6 +1767,11 @@ func (c *writerColumn) writeBufferedPage(page BufferedPage) (int64, error) {
 	if err := c.header.encoder.Encode(pageHeader); err != nil {
 		return 0, err
 	}
+	if c.encryption != nil {
+		if err := c.encrypt(c.header.buffer, dataPageHeaderModule, pageOrdinal); err != nil {
+			return 0, err
+		}
+	}
 	headerSize := int32(c.header.buffer.Len())
 	compressedSize := int64(headerSize) + int64(compressedPageSize)
 	if err := c.writePage(compressedSize, c.header.buffer, c.page.buffer); err != nil {
@@ -1051,6 +1781,36 @@ func (c *writerColumn) writeBufferedPage(page BufferedPage) (int64, error) {
 	return numValues, nil
 }
 
+func (c *writerColumn) canWriteCompressedPage(page CompressedPage) bool {
+	if page.PageHeader().Encoding() != c.page.encoding {
+		return false
+	}
+	if p, ok := page.(interface {
+		zstdDictionaryID() uint32
+	}); ok && p.zstdDictionaryID() != c.zstdDictionaryID {
+		return false // the page must be decompressed with another dictionary
+	}
+	// Pages which do not expose their compression codec are re-encoded since
+	// they cannot be assumed to be compressed like the column.
+	p, ok := page.(interface {
+		compressionCodec() format.CompressionCodec
+	})
+	return ok && p.compressionCodec() == c.compression.CompressionCodec()
+}
+
+// keyValueMetadata returns the key/value metadata of the column chunks written
+// by c, which record the identifier of the dictionary of zstd codecs so readers
+// can tell which dictionary the pages must be decompressed with.
+func (c *writerColumn) keyValueMetadata() []format.KeyValue {
+	if c.zstdDictionaryID == 0 {
+		return nil
+	}
+	return []format.KeyValue{{
+		Key:   zstdDictionaryKey,
+		Value: strconv.FormatUint(uint64(c.zstdDictionaryID), 10),
+	}}
+}
+
 func (c *writerColumn) writeCompressedPage(page CompressedPage) (int64, error) {
 	switch {
 	case c.page.filter != nil:
@@ -1079,10 +1839,27 @@ func (c *writerColumn) writeCompressedPage(page CompressedPage) (int64, error) {
 		CRC:                  int32(page.CRC()),
 	}
 
+	pageData := page.PageData()
+	pageOrdinal := len(c.offsetIndex.PageLocations)
+	if c.encryption != nil {
+		c.page.buffer.Reset()
+		if _, err := io.Copy(c.page.buffer, pageData); err != nil {
+			return 0, err
+		}
+		if err := c.encrypt(c.page.buffer, dataPageModule, pageOrdinal); err != nil {
+			return 0, err
+		}
+		pageHeader.CompressedPageSize = int32(c.page.buffer.Len())
+		pageHeader.CRC = int32(crc32.ChecksumIEEE(c.page.buffer.Bytes()))
+		pageData = c.page.buffer
+	}
+
 	switch h := page.PageHeader().(type) {
 	case DataPageHeaderV1:
+		pageHeader.Type = format.DataPage
 		pageHeader.DataPageHeader = h.header
 	case DataPageHeaderV2:
+		pageHeader.Type = format.DataPageV2
 		pageHeader.DataPageHeaderV2 = h.header
 	default:
 		return 0, fmt.Errorf("writing compressed page type of unknown type: %s", h.PageType())
@@ -1092,9 +1869,14 @@ func (c *writerColumn) writeCompressedPage(page CompressedPage) (int64, error) {
 	if err := c.header.encoder.Encode(pageHeader); err != nil {
 		return 0, err
 	}
+	if c.encryption != nil {
+		if err := c.encrypt(c.header.buffer, dataPageHeaderModule, pageOrdinal); err != nil {
+			return 0, err
+		}
+	}
 	headerSize := int32(c.header.buffer.Len())
 	compressedSize := int64(headerSize + pageHeader.CompressedPageSize)
-	if err := c.writePage(compressedSize, c.header.buffer, page.PageData()); err != nil {
+	if err := c.writePage(compressedSize, c.header.buffer, pageData); err != nil {
 		return 0, err
 	}
 	c.recordPageStats(headerSize, pageHeader, page)
@@ -1142,6 +1924,11 @@ func (c *writerColumn) writeDictionaryPage(output io.Writer, dict Dictionary) er
 	if err := p.Close(); err != nil {
 		return fmt.Errorf("flushing compressed parquet dictionary page: %w", err)
 	}
+	if c.encryption != nil {
+		if err := c.encrypt(c.page.buffer, dictionaryPageModule, -1); err != nil {
+			return fmt.Errorf("encrypting parquet dictionary page: %w", err)
+		}
+	}
 
 	pageHeader := &format.PageHeader{
 		Type:                 format.DictionaryPage,
@@ -1159,6 +1946,11 @@ func (c *writerColumn) writeDictionaryPage(output io.Writer, dict Dictionary) er
 	if err := c.header.encoder.Encode(pageHeader); err != nil {
 		return err
 	}
+	if c.encryption != nil {
+		if err := c.encrypt(c.header.buffer, dictionaryPageHeaderModule, -1); err != nil {
+			return fmt.Errorf("encrypting parquet dictionary page header: %w", err)
+		}
+	}
 	if _, err := output.Write(c.header.buffer.Bytes()); err != nil {
 		return err
 	}
@@ -1198,6 +1990,44 @@ func (c *writerColumn) makePageStatistics(page Page) format.Statistics {
 	}
 }
 
+// makeChunkStatistics returns the statistics of the column chunk written for
+// the current row group. Unlike the column index, the bounds are never
+// truncated.
+func (c *writerColumn) makeChunkStatistics() format.Statistics {
+	minValueBytes := c.rowGroupStats.minValue.Bytes()
+	maxValueBytes := c.rowGroupStats.maxValue.Bytes()
+	return format.Statistics{
+		Min:       minValueBytes, // deprecated
+		Max:       maxValueBytes, // deprecated
+		NullCount: c.rowGroupStats.numNulls,
+		MinValue:  minValueBytes,
+		MaxValue:  maxValueBytes,
+	}
+}
+
+// columnFileStats accumulates the statistics of the pages written to a column
+// of a file.
+type columnFileStats struct {
+	numValues int64
+	numNulls  int64
+	minValue  Value
+	maxValue  Value
+}
+
+func (s *columnFileStats) observe(typ Type, numValues, numNulls int64, minValue, maxValue Value) {
+	s.numValues += numValues
+	s.numNulls += numNulls
+	if minValue.IsNull() || maxValue.IsNull() {
+		return
+	}
+	if s.minValue.IsNull() || typ.Compare(minValue, s.minValue) < 0 {
+		s.minValue = minValue.Clone()
+	}
+	if s.maxValue.IsNull() || typ.Compare(maxValue, s.maxValue) > 0 {
+		s.maxValue = maxValue.Clone()
+	}
+}
+
 func (c *writerColumn) recordPageStats(headerSize int32, header *format.PageHeader, page Page) {
 	uncompressedSize := headerSize + header.UncompressedPageSize
 	compressedSize := headerSize + header.CompressedPageSize
@@ -1208,6 +2038,13 @@ func (c *writerColumn) recordPageStats(headerSize int32, header *format.PageHead
 		minValue, maxValue := page.Bounds()
 		c.columnIndex.IndexPage(numValues, numNulls, minValue, maxValue)
 		c.columnChunk.MetaData.NumValues += numValues
+		c.fileStats.observe(c.columnType, numValues, numNulls, minValue, maxValue)
+		// The statistics of the previous row group are retained until the
+		// first page of the next one, so they can be reported after flushing.
+		if len(c.offsetIndex.PageLocations) == 0 {
+			c.rowGroupStats = columnFileStats{}
+		}
+		c.rowGroupStats.observe(c.columnType, numValues, numNulls, minValue, maxValue)
 
 		c.offsetIndex.PageLocations = append(c.offsetIndex.PageLocations, format.PageLocation{
 			Offset:             c.columnChunk.MetaData.TotalCompressedSize,
@@ -1236,6 +2073,23 @@ func (c *writerColumn) recordPageStats(headerSize int32, header *format.PageHead
 		Encoding: encoding,
 		Count:    1,
 	})
+
+	if c.observer != nil && c.observer.OnPage != nil {
+		stats := WriterPageStats{
+			Column:           int(c.bufferIndex),
+			Path:             c.columnPath,
+			PageType:         pageType,
+			Encoding:         encoding,
+			UncompressedSize: int64(uncompressedSize),
+			CompressedSize:   int64(compressedSize),
+		}
+		if page != nil {
+			stats.NumValues = page.NumValues()
+		} else if header.DictionaryPageHeader != nil {
+			stats.NumValues = int64(header.DictionaryPageHeader.NumValues)
+		}
+		c.observer.OnPage(stats)
+	}
 }
 
 func addEncoding(encodings []format.Encoding, add format.Encoding) []format.Encoding {
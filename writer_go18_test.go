@@ -0,0 +1,50 @@
+//go:build go1.18
+
+package parquet_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestWriterDeriveFrom(t *testing.T) {
+	type Row struct {
+		Name string `parquet:"name"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer,
+		parquet.DerivedColumns(
+			parquet.DeriveFrom("upper", parquet.String(), func(row *Row) parquet.Value {
+				return parquet.ValueOf(strings.ToUpper(row.Name))
+			}),
+		),
+	)
+	for _, name := range []string{"a", "b"} {
+		if err := writer.Write(&Row{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	type RowWithUpper struct {
+		Name  string `parquet:"name"`
+		Upper string `parquet:"upper"`
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	for _, want := range []RowWithUpper{{"a", "A"}, {"b", "B"}} {
+		got := RowWithUpper{}
+		if err := reader.Read(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("wrong row: want=%+v got=%+v", want, got)
+		}
+	}
+}
@@ -2,18 +2,33 @@ package parquet_test
 
 import (
 	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"os/exec"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"testing/quick"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hexops/gotextdiff"
 	"github.com/hexops/gotextdiff/myers"
 	"github.com/hexops/gotextdiff/span"
+	"github.com/segmentio/encoding/thrift"
 	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress/zstd"
+	"github.com/segmentio/parquet-go/encoding"
+	"github.com/segmentio/parquet-go/encoding/plain"
+	"github.com/segmentio/parquet-go/format"
 )
 
 const (
@@ -603,3 +618,2590 @@ func TestWriterRepeatedUUIDDict(t *testing.T) {
 		t.Errorf("expected to get UUID %q back out, got %q", inputID, row[0].Bytes())
 	}
 }
+
+func TestWriterEncryption(t *testing.T) {
+	type Record struct {
+		Name  string `parquet:"name,dict"`
+		Email string `parquet:"email"`
+		Score int64  `parquet:"score"`
+	}
+
+	key := []byte("0123456789abcdef")
+	keyMetadata := []byte("footer-key")
+	aadPrefix := []byte("table/people")
+
+	for _, config := range [...]struct {
+		scenario string
+		options  []parquet.WriterOption
+	}{
+		{scenario: "data page v1", options: []parquet.WriterOption{parquet.DataPageVersion(v1)}},
+		{scenario: "data page v2", options: []parquet.WriterOption{parquet.DataPageVersion(v2)}},
+		{scenario: "bloom filters", options: []parquet.WriterOption{parquet.BloomFilters(parquet.SplitBlockFilter("email"))}},
+	} {
+		t.Run(config.scenario, func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			writer := parquet.NewWriter(buffer, append(config.options,
+				parquet.PageBufferSize(64),
+				parquet.Encryption(&parquet.EncryptionConfig{
+					FooterKey:         key,
+					FooterKeyMetadata: keyMetadata,
+					AADPrefix:         aadPrefix,
+				}),
+			)...)
+
+			const numRows = 100
+			for i := 0; i < numRows; i++ {
+				if err := writer.Write(&Record{
+					Name:  fmt.Sprintf("secret-name-%d", i%3),
+					Email: fmt.Sprintf("secret-email-%d@example.com", i),
+					Score: int64(i),
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			b := buffer.Bytes()
+			if string(b[:4]) != "PARE" || string(b[len(b)-4:]) != "PARE" {
+				t.Fatalf("encrypted file must start and end with PARE: %q...%q", b[:4], b[len(b)-4:])
+			}
+			if bytes.Contains(b, []byte("secret")) {
+				t.Fatal("encrypted file contains plaintext values")
+			}
+
+			footerLength := int(binary.LittleEndian.Uint32(b[len(b)-8:]))
+			footer := b[len(b)-(footerLength+8) : len(b)-8]
+
+			cryptoMetaData := format.FileCryptoMetaData{}
+			footerReader := bytes.NewReader(footer)
+			decoder := thrift.NewDecoder(new(thrift.CompactProtocol).NewReader(footerReader))
+			if err := decoder.Decode(&cryptoMetaData); err != nil {
+				t.Fatal(err)
+			}
+			algorithm := cryptoMetaData.EncryptionAlgorithm.AesGcmV1
+			if algorithm == nil {
+				t.Fatal("file was not encrypted with AES_GCM_V1")
+			}
+			if !bytes.Equal(algorithm.AadPrefix, aadPrefix) {
+				t.Errorf("wrong AAD prefix: want=%q got=%q", aadPrefix, algorithm.AadPrefix)
+			}
+			if !bytes.Equal(cryptoMetaData.KeyMetadata, keyMetadata) {
+				t.Errorf("wrong key metadata: want=%q got=%q", keyMetadata, cryptoMetaData.KeyMetadata)
+			}
+
+			// The encrypted footer module follows the crypto metadata.
+			module := footer[len(footer)-footerReader.Len():]
+			block, err := aes.NewCipher(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gcm, err := cipher.NewGCM(block)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n := int(binary.LittleEndian.Uint32(module)); n != len(module)-4 {
+				t.Fatalf("wrong footer module length: want=%d got=%d", len(module)-4, n)
+			}
+			aad := append(append(append([]byte{}, aadPrefix...), algorithm.AadFileUnique...), 0)
+			plaintext, err := gcm.Open(nil, module[4:16], module[16:], aad)
+			if err != nil {
+				t.Fatal("decrypting footer:", err)
+			}
+
+			metadata := format.FileMetaData{}
+			if err := thrift.Unmarshal(new(thrift.CompactProtocol), plaintext, &metadata); err != nil {
+				t.Fatal(err)
+			}
+			if metadata.NumRows != numRows {
+				t.Errorf("wrong number of rows: want=%d got=%d", numRows, metadata.NumRows)
+			}
+			for _, rowGroup := range metadata.RowGroups {
+				for _, column := range rowGroup.Columns {
+					if column.CryptoMetadata.EncryptionWithFooterKey == nil {
+						t.Errorf("column %q is not encrypted with the footer key", column.MetaData.PathInSchema)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestWriterEncryptionInvalidKey(t *testing.T) {
+	_, err := parquet.NewWriterConfig(parquet.Encryption(&parquet.EncryptionConfig{
+		FooterKey: []byte("too short"),
+	}))
+	if err == nil {
+		t.Fatal("expected an error for an invalid encryption key")
+	}
+	if strings.Contains(err.Error(), "too short") {
+		t.Errorf("the error message must not contain the key: %v", err)
+	}
+}
+
+func TestWriterEncryptionUnknownColumnKey(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("creating a writer with a key for a column which does not exist must panic")
+		}
+	}()
+	parquet.NewWriter(new(bytes.Buffer), parquet.SchemaOf(struct{ Name string }{}), parquet.Encryption(&parquet.EncryptionConfig{
+		FooterKey: []byte("0123456789abcdef"),
+		ColumnKeys: []parquet.ColumnKey{
+			{Path: []string{"email"}, Key: []byte("fedcba9876543210")},
+		},
+	}))
+}
+
+func TestWriterEncryptionKeyWrapperError(t *testing.T) {
+	writer := parquet.NewWriter(new(bytes.Buffer), parquet.Encryption(&parquet.EncryptionConfig{
+		FooterKey: []byte("0123456789abcdef"),
+		KeyWrapper: parquet.EncryptionKeyWrapperFunc(func([]byte) ([]byte, error) {
+			return nil, errors.New("key management service unavailable")
+		}),
+	}))
+	if err := writer.Write(&struct{ Name string }{Name: "Luke"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err == nil {
+		t.Error("expected an error when the key wrapper fails")
+	}
+}
+
+func TestWriterColumnCompression(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name,snappy"`
+		Body string `parquet:"body,snappy"`
+		Blob []byte `parquet:"blob"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer,
+		parquet.Compression(&parquet.Zstd),
+		parquet.ColumnCompression(&parquet.Gzip, "body"),
+		parquet.ColumnCompression(&parquet.Uncompressed, "blob"),
+	)
+	for i := 0; i < 100; i++ {
+		row := Row{
+			ID:   int64(i),
+			Name: fmt.Sprintf("name-%d", i%3),
+			Body: strings.Repeat("hello world! ", i),
+			Blob: []byte{byte(i)},
+		}
+		if err := writer.Write(&row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]format.CompressionCodec{
+		"id":   format.Zstd,   // default codec of the writer
+		"name": format.Snappy, // codec declared in the schema
+		"body": format.Gzip,
+		"blob": format.Uncompressed,
+	}
+	for _, column := range f.Metadata().RowGroups[0].Columns {
+		path := strings.Join(column.MetaData.PathInSchema, ".")
+		if codec := column.MetaData.Codec; codec != want[path] {
+			t.Errorf("wrong codec for column %q: want=%s got=%s", path, want[path], codec)
+		}
+	}
+
+	reader := parquet.NewReader(f)
+	for i := 0; i < 100; i++ {
+		var row Row
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if row.ID != int64(i) || row.Body != strings.Repeat("hello world! ", i) {
+			t.Fatalf("row %d mismatch: %+v", i, row)
+		}
+	}
+}
+
+func TestWriterColumnCompressionInvalid(t *testing.T) {
+	if _, err := parquet.NewWriterConfig(parquet.ColumnCompression(nil, "name")); err == nil {
+		t.Error("expected an error when configuring a nil codec on a column")
+	}
+}
+
+func TestWriterColumnEncoding(t *testing.T) {
+	type Row struct {
+		Timestamp int64  `parquet:"ts"`
+		ID        string `parquet:"id,dict"`
+		Blob      []byte `parquet:"blob,dict"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer,
+		parquet.ColumnEncoding(&parquet.DeltaBinaryPacked, "ts"),
+		parquet.ColumnEncoding(&parquet.Plain, "id"),
+		parquet.DisableColumnEncoding(&parquet.RLEDictionary, "blob"),
+	)
+	for i := 0; i < 100; i++ {
+		row := Row{
+			Timestamp: int64(1e9 + i),
+			ID:        uuid.NewString(),
+			Blob:      []byte{byte(i)},
+		}
+		if err := writer.Write(&row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]format.Encoding{
+		"ts":   format.DeltaBinaryPacked,
+		"id":   format.Plain,
+		"blob": format.DeltaLengthByteArray,
+	}
+	for _, column := range f.Metadata().RowGroups[0].Columns {
+		path := strings.Join(column.MetaData.PathInSchema, ".")
+		found := false
+		for _, encoding := range column.MetaData.Encoding {
+			if encoding == format.RLEDictionary {
+				t.Errorf("column %q must not use dictionary encoding", path)
+			}
+			if encoding == want[path] {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("column %q was not encoded with %s: %v", path, want[path], column.MetaData.Encoding)
+		}
+	}
+
+	reader := parquet.NewReader(f)
+	for i := 0; i < 100; i++ {
+		var row Row
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if row.Timestamp != int64(1e9+i) || len(row.Blob) != 1 || row.Blob[0] != byte(i) {
+			t.Fatalf("row %d mismatch: %+v", i, row)
+		}
+	}
+}
+
+func TestWriterColumnEncodingInvalid(t *testing.T) {
+	for _, test := range []struct {
+		scenario string
+		writer   func()
+	}{
+		{
+			scenario: "column encoding",
+			writer: func() {
+				parquet.NewWriter(new(bytes.Buffer),
+					parquet.SchemaOf(struct{ Name string }{}),
+					parquet.ColumnEncoding(&parquet.DeltaBinaryPacked, "Name"),
+				)
+			},
+		},
+		{
+			// Encodings which cannot encode the column are skipped when they
+			// come from the schema, but not when configured on the writer.
+			scenario: "column encoding overriding the schema",
+			writer: func() {
+				parquet.NewWriter(new(bytes.Buffer),
+					parquet.NewSchema("test", parquet.Group{
+						"name": parquet.Encoded(parquet.String(), &parquet.DeltaLengthByteArray),
+					}),
+					parquet.ColumnEncoding(&parquet.DeltaBinaryPacked, "name"),
+					parquet.DisableColumnEncoding(&parquet.DeltaLengthByteArray, "name"),
+				)
+			},
+		},
+		{
+			scenario: "schema encoding",
+			writer: func() {
+				parquet.NewWriter(new(bytes.Buffer),
+					parquet.NewSchema("test", parquet.Group{
+						"name": parquet.Encoded(parquet.String(), &parquet.DeltaBinaryPacked),
+					}),
+				)
+			},
+		},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("creating a writer with an encoding which cannot be applied to a column must panic")
+				}
+			}()
+			test.writer()
+		})
+	}
+}
+
+// proprietaryEncoding is an encoding identified by a code which is not defined
+// by the parquet format, encoding values like PLAIN.
+type proprietaryEncoding struct{ plain plain.Encoding }
+
+const proprietaryEncodingCode format.Encoding = 100
+
+func (e *proprietaryEncoding) String() string { return "PROPRIETARY" }
+
+func (e *proprietaryEncoding) Encoding() format.Encoding { return proprietaryEncodingCode }
+
+func (e *proprietaryEncoding) CanEncode(t format.Type) bool { return e.plain.CanEncode(t) }
+
+func (e *proprietaryEncoding) NewDecoder(r io.Reader) encoding.Decoder { return e.plain.NewDecoder(r) }
+
+func (e *proprietaryEncoding) NewEncoder(w io.Writer) encoding.Encoder { return e.plain.NewEncoder(w) }
+
+var registerProprietaryEncoding sync.Once
+
+func TestWriterRegisteredEncoding(t *testing.T) {
+	registerProprietaryEncoding.Do(func() {
+		if err := parquet.RegisterEncoding(&proprietaryEncoding{}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if err := parquet.RegisterEncoding(&proprietaryEncoding{}); err == nil {
+		t.Error("registering an encoding twice did not fail")
+	}
+	if err := parquet.RegisterEncoding(&plain.Encoding{}); err == nil {
+		t.Error("registering an encoding supported by the package did not fail")
+	}
+	if e := parquet.LookupEncoding(proprietaryEncodingCode); e.Encoding() != proprietaryEncodingCode {
+		t.Fatalf("wrong encoding returned by the lookup: %s", e)
+	}
+
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.ColumnEncoding(&proprietaryEncoding{}, "name"))
+	for i := 0; i < 100; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Name: fmt.Sprint("name-", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	column := f.Metadata().RowGroups[0].Columns[1]
+	if path := strings.Join(column.MetaData.PathInSchema, "."); path != "name" {
+		t.Fatalf("wrong column path: %q", path)
+	}
+	found := false
+	for _, enc := range column.MetaData.Encoding {
+		found = found || enc == proprietaryEncodingCode
+	}
+	if !found {
+		t.Errorf("column was not encoded with the registered encoding: %v", column.MetaData.Encoding)
+	}
+
+	reader := parquet.NewReader(f)
+	for i := 0; i < 100; i++ {
+		var row Row
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if row.ID != int64(i) || row.Name != fmt.Sprint("name-", i) {
+			t.Fatalf("row %d mismatch: %+v", i, row)
+		}
+	}
+}
+
+func TestWriterDictionaryLimits(t *testing.T) {
+	type Row struct {
+		ID   string `parquet:"id,dict,snappy"`
+		City string `parquet:"city,dict,snappy"`
+		Code string `parquet:"code,dict,snappy"`
+	}
+
+	const numRows = 1000
+	rows := make([]Row, numRows)
+	for i := range rows {
+		rows[i] = Row{
+			ID:   fmt.Sprintf("id-%04d", i),
+			City: fmt.Sprintf("city-%d", i%10),
+			Code: fmt.Sprintf("code-%d", i%200),
+		}
+	}
+
+	for _, dataPageVersion := range []int{1, 2} {
+		t.Run(fmt.Sprintf("v%d", dataPageVersion), func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			writer := parquet.NewWriter(buffer,
+				parquet.DataPageVersion(dataPageVersion),
+				parquet.PageBufferSize(512),
+				parquet.DictionaryMaxValues(100),
+				parquet.DictionaryLimits(0, 0, "code"),
+				parquet.BloomFilters(parquet.SplitBlockFilter("id")),
+			)
+			for i := range rows {
+				if err := writer.Write(&rows[i]); err != nil {
+					t.Fatal(err)
+				}
+				if i == numRows/2 {
+					if err := writer.Flush(); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, rowGroup := range f.Metadata().RowGroups {
+				for _, column := range rowGroup.Columns {
+					path := strings.Join(column.MetaData.PathInSchema, ".")
+					hasFallback := false
+					for _, encoding := range column.MetaData.Encoding {
+						if encoding == format.DeltaLengthByteArray {
+							hasFallback = true
+						}
+					}
+					if want := path == "id"; hasFallback != want {
+						t.Errorf("row group %d: column %q fallback encoding: want=%t got=%t (%v)", i, path, want, hasFallback, column.MetaData.Encoding)
+					}
+				}
+			}
+
+			// Columns are sorted by name: city, code, id. The dictionary must
+			// be used again at the beginning of each row group.
+			for i := 0; i < f.NumRowGroups(); i++ {
+				page, err := f.RowGroup(i).Column(2).Pages().ReadPage()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if dict := page.Dictionary(); dict == nil {
+					t.Errorf("row group %d: the first page is not dictionary encoded", i)
+				} else if n := dict.Len(); n > 100+1 {
+					t.Errorf("row group %d: the dictionary exceeded its limit: %d values", i, n)
+				}
+			}
+
+			for _, id := range []string{rows[0].ID, rows[numRows/2].ID} {
+				bloomFilter := f.RowGroup(0).Column(2).BloomFilter()
+				if ok, err := bloomFilter.Check(parquet.ValueOf(id)); err != nil {
+					t.Fatal(err)
+				} else if !ok {
+					t.Errorf("bloom filter does not contain %q", id)
+				}
+			}
+
+			reader := parquet.NewReader(f)
+			for i := range rows {
+				var row Row
+				if err := reader.Read(&row); err != nil {
+					t.Fatalf("reading row %d: %v", i, err)
+				}
+				if row != rows[i] {
+					t.Fatalf("row %d mismatch:\nwant = %+v\ngot  = %+v", i, rows[i], row)
+				}
+			}
+		})
+	}
+}
+
+func TestWriterDisableDictionary(t *testing.T) {
+	type Row struct {
+		ID   string `parquet:"id,dict"`
+		City string `parquet:"city,dict"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.DisableDictionary("id"))
+	for i := 0; i < 100; i++ {
+		if err := writer.Write(&Row{ID: uuid.NewString(), City: "Paris"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, column := range f.Metadata().RowGroups[0].Columns {
+		path := strings.Join(column.MetaData.PathInSchema, ".")
+		hasDictionary := column.MetaData.DictionaryPageOffset != 0
+		if want := path == "city"; hasDictionary != want {
+			t.Errorf("column %q dictionary: want=%t got=%t", path, want, hasDictionary)
+		}
+	}
+}
+
+func TestWriterDataPageSize(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	const numRows = 10000
+	const dataPageSize = 8 * 1024
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.DataPageSize(dataPageSize))
+	for i := 0; i < numRows; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Name: fmt.Sprintf("name-%d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rowGroup := f.RowGroup(0)
+	for i := 0; i < rowGroup.NumColumns(); i++ {
+		column := rowGroup.Column(i)
+		numPages, numValues := 0, int64(0)
+		pages := column.Pages()
+		for {
+			page, err := pages.ReadPage()
+			if err != nil {
+				if err == io.EOF {
+					break
+				}
+				t.Fatal(err)
+			}
+			numPages++
+			numValues += page.NumValues()
+			// Allow the page to exceed the target by the size of one value.
+			if size := page.Size(); size > dataPageSize+32 {
+				t.Errorf("page of column %d is larger than the target: %d", column.Column(), size)
+			}
+		}
+		if numPages < 2 {
+			t.Errorf("column %d was written to a single page", column.Column())
+		}
+		if numValues != numRows {
+			t.Errorf("wrong number of values in column %d: want=%d got=%d", column.Column(), numRows, numValues)
+		}
+	}
+}
+
+func TestWriterWriteRowGroup(t *testing.T) {
+	type Row struct {
+		ID   int64    `parquet:"id"`
+		Name string   `parquet:"name,dict,zstd"`
+		Tags []string `parquet:"tags"`
+	}
+
+	makeRow := func(i int) Row {
+		return Row{ID: int64(i), Name: fmt.Sprintf("name-%d", i%10), Tags: []string{"a", "b", "c"}[:i%4]}
+	}
+
+	schema := parquet.SchemaOf(Row{})
+	sorting := parquet.SortingColumns(parquet.Ascending("id"))
+	even := parquet.NewBuffer(schema, sorting)
+	odd := parquet.NewBuffer(schema, sorting)
+	for i := 0; i < 1000; i++ {
+		row := makeRow(i)
+		if i%2 == 0 {
+			even.Write(&row)
+		} else {
+			odd.Write(&row)
+		}
+	}
+
+	merged, err := parquet.MergeRowGroups([]parquet.RowGroup{even, odd}, sorting)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(t *testing.T, rowGroups []parquet.RowGroup, options ...parquet.WriterOption) *parquet.File {
+		buffer := new(bytes.Buffer)
+		writer := parquet.NewWriter(buffer, options...)
+		for _, rowGroup := range rowGroups {
+			n, err := writer.WriteRowGroup(rowGroup)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != rowGroup.NumRows() {
+				t.Fatalf("wrong number of rows written: want=%d got=%d", rowGroup.NumRows(), n)
+			}
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	check := func(t *testing.T, f *parquet.File, rowIDs []int) {
+		reader := parquet.NewReader(f)
+		for _, i := range rowIDs {
+			want, got := makeRow(i), Row{}
+			if err := reader.Read(&got); err != nil {
+				t.Fatal(err)
+			}
+			if got.ID != want.ID || got.Name != want.Name || len(got.Tags) != len(want.Tags) {
+				t.Fatalf("row mismatch:\nwant = %+v\ngot  = %+v", want, got)
+			}
+		}
+		if n := f.NumRows(); n != int64(len(rowIDs)) {
+			t.Errorf("wrong number of rows: want=%d got=%d", len(rowIDs), n)
+		}
+	}
+
+	rowIDs := make([]int, 0, 1500)
+	for i := 0; i < 1000; i++ {
+		rowIDs = append(rowIDs, i)
+	}
+	for i := 0; i < 1000; i += 2 {
+		rowIDs = append(rowIDs, i)
+	}
+
+	for _, version := range []int{v1, v2} {
+		t.Run(fmt.Sprintf("v%d", version), func(t *testing.T) {
+			f := write(t, []parquet.RowGroup{merged, even}, parquet.DataPageVersion(version))
+			check(t, f, rowIDs)
+
+			rowGroups := make([]parquet.RowGroup, f.NumRowGroups())
+			for i := range rowGroups {
+				rowGroups[i] = f.RowGroup(i)
+			}
+
+			t.Run("copy", func(t *testing.T) {
+				check(t, write(t, rowGroups), rowIDs)
+			})
+
+			t.Run("compress", func(t *testing.T) {
+				// The pages of the "id" column cannot be copied because they
+				// were compressed with a different codec.
+				f := write(t, rowGroups, parquet.ColumnCompression(&parquet.Snappy, "id"))
+				check(t, f, rowIDs)
+				for _, rowGroup := range f.Metadata().RowGroups {
+					for _, column := range rowGroup.Columns {
+						want := format.Uncompressed
+						switch column.MetaData.PathInSchema[0] {
+						case "id":
+							want = format.Snappy
+						case "name":
+							want = format.Zstd
+						}
+						if codec := column.MetaData.Codec; codec != want {
+							t.Errorf("wrong codec for column %v: want=%s got=%s", column.MetaData.PathInSchema, want, codec)
+						}
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestWriterDataPageMaxValues(t *testing.T) {
+	type Row struct {
+		ID   int64    `parquet:"id"`
+		Tags []string `parquet:"tags"`
+	}
+
+	const numRows = 1000
+	const maxValues = 100
+
+	write := func(t *testing.T, do func(*parquet.Writer) error) *parquet.File {
+		buffer := new(bytes.Buffer)
+		writer := parquet.NewWriter(buffer, parquet.SchemaOf(Row{}), parquet.DataPageMaxValues(maxValues))
+		if err := do(writer); err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	check := func(t *testing.T, f *parquet.File) {
+		rowGroup := f.RowGroup(0)
+		for i := 0; i < rowGroup.NumColumns(); i++ {
+			column := rowGroup.Column(i)
+			numPages, numRowsInPages := 0, int64(0)
+			pages := column.Pages()
+			for {
+				page, err := pages.ReadPage()
+				if err != nil {
+					if err == io.EOF {
+						break
+					}
+					t.Fatal(err)
+				}
+				numPages++
+				numRowsInPages += page.NumRows()
+				// Pages contain whole rows, they may exceed the limit by the
+				// number of values in one row.
+				if n := page.NumValues(); n > maxValues+2 {
+					t.Errorf("page of column %d has too many values: %d", column.Column(), n)
+				}
+			}
+			if numPages < 2 {
+				t.Errorf("column %d was written to a single page", column.Column())
+			}
+			if numRowsInPages != numRows {
+				t.Errorf("wrong number of rows in column %d: want=%d got=%d", column.Column(), numRows, numRowsInPages)
+			}
+		}
+	}
+
+	t.Run("rows", func(t *testing.T) {
+		f := write(t, func(w *parquet.Writer) error {
+			for i := 0; i < numRows; i++ {
+				if err := w.Write(&Row{ID: int64(i), Tags: []string{"a", "b", "c"}[:i%4]}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		check(t, f)
+	})
+
+	t.Run("row groups", func(t *testing.T) {
+		buffer := parquet.NewBuffer(parquet.SchemaOf(Row{}))
+		for i := 0; i < numRows; i++ {
+			if err := buffer.Write(&Row{ID: int64(i)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		f := write(t, func(w *parquet.Writer) error {
+			_, err := w.WriteRowGroup(buffer)
+			return err
+		})
+		check(t, f)
+	})
+}
+
+func TestWriterRowGroupTargetSize(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Body string `parquet:"body"`
+	}
+
+	const numRows = 10000
+	const targetSize = 64 * 1024
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer,
+		parquet.PageBufferSize(4096),
+		parquet.RowGroupTargetSize(targetSize),
+	)
+	for i := 0; i < numRows; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Body: uuid.NewString()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rowGroups := f.Metadata().RowGroups
+	if len(rowGroups) < 2 {
+		t.Fatalf("the row groups were not flushed automatically: %d row group(s)", len(rowGroups))
+	}
+	numRowsInFile := int64(0)
+	for i, rowGroup := range rowGroups {
+		numRowsInFile += rowGroup.NumRows
+		// Allow the row group to exceed the target by one page per column.
+		if size := rowGroup.TotalCompressedSize; size > targetSize+2*4096 {
+			t.Errorf("row group %d is larger than the target size: %d", i, size)
+		}
+	}
+	if numRowsInFile != numRows {
+		t.Errorf("wrong number of rows: want=%d got=%d", numRows, numRowsInFile)
+	}
+}
+
+func TestWriterMaxRowsPerRowGroup(t *testing.T) {
+	type Row struct {
+		ID   int64    `parquet:"id"`
+		Tags []string `parquet:"tags"`
+	}
+
+	write := func(t *testing.T, maxRows int64, do func(*parquet.Writer) error) *parquet.File {
+		buffer := new(bytes.Buffer)
+		writer := parquet.NewWriter(buffer, parquet.SchemaOf(Row{}), parquet.MaxRowsPerRowGroup(maxRows))
+		if err := do(writer); err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	check := func(t *testing.T, f *parquet.File, want []int64) {
+		rowGroups := f.Metadata().RowGroups
+		got := make([]int64, len(rowGroups))
+		for i := range rowGroups {
+			got[i] = rowGroups[i].NumRows
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("wrong number of rows in row groups:\nwant = %v\ngot  = %v", want, got)
+		}
+	}
+
+	f := write(t, 100, func(w *parquet.Writer) error {
+		for i := 0; i < 1000; i++ {
+			if err := w.Write(&Row{ID: int64(i), Tags: []string{"a", "b", "c"}[:i%4]}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	check(t, f, []int64{100, 100, 100, 100, 100, 100, 100, 100, 100, 100})
+
+	f = write(t, 300, func(w *parquet.Writer) error {
+		_, err := w.ReadRowsFrom(parquet.NewReader(f))
+		return err
+	})
+	check(t, f, []int64{300, 300, 300, 100})
+
+	reader := parquet.NewReader(f)
+	for i := 0; i < 1000; i++ {
+		var row Row
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if row.ID != int64(i) || len(row.Tags) != i%4 {
+			t.Fatalf("row %d mismatch: %+v", i, row)
+		}
+	}
+
+	f = write(t, 400, func(w *parquet.Writer) error {
+		for _, rowGroup := range f.RowGroups() {
+			if _, err := w.WriteRowGroup(rowGroup); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	check(t, f, []int64{300, 300, 300, 100})
+
+	f = write(t, 250, func(w *parquet.Writer) error {
+		rowGroup := parquet.NewBuffer(parquet.SchemaOf(Row{}))
+		if _, err := parquet.CopyRows(rowGroup, parquet.NewReader(f)); err != nil {
+			return err
+		}
+		n, err := w.WriteRowGroup(rowGroup)
+		if err == nil && n != 1000 {
+			err = fmt.Errorf("wrong number of rows written: want=1000 got=%d", n)
+		}
+		return err
+	})
+	check(t, f, []int64{250, 250, 250, 250})
+
+	reader = parquet.NewReader(f)
+	for i := 0; i < 1000; i++ {
+		var row Row
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if row.ID != int64(i) || len(row.Tags) != i%4 {
+			t.Fatalf("row %d mismatch after writing row groups: %+v", i, row)
+		}
+	}
+}
+
+func TestWriterFlushRowGroup(t *testing.T) {
+	type Row struct {
+		ID   int64   `parquet:"id"`
+		Name *string `parquet:"name,optional"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	write := func(min, max int64) {
+		for id := min; id <= max; id++ {
+			name := fmt.Sprintf("name-%d", id)
+			row := &Row{ID: id}
+			if id%3 != 0 {
+				row.Name = &name
+			}
+			if err := writer.Write(row); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	write(0, 9)
+	rowGroup0, err := writer.FlushRowGroup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	write(10, 14)
+	rowGroup1, err := writer.FlushRowGroup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rowGroup, err := writer.FlushRowGroup(); err != nil {
+		t.Fatal(err)
+	} else if rowGroup != nil {
+		t.Errorf("flushing an empty row group returned metadata: %+v", rowGroup)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rowGroups := f.Metadata().RowGroups
+	if len(rowGroups) != 2 {
+		t.Fatalf("wrong number of row groups: want=2 got=%d", len(rowGroups))
+	}
+
+	for i, test := range []struct {
+		rowGroup *parquet.FlushedRowGroup
+		numRows  int64
+		nulls    int64
+		minID    int64
+		maxID    int64
+		minName  string
+		maxName  string
+	}{
+		{rowGroup0, 10, 4, 0, 9, "name-1", "name-8"},
+		{rowGroup1, 5, 1, 10, 14, "name-10", "name-14"},
+	} {
+		rowGroup := test.rowGroup
+		if rowGroup.Index != i {
+			t.Errorf("row group %d: wrong index: %d", i, rowGroup.Index)
+		}
+		if !reflect.DeepEqual(rowGroup.RowGroup, rowGroups[i]) {
+			t.Errorf("row group %d: metadata mismatch:\nwant = %+v\ngot  = %+v", i, rowGroups[i], rowGroup.RowGroup)
+		}
+		if rowGroup.RowGroup.NumRows != test.numRows {
+			t.Errorf("row group %d: wrong number of rows: want=%d got=%d", i, test.numRows, rowGroup.RowGroup.NumRows)
+		}
+
+		id, name := rowGroup.Columns[0], rowGroup.Columns[1]
+		if id.NumValues != test.numRows || id.NullCount != 0 || id.MinValue.Int64() != test.minID || id.MaxValue.Int64() != test.maxID {
+			t.Errorf("row group %d: wrong statistics of id column: %+v", i, id)
+		}
+		if name.NumValues != test.numRows || name.NullCount != test.nulls || string(name.MinValue.ByteArray()) != test.minName || string(name.MaxValue.ByteArray()) != test.maxName {
+			t.Errorf("row group %d: wrong statistics of name column: %+v", i, name)
+		}
+	}
+}
+
+func TestWriterOnRowGroupFlush(t *testing.T) {
+	type Row struct {
+		ID int64 `parquet:"id"`
+	}
+
+	var flushed []string
+	errStop := errors.New("stop")
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer,
+		parquet.MaxRowsPerRowGroup(4),
+		parquet.OnRowGroupFlush(func(rowGroup *parquet.FlushedRowGroup) error {
+			stats := rowGroup.Columns[0]
+			flushed = append(flushed, fmt.Sprintf("%d:%d:%d-%d", rowGroup.Index, rowGroup.RowGroup.NumRows, stats.MinValue.Int64(), stats.MaxValue.Int64()))
+			return nil
+		}),
+		parquet.OnRowGroupFlush(func(rowGroup *parquet.FlushedRowGroup) error {
+			if rowGroup.Index == 1 {
+				return errStop
+			}
+			return nil
+		}),
+	)
+
+	for i := 0; i < 10; i++ {
+		err := writer.Write(&Row{ID: int64(i)})
+		switch {
+		case i == 7 && err != errStop:
+			t.Fatalf("writing row %d: want=%v got=%v", i, errStop, err)
+		case i != 7 && err != nil:
+			t.Fatalf("writing row %d: %v", i, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"0:4:0-3", "1:4:4-7", "2:2:8-9"}
+	if !reflect.DeepEqual(flushed, want) {
+		t.Errorf("wrong row groups flushed:\nwant = %v\ngot  = %v", want, flushed)
+	}
+}
+
+func TestWriterRowGroupFlushInterval(t *testing.T) {
+	type Row struct {
+		ID int64 `parquet:"id"`
+	}
+
+	const interval = 50 * time.Millisecond
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.RowGroupFlushInterval(interval))
+	id := int64(0)
+	write := func(numRows int) {
+		for i := 0; i < numRows; i++ {
+			if err := writer.Write(&Row{ID: id}); err != nil {
+				t.Fatal(err)
+			}
+			id++
+		}
+	}
+
+	write(3)
+	time.Sleep(2 * interval)
+	// The row group is flushed when the next row is written.
+	write(1)
+	write(2)
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rowGroups := f.Metadata().RowGroups
+	got := make([]int64, len(rowGroups))
+	for i := range rowGroups {
+		got[i] = rowGroups[i].NumRows
+	}
+	if want := []int64{4, 2}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("wrong number of rows in row groups:\nwant = %v\ngot  = %v", want, got)
+	}
+}
+
+type countingBufferPool struct {
+	get, put int
+}
+
+func (pool *countingBufferPool) GetPageBuffer() io.ReadWriter {
+	pool.get++
+	return new(bytes.Buffer)
+}
+
+func (pool *countingBufferPool) PutPageBuffer(io.ReadWriter) {
+	pool.put++
+}
+
+func TestWriterBufferSizes(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	pool := new(countingBufferPool)
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.SchemaOf(Row{}),
+		parquet.ColumnPageBuffers(pool),
+		parquet.PageEncodingBufferSize(64),
+		parquet.ColumnChunkBufferSize(64),
+		parquet.FooterBufferSize(64),
+	)
+
+	for i := 0; i < 1000; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Name: fmt.Sprintf("row-%d", i)}); err != nil {
+			t.Fatal(err)
+		}
+		if i%100 == 99 {
+			if err := writer.Flush(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// All the pages are larger than 64 bytes, so none of the buffers acquired
+	// from the pool must have been released to it.
+	if pool.get == 0 {
+		t.Fatal("no page buffers were acquired from the pool")
+	}
+	if pool.put != 0 {
+		t.Errorf("oversized page buffers were released to the pool: %d/%d", pool.put, pool.get)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(f.Metadata().RowGroups); n != 10 {
+		t.Errorf("wrong number of row groups: want=10 got=%d", n)
+	}
+
+	reader := parquet.NewReader(f)
+	for i := 0; i < 1000; i++ {
+		var row Row
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if row.ID != int64(i) || row.Name != fmt.Sprintf("row-%d", i) {
+			t.Fatalf("row %d mismatch: %+v", i, row)
+		}
+	}
+}
+
+func TestWriterBufferSizesInvalid(t *testing.T) {
+	_, err := parquet.NewWriterConfig(parquet.FooterBufferSize(-1))
+	if err == nil {
+		t.Fatal("expected an error for a negative footer buffer size")
+	}
+}
+
+func TestWriterDeeplyNestedColumns(t *testing.T) {
+	// Levels of deeply nested columns exceed the range of 8 bits integers.
+	const depth = 200
+
+	node := parquet.Repeated(parquet.String())
+	for i := 0; i < depth; i++ {
+		node = parquet.Repeated(parquet.Group{"level": node})
+	}
+	schema := parquet.NewSchema("deep", parquet.Group{"root": node})
+
+	const maxRepetitionLevel = depth + 1
+	const maxDefinitionLevel = depth + 1
+
+	rows := []parquet.Row{
+		{
+			parquet.ValueOf("a").Level(0, maxDefinitionLevel, 0),
+			parquet.ValueOf("b").Level(maxRepetitionLevel, maxDefinitionLevel, 0),
+			parquet.ValueOf("c").Level(150, maxDefinitionLevel, 0),
+		},
+		{
+			parquet.ValueOf(nil).Level(0, 0, 0),
+		},
+		{
+			parquet.ValueOf(nil).Level(0, 130, 0),
+		},
+		{
+			parquet.ValueOf("d").Level(0, maxDefinitionLevel, 0),
+		},
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, schema)
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	column := f.Root().Columns()[0]
+	for len(column.Columns()) > 0 {
+		column = column.Columns()[0]
+	}
+	if column.MaxRepetitionLevel() != maxRepetitionLevel || column.MaxDefinitionLevel() != maxDefinitionLevel {
+		t.Fatalf("wrong levels of leaf column: repetition=%d definition=%d", column.MaxRepetitionLevel(), column.MaxDefinitionLevel())
+	}
+
+	reader := parquet.NewReader(f)
+	for i, want := range rows {
+		row, err := reader.ReadRow(nil)
+		if err != nil {
+			t.Fatalf("reading row %d: %v", i, err)
+		}
+		if !row.Equal(want) {
+			t.Errorf("row %d mismatch:\nwant = %+v\ngot  = %+v", i, want, row)
+		}
+	}
+}
+
+func TestWriterSchemaValidation(t *testing.T) {
+	schema := parquet.NewSchema("test", parquet.Group{
+		"cost": parquet.Decimal(0, 10, parquet.Int32Type),
+	})
+
+	t.Run("config", func(t *testing.T) {
+		if _, err := parquet.NewWriterConfig(schema); err != nil {
+			t.Fatalf("schema must not be validated by default: %v", err)
+		}
+		_, err := parquet.NewWriterConfig(schema, parquet.SchemaValidation(true))
+		if err == nil {
+			t.Fatal("expected error configuring writer with invalid schema")
+		}
+		if !strings.Contains(err.Error(), "DECIMAL precision must be between 1 and 9") {
+			t.Errorf("wrong error: %v", err)
+		}
+	})
+
+	t.Run("row group", func(t *testing.T) {
+		buffer := parquet.NewBuffer(schema)
+		writer := parquet.NewWriter(new(bytes.Buffer), parquet.SchemaValidation(true))
+
+		_, err := writer.WriteRowGroup(buffer)
+		errs, ok := err.(parquet.SchemaErrors)
+		if !ok {
+			t.Fatalf("wrong error type: %T: %v", err, err)
+		}
+		if len(errs) != 1 || errs[0].Path[0] != "cost" {
+			t.Errorf("wrong errors: %v", errs)
+		}
+	})
+}
+
+func TestWriterObserver(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name,dict"`
+	}
+
+	const numRows = 100
+
+	var pages []parquet.WriterPageStats
+	var rowGroups []parquet.WriterRowGroupStats
+	var fallbacks [][]string
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer,
+		parquet.MaxRowsPerRowGroup(numRows/2),
+		parquet.DataPageMaxValues(numRows/10),
+		parquet.DictionaryMaxValues(numRows/4),
+		parquet.ObserveWriter(&parquet.WriterObserver{
+			OnPage:               func(stats parquet.WriterPageStats) { pages = append(pages, stats) },
+			OnRowGroup:           func(stats parquet.WriterRowGroupStats) { rowGroups = append(rowGroups, stats) },
+			OnDictionaryFallback: func(path []string) { fallbacks = append(fallbacks, path) },
+		}),
+	)
+	for i := 0; i < numRows; i++ {
+		if err := writer.Write(&Row{ID: int64(i), Name: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata := f.Metadata()
+
+	if len(rowGroups) != len(metadata.RowGroups) {
+		t.Fatalf("wrong number of row groups observed: want=%d got=%d", len(metadata.RowGroups), len(rowGroups))
+	}
+	numPages := 0
+	for i, rowGroup := range metadata.RowGroups {
+		stats := rowGroups[i]
+		if stats.RowGroup != i || stats.NumRows != rowGroup.NumRows || stats.CompressedSize != rowGroup.TotalCompressedSize || stats.UncompressedSize != rowGroup.TotalByteSize {
+			t.Errorf("wrong stats of row group %d: %+v", i, stats)
+		}
+		for j, column := range rowGroup.Columns {
+			s := stats.Columns[j]
+			if !reflect.DeepEqual(s.Path, column.MetaData.PathInSchema) || s.NumValues != column.MetaData.NumValues || s.CompressedSize != column.MetaData.TotalCompressedSize {
+				t.Errorf("wrong stats of column %d in row group %d: %+v", j, i, s)
+			}
+			numPages += s.NumPages
+		}
+	}
+
+	numDataPages, numValues := 0, int64(0)
+	for _, page := range pages {
+		if page.PageType != format.DictionaryPage {
+			numDataPages++
+			numValues += page.NumValues
+		}
+		if page.CompressedSize <= 0 {
+			t.Errorf("page with invalid size: %+v", page)
+		}
+	}
+	if numDataPages != numPages {
+		t.Errorf("wrong number of data pages observed: want=%d got=%d", numPages, numDataPages)
+	}
+	if numValues != 2*numRows {
+		t.Errorf("wrong number of values observed: want=%d got=%d", 2*numRows, numValues)
+	}
+
+	// The dictionary of the name column exceeds its limit in each row group.
+	if len(fallbacks) != 2 || !reflect.DeepEqual(fallbacks[0], []string{"name"}) {
+		t.Errorf("wrong dictionary fallbacks observed: %v", fallbacks)
+	}
+}
+
+func TestWriterFlushContext(t *testing.T) {
+	type Row struct {
+		ID int64 `parquet:"id"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	for i := 0; i < 10; i++ {
+		if err := writer.Write(&Row{ID: int64(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := writer.FlushContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("flushing with a cancelled context: want=%v got=%v", context.Canceled, err)
+	}
+	if err := writer.CloseContext(ctx); err != nil {
+		// The buffered rows were discarded, there is nothing left to flush.
+		t.Fatal(err)
+	}
+
+	buffer.Reset()
+	writer.Reset(buffer)
+	for i := 0; i < 10; i++ {
+		if err := writer.Write(&Row{ID: int64(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.CloseContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("closing with a cancelled context: want=%v got=%v", context.Canceled, err)
+	}
+
+	buffer.Reset()
+	writer.Reset(buffer)
+	for i := 0; i < 10; i++ {
+		if err := writer.Write(&Row{ID: int64(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.CloseContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := f.NumRows(); n != 10 {
+		t.Errorf("wrong number of rows: want=10 got=%d", n)
+	}
+}
+
+func TestWriterWriteRows(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name,optional"`
+	}
+
+	rows := make([]Row, 25)
+	for i := range rows {
+		rows[i] = Row{ID: int64(i)}
+		if i%2 == 0 {
+			rows[i].Name = fmt.Sprint(i)
+		}
+	}
+
+	for _, test := range []struct {
+		scenario string
+		rows     interface{}
+	}{
+		{scenario: "slice", rows: rows},
+		{scenario: "pointer to slice", rows: &rows},
+		{scenario: "slice of pointers", rows: []*Row{&rows[0], &rows[1], &rows[2]}},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			writer := parquet.NewWriter(buffer, parquet.MaxRowsPerRowGroup(10))
+			n, err := writer.WriteRows(test.rows)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+			for i := 0; i < n; i++ {
+				row := Row{}
+				if err := reader.Read(&row); err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(row, rows[i]) {
+					t.Fatalf("rows at index %d mismatch: want=%+v got=%+v", i, rows[i], row)
+				}
+			}
+			if err := reader.Read(new(Row)); err != io.EOF {
+				t.Fatalf("expected io.EOF after reading %d rows but got %v", n, err)
+			}
+		})
+	}
+
+	writer := parquet.NewWriter(new(bytes.Buffer))
+	if _, err := writer.WriteRows(rows[0]); err == nil {
+		t.Error("expected an error when writing rows from a value which is not a slice")
+	}
+
+	type OtherRow struct {
+		ID int64 `parquet:"id"`
+	}
+
+	writer = parquet.NewWriter(new(bytes.Buffer), parquet.SchemaOf(new(Row)))
+	if _, err := writer.WriteRows([]parquet.Row{{parquet.ValueOf(int64(1))}}); err == nil {
+		t.Error("expected an error when writing a slice of parquet rows")
+	}
+	if _, err := writer.WriteRows([]OtherRow{{ID: 1}}); err == nil {
+		t.Error("expected an error when writing values with a different schema")
+	}
+	if _, err := writer.WriteRows(rows[:1]); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWriterInferredSchema(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+	type SameRow struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+	type OtherRow struct {
+		ID int64 `parquet:"id"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	if err := writer.Write(&Row{ID: 1, Name: "one"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Write(SameRow{ID: 2, Name: "two"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.WriteRows([]SameRow{{ID: 3, Name: "three"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Write(&OtherRow{ID: 4}); err == nil {
+		t.Error("expected an error when writing a value with a different schema")
+	}
+	if _, err := writer.WriteRows([]OtherRow{{ID: 5}}); err == nil {
+		t.Error("expected an error when writing values with a different schema")
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	for _, want := range []Row{{1, "one"}, {2, "two"}, {3, "three"}} {
+		row := Row{}
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if row != want {
+			t.Errorf("row mismatch: want=%+v got=%+v", want, row)
+		}
+	}
+	if err := reader.Read(new(Row)); err != io.EOF {
+		t.Fatalf("expected io.EOF but got %v", err)
+	}
+}
+
+func TestWriterDerivedColumns(t *testing.T) {
+	type Event struct {
+		Name string `parquet:"name"`
+		Time int64  `parquet:"time,timestamp"`
+	}
+
+	const day = 24 * 3600 * 1e9
+	events := []Event{
+		{Name: "A", Time: 1 * day},
+		{Name: "B", Time: 2*day + 1},
+		{Name: "C", Time: 3*day + 2},
+	}
+
+	schema := parquet.SchemaOf(new(Event))
+	const timeColumn = 1 // columns are ordered by name
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer,
+		parquet.DerivedColumns(
+			parquet.Derive("day", parquet.Int(32), func(row parquet.Row) parquet.Value {
+				return parquet.ValueOf(int32(row[timeColumn].Int64() / day))
+			}),
+			parquet.Derive("tag", parquet.Optional(parquet.String()), func(row parquet.Row) parquet.Value {
+				if name := row[0].String(); name != "B" {
+					return parquet.ValueOf("tag-" + name)
+				}
+				return parquet.Value{}
+			}),
+		),
+	)
+
+	if err := writer.Write(&events[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.WriteRows(events[1:2]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.WriteRowGroup(func() parquet.RowGroup {
+		b := parquet.NewBuffer(schema)
+		if err := b.Write(&events[2]); err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}()); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := writer.Schema(); got != schema {
+		t.Errorf("the writer schema must not include the derived columns:\n%s", got)
+	}
+
+	type EventWithDay struct {
+		Name string  `parquet:"name"`
+		Time int64   `parquet:"time,timestamp"`
+		Day  int32   `parquet:"day"`
+		Tag  *string `parquet:"tag,optional"`
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	const want = `message Event {
+	required int32 day (INT(32,true));
+	required binary name (STRING);
+	optional binary tag (STRING);
+	required int64 time (TIMESTAMP(isAdjustedToUTC=true,unit=MILLIS));
+}`
+	if got := reader.Schema().String(); got != want {
+		t.Errorf("wrong file schema:\nwant:\n%s\ngot:\n%s", want, got)
+	}
+
+	for i, event := range events {
+		row := EventWithDay{}
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if row.Name != event.Name || row.Time != event.Time || row.Day != int32(i+1) {
+			t.Errorf("wrong row at index %d: %+v", i, row)
+		}
+		if (row.Tag == nil) != (event.Name == "B") || (row.Tag != nil && *row.Tag != "tag-"+event.Name) {
+			t.Errorf("wrong tag at index %d: %v", i, row.Tag)
+		}
+	}
+}
+
+func TestWriterDerivedColumnsErrors(t *testing.T) {
+	type Row struct {
+		Name string `parquet:"name"`
+	}
+
+	writer := parquet.NewWriter(new(bytes.Buffer),
+		parquet.DerivedColumns(
+			parquet.Derive("name", parquet.String(), func(parquet.Row) parquet.Value { return parquet.ValueOf("") }),
+		),
+	)
+	if err := writer.Write(&Row{}); err == nil {
+		t.Error("expected an error when a derived column conflicts with a column of the schema")
+	}
+
+	writer = parquet.NewWriter(new(bytes.Buffer),
+		parquet.DerivedColumns(
+			parquet.Derive("other", parquet.String(), func(parquet.Row) parquet.Value { return parquet.Value{} }),
+		),
+	)
+	if err := writer.Write(&Row{}); err == nil {
+		t.Error("expected an error when a required derived column is null")
+	}
+
+	if _, err := parquet.NewWriterConfig(parquet.DerivedColumns(
+		parquet.Derive("list", parquet.Repeated(parquet.String()), nil),
+	)); err == nil {
+		t.Error("expected an error when a derived column is repeated")
+	}
+}
+
+func TestWriterColumnOverride(t *testing.T) {
+	type Address struct {
+		Street string `parquet:"street"`
+		City   string `parquet:"city"`
+	}
+	type Row struct {
+		ID       int64    `parquet:"id"`
+		Status   string   `parquet:"status"`
+		Nickname string   `parquet:"nickname"`
+		Address  Address  `parquet:"address"`
+		Tags     []string `parquet:"tags"`
+	}
+	type FileRow struct {
+		ID       int64    `parquet:"id"`
+		Status   string   `parquet:"status"`
+		Nickname *string  `parquet:"nickname,optional"`
+		Address  *Address `parquet:"address,optional"`
+		Tags     []string `parquet:"tags"`
+	}
+
+	rows := []Row{
+		{ID: 1, Status: "active", Nickname: "one", Address: Address{"1st", "SF"}, Tags: []string{"a"}},
+		{ID: 2, Status: "inactive", Address: Address{"2nd", "NYC"}},
+		{ID: 3, Status: "active", Nickname: "three", Tags: []string{"b", "c"}},
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer,
+		parquet.ColumnOverride(parquet.Optional, "nickname"),
+		parquet.ColumnOverride(parquet.Optional, "address"),
+		parquet.ColumnOverride(parquet.OverrideType(parquet.Enum().Type()), "status"),
+		parquet.ColumnOverride(func(node parquet.Node) parquet.Node {
+			return parquet.Compressed(node, &parquet.Zstd)
+		}, "address", "city"),
+	)
+	for i := range rows {
+		if err := writer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := f.Root()
+	if !schema.ChildByName("nickname").Optional() || !schema.ChildByName("address").Optional() {
+		t.Errorf("columns were not made optional:\n%s", schema)
+	}
+	if lt := schema.ChildByName("status").Type().LogicalType(); lt == nil || lt.Enum == nil {
+		t.Errorf("wrong logical type of status column: %v", lt)
+	}
+	for _, c := range f.Metadata().RowGroups[0].Columns {
+		codec := format.Uncompressed
+		if strings.Join(c.MetaData.PathInSchema, ".") == "address.city" {
+			codec = format.Zstd
+		}
+		if c.MetaData.Codec != codec {
+			t.Errorf("wrong compression codec of column %q: want=%v got=%v", c.MetaData.PathInSchema, codec, c.MetaData.Codec)
+		}
+	}
+
+	reader := parquet.NewReader(f)
+	for i, row := range rows {
+		got := FileRow{}
+		if err := reader.Read(&got); err != nil {
+			t.Fatal(err)
+		}
+		want := FileRow{ID: row.ID, Status: row.Status, Nickname: &rows[i].Nickname, Address: &rows[i].Address, Tags: row.Tags}
+		if len(got.Tags) == 0 {
+			got.Tags = nil
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("row %d mismatch:\nwant = %+v\ngot  = %+v", i, want, got)
+		}
+	}
+
+	for _, override := range []parquet.WriterOption{
+		parquet.ColumnOverride(parquet.Repeated, "nickname"),
+		parquet.ColumnOverride(parquet.OverrideType(parquet.Int(64).Type()), "status"),
+		parquet.ColumnOverride(func(parquet.Node) parquet.Node { return parquet.String() }, "address"),
+	} {
+		writer := parquet.NewWriter(new(bytes.Buffer), override)
+		if err := writer.Write(&rows[0]); err == nil {
+			t.Error("expected an error when overriding a column with an incompatible node")
+		}
+	}
+}
+
+func TestWriterTimeDuration(t *testing.T) {
+	type Trip struct {
+		Departure time.Duration `parquet:"departure,time(millis)"`
+		Duration  time.Duration `parquet:"duration,time(micros)"`
+	}
+
+	trips := []Trip{
+		{Departure: 8*time.Hour + 30*time.Minute, Duration: 90 * time.Minute},
+		{Departure: 23*time.Hour + 59*time.Minute + 999*time.Millisecond, Duration: time.Microsecond},
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	for i := range trips {
+		if err := writer.Write(&trips[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	for i := range trips {
+		row, err := reader.ReadRow(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if kind := row[0].Kind(); kind != parquet.Int32 {
+			t.Errorf("row %d: wrong kind of departure value: %s", i, kind)
+		}
+		if millis := time.Duration(row[0].Int32()) * time.Millisecond; millis != trips[i].Departure {
+			t.Errorf("row %d: wrong departure value: want=%v got=%v", i, trips[i].Departure, millis)
+		}
+		if micros := time.Duration(row[1].Int64()) * time.Microsecond; micros != trips[i].Duration {
+			t.Errorf("row %d: wrong duration value: want=%v got=%v", i, trips[i].Duration, micros)
+		}
+	}
+
+	reader = parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	for i := range trips {
+		trip := Trip{}
+		if err := reader.Read(&trip); err != nil {
+			t.Fatal(err)
+		}
+		if trip != trips[i] {
+			t.Errorf("row %d: wrong trip: want=%+v got=%+v", i, trips[i], trip)
+		}
+	}
+}
+
+func TestWriterTimestampAdjustedToUTC(t *testing.T) {
+	type Event struct {
+		Instant   time.Time  `parquet:"instant,timestamp(millis)"`
+		WallClock time.Time  `parquet:"wall_clock,timestamp(millis:local)"`
+		Optional  *time.Time `parquet:"optional"`
+	}
+
+	zone := time.FixedZone("UTC+2", 2*3600)
+	when := time.Date(2022, time.March, 14, 10, 30, 0, 0, zone)
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	if err := writer.Write(&Event{Instant: when, WallClock: when}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	row, err := reader.ReadRow(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Columns are ordered by name: instant, optional, wall_clock.
+	if millis := row[0].Int64(); millis != when.UnixMilli() {
+		t.Errorf("wrong UTC timestamp value: want=%d got=%d", when.UnixMilli(), millis)
+	}
+	if !row[1].IsNull() {
+		t.Errorf("optional timestamp value is not null: %v", row[1])
+	}
+	wallClock := time.Date(2022, time.March, 14, 10, 30, 0, 0, time.UTC)
+	if millis := row[2].Int64(); millis != wallClock.UnixMilli() {
+		t.Errorf("wrong local timestamp value: want=%d got=%d", wallClock.UnixMilli(), millis)
+	}
+
+	reader = parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	event := Event{}
+	if err := reader.Read(&event); err != nil {
+		t.Fatal(err)
+	}
+	if !event.Instant.Equal(when) || event.Instant.Location() != time.UTC {
+		t.Errorf("wrong UTC timestamp: want=%v got=%v", when.UTC(), event.Instant)
+	}
+	if event.Optional != nil {
+		t.Errorf("optional timestamp is not nil: %v", event.Optional)
+	}
+	want := time.Date(2022, time.March, 14, 10, 30, 0, 0, time.Local)
+	if !event.WallClock.Equal(want) || event.WallClock.Location() != time.Local {
+		t.Errorf("wrong local timestamp: want=%v got=%v", want, event.WallClock)
+	}
+}
+
+func TestWriterDate(t *testing.T) {
+	type Order struct {
+		Placed  parquet.CivilDate `parquet:"placed"`
+		Shipped time.Time         `parquet:"shipped,date"`
+	}
+
+	zone := time.FixedZone("UTC-8", -8*3600)
+	orders := []Order{
+		{
+			Placed:  parquet.CivilDate{Year: 2022, Month: time.March, Day: 14},
+			Shipped: time.Date(2022, time.March, 15, 23, 30, 0, 0, zone),
+		},
+		{
+			Placed:  parquet.CivilDate{Year: 1969, Month: time.December, Day: 31},
+			Shipped: time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	for i := range orders {
+		if err := writer.Write(&orders[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	for i, days := range [][2]int32{{19065, 19066}, {-1, 0}} {
+		row, err := reader.ReadRow(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if placed, shipped := row[0].Int32(), row[1].Int32(); placed != days[0] || shipped != days[1] {
+			t.Errorf("row %d: wrong number of days: want=%v got=[%d %d]", i, days, placed, shipped)
+		}
+	}
+
+	reader = parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	for i := range orders {
+		order := Order{}
+		if err := reader.Read(&order); err != nil {
+			t.Fatal(err)
+		}
+		if order.Placed != orders[i].Placed {
+			t.Errorf("row %d: wrong placed date: want=%s got=%s", i, orders[i].Placed, order.Placed)
+		}
+		if want := parquet.CivilDateOf(orders[i].Shipped).In(time.UTC); order.Shipped != want {
+			t.Errorf("row %d: wrong shipped date: want=%v got=%v", i, want, order.Shipped)
+		}
+	}
+}
+
+func TestWriterTimestampNanos(t *testing.T) {
+	type Span struct {
+		Start time.Time  `parquet:"start,timestamp(nanos)"`
+		End   *time.Time `parquet:"end,timestamp(nanos)"`
+	}
+
+	start := time.Date(2022, time.March, 14, 10, 30, 0, 123456789, time.UTC)
+	end := start.Add(999 * time.Nanosecond)
+	spans := []Span{{Start: start, End: &end}, {Start: start}}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	for i := range spans {
+		if err := writer.Write(&spans[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	for i := range spans {
+		span := Span{}
+		if err := reader.Read(&span); err != nil {
+			t.Fatal(err)
+		}
+		if !span.Start.Equal(spans[i].Start) {
+			t.Errorf("row %d: wrong start time: want=%v got=%v", i, spans[i].Start, span.Start)
+		}
+		if (span.End == nil) != (spans[i].End == nil) || (span.End != nil && !span.End.Equal(*spans[i].End)) {
+			t.Errorf("row %d: wrong end time: want=%v got=%v", i, spans[i].End, span.End)
+		}
+	}
+
+	// Reading nanosecond timestamps into time.Time fields of microsecond
+	// precision converts the values to the unit of the fields.
+	type SpanMicros struct {
+		Start time.Time  `parquet:"start"`
+		End   *time.Time `parquet:"end"`
+	}
+
+	reader = parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	for i := range spans {
+		span := SpanMicros{}
+		if err := reader.Read(&span); err != nil {
+			t.Fatal(err)
+		}
+		if want := spans[i].Start.Truncate(time.Microsecond); !span.Start.Equal(want) {
+			t.Errorf("row %d: wrong start time: want=%v got=%v", i, want, span.Start)
+		}
+		if spans[i].End == nil {
+			if span.End != nil {
+				t.Errorf("row %d: end time is not nil: %v", i, span.End)
+			}
+		} else if want := spans[i].End.Truncate(time.Microsecond); span.End == nil || !span.End.Equal(want) {
+			t.Errorf("row %d: wrong end time: want=%v got=%v", i, want, span.End)
+		}
+	}
+}
+
+func TestWriterUnsignedIntegers(t *testing.T) {
+	type Counters struct {
+		A uint8
+		B uint16
+		C uint32
+		D uint64
+	}
+
+	counters := []Counters{
+		{A: 1, B: 2, C: 3, D: 4},
+		{A: math.MaxUint8, B: math.MaxUint16, C: math.MaxUint32, D: math.MaxUint64},
+		{A: 1 << 7, B: 1 << 15, C: 1 << 31, D: 1 << 63},
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	for i := range counters {
+		if err := writer.Write(&counters[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `message Counters {
+	required int32 A (INT(8,false));
+	required int32 B (INT(16,false));
+	required int32 C (INT(32,false));
+	required int64 D (INT(64,false));
+}`
+	if s := parquet.SchemaOf(new(Counters)).String(); s != want {
+		t.Errorf("wrong schema:\nwant:\n%s\ngot:\n%s", want, s)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	maxValues := []parquet.Value{
+		parquet.ValueOf(uint8(math.MaxUint8)),
+		parquet.ValueOf(uint16(math.MaxUint16)),
+		parquet.ValueOf(uint32(math.MaxUint32)),
+		parquet.ValueOf(uint64(math.MaxUint64)),
+	}
+	for i, want := range maxValues {
+		columnChunk := f.RowGroups()[0].Column(i)
+		columnIndex := columnChunk.ColumnIndex()
+		minValue, maxValue := columnIndex.MinValue(0), columnIndex.MaxValue(0)
+		if columnChunk.Type().Compare(minValue, maxValue) > 0 {
+			t.Errorf("column %d: min value %v is greater than max value %v", i, minValue, maxValue)
+		}
+		if !parquet.Equal(maxValue, want) {
+			t.Errorf("column %d: wrong max value: want=%v got=%v", i, want, maxValue)
+		}
+	}
+
+	// Unsigned 32 bits values are zero-extended when read into wider types.
+	type WideCounters struct {
+		A int64
+		B uint64
+		C uint64
+		D uint64
+	}
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	for i := range counters {
+		wide := WideCounters{}
+		if err := reader.Read(&wide); err != nil {
+			t.Fatal(err)
+		}
+		want := WideCounters{
+			A: int64(counters[i].A),
+			B: uint64(counters[i].B),
+			C: uint64(counters[i].C),
+			D: counters[i].D,
+		}
+		if wide != want {
+			t.Errorf("row %d: wrong values: want=%+v got=%+v", i, want, wide)
+		}
+	}
+}
+
+func TestWriterSmallIntegers(t *testing.T) {
+	type Small struct {
+		A int8
+		B int16
+		C uint8
+		D uint16
+	}
+
+	values := []Small{
+		{A: math.MinInt8, B: math.MinInt16, C: 0, D: 0},
+		{A: math.MaxInt8, B: math.MaxInt16, C: math.MaxUint8, D: math.MaxUint16},
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	for i := range values {
+		if err := writer.Write(&values[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []format.IntType{{BitWidth: 8, IsSigned: true}, {BitWidth: 16, IsSigned: true}, {BitWidth: 8}, {BitWidth: 16}} {
+		element := f.Metadata().Schema[i+1]
+		if element.Type == nil || *element.Type != format.Int32 {
+			t.Errorf("column %s: wrong physical type: %v", element.Name, element.Type)
+		}
+		if element.LogicalType == nil || element.LogicalType.Integer == nil || *element.LogicalType.Integer != want {
+			t.Errorf("column %s: wrong logical type: %v", element.Name, element.LogicalType)
+		}
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	for i := range values {
+		value := Small{}
+		if err := reader.Read(&value); err != nil {
+			t.Fatal(err)
+		}
+		if value != values[i] {
+			t.Errorf("row %d: wrong values: want=%+v got=%+v", i, values[i], value)
+		}
+	}
+
+	// Values of wider columns which do not fit in small integers are reported
+	// as errors instead of being truncated.
+	type Wide struct{ A int32 }
+	buffer.Reset()
+	writer = parquet.NewWriter(buffer)
+	if err := writer.Write(&Wide{A: math.MaxInt8 + 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	type Narrow struct{ A int8 }
+	reader = parquet.NewReader(bytes.NewReader(buffer.Bytes()), parquet.SchemaOf(new(Wide)))
+	if err := reader.Read(new(Narrow)); err == nil {
+		t.Error("expected an error reading an out of range value into an int8 field")
+	}
+}
+
+func TestWriterGeospatialColumns(t *testing.T) {
+	schema := parquet.NewSchema("places", parquet.Group{
+		"location": parquet.Geometry("EPSG:4326"),
+		"area":     parquet.Optional(parquet.Geography("", format.Karney)),
+	})
+
+	// Well-Known Binary encoding of POINT(2.35 48.85).
+	point := make([]byte, 21)
+	point[0] = 1 // little-endian
+	binary.LittleEndian.PutUint32(point[1:], 1)
+	binary.LittleEndian.PutUint64(point[5:], math.Float64bits(2.35))
+	binary.LittleEndian.PutUint64(point[13:], math.Float64bits(48.85))
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, schema)
+	// Columns are ordered by name: area, location.
+	row := parquet.Row{
+		parquet.Value{}.Level(0, 0, 0),
+		parquet.ValueOf(point).Level(0, 0, 1),
+	}
+	if err := writer.WriteRow(row); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `message places {
+	optional binary area (GEOGRAPHY(,KARNEY));
+	required binary location (GEOMETRY(EPSG:4326));
+}`
+	if s := parquet.NewSchema("places", f.Root()).String(); s != want {
+		t.Errorf("wrong schema:\nwant:\n%s\ngot:\n%s", want, s)
+	}
+
+	rows := f.RowGroups()[0].Rows()
+	values, err := rows.ReadRow(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !values[0].IsNull() {
+		t.Errorf("area is not null: %v", values[0])
+	}
+	if !bytes.Equal(values[1].ByteArray(), point) {
+		t.Errorf("wrong location: want=%x got=%x", point, values[1].ByteArray())
+	}
+}
+
+func TestWriterVariantColumns(t *testing.T) {
+	schema := parquet.NewSchema("events", parquet.Group{
+		"id":      parquet.Leaf(parquet.Int64Type),
+		"payload": parquet.Optional(parquet.Variant()),
+	})
+
+	// Variant encoding of an empty metadata dictionary and of the int8 value 42.
+	metadata := []byte{0x01, 0x00, 0x00}
+	value := []byte{0x0C, 42}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, schema)
+	// Columns are ordered by name: id, payload.metadata, payload.value.
+	row := parquet.Row{
+		parquet.ValueOf(int64(1)).Level(0, 0, 0),
+		parquet.ValueOf(metadata).Level(0, 1, 1),
+		parquet.ValueOf(value).Level(0, 1, 2),
+	}
+	if err := writer.WriteRow(row); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `message events {
+	required int64 id;
+	optional group payload (VARIANT) {
+		required binary metadata;
+		required binary value;
+	}
+}`
+	fileSchema := parquet.NewSchema("events", f.Root())
+	if s := fileSchema.String(); s != want {
+		t.Errorf("wrong schema:\nwant:\n%s\ngot:\n%s", want, s)
+	}
+	if err := fileSchema.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	rows := f.RowGroups()[0].Rows()
+	values, err := rows.ReadRow(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(values[1].ByteArray(), metadata) {
+		t.Errorf("wrong metadata: want=%x got=%x", metadata, values[1].ByteArray())
+	}
+	if !bytes.Equal(values[2].ByteArray(), value) {
+		t.Errorf("wrong value: want=%x got=%x", value, values[2].ByteArray())
+	}
+}
+
+func TestWriterRecursiveType(t *testing.T) {
+	tree := treeNode{
+		Name: "root",
+		Children: []treeNode{
+			{Name: "a", Children: []treeNode{{Name: "a1", Children: []treeNode{{Name: "a1x"}}}}},
+			{Name: "b"},
+		},
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer)
+	if err := writer.Write(&tree); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nodes deeper than the depth of the children field are not written, and
+	// the children of the deepest nodes are not part of the schema.
+	want := treeNode{
+		Name: "root",
+		Children: []treeNode{
+			{Name: "a", Children: []treeNode{{Name: "a1"}}},
+			{Name: "b", Children: []treeNode{}},
+		},
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	var got treeNode
+	if err := reader.Read(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong value read back:\nwant: %+v\ngot:  %+v", want, got)
+	}
+}
+
+func TestWriterNullColumns(t *testing.T) {
+	schema := parquet.NewSchema("nulls", parquet.Group{
+		"id":      parquet.Leaf(parquet.Int64Type),
+		"nothing": parquet.Optional(parquet.Null(parquet.Int32Type)),
+	})
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, schema)
+	for i := int64(0); i < 3; i++ {
+		row := parquet.Row{
+			parquet.ValueOf(i).Level(0, 0, 0),
+			parquet.ValueOf(nil).Level(0, 0, 1),
+		}
+		if err := writer.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `message nulls {
+	required int64 id;
+	optional int32 nothing (NULL);
+}`
+	fileSchema := parquet.NewSchema("nulls", f.Root())
+	if s := fileSchema.String(); s != want {
+		t.Errorf("wrong schema:\nwant:\n%s\ngot:\n%s", want, s)
+	}
+
+	type record struct {
+		ID      int64  `parquet:"id"`
+		Nothing *int32 `parquet:"nothing"`
+	}
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	for i := int64(0); i < 3; i++ {
+		r := record{Nothing: new(int32)}
+		if err := reader.Read(&r); err != nil {
+			t.Fatal(err)
+		}
+		if r.ID != i || r.Nothing != nil {
+			t.Errorf("wrong record at index %d: %+v", i, r)
+		}
+	}
+}
+
+func TestWriterLegacyNestedLayouts(t *testing.T) {
+	type record struct {
+		ID     int64            `parquet:"id"`
+		Tags   []string         `parquet:"tags,list"`
+		Matrix [][]int32        `parquet:"matrix"`
+		Labels map[string]int64 `parquet:"labels"`
+	}
+
+	records := []record{
+		{
+			ID:     1,
+			Tags:   []string{"a", "b"},
+			Matrix: [][]int32{{1, 2}, {}, {3}},
+			Labels: map[string]int64{"x": 1, "y": 2},
+		},
+		{
+			ID:     2,
+			Tags:   []string{},
+			Matrix: [][]int32{},
+			Labels: map[string]int64{},
+		},
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.LegacyNestedLayouts(true))
+	for i := range records {
+		if err := writer.Write(&records[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `message record {
+	required int64 id (INT(64,true));
+	required group labels (MAP) {
+		repeated group map {
+			required binary key (STRING);
+			required int64 value (INT(64,true));
+		}
+	}
+	required group matrix (LIST) {
+		repeated group array (LIST) {
+			repeated int32 array (INT(32,true));
+		}
+	}
+	required group tags (LIST) {
+		repeated binary array (STRING);
+	}
+}`
+	if s := parquet.NewSchema("record", f.Root()).String(); s != want {
+		t.Errorf("wrong schema:\nwant:\n%s\ngot:\n%s", want, s)
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()))
+	for i := range records {
+		var r record
+		if err := reader.Read(&r); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(r, records[i]) {
+			t.Errorf("wrong record at index %d:\nwant = %+v\ngot  = %+v", i, records[i], r)
+		}
+	}
+}
+
+func TestWriterLegacyNestedLayoutsOptionalElements(t *testing.T) {
+	schema := parquet.NewSchema("test", parquet.Group{
+		"tags": parquet.List(parquet.Optional(parquet.String())),
+	})
+	_, err := parquet.NewWriterConfig(schema, parquet.LegacyNestedLayouts(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a list of optional elements")
+		}
+	}()
+	parquet.NewWriter(new(bytes.Buffer), schema, parquet.LegacyNestedLayouts(true))
+}
+
+func TestWriterFieldIDs(t *testing.T) {
+	schema := parquet.NewSchema("table", parquet.Group{
+		"id": parquet.FieldID(parquet.Leaf(parquet.Int64Type), 1),
+		"location": parquet.FieldID(parquet.Optional(parquet.Group{
+			"lat": parquet.FieldID(parquet.Leaf(parquet.DoubleType), 3),
+			"lon": parquet.FieldID(parquet.Leaf(parquet.DoubleType), 4),
+		}), 2),
+		"name": parquet.String(),
+	})
+
+	const want = `message table {
+	required int64 id = 1;
+	optional group location = 2 {
+		required double lat = 3;
+		required double lon = 4;
+	}
+	required binary name (STRING);
+}`
+
+	writeFile := func(schema *parquet.Schema, rows parquet.RowReader) *parquet.File {
+		t.Helper()
+		buffer := new(bytes.Buffer)
+		writer := parquet.NewWriter(buffer, schema)
+		if _, err := parquet.CopyRows(writer, rows); err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	buffer := parquet.NewBuffer(schema)
+	if err := buffer.WriteRow(parquet.Row{
+		parquet.ValueOf(int64(1)).Level(0, 0, 0),
+		parquet.ValueOf(48.85).Level(0, 1, 1),
+		parquet.ValueOf(2.35).Level(0, 1, 2),
+		parquet.ValueOf("Paris").Level(0, 0, 3),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	f := writeFile(schema, parquet.NewRowGroupReader(buffer))
+	fileSchema := parquet.NewSchema("table", f.Root())
+	if s := fileSchema.String(); s != want {
+		t.Errorf("wrong schema:\nwant:\n%s\ngot:\n%s", want, s)
+	}
+	if id := f.Root().Column("location").Column("lat").ID(); id != 3 {
+		t.Errorf("wrong field id of location.lat: want=3 got=%d", id)
+	}
+
+	// Writing the rows of a file with its schema retains the field ids.
+	f = writeFile(fileSchema, f.RowGroups()[0].Rows())
+	if s := parquet.NewSchema("table", f.Root()).String(); s != want {
+		t.Errorf("wrong schema after copy:\nwant:\n%s\ngot:\n%s", want, s)
+	}
+}
+
+func TestWriterIcebergMetrics(t *testing.T) {
+	type record struct {
+		ID    int64   `parquet:"id,id(1)"`
+		Name  string  `parquet:"name,id(2)"`
+		Score *int32  `parquet:"score,optional,id(3)"`
+		Tags  []int64 `parquet:"tags,id(4)"`
+		Extra string  `parquet:"extra"`
+	}
+
+	score := int32(-2)
+	rows := []record{
+		{ID: 3, Name: "abcdef", Score: &score, Tags: []int64{1, 2}},
+		{ID: -1, Name: "ab\U0010FFFF\U0010FFFFx"},
+		{ID: 7, Name: "a", Tags: []int64{3}},
+	}
+
+	writer := parquet.NewWriter(new(bytes.Buffer), parquet.SchemaOf(record{}), parquet.MaxRowsPerRowGroup(2))
+	for _, row := range rows {
+		if err := writer.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	metrics := writer.IcebergMetrics(3)
+
+	le32 := func(v int32) []byte { b := make([]byte, 4); binary.LittleEndian.PutUint32(b, uint32(v)); return b }
+	le64 := func(v int64) []byte { b := make([]byte, 8); binary.LittleEndian.PutUint64(b, uint64(v)); return b }
+
+	want := parquet.IcebergMetrics{
+		RecordCount:     3,
+		ValueCounts:     map[int]int64{1: 3, 2: 3, 3: 3, 4: 4},
+		NullValueCounts: map[int]int64{1: 0, 2: 0, 3: 2, 4: 1},
+		LowerBounds: map[int][]byte{
+			1: le64(-1),
+			2: []byte("a"),
+			3: le32(-2),
+		},
+		UpperBounds: map[int][]byte{
+			1: le64(7),
+			2: []byte("ac"),
+			3: le32(-2),
+		},
+	}
+
+	if !reflect.DeepEqual(metrics, want) {
+		t.Errorf("iceberg metrics mismatch:\nwant = %+v\ngot  = %+v", want, metrics)
+	}
+}
+
+func TestWriterZstdDictionary(t *testing.T) {
+	type Row struct {
+		Request string `parquet:"request"`
+	}
+
+	dictionary, err := os.ReadFile("fixtures/zstd.dict")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(codec *zstd.Codec) []byte {
+		buffer := new(bytes.Buffer)
+		writer := parquet.NewWriter(buffer,
+			parquet.Compression(codec),
+			parquet.DataPageMaxValues(10),
+		)
+		for i := 0; i < 100; i++ {
+			row := Row{Request: fmt.Sprintf("method=GET path=/api/v1/users/%d status=200 user_agent=Mozilla/5.0 (X11; Linux x86_64)", i)}
+			if err := writer.Write(&row); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buffer.Bytes()
+	}
+
+	withoutDictionary := write(&zstd.Codec{})
+	withDictionary := write(&zstd.Codec{Dictionary: dictionary})
+	if len(withDictionary) >= len(withoutDictionary) {
+		t.Errorf("compressing with the dictionary did not reduce the file size: %d >= %d", len(withDictionary), len(withoutDictionary))
+	}
+
+	if _, err := parquet.OpenFile(bytes.NewReader(withDictionary), int64(len(withDictionary))); err == nil {
+		t.Fatal("opening a file compressed with a zstd dictionary without the dictionary did not fail")
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(withDictionary), int64(len(withDictionary)), parquet.ZstdDictionaries(dictionary))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []format.KeyValue{{Key: "zstd.dictionary.id", Value: "42"}}
+	if got := f.Metadata().RowGroups[0].Columns[0].MetaData.KeyValueMetadata; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong column chunk metadata:\nwant = %+v\ngot  = %+v", want, got)
+	}
+
+	// Pages compressed with the dictionary are decompressed when copied to a
+	// writer which does not use it.
+	output := new(bytes.Buffer)
+	writer := parquet.NewWriter(output, parquet.Compression(&zstd.Codec{}))
+	if _, err := writer.WriteRowGroup(f.RowGroups()[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	copied := parquet.NewReader(bytes.NewReader(output.Bytes()))
+	for {
+		if err := copied.Read(new(Row)); err != nil {
+			if err != io.EOF {
+				t.Fatal(err)
+			}
+			break
+		}
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(withDictionary), parquet.ZstdDictionaries(dictionary))
+	for i := 0; ; i++ {
+		row := Row{}
+		if err := reader.Read(&row); err != nil {
+			if err != io.EOF {
+				t.Fatal(err)
+			}
+			if i != 100 {
+				t.Errorf("wrong number of rows: want=100 got=%d", i)
+			}
+			break
+		}
+		if want := fmt.Sprintf("method=GET path=/api/v1/users/%d status=200 user_agent=Mozilla/5.0 (X11; Linux x86_64)", i); row.Request != want {
+			t.Fatalf("wrong row at index %d: want=%q got=%q", i, want, row.Request)
+		}
+	}
+}